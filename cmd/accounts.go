@@ -2,10 +2,17 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
-	"github.com/langtind/ynabctl/internal/client"
 	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/langtind/ynabctl/internal/reqfile"
+	"github.com/langtind/ynabctl/pkg/ynab"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var accountsCmd = &cobra.Command{
@@ -14,20 +21,42 @@ var accountsCmd = &cobra.Command{
 	Long:  `List, view, and create accounts within a budget.`,
 }
 
+// accountsStaleAge is set by --stale-reconciliation
+var accountsStaleAge string
+
 var accountsListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all accounts",
-	Long:  `Returns a list of all accounts for the specified budget.`,
+	Long: `Returns a list of all accounts for the specified budget.
+
+With --stale-reconciliation, only open accounts not reconciled within the
+given age (e.g. 30d) are listed, including those never reconciled.`,
+	Example: `  ynabctl accounts list -f table
+  ynabctl accounts list --stale-reconciliation 30d -f table`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		id, err := getBudgetID()
 		if err != nil {
 			return err
 		}
+		var maxAge time.Duration
+		if accountsStaleAge != "" {
+			if maxAge, err = parseAge(accountsStaleAge); err != nil {
+				return err
+			}
+		}
 
-		accounts, err := apiClient.GetAccounts(id)
+		var accounts []ynab.Account
+		if listDelta {
+			accounts, err = deltaAccounts(id)
+		} else {
+			accounts, err = apiClient.GetAccounts(id)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to get accounts: %w", err)
 		}
+		if accountsStaleAge != "" {
+			accounts = report.StaleReconciliation(accounts, maxAge, time.Now())
+		}
 
 		formatter := output.New(getOutputFormat())
 		return formatter.Print(accounts)
@@ -35,17 +64,25 @@ var accountsListCmd = &cobra.Command{
 }
 
 var accountsGetCmd = &cobra.Command{
-	Use:   "get <account-id>",
+	Use:   "get <account>",
 	Short: "Get account details",
-	Long:  `Returns details for a specific account.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Returns details for a specific account.
+
+The account can be given by ID or by name; names are matched ignoring
+case and emojis. If several accounts match, you are asked to choose.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		budgetID, err := getBudgetID()
 		if err != nil {
 			return err
 		}
 
-		account, err := apiClient.GetAccount(budgetID, args[0])
+		accountID, err := resolveAccountID(budgetID, args[0])
+		if err != nil {
+			return err
+		}
+
+		account, err := apiClient.GetAccount(budgetID, accountID)
 		if err != nil {
 			return fmt.Errorf("failed to get account: %w", err)
 		}
@@ -55,10 +92,19 @@ var accountsGetCmd = &cobra.Command{
 	},
 }
 
+// accountSpec is an account to create, from the flags of accounts create
+// or an entry of its request file
+type accountSpec struct {
+	Name     string
+	Type     string
+	Balance  float64
+	Note     string
+	Tracking bool
+}
+
 var (
-	accountName    string
-	accountType    string
-	accountBalance float64
+	newAccount   accountSpec
+	accountBatch [][]reqfile.Field
 )
 
 var accountsCreateCmd = &cobra.Command{
@@ -66,42 +112,155 @@ var accountsCreateCmd = &cobra.Command{
 	Short: "Create a new account",
 	Long: `Create a new account in the budget.
 
-Account types: checking, savings, cash, creditCard, lineOfCredit,
-otherAsset, otherLiability, mortgage, autoLoan, studentLoan,
-personalLoan, medicalDebt, otherDebt`,
+Budget account types (on budget):
+  checking, savings, cash, creditCard, lineOfCredit
+
+Tracking account types (off budget, e.g. investments, property, loans):
+  otherAsset, otherLiability, mortgage, autoLoan, studentLoan,
+  personalLoan, medicalDebt, otherDebt
+
+YNAB decides on/off budget from the type. Pass --tracking to create a
+tracking account; it defaults to otherAsset and rejects budget types.
+
+The new account's transfer_payee_id is printed to stderr; use it as
+--payee-id to record transfers into the account.
+
+A --from-file holding a list of accounts creates them all in one run,
+e.g. to set up a new budget or a test environment:
+
+  - name: Checking
+    type: checking
+    balance: 1000
+  - name: Index fund
+    tracking: true
+    balance: 25000
+
+Every entry is checked before the first account is created. The created
+accounts are printed, followed by a summary with any failures.`,
+	Example: `  ynabctl accounts create --name "Checking" --type checking --balance 1000
+  ynabctl accounts create --name "Index fund" --tracking --balance 25000 --note "Broker X"
+  ynabctl accounts create --from-file accounts.yaml -f table`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		budgetID, err := getBudgetID()
 		if err != nil {
 			return err
 		}
-
-		if accountName == "" {
-			return fmt.Errorf("account name is required (--name)")
-		}
-		if accountType == "" {
-			return fmt.Errorf("account type is required (--type)")
+		if accountBatch != nil {
+			return createAccounts(cmd, budgetID, accountBatch)
 		}
 
-		// Convert balance to milliunits
-		balance := client.AmountToMilliunits(accountBalance)
-
-		account, err := apiClient.CreateAccount(budgetID, accountName, accountType, balance)
+		if err := newAccount.validate(); err != nil {
+			return err
+		}
+		account, err := createAccount(budgetID, newAccount)
 		if err != nil {
-			return fmt.Errorf("failed to create account: %w", err)
+			return err
 		}
+		infof("transfer payee ID: %s\n", account.TransferPayeeID)
 
 		formatter := output.New(getOutputFormat())
 		return formatter.Print(account)
 	},
 }
 
+// addAccountFlags defines the flags describing an account on fs
+func addAccountFlags(fs *pflag.FlagSet, a *accountSpec) {
+	fs.StringVar(&a.Name, "name", "", "Account name (required)")
+	fs.StringVar(&a.Type, "type", "", "Account type (required)")
+	fs.Float64Var(&a.Balance, "balance", 0, "Starting balance")
+	fs.StringVar(&a.Note, "note", "", "Account note")
+	fs.BoolVar(&a.Tracking, "tracking", false, "Create an off-budget tracking account")
+}
+
+// validate checks a and fills in the default type of a tracking account
+func (a *accountSpec) validate() error {
+	if a.Name == "" {
+		return fmt.Errorf("account name is required (--name)")
+	}
+	if a.Tracking {
+		if a.Type == "" {
+			a.Type = "otherAsset"
+		}
+		if !ynab.IsTrackingAccountType(a.Type) {
+			return fmt.Errorf("--tracking needs a tracking account type (%s), got %s",
+				strings.Join(ynab.TrackingAccountTypes, ", "), a.Type)
+		}
+	}
+	if a.Type == "" {
+		return fmt.Errorf("account type is required (--type)")
+	}
+	return nil
+}
+
+// createAccount creates a validated account
+func createAccount(budgetID string, a accountSpec) (*ynab.Account, error) {
+	account, err := apiClient.CreateAccount(budgetID, ynab.SaveAccount{
+		Name:    a.Name,
+		Type:    a.Type,
+		Balance: ynab.AmountToMilliunits(a.Balance),
+		Note:    a.Note,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create account: %w", err)
+	}
+	if a.Note != "" && account.Note == "" {
+		fmt.Fprintf(os.Stderr, "warning: YNAB did not store the note of %s; add it in the YNAB app\n", a.Name)
+	}
+	return account, nil
+}
+
+// createAccounts creates one account per entry of a request file. All
+// entries are checked first, so a mistake in the file creates nothing.
+func createAccounts(cmd *cobra.Command, budgetID string, requests [][]reqfile.Field) error {
+	specs := make([]accountSpec, len(requests))
+	for i, fields := range requests {
+		fs := pflag.NewFlagSet("account", pflag.ContinueOnError)
+		addAccountFlags(fs, &specs[i])
+		if err := setRequestFields(fs, cmd.CommandPath(), fromFile, fields); err != nil {
+			return err
+		}
+		if err := specs[i].validate(); err != nil {
+			line := 0
+			if len(fields) > 0 {
+				line = fields[0].Line
+			}
+			return fmt.Errorf("%s: account at line %d: %w", fromFile, line, err)
+		}
+	}
+
+	log := output.NewChangelog(cmd.CommandPath())
+	defer finishChangelog(log)
+	bar := progress.NewBar("creating accounts", len(specs))
+	created := []ynab.Account{}
+	for _, a := range specs {
+		account, err := createAccount(budgetID, a)
+		id := ""
+		if err == nil {
+			id = account.ID
+			created = append(created, *account)
+		}
+		log.Record("create account", id, a.Name, ynab.AmountToMilliunits(a.Balance), err)
+		bar.Add(1)
+	}
+	bar.Done()
+
+	if err := output.New(getOutputFormat()).Print(created); err != nil {
+		return err
+	}
+	if failed := len(specs) - len(created); failed > 0 {
+		return fmt.Errorf("%d of %d accounts could not be created", failed, len(specs))
+	}
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(accountsCmd)
 	accountsCmd.AddCommand(accountsListCmd)
+	addDeltaFlag(accountsListCmd)
 	accountsCmd.AddCommand(accountsGetCmd)
 	accountsCmd.AddCommand(accountsCreateCmd)
 
-	accountsCreateCmd.Flags().StringVar(&accountName, "name", "", "Account name (required)")
-	accountsCreateCmd.Flags().StringVar(&accountType, "type", "", "Account type (required)")
-	accountsCreateCmd.Flags().Float64Var(&accountBalance, "balance", 0, "Starting balance")
+	accountsListCmd.Flags().StringVar(&accountsStaleAge, "stale-reconciliation", "", "Only open accounts not reconciled within this age (e.g. 30d)")
+	addAccountFlags(accountsCreateCmd.Flags(), &newAccount)
+	addBatchFromFileFlag(accountsCreateCmd, &accountBatch)
 }
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	amortizeExtra    float64
+	amortizeSchedule bool
+)
+
+var accountsAmortizeCmd = &cobra.Command{
+	Use:   "amortize <loan>",
+	Short: "Project the payoff of a loan account",
+	Long: `Project when a loan account (mortgage, auto loan, student loan and
+other debts) is paid off and how much interest it costs, from the
+interest rate, minimum payment and escrow entered for it in YNAB. Rate
+and payment changes YNAB knows about are followed month by month.
+
+--extra adds an amount to every monthly payment and shows the payoff
+month and interest with it, and how many months and how much interest
+it saves. --schedule lists every projected payment instead (with the
+extra payment if given).
+
+The loan is given by ID or name. Escrow is paid on top of the minimum
+payment and counts towards the total paid, not the principal.`,
+	Example: `  ynabctl accounts amortize Mortgage -f table
+  ynabctl accounts amortize Mortgage --extra 500 -f table
+  ynabctl accounts amortize "Car loan" --extra 200 --schedule -f table`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		if amortizeExtra < 0 {
+			return fmt.Errorf("--extra must not be negative")
+		}
+
+		account, err := resolveAccount(budgetID, args[0])
+		if err != nil {
+			return err
+		}
+		a, err := report.Amortize(*account, ynab.AmountToMilliunits(amortizeExtra), time.Now())
+		if err != nil {
+			return err
+		}
+
+		formatter := output.New(getOutputFormat())
+		if amortizeSchedule {
+			if a.WithExtra != nil {
+				return formatter.Print(a.WithExtra.Schedule)
+			}
+			return formatter.Print(a.Baseline.Schedule)
+		}
+		a.Baseline.Schedule = nil
+		if a.WithExtra != nil {
+			a.WithExtra.Schedule = nil
+		}
+		return formatter.Print(a)
+	},
+}
+
+func init() {
+	accountsCmd.AddCommand(accountsAmortizeCmd)
+	accountsAmortizeCmd.Flags().Float64Var(&amortizeExtra, "extra", 0, "Extra amount paid every month")
+	accountsAmortizeCmd.Flags().BoolVar(&amortizeSchedule, "schedule", false, "List every projected payment")
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/langtind/ynabctl/internal/clipboard"
+	"github.com/langtind/ynabctl/internal/notemeta"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var accountsMetaCmd = &cobra.Command{
+	Use:   "meta",
+	Short: "Read and write key/value metadata in account notes",
+	Long: `Account metadata is kept in a fenced block at the end of the account
+note, so settings such as a credit limit or statement day live in YNAB
+itself rather than in a separate file:
+
+  ` + "```ynabctl" + `
+  limit=50000
+  statement_day=15
+  ` + "```" + `
+
+Text outside the block is left alone.`,
+}
+
+var accountsMetaListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the metadata of every account that has some",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		accounts, err := apiClient.GetAccounts(budgetID)
+		if err != nil {
+			return fmt.Errorf("failed to get accounts: %w", err)
+		}
+
+		result := []notemeta.Account{}
+		for _, a := range accounts {
+			if meta := notemeta.Parse(a.Note); len(meta) > 0 && !a.Deleted {
+				result = append(result, notemeta.Account{AccountID: a.ID, AccountName: a.Name, Meta: meta})
+			}
+		}
+		return output.New(getOutputFormat()).Print(result)
+	},
+}
+
+var accountsMetaGetCmd = &cobra.Command{
+	Use:   "get <account> [key]",
+	Short: "Show the metadata of an account",
+	Long: `Show the metadata of an account, or print the value of a single key.
+A missing key is an error, so scripts can tell it apart from an empty
+value.`,
+	Example: `  ynabctl accounts meta get "Visa"
+  ynabctl accounts meta get "Visa" statement_day`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		account, err := resolveAccount(budgetID, args[0])
+		if err != nil {
+			return err
+		}
+
+		meta := notemeta.Parse(account.Note)
+		if len(args) == 2 {
+			value, ok := meta[args[1]]
+			if !ok {
+				return fmt.Errorf("account %q has no %s", account.Name, args[1])
+			}
+			fmt.Println(value)
+			return nil
+		}
+		if meta == nil {
+			meta = map[string]string{}
+		}
+		return output.New(getOutputFormat()).Print([]notemeta.Account{{AccountID: account.ID, AccountName: account.Name, Meta: meta}})
+	},
+}
+
+var accountsMetaSetCmd = &cobra.Command{
+	Use:   "set <account> <key=value>...",
+	Short: "Set metadata keys of an account",
+	Long: `Set metadata keys of an account; "key=" removes a key. Keys are
+lowercase letters, digits and underscores.
+
+The YNAB API cannot edit an existing account, so the updated note is
+printed rather than saved: paste it over the account's note in YNAB
+(Edit Account > Notes). With --copy it is also copied to the clipboard.`,
+	Example: `  ynabctl accounts meta set "Visa" statement_day=15 limit=50000
+  ynabctl accounts meta set "Visa" limit= --copy`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		values, err := notemeta.ParseAssignments(args[1:])
+		if err != nil {
+			return err
+		}
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		account, err := resolveAccount(budgetID, args[0])
+		if err != nil {
+			return err
+		}
+
+		note := notemeta.Set(account.Note, values)
+		if note == account.Note {
+			infof("No changes to the note of %s.\n", account.Name)
+			return nil
+		}
+		fmt.Println(note)
+		infof("\nThe YNAB API cannot edit account notes; replace the note of %s in YNAB with the text above.\n", account.Name)
+		if copyOutput {
+			if err := clipboard.Write(note); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to copy the note: %v\n", err)
+			} else {
+				infof("The note was copied to the clipboard.\n")
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	accountsCmd.AddCommand(accountsMetaCmd)
+	accountsMetaCmd.AddCommand(accountsMetaListCmd)
+	accountsMetaCmd.AddCommand(accountsMetaGetCmd)
+	accountsMetaCmd.AddCommand(accountsMetaSetCmd)
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var summaryIncludeClosed bool
+
+var accountsSummaryCmd = &cobra.Command{
+	Use:   "summary",
+	Short: "Show balances grouped by account type",
+	Long: `Group accounts into cash (checking, savings, cash), credit (credit
+cards, lines of credit), loans (mortgages and other debts) and tracking
+(other assets and liabilities), with a subtotal per group and the net
+position over all accounts.
+
+Closed accounts are left out unless --include-closed is given.`,
+	Example: `  ynabctl accounts summary -f table
+  ynabctl accounts summary --include-closed`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+
+		accounts, err := apiClient.GetAccounts(budgetID)
+		if err != nil {
+			return fmt.Errorf("failed to get accounts: %w", err)
+		}
+
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(report.SummarizeAccounts(accounts, summaryIncludeClosed))
+	},
+}
+
+func init() {
+	accountsCmd.AddCommand(accountsSummaryCmd)
+	accountsSummaryCmd.Flags().BoolVar(&summaryIncludeClosed, "include-closed", false, "Include closed accounts")
+}
@@ -78,6 +78,11 @@ ynabctl config show                            # Show current config
 ynabctl config set-token <token>               # Set API token
 ynabctl config set-default-budget <id>         # Set default budget
 ynabctl config set-format <json|table>         # Set output format
+ynabctl config set-alias visa <account-id>     # Use @visa wherever an ID or name is accepted
+ynabctl slugs list                             # Stable slugs (e.g. visa-credit) usable instead of IDs
+ynabctl slugs set account <id-or-name> visa    # Choose a record's slug
+ynabctl transactions get 3fa85f64             # Unique ID prefixes (6+ chars) work like full IDs
+ynabctl transactions create --account Checking --payee-id Rema ...  # --account/--category/--payee take full names; a miss suggests close ones
 ` + "```" + `
 
 ### Budgets
@@ -93,6 +98,7 @@ ynabctl budgets settings                       # Get budget settings (currency,
 
 ` + "```bash" + `
 ynabctl accounts list                          # List all accounts
+ynabctl accounts list --stale-reconciliation 30d  # Open accounts not reconciled recently
 ynabctl accounts get <account-id>              # Get account details
 ynabctl accounts create --name "Checking" --type checking --balance 1000.00
 ` + "```" + `
@@ -104,6 +110,7 @@ Account types: checking, savings, cash, creditCard, lineOfCredit, otherAsset, ot
 ` + "```bash" + `
 ynabctl categories list                        # List all category groups and categories
 ynabctl categories get <category-id>           # Get category details
+ynabctl categories inspect <category>          # Month figures, goal, 6-month trend, last 10 transactions, scheduled
 ynabctl categories update <id> --budgeted 500  # Update budgeted amount
 ynabctl categories update <id> --budgeted 500 --month 2024-01-01
 ` + "```" + `
@@ -119,6 +126,8 @@ ynabctl transactions list --category <id>      # By category
 ynabctl transactions list --payee <id>         # By payee
 ynabctl transactions list --type unapproved    # Unapproved only
 ynabctl transactions list --type uncategorized # Uncategorized only
+ynabctl transactions list --bucket month       # Grouped by day|week|month with subtotals (JSON: buckets)
+ynabctl transactions list --delta              # Only fetch changes since the last --delta run (also accounts/categories/payees/scheduled list)
 
 # Get single transaction
 ynabctl transactions get <transaction-id>
@@ -136,9 +145,18 @@ ynabctl transactions create \
 ynabctl transactions update <id> --amount -55.00
 ynabctl transactions update <id> --memo "Updated memo"
 ynabctl transactions update <id> --category <new-category-id>
+ynabctl transactions update <id> --date 2025-01-31 --allow-reconciled  # update/edit/delete refuse transactions before the last reconciliation without it
 
 # Delete transaction
 ynabctl transactions delete <transaction-id>
+
+# Bulk create from CSV in one request (columns date, amount|outflow+inflow, payee, memo, category, account)
+ynabctl transactions import --file bank.csv --account Checking --dry-run  # preview: each row new/duplicate/error
+ynabctl transactions import --file bank.csv --account Checking --column date=Dato,amount=Beløp --decimal-comma --date-format DD.MM.YYYY
+
+# Bulk edit category/memo/flag via a spreadsheet (only changed rows are patched)
+ynabctl transactions export --since 2025-01-01 --editable edit.csv
+ynabctl transactions apply-edits edit.csv --dry-run
 ` + "```" + `
 
 **Amount convention**: Negative = outflow (spending), Positive = inflow (income)
@@ -149,6 +167,8 @@ ynabctl transactions delete <transaction-id>
 ynabctl payees list                            # List all payees
 ynabctl payees get <payee-id>                  # Get payee details
 ynabctl payees update <id> --name "New Name"   # Rename payee
+ynabctl payees normalize --rules <file> [--watch]  # Rename raw payees by rules
+ynabctl doctor references                      # Transactions/payees pointing at deleted records, with fixes
 ` + "```" + `
 
 ### Scheduled Transactions
@@ -171,6 +191,13 @@ ynabctl scheduled update <id> --amount -150.00
 
 # Delete
 ynabctl scheduled delete <id>
+
+# Declare scheduled transactions in YAML; create missing, update drifted
+ynabctl scheduled export --out scheduled.yaml
+ynabctl scheduled apply scheduled.yaml --dry-run   # --prune deletes extras
+
+# Find recurring payments not scheduled yet (--create schedules them)
+ynabctl report subscriptions --create
 ` + "```" + `
 
 Frequency options: never, daily, weekly, everyOtherWeek, twiceAMonth, every4Weeks, monthly, everyOtherMonth, every3Months, every4Months, twiceAYear, yearly, everyOtherYear
@@ -180,24 +207,83 @@ Frequency options: never, daily, weekly, everyOtherWeek, twiceAMonth, every4Week
 ` + "```bash" + `
 ynabctl months list                            # List all budget months
 ynabctl months get current                     # Current month details
-ynabctl months get 2024-01-01                  # Specific month
+ynabctl months get 2024-01                     # Specific month
+ynabctl months get last                        # Also next, -1, +2, march, "mar 2025" (any month argument or --month)
+ynabctl months set-note --template note.tmpl   # Render a month note from a text/template (printed; the API cannot save it)
 ` + "```" + `
 
 Month response includes: income, budgeted, activity, to_be_budgeted, age_of_money
 
+` + "```bash" + `
+ynabctl tbb                                    # To Be Budgeted (Ready to Assign) this month
+ynabctl tbb history --snapshots data/raw       # To Be Budgeted over time, from snapshots
+ynabctl fund --amount 2000 --dry-run          # Assign an amount to categories in the configured priority order
+ynabctl transactions allocate <txn-id> --plan paycheck.yaml --dry-run  # Budget an inflow by fixed/percent/rest entries
+ynabctl allowance status --member emma         # Allowance balance, weekly amount, spending in the last 4 weeks
+ynabctl allowance credit --dry-run             # Add each member's weekly allowance (config set-allowance)
+ynabctl report consolidated --budgets personal,shared  # Net worth, spending and bills of several budgets together
+` + "```" + `
+
+categories update, categories apply, fund, transactions allocate and allowance credit refuse to push to_be_budgeted
+below zero unless --allow-negative-tbb is given.
+
+### Rate Limit
+
+` + "```bash" + `
+ynabctl plan --job import.csv                  # Will a bulk job be throttled? (--kind import|purge|merge|apply)
+` + "```" + `
+
 ### User
 
 ` + "```bash" + `
 ynabctl user                                   # Get authenticated user info
 ` + "```" + `
 
+### Command Discovery
+
+` + "```bash" + `
+ynabctl commands                               # Every command with its short description
+ynabctl commands --json                        # Full command tree: usage, flags (type, default, required), examples
+` + "```" + `
+
+### Watch and Exporter
+
+` + "```bash" + `
+ynabctl watch                                  # Print changed transactions as they appear (--notify for desktop notifications)
+ynabctl exporter --listen 127.0.0.1:9101       # Prometheus metrics of balances and the current month on /metrics
+` + "```" + `
+
+### Daemon
+
+` + "```bash" + `
+ynabctl daemon --schedule "daily: snapshot --out-dir data" --schedule "daily@06:00: doctor references"  # one job per flag; hourly|daily|weekly|daily@HH:MM|30m
+curl -s localhost:8765/status                  # Next/last run, last error and failures of each job
+` + "```" + `
+
 ---
 
 ## Global Flags
 
 ` + "```bash" + `
 --budget, -b <id>     # Use specific budget (overrides default)
---format, -f <fmt>    # Output format: json (default) or table
+--format, -f <fmt>    # Output format: json (default), table or markdown
+--yes, -y             # Skip confirmation prompts (required for update commands when not on a terminal)
+--force               # Allow changes to budgets listed in protected_budgets
+--ids                 # Print only record IDs, one per line (categories list: category IDs), for xargs
+--with-meta           # Wrap JSON in {"data": ..., "meta": {budget_id, generated_at, count, rate_limit_remaining}}
+--lang <code>         # Messages and table headers in en or nb (Norwegian); JSON stays English
+--changelog <file>    # Bulk commands: write every change (action, id, amount, error) as JSON
+--token <token|->      # Token for this run; "-" reads it from stdin
+--token-file <path>   # Read the token from a file (or set YNAB_TOKEN_FILE)
+--offline             # Read commands from the local cache, no network; writes fail; data age printed to stderr
+--no-pager            # Do not page long table output (only happens on a terminal)
+--wide                # Tables: extra columns (IDs, flags, account), nothing truncated
+--narrow              # Tables: fit 80 columns (abbreviated headers, truncated text); default fits the terminal
+--ca-bundle <file>    # Trust extra CA certificates (TLS-inspecting proxy); proxy comes from HTTPS_PROXY/NO_PROXY
+--cache-ttl <dur>     # Serve GETs from the response cache while younger than this (config: cache_ttl, YNAB_CACHE_TTL)
+--no-cache            # Always fetch from the API; 'ynabctl cache clear' empties the cache
+--notify              # Desktop notification on finish (osascript/notify-send/PowerShell), with change/failure counts
+--no-defaults         # Ignore per-command flag defaults ([defaults.<command>] in config); use in scripts
 ` + "```" + `
 
 ---
@@ -218,6 +304,12 @@ ynabctl accounts list -f table
 ynabctl transactions list -f table --since 2024-01-01
 ` + "```" + `
 
+### Markdown
+GitHub-flavored tables for issues, wikis, and chat:
+` + "```bash" + `
+ynabctl report spending --period month -f markdown
+` + "```" + `
+
 ---
 
 ## Common Workflows
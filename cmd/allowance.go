@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/config"
+	"github.com/langtind/ynabctl/internal/names"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/period"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	allowanceMember      string
+	allowanceSpentWeeks  int
+	allowanceCreditWeeks int
+	allowanceMonth       string
+	allowanceDryRun      bool
+)
+
+var allowanceCmd = &cobra.Command{
+	Use:   "allowance",
+	Short: "Weekly allowances of family members",
+	Long: `Track the weekly allowance of family members, each kept in a category
+of their own. Allowances are kept in the config file (see 'ynabctl
+config set-allowance'):
+
+  [[allowances]]
+  member = "emma"
+  category = "Emma's allowance"
+  weekly = 50
+  from = "Kids"   # optional, default Ready to Assign`,
+}
+
+var allowanceStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show allowance balances and recent spending",
+	Long: `Show the balance of each member's allowance category, the weekly
+amount it is credited, and what was spent from it in the last --weeks
+weeks (default 4). With --member, that member's transactions are listed
+too.`,
+	Example: `  ynabctl allowance status -f table
+  ynabctl allowance status --member emma --weeks 2 -f table`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		allowances, err := configuredAllowances(allowanceMember)
+		if err != nil {
+			return err
+		}
+		if allowanceSpentWeeks < 1 {
+			return fmt.Errorf("--weeks must be at least 1")
+		}
+		month, err := parseMonthArg("current")
+		if err != nil {
+			return err
+		}
+		since := time.Now().AddDate(0, 0, -7*allowanceSpentWeeks).Format("2006-01-02")
+
+		spinner := progress.Start("fetching allowance categories")
+		statuses := []report.Allowance{}
+		for _, a := range allowances {
+			status, err := allowanceStatus(budgetID, month, a, since)
+			if err != nil {
+				spinner.Stop()
+				return err
+			}
+			statuses = append(statuses, *status)
+		}
+		spinner.Stop()
+
+		formatter := output.New(getOutputFormat())
+		if allowanceMember != "" {
+			return formatter.Print(&statuses[0])
+		}
+		return formatter.Print(statuses)
+	},
+}
+
+var allowanceCreditCmd = &cobra.Command{
+	Use:   "credit",
+	Short: "Add the weekly allowance to members' categories",
+	Long: `Credit each member's allowance category with their weekly amount
+(times --weeks) by raising what is budgeted to it this month. The money
+comes from the member's "from" category, whose budgeted amount is
+lowered by as much, or from Ready to Assign.
+
+Without --member every configured member is credited. The changes are
+shown and must be confirmed unless --yes is given; --dry-run only prints
+the categories as they would be. Nothing records which weeks were
+credited, so run it once a week, e.g. from cron. Taking more from a
+"from" category than it has available is refused, and so is taking more
+than To Be Budgeted holds unless --allow-negative-tbb is given.`,
+	Example: `  ynabctl allowance credit --member emma --dry-run
+  ynabctl allowance credit --yes`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		allowances, err := configuredAllowances(allowanceMember)
+		if err != nil {
+			return err
+		}
+		if allowanceCreditWeeks < 1 {
+			return fmt.Errorf("--weeks must be at least 1")
+		}
+		month, err := parseMonthArg(allowanceMonth)
+		if err != nil {
+			return err
+		}
+
+		m, err := apiClient.GetMonth(budgetID, month)
+		if err != nil {
+			return fmt.Errorf("failed to get month: %w", err)
+		}
+		byID := map[string]ynab.Category{}
+		for _, c := range m.Categories {
+			byID[c.ID] = c
+		}
+		find := func(ref string) (ynab.Category, error) {
+			id, err := resolveCategoryID(budgetID, ref)
+			if err != nil {
+				return ynab.Category{}, err
+			}
+			c, ok := byID[id]
+			if !ok {
+				return ynab.Category{}, fmt.Errorf("category %q not found in %s", ref, month[:7])
+			}
+			return c, nil
+		}
+
+		// budgeted is the new budgeted amount of every category touched,
+		// so members sharing a "from" category add up
+		budgeted := map[string]int64{}
+		var order []string
+		change := func(c ynab.Category, delta int64) {
+			if _, ok := budgeted[c.ID]; !ok {
+				budgeted[c.ID] = c.Budgeted
+				order = append(order, c.ID)
+			}
+			budgeted[c.ID] += delta
+		}
+		var fromTBB int64
+		for _, a := range allowances {
+			amount := ynab.AmountToMilliunits(a.Weekly) * int64(allowanceCreditWeeks)
+			target, err := find(a.Category)
+			if err != nil {
+				return fmt.Errorf("allowance of %s: %w", a.Member, err)
+			}
+			change(target, amount)
+			if a.From == "" {
+				fromTBB += amount
+				continue
+			}
+			source, err := find(a.From)
+			if err != nil {
+				return fmt.Errorf("allowance of %s: %w", a.Member, err)
+			}
+			change(source, -amount)
+			if moved := source.Budgeted - budgeted[source.ID]; moved > source.Balance {
+				return fmt.Errorf("%s has only %.2f available, not the %.2f the allowances take from it",
+					source.Name, ynab.MilliunitsToAmount(source.Balance), ynab.MilliunitsToAmount(moved))
+			}
+		}
+
+		var changes output.Changes
+		for _, id := range order {
+			c := byID[id]
+			changes.AddAmount(c.Name, c.Budgeted, budgeted[id])
+		}
+		formatter := output.New(getOutputFormat())
+		if allowanceDryRun {
+			planned := []ynab.Category{}
+			for _, id := range order {
+				c := byID[id]
+				c.Balance += budgeted[id] - c.Budgeted
+				c.Budgeted = budgeted[id]
+				planned = append(planned, c)
+			}
+			return formatter.Print(planned)
+		}
+		if fromTBB > 0 {
+			if err := guardTBB(map[string]int64{m.Month: m.ToBeBudgeted}, map[string]int64{m.Month: fromTBB}); err != nil {
+				return err
+			}
+		}
+		ok, err := confirmChanges(fmt.Sprintf("the budgeted amounts of %s", m.Month[:7]), changes)
+		if err != nil || !ok {
+			return err
+		}
+
+		log := output.NewChangelog(cmd.CommandPath())
+		defer finishChangelog(log)
+		updated := []ynab.Category{}
+		for _, id := range order {
+			c := byID[id]
+			u, err := apiClient.UpdateCategory(budgetID, id, m.Month, budgeted[id])
+			log.Record("credit allowance", id, c.Name, budgeted[id]-c.Budgeted, err)
+			if err != nil {
+				return fmt.Errorf("failed to update %s: %w", c.Name, err)
+			}
+			updated = append(updated, *u)
+		}
+		return formatter.Print(updated)
+	},
+}
+
+// configuredAllowances returns the allowance of member, or all of them
+// when member is empty
+func configuredAllowances(member string) ([]config.Allowance, error) {
+	if cfg == nil || len(cfg.Allowances) == 0 {
+		return nil, fmt.Errorf("no allowances configured; add one with 'ynabctl config set-allowance <member> <category> <weekly>'")
+	}
+	if member == "" {
+		return cfg.Allowances, nil
+	}
+	for _, a := range cfg.Allowances {
+		if names.Equal(a.Member, member) {
+			return []config.Allowance{a}, nil
+		}
+	}
+	return nil, fmt.Errorf("no allowance configured for %q", member)
+}
+
+// allowanceStatus fetches the category and recent transactions of an
+// allowance
+func allowanceStatus(budgetID, month string, a config.Allowance, since string) (*report.Allowance, error) {
+	categoryID, err := resolveCategoryID(budgetID, a.Category)
+	if err != nil {
+		return nil, fmt.Errorf("allowance of %s: %w", a.Member, err)
+	}
+	category, err := apiClient.GetMonthCategory(budgetID, month, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category of %s: %w", a.Member, err)
+	}
+	transactions, err := apiClient.GetTransactionsByCategory(budgetID, categoryID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions of %s: %w", a.Member, err)
+	}
+	return report.AllowanceStatus(a.Member, *category, ynab.AmountToMilliunits(a.Weekly), transactions, since), nil
+}
+
+func init() {
+	rootCmd.AddCommand(allowanceCmd)
+	allowanceCmd.AddCommand(allowanceStatusCmd)
+	allowanceCmd.AddCommand(allowanceCreditCmd)
+
+	allowanceCmd.PersistentFlags().StringVar(&allowanceMember, "member", "", "Family member (default: all)")
+	allowanceStatusCmd.Flags().IntVar(&allowanceSpentWeeks, "weeks", 4, "Weeks of spending to show")
+	allowanceCreditCmd.Flags().IntVar(&allowanceCreditWeeks, "weeks", 1, "Weeks of allowance to credit")
+	allowanceCreditCmd.Flags().StringVar(&allowanceMonth, "month", "current", "Budget month ("+period.MonthHelp+")")
+	allowanceCreditCmd.Flags().BoolVar(&allowanceDryRun, "dry-run", false, "Only show the changes")
+	addTBBGuardFlag(allowanceCreditCmd)
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/config"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var apiCmd = &cobra.Command{
+	Use:   "api",
+	Short: "Inspect the YNAB API",
+	Long:  `Commands for inspecting what the YNAB API currently supports.`,
+}
+
+var apiCapabilitiesCached bool
+
+// capabilitiesRecord is the file format of the recorded probe results
+type capabilitiesRecord struct {
+	ProbedAt     string            `json:"probed_at"`
+	BudgetID     string            `json:"budget_id,omitempty"`
+	Capabilities []ynab.Capability `json:"capabilities"`
+}
+
+var apiCapabilitiesCmd = &cobra.Command{
+	Use:   "capabilities",
+	Short: "Probe which endpoints and fields the API supports",
+	Long: `Call each read endpoint once and compare the returned fields with the
+fields ynabctl knows about.
+
+new_fields are returned by YNAB but not yet used by ynabctl (e.g. newly
+added goal fields). missing_fields are expected by ynabctl but absent
+from the response. Budget-level endpoints are only probed when a budget
+is configured.
+
+Results are recorded in capabilities.json in the config directory; use
+--cached to show the last recorded probe without calling the API.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := filepath.Join(config.Dir(), "capabilities.json")
+		formatter := output.New(getOutputFormat())
+
+		if apiCapabilitiesCached {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("no recorded capabilities (run without --cached first): %w", err)
+			}
+			var rec capabilitiesRecord
+			if err := json.Unmarshal(data, &rec); err != nil {
+				return fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+			infof("probed at %s\n", rec.ProbedAt)
+			return formatter.Print(rec.Capabilities)
+		}
+
+		// A budget is optional here; without one only global endpoints are probed
+		bID, _ := getBudgetID()
+		rec := capabilitiesRecord{
+			ProbedAt:     time.Now().UTC().Format(time.RFC3339),
+			BudgetID:     bID,
+			Capabilities: apiClient.ProbeCapabilities(bID),
+		}
+
+		data, err := json.MarshalIndent(rec, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(config.Dir(), 0o700); err != nil {
+			return fmt.Errorf("mkdir %s: %w", config.Dir(), err)
+		}
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			return fmt.Errorf("write %s: %w", path, err)
+		}
+
+		return formatter.Print(rec.Capabilities)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(apiCmd)
+	apiCmd.AddCommand(apiCapabilitiesCmd)
+
+	apiCapabilitiesCmd.Flags().BoolVar(&apiCapabilitiesCached, "cached", false, "Show the last recorded probe instead of calling the API")
+}
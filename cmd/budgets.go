@@ -2,8 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/langtind/ynabctl/pkg/ynab"
 	"github.com/spf13/cobra"
 )
 
@@ -13,10 +18,17 @@ var budgetsCmd = &cobra.Command{
 	Long:  `List and view budget information.`,
 }
 
+var budgetsListDetailed bool
+
 var budgetsListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all budgets",
-	Long:  `Returns a list of all budgets associated with your YNAB account.`,
+	Long: `Returns a list of all budgets associated with your YNAB account.
+
+With --detailed, each budget's accounts and recent transactions are
+fetched concurrently to show the number of open accounts, the total
+on-budget balance, and the date of the latest transaction in the last
+90 days.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		budgets, err := apiClient.GetBudgets()
 		if err != nil {
@@ -24,10 +36,47 @@ var budgetsListCmd = &cobra.Command{
 		}
 
 		formatter := output.New(getOutputFormat())
-		return formatter.Print(budgets)
+		if !budgetsListDetailed {
+			return formatter.Print(budgets)
+		}
+		return formatter.Print(summarizeBudgets(budgets))
 	},
 }
 
+// summarizeBudgets fetches accounts and recent transactions for every
+// budget in parallel. A budget that fails to load is reported with its
+// error rather than failing the whole listing.
+func summarizeBudgets(budgets []ynab.Budget) []report.BudgetSummary {
+	since := time.Now().AddDate(0, 0, -90).Format("2006-01-02")
+	summaries := make([]report.BudgetSummary, len(budgets))
+
+	bar := progress.NewBar("fetching budgets", len(budgets))
+	defer bar.Done()
+
+	var wg sync.WaitGroup
+	for i, b := range budgets {
+		wg.Add(1)
+		go func(i int, b ynab.Budget) {
+			defer wg.Done()
+			defer bar.Add(1)
+			accounts, err := apiClient.GetAccounts(b.ID)
+			if err != nil {
+				summaries[i] = report.BudgetSummary{ID: b.ID, Name: b.Name, LastModifiedOn: b.LastModifiedOn, Error: err.Error()}
+				return
+			}
+			txns, err := apiClient.GetTransactions(b.ID, &ynab.TransactionFilter{SinceDate: since})
+			if err != nil {
+				summaries[i] = report.BudgetSummary{ID: b.ID, Name: b.Name, LastModifiedOn: b.LastModifiedOn, Error: err.Error()}
+				return
+			}
+			summaries[i] = report.SummarizeBudget(b, accounts, txns)
+		}(i, b)
+	}
+	wg.Wait()
+
+	return summaries
+}
+
 var budgetsGetCmd = &cobra.Command{
 	Use:   "get [budget-id]",
 	Short: "Get budget details",
@@ -37,7 +86,10 @@ If no budget ID is provided, uses the default budget from config.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var id string
 		if len(args) > 0 {
-			id = args[0]
+			var err error
+			if id, err = resolveBudgetID(args[0]); err != nil {
+				return err
+			}
 		} else {
 			var err error
 			id, err = getBudgetID()
@@ -65,7 +117,10 @@ If no budget ID is provided, uses the default budget from config.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var id string
 		if len(args) > 0 {
-			id = args[0]
+			var err error
+			if id, err = resolveBudgetID(args[0]); err != nil {
+				return err
+			}
 		} else {
 			var err error
 			id, err = getBudgetID()
@@ -89,4 +144,6 @@ func init() {
 	budgetsCmd.AddCommand(budgetsListCmd)
 	budgetsCmd.AddCommand(budgetsGetCmd)
 	budgetsCmd.AddCommand(budgetsSettingsCmd)
+
+	budgetsListCmd.Flags().BoolVar(&budgetsListDetailed, "detailed", false, "Include account count, on-budget balance, and last activity")
 }
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/langtind/ynabctl/internal/deltacache"
+	"github.com/langtind/ynabctl/internal/respcache"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the local response cache",
+	Long: `Manage the API responses ynabctl keeps on disk for --offline and for
+serving repeated reads within cache_ttl (see 'config set-cache-ttl').`,
+}
+
+var cacheClearAll bool
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove the cached API responses",
+	Long: `Remove the API responses saved in ~/.cache/ynabctl/responses, so the
+next reads go to the API. With --all, the local copies of transactions
+and of the lists kept by --delta are removed as well; they are rebuilt
+with a full fetch the next time they are used.`,
+	Example: `  ynabctl cache clear
+  ynabctl cache clear --all`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := respcache.New(respcache.Dir()).Clear()
+		if err != nil {
+			return fmt.Errorf("failed to clear the response cache: %w", err)
+		}
+		infof("removed %d cached responses\n", n)
+		if !cacheClearAll {
+			return nil
+		}
+		if err := os.RemoveAll(deltacache.Dir()); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", deltacache.Dir(), err)
+		}
+		infof("removed the transaction and --delta caches\n")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+
+	cacheClearCmd.Flags().BoolVar(&cacheClearAll, "all", false, "Also remove the transaction cache and the --delta copies")
+}
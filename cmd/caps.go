@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/period"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	capsMonth string
+	capsCheck bool
+)
+
+var capsCmd = &cobra.Command{
+	Use:   "caps",
+	Short: "Monthly spending caps per category group",
+	Long: `Spending caps limit what a whole category group may spend in a month,
+on top of the budgets of its categories. Caps are kept in the config
+file (see 'ynabctl config set-group-cap'):
+
+  [[group_caps]]
+  group = "Fun Money"
+  cap = 2000
+  warn_at = 0.9   # optional, default 0.8`,
+}
+
+var capsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show each capped group's spending against its cap",
+	Long: `Show the spending of each capped category group in a month against
+its cap. A group is in the warning state once it has spent warn_at of
+its cap (80% unless configured) and exceeded when it has spent more than
+the cap. Spending is the group's outflows less refunds.
+
+With --check the command exits with status 2 when a group has exceeded
+its cap, for use in scripts and scheduled jobs.`,
+	Example: `  ynabctl caps status -f table
+  ynabctl caps status --month 2025-06
+  ynabctl caps status --check -q`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		month, err := parseMonthArg(capsMonth)
+		if err != nil {
+			return err
+		}
+		if len(cfg.GroupCaps) == 0 {
+			return fmt.Errorf("no spending caps configured; add one with 'ynabctl config set-group-cap <group> <amount>'")
+		}
+
+		spinner := progress.Start("fetching categories")
+		groups, err := apiClient.GetCategories(budgetID)
+		if err != nil {
+			spinner.Stop()
+			return fmt.Errorf("failed to get categories: %w", err)
+		}
+		m, err := apiClient.GetMonth(budgetID, month)
+		spinner.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to get month: %w", err)
+		}
+
+		caps := make([]report.Cap, len(cfg.GroupCaps))
+		for i, c := range cfg.GroupCaps {
+			caps[i] = report.Cap{Group: c.Group, Limit: ynab.AmountToMilliunits(c.Cap), WarnAt: c.WarnAt}
+		}
+		status := report.CheckCaps(month, groups, m.Categories, caps)
+		for _, g := range status.Unknown {
+			fmt.Fprintf(os.Stderr, "Warning: no category group named %q\n", g)
+		}
+
+		if err := output.New(getOutputFormat()).Print(status); err != nil {
+			return err
+		}
+		if capsCheck {
+			for _, g := range status.Groups {
+				if g.State == report.CapExceeded {
+					cmd.SilenceUsage = true
+					return &exitError{code: 2, err: fmt.Errorf("%s has exceeded its cap", g.Group)}
+				}
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(capsCmd)
+	capsCmd.AddCommand(capsStatusCmd)
+
+	capsStatusCmd.Flags().StringVar(&capsMonth, "month", "current", "Month to check ("+period.MonthHelp+")")
+	capsStatusCmd.Flags().BoolVar(&capsCheck, "check", false, "Exit with status 2 if a group has exceeded its cap")
+}
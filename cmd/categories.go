@@ -2,10 +2,10 @@ package cmd
 
 import (
 	"fmt"
-	"time"
 
-	"github.com/langtind/ynabctl/internal/client"
 	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/period"
+	"github.com/langtind/ynabctl/pkg/ynab"
 	"github.com/spf13/cobra"
 )
 
@@ -15,38 +15,97 @@ var categoriesCmd = &cobra.Command{
 	Long:  `List, view, and update budget categories.`,
 }
 
+var (
+	categoriesOverspent   bool
+	categoriesUnderfunded bool
+	categoriesUnbudgeted  bool
+)
+
 var categoriesListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all categories",
-	Long:  `Returns a list of all category groups and categories for the budget.`,
+	Long: `Returns a list of all category groups and categories for the budget.
+
+Filters narrow the list to the current month's problem categories. When
+several are given a category must match all of them. Filtering leaves out
+hidden categories and groups without matches.
+
+  --overspent    available balance is negative
+  --underfunded  the goal still needs money this month
+  --unbudgeted   nothing is budgeted this month`,
+	Example: `  ynabctl categories list --overspent -f table
+  ynabctl categories list --underfunded --unbudgeted`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		id, err := getBudgetID()
 		if err != nil {
 			return err
 		}
 
-		categories, err := apiClient.GetCategories(id)
+		var categories []ynab.CategoryGroup
+		if listDelta {
+			categories, err = deltaCategories(id)
+		} else {
+			categories, err = apiClient.GetCategories(id)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to get categories: %w", err)
 		}
 
+		if categoriesOverspent || categoriesUnderfunded || categoriesUnbudgeted {
+			categories = filterCategories(categories, func(c ynab.Category) bool {
+				return (!categoriesOverspent || c.Balance < 0) &&
+					(!categoriesUnderfunded || c.GoalUnderFunded > 0) &&
+					(!categoriesUnbudgeted || c.Budgeted == 0)
+			})
+		}
+
 		formatter := output.New(getOutputFormat())
 		return formatter.Print(categories)
 	},
 }
 
+// filterCategories keeps the visible categories matching keep, dropping
+// groups left empty
+func filterCategories(groups []ynab.CategoryGroup, keep func(ynab.Category) bool) []ynab.CategoryGroup {
+	filtered := []ynab.CategoryGroup{}
+	for _, g := range groups {
+		if g.Deleted || g.Hidden {
+			continue
+		}
+		var categories []ynab.Category
+		for _, c := range g.Categories {
+			if !c.Deleted && !c.Hidden && keep(c) {
+				categories = append(categories, c)
+			}
+		}
+		if len(categories) > 0 {
+			g.Categories = categories
+			filtered = append(filtered, g)
+		}
+	}
+	return filtered
+}
+
 var categoriesGetCmd = &cobra.Command{
-	Use:   "get <category-id>",
+	Use:   "get <category>",
 	Short: "Get category details",
-	Long:  `Returns details for a specific category.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Returns details for a specific category.
+
+The category can be given by ID or by name; names are matched ignoring
+case and emojis. If several categories match, you are asked to choose.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		budgetID, err := getBudgetID()
 		if err != nil {
 			return err
 		}
 
-		category, err := apiClient.GetCategory(budgetID, args[0])
+		categoryID, err := resolveCategoryID(budgetID, args[0])
+		if err != nil {
+			return err
+		}
+
+		category, err := apiClient.GetCategory(budgetID, categoryID)
 		if err != nil {
 			return fmt.Errorf("failed to get category: %w", err)
 		}
@@ -62,11 +121,19 @@ var (
 )
 
 var categoriesUpdateCmd = &cobra.Command{
-	Use:   "update <category-id>",
+	Use:   "update <category>",
 	Short: "Update category budgeted amount",
 	Long: `Update the budgeted amount for a category in a specific month.
 
-The month should be in YYYY-MM-DD format (first day of the month) or "current" for the current month.`,
+The month can be YYYY-MM, "current", "last", "next", relative like -1,
+or a month name like "march".
+The category can be given by ID or by name. The change is shown before
+saving and must be confirmed unless --yes is given. --verify reads the
+category again just before saving and aborts if its budgeted amount was
+changed meanwhile, e.g. in the YNAB app while the prompt was open.
+
+Assigning more than To Be Budgeted holds is refused unless
+--allow-negative-tbb is given.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		budgetID, err := getBudgetID()
@@ -74,14 +141,50 @@ The month should be in YYYY-MM-DD format (first day of the month) or "current" f
 			return err
 		}
 
-		month := categoryMonth
-		if month == "" || month == "current" {
-			month = time.Now().Format("2006-01-01")
+		month, err := parseMonthArg(categoryMonth)
+		if err != nil {
+			return err
+		}
+
+		budgeted := ynab.AmountToMilliunits(categoryBudgeted)
+
+		categoryID, err := resolveCategoryID(budgetID, args[0])
+		if err != nil {
+			return err
+		}
+
+		existing, err := apiClient.GetMonthCategory(budgetID, month, categoryID)
+		if err != nil {
+			return fmt.Errorf("failed to get existing category: %w", err)
+		}
+
+		m, err := apiClient.GetMonth(budgetID, month)
+		if err != nil {
+			return fmt.Errorf("failed to get month: %w", err)
+		}
+		if err := guardTBB(map[string]int64{m.Month: m.ToBeBudgeted}, map[string]int64{m.Month: budgeted - existing.Budgeted}); err != nil {
+			return err
+		}
+
+		var changes output.Changes
+		changes.AddAmount("budgeted", existing.Budgeted, budgeted)
+
+		ok, err := confirmChanges(fmt.Sprintf("category %s (%s)", existing.Name, month), changes)
+		if err != nil || !ok {
+			return err
 		}
 
-		budgeted := client.AmountToMilliunits(categoryBudgeted)
+		if verifyUpdate {
+			current, err := apiClient.Fresh().GetMonthCategory(budgetID, month, categoryID)
+			if err != nil {
+				return fmt.Errorf("failed to re-read category: %w", err)
+			}
+			if err := abortIfChanged("category "+existing.Name, categoryDrift(existing, current)); err != nil {
+				return err
+			}
+		}
 
-		category, err := apiClient.UpdateCategory(budgetID, args[0], month, budgeted)
+		category, err := apiClient.UpdateCategory(budgetID, categoryID, month, budgeted)
 		if err != nil {
 			return fmt.Errorf("failed to update category: %w", err)
 		}
@@ -94,9 +197,16 @@ The month should be in YYYY-MM-DD format (first day of the month) or "current" f
 func init() {
 	rootCmd.AddCommand(categoriesCmd)
 	categoriesCmd.AddCommand(categoriesListCmd)
+	addDeltaFlag(categoriesListCmd)
 	categoriesCmd.AddCommand(categoriesGetCmd)
 	categoriesCmd.AddCommand(categoriesUpdateCmd)
 
-	categoriesUpdateCmd.Flags().StringVar(&categoryMonth, "month", "current", "Budget month (YYYY-MM-DD or 'current')")
+	categoriesListCmd.Flags().BoolVar(&categoriesOverspent, "overspent", false, "Only categories with a negative balance")
+	categoriesListCmd.Flags().BoolVar(&categoriesUnderfunded, "underfunded", false, "Only categories whose goal is underfunded this month")
+	categoriesListCmd.Flags().BoolVar(&categoriesUnbudgeted, "unbudgeted", false, "Only categories with nothing budgeted this month")
+
+	categoriesUpdateCmd.Flags().StringVar(&categoryMonth, "month", "current", "Budget month ("+period.MonthHelp+")")
 	categoriesUpdateCmd.Flags().Float64Var(&categoryBudgeted, "budgeted", 0, "Budgeted amount")
+	addTBBGuardFlag(categoriesUpdateCmd)
+	categoriesUpdateCmd.Flags().BoolVar(&verifyUpdate, "verify", false, "Re-read the category before saving and abort if its budgeted amount changed meanwhile")
 }
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var categoryHistoryMonths int
+
+var categoriesHistoryCmd = &cobra.Command{
+	Use:   "history <category>",
+	Short: "Show a category month by month",
+	Long: `Show the budgeted amount, activity and balance of one category for
+each of the last --months months up to the current one, with totals and
+monthly averages.
+
+The category can be given by ID or by name. Months before the budget
+starts are left out. Each month is one API request.`,
+	Example: `  ynabctl categories history Groceries -f table
+  ynabctl categories history "Bills: Power" --months 24`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		if categoryHistoryMonths < 1 {
+			return fmt.Errorf("--months must be at least 1")
+		}
+
+		categoryID, err := resolveCategoryID(budgetID, args[0])
+		if err != nil {
+			return err
+		}
+		history, err := fetchCategoryHistory(budgetID, categoryID, categoryHistoryMonths)
+		if err != nil {
+			return err
+		}
+
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(history)
+	},
+}
+
+// fetchCategoryHistory reads a category for each of the last n months up
+// to the current one, one request per month
+func fetchCategoryHistory(budgetID, categoryID string, n int) (*report.CategoryHistory, error) {
+	current, err := parseMonthArg("current")
+	if err != nil {
+		return nil, err
+	}
+
+	months, err := apiClient.GetMonths(budgetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get months: %w", err)
+	}
+	var wanted []string
+	for _, m := range months {
+		if !m.Deleted && m.Month <= current {
+			wanted = append(wanted, m.Month)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(wanted)))
+	if len(wanted) > n {
+		wanted = wanted[:n]
+	}
+
+	var name string
+	history := make([]report.CategoryMonth, 0, len(wanted))
+	bar := progress.NewBar("fetching months", len(wanted))
+	for _, month := range wanted {
+		c, err := apiClient.GetMonthCategory(budgetID, month, categoryID)
+		if err != nil {
+			bar.Done()
+			return nil, fmt.Errorf("failed to get category for %s: %w", month[:7], err)
+		}
+		if name == "" {
+			name = c.Name
+		}
+		history = append(history, report.CategoryMonth{
+			Month:    month,
+			Budgeted: c.Budgeted,
+			Activity: c.Activity,
+			Balance:  c.Balance,
+		})
+		bar.Add(1)
+	}
+	bar.Done()
+	return report.SummarizeCategoryHistory(categoryID, name, history), nil
+}
+func init() {
+	categoriesCmd.AddCommand(categoriesHistoryCmd)
+	categoriesHistoryCmd.Flags().IntVar(&categoryHistoryMonths, "months", 12, "Number of months to show, up to the current one")
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/spf13/cobra"
+)
+
+// Size of what categories inspect shows
+const (
+	inspectTrendMonths  = 6
+	inspectTransactions = 10
+)
+
+var categoriesInspectCmd = &cobra.Command{
+	Use:   "inspect <category>",
+	Short: "Show everything about a category at once",
+	Long: `Show the full picture of one category in one go: this month's
+budgeted, activity and balance, its goal and progress, the last six
+months, its ten latest transactions (from the past year), and the
+scheduled transactions that will use it.
+
+The category can be given by ID or by name. It takes about ten API
+requests.`,
+	Example: `  ynabctl categories inspect Groceries -f table
+  ynabctl categories inspect @car`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		categoryID, err := resolveCategoryID(budgetID, args[0])
+		if err != nil {
+			return err
+		}
+		month, err := parseMonthArg("current")
+		if err != nil {
+			return err
+		}
+
+		category, err := apiClient.GetMonthCategory(budgetID, month, categoryID)
+		if err != nil {
+			return fmt.Errorf("failed to get category: %w", err)
+		}
+		history, err := fetchCategoryHistory(budgetID, categoryID, inspectTrendMonths)
+		if err != nil {
+			return err
+		}
+		since := time.Now().AddDate(-1, 0, 0).Format("2006-01-02")
+		transactions, err := apiClient.GetTransactionsByCategory(budgetID, categoryID, since)
+		if err != nil {
+			return fmt.Errorf("failed to get transactions: %w", err)
+		}
+		scheduled, err := apiClient.GetScheduledTransactions(budgetID)
+		if err != nil {
+			return fmt.Errorf("failed to get scheduled transactions: %w", err)
+		}
+
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(report.InspectCategory(*category, month, history, transactions, scheduled, inspectTransactions))
+	},
+}
+
+func init() {
+	categoriesCmd.AddCommand(categoriesInspectCmd)
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"os"
+
+	"github.com/langtind/ynabctl/internal/budgetplan"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/period"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	planOut    string
+	planMonths []string
+	planDryRun bool
+)
+
+var categoriesExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export budgeted amounts and goals as YAML",
+	Long: `Write the budgeted amount of every category for one or more months,
+and the goal targets, to a YAML plan file. Edit the file and bring the
+budget in line with it using "categories apply".
+
+Hidden categories are left out. Without --out the plan is written to
+stdout.`,
+	Example: `  ynabctl categories export --out categories.yaml
+  ynabctl categories export --month 2025-01 --month 2025-02 --out q1.yaml`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+
+		monthArgs := planMonths
+		if len(monthArgs) == 0 {
+			monthArgs = []string{"current"}
+		}
+
+		spinner := progress.Start("fetching months and categories")
+		var months []*ynab.Month
+		for _, arg := range monthArgs {
+			month, err := parseMonthArg(arg)
+			if err != nil {
+				spinner.Stop()
+				return err
+			}
+			m, err := apiClient.GetMonth(budgetID, month)
+			if err != nil {
+				spinner.Stop()
+				return fmt.Errorf("failed to get month %s: %w", month[:7], err)
+			}
+			months = append(months, m)
+		}
+		groups, err := apiClient.GetCategories(budgetID)
+		spinner.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to get categories: %w", err)
+		}
+
+		plan := budgetplan.Export(budgetID, months, groups)
+		if planOut == "" {
+			data, err := plan.Marshal()
+			if err != nil {
+				return err
+			}
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+		if err := plan.Write(planOut); err != nil {
+			return fmt.Errorf("failed to write plan: %w", err)
+		}
+		infof("Wrote %d months and %d goals to %s\n", len(plan.Months), len(plan.Goals), planOut)
+		return nil
+	},
+}
+
+var categoriesApplyCmd = &cobra.Command{
+	Use:   "apply <file>",
+	Short: "Apply budgeted amounts and goals from a YAML plan",
+	Long: `Bring the budget in line with a plan file written by "categories
+export" (or by hand). Only amounts that differ are changed. The changes
+are shown and must be confirmed unless --yes is given; --dry-run only
+shows them.
+
+Categories are matched by id, or by group and name when no id is given.
+A category in the plan that does not exist is an error. Goal targets can
+only be changed for categories that already have a goal.
+
+A plan that assigns more than To Be Budgeted holds in a month is refused
+unless --allow-negative-tbb is given.`,
+	Example: `  ynabctl categories apply categories.yaml --dry-run
+  ynabctl categories apply categories.yaml --yes --changelog applied.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+
+		plan, err := budgetplan.Load(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to load plan: %w", err)
+		}
+		if plan.BudgetID != "" && plan.BudgetID != budgetID {
+			return fmt.Errorf("%s is a plan for budget %s, not %s (use --budget)", args[0], plan.BudgetID, budgetID)
+		}
+
+		spinner := progress.Start("fetching months and categories")
+		months := map[string]*ynab.Month{}
+		for _, pm := range plan.Months {
+			m, err := apiClient.GetMonth(budgetID, pm.Month+"-01")
+			if err != nil {
+				spinner.Stop()
+				return fmt.Errorf("failed to get month %s: %w", pm.Month, err)
+			}
+			months[pm.Month] = m
+		}
+		groups, err := apiClient.GetCategories(budgetID)
+		spinner.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to get categories: %w", err)
+		}
+
+		changes, err := budgetplan.Diff(plan, months, groups)
+		if err != nil {
+			return err
+		}
+
+		formatter := output.New(getOutputFormat())
+		if planDryRun || len(changes) == 0 {
+			if len(changes) == 0 {
+				infof("The budget already matches %s.\n", args[0])
+			}
+			return formatter.Print(changes)
+		}
+
+		tbb := map[string]int64{}
+		assigned := map[string]int64{}
+		for _, c := range changes {
+			if c.Field != "goal_target" {
+				tbb[c.Month] = months[c.Month].ToBeBudgeted
+				assigned[c.Month] += c.After - c.Before
+			}
+		}
+		if err := guardTBB(tbb, assigned); err != nil {
+			return err
+		}
+
+		var diff output.Changes
+		byKey := map[string]budgetplan.Change{}
+		var keys []string
+		for _, c := range changes {
+			field := c.Label + " " + c.Field
+			if c.Month != "" {
+				field = c.Month + " " + field
+			}
+			diff.AddAmount(field, c.Before, c.After)
+			key := c.Month + "/" + c.CategoryID + "/" + c.Field
+			byKey[key] = c
+			keys = append(keys, key)
+		}
+		ok, err := confirmChanges("the budget", diff)
+		if err != nil || !ok {
+			return err
+		}
+
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		sum := fmt.Sprintf("%x", sha1.Sum(append([]byte(budgetID+"\x00"), data...)))
+		log := output.NewChangelog(cmd.CommandPath())
+		defer finishChangelog(log)
+		err = runJob("categories-apply-"+sum[:12], keys, 1, func(key string) error {
+			c := byKey[key]
+			var err error
+			if c.Field == "goal_target" {
+				_, err = apiClient.UpdateCategoryGoal(budgetID, c.CategoryID, c.After)
+			} else {
+				_, err = apiClient.UpdateCategory(budgetID, c.CategoryID, c.Month+"-01", c.After)
+			}
+			log.Record(c.Field, c.CategoryID, c.Month+" "+c.Label, c.After-c.Before, err)
+			if err != nil {
+				return fmt.Errorf("failed to update %s of %s: %w", c.Field, c.Label, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return formatter.Print(changes)
+	},
+}
+
+func init() {
+	categoriesCmd.AddCommand(categoriesExportCmd)
+	categoriesCmd.AddCommand(categoriesApplyCmd)
+
+	categoriesExportCmd.Flags().StringVar(&planOut, "out", "", "Output file (default: stdout)")
+	categoriesExportCmd.Flags().StringSliceVar(&planMonths, "month", nil, "Month to export ("+period.MonthHelp+"; repeatable; default: current)")
+	categoriesApplyCmd.Flags().BoolVar(&planDryRun, "dry-run", false, "Show the changes without applying them")
+	addTBBGuardFlag(categoriesApplyCmd)
+	addResumeFlag(categoriesApplyCmd)
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var commandsJSON bool
+
+// manifest describes the command tree for tools that drive ynabctl
+type manifest struct {
+	Version     string        `json:"version"`
+	GlobalFlags []flagInfo    `json:"global_flags"`
+	Commands    []commandInfo `json:"commands"`
+}
+
+// commandInfo is one command of the manifest. Runnable is false for
+// groups such as "transactions" that only hold subcommands.
+type commandInfo struct {
+	Name        string        `json:"name"`
+	Path        string        `json:"path"`
+	Usage       string        `json:"usage"`
+	Aliases     []string      `json:"aliases,omitempty"`
+	Short       string        `json:"short"`
+	Long        string        `json:"long,omitempty"`
+	Example     string        `json:"example,omitempty"`
+	Runnable    bool          `json:"runnable"`
+	Flags       []flagInfo    `json:"flags"`
+	Subcommands []commandInfo `json:"subcommands,omitempty"`
+}
+
+// flagInfo is one flag of a command. Type is the pflag type name, e.g.
+// "string", "bool", "stringArray" or "duration".
+type flagInfo struct {
+	Name      string `json:"name"`
+	Shorthand string `json:"shorthand,omitempty"`
+	Type      string `json:"type"`
+	Default   string `json:"default"`
+	Usage     string `json:"usage"`
+	Required  bool   `json:"required,omitempty"`
+}
+
+var commandsCmd = &cobra.Command{
+	Use:   "commands",
+	Short: "List all commands, or describe them as JSON for tools",
+	Long: `List every command with its short description. With --json, the
+whole command tree is printed as JSON instead: each command's path,
+usage, descriptions, examples and flags with their types, defaults and
+whether they are required, plus the global flags. GUIs, scripts and
+agents can use it to discover what ynabctl can do instead of parsing
+--help text. The JSON does not depend on --format.`,
+	Example: `  ynabctl commands
+  ynabctl commands --json | jq '.commands[].path'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root := cmd.Root()
+		if !commandsJSON {
+			for _, c := range visibleCommands(root) {
+				printCommandList(c)
+			}
+			return nil
+		}
+
+		m := manifest{
+			Version:     version,
+			GlobalFlags: flagInfos(root.PersistentFlags()),
+		}
+		for _, c := range visibleCommands(root) {
+			m.Commands = append(m.Commands, describeCommand(c))
+		}
+		data, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(os.Stdout, string(data))
+		return err
+	},
+}
+
+// visibleCommands returns the subcommands of c that are not hidden,
+// leaving out help
+func visibleCommands(c *cobra.Command) []*cobra.Command {
+	var cmds []*cobra.Command
+	for _, sub := range c.Commands() {
+		if sub.Hidden || sub.Name() == "help" {
+			continue
+		}
+		cmds = append(cmds, sub)
+	}
+	return cmds
+}
+
+// printCommandList prints c and its subcommands, one per line
+func printCommandList(c *cobra.Command) {
+	fmt.Printf("%-40s %s\n", strings.TrimPrefix(c.CommandPath(), c.Root().Name()+" "), c.Short)
+	for _, sub := range visibleCommands(c) {
+		printCommandList(sub)
+	}
+}
+
+// describeCommand returns the manifest entry of c and its subcommands
+func describeCommand(c *cobra.Command) commandInfo {
+	info := commandInfo{
+		Name:     c.Name(),
+		Path:     c.CommandPath(),
+		Usage:    c.UseLine(),
+		Aliases:  c.Aliases,
+		Short:    c.Short,
+		Long:     c.Long,
+		Example:  c.Example,
+		Runnable: c.Runnable(),
+		Flags:    flagInfos(c.LocalNonPersistentFlags()),
+	}
+	// persistent flags of groups, e.g. "report --period", belong to the
+	// group's subcommands too; they are listed on the group
+	if c.HasParent() && c.PersistentFlags().HasFlags() {
+		info.Flags = append(info.Flags, flagInfos(c.PersistentFlags())...)
+	}
+	for _, sub := range visibleCommands(c) {
+		info.Subcommands = append(info.Subcommands, describeCommand(sub))
+	}
+	return info
+}
+
+// flagInfos describes the visible flags of a set, sorted by name
+func flagInfos(fs *pflag.FlagSet) []flagInfo {
+	flags := []flagInfo{}
+	fs.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden || f.Name == "help" {
+			return
+		}
+		_, required := f.Annotations[cobra.BashCompOneRequiredFlag]
+		flags = append(flags, flagInfo{
+			Name:      f.Name,
+			Shorthand: f.Shorthand,
+			Type:      f.Value.Type(),
+			Default:   f.DefValue,
+			Usage:     f.Usage,
+			Required:  required,
+		})
+	})
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+	return flags
+}
+
+func init() {
+	rootCmd.AddCommand(commandsCmd)
+
+	commandsCmd.Flags().BoolVar(&commandsJSON, "json", false, "Describe the command tree as JSON")
+}
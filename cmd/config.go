@@ -2,8 +2,16 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/langtind/ynabctl/internal/config"
+	"github.com/langtind/ynabctl/internal/i18n"
+	"github.com/langtind/ynabctl/internal/names"
+	"github.com/langtind/ynabctl/pkg/ynab"
 	"github.com/spf13/cobra"
 )
 
@@ -25,25 +33,40 @@ var configShowCmd = &cobra.Command{
 		fmt.Printf("Config file: %s\n\n", config.GetConfigFile())
 
 		// Mask the token for security
-		token := cfg.Token
-		if token != "" {
-			if len(token) > 8 {
-				token = token[:4] + "..." + token[len(token)-4:]
-			} else {
-				token = "****"
-			}
-		} else {
-			token = "(not set)"
-		}
-
-		fmt.Printf("Token:          %s\n", token)
+		fmt.Printf("Token:          %s\n", config.MaskToken(cfg.Token))
+		fmt.Printf("Token Type:     %s\n", tokenTypeName(cfg.TokenType))
 		fmt.Printf("Default Budget: %s\n", valueOrNotSet(cfg.DefaultBudget))
 		fmt.Printf("Format:         %s\n", valueOrNotSet(cfg.Format))
+		fmt.Printf("Savings:        %s\n", valueOrNotSet(strings.Join(cfg.SavingsCategories, ", ")))
+		fmt.Printf("Funding order:  %s\n", valueOrNotSet(strings.Join(cfg.FundingPriority, ", ")))
+		fmt.Printf("Report exclude: %s\n", valueOrNotSet(strings.Join(append(cfg.ExcludeAccounts, cfg.ExcludeCategories...), ", ")))
+		fmt.Printf("Protected:      %s\n", valueOrNotSet(strings.Join(cfg.ProtectedBudgets, ", ")))
+		var caps []string
+		for _, c := range cfg.GroupCaps {
+			caps = append(caps, fmt.Sprintf("%s %.2f", c.Group, c.Cap))
+		}
+		fmt.Printf("Group caps:     %s\n", valueOrNotSet(strings.Join(caps, ", ")))
+		var allowances []string
+		for _, a := range cfg.Allowances {
+			allowances = append(allowances, fmt.Sprintf("%s %.2f/week in %s", a.Member, a.Weekly, a.Category))
+		}
+		fmt.Printf("Allowances:     %s\n", valueOrNotSet(strings.Join(allowances, ", ")))
+		fmt.Printf("Language:       %s\n", valueOrNotSet(cfg.Lang))
+		fmt.Printf("CA bundle:      %s\n", valueOrNotSet(cfg.CABundle))
+		fmt.Printf("Cache TTL:      %s\n", valueOrNotSet(cfg.CacheTTL))
+		var aliases []string
+		for name, target := range cfg.Aliases {
+			aliases = append(aliases, "@"+name+" = "+target)
+		}
+		sort.Strings(aliases)
+		fmt.Printf("Aliases:        %s\n", valueOrNotSet(strings.Join(aliases, ", ")))
 
 		return nil
 	},
 }
 
+var setTokenOAuth bool
+
 var configSetTokenCmd = &cobra.Command{
 	Use:   "set-token <token>",
 	Short: "Set the YNAB API token",
@@ -54,11 +77,18 @@ You can obtain a token from YNAB:
   2. Click on your account name (top left)
   3. Go to "Account Settings"
   4. Click on "Developer Settings"
-  5. Create a new Personal Access Token`,
+  5. Create a new Personal Access Token
+
+Pass --oauth when the token is an OAuth access token obtained by another
+application; "ynabctl user" shows the type.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		token := args[0]
-		if err := config.SetToken(token); err != nil {
+		tokenType := ""
+		if setTokenOAuth {
+			tokenType = "oauth"
+		}
+		if err := config.SetToken(token, tokenType); err != nil {
 			return fmt.Errorf("failed to save token: %w", err)
 		}
 		fmt.Println("Token saved successfully.")
@@ -87,14 +117,14 @@ You can find budget IDs by running: ynabctl budgets list`,
 var configSetFormatCmd = &cobra.Command{
 	Use:   "set-format <format>",
 	Short: "Set the default output format",
-	Long: `Set the default output format (json or table).
+	Long: `Set the default output format (json, table or markdown).
 
 This format will be used when the --format flag is not specified.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		format := args[0]
-		if format != "json" && format != "table" {
-			return fmt.Errorf("invalid format: %s (must be 'json' or 'table')", format)
+		if format != "json" && format != "table" && format != "markdown" {
+			return fmt.Errorf("invalid format: %s (must be 'json', 'table' or 'markdown')", format)
 		}
 		if err := config.SetFormat(format); err != nil {
 			return fmt.Errorf("failed to save format: %w", err)
@@ -104,6 +134,388 @@ This format will be used when the --format flag is not specified.`,
 	},
 }
 
+var configSetLangCmd = &cobra.Command{
+	Use:   "set-lang [language]",
+	Short: "Set the language of messages and table headers",
+	Long: `Set the language of prompts, error messages and table headers:
+en (English) or nb (Norwegian bokmål; no and nn are accepted too).
+
+Run without arguments to follow the locale again. --lang and YNAB_LANG
+override the setting. JSON output and command help stay in English.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lang := ""
+		if len(args) == 1 {
+			var ok bool
+			lang, ok = i18n.Normalize(args[0])
+			if !ok {
+				return fmt.Errorf("unsupported language %q (supported: %s)", args[0], strings.Join(i18n.Languages(), ", "))
+			}
+		}
+		if err := config.SetLang(lang); err != nil {
+			return fmt.Errorf("failed to save language: %w", err)
+		}
+		if lang == "" {
+			fmt.Println("Language cleared; following the locale")
+			return nil
+		}
+		fmt.Printf("Language set to: %s\n", lang)
+		return nil
+	},
+}
+
+var configSetCABundleCmd = &cobra.Command{
+	Use:   "set-ca-bundle [file]",
+	Short: "Trust extra CA certificates for API requests",
+	Long: `Set a PEM file of CA certificates trusted in addition to the system
+roots, e.g. the CA of a corporate proxy that inspects TLS traffic.
+
+Run without arguments to trust the system roots only. --ca-bundle and
+YNAB_CA_BUNDLE override the setting. The proxy itself is taken from
+HTTPS_PROXY, with NO_PROXY listing hosts to reach directly.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := ""
+		if len(args) == 1 {
+			abs, err := filepath.Abs(args[0])
+			if err != nil {
+				return err
+			}
+			if _, err := ynab.LoadCABundle(abs); err != nil {
+				return err
+			}
+			path = abs
+		}
+		if err := config.SetCABundle(path); err != nil {
+			return fmt.Errorf("failed to save CA bundle: %w", err)
+		}
+		if path == "" {
+			fmt.Println("CA bundle cleared; trusting the system roots only")
+			return nil
+		}
+		fmt.Printf("CA bundle set to: %s\n", path)
+		return nil
+	},
+}
+
+var configSetCacheTTLCmd = &cobra.Command{
+	Use:   "set-cache-ttl [duration]",
+	Short: "Serve repeated reads from the local response cache",
+	Long: `Set how long a GET response is served from the local response cache
+before it is fetched again, e.g. 5m or 1h, so scripts that list the same
+categories and payees over and over stay under YNAB's limit of 200
+requests per hour. Changes made by ynabctl drop the cached responses of
+their budget; changes made elsewhere show up once the TTL has passed.
+
+Run without arguments to turn the cache off. --cache-ttl and
+YNAB_CACHE_TTL override the setting, and --no-cache skips the cache for
+one run.`,
+	Example: `  ynabctl config set-cache-ttl 10m
+  ynabctl config set-cache-ttl`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ttl := ""
+		if len(args) == 1 {
+			d, err := time.ParseDuration(args[0])
+			if err != nil || d <= 0 {
+				return fmt.Errorf("invalid TTL %q (want a duration such as 5m or 1h)", args[0])
+			}
+			ttl = d.String()
+		}
+		if err := config.SetCacheTTL(ttl); err != nil {
+			return fmt.Errorf("failed to save cache TTL: %w", err)
+		}
+		if ttl == "" {
+			fmt.Println("Response cache turned off")
+			return nil
+		}
+		fmt.Printf("Cache TTL set to: %s\n", ttl)
+		return nil
+	},
+}
+
+var configSetFundingPriorityCmd = &cobra.Command{
+	Use:   "set-funding-priority [category]...",
+	Short: "Set the order in which \"fund\" gives categories money",
+	Long: `Set the categories (names or IDs) that "ynabctl fund" gives money to,
+most important first.
+
+Run without arguments to clear the list.`,
+	Example: `  ynabctl config set-funding-priority Rent Electricity Groceries "Car Repairs"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.SetFundingPriority(args); err != nil {
+			return fmt.Errorf("failed to save funding priority: %w", err)
+		}
+		if len(args) == 0 {
+			fmt.Println("Funding priority cleared")
+			return nil
+		}
+		fmt.Printf("Funding priority set to: %s\n", strings.Join(args, ", "))
+		return nil
+	},
+}
+
+var (
+	excludeAccounts   []string
+	excludeCategories []string
+)
+
+var configSetReportExcludeCmd = &cobra.Command{
+	Use:   "set-report-exclude",
+	Short: "Set the accounts and categories left out of reports",
+	Long: `Set the accounts and categories (names or IDs) that reports leave out
+by default, e.g. a reimbursable business card that would skew personal
+spending. Reports include them again with --include-all.
+
+Run without flags to clear both lists.`,
+	Example: `  ynabctl config set-report-exclude --account "Work Visa" --category Reimbursable`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.SetReportExclusions(excludeAccounts, excludeCategories); err != nil {
+			return fmt.Errorf("failed to save report exclusions: %w", err)
+		}
+		if len(excludeAccounts)+len(excludeCategories) == 0 {
+			fmt.Println("Report exclusions cleared")
+			return nil
+		}
+		fmt.Printf("Reports exclude: %s\n", strings.Join(append(excludeAccounts, excludeCategories...), ", "))
+		return nil
+	},
+}
+
+var configSetSavingsCategoriesCmd = &cobra.Command{
+	Use:   "set-savings-categories [category]...",
+	Short: "Set the categories counted as savings",
+	Long: `Set the categories (names or IDs) whose balances count as the
+emergency fund in "ynabctl report runway".
+
+Run without arguments to clear the list.`,
+	Example: `  ynabctl config set-savings-categories "Emergency Fund" "Savings: Buffer"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.SetSavingsCategories(args); err != nil {
+			return fmt.Errorf("failed to save savings categories: %w", err)
+		}
+		if len(args) == 0 {
+			fmt.Println("Savings categories cleared")
+			return nil
+		}
+		fmt.Printf("Savings categories set to: %s\n", strings.Join(args, ", "))
+		return nil
+	},
+}
+
+var groupCapWarnAt float64
+
+var configSetGroupCapCmd = &cobra.Command{
+	Use:   "set-group-cap <group> [amount]",
+	Short: "Set the monthly spending cap of a category group",
+	Long: `Set the monthly spending cap of a category group, checked by
+"ynabctl caps status". The group is named as in YNAB (case and emojis
+are ignored when matching). Run without an amount to remove the cap.`,
+	Example: `  ynabctl config set-group-cap "Fun Money" 2000
+  ynabctl config set-group-cap "Eating Out" 4500 --warn-at 0.9
+  ynabctl config set-group-cap "Fun Money"`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		group := args[0]
+		var limit float64
+		if len(args) == 2 {
+			var err error
+			limit, err = strconv.ParseFloat(args[1], 64)
+			if err != nil || limit <= 0 {
+				return fmt.Errorf("invalid amount %q: must be a positive number", args[1])
+			}
+		}
+		if groupCapWarnAt < 0 || groupCapWarnAt > 1 {
+			return fmt.Errorf("--warn-at must be between 0 and 1")
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			cfg = &config.Config{}
+		}
+		caps := []config.GroupCap{}
+		for _, c := range cfg.GroupCaps {
+			if !names.Equal(c.Group, group) {
+				caps = append(caps, c)
+			}
+		}
+		if len(args) == 2 {
+			caps = append(caps, config.GroupCap{Group: group, Cap: limit, WarnAt: groupCapWarnAt})
+		}
+		if err := config.SetGroupCaps(caps); err != nil {
+			return fmt.Errorf("failed to save spending caps: %w", err)
+		}
+		if len(args) == 1 {
+			fmt.Printf("Spending cap of %s removed\n", group)
+			return nil
+		}
+		fmt.Printf("Spending cap of %s set to: %.2f\n", group, limit)
+		return nil
+	},
+}
+
+var allowanceFrom string
+
+var configSetAllowanceCmd = &cobra.Command{
+	Use:   "set-allowance <member> [category] [weekly]",
+	Short: "Set a family member's weekly allowance",
+	Long: `Set the category holding a family member's allowance and the amount
+it is credited each week by "ynabctl allowance credit". The money is
+assigned from Ready to Assign, or moved from the category given with
+--from. Run with only the member to remove the allowance.`,
+	Example: `  ynabctl config set-allowance emma "Emma's allowance" 50
+  ynabctl config set-allowance noah "Noah's allowance" 30 --from "Kids"
+  ynabctl config set-allowance emma`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 && len(args) != 3 {
+			return fmt.Errorf("want a member, or a member, category and weekly amount")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		member := args[0]
+		var weekly float64
+		if len(args) == 3 {
+			var err error
+			weekly, err = strconv.ParseFloat(args[2], 64)
+			if err != nil || weekly <= 0 {
+				return fmt.Errorf("invalid amount %q: must be a positive number", args[2])
+			}
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			cfg = &config.Config{}
+		}
+		allowances := []config.Allowance{}
+		for _, a := range cfg.Allowances {
+			if !names.Equal(a.Member, member) {
+				allowances = append(allowances, a)
+			}
+		}
+		if len(args) == 3 {
+			allowances = append(allowances, config.Allowance{Member: member, Category: args[1], Weekly: weekly, From: allowanceFrom})
+		}
+		if err := config.SetAllowances(allowances); err != nil {
+			return fmt.Errorf("failed to save allowances: %w", err)
+		}
+		if len(args) == 1 {
+			fmt.Printf("Allowance of %s removed\n", member)
+			return nil
+		}
+		fmt.Printf("Allowance of %s set to: %.2f a week in %s\n", member, weekly, args[1])
+		return nil
+	},
+}
+
+var configSetAliasCmd = &cobra.Command{
+	Use:   "set-alias <name> [id]",
+	Short: "Set a short alias for an ID",
+	Long: `Give a budget, account, category or payee ID a short name that can be
+used as "@name" wherever an ID or name is accepted. Unlike names,
+aliases keep working when the record is renamed in YNAB. Names are not
+case sensitive. Run without an ID to remove the alias.
+
+Aliases are kept in the [aliases] table of the config file:
+
+  [aliases]
+  visa = "3fa85f64-5717-4562-b3fc-2c963f66afa6"`,
+	Example: `  ynabctl config set-alias visa 3fa85f64-5717-4562-b3fc-2c963f66afa6
+  ynabctl transactions list --account @visa
+  ynabctl config set-alias visa`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := strings.TrimPrefix(args[0], "@")
+		target := ""
+		if len(args) == 2 {
+			target = args[1]
+		}
+		if err := config.SetAlias(name, target); err != nil {
+			return fmt.Errorf("failed to save alias: %w", err)
+		}
+		if target == "" {
+			fmt.Printf("Alias @%s removed\n", name)
+			return nil
+		}
+		fmt.Printf("Alias @%s set to: %s\n", name, target)
+		return nil
+	},
+}
+
+var configSetProtectedBudgetsCmd = &cobra.Command{
+	Use:   "set-protected-budgets [budget-id]...",
+	Short: "Set the budgets guarded against accidental changes",
+	Long: `Set the budgets that mutating commands only change with --force, or
+after the budget name is typed to confirm.
+
+Use this for real budgets when scripts normally run against a test
+budget. Run without arguments to clear the list.`,
+	Example: `  ynabctl config set-protected-budgets <budget-id>`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.SetProtectedBudgets(args); err != nil {
+			return fmt.Errorf("failed to save protected budgets: %w", err)
+		}
+		if len(args) == 0 {
+			fmt.Println("Protected budgets cleared")
+			return nil
+		}
+		fmt.Printf("Protected budgets set to: %s\n", strings.Join(args, ", "))
+		return nil
+	},
+}
+
+var doctorNoFix bool
+
+var configDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the configuration for problems",
+	Long: `Show the effective configuration and where each value comes from:
+a flag, an environment variable, the config file or the default. Flags
+win over environment variables, which win over the config file.
+
+Common problems are reported: a missing token or one with stray
+whitespace, a default budget that is not a budget ID, an unknown format
+and a config directory that cannot be written. A config file readable by
+other users is made private (0600) unless --no-fix is given.
+
+Exits with an error if a problem remains.`,
+	Example: `  ynabctl config doctor
+  YNAB_DEFAULT_BUDGET=last-used ynabctl config doctor --budget <budget-id>`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		d := config.Diagnose(config.Flags{
+			Token:     tokenFlag,
+			TokenFile: tokenFileFlag,
+			Budget:    budgetID,
+			Format:    outputFormat,
+			Lang:      langFlag,
+			CABundle:  caBundleFlag,
+		}, !doctorNoFix)
+
+		fmt.Printf("Config file: %s\n\n", d.ConfigFile)
+		for _, s := range d.Settings {
+			fmt.Printf("%-20s %-40s %s\n", s.Key, valueOrNotSet(s.Value), s.Origin)
+		}
+		fmt.Println()
+
+		remaining := 0
+		for _, p := range d.Problems {
+			status := "problem"
+			if p.Fixed {
+				status = "fixed"
+			} else {
+				remaining++
+			}
+			fmt.Printf("[%s] %s: %s\n", status, p.Key, p.Message)
+		}
+		if remaining > 0 {
+			return fmt.Errorf("%d configuration problems found", remaining)
+		}
+		fmt.Println("No problems found.")
+		return nil
+	},
+}
+
 func valueOrNotSet(s string) string {
 	if s == "" {
 		return "(not set)"
@@ -117,4 +529,22 @@ func init() {
 	configCmd.AddCommand(configSetTokenCmd)
 	configCmd.AddCommand(configSetDefaultBudgetCmd)
 	configCmd.AddCommand(configSetFormatCmd)
+	configCmd.AddCommand(configSetLangCmd)
+	configCmd.AddCommand(configSetCABundleCmd)
+	configCmd.AddCommand(configSetCacheTTLCmd)
+	configCmd.AddCommand(configSetSavingsCategoriesCmd)
+	configCmd.AddCommand(configSetFundingPriorityCmd)
+	configCmd.AddCommand(configSetReportExcludeCmd)
+	configCmd.AddCommand(configSetProtectedBudgetsCmd)
+	configCmd.AddCommand(configSetGroupCapCmd)
+	configCmd.AddCommand(configSetAliasCmd)
+	configCmd.AddCommand(configSetAllowanceCmd)
+	configSetAllowanceCmd.Flags().StringVar(&allowanceFrom, "from", "", "Category to move the allowance from (default: Ready to Assign)")
+	configSetGroupCapCmd.Flags().Float64Var(&groupCapWarnAt, "warn-at", 0, "Share of the cap (0-1) at which to warn (default 0.8)")
+	configCmd.AddCommand(configDoctorCmd)
+
+	configSetTokenCmd.Flags().BoolVar(&setTokenOAuth, "oauth", false, "The token is an OAuth access token, not a personal access token")
+	configDoctorCmd.Flags().BoolVar(&doctorNoFix, "no-fix", false, "Only report problems, do not change file permissions")
+	configSetReportExcludeCmd.Flags().StringSliceVar(&excludeAccounts, "account", nil, "Account to leave out of reports (repeatable)")
+	configSetReportExcludeCmd.Flags().StringSliceVar(&excludeCategories, "category", nil, "Category to leave out of reports (repeatable)")
 }
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/langtind/ynabctl/internal/i18n"
+	"github.com/langtind/ynabctl/internal/output"
+)
+
+// assumeYes skips interactive confirmation prompts
+var assumeYes bool
+
+// confirmChanges prints the pending field changes to stderr and asks the
+// user to confirm them. It returns false if there is nothing to change or
+// the user declines. With --yes the diff is still shown but no prompt is
+// made.
+func confirmChanges(what string, changes output.Changes) (bool, error) {
+	if len(changes) == 0 {
+		fmt.Fprint(os.Stderr, i18n.T("No changes to %s.\n", what))
+		return false, nil
+	}
+
+	fmt.Fprint(os.Stderr, i18n.T("Changes to %s:\n", what))
+	output.PrintDiff(os.Stderr, changes, output.UseColor(os.Stderr))
+
+	return confirmOrAbort(i18n.T("Apply these changes?"))
+}
+
+// confirmOrAbort is confirm that says "Aborted." when the user declines
+func confirmOrAbort(question string) (bool, error) {
+	ok, err := confirm(question)
+	if err == nil && !ok {
+		fmt.Fprintln(os.Stderr, i18n.T("Aborted."))
+	}
+	return ok, err
+}
+
+// confirm asks a yes/no question on stderr and reads the answer from
+// stdin. It refuses to guess when stdin is not a terminal.
+func confirm(question string) (bool, error) {
+	if assumeYes {
+		return true, nil
+	}
+	if !output.IsTerminal(os.Stdin) {
+		return false, errors.New(i18n.T("confirmation required but stdin is not a terminal; pass --yes to proceed"))
+	}
+
+	fmt.Fprintf(os.Stderr, "%s %s ", question, i18n.T("[y/N]"))
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		return false, nil
+	}
+	return i18n.IsYes(answer), nil
+}
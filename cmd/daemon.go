@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/langtind/ynabctl/internal/daemon"
+	"github.com/spf13/cobra"
+)
+
+var (
+	daemonSchedule []string
+	daemonListen   string
+	daemonLogFile  string
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon --schedule \"<schedule>: <command>\"...",
+	Short: "Run ynabctl commands on schedules in one long-running process",
+	Long: `Run ynabctl commands on schedules without cron or Task Scheduler
+entries. Each job is a schedule and a command line, given as
+--schedule "<schedule>: <command>" with one flag per job. Schedules are:
+
+  hourly          at the start of every hour
+  daily           every day at midnight
+  daily@06:30     every day at 06:30
+  weekly          every Monday at midnight (weekly@07:00 at 07:00)
+  30m, 6h         every interval, counted from the previous run
+
+The command is split into arguments like a shell would, so quote
+arguments holding spaces: "daily: snapshot --out-dir 'my data'". Each
+run is a separate ynabctl process with the same budget and token;
+commands that ask for confirmation need --yes in the job. A job still
+running when it is due again skips that run.
+
+Runs and the output of the jobs are logged with timestamps to stderr, or
+appended to --log. The state of every job (next and last run, duration,
+last error, failure count) is served as JSON on
+http://<--listen>/status; --listen "" turns this off. The daemon stops on
+Ctrl-C or SIGTERM.`,
+	Example: `  ynabctl daemon --schedule "hourly: payees normalize --rules rules.yaml --yes" \
+    --schedule "daily: snapshot --out-dir data" --schedule "daily@06:00: doctor references"
+  ynabctl daemon --schedule "weekly@07:00: report weekly" --log ~/ynabctl.log --listen 127.0.0.1:8765
+  curl -s localhost:8765/status | jq '.jobs[] | {name, next_run, last_error}'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jobs, err := daemon.ParseJobs(daemonSchedule)
+		if err != nil {
+			return err
+		}
+		for _, job := range jobs {
+			c, _, err := cmd.Root().Find(job.Args)
+			if err != nil || c == cmd || c == cmd.Root() || !c.Runnable() {
+				return fmt.Errorf("job %q is not a ynabctl command", job.Name)
+			}
+		}
+		exe, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to find the ynabctl executable: %w", err)
+		}
+
+		var logOut io.Writer = os.Stderr
+		if daemonLogFile != "" {
+			f, err := os.OpenFile(daemonLogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+			if err != nil {
+				return fmt.Errorf("failed to open log: %w", err)
+			}
+			defer f.Close()
+			logOut = f
+		}
+		logger := log.New(logOut, "", log.LstdFlags)
+
+		d := daemon.New(jobs, func(ctx context.Context, job daemon.Job, out io.Writer) error {
+			c := exec.CommandContext(ctx, exe, append(daemonGlobalArgs(), job.Args...)...)
+			c.Env = daemonEnv()
+			c.Stdout, c.Stderr = out, out
+			return c.Run()
+		}, logger)
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if daemonListen != "" {
+			ln, err := net.Listen("tcp", daemonListen)
+			if err != nil {
+				return fmt.Errorf("failed to listen for status requests: %w", err)
+			}
+			srv := &http.Server{Handler: d}
+			go srv.Serve(ln)
+			defer srv.Close()
+			logger.Printf("status on http://%s/status", ln.Addr())
+		}
+
+		logger.Printf("running %d jobs; stop with Ctrl-C", len(jobs))
+		err = d.Start(ctx)
+		logger.Printf("stopped")
+		return err
+	},
+}
+
+// daemonGlobalArgs returns the global flags the jobs inherit
+func daemonGlobalArgs() []string {
+	var args []string
+	if budgetID != "" {
+		args = append(args, "--budget", budgetID)
+	}
+	if caBundleFlag != "" {
+		args = append(args, "--ca-bundle", caBundleFlag)
+	}
+	if langFlag != "" {
+		args = append(args, "--lang", langFlag)
+	}
+	if noCache {
+		args = append(args, "--no-cache")
+	} else if cacheTTLFlag > 0 {
+		args = append(args, "--cache-ttl", cacheTTLFlag.String())
+	}
+	return args
+}
+
+// daemonEnv returns the environment of the jobs: the daemon's own, with
+// the token it uses, however it was given, in YNAB_TOKEN
+func daemonEnv() []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, "YNAB_TOKEN=") && !strings.HasPrefix(kv, "YNAB_TOKEN_FILE=") {
+			env = append(env, kv)
+		}
+	}
+	return append(env, "YNAB_TOKEN="+cfg.Token)
+}
+
+func init() {
+	rootCmd.AddCommand(daemonCmd)
+
+	daemonCmd.Flags().StringArrayVar(&daemonSchedule, "schedule", nil, "A job as \"<schedule>: <command>\" (repeatable, one job each)")
+	daemonCmd.Flags().StringVar(&daemonListen, "listen", "127.0.0.1:8765", "Address of the status endpoint (\"\" to disable)")
+	daemonCmd.Flags().StringVar(&daemonLogFile, "log", "", "Append the log to this file instead of stderr")
+	_ = daemonCmd.MarkFlagRequired("schedule")
+}
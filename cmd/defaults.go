@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// applyCommandDefaults sets the flags of cmd that were not given on the
+// command line to the values in its [defaults.<command path>] section
+// of the config. A list sets a repeatable flag once per item.
+func applyCommandDefaults(cmd *cobra.Command) error {
+	path := strings.Fields(cmd.CommandPath())[1:]
+	defaults := cfg.CommandDefaults(path)
+	section := "defaults." + strings.Join(path, ".")
+
+	flagNames := make([]string, 0, len(defaults))
+	for name := range defaults {
+		flagNames = append(flagNames, name)
+	}
+	sort.Strings(flagNames)
+
+	for _, name := range flagNames {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil {
+			return fmt.Errorf("unknown flag %q in [%s] of the config", name, section)
+		}
+		if flag.Changed {
+			continue
+		}
+		values, ok := defaults[name].([]interface{})
+		if !ok {
+			values = []interface{}{defaults[name]}
+		}
+		for _, v := range values {
+			if err := cmd.Flags().Set(name, fmt.Sprint(v)); err != nil {
+				return fmt.Errorf("invalid value for %s in [%s] of the config: %w", name, section, err)
+			}
+		}
+	}
+	return nil
+}
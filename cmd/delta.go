@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/langtind/ynabctl/internal/deltacache"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+// listDelta makes list commands read a local copy that is brought up to
+// date with only the changes since the previous run
+var listDelta bool
+
+// addDeltaFlag adds --delta to a list command
+func addDeltaFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&listDelta, "delta", false, "Fetch only changes since the last --delta run and merge them into a local copy")
+}
+
+// deltaItems returns all items of an endpoint of the budget from its
+// local copy, after fetching the changes since it was last updated.
+// complete may fill in what a delta response leaves out of changed items.
+func deltaItems[T any](budgetID, endpoint string, fetch func(knowledge int64) ([]T, int64, error),
+	id func(T) string, deleted func(T) bool, complete func(cached, changed []T) []T) ([]T, error) {
+	cache, err := deltacache.Load[T](deltacache.Path(budgetID, endpoint))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: rebuilding %s cache: %v\n", endpoint, err)
+	}
+	if offlineMode {
+		if cache.ServerKnowledge == 0 {
+			return nil, fmt.Errorf("%w: no cached %s for budget %s", ynab.ErrOffline, endpoint, budgetID)
+		}
+		noteOfflineAsOf(cache.UpdatedAt)
+		return cache.Items, nil
+	}
+
+	spinner := progress.Start("fetching " + endpoint)
+	changed, knowledge, err := fetch(cache.ServerKnowledge)
+	spinner.Stop()
+	if err != nil {
+		return nil, err
+	}
+	if complete != nil {
+		changed = complete(cache.Items, changed)
+	}
+	cache.Merge(changed, knowledge, id, deleted)
+	if err := cache.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save %s cache: %v\n", endpoint, err)
+	}
+	return cache.Items, nil
+}
+
+func deltaAccounts(budgetID string) ([]ynab.Account, error) {
+	return deltaItems(budgetID, "accounts", func(k int64) ([]ynab.Account, int64, error) {
+		return apiClient.GetAccountsSince(budgetID, k)
+	}, func(a ynab.Account) string { return a.ID }, func(a ynab.Account) bool { return a.Deleted }, nil)
+}
+
+func deltaCategories(budgetID string) ([]ynab.CategoryGroup, error) {
+	return deltaItems(budgetID, "categories", func(k int64) ([]ynab.CategoryGroup, int64, error) {
+		return apiClient.GetCategoriesSince(budgetID, k)
+	}, func(g ynab.CategoryGroup) string { return g.ID }, func(g ynab.CategoryGroup) bool { return g.Deleted }, deltacache.CompleteGroups)
+}
+
+func deltaPayees(budgetID string) ([]ynab.Payee, error) {
+	return deltaItems(budgetID, "payees", func(k int64) ([]ynab.Payee, int64, error) {
+		return apiClient.GetPayeesSince(budgetID, k)
+	}, func(p ynab.Payee) string { return p.ID }, func(p ynab.Payee) bool { return p.Deleted }, nil)
+}
+
+func deltaScheduled(budgetID string) ([]ynab.ScheduledTransaction, error) {
+	return deltaItems(budgetID, "scheduled_transactions", func(k int64) ([]ynab.ScheduledTransaction, int64, error) {
+		return apiClient.GetScheduledTransactionsSince(budgetID, k)
+	}, func(s ynab.ScheduledTransaction) string { return s.ID }, func(s ynab.ScheduledTransaction) bool { return s.Deleted }, nil)
+}
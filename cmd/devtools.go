@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/names"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/internal/seed"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var devtoolsCmd = &cobra.Command{
+	Use:   "devtools",
+	Short: "Tools for testing automations and developing ynabctl",
+}
+
+var (
+	seedTransactions int
+	seedMonths       int
+	seedValue        int64
+	seedDryRun       bool
+)
+
+// seedBatchSize is the number of transactions created per request
+const seedBatchSize = 100
+
+var devtoolsSeedCmd = &cobra.Command{
+	Use:   "seed",
+	Short: "Fill a test budget with realistic fake data",
+	Long: `Populate a test budget with fake accounts, category groups, payees
+and transactions: monthly bills and salary plus everyday purchases spread
+over the last --months months. Use it to try out automations, or to work
+on ynabctl without a personal budget to risk.
+
+The budget must be given with --budget; the default budget is never
+seeded. Accounts and categories that already exist (by name) are reused,
+so seeding again only adds transactions. Seeding again with the same
+--seed adds nothing: YNAB skips the import_ids it has already seen.
+
+Every seeded transaction has an import_id starting with "YNABCTL-SEED:",
+so they can all be removed again with
+"ynabctl transactions purge --import-prefix YNABCTL-SEED:".`,
+	Example: `  ynabctl devtools seed --budget <test-budget> --transactions 500
+  ynabctl devtools seed --budget <test-budget> --months 12 --seed 42 --dry-run -f table`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !cmd.Flags().Changed("budget") {
+			return fmt.Errorf("--budget is required: name the test budget to seed explicitly")
+		}
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		if seedTransactions < 1 || seedMonths < 1 {
+			return fmt.Errorf("--transactions and --months must be positive")
+		}
+		if !cmd.Flags().Changed("seed") {
+			seedValue = time.Now().UnixNano() % 1_000_000
+		}
+
+		data := seed.Generate(seedValue, seedTransactions, seedMonths, time.Now())
+		summary := &seed.Summary{
+			Seed:         seedValue,
+			Transactions: len(data.Transactions),
+			ImportPrefix: seed.ImportPrefix,
+		}
+		if n := len(data.Transactions); n > 0 {
+			summary.FirstDate, summary.LastDate = data.Transactions[0].Date, data.Transactions[n-1].Date
+		}
+
+		spinner := progress.Start("reading the budget")
+		accounts, err := apiClient.GetAccounts(budgetID)
+		if err != nil {
+			spinner.Stop()
+			return fmt.Errorf("failed to get accounts: %w", err)
+		}
+		groups, err := apiClient.GetCategories(budgetID)
+		spinner.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to get categories: %w", err)
+		}
+
+		accountIDs := map[string]string{}
+		var newAccounts []seed.Account
+		for _, a := range data.Accounts {
+			if id := findAccount(accounts, a.Name); id != "" {
+				accountIDs[a.Name] = id
+			} else {
+				newAccounts = append(newAccounts, a)
+			}
+		}
+		categoryIDs := map[string]string{}
+		var newCategories int
+		for _, g := range data.Groups {
+			for _, c := range g.Categories {
+				if id := findCategory(groups, g.Name, c); id != "" {
+					categoryIDs[g.Name+"/"+c] = id
+				} else {
+					newCategories++
+				}
+			}
+		}
+		inflowID := findCategory(groups, "", seed.InflowCategory)
+		if inflowID == "" {
+			return fmt.Errorf("the budget has no %q category", seed.InflowCategory)
+		}
+		categoryIDs["/"+seed.InflowCategory] = inflowID
+		summary.Accounts, summary.Categories = len(newAccounts), newCategories
+
+		formatter := output.New(getOutputFormat())
+		if seedDryRun {
+			return formatter.Print(summary)
+		}
+		ok, err := confirmOrAbort(fmt.Sprintf("Create %d accounts, %d categories and %d transactions in budget %s?",
+			len(newAccounts), newCategories, len(data.Transactions), budgetID))
+		if err != nil || !ok {
+			return err
+		}
+
+		log := output.NewChangelog(cmd.CommandPath())
+		defer finishChangelog(log)
+
+		for _, a := range newAccounts {
+			created, err := apiClient.CreateAccount(budgetID, ynab.SaveAccount{Name: a.Name, Type: a.Type, Balance: a.Balance})
+			if err != nil {
+				log.Record("create account", "", a.Name, a.Balance, err)
+				return fmt.Errorf("failed to create account %s: %w", a.Name, err)
+			}
+			log.Record("create account", created.ID, a.Name, a.Balance, nil)
+			accountIDs[a.Name] = created.ID
+		}
+		for _, g := range data.Groups {
+			groupID := findGroup(groups, g.Name)
+			for _, c := range g.Categories {
+				if categoryIDs[g.Name+"/"+c] != "" {
+					continue
+				}
+				if groupID == "" {
+					group, err := apiClient.CreateCategoryGroup(budgetID, g.Name)
+					if err != nil {
+						log.Record("create category_group", "", g.Name, 0, err)
+						return fmt.Errorf("failed to create category group %s: %w", g.Name, err)
+					}
+					log.Record("create category_group", group.ID, g.Name, 0, nil)
+					groupID = group.ID
+				}
+				category, err := apiClient.CreateCategory(budgetID, ynab.SaveCategory{Name: c, CategoryGroupID: groupID})
+				if err != nil {
+					log.Record("create category", "", g.Name+": "+c, 0, err)
+					return fmt.Errorf("failed to create category %s: %w", c, err)
+				}
+				log.Record("create category", category.ID, g.Name+": "+c, 0, nil)
+				categoryIDs[g.Name+"/"+c] = category.ID
+			}
+		}
+
+		txns := make([]ynab.SaveTransaction, len(data.Transactions))
+		for i, t := range data.Transactions {
+			cleared := "uncleared"
+			if t.Cleared {
+				cleared = "cleared"
+			}
+			txns[i] = ynab.SaveTransaction{
+				AccountID:  accountIDs[t.Account],
+				Date:       t.Date,
+				Amount:     t.Amount,
+				PayeeName:  t.Payee,
+				CategoryID: categoryIDs[t.Group+"/"+t.Category],
+				Memo:       t.Memo,
+				Cleared:    cleared,
+				Approved:   t.Approved,
+				ImportID:   t.ImportID,
+			}
+		}
+
+		bar := progress.NewBar("creating transactions", len(txns))
+		summary.Transactions = 0
+		for start := 0; start < len(txns); start += seedBatchSize {
+			batch := txns[start:min(start+seedBatchSize, len(txns))]
+			result, err := apiClient.CreateTransactions(budgetID, batch)
+			var amount int64
+			for _, t := range batch {
+				amount += t.Amount
+			}
+			log.Record("create transactions", "", fmt.Sprintf("%d seeded transactions", len(batch)), amount, err)
+			if err != nil {
+				bar.Done()
+				return fmt.Errorf("failed to create transactions: %w", err)
+			}
+			summary.Transactions += len(result.TransactionIDs)
+			summary.Duplicates += len(result.DuplicateImportIDs)
+			bar.Add(len(batch))
+		}
+		bar.Done()
+
+		return formatter.Print(summary)
+	},
+}
+
+// findAccount returns the ID of the open account named name, if any
+func findAccount(accounts []ynab.Account, name string) string {
+	for _, a := range accounts {
+		if !a.Deleted && !a.Closed && names.Equal(a.Name, name) {
+			return a.ID
+		}
+	}
+	return ""
+}
+
+// findGroup returns the ID of the category group named name, if any
+func findGroup(groups []ynab.CategoryGroup, name string) string {
+	for _, g := range groups {
+		if !g.Deleted && names.Equal(g.Name, name) {
+			return g.ID
+		}
+	}
+	return ""
+}
+
+// findCategory returns the ID of the category named name in group, or
+// in any group when group is empty
+func findCategory(groups []ynab.CategoryGroup, group, name string) string {
+	for _, g := range groups {
+		if g.Deleted || (group != "" && !names.Equal(g.Name, group)) {
+			continue
+		}
+		for _, c := range g.Categories {
+			if !c.Deleted && names.Equal(c.Name, name) {
+				return c.ID
+			}
+		}
+	}
+	return ""
+}
+
+func init() {
+	rootCmd.AddCommand(devtoolsCmd)
+	devtoolsCmd.AddCommand(devtoolsSeedCmd)
+
+	devtoolsSeedCmd.Flags().IntVar(&seedTransactions, "transactions", 500, "Number of transactions to create")
+	devtoolsSeedCmd.Flags().IntVar(&seedMonths, "months", 6, "Number of months, up to the current one, to spread them over")
+	devtoolsSeedCmd.Flags().Int64Var(&seedValue, "seed", 0, "Random seed, to create the same data again (default: random)")
+	devtoolsSeedCmd.Flags().BoolVar(&seedDryRun, "dry-run", false, "Only show what would be created")
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/langtind/ynabctl/internal/apispec"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var verifySchemaSpec string
+
+var devtoolsVerifySchemaCmd = &cobra.Command{
+	Use:   "verify-schema",
+	Short: "Compare the client with YNAB's published OpenAPI spec",
+	Long: `Download YNAB's OpenAPI specification and compare it with the endpoints
+and record fields the built-in client knows about, so the hand-written
+client does not silently drift from the API.
+
+The report lists endpoints in the spec the client does not implement,
+endpoints the client calls that the spec lacks, and per record schema
+the fields the client does not decode and the fields it decodes that
+the spec no longer has. Path parameters match whatever they are named.
+
+--spec reads another URL or a local file, YAML or JSON. No token is
+needed. The exit code is 2 when the client differs from the spec, so the
+command can run in CI.`,
+	Example: `  ynabctl devtools verify-schema -f table
+  ynabctl devtools verify-schema --spec open_api_spec.yaml | jq '.schemas'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := readSpec(verifySchemaSpec)
+		if err != nil {
+			return err
+		}
+		spec, err := apispec.Parse(data)
+		if err != nil {
+			return err
+		}
+
+		report := apispec.Compare(spec, ynab.Endpoints(), ynab.SchemaFields())
+		formatter := output.New(getOutputFormat())
+		if err := formatter.Print(report); err != nil {
+			return err
+		}
+		if report.Drifted() {
+			return &exitError{code: 2, err: fmt.Errorf("the client differs from the OpenAPI spec")}
+		}
+		infof("the client matches the OpenAPI spec\n")
+		return nil
+	},
+}
+
+// readSpec reads an OpenAPI spec from a URL or a file
+func readSpec(location string) ([]byte, error) {
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		return os.ReadFile(location)
+	}
+	hc, err := ynab.NewHTTPClient(caBundlePath())
+	if err != nil {
+		return nil, err
+	}
+	spinner := progress.Start("downloading OpenAPI spec")
+	defer spinner.Stop()
+	resp, err := hc.Get(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", location, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", location, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func init() {
+	devtoolsCmd.AddCommand(devtoolsVerifySchemaCmd)
+
+	devtoolsVerifySchemaCmd.Flags().StringVar(&verifySchemaSpec, "spec", ynab.SpecURL, "URL or file of the OpenAPI spec")
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var doctorSince string
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check budget data for problems",
+	Long: `Check the data of a budget for problems the YNAB web app does not
+show. To check the ynabctl setup itself, use 'ynabctl config doctor'.`,
+}
+
+var doctorReferencesCmd = &cobra.Command{
+	Use:   "references",
+	Short: "Find references to deleted accounts, categories and payees",
+	Long: `List transactions that point at a deleted (or unknown) category, payee
+or account, transfers whose other account was deleted, and transfer
+payees left behind by deleted accounts, each with a suggested fix.
+
+Parts of a split are checked too. Nothing is changed; the fixes are
+commands to run, or steps to take in YNAB where the API cannot do it.
+Use --since to check only recent transactions.`,
+	Example: `  ynabctl doctor references -f table
+  ynabctl doctor references --since 2025-01-01`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+
+		spinner := progress.Start("fetching transactions, accounts, categories and payees")
+		transactions, err := apiClient.GetTransactions(budgetID, &ynab.TransactionFilter{SinceDate: doctorSince})
+		if err != nil {
+			spinner.Stop()
+			return fmt.Errorf("failed to get transactions: %w", err)
+		}
+		accounts, err := apiClient.GetAccounts(budgetID)
+		if err != nil {
+			spinner.Stop()
+			return fmt.Errorf("failed to get accounts: %w", err)
+		}
+		groups, err := apiClient.GetCategories(budgetID)
+		if err != nil {
+			spinner.Stop()
+			return fmt.Errorf("failed to get categories: %w", err)
+		}
+		payees, err := apiClient.GetPayees(budgetID)
+		spinner.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to get payees: %w", err)
+		}
+
+		problems := report.References(transactions, accounts, groups, payees)
+		if len(problems) == 0 {
+			infof("No broken references found.\n")
+		}
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(problems)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.AddCommand(doctorReferencesCmd)
+
+	doctorReferencesCmd.Flags().StringVar(&doctorSince, "since", "", "Only check transactions on or after this date (YYYY-MM-DD)")
+}
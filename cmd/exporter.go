@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/metrics"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exporterListen  string
+	exporterRefresh refreshFlags
+)
+
+var exporterCmd = &cobra.Command{
+	Use:   "exporter",
+	Short: "Serve budget balances as Prometheus metrics",
+	Long: `Keep running and serve the budget's figures in the Prometheus text
+format on http://<--listen>/metrics, so they can be graphed and alerted
+on. Amounts are in the budget's currency:
+
+  ynab_account_balance, ynab_account_cleared_balance   open accounts
+  ynab_to_be_budgeted, ynab_age_of_money_days           current month
+  ynab_category_budgeted, ynab_category_activity,
+  ynab_category_balance                                 visible categories
+
+Scrapes are answered from the last refresh and never call the API. The
+figures are refreshed every --interval after a change and twice as
+rarely after each refresh that changed nothing, up to --max-interval
+(1h, or --interval if longer), and less often when the API rate limit
+runs low. The exporter stops on Ctrl-C or SIGTERM.`,
+	Example: `  ynabctl exporter
+  ynabctl exporter --listen :9101 --interval 10m`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		refresher, err := exporterRefresh.refresher()
+		if err != nil {
+			return err
+		}
+		budget := budgetID
+		if budgets, err := apiClient.GetBudgets(); err == nil {
+			for _, b := range budgets {
+				if b.ID == budgetID {
+					budget = b.Name
+				}
+			}
+		}
+
+		var mu sync.Mutex
+		var current []byte
+		render := func() (bool, error) {
+			accounts, err := apiClient.Fresh().GetAccounts(budgetID)
+			if err != nil {
+				return false, fmt.Errorf("failed to get accounts: %w", err)
+			}
+			month, err := apiClient.Fresh().GetMonth(budgetID, "current")
+			if err != nil {
+				return false, fmt.Errorf("failed to get the current month: %w", err)
+			}
+			var buf bytes.Buffer
+			if err := metrics.Write(&buf, budget, accounts, month); err != nil {
+				return false, err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			changed := !bytes.Equal(buf.Bytes(), current)
+			current = buf.Bytes()
+			return changed, nil
+		}
+		if _, err := render(); err != nil {
+			return err
+		}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			body := current
+			mu.Unlock()
+			w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+			w.Write(body)
+		})
+		ln, err := net.Listen("tcp", exporterListen)
+		if err != nil {
+			return fmt.Errorf("failed to listen for scrapes: %w", err)
+		}
+		srv := &http.Server{Handler: mux}
+		go srv.Serve(ln)
+		defer srv.Close()
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		infof("serving metrics on http://%s/metrics, refreshed every %s to %s; press Ctrl-C to stop\n", ln.Addr(), refresher.Min, refresher.Max)
+		return refresher.Run(ctx, render)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exporterCmd)
+	exporterCmd.Flags().StringVar(&exporterListen, "listen", "127.0.0.1:9101", "Address to serve /metrics on")
+	exporterRefresh.add(exporterCmd, 5*time.Minute, time.Hour, "How often to refresh the figures")
+}
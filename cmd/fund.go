@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/langtind/ynabctl/internal/names"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/period"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fundAmount   float64
+	fundMonth    string
+	fundPriority []string
+	fundDryRun   bool
+)
+
+var fundCmd = &cobra.Command{
+	Use:   "fund --amount <amount>",
+	Short: "Assign money to categories in priority order",
+	Long: `Spread an amount, e.g. a paycheck that just came in, over categories
+in priority order. Each category gets what it still needs this month
+(what its goal is underfunded by, or for a category without a goal what
+it is overspent by) until the amount runs out. The result shows what
+got funded and what is still short.
+
+The order is set with "ynabctl config set-funding-priority" or given
+with --priority. The changes are shown and must be confirmed unless
+--yes is given; --dry-run only shows them. Assigning more than To Be
+Budgeted holds is refused unless --allow-negative-tbb is given.`,
+	Example: `  ynabctl fund --amount 2000 --dry-run -f table
+  ynabctl fund --amount 2000 --priority Rent,Groceries,@car`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		if fundAmount <= 0 {
+			return fmt.Errorf("--amount must be positive")
+		}
+		month, err := parseMonthArg(fundMonth)
+		if err != nil {
+			return err
+		}
+		priority := fundPriority
+		if len(priority) == 0 && cfg != nil {
+			priority = cfg.FundingPriority
+		}
+		if len(priority) == 0 {
+			return fmt.Errorf("no funding priority; set one with 'ynabctl config set-funding-priority' or pass --priority")
+		}
+
+		m, err := apiClient.GetMonth(budgetID, month)
+		if err != nil {
+			return fmt.Errorf("failed to get month: %w", err)
+		}
+		categories, err := fundingOrder(m.Categories, priority)
+		if err != nil {
+			return err
+		}
+
+		funding := report.FundInOrder(m.Month, categories, ynab.AmountToMilliunits(fundAmount))
+		formatter := output.New(getOutputFormat())
+		if fundDryRun || funding.Funded == 0 {
+			return formatter.Print(funding)
+		}
+		if err := guardTBB(map[string]int64{m.Month: m.ToBeBudgeted}, map[string]int64{m.Month: funding.Funded}); err != nil {
+			return err
+		}
+
+		var changes output.Changes
+		for _, it := range funding.Items {
+			if it.Funded > 0 {
+				changes.AddAmount(it.Name, it.Budgeted-it.Funded, it.Budgeted)
+			}
+		}
+		ok, err := confirmChanges(fmt.Sprintf("the budgeted amounts of %s", m.Month[:7]), changes)
+		if err != nil || !ok {
+			return err
+		}
+
+		log := output.NewChangelog(cmd.CommandPath())
+		defer finishChangelog(log)
+		for _, it := range funding.Items {
+			if it.Funded == 0 {
+				continue
+			}
+			_, err := apiClient.UpdateCategory(budgetID, it.CategoryID, m.Month, it.Budgeted)
+			log.Record("fund", it.CategoryID, it.Name, it.Funded, err)
+			if err != nil {
+				return fmt.Errorf("failed to fund %s: %w", it.Name, err)
+			}
+		}
+		return formatter.Print(funding)
+	},
+}
+
+// fundingOrder returns the categories of a month named (or identified)
+// in priority, in that order
+func fundingOrder(categories []ynab.Category, priority []string) ([]ynab.Category, error) {
+	var ordered []ynab.Category
+	seen := map[string]bool{}
+	for _, p := range priority {
+		p, err := expandAlias(p)
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, c := range categories {
+			if !c.Deleted && (c.ID == p || names.Equal(c.Name, p)) {
+				if !seen[c.ID] {
+					ordered = append(ordered, c)
+					seen[c.ID] = true
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("category %q in the funding priority not found", p)
+		}
+	}
+	return ordered, nil
+}
+
+func init() {
+	rootCmd.AddCommand(fundCmd)
+
+	fundCmd.Flags().Float64Var(&fundAmount, "amount", 0, "Amount to assign (required)")
+	fundCmd.Flags().StringVar(&fundMonth, "month", "current", "Budget month ("+period.MonthHelp+")")
+	fundCmd.Flags().StringSliceVar(&fundPriority, "priority", nil, "Categories in funding order (overrides config)")
+	fundCmd.Flags().BoolVar(&fundDryRun, "dry-run", false, "Only show how the amount would be spread")
+	addTBBGuardFlag(fundCmd)
+	_ = fundCmd.MarkFlagRequired("amount")
+}
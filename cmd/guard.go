@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	guardAccount        string
+	guardHorizon        string
+	guardMinBalance     float64
+	guardIncludeInflows bool
+)
+
+var guardCmd = &cobra.Command{
+	Use:   "guard",
+	Short: "Warn when scheduled outflows would overdraw an account",
+	Long: `Project an account's cleared balance through its scheduled transactions
+over the horizon and exit with a non-zero status if it would drop below
+--min-balance (default 0). Meant to be run from cron as an overdraft
+early warning.
+
+Only outflows are applied by default, so a shortfall is reported even if
+a scheduled paycheck would arrive in time. Use --include-inflows to also
+apply scheduled inflows.
+
+The horizon is a number of days (14d), weeks (2w) or months (1m).`,
+	Example: `  ynabctl guard --account Checking --horizon 14d
+  ynabctl guard --account Checking --horizon 1m --min-balance 500 -f table`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+
+		until, err := parseHorizon(guardHorizon, time.Now())
+		if err != nil {
+			return err
+		}
+
+		account, err := resolveAccount(budgetID, guardAccount)
+		if err != nil {
+			return err
+		}
+
+		scheduled, err := apiClient.GetScheduledTransactions(budgetID)
+		if err != nil {
+			return fmt.Errorf("failed to get scheduled transactions: %w", err)
+		}
+
+		projection := report.ProjectBalance(*account, scheduled, until,
+			ynab.AmountToMilliunits(guardMinBalance), guardIncludeInflows)
+
+		formatter := output.New(getOutputFormat())
+		if err := formatter.Print(projection); err != nil {
+			return err
+		}
+
+		if projection.Shortfall > 0 {
+			return fmt.Errorf("projected shortfall of %.2f in %s on %s (lowest balance %.2f)",
+				ynab.MilliunitsToAmount(projection.Shortfall), account.Name,
+				projection.LowestDate, ynab.MilliunitsToAmount(projection.LowestBalance))
+		}
+		return nil
+	},
+}
+
+// parseHorizon turns "14d", "2w" or "1m" into the last day covered,
+// counted from now
+func parseHorizon(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 {
+		return time.Time{}, fmt.Errorf("invalid horizon %q (want e.g. 14d, 2w, 1m)", s)
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n < 0 {
+		return time.Time{}, fmt.Errorf("invalid horizon %q (want e.g. 14d, 2w, 1m)", s)
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch s[len(s)-1] {
+	case 'd':
+		return today.AddDate(0, 0, n), nil
+	case 'w':
+		return today.AddDate(0, 0, 7*n), nil
+	case 'm':
+		return today.AddDate(0, n, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid horizon %q (want e.g. 14d, 2w, 1m)", s)
+}
+
+func init() {
+	rootCmd.AddCommand(guardCmd)
+	guardCmd.Flags().StringVar(&guardAccount, "account", "", "Account ID or name (required)")
+	guardCmd.Flags().StringVar(&guardHorizon, "horizon", "14d", "How far ahead to look (e.g. 14d, 2w, 1m)")
+	guardCmd.Flags().Float64Var(&guardMinBalance, "min-balance", 0, "Lowest acceptable balance")
+	guardCmd.Flags().BoolVar(&guardIncludeInflows, "include-inflows", false, "Also apply scheduled inflows")
+	_ = guardCmd.MarkFlagRequired("account")
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/audit"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historySince   string
+	historyUntil   string
+	historyCommand string
+	historyID      string
+	historyFailed  bool
+	historyLimit   int
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the log of commands that changed data",
+	Long: `Show the audit log of mutating commands.
+
+Every command that sends a write request to YNAB is recorded with its
+timestamp, command line, the IDs of affected records and its result.
+The log is kept in $XDG_STATE_HOME/ynabctl/audit.log
+(~/.local/state/ynabctl/audit.log by default).`,
+	Example: `  ynabctl history -f table
+  ynabctl history --since 2025-07-01 --command "transactions delete"
+  ynabctl history --id <transaction-id>
+  ynabctl history --failed --limit 10`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var filter audit.Filter
+		var err error
+		if historySince != "" {
+			if filter.Since, err = time.ParseInLocation("2006-01-02", historySince, time.Local); err != nil {
+				return fmt.Errorf("invalid --since date %q (want YYYY-MM-DD)", historySince)
+			}
+		}
+		if historyUntil != "" {
+			if filter.Until, err = time.ParseInLocation("2006-01-02", historyUntil, time.Local); err != nil {
+				return fmt.Errorf("invalid --until date %q (want YYYY-MM-DD)", historyUntil)
+			}
+			// --until is inclusive of the whole day
+			filter.Until = filter.Until.AddDate(0, 0, 1)
+		}
+		filter.Command = historyCommand
+		filter.ID = historyID
+		filter.Failed = historyFailed
+
+		entries, err := audit.Read(audit.Path())
+		if err != nil {
+			return fmt.Errorf("failed to read audit log: %w", err)
+		}
+
+		matched := []audit.Entry{}
+		for _, e := range entries {
+			if filter.Match(e) {
+				matched = append(matched, e)
+			}
+		}
+		// Keep the most recent entries when limiting
+		if historyLimit > 0 && len(matched) > historyLimit {
+			matched = matched[len(matched)-historyLimit:]
+		}
+
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(matched)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.Flags().StringVar(&historySince, "since", "", "Only show entries on or after this date (YYYY-MM-DD)")
+	historyCmd.Flags().StringVar(&historyUntil, "until", "", "Only show entries on or before this date (YYYY-MM-DD)")
+	historyCmd.Flags().StringVar(&historyCommand, "command", "", "Only show commands containing this text (e.g. \"transactions delete\")")
+	historyCmd.Flags().StringVar(&historyID, "id", "", "Only show commands that affected this record ID")
+	historyCmd.Flags().BoolVar(&historyFailed, "failed", false, "Only show commands that ended in an error")
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 0, "Show at most this many of the most recent entries")
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/pacing"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/internal/refresh"
+	"github.com/spf13/cobra"
+)
+
+// resumeFile is a checkpoint file given with --resume
+var resumeFile string
+
+// changelogFile is where --changelog writes the changes of a bulk command
+var changelogFile string
+
+// finishChangelog prints the summary of a bulk command's changes to
+// stderr and writes the --changelog file. It is deferred by bulk
+// commands so the summary also appears when they fail part way.
+func finishChangelog(log *output.Changelog) {
+	lastChangelog = log
+	if len(log.Entries) == 0 {
+		return
+	}
+	if !quiet {
+		fmt.Fprintln(os.Stderr, "Summary:")
+		log.PrintSummary(os.Stderr, output.UseColor(os.Stderr))
+	}
+	if changelogFile != "" {
+		if err := log.WriteFile(changelogFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to write changelog: %v\n", err)
+			return
+		}
+		infof("Changelog written to %s\n", changelogFile)
+	}
+}
+
+// addResumeFlag adds --resume to a command that runs a bulk job
+func addResumeFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&resumeFile, "resume", "", "Continue an interrupted run from its progress file")
+}
+
+// runJob runs fn for each key of a bulk job, paced to stay under the API
+// rate limit. Finished keys are checkpointed under the job name (or in
+// the --resume file), so running the same job again after an
+// interruption skips them. The key being processed is recorded before
+// fn runs; if the job died during it, it is retried with a warning, so
+// fn should be safe to repeat (e.g. creates carry an import_id).
+// requestsPerItem is used to estimate the completion time.
+func runJob(job string, keys []string, requestsPerItem int, fn func(key string) error) error {
+	var cp *pacing.Checkpoint
+	var err error
+	if resumeFile != "" {
+		cp, err = pacing.OpenCheckpoint(resumeFile)
+		if err == nil && cp.Job != job {
+			err = fmt.Errorf("%s is the progress of %s, not of this command", resumeFile, cp.Job)
+		}
+	} else {
+		cp, err = pacing.LoadCheckpoint(pacing.CheckpointDir(), job)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	if cp.InFlight != "" {
+		fmt.Fprintf(os.Stderr, "Warning: the previous run stopped while processing %s; it is processed again.\n", cp.InFlight)
+	}
+
+	q := &pacing.Queue{Pacer: pacer, Checkpoint: cp, RequestsPerItem: requestsPerItem}
+	pending := len(q.Pending(keys))
+	if pending < len(keys) {
+		infof("Resuming %s: %d of %d already done.\n", job, len(keys)-pending, len(keys))
+	}
+	if eta := pacer.ETA(pending * requestsPerItem); eta > 0 {
+		infof("Rate limit: %d items will take about %s (finishing around %s).\n",
+			pending, eta.Round(time.Minute), time.Now().Add(eta).Format("15:04"))
+	}
+
+	bar := progress.NewBar(job, len(keys))
+	shown := 0
+	q.OnProgress = func(done, total int, eta time.Duration) {
+		bar.Add(done - shown)
+		shown = done
+	}
+	err = q.Run(keys, fn)
+	bar.Done()
+	if err != nil {
+		return fmt.Errorf("%w (progress saved to %s; run the same command again, or with --resume %s, to continue)", err, cp.Path(), cp.Path())
+	}
+	return nil
+}
+
+// refreshFlags are the --interval and --max-interval of a long-running
+// command that polls the API
+type refreshFlags struct {
+	interval, max time.Duration
+	// defaultMax is the longest wait when --max-interval is not given,
+	// unless --interval is longer
+	defaultMax time.Duration
+}
+
+// add adds --interval, with the given default and usage, and
+// --max-interval to cmd
+func (f *refreshFlags) add(cmd *cobra.Command, interval, defaultMax time.Duration, usage string) {
+	f.defaultMax = defaultMax
+	cmd.Flags().DurationVar(&f.interval, "interval", interval, usage)
+	cmd.Flags().DurationVar(&f.max, "max-interval", 0,
+		fmt.Sprintf("Longest wait when nothing changes (default %s, or --interval if longer)", defaultMax))
+}
+
+// refresher checks the flags and returns the poller they describe
+func (f *refreshFlags) refresher() (*refresh.Refresher, error) {
+	if f.interval < time.Minute {
+		return nil, fmt.Errorf("--interval must be at least 1m")
+	}
+	longest := f.max
+	if longest == 0 {
+		longest = max(f.interval, f.defaultMax)
+	}
+	return newRefresher(f.interval, longest), nil
+}
+
+// newRefresher returns the poller of a long-running command: it checks
+// every min to max depending on how much changes, backs off when the
+// rate limit runs low, and warns about failed checks without stopping
+func newRefresher(min, max time.Duration) *refresh.Refresher {
+	r := refresh.New(min, max)
+	r.RateLimit = apiClient.RateLimit
+	r.OnError = func(err error) {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return r
+}
@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/migrate"
+	"github.com/langtind/ynabctl/internal/names"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateFrom       string
+	migrateTo         string
+	migrateCategories bool
+	migrateScheduled  bool
+	migratePayees     bool
+	migrateDryRun     bool
+	migrateMapFile    string
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate --from <budget> --to <budget>",
+	Short: "Copy categories, scheduled transactions and payees to another budget",
+	Long: `Recreate the structure of one budget in another, e.g. after a fresh
+start: the category groups and categories (--categories), the scheduled
+transactions (--scheduled) and the payees (--payees). Without any of
+these flags everything is migrated. Budgets are given by ID or name.
+
+Records are matched by name (ignoring case and emojis), so what the
+target already has is left alone and running the migration again only
+creates what is still missing. Hidden categories and YNAB's internal
+groups are skipped.
+
+Scheduled transactions need an account with the same name in the target
+budget, and start at their next date. Split scheduled transactions
+cannot be created through the API and are skipped. The API cannot
+create payees on their own either: --payees reports which ones the
+target lacks, and migrated scheduled transactions create theirs by name.
+
+The plan is shown and must be confirmed unless --yes is given;
+--dry-run only shows it. --map-file saves the mapping from source to
+target IDs as JSON.`,
+	Example: `  ynabctl migrate --from "Budget 2024" --to "Budget 2025" --dry-run -f table
+  ynabctl migrate --from <old-id> --to <new-id> --categories --scheduled
+  ynabctl migrate --from <old-id> --to <new-id> --yes --map-file ids.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !migrateCategories && !migrateScheduled && !migratePayees {
+			migrateCategories, migrateScheduled, migratePayees = true, true, true
+		}
+		from, err := resolveBudgetID(migrateFrom)
+		if err != nil {
+			return err
+		}
+		to, err := resolveBudgetID(migrateTo)
+		if err != nil {
+			return err
+		}
+		if from == to {
+			return fmt.Errorf("--from and --to are the same budget")
+		}
+
+		spinner := progress.Start("reading both budgets")
+		src, dst, err := readMigrationBudgets(from, to)
+		spinner.Stop()
+		if err != nil {
+			return err
+		}
+
+		m := migrate.NewMapping(src.accounts, dst.accounts, src.payees, dst.payees)
+		// categories are always matched, so scheduled transactions keep
+		// theirs when the target already has them
+		categorySteps := migrate.PlanCategories(src.groups, dst.groups, m)
+		var steps []migrate.Step
+		if migrateCategories {
+			steps = append(steps, categorySteps...)
+		}
+		if migrateScheduled {
+			steps = append(steps, migrate.PlanScheduled(src.scheduled, dst.scheduled, m)...)
+		}
+		if migratePayees {
+			steps = append(steps, migrate.PlanPayees(src.payees, m)...)
+		}
+
+		creates := 0
+		for _, s := range steps {
+			if s.Action == migrate.ActionCreate {
+				creates++
+			}
+		}
+		formatter := output.New(getOutputFormat())
+		if migrateDryRun || creates == 0 {
+			infof("%d records to create\n", creates)
+			return formatter.Print(steps)
+		}
+
+		ok, err := confirmOrAbort(fmt.Sprintf("Create %d records in the target budget?", creates))
+		if err != nil || !ok {
+			return err
+		}
+
+		log := output.NewChangelog(cmd.CommandPath())
+		defer finishChangelog(log)
+		scheduled := map[string]ynab.ScheduledTransaction{}
+		for _, st := range src.scheduled {
+			scheduled[st.ID] = st
+		}
+		bar := progress.NewBar("migrating", creates)
+		failed := 0
+		for i, s := range steps {
+			if s.Action != migrate.ActionCreate {
+				continue
+			}
+			if err := createMigrated(to, &steps[i], m, scheduled, log); err != nil {
+				failed++
+			}
+			bar.Add(1)
+		}
+		bar.Done()
+
+		if migrateMapFile != "" {
+			if err := writeJSONFile(migrateMapFile, m); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to write ID mapping: %v\n", err)
+			}
+		}
+		if err := formatter.Print(steps); err != nil {
+			return err
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d records could not be created", failed, creates)
+		}
+		return nil
+	},
+}
+
+// migrationBudget is what migrate reads from each budget
+type migrationBudget struct {
+	groups    []ynab.CategoryGroup
+	accounts  []ynab.Account
+	payees    []ynab.Payee
+	scheduled []ynab.ScheduledTransaction
+}
+
+// readMigrationBudgets reads the records migrate needs from both budgets
+func readMigrationBudgets(from, to string) (src, dst *migrationBudget, err error) {
+	read := func(budgetID string) (*migrationBudget, error) {
+		b := &migrationBudget{}
+		var err error
+		if b.groups, err = apiClient.GetCategories(budgetID); err != nil {
+			return nil, fmt.Errorf("failed to get categories: %w", err)
+		}
+		if b.accounts, err = apiClient.GetAccounts(budgetID); err != nil {
+			return nil, fmt.Errorf("failed to get accounts: %w", err)
+		}
+		if b.payees, err = apiClient.GetPayees(budgetID); err != nil {
+			return nil, fmt.Errorf("failed to get payees: %w", err)
+		}
+		if migrateScheduled {
+			if b.scheduled, err = apiClient.GetScheduledTransactions(budgetID); err != nil {
+				return nil, fmt.Errorf("failed to get scheduled transactions: %w", err)
+			}
+		}
+		return b, nil
+	}
+	if src, err = read(from); err != nil {
+		return nil, nil, err
+	}
+	if dst, err = read(to); err != nil {
+		return nil, nil, err
+	}
+	return src, dst, nil
+}
+
+// createMigrated creates the record of one step in the target budget,
+// recording the new ID in the step and the mapping
+func createMigrated(budgetID string, s *migrate.Step, m *migrate.Mapping, scheduled map[string]ynab.ScheduledTransaction, log *output.Changelog) error {
+	var err error
+	switch s.Kind {
+	case migrate.KindCategoryGroup:
+		var g *ynab.CategoryGroup
+		if g, err = apiClient.CreateCategoryGroup(budgetID, s.Name); err == nil {
+			s.TargetID, m.CategoryGroups[s.SourceID] = g.ID, g.ID
+		}
+
+	case migrate.KindCategory:
+		groupID, ok := m.CategoryGroups[s.Parent]
+		if !ok {
+			err = fmt.Errorf("its group was not created")
+			break
+		}
+		var c *ynab.Category
+		if c, err = apiClient.CreateCategory(budgetID, ynab.SaveCategory{Name: s.Name, CategoryGroupID: groupID}); err == nil {
+			s.TargetID, m.Categories[s.SourceID] = c.ID, c.ID
+		}
+
+	case migrate.KindScheduled:
+		st := scheduled[s.SourceID]
+		if err = ynab.ValidateSchedule(st.DateNext, st.Frequency, time.Now()); err != nil {
+			break
+		}
+		var created *ynab.ScheduledTransaction
+		if created, err = apiClient.CreateScheduledTransaction(budgetID, migrate.SaveScheduled(st, m)); err == nil {
+			s.TargetID = created.ID
+		}
+		log.Record("create "+s.Kind, s.TargetID, s.Name, st.Amount, err)
+		return err
+	}
+	log.Record("create "+s.Kind, s.TargetID, s.Name, 0, err)
+	return err
+}
+
+// resolveBudgetID turns a budget name, unique ID prefix or @alias into
+// its ID. IDs, "last-used" and "default" are returned as they are.
+func resolveBudgetID(value string) (string, error) {
+	value, err := expandAlias(value)
+	if err != nil {
+		return "", err
+	}
+	if value == "" {
+		return getBudgetID()
+	}
+	if isUUID(value) || value == "last-used" || value == "default" {
+		return value, nil
+	}
+	budgets, err := apiClient.GetBudgets()
+	if err != nil {
+		return "", fmt.Errorf("failed to get budgets: %w", err)
+	}
+	if isIDPrefix(value) {
+		var all []candidate
+		for _, b := range budgets {
+			all = append(all, candidate{ID: b.ID, Label: b.Name})
+		}
+		if id, found, err := matchPrefix("budget", value, all); found || err != nil {
+			return id, err
+		}
+	}
+	for _, b := range budgets {
+		if names.Equal(b.Name, value) {
+			return b.ID, nil
+		}
+	}
+	return "", fmt.Errorf("no budget named %q", value)
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+
+	migrateCmd.Flags().StringVar(&migrateFrom, "from", "", "Budget to copy from (ID or name; default the current budget)")
+	migrateCmd.Flags().StringVar(&migrateTo, "to", "", "Budget to copy to (ID or name, required)")
+	migrateCmd.Flags().BoolVar(&migrateCategories, "categories", false, "Copy category groups and categories")
+	migrateCmd.Flags().BoolVar(&migrateScheduled, "scheduled", false, "Copy scheduled transactions")
+	migrateCmd.Flags().BoolVar(&migratePayees, "payees", false, "Report which payees the target lacks")
+	migrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Show the plan without changing anything")
+	migrateCmd.Flags().StringVar(&migrateMapFile, "map-file", "", "Write the mapping from source to target IDs to this JSON file")
+	_ = migrateCmd.MarkFlagRequired("to")
+}
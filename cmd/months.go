@@ -2,9 +2,12 @@ package cmd
 
 import (
 	"fmt"
-	"time"
+	"os"
+	"strings"
 
+	"github.com/langtind/ynabctl/internal/clipboard"
 	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/report"
 	"github.com/spf13/cobra"
 )
 
@@ -39,23 +42,27 @@ var monthsGetCmd = &cobra.Command{
 	Short: "Get budget month details",
 	Long: `Returns details for a specific budget month.
 
-The month should be in YYYY-MM-DD format (first day of the month) or "current" for the current month.
-If no month is specified, returns the current month.`,
+The month can be YYYY-MM, "current", "last", "next", relative like -1
+or +2, or a month name like "march" or "mar 2025". If no month is
+specified, returns the current month.`,
+	Example: `  ynabctl months get
+  ynabctl months get last
+  ynabctl months get -3
+  ynabctl months get 2025-03`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		budgetID, err := getBudgetID()
 		if err != nil {
 			return err
 		}
 
-		month := "current"
+		arg := "current"
 		if len(args) > 0 {
-			month = args[0]
+			arg = args[0]
 		}
-
-		if month == "current" {
-			// Use first day of current month
-			now := time.Now()
-			month = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+		month, err := parseMonthArg(arg)
+		if err != nil {
+			return err
 		}
 
 		monthData, err := apiClient.GetMonth(budgetID, month)
@@ -68,8 +75,84 @@ If no month is specified, returns the current month.`,
 	},
 }
 
+var monthsNoteTemplate string
+
+var monthsSetNoteCmd = &cobra.Command{
+	Use:   "set-note [month] --template <file>",
+	Short: "Render a month note from a template",
+	Long: `Render a note for a month (YYYY-MM, last, -1 etc., default:
+current) from a Go text/template, filled with values computed from the
+budget, to keep a small dashboard in the month note in YNAB.
+
+The template can use:
+
+  .Month .ToBeBudgeted .Income .Funded .Activity .AgeOfMoney
+  .Goals .GoalsOnTrack .NeededTotal
+  .Underfunded   categories still short this month, most needed first,
+                 each with .Name and .Needed
+  amount         formats an amount with two decimals
+
+The YNAB API cannot edit month notes, so the note is printed rather than
+saved: paste it over the month's note in YNAB. With --copy it is also
+copied to the clipboard.`,
+	Example: `  ynabctl months set-note --template note.tmpl --copy
+  ynabctl months set-note 2026-11 --template note.tmpl
+
+  # note.tmpl
+  Ready to assign: {{amount .ToBeBudgeted}}
+  Goals on track: {{.GoalsOnTrack}}/{{.Goals}}
+  {{range .Underfunded}}- {{.Name}}: {{amount .Needed}}
+  {{end}}`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		tmpl, err := os.ReadFile(monthsNoteTemplate)
+		if err != nil {
+			return fmt.Errorf("failed to read template: %w", err)
+		}
+		arg := "current"
+		if len(args) > 0 {
+			arg = args[0]
+		}
+		month, err := parseMonthArg(arg)
+		if err != nil {
+			return err
+		}
+
+		m, err := apiClient.GetMonth(budgetID, month)
+		if err != nil {
+			return fmt.Errorf("failed to get month: %w", err)
+		}
+		note, err := report.RenderMonthNote(string(tmpl), report.NewMonthNote(m))
+		if err != nil {
+			return err
+		}
+		if note == strings.TrimSpace(m.Note) {
+			infof("The note of %s is up to date.\n", m.Month[:7])
+			return nil
+		}
+		fmt.Println(note)
+		infof("\nThe YNAB API cannot edit month notes; replace the note of %s in YNAB with the text above.\n", m.Month[:7])
+		if copyOutput {
+			if err := clipboard.Write(note); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to copy the note: %v\n", err)
+			} else {
+				infof("The note was copied to the clipboard.\n")
+			}
+		}
+		return nil
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(monthsCmd)
 	monthsCmd.AddCommand(monthsListCmd)
 	monthsCmd.AddCommand(monthsGetCmd)
+	monthsCmd.AddCommand(monthsSetNoteCmd)
+
+	monthsSetNoteCmd.Flags().StringVar(&monthsNoteTemplate, "template", "", "Go text/template file for the note (required)")
+	_ = monthsSetNoteCmd.MarkFlagRequired("template")
 }
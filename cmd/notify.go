@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/notify"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// lastChangelog is the changelog of the bulk command that ran, if any,
+// so --notify can tell how many changes it made
+var lastChangelog *output.Changelog
+
+// notifyFinished shows the --notify desktop notification with the
+// outcome of cmd, which ran for elapsed
+func notifyFinished(cmd *cobra.Command, err error, elapsed time.Duration) {
+	if !notifyFlag || cmd == nil {
+		return
+	}
+	if err := notify.Send(cmd.CommandPath(), notifyMessage(err, elapsed.Round(time.Second), lastChangelog)); err != nil {
+		if errors.Is(err, notify.ErrUnavailable) {
+			// Ring the terminal bell instead
+			fmt.Fprint(os.Stderr, "\a")
+		}
+		fmt.Fprintf(os.Stderr, "Warning: failed to show notification: %v\n", err)
+	}
+}
+
+// notifyMessage summarises the outcome of a command for a notification
+func notifyMessage(err error, elapsed time.Duration, log *output.Changelog) string {
+	msg := fmt.Sprintf("Finished in %s", elapsed)
+	if err != nil {
+		msg = fmt.Sprintf("Failed after %s: %v", elapsed, err)
+	}
+	if log != nil && len(log.Entries) > 0 {
+		done, failed := log.Counts()
+		msg += fmt.Sprintf(" (%d changed, %d failed)", done, failed)
+	}
+	return msg
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/i18n"
+)
+
+// offlineCacheAsOf is when the oldest local cache served by --offline
+// (e.g. the transaction cache) was last brought up to date
+var offlineCacheAsOf time.Time
+
+// noteOfflineAsOf records the age of a local cache served by --offline
+func noteOfflineAsOf(t time.Time) {
+	if !t.IsZero() && (offlineCacheAsOf.IsZero() || t.Before(offlineCacheAsOf)) {
+		offlineCacheAsOf = t
+	}
+}
+
+// offlineDataAsOf returns when the oldest data served by --offline was
+// fetched from the API
+func offlineDataAsOf() (time.Time, bool) {
+	asOf := offlineCacheAsOf
+	if apiClient != nil {
+		if t, ok := apiClient.DataAsOf(); ok && (asOf.IsZero() || t.Before(asOf)) {
+			asOf = t
+		}
+	}
+	return asOf, !asOf.IsZero()
+}
+
+// reportOfflineAge tells how old the data shown by --offline is. It is
+// printed even with --quiet, since stale numbers must not pass as
+// current ones.
+func reportOfflineAge() {
+	if !offlineMode {
+		return
+	}
+	asOf, ok := offlineDataAsOf()
+	if !ok {
+		return
+	}
+	age := time.Since(asOf).Round(time.Minute)
+	fmt.Fprintf(os.Stderr, i18n.T("Offline: data as of %s (%s old)")+"\n", asOf.Local().Format("2006-01-02 15:04"), age)
+}
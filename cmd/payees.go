@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/pkg/ynab"
 	"github.com/spf13/cobra"
 )
 
@@ -23,7 +24,12 @@ var payeesListCmd = &cobra.Command{
 			return err
 		}
 
-		payees, err := apiClient.GetPayees(budgetID)
+		var payees []ynab.Payee
+		if listDelta {
+			payees, err = deltaPayees(budgetID)
+		} else {
+			payees, err = apiClient.GetPayees(budgetID)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to get payees: %w", err)
 		}
@@ -34,17 +40,25 @@ var payeesListCmd = &cobra.Command{
 }
 
 var payeesGetCmd = &cobra.Command{
-	Use:   "get <payee-id>",
+	Use:   "get <payee>",
 	Short: "Get payee details",
-	Long:  `Returns details for a specific payee.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Returns details for a specific payee.
+
+The payee can be given by ID or by name; names are matched ignoring case
+and emojis. If several payees match, you are asked to choose.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		budgetID, err := getBudgetID()
 		if err != nil {
 			return err
 		}
 
-		payee, err := apiClient.GetPayee(budgetID, args[0])
+		payeeID, err := resolvePayeeID(budgetID, args[0])
+		if err != nil {
+			return err
+		}
+
+		payee, err := apiClient.GetPayee(budgetID, payeeID)
 		if err != nil {
 			return fmt.Errorf("failed to get payee: %w", err)
 		}
@@ -66,6 +80,9 @@ var payeesUpdateCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		if args[0], err = resolvePayeeID(budgetID, args[0]); err != nil {
+			return err
+		}
 
 		if payeeNewName == "" {
 			return fmt.Errorf("new name is required (--name)")
@@ -84,6 +101,7 @@ var payeesUpdateCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(payeesCmd)
 	payeesCmd.AddCommand(payeesListCmd)
+	addDeltaFlag(payeesListCmd)
 	payeesCmd.AddCommand(payeesGetCmd)
 	payeesCmd.AddCommand(payeesUpdateCmd)
 
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/langtind/ynabctl/internal/i18n"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mergeInto         string
+	mergeRenamePrefix string
+)
+
+var payeesMergeCmd = &cobra.Command{
+	Use:   "merge --into <target-payee-id> <source-payee-id>...",
+	Short: "Merge payees into one",
+	Long: `Reassign every transaction of the source payees to the target payee,
+then rename the sources with a prefix so they are easy to spot (YNAB does
+not allow deleting payees through the API).
+
+Each source is one bulk update. Requests are paced to stay under the API
+rate limit, and finished sources are checkpointed: if the merge is
+interrupted, running the same command again continues where it stopped.
+
+Transfer payees cannot be merged. Scheduled transactions that still use a
+source payee are listed so they can be updated by hand.`,
+	Example: `  ynabctl payees merge --into <target-id> <source-id> <source-id>
+  ynabctl payees merge --into <target-id> <source-id> --rename-prefix ""`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+
+		targetID, err := resolvePayeeID(budgetID, mergeInto)
+		if err != nil {
+			return err
+		}
+		target, err := apiClient.GetPayee(budgetID, targetID)
+		if err != nil {
+			return fmt.Errorf("failed to get target payee: %w", err)
+		}
+		if target.TransferAccountID != "" {
+			return fmt.Errorf("cannot merge into transfer payee %q", target.Name)
+		}
+
+		// sources are resolved up front so the job checkpoints full IDs
+		for i, id := range args {
+			if args[i], err = resolvePayeeID(budgetID, id); err != nil {
+				return err
+			}
+		}
+
+		var sources []*ynab.Payee
+		patches := map[string][]ynab.PatchTransaction{}
+		total := 0
+		bar := progress.NewBar("collecting transactions", len(args))
+		for _, id := range args {
+			if id == target.ID {
+				return fmt.Errorf("source %s is the merge target", id)
+			}
+			source, err := apiClient.GetPayee(budgetID, id)
+			if err != nil {
+				return fmt.Errorf("failed to get payee %s: %w", id, err)
+			}
+			if source.TransferAccountID != "" {
+				return fmt.Errorf("cannot merge transfer payee %q", source.Name)
+			}
+			sources = append(sources, source)
+
+			txns, err := apiClient.GetTransactionsByPayee(budgetID, id, "")
+			if err != nil {
+				return fmt.Errorf("failed to get transactions for %q: %w", source.Name, err)
+			}
+			for _, t := range txns {
+				if t.Deleted || t.PayeeID != id {
+					continue
+				}
+				patches[id] = append(patches[id], ynab.PatchTransaction{ID: t.ID, PayeeID: &target.ID})
+				total++
+			}
+			bar.Add(1)
+		}
+		bar.Done()
+
+		names := make([]string, len(sources))
+		byID := map[string]*ynab.Payee{}
+		for i, s := range sources {
+			names[i] = fmt.Sprintf("%q", s.Name)
+			byID[s.ID] = s
+		}
+		fmt.Fprintf(os.Stderr, "Merging %s into %q: %d transactions will be reassigned.\n",
+			strings.Join(names, ", "), target.Name, total)
+		ok, err := confirmOrAbort(i18n.T("Merge these payees?"))
+		if err != nil || !ok {
+			return err
+		}
+
+		// Each source is reassigned and renamed as one checkpointed step
+		reassigned := 0
+		log := output.NewChangelog(cmd.CommandPath())
+		defer finishChangelog(log)
+		err = runJob("payees-merge-"+target.ID, args, 2, func(id string) error {
+			s := byID[id]
+			if len(patches[id]) > 0 {
+				updated, _, err := apiClient.PatchTransactions(budgetID, patches[id])
+				if err != nil {
+					log.Record("reassign", s.ID, s.Name, 0, err)
+					return fmt.Errorf("failed to reassign transactions of %q: %w", s.Name, err)
+				}
+				for _, t := range updated {
+					log.Record("reassign", t.ID, s.Name+" -> "+target.Name, t.Amount, nil)
+				}
+				reassigned += len(updated)
+			}
+			if mergeRenamePrefix != "" && !strings.HasPrefix(s.Name, mergeRenamePrefix) {
+				_, err := apiClient.UpdatePayee(budgetID, s.ID, mergeRenamePrefix+s.Name)
+				log.Record("rename", s.ID, s.Name, 0, err)
+				if err != nil {
+					return fmt.Errorf("transactions reassigned, but failed to rename %q: %w", s.Name, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		warnScheduledPayees(budgetID, sources)
+
+		infof("Reassigned %d transactions to %q.\n", reassigned, target.Name)
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(target)
+	},
+}
+
+// warnScheduledPayees lists scheduled transactions that still reference
+// one of the merged payees
+func warnScheduledPayees(budgetID string, sources []*ynab.Payee) {
+	scheduled, err := apiClient.GetScheduledTransactions(budgetID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not check scheduled transactions: %v\n", err)
+		return
+	}
+	merged := map[string]bool{}
+	for _, s := range sources {
+		merged[s.ID] = true
+	}
+	for _, st := range scheduled {
+		if !st.Deleted && merged[st.PayeeID] {
+			fmt.Fprintf(os.Stderr, "warning: scheduled transaction %s (%s, %s) still uses %q\n",
+				st.ID, st.DateNext, st.Frequency, st.PayeeName)
+		}
+	}
+}
+
+func init() {
+	payeesCmd.AddCommand(payeesMergeCmd)
+
+	payeesMergeCmd.Flags().StringVar(&mergeInto, "into", "", "Target payee ID or name (required)")
+	payeesMergeCmd.Flags().StringVar(&mergeRenamePrefix, "rename-prefix", "[merged] ", "Prefix added to source payee names (empty to keep names)")
+	addResumeFlag(payeesMergeCmd)
+	_ = payeesMergeCmd.MarkFlagRequired("into")
+}
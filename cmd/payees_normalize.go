@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/names"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/payeerules"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	normalizeRules   string
+	normalizeSince   string
+	normalizeDryRun  bool
+	normalizeWatch   bool
+	normalizeRefresh refreshFlags
+)
+
+var payeesNormalizeCmd = &cobra.Command{
+	Use:   "normalize --rules <file>",
+	Short: "Rename messy imported payees by rules",
+	Long: `Give transactions clean payees by rename rules, e.g. turn "AMZN Mktp"
+and "AMZN *2K4LP0" into "Amazon". The rules file has one rule per line:
+the clean name, "=", and the raw names that should become it, separated
+by "|". A pattern between slashes is a regular expression (ignoring
+case); other patterns match the whole name (ignoring case and emojis).
+Lines starting with # are comments.
+
+  Amazon = AMZN Mktp | Amazon.com | /^AMZN\b/
+  Spotify = /^spotify/
+
+Matching transactions are moved to the payee with the clean name. When
+it does not exist yet, the raw payee is renamed to it instead. Transfers
+are never touched.
+
+Without --watch, all transactions (or those since --since) are
+normalized once and the changed ones printed. With --watch, the command
+keeps running and normalizes new and changed transactions as they
+appear, checking with a delta request; stop it with Ctrl-C. It checks
+every --interval after a change and waits twice as long after each
+check that found nothing, up to --max-interval (1h, or --interval if
+longer), and slows down further when the API rate limit runs low.`,
+	Example: `  ynabctl payees normalize --rules payee-rules.txt --dry-run -f table
+  ynabctl payees normalize --rules payee-rules.txt --since 2025-01-01
+  ynabctl payees normalize --rules payee-rules.txt --watch --interval 10m`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		refresher, err := normalizeRefresh.refresher()
+		if err != nil {
+			return err
+		}
+
+		rules, err := payeerules.Load(normalizeRules)
+		if err != nil {
+			return fmt.Errorf("failed to load payee rules: %w", err)
+		}
+		if rules.Len() == 0 {
+			return fmt.Errorf("%s has no rules", normalizeRules)
+		}
+
+		log := output.NewChangelog(cmd.CommandPath())
+		defer finishChangelog(log)
+
+		spinner := progress.Start("fetching transactions")
+		transactions, knowledge, err := apiClient.GetTransactionsSince(budgetID, 0)
+		spinner.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to get transactions: %w", err)
+		}
+		renamed, err := normalizePayees(budgetID, rules, transactions, log)
+		if err != nil {
+			return err
+		}
+		if !normalizeWatch {
+			verb := "normalized"
+			if normalizeDryRun {
+				verb = "would normalize"
+			}
+			infof("%s %d transactions\n", verb, len(renamed))
+			formatter := output.New(getOutputFormat())
+			return formatter.Print(renamed)
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+		infof("watching for new transactions every %s to %s; press Ctrl-C to stop\n", refresher.Min, refresher.Max)
+		return refresher.Run(ctx, func() (bool, error) {
+			changed, current, err := apiClient.Fresh().GetTransactionsSince(budgetID, knowledge)
+			if err != nil {
+				return false, fmt.Errorf("failed to get new transactions: %w", err)
+			}
+			knowledge = current
+			if _, err := normalizePayees(budgetID, rules, changed, log); err != nil {
+				return true, err
+			}
+			return len(changed) > 0, nil
+		})
+	},
+}
+
+// normalizePayees gives the transactions matching a rule their clean
+// payee and returns them as changed. A clean payee that does not exist
+// yet is made by renaming the first raw payee found for it.
+func normalizePayees(budgetID string, rules *payeerules.Rules, transactions []ynab.Transaction, log *output.Changelog) ([]ynab.Transaction, error) {
+	payees, err := apiClient.Fresh().GetPayees(budgetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payees: %w", err)
+	}
+	targets := map[string]string{}
+	targetID := func(name string) string {
+		if id, ok := targets[name]; ok {
+			return id
+		}
+		for _, p := range payees {
+			if !p.Deleted && p.TransferAccountID == "" && names.Equal(p.Name, name) {
+				targets[name] = p.ID
+				return p.ID
+			}
+		}
+		return ""
+	}
+
+	renamed := []ynab.Transaction{}
+	renamedPayees := map[string]bool{}
+	var patches []ynab.PatchTransaction
+	var pending []ynab.Transaction
+	for _, t := range transactions {
+		if t.Deleted || t.TransferAccountID != "" || normalizeSince != "" && t.Date < normalizeSince {
+			continue
+		}
+		target, ok := rules.Rename(t.PayeeName)
+		if !ok {
+			continue
+		}
+		infof("rename  %s  %-30s -> %s\n", t.Date, t.PayeeName, target)
+		raw := t.PayeeName
+		t.PayeeName = target
+
+		switch id := targetID(target); {
+		case normalizeDryRun || renamedPayees[t.PayeeID]:
+			renamed = append(renamed, t)
+		case id == "":
+			_, err := apiClient.UpdatePayee(budgetID, t.PayeeID, target)
+			log.Record("rename payee", t.PayeeID, raw+" -> "+target, 0, err)
+			if err != nil {
+				return renamed, fmt.Errorf("failed to rename payee %s: %w", raw, err)
+			}
+			targets[target] = t.PayeeID
+			renamedPayees[t.PayeeID] = true
+			renamed = append(renamed, t)
+		default:
+			patches = append(patches, ynab.PatchTransaction{ID: t.ID, PayeeID: &id})
+			pending = append(pending, t)
+		}
+	}
+	if len(patches) == 0 {
+		return renamed, nil
+	}
+
+	updated, _, err := apiClient.PatchTransactions(budgetID, patches)
+	for _, t := range pending {
+		log.Record("set payee", t.ID, t.Date+" "+t.PayeeName, t.Amount, err)
+	}
+	if err != nil {
+		return renamed, fmt.Errorf("failed to update transactions: %w", err)
+	}
+	return append(renamed, updated...), nil
+}
+
+func init() {
+	payeesCmd.AddCommand(payeesNormalizeCmd)
+
+	payeesNormalizeCmd.Flags().StringVar(&normalizeRules, "rules", "", "File with payee rename rules (required)")
+	payeesNormalizeCmd.Flags().StringVar(&normalizeSince, "since", "", "Only normalize transactions on or after this date (YYYY-MM-DD)")
+	payeesNormalizeCmd.Flags().BoolVar(&normalizeDryRun, "dry-run", false, "Only show what would be renamed")
+	payeesNormalizeCmd.Flags().BoolVar(&normalizeWatch, "watch", false, "Keep running and normalize new transactions as they appear")
+	normalizeRefresh.add(payeesNormalizeCmd, 5*time.Minute, time.Hour, "How often --watch checks for new transactions")
+	_ = payeesNormalizeCmd.MarkFlagRequired("rules")
+}
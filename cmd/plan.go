@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	planJobFile string
+	planJobKind string
+)
+
+// jobKind is how many API requests a kind of bulk job sends: setup
+// requests to read the budget, then perItem requests for every batch of
+// batch items
+type jobKind struct {
+	setup, batch, perItem int
+	command               string
+}
+
+var jobKinds = map[string]jobKind{
+	"import": {setup: 2, batch: 100, perItem: 1, command: "create transactions"},
+	"purge":  {setup: 1, batch: 1, perItem: 1, command: "transactions purge"},
+	"merge":  {setup: 1, batch: 1, perItem: 4, command: "payees merge"},
+	"apply":  {setup: 2, batch: 1, perItem: 1, command: "categories apply"},
+}
+
+// requests returns the number of requests a job of n items sends
+func (k jobKind) requests(n int) int {
+	return k.setup + (n+k.batch-1)/k.batch*k.perItem
+}
+
+var planCmd = &cobra.Command{
+	Use:   "plan --job <file>",
+	Short: "Estimate whether a bulk job will hit the API rate limit",
+	Long: `Estimate how many API requests a bulk job will send, compare it with
+what is left of the rate limit (200 requests per hour), and predict
+whether and when the job will be throttled, before starting it.
+
+The job file holds one item per line: a transaction per row of a CSV
+file (after its header row), or a payee or transaction ID per line of
+any other file. --kind tells what the job does with them:
+
+  import  create the transactions: 100 per request
+  purge   transactions purge: one request per transaction
+  merge   payees merge: four requests per source payee
+  apply   categories apply: one request per change (count changes, not
+          categories, when planning a plan file)
+
+Checking the rate limit itself takes one request.`,
+	Example: `  ynabctl plan --job import.csv -f table
+  ynabctl plan --job payee-ids.txt --kind merge`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kind, ok := jobKinds[planJobKind]
+		if !ok {
+			var kinds []string
+			for k := range jobKinds {
+				kinds = append(kinds, k)
+			}
+			sort.Strings(kinds)
+			return fmt.Errorf("unknown --kind %q (want one of %s)", planJobKind, strings.Join(kinds, ", "))
+		}
+		items, err := countJobItems(planJobFile)
+		if err != nil {
+			return err
+		}
+
+		// Any request reports the rate limit used so far, including by
+		// other programs sharing the token
+		if _, err := apiClient.Fresh().GetUser(); err != nil {
+			return fmt.Errorf("failed to read the rate limit: %w", err)
+		}
+
+		plan := pacer.Plan(kind.requests(items))
+		plan.Job, plan.Kind, plan.Items = planJobFile, kind.command, items
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(plan)
+	},
+}
+
+// countJobItems returns the number of items in a job file: the records
+// of a CSV file after its header, or the non-empty lines of other files
+func countJobItems(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open job file: %w", err)
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		r := csv.NewReader(f)
+		r.FieldsPerRecord = -1
+		records, err := r.ReadAll()
+		if err != nil {
+			return 0, fmt.Errorf("failed to read job file: %w", err)
+		}
+		return max(len(records)-1, 0), nil
+	}
+
+	n := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) != "" {
+			n++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read job file: %w", err)
+	}
+	return n, nil
+}
+
+func init() {
+	rootCmd.AddCommand(planCmd)
+
+	planCmd.Flags().StringVar(&planJobFile, "job", "", "File with the items of the job (required)")
+	planCmd.Flags().StringVar(&planJobKind, "kind", "import", "What the job does: import, purge, merge, or apply")
+	_ = planCmd.MarkFlagRequired("job")
+}
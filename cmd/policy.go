@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/policy"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var policyRulesFile string
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Check the budget against rules",
+	Long:  `Check the budget against rules kept in a policy file.`,
+}
+
+var policyCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Report violations of a policy file",
+	Long: `Check the budget against the rules in a YAML policy file and list
+every violation. Meant to run on a schedule (cron, CI) to keep budget
+discipline:
+
+  rules:
+    - name: categorize within a week
+      type: uncategorized_age
+      max_days: 7
+    - type: unapproved_age
+      max_days: 3
+      severity: warning
+    - type: negative_balance
+      exclude: ["Buffer"]
+    - type: credit_cards_funded
+
+Rule types:
+  uncategorized_age    no uncategorized transaction in a budget account
+                       older than max_days
+  unapproved_age       no unapproved transaction older than max_days
+  negative_balance     no overspent category this month (exclude: names)
+  credit_cards_funded  every credit card balance is covered by its payment
+                       category (exclude: account names)
+
+Rules have severity error (the default) or warning. The exit code is 0
+when no error rule is violated, 2 when one is, and 1 when the check
+itself failed.`,
+	Example: `  ynabctl policy check --rules policy.yaml -f table
+  ynabctl policy check --rules policy.yaml -q || notify-send "Budget policy violated"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		p, err := policy.Load(policyRulesFile)
+		if err != nil {
+			return err
+		}
+
+		b := policy.Budget{Today: time.Now()}
+		if p.Needs(policy.UncategorizedAge) || p.Needs(policy.CreditCardsFunded) {
+			if b.Accounts, err = apiClient.GetAccounts(budgetID); err != nil {
+				return fmt.Errorf("failed to get accounts: %w", err)
+			}
+		}
+		if p.Needs(policy.NegativeBalance) || p.Needs(policy.CreditCardsFunded) {
+			month, err := apiClient.GetMonth(budgetID, b.Today.Format("2006-01")+"-01")
+			if err != nil {
+				return fmt.Errorf("failed to get month: %w", err)
+			}
+			b.Categories = month.Categories
+		}
+		var types []string
+		if p.Needs(policy.UncategorizedAge) {
+			types = append(types, "uncategorized")
+		}
+		if p.Needs(policy.UnapprovedAge) {
+			types = append(types, "unapproved")
+		}
+		b.Transactions, err = fetchEach(types, func(t string) ([]ynab.Transaction, error) {
+			return apiClient.GetTransactions(budgetID, &ynab.TransactionFilter{Type: t})
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get transactions: %w", err)
+		}
+
+		violations := p.Check(b)
+		formatter := output.New(getOutputFormat())
+		if err := formatter.Print(violations); err != nil {
+			return err
+		}
+
+		errs := 0
+		for _, v := range violations {
+			if v.Severity == policy.SeverityError {
+				errs++
+			}
+		}
+		if errs > 0 {
+			// violations are a result, not a usage mistake
+			cmd.SilenceUsage = true
+			return &exitError{code: 2, err: fmt.Errorf("%d policy violations (%d warnings)", errs, len(violations)-errs)}
+		}
+		infof("policy ok (%d warnings)\n", len(violations))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(policyCmd)
+	policyCmd.AddCommand(policyCheckCmd)
+	policyCheckCmd.Flags().StringVar(&policyRulesFile, "rules", "", "Policy file (YAML) (required)")
+	_ = policyCheckCmd.MarkFlagRequired("rules")
+}
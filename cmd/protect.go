@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/langtind/ynabctl/internal/i18n"
+	"github.com/langtind/ynabctl/internal/output"
+)
+
+// forceProtected allows changes to protected budgets without confirmation
+var forceProtected bool
+
+var (
+	protectMu        sync.Mutex
+	confirmedBudgets = map[string]bool{}
+)
+
+// guardProtectedBudget is the client write guard. Writes to a budget in
+// the protected_budgets config need --force or the budget name typed at
+// a prompt; the confirmation holds for the rest of the command.
+// --yes does not bypass it.
+func guardProtectedBudget(budgetID string) error {
+	if budgetID == "" || forceProtected || cfg == nil || !cfg.IsProtected(budgetID) {
+		return nil
+	}
+
+	protectMu.Lock()
+	defer protectMu.Unlock()
+	if confirmedBudgets[budgetID] {
+		return nil
+	}
+
+	name := budgetID
+	if b, err := apiClient.GetBudget(budgetID); err == nil {
+		name = b.Name
+	}
+	if !output.IsTerminal(os.Stdin) {
+		return errors.New(i18n.T("budget %q is protected; pass --force to change it", name))
+	}
+
+	fmt.Fprint(os.Stderr, i18n.T("Budget %q is protected. Type its name to allow changes: ", name))
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		return errors.New(i18n.T("aborted: protected budget %q not confirmed", name))
+	}
+	if strings.TrimSpace(answer) != name {
+		return errors.New(i18n.T("aborted: %q does not match the budget name", strings.TrimSpace(answer)))
+	}
+	confirmedBudgets[budgetID] = true
+	return nil
+}
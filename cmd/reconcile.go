@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+// allowReconciled allows changes to transactions dated before their
+// account was last reconciled. It is independent of --force: neither
+// flag implies the other, and --force only covers protected budgets.
+var allowReconciled bool
+
+// addAllowReconciledFlag adds --allow-reconciled to a command changing
+// existing transactions
+func addAllowReconciledFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&allowReconciled, "allow-reconciled", false, "Allow changing a transaction dated before its account was last reconciled")
+}
+
+// guardReconciled refuses to change a transaction dated before the last
+// reconciliation of its account, which would make the reconciled
+// balance wrong, unless --allow-reconciled is given. It checks both the
+// account and date the transaction has and those it is moved to.
+func guardReconciled(budgetID string, t *ynab.Transaction, accountID, date string) error {
+	if allowReconciled {
+		return nil
+	}
+	check := func(accountID, date string) error {
+		account, err := apiClient.GetAccount(budgetID, accountID)
+		if err != nil {
+			return fmt.Errorf("failed to get account: %w", err)
+		}
+		if reconciled := report.ReconciledDate(*account); reconciled != "" && date < reconciled {
+			return fmt.Errorf("transaction %s is dated %s, before %s was reconciled on %s; pass --allow-reconciled to change it anyway",
+				t.ID, date, account.Name, reconciled)
+		}
+		return nil
+	}
+	if err := check(t.AccountID, t.Date); err != nil {
+		return err
+	}
+	if accountID != t.AccountID || date != t.Date {
+		return check(accountID, date)
+	}
+	return nil
+}
+
+// parseAge parses an age such as "30d", "2w" or a Go duration like "36h"
+func parseAge(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			days, err := strconv.Atoi(n)
+			if err != nil || days < 0 {
+				return 0, fmt.Errorf("invalid age %q (want e.g. 30d)", s)
+			}
+			return time.Duration(days) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q (want e.g. 30d)", s)
+	}
+	return d, nil
+}
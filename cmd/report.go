@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/langtind/ynabctl/internal/chart"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/period"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Summarize budget data",
+	Long: `Aggregate transactions into summaries such as spending per category, payee, account, or memo tag.
+
+Accounts and categories set with "ynabctl config set-report-exclude"
+are left out of the spending, weekly, runway, goal-schedule,
+subscriptions and consolidated reports; --include-all brings them back.`,
+}
+
+var (
+	reportPeriod   string
+	reportSpecific string
+	reportSince    string
+	reportUntil    string
+	reportGroupBy  string
+	reportChart    string
+	reportChartAs  string
+	// reportIncludeAll is set by --include-all: ignore the configured
+	// report exclusions
+	reportIncludeAll bool
+)
+
+// chartItems is the number of groups drawn in a chart; smaller groups
+// are summed into one
+const chartItems = 12
+
+var reportSpendingCmd = &cobra.Command{
+	Use:   "spending",
+	Short: "Report spending grouped by category, payee, account, or tag",
+	Long: `Sum outflows for a date range, grouped by one dimension.
+
+Transfers and inflows are excluded. Split transactions are broken into
+their parts. With --group-by tag, a transaction carrying several memo
+hashtags counts towards each of them; spending without tags is shown as
+"(untagged)".
+
+The date range is either a period (--period, optionally with --specific)
+or an explicit --since/--until pair.
+
+With --chart-file, the report is also drawn as a bar or pie chart and
+saved as PNG or SVG, chosen by the file extension. The twelve largest
+groups are drawn; the rest are summed as "Other".`,
+	Example: `  ynabctl report spending --period month
+  ynabctl report spending --period year --specific 2025 --group-by tag
+  ynabctl report spending --since 2025-07-01 --until 2025-07-31 --group-by payee -f table
+  ynabctl report spending --period month --chart-file spending.png
+  ynabctl report spending --period year --chart-file spending.svg --chart-type pie`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if reportChartAs != chart.KindBar && reportChartAs != chart.KindPie {
+			return fmt.Errorf("invalid --chart-type %q (want bar|pie)", reportChartAs)
+		}
+
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+
+		start, end, err := reportRange()
+		if err != nil {
+			return err
+		}
+
+		spinner := progress.Start("fetching transactions")
+		transactions, err := apiClient.GetTransactions(budgetID, &ynab.TransactionFilter{SinceDate: start})
+		spinner.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to get transactions: %w", err)
+		}
+
+		transactions = reportExclusions().Transactions(transactions)
+
+		spending, err := report.SpendingBy(transactions, reportGroupBy, start, end)
+		if err != nil {
+			return err
+		}
+
+		if reportChart != "" {
+			if err := chart.WriteFile(reportChart, spendingChart(spending)); err != nil {
+				return fmt.Errorf("failed to write chart: %w", err)
+			}
+			infof("Wrote chart to %s\n", reportChart)
+		}
+
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(spending)
+	},
+}
+
+// spendingChart draws the largest groups of s
+func spendingChart(s *report.Spending) *chart.Chart {
+	var items []chart.Item
+	for _, row := range s.Rows {
+		if row.Amount > 0 {
+			items = append(items, chart.Item{Label: row.Name, Value: ynab.MilliunitsToAmount(row.Amount)})
+		}
+	}
+	title := "Spending by " + s.GroupBy
+	if s.StartDate != "" || s.EndDate != "" {
+		title += fmt.Sprintf(", %s – %s", s.StartDate, s.EndDate)
+	}
+	return &chart.Chart{Title: title, Kind: reportChartAs, Items: chart.Top(items, chartItems, "Other")}
+}
+
+// reportExclusions returns the accounts and categories reports leave
+// out, none with --include-all
+func reportExclusions() report.Exclusions {
+	if reportIncludeAll || cfg == nil {
+		return report.Exclusions{}
+	}
+	return report.Exclusions{Accounts: cfg.ExcludeAccounts, Categories: cfg.ExcludeCategories}
+}
+
+// reportRange resolves the report date range from --period/--specific or
+// --since/--until.
+func reportRange() (start, end string, err error) {
+	if reportPeriod != "" {
+		if reportSince != "" || reportUntil != "" {
+			return "", "", fmt.Errorf("use either --period or --since/--until, not both")
+		}
+		p, err := period.Compute(reportPeriod, reportSpecific)
+		if err != nil {
+			return "", "", err
+		}
+		return p.StartDate, p.EndDate, nil
+	}
+	return reportSince, reportUntil, nil
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.AddCommand(reportSpendingCmd)
+
+	reportCmd.PersistentFlags().StringVar(&reportPeriod, "period", "", "Period kind: week|month|quarter|year")
+	reportCmd.PersistentFlags().StringVar(&reportSpecific, "specific", "", "Specific period (e.g. 2026-03, 2026-W15, 2026-Q1, 2026)")
+	reportCmd.PersistentFlags().StringVar(&reportSince, "since", "", "Start date (YYYY-MM-DD)")
+	reportCmd.PersistentFlags().StringVar(&reportUntil, "until", "", "End date (YYYY-MM-DD)")
+	reportCmd.PersistentFlags().BoolVar(&reportIncludeAll, "include-all", false, "Include the accounts and categories excluded from reports in config")
+
+	reportSpendingCmd.Flags().StringVar(&reportGroupBy, "group-by", report.ByCategory, "Group by: category|payee|account|tag")
+	reportSpendingCmd.Flags().StringVar(&reportChart, "chart-file", "", "Also draw the report as a chart to this .png or .svg file")
+	reportSpendingCmd.Flags().StringVar(&reportChartAs, "chart-type", chart.KindBar, "Chart type: bar|pie")
+}
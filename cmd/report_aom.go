@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/config"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	aomSnapshotDir string
+	aomNoRecord    bool
+)
+
+var reportAOMCmd = &cobra.Command{
+	Use:   "aom",
+	Short: "Show the age of money over time",
+	Long: `Show how the age of money developed. YNAB only shows the current
+value, so ynabctl combines three sources:
+
+  - the final value of every past month, from the month data
+  - values recorded by earlier runs of this command, kept in
+    ~/.local/state/ynabctl/aom/<budget>.json
+  - snapshots written by "ynabctl snapshot" in --snapshots <dir>
+
+Each run records today's value (unless --no-record), so running it from
+cron builds a daily history.`,
+	Example: `  ynabctl report aom -f table
+  ynabctl report aom --snapshots data/raw`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+
+		months, err := apiClient.GetMonths(budgetID)
+		if err != nil {
+			return fmt.Errorf("failed to get months: %w", err)
+		}
+
+		now := time.Now()
+		currentMonth := now.Format("2006-01") + "-01"
+		points := report.MonthAgePoints(months, currentMonth)
+
+		statePath := filepath.Join(config.StateDir(), "aom", budgetID+".json")
+		var recorded []report.AgePoint
+		data, err := os.ReadFile(statePath)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(data, &recorded); err != nil {
+				return fmt.Errorf("failed to parse %s: %w", statePath, err)
+			}
+		case !os.IsNotExist(err):
+			return fmt.Errorf("failed to read recorded history: %w", err)
+		}
+
+		for _, m := range months {
+			if m.Month == currentMonth && m.AgeOfMoney > 0 {
+				today := report.AgePoint{Date: now.Format("2006-01-02"), AgeOfMoney: m.AgeOfMoney, Source: report.AgeFromRecord}
+				recorded = appendAgePoint(recorded, today)
+			}
+		}
+		if !aomNoRecord {
+			if err := writeJSONFile(statePath, recorded); err != nil {
+				return fmt.Errorf("failed to record age of money: %w", err)
+			}
+		}
+		points = append(points, recorded...)
+
+		if aomSnapshotDir != "" {
+			snapshotPoints, err := snapshotAgePoints(aomSnapshotDir, budgetID)
+			if err != nil {
+				return err
+			}
+			points = append(points, snapshotPoints...)
+		}
+
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(report.TrendAgeOfMoney(points))
+	},
+}
+
+// appendAgePoint adds p, replacing an earlier point of the same date
+func appendAgePoint(points []report.AgePoint, p report.AgePoint) []report.AgePoint {
+	for i := range points {
+		if points[i].Date == p.Date {
+			points[i] = p
+			return points
+		}
+	}
+	return append(points, p)
+}
+
+// snapshotAgePoints reads the age of money at fetch time from the
+// snapshots of budgetID in dir
+func snapshotAgePoints(dir, budgetID string) ([]report.AgePoint, error) {
+	snaps, err := loadSnapshotMonths(dir, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	var points []report.AgePoint
+	for _, snap := range snaps {
+		if m := snap.current(); m != nil && m.AgeOfMoney > 0 {
+			points = append(points, report.AgePoint{
+				Date:       snap.Fetched.Format("2006-01-02"),
+				AgeOfMoney: m.AgeOfMoney,
+				Source:     report.AgeFromSnapshot,
+			})
+		}
+	}
+	return points, nil
+}
+
+// snapshotMonths is the month data of a snapshot
+type snapshotMonths struct {
+	Fetched time.Time
+	Months  []ynab.Month
+}
+
+// current returns the month the snapshot was fetched in, or nil
+func (s snapshotMonths) current() *ynab.Month {
+	month := s.Fetched.Format("2006-01") + "-01"
+	for i := range s.Months {
+		if s.Months[i].Month == month {
+			return &s.Months[i]
+		}
+	}
+	return nil
+}
+
+// loadSnapshotMonths reads the month data of the snapshots of budgetID
+// in dir, oldest first. Files that are not snapshots are skipped.
+func loadSnapshotMonths(dir, budgetID string) ([]snapshotMonths, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var snaps []snapshotMonths
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		var snap struct {
+			FetchedAt string       `json:"fetched_at"`
+			BudgetID  string       `json:"budget_id"`
+			Months    []ynab.Month `json:"months"`
+		}
+		if json.Unmarshal(data, &snap) != nil || snap.BudgetID != budgetID {
+			continue
+		}
+		fetched, err := time.Parse(time.RFC3339, snap.FetchedAt)
+		if err != nil {
+			continue
+		}
+		snaps = append(snaps, snapshotMonths{Fetched: fetched, Months: snap.Months})
+	}
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].Fetched.Before(snaps[j].Fetched) })
+	return snaps, nil
+}
+
+func init() {
+	reportCmd.AddCommand(reportAOMCmd)
+	reportAOMCmd.Flags().StringVar(&aomSnapshotDir, "snapshots", "", "Also read the age of money from snapshots in this directory")
+	reportAOMCmd.Flags().BoolVar(&aomNoRecord, "no-record", false, "Do not record today's value")
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/period"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	consolidatedBudgets  []string
+	consolidatedCurrency string
+	consolidatedRates    []string
+	consolidatedGroupBy  string
+	consolidatedDays     int
+)
+
+var reportConsolidatedCmd = &cobra.Command{
+	Use:   "consolidated --budgets <budget>,<budget>...",
+	Short: "Combine net worth, spending and bills of several budgets",
+	Long: `Merge several budgets, e.g. a personal and a shared household budget,
+into one report: net worth (open account balances), spending for the
+date range (default this month) grouped by --group-by, and scheduled
+bills due in the next --days days. Spending groups with the same name in
+several budgets are added up.
+
+Amounts are converted to --currency, which defaults to the currency of
+the first budget. Budgets in another currency need a --rate, given as
+the value of one unit of their currency: --currency NOK --rate USD=10.85.`,
+	Example: `  ynabctl report consolidated --budgets personal,shared -f table
+  ynabctl report consolidated --budgets @home,@cabin --period year --group-by payee
+  ynabctl report consolidated --budgets Norway,US --currency NOK --rate USD=10.85`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(consolidatedBudgets) < 2 {
+			return fmt.Errorf("--budgets needs at least two budgets")
+		}
+		if consolidatedDays < 0 {
+			return fmt.Errorf("--days must not be negative")
+		}
+		rates, err := report.ParseRates(consolidatedRates)
+		if err != nil {
+			return err
+		}
+		start, end, err := reportRange()
+		if err != nil {
+			return err
+		}
+		if start == "" && end == "" {
+			p, err := period.Compute("month", "")
+			if err != nil {
+				return err
+			}
+			start, end = p.StartDate, p.EndDate
+		}
+
+		budgets, err := apiClient.GetBudgets()
+		if err != nil {
+			return fmt.Errorf("failed to get budgets: %w", err)
+		}
+		exclusions := reportExclusions()
+		currency := strings.ToUpper(consolidatedCurrency)
+		var data []report.BudgetData
+		for _, ref := range consolidatedBudgets {
+			id, err := resolveBudgetID(ref)
+			if err != nil {
+				return err
+			}
+			d := report.BudgetData{ID: id, Name: ref}
+			for _, b := range budgets {
+				if b.ID == id {
+					d.Name = b.Name
+					if b.CurrencyFormat != nil {
+						d.Currency = b.CurrencyFormat.ISOCode
+					}
+				}
+			}
+			if d.Currency == "" {
+				settings, err := apiClient.GetBudgetSettings(id)
+				if err != nil {
+					return fmt.Errorf("failed to get settings of %s: %w", d.Name, err)
+				}
+				d.Currency = settings.CurrencyFormat.ISOCode
+			}
+			if currency == "" {
+				currency = d.Currency
+			}
+			switch rate, ok := rates[d.Currency]; {
+			case d.Currency == currency:
+				d.Rate = 1
+			case ok:
+				d.Rate = rate
+			default:
+				return fmt.Errorf("%s is in %s; give its value in %s with --rate %s=<rate>", d.Name, d.Currency, currency, d.Currency)
+			}
+
+			spinner := progress.Start("fetching " + d.Name)
+			d.Accounts, err = apiClient.GetAccounts(id)
+			if err != nil {
+				spinner.Stop()
+				return fmt.Errorf("failed to get accounts of %s: %w", d.Name, err)
+			}
+			d.Transactions, err = apiClient.GetTransactions(id, &ynab.TransactionFilter{SinceDate: start})
+			if err != nil {
+				spinner.Stop()
+				return fmt.Errorf("failed to get transactions of %s: %w", d.Name, err)
+			}
+			d.Scheduled, err = apiClient.GetScheduledTransactions(id)
+			spinner.Stop()
+			if err != nil {
+				return fmt.Errorf("failed to get scheduled transactions of %s: %w", d.Name, err)
+			}
+			d.Transactions = exclusions.Transactions(d.Transactions)
+			data = append(data, d)
+		}
+
+		until := time.Now().AddDate(0, 0, consolidatedDays)
+		consolidated, err := report.Consolidate(data, currency, consolidatedGroupBy, start, end, until)
+		if err != nil {
+			return err
+		}
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(consolidated)
+	},
+}
+
+func init() {
+	reportCmd.AddCommand(reportConsolidatedCmd)
+
+	reportConsolidatedCmd.Flags().StringSliceVar(&consolidatedBudgets, "budgets", nil, "Budgets to combine (IDs, names or @aliases, comma-separated)")
+	reportConsolidatedCmd.Flags().StringVar(&consolidatedCurrency, "currency", "", "Currency of the report (default: that of the first budget)")
+	reportConsolidatedCmd.Flags().StringArrayVar(&consolidatedRates, "rate", nil, "Value of one unit of a budget currency, e.g. USD=10.85 (repeatable)")
+	reportConsolidatedCmd.Flags().StringVar(&consolidatedGroupBy, "group-by", report.ByCategory, "Group spending by: category|payee|account|tag")
+	reportConsolidatedCmd.Flags().IntVar(&consolidatedDays, "days", 30, "Days of upcoming bills")
+	_ = reportConsolidatedCmd.MarkFlagRequired("budgets")
+}
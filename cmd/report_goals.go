@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	goalScheduleOut    string
+	goalScheduleMonths int
+	goalScheduleIncome float64
+)
+
+var reportGoalScheduleCmd = &cobra.Command{
+	Use:   "goal-schedule",
+	Short: "Project the monthly funding every goal needs",
+	Long: `Project the month-by-month contributions required for each goal.
+
+Goals with a target month get their remaining amount spread evenly from
+this month through the target month. Monthly funding goals need their
+target every month. Other goals are not included.
+
+Each month's total is compared with the expected monthly income, by
+default the average income of the last three complete months, and months
+that need more than that are listed as over income.
+
+With --out the full schedule (one column per month) is written as CSV;
+otherwise the monthly totals are printed.`,
+	Example: `  ynabctl report goal-schedule -f table
+  ynabctl report goal-schedule --months 24 --out schedule.csv`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		if goalScheduleMonths < 1 {
+			return fmt.Errorf("--months must be at least 1")
+		}
+
+		spinner := progress.Start("fetching categories")
+		groups, err := apiClient.GetCategories(budgetID)
+		spinner.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to get categories: %w", err)
+		}
+
+		currentMonth := time.Now().Format("2006-01") + "-01"
+		income := ynab.AmountToMilliunits(goalScheduleIncome)
+		if !cmd.Flags().Changed("income") {
+			months, err := apiClient.GetMonths(budgetID)
+			if err != nil {
+				return fmt.Errorf("failed to get months: %w", err)
+			}
+			income = averageIncome(months, currentMonth, 3)
+		}
+
+		schedule, err := report.ScheduleGoals(reportExclusions().Groups(groups), currentMonth, goalScheduleMonths, income)
+		if err != nil {
+			return err
+		}
+		for _, m := range schedule.Over {
+			fmt.Fprintf(os.Stderr, "Warning: goals need more than the expected income in %s\n", m[:7])
+		}
+
+		if goalScheduleOut != "" {
+			f, err := os.Create(goalScheduleOut)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", goalScheduleOut, err)
+			}
+			if err := schedule.WriteCSV(f); err != nil {
+				f.Close()
+				return fmt.Errorf("failed to write %s: %w", goalScheduleOut, err)
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+			infof("Wrote %d goals over %d months to %s\n", len(schedule.Rows), len(schedule.Months), goalScheduleOut)
+			return nil
+		}
+
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(schedule)
+	},
+}
+
+// averageIncome returns the average income of the last n complete months
+// before currentMonth
+func averageIncome(months []ynab.Month, currentMonth string, n int) int64 {
+	var past []ynab.Month
+	for _, m := range months {
+		if !m.Deleted && m.Month < currentMonth {
+			past = append(past, m)
+		}
+	}
+	if len(past) == 0 {
+		return 0
+	}
+	sort.Slice(past, func(i, j int) bool { return past[i].Month > past[j].Month })
+	if len(past) > n {
+		past = past[:n]
+	}
+	var total int64
+	for _, m := range past {
+		total += m.Income
+	}
+	return total / int64(len(past))
+}
+
+func init() {
+	reportCmd.AddCommand(reportGoalScheduleCmd)
+	reportGoalScheduleCmd.Flags().StringVar(&goalScheduleOut, "out", "", "Write the full schedule as CSV to this file")
+	reportGoalScheduleCmd.Flags().IntVar(&goalScheduleMonths, "months", 12, "Number of months to project")
+	reportGoalScheduleCmd.Flags().Float64Var(&goalScheduleIncome, "income", 0, "Expected monthly income (default: average of the last 3 months)")
+}
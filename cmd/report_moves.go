@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/config"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/period"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var (
+	movesMonth    string
+	movesBaseline string
+	movesNoRecord bool
+)
+
+var reportMovesCmd = &cobra.Command{
+	Use:   "moves",
+	Short: "Show budget moves since the last run",
+	Long: `Reconstruct the budget moves made in a month by comparing the budgeted
+amount of every category with the previous invocation.
+
+YNAB does not expose a history of moves, so each run records the current
+budgeted amounts in the state directory
+(~/.local/state/ynabctl/moves/<budget>/<month>.json) and the next run
+reports what changed since. The first run for a month only records the
+baseline. Use --baseline to compare with a specific recorded file and
+--no-record to keep the stored baseline unchanged.`,
+	Example: `  ynabctl report moves -f table
+  ynabctl report moves --month 2025-01
+  ynabctl report moves --baseline moves-before-payday.json --no-record`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+
+		month, err := parseMonthArg(movesMonth)
+		if err != nil {
+			return err
+		}
+
+		monthData, err := apiClient.GetMonth(budgetID, month)
+		if err != nil {
+			return fmt.Errorf("failed to get month: %w", err)
+		}
+		current := report.StateFromMonth(budgetID, monthData, time.Now().UTC().Format(time.RFC3339))
+
+		statePath := filepath.Join(config.StateDir(), "moves", budgetID, month+".json")
+		baselinePath := movesBaseline
+		if baselinePath == "" {
+			baselinePath = statePath
+		}
+
+		var previous report.BudgetedState
+		data, err := os.ReadFile(baselinePath)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(data, &previous); err != nil {
+				return fmt.Errorf("failed to parse %s: %w", baselinePath, err)
+			}
+		case os.IsNotExist(err) && movesBaseline == "":
+			// first run for this month
+		default:
+			return fmt.Errorf("failed to read baseline: %w", err)
+		}
+
+		if !movesNoRecord {
+			if err := writeJSONFile(statePath, current); err != nil {
+				return fmt.Errorf("failed to record budgeted amounts: %w", err)
+			}
+		}
+
+		if previous.RecordedAt == "" {
+			infof("Recorded the budgeted amounts for %s; run again later to see the moves made since.\n", month[:7])
+			return nil
+		}
+
+		moves := report.MovesBetween(previous, current)
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(moves)
+	},
+}
+
+// parseMonthArg accepts the month arguments of period.ParseMonth, such
+// as YYYY-MM, "current", "last" or "-1", and returns the first day of
+// the month (YYYY-MM-01) as the API expects
+func parseMonthArg(s string) (string, error) {
+	return period.ParseMonth(s, time.Now())
+}
+
+// writeJSONFile writes v as indented JSON, creating parent directories
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("mkdir %s: %w", filepath.Dir(path), err)
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func init() {
+	reportCmd.AddCommand(reportMovesCmd)
+	reportMovesCmd.Flags().StringVar(&movesMonth, "month", "", "Budget month ("+period.MonthHelp+"; default: current)")
+	reportMovesCmd.Flags().StringVar(&movesBaseline, "baseline", "", "Compare with this recorded state file instead of the last run")
+	reportMovesCmd.Flags().BoolVar(&movesNoRecord, "no-record", false, "Do not update the stored baseline")
+}
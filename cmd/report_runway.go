@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	runwayMonths            int
+	runwaySavingsCategories []string
+)
+
+var reportRunwayCmd = &cobra.Command{
+	Use:   "runway",
+	Short: "Show how many months of expenses your cash covers",
+	Long: `Compute the emergency-fund metric: how many months of average
+expenses are covered by on-budget cash, and by a set of savings
+categories.
+
+Average expenses are the category activity of the last --months complete
+months. Cash is the balance of open on-budget checking, savings, and cash
+accounts; credit cards and loans are not counted.
+
+Savings categories are taken from --savings-category or, if not given,
+from the savings_categories config setting
+(see 'ynabctl config set-savings-categories').`,
+	Example: `  ynabctl report runway -f table
+  ynabctl report runway --months 12 --savings-category "Emergency Fund"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+
+		savingsNames := runwaySavingsCategories
+		if len(savingsNames) == 0 && cfg != nil {
+			savingsNames = cfg.SavingsCategories
+		}
+
+		spinner := progress.Start("fetching months and accounts")
+		months, err := apiClient.GetMonths(budgetID)
+		if err != nil {
+			spinner.Stop()
+			return fmt.Errorf("failed to get months: %w", err)
+		}
+		accounts, err := apiClient.GetAccounts(budgetID)
+		spinner.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to get accounts: %w", err)
+		}
+
+		savings, err := savingsCategories(budgetID, savingsNames)
+		if err != nil {
+			return err
+		}
+
+		currentMonth := time.Now().Format("2006-01") + "-01"
+		runway := report.ComputeRunway(months, reportExclusions().AccountList(accounts), savings, currentMonth, runwayMonths)
+
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(runway)
+	},
+}
+
+// savingsCategories resolves category names or IDs to their current
+// categories
+func savingsCategories(budgetID string, values []string) ([]ynab.Category, error) {
+	var categories []ynab.Category
+	for _, v := range values {
+		id, err := resolveCategoryID(budgetID, v)
+		if err != nil {
+			return nil, err
+		}
+		c, err := apiClient.GetCategory(budgetID, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get category %s: %w", v, err)
+		}
+		categories = append(categories, *c)
+	}
+	return categories, nil
+}
+
+func init() {
+	reportCmd.AddCommand(reportRunwayCmd)
+	reportRunwayCmd.Flags().IntVar(&runwayMonths, "months", 6, "Number of complete months to average expenses over")
+	reportRunwayCmd.Flags().StringSliceVar(&runwaySavingsCategories, "savings-category", nil, "Category counted as savings (repeatable; overrides config)")
+}
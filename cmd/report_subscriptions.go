@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	subscriptionsMonths int
+	subscriptionsCreate bool
+)
+
+var reportSubscriptionsCmd = &cobra.Command{
+	Use:   "subscriptions",
+	Short: "Find recurring payments that are not scheduled yet",
+	Long: `Look through the last --months months of transactions for payments to
+the same payee, of about the same amount (within 10%), at a steady
+weekly, monthly or yearly rhythm: subscriptions and bills, including
+forgotten ones. Payees that already have a scheduled transaction are
+left out, and so are series that stopped more than one and a half
+periods ago.
+
+With --create, a scheduled transaction is created for each one found,
+starting at its next expected date, after confirmation (or --yes).`,
+	Example: `  ynabctl report subscriptions -f table
+  ynabctl report subscriptions --months 24 --create`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		if subscriptionsMonths < 1 {
+			return fmt.Errorf("--months must be at least 1")
+		}
+
+		now := time.Now()
+		since := now.AddDate(0, -subscriptionsMonths, 0).Format("2006-01-02")
+		spinner := progress.Start("fetching transactions")
+		transactions, err := apiClient.GetTransactions(budgetID, &ynab.TransactionFilter{SinceDate: since})
+		if err != nil {
+			spinner.Stop()
+			return fmt.Errorf("failed to get transactions: %w", err)
+		}
+		scheduled, err := apiClient.GetScheduledTransactions(budgetID)
+		spinner.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to get scheduled transactions: %w", err)
+		}
+
+		subs := report.DetectSubscriptions(reportExclusions().Transactions(transactions), scheduled, now)
+		formatter := output.New(getOutputFormat())
+		if !subscriptionsCreate || len(subs) == 0 {
+			return formatter.Print(subs)
+		}
+
+		if err := formatter.Print(subs); err != nil {
+			return err
+		}
+		ok, err := confirmOrAbort(fmt.Sprintf("Create %d scheduled transactions?", len(subs)))
+		if err != nil || !ok {
+			return err
+		}
+
+		log := output.NewChangelog(cmd.CommandPath())
+		defer finishChangelog(log)
+		for _, s := range subs {
+			created, err := apiClient.CreateScheduledTransaction(budgetID, ynab.SaveScheduledTransaction{
+				AccountID:  s.AccountID,
+				Date:       s.NextDate,
+				Frequency:  s.Frequency,
+				Amount:     s.Amount,
+				PayeeID:    s.PayeeID,
+				PayeeName:  s.PayeeName,
+				CategoryID: s.CategoryID,
+			})
+			desc := s.PayeeName + " (" + s.Frequency + ")"
+			if err != nil {
+				log.Record("create scheduled_transaction", "", desc, s.Amount, err)
+				return fmt.Errorf("failed to schedule %s: %w", s.PayeeName, err)
+			}
+			log.Record("create scheduled_transaction", created.ID, desc, s.Amount, nil)
+		}
+		return nil
+	},
+}
+
+func init() {
+	reportCmd.AddCommand(reportSubscriptionsCmd)
+	reportSubscriptionsCmd.Flags().IntVar(&subscriptionsMonths, "months", 12, "Number of months of history to look through")
+	reportSubscriptionsCmd.Flags().BoolVar(&subscriptionsCreate, "create", false, "Create a scheduled transaction for each subscription found")
+}
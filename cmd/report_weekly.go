@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/period"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	weeklyMonth    string
+	weeklyStartDay string
+	weeklyBiweekly bool
+)
+
+var reportWeeklyCmd = &cobra.Command{
+	Use:   "weekly",
+	Short: "Show spending per week against a prorated budget",
+	Long: `Slice a month's spending into weeks and compare each category's weekly
+spending with its monthly budget prorated over the days of the week.
+
+Weeks begin on --start-day (default monday). The first and last weeks are
+cut at the month boundaries and get a correspondingly smaller share of the
+budget. Use --biweekly for two-week periods. In table output, a "!" marks
+weeks where spending exceeded the prorated budget.`,
+	Example: `  ynabctl report weekly -f table
+  ynabctl report weekly --month 2025-01 --start-day friday --biweekly`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+
+		month, err := parseMonthArg(weeklyMonth)
+		if err != nil {
+			return err
+		}
+		startDay, err := report.ParseWeekday(weeklyStartDay)
+		if err != nil {
+			return err
+		}
+		length := 7
+		if weeklyBiweekly {
+			length = 14
+		}
+
+		spinner := progress.Start("fetching month and transactions")
+		monthData, err := apiClient.GetMonth(budgetID, month)
+		if err != nil {
+			spinner.Stop()
+			return fmt.Errorf("failed to get month: %w", err)
+		}
+		transactions, err := apiClient.GetTransactions(budgetID, &ynab.TransactionFilter{SinceDate: month})
+		spinner.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to get transactions: %w", err)
+		}
+
+		exclude := reportExclusions()
+		monthData.Categories = exclude.CategoryList(monthData.Categories)
+		weekly, err := report.WeeklySpending(monthData, exclude.Transactions(transactions), startDay, length)
+		if err != nil {
+			return err
+		}
+
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(weekly)
+	},
+}
+
+func init() {
+	reportCmd.AddCommand(reportWeeklyCmd)
+	reportWeeklyCmd.Flags().StringVar(&weeklyMonth, "month", "", "Budget month ("+period.MonthHelp+"; default: current)")
+	reportWeeklyCmd.Flags().StringVar(&weeklyStartDay, "start-day", "monday", "First day of the week")
+	reportWeeklyCmd.Flags().BoolVar(&weeklyBiweekly, "biweekly", false, "Use two-week periods")
+}
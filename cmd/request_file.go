@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/langtind/ynabctl/internal/reqfile"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// fromFile is the request file given with --from-file
+var fromFile string
+
+// addFromFileFlag adds --from-file to a create or update command. The
+// file's values are applied as if they were given as flags, before the
+// command runs; flags on the command line win over the file.
+func addFromFileFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&fromFile, "from-file", "", "Read flag values from a YAML request file (- for stdin); flags on the command line win")
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if fromFile == "" {
+			return nil
+		}
+		return applyRequestFile(cmd, fromFile)
+	}
+}
+
+// applyRequestFile sets the flags of cmd from a request file. Only the
+// command's own flags can be set; global flags such as --yes or --token
+// must be given on the command line.
+func applyRequestFile(cmd *cobra.Command, path string) error {
+	fields, err := reqfile.Load(path)
+	if err != nil {
+		return err
+	}
+	return setRequestFields(cmd.LocalNonPersistentFlags(), cmd.CommandPath(), path, fields)
+}
+
+// addBatchFromFileFlag is addFromFileFlag for create commands that also
+// take a list of requests. A file holding a single request sets the
+// flags as usual; a list is stored in batch for the command to create
+// one record per entry, and cannot be combined with the command's own
+// flags.
+func addBatchFromFileFlag(cmd *cobra.Command, batch *[][]reqfile.Field) {
+	cmd.Flags().StringVar(&fromFile, "from-file", "", "Read flag values from a YAML request file, or a list of them (- for stdin); flags on the command line win")
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if fromFile == "" {
+			return nil
+		}
+		requests, list, err := reqfile.LoadBatch(fromFile)
+		if err != nil {
+			return err
+		}
+		local := cmd.LocalNonPersistentFlags()
+		if !list {
+			return setRequestFields(local, cmd.CommandPath(), fromFile, requests[0])
+		}
+		var given []string
+		local.VisitAll(func(f *pflag.Flag) {
+			if f.Changed && f.Name != "from-file" {
+				given = append(given, "--"+f.Name)
+			}
+		})
+		if len(given) > 0 {
+			return fmt.Errorf("%s cannot be combined with a list of requests in %s", strings.Join(given, ", "), fromFile)
+		}
+		*batch = requests
+		return nil
+	}
+}
+
+// setRequestFields sets flags from the fields of a request file. Flags
+// already set, e.g. on the command line, are left alone.
+func setRequestFields(flags *pflag.FlagSet, command, path string, fields []reqfile.Field) error {
+	for _, field := range fields {
+		flag := flags.Lookup(field.Name)
+		if flag == nil || field.Name == "from-file" || field.Name == "help" {
+			return fmt.Errorf("%s line %d: %s is not a flag of %q (use one of: %s)",
+				path, field.Line, field.Name, command, strings.Join(requestFileFlags(flags), ", "))
+		}
+		if flag.Changed {
+			continue
+		}
+		repeatable := strings.HasSuffix(flag.Value.Type(), "Array") || strings.HasSuffix(flag.Value.Type(), "Slice")
+		if len(field.Values) > 1 && !repeatable {
+			return fmt.Errorf("%s line %d: %s takes a single value", path, field.Line, field.Name)
+		}
+		for _, v := range field.Values {
+			if err := flags.Set(field.Name, v); err != nil {
+				return fmt.Errorf("%s line %d: %w", path, field.Line, err)
+			}
+		}
+	}
+	return nil
+}
+
+// requestFileFlags lists the flag names a request file may use
+func requestFileFlags(flags *pflag.FlagSet) []string {
+	var names []string
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Name != "from-file" && f.Name != "help" {
+			names = append(names, f.Name)
+		}
+	})
+	return names
+}
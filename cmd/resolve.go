@@ -0,0 +1,454 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/langtind/ynabctl/internal/idcache"
+	"github.com/langtind/ynabctl/internal/names"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/internal/slugs"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var reUUID = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// isUUID reports whether s looks like a YNAB entity ID
+func isUUID(s string) bool {
+	return reUUID.MatchString(s)
+}
+
+// minPrefixLength is the shortest ID prefix accepted in place of a full
+// ID. Shorter hex strings are too likely to be names or amounts.
+const minPrefixLength = 6
+
+var reIDPrefix = regexp.MustCompile(`^[0-9a-fA-F][0-9a-fA-F-]*$`)
+
+// isIDPrefix reports whether s could be the start of a YNAB ID, like a
+// git short hash
+func isIDPrefix(s string) bool {
+	return len(s) >= minPrefixLength && !isUUID(s) && reIDPrefix.MatchString(s)
+}
+
+// matchPrefix returns the ID of the only candidate whose ID starts with
+// prefix, ignoring case. found is false when none does; several are an
+// error listing them.
+func matchPrefix(kind, prefix string, candidates []candidate) (id string, found bool, err error) {
+	prefix = strings.ToLower(prefix)
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(strings.ToLower(c.ID), prefix) {
+			id = c.ID
+			matches = append(matches, fmt.Sprintf("%s (%s)", c.Label, c.ID))
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", false, nil
+	case 1:
+		return id, true, nil
+	}
+	return "", false, fmt.Errorf("%s ID prefix %q is ambiguous, use more characters: %s", kind, prefix, strings.Join(matches, ", "))
+}
+
+// expandAlias returns what an "@name" alias from the config stands for,
+// or value itself when it is not an alias
+func expandAlias(value string) (string, error) {
+	if cfg == nil {
+		return value, nil
+	}
+	return cfg.ExpandAlias(value)
+}
+
+// candidate is one record matching a name
+type candidate struct {
+	ID    string
+	Label string
+	// Name is the record's own name, suggested when a name is mistyped
+	Name string
+}
+
+// chooseCandidate returns the ID of the only candidate, or asks the user
+// to pick one when several records share the name. Without a terminal
+// the ambiguity is an error listing the candidates and hint.
+func chooseCandidate(kind, value, hint string, candidates []candidate) (string, error) {
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("no %s named %q", kind, value)
+	case 1:
+		return candidates[0].ID, nil
+	}
+
+	labels := make([]string, len(candidates))
+	for i, c := range candidates {
+		labels[i] = fmt.Sprintf("%s (%s)", c.Label, c.ID)
+	}
+	if !output.IsTerminal(os.Stdin) {
+		return "", fmt.Errorf("%s name %q is ambiguous, %s: %s", kind, value, hint, strings.Join(labels, ", "))
+	}
+
+	fmt.Fprintf(os.Stderr, "Several %ss match %q:\n", kind, value)
+	for i, l := range labels {
+		fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, l)
+	}
+	fmt.Fprintf(os.Stderr, "Choose [1-%d]: ", len(candidates))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	n, err := strconv.Atoi(strings.TrimSpace(answer))
+	if err != nil || n < 1 || n > len(candidates) {
+		return "", fmt.Errorf("no %s chosen", kind)
+	}
+	return candidates[n-1].ID, nil
+}
+
+// chooseNamed is chooseCandidate for the records whose names equal value
+// exactly. Without one, nothing is guessed, as the ID may end up in a
+// change: the error suggests the names of all that contain value or are
+// close to it.
+func chooseNamed(kind, value, hint string, exact, all []candidate) (string, error) {
+	if len(exact) > 0 {
+		return chooseCandidate(kind, value, hint, exact)
+	}
+	var suggestions, allNames []string
+	seen := map[string]bool{}
+	for _, c := range all {
+		if names.Contains(c.Name, value) && !seen[c.Name] {
+			suggestions = append(suggestions, c.Name)
+			seen[c.Name] = true
+		}
+		allNames = append(allNames, c.Name)
+	}
+	for _, name := range names.Closest(value, allNames, 3) {
+		if !seen[name] {
+			suggestions = append(suggestions, name)
+			seen[name] = true
+		}
+	}
+	if len(suggestions) > 5 {
+		suggestions = suggestions[:5]
+	}
+	if len(suggestions) > 0 {
+		return "", fmt.Errorf("no %s named %q; did you mean %q?", kind, value, strings.Join(suggestions, `" or "`))
+	}
+	return chooseCandidate(kind, value, hint, nil)
+}
+
+// resolveCategoryID accepts a category ID, unique ID prefix, slug, name
+// or @alias and returns the ID. Names are matched ignoring case and emojis,
+// so "Groceries" finds "🛒 Groceries". Use "Group: Category" when a name
+// exists in more than one group.
+func resolveCategoryID(budgetID, value string) (string, error) {
+	value, err := expandAlias(value)
+	if err != nil {
+		return "", err
+	}
+	if value == "" || isUUID(value) {
+		return value, nil
+	}
+	if id, ok := slugStore.Lookup(budgetID, slugs.Category, value); ok {
+		return id, nil
+	}
+
+	groups, err := apiClient.GetCategories(budgetID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get categories: %w", err)
+	}
+
+	if isIDPrefix(value) {
+		var all []candidate
+		for _, g := range groups {
+			for _, c := range g.Categories {
+				if !g.Deleted && !c.Deleted {
+					all = append(all, candidate{ID: c.ID, Label: g.Name + ": " + c.Name})
+				}
+			}
+		}
+		if id, found, err := matchPrefix("category", value, all); found || err != nil {
+			return id, err
+		}
+	}
+
+	group, name, qualified := strings.Cut(value, ":")
+	if !qualified {
+		name = value
+	}
+
+	var candidates, inGroup []candidate
+	for _, g := range groups {
+		if g.Deleted || (qualified && !names.Equal(g.Name, group)) {
+			continue
+		}
+		for _, c := range g.Categories {
+			if c.Deleted {
+				continue
+			}
+			if !qualified && slugStore.Assign(budgetID, slugs.Category, c.ID, c.Name) == value {
+				return c.ID, nil
+			}
+			match := candidate{ID: c.ID, Label: g.Name + ": " + c.Name, Name: c.Name}
+			inGroup = append(inGroup, match)
+			if names.Equal(c.Name, name) {
+				candidates = append(candidates, match)
+			}
+		}
+	}
+	if qualified && len(candidates) == 0 {
+		// "Group: Categ" narrows the suggestions to the group
+		return chooseNamed("category", strings.TrimSpace(name), `use "Group: Category"`, nil, inGroup)
+	}
+	return chooseNamed("category", value, `use "Group: Category"`, candidates, inGroup)
+}
+
+// resolveAccountID accepts an account ID, unique ID prefix, slug, name
+// or @alias and returns the ID. Names are matched ignoring case and emojis;
+// deleted accounts are never matched.
+func resolveAccountID(budgetID, value string) (string, error) {
+	value, err := expandAlias(value)
+	if err != nil {
+		return "", err
+	}
+	if value == "" || isUUID(value) {
+		return value, nil
+	}
+
+	if id, ok := slugStore.Lookup(budgetID, slugs.Account, value); ok {
+		return id, nil
+	}
+
+	accounts, err := apiClient.GetAccounts(budgetID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get accounts: %w", err)
+	}
+	var all []candidate
+	for _, a := range accounts {
+		if a.Deleted {
+			continue
+		}
+		label := a.Name + ", " + a.Type
+		if a.Closed {
+			label += ", closed"
+		}
+		all = append(all, candidate{ID: a.ID, Label: label, Name: a.Name})
+	}
+	if isIDPrefix(value) {
+		if id, found, err := matchPrefix("account", value, all); found || err != nil {
+			return id, err
+		}
+	}
+
+	var candidates []candidate
+	for _, c := range all {
+		if slugStore.Assign(budgetID, slugs.Account, c.ID, c.Name) == value {
+			return c.ID, nil
+		}
+		if names.Equal(c.Name, value) {
+			candidates = append(candidates, c)
+		}
+	}
+	return chooseNamed("account", value, "use the ID", candidates, all)
+}
+
+// resolveAccount accepts an account ID or name and returns the account
+func resolveAccount(budgetID, value string) (*ynab.Account, error) {
+	id, err := resolveAccountID(budgetID, value)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := apiClient.GetAccounts(budgetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+	for _, a := range accounts {
+		if a.ID == id && !a.Deleted {
+			return &a, nil
+		}
+	}
+	return nil, fmt.Errorf("no account with ID %s", id)
+}
+
+// resolvePayeeID accepts a payee ID, unique ID prefix, slug, name or
+// @alias and returns the ID. Names are matched ignoring case and emojis.
+func resolvePayeeID(budgetID, value string) (string, error) {
+	value, err := expandAlias(value)
+	if err != nil {
+		return "", err
+	}
+	if value == "" || isUUID(value) {
+		return value, nil
+	}
+
+	if id, ok := slugStore.Lookup(budgetID, slugs.Payee, value); ok {
+		return id, nil
+	}
+
+	payees, err := apiClient.GetPayees(budgetID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get payees: %w", err)
+	}
+	var all []candidate
+	for _, p := range payees {
+		if !p.Deleted {
+			all = append(all, candidate{ID: p.ID, Label: p.Name, Name: p.Name})
+		}
+	}
+	if isIDPrefix(value) {
+		if id, found, err := matchPrefix("payee", value, all); found || err != nil {
+			return id, err
+		}
+	}
+
+	var candidates []candidate
+	for _, c := range all {
+		if slugStore.Assign(budgetID, slugs.Payee, c.ID, c.Name) == value {
+			return c.ID, nil
+		}
+		if names.Equal(c.Name, value) {
+			candidates = append(candidates, c)
+		}
+	}
+	return chooseNamed("payee", value, "use the ID", candidates, all)
+}
+
+// resolveTransactionID accepts a transaction ID or a unique prefix of
+// one, which is looked up in the local transaction cache
+func resolveTransactionID(budgetID, value string) (string, error) {
+	if !isIDPrefix(value) {
+		return value, nil
+	}
+	transactions, err := cachedTransactions(budgetID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get transactions: %w", err)
+	}
+	var all []candidate
+	for _, t := range transactions {
+		if !t.Deleted {
+			all = append(all, candidate{ID: t.ID, Label: t.Date + " " + t.PayeeName})
+		}
+	}
+	id, found, err := matchPrefix("transaction", value, all)
+	if err == nil && !found {
+		err = fmt.Errorf("no transaction ID starts with %q", value)
+	}
+	return id, err
+}
+
+// resolveScheduledID accepts a scheduled transaction ID or a unique
+// prefix of one
+func resolveScheduledID(budgetID, value string) (string, error) {
+	if !isIDPrefix(value) {
+		return value, nil
+	}
+	scheduled, err := apiClient.GetScheduledTransactions(budgetID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get scheduled transactions: %w", err)
+	}
+	var all []candidate
+	for _, st := range scheduled {
+		if !st.Deleted {
+			all = append(all, candidate{ID: st.ID, Label: st.DateNext + " " + st.PayeeName})
+		}
+	}
+	id, found, err := matchPrefix("scheduled transaction", value, all)
+	if err == nil && !found {
+		err = fmt.Errorf("no scheduled transaction ID starts with %q", value)
+	}
+	return id, err
+}
+
+var resolveCmd = &cobra.Command{
+	Use:   "resolve <id>",
+	Short: "Show what a YNAB ID refers to",
+	Long: `Look up the kind and name of a budget, account, category, category
+group, or payee ID.
+
+Names are remembered from API responses in a local cache. If the ID is
+not cached yet, the budget's accounts, categories, and payees are fetched
+once to refresh it. An @alias from the config is looked up as the ID it
+stands for, and a unique ID prefix of at least 6 characters as the full
+ID.`,
+	Example: `  ynabctl resolve 3fa85f64-5717-4562-b3fc-2c963f66afa6
+  ynabctl resolve 3fa85f64
+  ynabctl resolve @visa`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := expandAlias(args[0])
+		if err != nil {
+			return err
+		}
+		if !isUUID(id) && !isIDPrefix(id) {
+			return fmt.Errorf("%q is not a YNAB ID", id)
+		}
+
+		entry, ok, err := lookupCachedID(id)
+		if err == nil && !ok {
+			refreshNameCache()
+			entry, ok, err = lookupCachedID(id)
+		}
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("unknown ID %s", id)
+		}
+
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(&entry)
+	},
+}
+
+// lookupCachedID returns the name cache entry of an ID or a unique ID
+// prefix
+func lookupCachedID(id string) (idcache.Entry, bool, error) {
+	if isUUID(id) {
+		e, ok := nameCache.Lookup(id)
+		return e, ok, nil
+	}
+	var all []candidate
+	for _, e := range nameCache.Prefix(id) {
+		all = append(all, candidate{ID: e.ID, Label: e.Kind + " " + e.Name})
+	}
+	full, found, err := matchPrefix("YNAB", id, all)
+	if !found || err != nil {
+		return idcache.Entry{}, false, err
+	}
+	e, ok := nameCache.Lookup(full)
+	return e, ok, nil
+}
+
+// slugOf returns the slug of an account, category or payee ID seen in
+// an API response, giving it one if it has none yet
+func slugOf(id string) string {
+	if slug := slugStore.Slug(id); slug != "" {
+		return slug
+	}
+	e, ok := nameCache.Lookup(id)
+	if !ok {
+		return ""
+	}
+	return slugStore.Assign(e.BudgetID, e.Kind, e.ID, e.Name)
+}
+
+// refreshNameCache fetches the lists that feed the name cache. Errors are
+// ignored; whatever was fetched is still cached.
+func refreshNameCache() {
+	spinner := progress.Start("refreshing name cache")
+	defer spinner.Stop()
+
+	apiClient.GetBudgets()
+	budgetID, err := getBudgetID()
+	if err != nil {
+		return
+	}
+	apiClient.GetAccounts(budgetID)
+	apiClient.GetCategories(budgetID)
+	apiClient.GetPayees(budgetID)
+}
+
+func init() {
+	rootCmd.AddCommand(resolveCmd)
+}
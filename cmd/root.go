@@ -1,24 +1,67 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
-	"github.com/langtind/ynabctl/internal/client"
+	"github.com/langtind/ynabctl/internal/audit"
 	"github.com/langtind/ynabctl/internal/config"
+	"github.com/langtind/ynabctl/internal/i18n"
+	"github.com/langtind/ynabctl/internal/idcache"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/pacing"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/internal/respcache"
+	"github.com/langtind/ynabctl/internal/slugs"
+	"github.com/langtind/ynabctl/pkg/ynab"
 	"github.com/spf13/cobra"
 )
 
 var (
 	// Global flags
-	outputFormat string
-	budgetID     string
+	outputFormat  string
+	budgetID      string
+	copyOutput    bool
+	copyID        bool
+	idsOnly       bool
+	stripEmoji    bool
+	quiet         bool
+	noProgress    bool
+	withMeta      bool
+	tokenFlag     string
+	tokenFileFlag string
+	langFlag      string
+	offlineMode   bool
+	noDefaults    bool
+	noPager       bool
+	wideLayout    bool
+	narrowLayout  bool
+	caBundleFlag  string
+	cacheTTLFlag  time.Duration
+	noCache       bool
+	notifyFlag    bool
+
+	// tokenSource describes where the token in use came from
+	tokenSource string
 
 	// Shared client instance
-	apiClient *client.Client
+	apiClient *ynab.Client
 
 	// Config instance
 	cfg *config.Config
+
+	// nameCache maps IDs seen in API responses to names
+	nameCache *idcache.Cache
+
+	// slugStore holds the stable slugs of accounts, categories and payees
+	slugStore *slugs.Store
+
+	// pacer keeps requests under the API rate limit
+	pacer = pacing.New(pacing.DefaultLimit, pacing.DefaultWindow)
 )
 
 var rootCmd = &cobra.Command{
@@ -35,11 +78,14 @@ To get started, set your YNAB API token:
 You can obtain a token from YNAB: Account Settings > Developer Settings`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		// Skip initialization for commands that don't need it
-		if cmd.Name() == "version" || cmd.Name() == "help" || cmd.Name() == "ai" {
+		if cmd.Name() == "version" || cmd.Name() == "help" || cmd.Name() == "ai" || cmd.Name() == "commands" {
 			return nil
 		}
 		if cmd.Parent() != nil && cmd.Parent().Name() == "config" {
-			// Allow config commands to run without full initialization
+			// Allow config commands to run without full initialization;
+			// a broken config file must not stop them
+			lang, _ := i18n.Detect(langFlag, "")
+			i18n.SetLanguage(lang)
 			return nil
 		}
 
@@ -49,6 +95,16 @@ You can obtain a token from YNAB: Account Settings > Developer Settings`,
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
+		if !noDefaults {
+			if err := applyCommandDefaults(cmd); err != nil {
+				return err
+			}
+		}
+		lang, err := i18n.Detect(langFlag, cfg.Lang)
+		if err != nil {
+			return err
+		}
+		i18n.SetLanguage(lang)
 
 		// Set output format from config if not specified via flag
 		if outputFormat == "" {
@@ -58,44 +114,344 @@ You can obtain a token from YNAB: Account Settings > Developer Settings`,
 			outputFormat = "json"
 		}
 
+		if copyOutput && copyID {
+			return fmt.Errorf("use either --copy or --copy-id, not both")
+		}
+		if idsOnly && (copyOutput || copyID || withMeta) {
+			return fmt.Errorf("--ids cannot be combined with --copy, --copy-id or --with-meta")
+		}
+		layout := output.LayoutAuto
+		switch {
+		case wideLayout && narrowLayout:
+			return fmt.Errorf("use either --wide or --narrow, not both")
+		case wideLayout:
+			layout = output.LayoutWide
+		case narrowLayout:
+			layout = output.LayoutNarrow
+		}
+		width := 0
+		if output.IsTerminal(os.Stdout) {
+			width = output.TerminalWidth(os.Stdout)
+		}
+		nameCache, err = idcache.Load(idcache.Path())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring name cache: %v\n", err)
+		}
+		slugStore, err = slugs.Load(slugs.Path(config.Dir()))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring slugs: %v\n", err)
+		}
+		output.Configure(output.Options{
+			Copy:       copyOutput,
+			CopyID:     copyID,
+			IDsOnly:    idsOnly,
+			StripEmoji: stripEmoji,
+			LookupName: nameCache.Name,
+			LookupSlug: slugOf,
+			WithMeta:   withMeta,
+			Meta:       outputMeta,
+			Pager:      !noPager && output.IsTerminal(os.Stdout),
+			Layout:     layout,
+			Width:      width,
+		})
+		progress.SetEnabled(!quiet && !noProgress && output.IsTerminal(os.Stderr))
+
 		// Set budget ID from config if not specified via flag
 		if budgetID == "" {
 			budgetID = cfg.DefaultBudget
 		}
+		if budgetID, err = cfg.ExpandAlias(budgetID); err != nil {
+			return err
+		}
 
 		// Initialize API client for commands that need it
 		if requiresAuth(cmd) {
-			if cfg.Token == "" {
-				return fmt.Errorf("YNAB API token not configured. Run 'ynabctl config set-token <token>' to set it")
+			if err := applyTokenFlags(); err != nil {
+				return err
+			}
+			if cfg.Token == "" && !offlineMode {
+				return errors.New(i18n.T("YNAB API token not configured. Run 'ynabctl config set-token <token>' to set it"))
+			}
+			opts := []ynab.Option{
+				ynab.WithNameRecorder(nameCache),
+				ynab.WithPacer(pacer),
+				ynab.WithWriteGuard(guardProtectedBudget),
+				ynab.WithDecodeWarnings(warnDecode),
+				ynab.WithResponseStore(respcache.New(respcache.Dir()).ForToken(cfg.Token)),
+			}
+			if offlineMode {
+				opts = append(opts, ynab.WithOffline())
+			}
+			ttl, err := cacheTTL(cmd)
+			if err != nil {
+				return err
+			}
+			if ttl > 0 {
+				opts = append(opts, ynab.WithCacheTTL(ttl))
+			}
+			if caBundle := caBundlePath(); caBundle != "" {
+				hc, err := ynab.NewHTTPClient(caBundle)
+				if err != nil {
+					return err
+				}
+				opts = append(opts, ynab.WithHTTPClient(hc))
+			}
+			apiClient = ynab.New(cfg.Token, opts...).WithContext(cmd.Context())
+
+			// a short budget ID, like a git short hash, is expanded
+			// once against the budget list
+			if isIDPrefix(budgetID) {
+				if budgetID, err = resolveBudgetID(budgetID); err != nil {
+					return err
+				}
 			}
-			apiClient = client.New(cfg.Token)
 		}
 
 		return nil
 	},
 }
 
+// applyTokenFlags replaces the configured token with one given by
+// --token (or read from stdin with --token -) or --token-file
+func applyTokenFlags() error {
+	switch {
+	case tokenFlag != "" && tokenFileFlag != "":
+		return fmt.Errorf("use either --token or --token-file, not both")
+	case tokenFlag == "-":
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read token from stdin: %w", err)
+		}
+		cfg.Token = strings.TrimSpace(string(data))
+		if cfg.Token == "" {
+			return fmt.Errorf("no token on stdin")
+		}
+		tokenSource = "stdin (--token -)"
+	case tokenFlag != "":
+		cfg.Token = tokenFlag
+		tokenSource = "--token flag"
+	case tokenFileFlag != "":
+		token, err := config.ReadTokenFile(tokenFileFlag)
+		if err != nil {
+			return err
+		}
+		cfg.Token = token
+		tokenSource = tokenFileFlag + " (--token-file)"
+	default:
+		tokenSource = config.TokenSource()
+	}
+	return nil
+}
+
+// caBundlePath returns the CA bundle given with --ca-bundle or in the
+// config, if any
+func caBundlePath() string {
+	if caBundleFlag != "" {
+		return caBundleFlag
+	}
+	return cfg.CABundle
+}
+
+// cacheTTL returns how long saved responses are served without a
+// request: --cache-ttl, else the cache_ttl setting; 0 with --no-cache
+func cacheTTL(cmd *cobra.Command) (time.Duration, error) {
+	if noCache {
+		return 0, nil
+	}
+	if cmd.Flags().Changed("cache-ttl") {
+		return cacheTTLFlag, nil
+	}
+	if cfg.CacheTTL == "" {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(cfg.CacheTTL)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cache_ttl %q in config (want a duration such as 5m)", cfg.CacheTTL)
+	}
+	return ttl, nil
+}
+
 // requiresAuth returns true if the command needs API authentication
 func requiresAuth(cmd *cobra.Command) bool {
 	// Config commands don't need auth
 	if cmd.Name() == "show" && cmd.Parent() != nil && cmd.Parent().Name() == "config" {
 		return false
 	}
-	if cmd.Name() == "set-token" || cmd.Name() == "set-default-budget" {
+	if cmd.Name() == "set-token" || cmd.Name() == "set-default-budget" || cmd.Name() == "verify-schema" {
+		return false
+	}
+	if cmd.Name() == "clear" && cmd.Parent() != nil && cmd.Parent().Name() == "cache" {
+		return false
+	}
+	// the audit log; "categories history" and others read the API
+	if cmd.Name() == "history" && !cmd.Parent().HasParent() {
 		return false
 	}
 	return true
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	started := time.Now()
+	cmd, err := rootCmd.ExecuteC()
+	notifyFinished(cmd, err, time.Since(started))
+	recordAudit(cmd, err)
+	recordTokenUse()
+	reportOfflineAge()
+	if saveErr := nameCache.Save(); saveErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save name cache: %v\n", saveErr)
+	}
+	if saveErr := slugStore.Save(); saveErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save slugs: %v\n", saveErr)
+	}
+	if err != nil {
+		if hint := errorHint(err); hint != "" {
+			fmt.Fprintln(os.Stderr, hint)
+		}
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		os.Exit(1)
 	}
 }
 
+// warnDecode reports a response field the client could not decode
+func warnDecode(w *ynab.DecodeWarning) {
+	fmt.Fprintf(os.Stderr, "Warning: %v; it was ignored. Run 'ynabctl api capabilities' to check for schema changes.\n", w)
+}
+
+// exitError is returned by commands whose outcome is told apart by the
+// exit code, e.g. "policy check" exits 2 on violations and 1 when it
+// could not check
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// errorHint suggests what to do about common API errors
+func errorHint(err error) string {
+	switch {
+	case errors.Is(err, ynab.ErrUnauthorized):
+		return i18n.T("Hint: check your API token with 'ynabctl config show' or set a new one with 'ynabctl config set-token'.")
+	case errors.Is(err, ynab.ErrRateLimited):
+		return i18n.T("Hint: YNAB allows 200 requests per hour; wait a while and try again.")
+	case errors.Is(err, ynab.ErrNetwork):
+		return i18n.T("Hint: could not reach the YNAB API; check your network connection.")
+	case errors.Is(err, ynab.ErrPartialRecord):
+		return i18n.T("Hint: a field changed type in the API; run 'ynabctl api capabilities' and update ynabctl.")
+	case errors.Is(err, ynab.ErrOffline):
+		return i18n.T("Hint: run the command once without --offline to make its data available offline.")
+	}
+	return ""
+}
+
+// recordTokenUse notes that the token was used if the command reached
+// the API, for the staleness warning of "ynabctl user"
+func recordTokenUse() {
+	if apiClient == nil || cfg == nil {
+		return
+	}
+	if _, _, ok := apiClient.RateLimit(); !ok {
+		return
+	}
+	if err := config.RecordTokenUse(cfg.Token, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record token use: %v\n", err)
+	}
+}
+
+// recordAudit appends the command to the audit log if it sent any write
+// requests. Failing to write the log only produces a warning.
+func recordAudit(cmd *cobra.Command, runErr error) {
+	if apiClient == nil {
+		return
+	}
+	mutations := apiClient.Mutations()
+	if len(mutations) == 0 {
+		return
+	}
+
+	e := audit.Entry{
+		Time:     time.Now().UTC(),
+		Command:  cmd.CommandPath(),
+		Args:     redactToken(os.Args[1:]),
+		BudgetID: budgetID,
+		Result:   "ok",
+	}
+	for _, m := range mutations {
+		e.Requests = append(e.Requests, m.Method+" "+m.Path)
+		e.IDs = append(e.IDs, m.IDs...)
+	}
+	if runErr != nil {
+		e.Result = "error: " + runErr.Error()
+	}
+
+	if err := audit.Append(audit.Path(), e); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to write audit log: %v\n", err)
+	}
+}
+
+// redactToken hides the value of --token so it never reaches the audit
+// log
+func redactToken(args []string) []string {
+	out := make([]string, len(args))
+	copy(out, args)
+	for i, a := range out {
+		switch {
+		case a == "--token" && i+1 < len(out) && out[i+1] != "-":
+			out[i+1] = "***"
+		case strings.HasPrefix(a, "--token=") && a != "--token=-":
+			out[i] = "--token=***"
+		}
+	}
+	return out
+}
+
 func init() {
-	rootCmd.PersistentFlags().StringVarP(&outputFormat, "format", "f", "", "Output format (json, table)")
-	rootCmd.PersistentFlags().StringVarP(&budgetID, "budget", "b", "", "Budget ID to use")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "format", "f", "", "Output format (json, table, markdown)")
+	rootCmd.PersistentFlags().StringVarP(&budgetID, "budget", "b", "", "Budget ID or unique ID prefix to use")
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "Skip confirmation prompts")
+	rootCmd.PersistentFlags().BoolVar(&forceProtected, "force", false, "Allow changes to protected budgets without typing the budget name")
+	rootCmd.PersistentFlags().BoolVar(&copyOutput, "copy", false, "Also copy the command output to the clipboard")
+	rootCmd.PersistentFlags().BoolVar(&idsOnly, "ids", false, "Print only the IDs of the listed records, one per line")
+	rootCmd.PersistentFlags().BoolVar(&copyID, "copy-id", false, "Copy the ID of the returned record to the clipboard")
+	rootCmd.PersistentFlags().BoolVar(&stripEmoji, "strip-emoji", false, "Remove emojis from table output")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Suppress progress and informational messages on stderr")
+	rootCmd.PersistentFlags().BoolVar(&noProgress, "no-progress", false, "Disable spinners and progress bars")
+	rootCmd.PersistentFlags().StringVar(&tokenFlag, "token", "", "API token to use instead of the configured one (- reads it from stdin)")
+	rootCmd.PersistentFlags().StringVar(&tokenFileFlag, "token-file", "", "Read the API token from this file")
+	rootCmd.PersistentFlags().StringVar(&changelogFile, "changelog", "", "Write the changes made by bulk commands to this JSON file")
+	rootCmd.PersistentFlags().StringVar(&langFlag, "lang", "", "Language of messages and table headers (en, nb)")
+	rootCmd.PersistentFlags().StringVar(&caBundleFlag, "ca-bundle", "", "PEM file of extra CA certificates to trust, e.g. for a TLS-inspecting proxy")
+	rootCmd.PersistentFlags().BoolVar(&offlineMode, "offline", false, "Serve reads from the local cache without network access; changes are refused")
+	rootCmd.PersistentFlags().DurationVar(&cacheTTLFlag, "cache-ttl", 0, "Serve reads from the local response cache while younger than this, e.g. 5m")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Always fetch from the API, ignoring cache_ttl")
+	rootCmd.PersistentFlags().BoolVar(&notifyFlag, "notify", false, "Show a desktop notification with the outcome when the command finishes")
+	rootCmd.PersistentFlags().BoolVar(&noPager, "no-pager", false, "Do not page long table output")
+	rootCmd.PersistentFlags().BoolVar(&wideLayout, "wide", false, "Table output with more columns (IDs, flags) and nothing truncated")
+	rootCmd.PersistentFlags().BoolVar(&narrowLayout, "narrow", false, "Fit table output into 80 columns, abbreviating headers and truncating text")
+	rootCmd.PersistentFlags().BoolVar(&noDefaults, "no-defaults", false, "Ignore the per-command flag defaults from the config file")
+	rootCmd.PersistentFlags().BoolVar(&withMeta, "with-meta", false, "Wrap JSON output in {\"data\": ..., \"meta\": ...} with provenance")
+}
+
+// outputMeta returns the budget and rate limit fields for --with-meta
+func outputMeta() output.Meta {
+	m := output.Meta{BudgetID: budgetID}
+	if apiClient != nil {
+		if used, limit, ok := apiClient.RateLimit(); ok {
+			remaining := limit - used
+			m.RateLimitRemaining = &remaining
+		}
+	}
+	if offlineMode {
+		m.Offline = true
+		if asOf, ok := offlineDataAsOf(); ok {
+			m.DataAsOf = asOf.UTC().Format(time.RFC3339)
+		}
+	}
+	return m
 }
 
 // getBudgetID returns the budget ID to use, checking flag first, then config default
@@ -106,7 +462,16 @@ func getBudgetID() (string, error) {
 	if cfg != nil && cfg.DefaultBudget != "" {
 		return cfg.DefaultBudget, nil
 	}
-	return "", fmt.Errorf("no budget specified. Use --budget flag or set a default with 'ynabctl config set-default-budget <id>'")
+	return "", errors.New(i18n.T("no budget specified. Use --budget flag or set a default with 'ynabctl config set-default-budget <id>'"))
+}
+
+// infof prints an informational message to stderr unless --quiet is set.
+// Warnings and prompts are written directly and are never suppressed.
+func infof(format string, args ...interface{}) {
+	if quiet {
+		return
+	}
+	fmt.Fprintf(os.Stderr, format, args...)
 }
 
 // getOutputFormat returns the output format to use
@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/langtind/ynabctl/internal/client"
 	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/pkg/ynab"
 	"github.com/spf13/cobra"
 )
 
@@ -26,7 +26,12 @@ var scheduledListCmd = &cobra.Command{
 			return err
 		}
 
-		transactions, err := apiClient.GetScheduledTransactions(budgetID)
+		var transactions []ynab.ScheduledTransaction
+		if listDelta {
+			transactions, err = deltaScheduled(budgetID)
+		} else {
+			transactions, err = apiClient.GetScheduledTransactions(budgetID)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to get scheduled transactions: %w", err)
 		}
@@ -46,6 +51,9 @@ var scheduledGetCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		if args[0], err = resolveScheduledID(budgetID, args[0]); err != nil {
+			return err
+		}
 
 		transaction, err := apiClient.GetScheduledTransaction(budgetID, args[0])
 		if err != nil {
@@ -83,7 +91,12 @@ Required flags:
 Frequency options:
   never, daily, weekly, everyOtherWeek, twiceAMonth,
   every4Weeks, monthly, everyOtherMonth, every3Months,
-  every4Months, twiceAYear, yearly, everyOtherYear`,
+  every4Months, twiceAYear, yearly, everyOtherYear
+
+Frequencies are matched ignoring case and dashes (every-other-week).
+The date must be today or later and at most 5 years ahead; a
+twiceAMonth schedule repeats 15 days after its date, so it must start on
+day 1-15. These are checked before anything is sent to YNAB.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		budgetID, err := getBudgetID()
 		if err != nil {
@@ -96,17 +109,36 @@ Frequency options:
 		if schedFrequency == "" {
 			return fmt.Errorf("frequency is required (--frequency)")
 		}
+		frequency, err := ynab.ParseFrequency(schedFrequency)
+		if err != nil {
+			return err
+		}
 
 		date := schedDate
 		if date == "" {
 			date = time.Now().Format("2006-01-02")
 		}
+		// checked here so mistakes are not reported as a bare 400
+		if err := ynab.ValidateSchedule(date, frequency, time.Now()); err != nil {
+			return err
+		}
+
+		if schedAccountID, err = resolveAccountID(budgetID, schedAccountID); err != nil {
+			return err
+		}
+		if schedPayeeID, err = resolvePayeeID(budgetID, schedPayeeID); err != nil {
+			return err
+		}
+		schedCategoryID, err = resolveCategoryID(budgetID, schedCategoryID)
+		if err != nil {
+			return err
+		}
 
-		st := client.SaveScheduledTransaction{
+		st := ynab.SaveScheduledTransaction{
 			AccountID:  schedAccountID,
 			Date:       date,
-			Frequency:  schedFrequency,
-			Amount:     client.AmountToMilliunits(schedAmount),
+			Frequency:  frequency,
+			Amount:     ynab.AmountToMilliunits(schedAmount),
 			PayeeID:    schedPayeeID,
 			PayeeName:  schedPayeeName,
 			CategoryID: schedCategoryID,
@@ -127,13 +159,24 @@ Frequency options:
 var scheduledUpdateCmd = &cobra.Command{
 	Use:   "update <scheduled-transaction-id>",
 	Short: "Update a scheduled transaction",
-	Long:  `Update an existing scheduled transaction.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Update an existing scheduled transaction.
+
+The changed fields are shown before saving and must be confirmed
+unless --yes is given.
+
+YNAB replaces the whole scheduled transaction, so an edit made in the
+app between reading and saving would be overwritten. --verify reads it
+again just before saving and aborts, showing what changed, if any field
+differs from the first read.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		budgetID, err := getBudgetID()
 		if err != nil {
 			return err
 		}
+		if args[0], err = resolveScheduledID(budgetID, args[0]); err != nil {
+			return err
+		}
 
 		// Get existing scheduled transaction
 		existing, err := apiClient.GetScheduledTransaction(budgetID, args[0])
@@ -141,7 +184,7 @@ var scheduledUpdateCmd = &cobra.Command{
 			return fmt.Errorf("failed to get existing scheduled transaction: %w", err)
 		}
 
-		st := client.SaveScheduledTransaction{
+		st := ynab.SaveScheduledTransaction{
 			AccountID:  existing.AccountID,
 			Date:       existing.DateFirst,
 			Frequency:  existing.Frequency,
@@ -153,24 +196,46 @@ var scheduledUpdateCmd = &cobra.Command{
 		}
 
 		if cmd.Flags().Changed("account") {
-			st.AccountID = schedAccountID
+			if st.AccountID, err = resolveAccountID(budgetID, schedAccountID); err != nil {
+				return err
+			}
 		}
 		if cmd.Flags().Changed("date") {
 			st.Date = schedDate
 		}
 		if cmd.Flags().Changed("frequency") {
-			st.Frequency = schedFrequency
+			st.Frequency, err = ynab.ParseFrequency(schedFrequency)
+			if err != nil {
+				return err
+			}
+		}
+		if cmd.Flags().Changed("date") {
+			if err := ynab.ValidateSchedule(st.Date, st.Frequency, time.Now()); err != nil {
+				return err
+			}
+		} else if cmd.Flags().Changed("frequency") {
+			// the first date is kept, so check the frequency against
+			// the upcoming one
+			if err := ynab.ValidateSchedule(existing.DateNext, st.Frequency, time.Now()); err != nil {
+				return err
+			}
 		}
 		if cmd.Flags().Changed("amount") {
-			st.Amount = client.AmountToMilliunits(schedAmount)
+			st.Amount = ynab.AmountToMilliunits(schedAmount)
 		}
 		if cmd.Flags().Changed("payee-id") {
-			st.PayeeID = schedPayeeID
+			if st.PayeeID, err = resolvePayeeID(budgetID, schedPayeeID); err != nil {
+				return err
+			}
 		}
 		if cmd.Flags().Changed("payee-name") {
 			st.PayeeName = schedPayeeName
 		}
 		if cmd.Flags().Changed("category") {
+			schedCategoryID, err = resolveCategoryID(budgetID, schedCategoryID)
+			if err != nil {
+				return err
+			}
 			st.CategoryID = schedCategoryID
 		}
 		if cmd.Flags().Changed("memo") {
@@ -180,6 +245,34 @@ var scheduledUpdateCmd = &cobra.Command{
 			st.FlagColor = schedFlagColor
 		}
 
+		var changes output.Changes
+		changes.Add("account", existing.AccountID, st.AccountID)
+		changes.Add("date", existing.DateFirst, st.Date)
+		changes.Add("frequency", existing.Frequency, st.Frequency)
+		changes.AddAmount("amount", existing.Amount, st.Amount)
+		changes.Add("payee_id", existing.PayeeID, st.PayeeID)
+		if st.PayeeName != "" {
+			changes.Add("payee_name", existing.PayeeName, st.PayeeName)
+		}
+		changes.Add("category", existing.CategoryID, st.CategoryID)
+		changes.Add("memo", existing.Memo, st.Memo)
+		changes.Add("flag", existing.FlagColor, st.FlagColor)
+
+		ok, err := confirmChanges("scheduled transaction "+args[0], changes)
+		if err != nil || !ok {
+			return err
+		}
+
+		if verifyUpdate {
+			current, err := apiClient.Fresh().GetScheduledTransaction(budgetID, args[0])
+			if err != nil {
+				return fmt.Errorf("failed to re-read scheduled transaction: %w", err)
+			}
+			if err := abortIfChanged("scheduled transaction "+args[0], scheduledDrift(existing, current)); err != nil {
+				return err
+			}
+		}
+
 		transaction, err := apiClient.UpdateScheduledTransaction(budgetID, args[0], st)
 		if err != nil {
 			return fmt.Errorf("failed to update scheduled transaction: %w", err)
@@ -200,6 +293,9 @@ var scheduledDeleteCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		if args[0], err = resolveScheduledID(budgetID, args[0]); err != nil {
+			return err
+		}
 
 		transaction, err := apiClient.DeleteScheduledTransaction(budgetID, args[0])
 		if err != nil {
@@ -214,30 +310,34 @@ var scheduledDeleteCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(scheduledCmd)
 	scheduledCmd.AddCommand(scheduledListCmd)
+	addDeltaFlag(scheduledListCmd)
 	scheduledCmd.AddCommand(scheduledGetCmd)
 	scheduledCmd.AddCommand(scheduledCreateCmd)
 	scheduledCmd.AddCommand(scheduledUpdateCmd)
 	scheduledCmd.AddCommand(scheduledDeleteCmd)
 
 	// Create flags
-	scheduledCreateCmd.Flags().StringVar(&schedAccountID, "account", "", "Account ID (required)")
+	scheduledCreateCmd.Flags().StringVar(&schedAccountID, "account", "", "Account ID or name (required)")
 	scheduledCreateCmd.Flags().StringVar(&schedDate, "date", "", "First occurrence date (YYYY-MM-DD)")
 	scheduledCreateCmd.Flags().StringVar(&schedFrequency, "frequency", "", "Recurrence frequency (required)")
 	scheduledCreateCmd.Flags().Float64Var(&schedAmount, "amount", 0, "Amount")
-	scheduledCreateCmd.Flags().StringVar(&schedPayeeID, "payee-id", "", "Payee ID")
+	scheduledCreateCmd.Flags().StringVar(&schedPayeeID, "payee-id", "", "Existing payee ID or name")
 	scheduledCreateCmd.Flags().StringVar(&schedPayeeName, "payee-name", "", "Payee name")
-	scheduledCreateCmd.Flags().StringVar(&schedCategoryID, "category", "", "Category ID")
+	scheduledCreateCmd.Flags().StringVar(&schedCategoryID, "category", "", "Category ID or name")
 	scheduledCreateCmd.Flags().StringVar(&schedMemo, "memo", "", "Memo")
 	scheduledCreateCmd.Flags().StringVar(&schedFlagColor, "flag", "", "Flag color")
+	addFromFileFlag(scheduledCreateCmd)
 
 	// Update flags
-	scheduledUpdateCmd.Flags().StringVar(&schedAccountID, "account", "", "Account ID")
+	scheduledUpdateCmd.Flags().StringVar(&schedAccountID, "account", "", "Account ID or name")
 	scheduledUpdateCmd.Flags().StringVar(&schedDate, "date", "", "Date (YYYY-MM-DD)")
 	scheduledUpdateCmd.Flags().StringVar(&schedFrequency, "frequency", "", "Recurrence frequency")
 	scheduledUpdateCmd.Flags().Float64Var(&schedAmount, "amount", 0, "Amount")
-	scheduledUpdateCmd.Flags().StringVar(&schedPayeeID, "payee-id", "", "Payee ID")
+	scheduledUpdateCmd.Flags().StringVar(&schedPayeeID, "payee-id", "", "Existing payee ID or name")
 	scheduledUpdateCmd.Flags().StringVar(&schedPayeeName, "payee-name", "", "Payee name")
-	scheduledUpdateCmd.Flags().StringVar(&schedCategoryID, "category", "", "Category ID")
+	scheduledUpdateCmd.Flags().StringVar(&schedCategoryID, "category", "", "Category ID or name")
 	scheduledUpdateCmd.Flags().StringVar(&schedMemo, "memo", "", "Memo")
 	scheduledUpdateCmd.Flags().StringVar(&schedFlagColor, "flag", "", "Flag color")
+	scheduledUpdateCmd.Flags().BoolVar(&verifyUpdate, "verify", false, "Re-read the scheduled transaction before saving and abort if it changed meanwhile")
+	addFromFileFlag(scheduledUpdateCmd)
 }
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var (
+	commitmentsGroupBy          string
+	commitmentsIncludeTransfers bool
+)
+
+var scheduledCommitmentsCmd = &cobra.Command{
+	Use:   "commitments",
+	Short: "Report total fixed monthly obligations",
+	Long: `Normalize every recurring scheduled outflow to a monthly equivalent
+(weekly x 52/12, yearly / 12, and so on), group the results by category
+or account, and report the total fixed monthly obligations.
+
+One-off ("never") schedules are ignored. Scheduled inflows are reported
+separately as scheduled income. Transfers are excluded unless
+--include-transfers is given.`,
+	Example: `  ynabctl scheduled commitments -f table
+  ynabctl scheduled commitments --group-by account --include-transfers`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+
+		scheduled, err := apiClient.GetScheduledTransactions(budgetID)
+		if err != nil {
+			return fmt.Errorf("failed to get scheduled transactions: %w", err)
+		}
+
+		commitments, err := report.CommitmentsBy(scheduled, commitmentsGroupBy, commitmentsIncludeTransfers)
+		if err != nil {
+			return err
+		}
+
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(commitments)
+	},
+}
+
+func init() {
+	scheduledCmd.AddCommand(scheduledCommitmentsCmd)
+
+	scheduledCommitmentsCmd.Flags().StringVar(&commitmentsGroupBy, "group-by", report.ByCategory, "Group by: category|account")
+	scheduledCommitmentsCmd.Flags().BoolVar(&commitmentsIncludeTransfers, "include-transfers", false, "Count scheduled transfers as commitments")
+}
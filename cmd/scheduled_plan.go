@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/internal/schedplan"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	schedPlanOut    string
+	schedPlanDryRun bool
+	schedPlanPrune  bool
+)
+
+var scheduledExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export scheduled transactions as YAML",
+	Long: `Write every scheduled transaction to a YAML plan file. Edit the file
+and bring the budget in line with it using "scheduled apply".
+
+Without --out the plan is written to stdout.`,
+	Example: `  ynabctl scheduled export --out scheduled.yaml`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+
+		scheduled, err := apiClient.GetScheduledTransactions(budgetID)
+		if err != nil {
+			return fmt.Errorf("failed to get scheduled transactions: %w", err)
+		}
+
+		plan := schedplan.Export(budgetID, scheduled)
+		if schedPlanOut == "" {
+			data, err := plan.Marshal()
+			if err != nil {
+				return err
+			}
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+		if err := plan.Write(schedPlanOut); err != nil {
+			return fmt.Errorf("failed to write plan: %w", err)
+		}
+		infof("Wrote %d scheduled transactions to %s\n", len(plan.Scheduled), schedPlanOut)
+		return nil
+	},
+}
+
+var scheduledApplyCmd = &cobra.Command{
+	Use:   "apply <file>",
+	Short: "Create and update scheduled transactions from a YAML plan",
+	Long: `Bring the scheduled transactions in line with a plan file written by
+"scheduled export" (or by hand): missing ones are created and those that
+drifted from the plan are updated. With --prune, scheduled transactions
+not in the plan are deleted. The changes are shown and must be confirmed
+unless --yes is given; --dry-run only shows them.
+
+  scheduled:
+    - account: Checking
+      payee: Landlord
+      category: Rent
+      amount: -1200
+      frequency: monthly
+      date: 2025-04-01
+
+Entries are matched by id, or by account and payee when no id is given.
+Account and category are names (or IDs); a category can be written as
+"Group: Name" or as an alias. The date is the first date of a new
+scheduled transaction (default today); existing ones keep their dates.
+Split scheduled transactions cannot be applied.`,
+	Example: `  ynabctl scheduled apply scheduled.yaml --dry-run -f table
+  ynabctl scheduled apply scheduled.yaml --prune`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+
+		plan, err := schedplan.Load(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to load plan: %w", err)
+		}
+		if plan.BudgetID != "" && plan.BudgetID != budgetID {
+			return fmt.Errorf("%s is a plan for budget %s, not %s (use --budget)", args[0], plan.BudgetID, budgetID)
+		}
+		for i, e := range plan.Scheduled {
+			if plan.Scheduled[i].Category, err = expandAlias(e.Category); err != nil {
+				return err
+			}
+		}
+
+		spinner := progress.Start("fetching scheduled transactions, accounts and categories")
+		scheduled, err := apiClient.GetScheduledTransactions(budgetID)
+		if err != nil {
+			spinner.Stop()
+			return fmt.Errorf("failed to get scheduled transactions: %w", err)
+		}
+		accounts, err := apiClient.GetAccounts(budgetID)
+		if err != nil {
+			spinner.Stop()
+			return fmt.Errorf("failed to get accounts: %w", err)
+		}
+		groups, err := apiClient.GetCategories(budgetID)
+		spinner.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to get categories: %w", err)
+		}
+
+		changes, err := schedplan.Diff(plan, scheduled, accounts, groups, schedPlanPrune, time.Now())
+		if err != nil {
+			return err
+		}
+
+		formatter := output.New(getOutputFormat())
+		if schedPlanDryRun || len(changes) == 0 {
+			if len(changes) == 0 {
+				infof("The scheduled transactions already match %s.\n", args[0])
+			}
+			return formatter.Print(changes)
+		}
+
+		var diff output.Changes
+		byKey := map[string]schedplan.Change{}
+		var keys []string
+		for i, c := range changes {
+			for _, f := range c.Fields {
+				diff.Add(fmt.Sprintf("%s %s (%s) %s", c.Action, c.Payee, c.Account, f.Name), f.Before, f.After)
+			}
+			key := c.Action + "/" + c.ID
+			if c.ID == "" {
+				key += strconv.Itoa(i)
+			}
+			byKey[key] = c
+			keys = append(keys, key)
+		}
+		ok, err := confirmChanges("the scheduled transactions", diff)
+		if err != nil || !ok {
+			return err
+		}
+
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		sum := fmt.Sprintf("%x", sha1.Sum(append([]byte(budgetID+"\x00"), data...)))
+		log := output.NewChangelog(cmd.CommandPath())
+		defer finishChangelog(log)
+		err = runJob("scheduled-apply-"+sum[:12], keys, 1, func(key string) error {
+			c := byKey[key]
+			label := c.Payee + " (" + c.Account + ")"
+			var st *ynab.ScheduledTransaction
+			var err error
+			switch c.Action {
+			case schedplan.Create:
+				st, err = apiClient.CreateScheduledTransaction(budgetID, c.Save)
+			case schedplan.Update:
+				st, err = apiClient.UpdateScheduledTransaction(budgetID, c.ID, c.Save)
+			case schedplan.Delete:
+				st, err = apiClient.DeleteScheduledTransaction(budgetID, c.ID)
+			}
+			id, amount := c.ID, c.Save.Amount
+			if err == nil {
+				id, amount = st.ID, st.Amount
+			}
+			log.Record(c.Action+" scheduled", id, label, amount, err)
+			if err != nil {
+				return fmt.Errorf("failed to %s scheduled transaction %s: %w", c.Action, label, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return formatter.Print(changes)
+	},
+}
+
+func init() {
+	scheduledCmd.AddCommand(scheduledExportCmd)
+	scheduledCmd.AddCommand(scheduledApplyCmd)
+
+	scheduledExportCmd.Flags().StringVar(&schedPlanOut, "out", "", "Output file (default: stdout)")
+	scheduledApplyCmd.Flags().BoolVar(&schedPlanDryRun, "dry-run", false, "Show the changes without applying them")
+	scheduledApplyCmd.Flags().BoolVar(&schedPlanPrune, "prune", false, "Delete scheduled transactions that are not in the plan")
+	addResumeFlag(scheduledApplyCmd)
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/internal/slugs"
+	"github.com/spf13/cobra"
+)
+
+var slugsCmd = &cobra.Command{
+	Use:   "slugs",
+	Short: "Short stable names for accounts, categories and payees",
+	Long: `Accounts, categories and payees get a slug such as "groceries" or
+"visa-credit" the first time ynabctl sees them. Slugs are shown in list
+output and accepted everywhere an ID or name is, and they stay the same
+when the record is renamed in YNAB, so scripts can rely on them.
+
+Slugs are kept in slugs.json next to the config file.`,
+}
+
+var slugsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the slugs of the budget",
+	Long: `List the slugs of all accounts, categories and payees of the budget.
+Records that have none yet are given one.`,
+	Example: `  ynabctl slugs list -f table`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+
+		spinner := progress.Start("fetching accounts, categories and payees")
+		accounts, err := apiClient.GetAccounts(budgetID)
+		if err != nil {
+			spinner.Stop()
+			return fmt.Errorf("failed to get accounts: %w", err)
+		}
+		groups, err := apiClient.GetCategories(budgetID)
+		if err != nil {
+			spinner.Stop()
+			return fmt.Errorf("failed to get categories: %w", err)
+		}
+		payees, err := apiClient.GetPayees(budgetID)
+		spinner.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to get payees: %w", err)
+		}
+
+		for _, a := range accounts {
+			if !a.Deleted {
+				slugStore.Assign(budgetID, slugs.Account, a.ID, a.Name)
+			}
+		}
+		for _, g := range groups {
+			for _, c := range g.Categories {
+				if !c.Deleted {
+					slugStore.Assign(budgetID, slugs.Category, c.ID, c.Name)
+				}
+			}
+		}
+		for _, p := range payees {
+			if !p.Deleted {
+				slugStore.Assign(budgetID, slugs.Payee, p.ID, p.Name)
+			}
+		}
+
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(slugStore.Entries(budgetID))
+	},
+}
+
+var slugsSetCmd = &cobra.Command{
+	Use:   "set <account|category|payee> <id-or-name> <slug>",
+	Short: "Choose the slug of a record",
+	Long: `Replace the generated slug of an account, category or payee. Slugs are
+lowercase letters, digits and dashes, and unique per kind within a budget.
+The old slug stops working.`,
+	Example: `  ynabctl slugs set account "Visa Credit Card" visa
+  ynabctl slugs set category "Bills: Electric" power`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		kind, ref, slug := args[0], args[1], args[2]
+
+		var id string
+		switch kind {
+		case slugs.Account:
+			id, err = resolveAccountID(budgetID, ref)
+		case slugs.Category:
+			id, err = resolveCategoryID(budgetID, ref)
+		case slugs.Payee:
+			id, err = resolvePayeeID(budgetID, ref)
+		default:
+			return fmt.Errorf("invalid kind %q (want account|category|payee)", kind)
+		}
+		if err != nil {
+			return err
+		}
+		if err := slugStore.Set(budgetID, kind, id, slug); err != nil {
+			return err
+		}
+		infof("%s %s is now %q\n", kind, ref, slug)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(slugsCmd)
+	slugsCmd.AddCommand(slugsListCmd)
+	slugsCmd.AddCommand(slugsSetCmd)
+}
@@ -7,8 +7,9 @@ import (
 	"path/filepath"
 	"time"
 
-	"github.com/langtind/ynabctl/internal/client"
 	"github.com/langtind/ynabctl/internal/period"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/pkg/ynab"
 	"github.com/spf13/cobra"
 )
 
@@ -20,15 +21,15 @@ var (
 )
 
 type snapshot struct {
-	Period       period.Range                  `json:"period"`
-	FetchedAt    string                        `json:"fetched_at"`
-	BudgetID     string                        `json:"budget_id"`
-	Accounts     []client.Account              `json:"accounts"`
-	Categories   []client.CategoryGroup        `json:"categories"`
-	Payees       []client.Payee                `json:"payees"`
-	Months       []client.Month                `json:"months"`
-	Transactions []client.Transaction          `json:"transactions"`
-	Scheduled    []client.ScheduledTransaction `json:"scheduled_transactions"`
+	Period       period.Range                `json:"period"`
+	FetchedAt    string                      `json:"fetched_at"`
+	BudgetID     string                      `json:"budget_id"`
+	Accounts     []ynab.Account              `json:"accounts"`
+	Categories   []ynab.CategoryGroup        `json:"categories"`
+	Payees       []ynab.Payee                `json:"payees"`
+	Months       []ynab.Month                `json:"months"`
+	Transactions []ynab.Transaction          `json:"transactions"`
+	Scheduled    []ynab.ScheduledTransaction `json:"scheduled_transactions"`
 }
 
 var snapshotCmd = &cobra.Command{
@@ -52,30 +53,39 @@ unless --out is given.`,
 			return err
 		}
 
+		bar := progress.NewBar("fetching snapshot", 6)
+		defer bar.Done()
+
 		accounts, err := apiClient.GetAccounts(bID)
 		if err != nil {
 			return fmt.Errorf("accounts: %w", err)
 		}
+		bar.Add(1)
 		cats, err := apiClient.GetCategories(bID)
 		if err != nil {
 			return fmt.Errorf("categories: %w", err)
 		}
+		bar.Add(1)
 		payees, err := apiClient.GetPayees(bID)
 		if err != nil {
 			return fmt.Errorf("payees: %w", err)
 		}
+		bar.Add(1)
 		months, err := apiClient.GetMonths(bID)
 		if err != nil {
 			return fmt.Errorf("months: %w", err)
 		}
-		txns, err := apiClient.GetTransactions(bID, &client.TransactionFilter{SinceDate: p.StartDate})
+		bar.Add(1)
+		txns, err := apiClient.GetTransactions(bID, &ynab.TransactionFilter{SinceDate: p.StartDate})
 		if err != nil {
 			return fmt.Errorf("transactions: %w", err)
 		}
+		bar.Add(1)
 		sched, err := apiClient.GetScheduledTransactions(bID)
 		if err != nil {
 			return fmt.Errorf("scheduled: %w", err)
 		}
+		bar.Done()
 
 		snap := snapshot{
 			Period:       p,
@@ -105,7 +115,7 @@ unless --out is given.`,
 			if err := os.WriteFile(outPath, data, 0o644); err != nil {
 				return fmt.Errorf("write %s: %w", outPath, err)
 			}
-			fmt.Fprintf(os.Stderr, "snapshot saved: %s (%s → %s)\n", outPath, p.StartDate, p.EndDate)
+			infof("snapshot saved: %s (%s → %s)\n", outPath, p.StartDate, p.EndDate)
 			fmt.Println(outPath)
 			return nil
 		}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statusShort  bool
+	statusMaxAge time.Duration
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the budget at a glance",
+	Long: `Show To Be Budgeted, the number of unapproved and uncategorized
+transactions and the age of money.
+
+--short prints a single line for tmux, starship and other status bars:
+
+  TBB:1 250 | unappr:4 | uncat:2 | AoM:34d
+
+Status bars run the command often, so the result is cached and reused
+for --max-age. A refresh costs three requests: the current month, the
+accounts and only the transactions changed since the last refresh. If
+a refresh fails, the last cached status is shown with a warning. With
+--offline the cached status is shown however old it is.`,
+	Example: `  ynabctl status -f table
+  ynabctl status --short
+  ynabctl status --short --max-age 30m`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+
+		path := statusCachePath(budgetID)
+		cached, cacheErr := loadStatus(path)
+		if cacheErr != nil && !os.IsNotExist(cacheErr) {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring status cache: %v\n", cacheErr)
+		}
+
+		status := cached
+		switch {
+		case offlineMode && cached != nil:
+			noteOfflineAsOf(cached.UpdatedAt)
+		case offlineMode:
+			// computed from cached responses, which are not saved as a
+			// fresh status
+			if status, err = refreshStatus(budgetID); err != nil {
+				return err
+			}
+		case cached == nil || time.Since(cached.UpdatedAt) >= statusMaxAge:
+			status, err = refreshStatus(budgetID)
+			if err != nil {
+				if cached == nil {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "Warning: showing status from %s: %v\n", cached.UpdatedAt.Local().Format("15:04"), err)
+				status = cached
+			} else if err := writeJSONFile(path, status); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to save status cache: %v\n", err)
+			}
+		}
+
+		if statusShort {
+			fmt.Println(status.Short())
+			return nil
+		}
+		return output.New(getOutputFormat()).Print(status)
+	},
+}
+
+// refreshStatus computes the status from the API
+func refreshStatus(budgetID string) (*report.Status, error) {
+	month, err := apiClient.GetMonth(budgetID, "current")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get month: %w", err)
+	}
+	accounts, err := apiClient.GetAccounts(budgetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+	transactions, err := cachedTransactions(budgetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+	return report.ComputeStatus(month, transactions, accounts, time.Now()), nil
+}
+
+// statusCachePath returns the file holding the last status of a budget
+// (e.g. ~/.cache/ynabctl/status/<budget-id>.json)
+func statusCachePath(budgetID string) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "ynabctl", "status", budgetID+".json")
+}
+
+// loadStatus reads a cached status
+func loadStatus(path string) (*report.Status, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s report.Status
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+	statusCmd.Flags().BoolVar(&statusShort, "short", false, "Print one compact line for status bars")
+	statusCmd.Flags().DurationVar(&statusMaxAge, "max-age", 10*time.Minute, "Reuse a cached status younger than this (0 always refreshes)")
+}
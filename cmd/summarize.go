@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/period"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	summarizeMonth    string
+	summarizeTop      int
+	summarizeLookback int
+)
+
+var summarizeCmd = &cobra.Command{
+	Use:   "summarize",
+	Short: "Summarize a month in plain words",
+	Long: `Write a short Markdown summary of a month, ready to paste into a family
+chat: how much was spent compared to the month before, the top
+categories, what is overspent, the categories that changed the most and
+the payees paid for the first time.
+
+A payee counts as new when it had no transactions in the --lookback
+months before. Pass -f json for the numbers behind the summary.`,
+	Example: `  ynabctl summarize
+  ynabctl summarize --month 2025-03 --top 3
+  ynabctl summarize -f json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		month, err := parseMonthArg(summarizeMonth)
+		if err != nil {
+			return err
+		}
+		if summarizeLookback < 1 {
+			return fmt.Errorf("--lookback must be at least 1")
+		}
+
+		m, err := apiClient.GetMonth(budgetID, month)
+		if err != nil {
+			return fmt.Errorf("failed to get month: %w", err)
+		}
+
+		start, _ := time.Parse("2006-01-02", month)
+		since := start.AddDate(0, -summarizeLookback, 0).Format("2006-01-02")
+		spinner := progress.Start("fetching transactions")
+		transactions, err := apiClient.GetTransactions(budgetID, &ynab.TransactionFilter{SinceDate: since})
+		spinner.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to get transactions: %w", err)
+		}
+
+		summary, err := report.SummarizeMonth(m, transactions, summarizeTop)
+		if err != nil {
+			return err
+		}
+
+		// the summary is prose, so only an explicit -f json changes it
+		if cmd.Flags().Changed("format") && getOutputFormat() == "json" {
+			return output.New("json").Print(summary)
+		}
+		fmt.Print(summary.Markdown())
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(summarizeCmd)
+	summarizeCmd.Flags().StringVar(&summarizeMonth, "month", "current", "Month to summarize ("+period.MonthHelp+")")
+	summarizeCmd.Flags().IntVar(&summarizeTop, "top", 5, "Number of entries in each list")
+	summarizeCmd.Flags().IntVar(&summarizeLookback, "lookback", 6, "Months of history used to tell new payees from known ones")
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/langtind/ynabctl/internal/ledger"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncDir    string
+	syncNoPush bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Mirror transactions to a local ledger directory",
+	Long: `Keep a directory of per-month JSON files (YYYY-MM.json) in sync with
+the budget's transactions.
+
+Each sync first pushes new local entries, i.e. entries without an "id",
+to YNAB. They are given an import_id so YNAB can match them later and a
+retried push never creates duplicates. It then pulls every transaction
+changed since the previous sync and rewrites the month files. Remote
+data wins for entries that already have an id; edit those in YNAB.
+
+A new entry needs at least date, account_id, and amount (a decimal,
+negative for outflows):
+
+  {"date": "2025-03-14", "account_id": "<id>", "payee": "Bakery", "amount": -4.5}
+
+The server knowledge of the last sync is kept in .sync-state.json. Other
+files in the directory are left alone.`,
+	Example: `  ynabctl sync --dir ./ledger
+  ynabctl sync --dir ./ledger --no-push`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+
+		state, err := ledger.LoadState(syncDir)
+		if err != nil {
+			return fmt.Errorf("failed to read sync state: %w", err)
+		}
+		if state.BudgetID != "" && state.BudgetID != budgetID {
+			return fmt.Errorf("%s mirrors budget %s, not %s", syncDir, state.BudgetID, budgetID)
+		}
+		state.BudgetID = budgetID
+
+		entries, err := ledger.Load(syncDir)
+		if err != nil {
+			return fmt.Errorf("failed to load ledger: %w", err)
+		}
+
+		pending := ledger.Pending(entries)
+		if len(pending) > 0 && !syncNoPush {
+			for _, e := range pending {
+				if err := e.Validate(); err != nil {
+					return fmt.Errorf("local entry (%s, %q, %.2f) %w", e.Date, e.PayeeName, e.Amount, err)
+				}
+			}
+			ledger.AssignImportIDs(entries)
+			if err := ledger.Save(syncDir, entries); err != nil {
+				return fmt.Errorf("failed to save ledger: %w", err)
+			}
+			if err := pushLedgerEntries(budgetID, ledger.Pending(entries)); err != nil {
+				return err
+			}
+		}
+
+		spinner := progress.Start("pulling transactions")
+		remote, knowledge, err := apiClient.GetTransactionsSince(budgetID, state.ServerKnowledge)
+		spinner.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to get transactions: %w", err)
+		}
+		entries = ledger.Merge(entries, remote)
+
+		if err := ledger.Save(syncDir, entries); err != nil {
+			return fmt.Errorf("failed to save ledger: %w", err)
+		}
+		state.ServerKnowledge = knowledge
+		if err := ledger.SaveState(syncDir, state); err != nil {
+			return fmt.Errorf("failed to save sync state: %w", err)
+		}
+
+		infof("pulled %d changed transactions, %d entries in %s (server knowledge %d)\n",
+			len(remote), len(entries), syncDir, knowledge)
+		return nil
+	},
+}
+
+// pushLedgerEntries creates the given new local entries in YNAB
+func pushLedgerEntries(budgetID string, pending []ledger.Entry) error {
+	log := output.NewChangelog("ynabctl sync")
+	defer finishChangelog(log)
+
+	txns := make([]ynab.SaveTransaction, 0, len(pending))
+	for _, e := range pending {
+		txns = append(txns, e.ToSave())
+	}
+
+	result, err := apiClient.CreateTransactions(budgetID, txns)
+	if err != nil {
+		for _, e := range pending {
+			log.Record("create", e.ImportID, e.Date+" "+e.PayeeName, e.ToSave().Amount, err)
+		}
+		return fmt.Errorf("failed to push transactions: %w", err)
+	}
+	duplicate := map[string]bool{}
+	for _, id := range result.DuplicateImportIDs {
+		duplicate[id] = true
+	}
+	for _, e := range pending {
+		action := "create"
+		if duplicate[e.ImportID] {
+			action = "skip duplicate"
+		}
+		log.Record(action, e.ImportID, e.Date+" "+e.PayeeName, e.ToSave().Amount, nil)
+	}
+	infof("pushed %d new transactions (%d already existed)\n", len(result.TransactionIDs), len(result.DuplicateImportIDs))
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.Flags().StringVar(&syncDir, "dir", "", "Ledger directory (required)")
+	syncCmd.Flags().BoolVar(&syncNoPush, "no-push", false, "Only pull remote changes")
+	_ = syncCmd.MarkFlagRequired("dir")
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+// allowNegativeTBB is set by --allow-negative-tbb: assign money even
+// when To Be Budgeted goes negative
+var allowNegativeTBB bool
+
+var tbbSnapshotDir string
+
+var tbbCmd = &cobra.Command{
+	Use:   "tbb [month]",
+	Short: "Show To Be Budgeted (Ready to Assign)",
+	Long: `Show To Be Budgeted, the money not yet assigned to a category, for a
+month (YYYY-MM, last, next, -1 or a month name; default: current).
+
+Commands that assign money (categories update, categories apply, fund)
+refuse to push To Be Budgeted below zero unless --allow-negative-tbb is
+given.`,
+	Example: `  ynabctl tbb
+  ynabctl tbb 2026-03 -f table
+  ynabctl tbb history --snapshots data/raw -f table`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		arg := "current"
+		if len(args) > 0 {
+			arg = args[0]
+		}
+		month, err := parseMonthArg(arg)
+		if err != nil {
+			return err
+		}
+
+		m, err := apiClient.GetMonth(budgetID, month)
+		if err != nil {
+			return fmt.Errorf("failed to get month: %w", err)
+		}
+
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(report.NewTBB(m))
+	},
+}
+
+var tbbHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show how To Be Budgeted developed",
+	Long: `Show To Be Budgeted over time, read from the snapshots written by
+"ynabctl snapshot" in --snapshots <dir>. Each snapshot gives the value of
+the month it was fetched in; today's value is added at the end.`,
+	Example: `  ynabctl tbb history --snapshots data/raw -f table`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+
+		snaps, err := loadSnapshotMonths(tbbSnapshotDir, budgetID)
+		if err != nil {
+			return err
+		}
+		var points []report.TBBPoint
+		for _, snap := range snaps {
+			if m := snap.current(); m != nil {
+				points = append(points, report.TBBPoint{
+					Date:         snap.Fetched.Format("2006-01-02"),
+					Month:        m.Month,
+					ToBeBudgeted: m.ToBeBudgeted,
+					Source:       report.TBBFromSnapshot,
+				})
+			}
+		}
+
+		now := time.Now()
+		m, err := apiClient.GetMonth(budgetID, now.Format("2006-01")+"-01")
+		if err != nil {
+			return fmt.Errorf("failed to get month: %w", err)
+		}
+		points = append(points, report.TBBPoint{
+			Date:         now.Format("2006-01-02"),
+			Month:        m.Month,
+			ToBeBudgeted: m.ToBeBudgeted,
+			Source:       report.TBBFromCurrent,
+		})
+
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(report.TrendTBB(points))
+	},
+}
+
+// guardTBB refuses to assign money that would leave To Be Budgeted
+// negative. tbb holds the current To Be Budgeted of each month and
+// assigned the increase of its budgeted total.
+func guardTBB(tbb, assigned map[string]int64) error {
+	if allowNegativeTBB {
+		return nil
+	}
+	b := report.CheckTBB(tbb, assigned)
+	if b == nil {
+		return nil
+	}
+	return fmt.Errorf("assigning %.2f more in %s would leave To Be Budgeted at %.2f (now %.2f); use --allow-negative-tbb to do it anyway",
+		ynab.MilliunitsToAmount(b.Assigned), b.Month, ynab.MilliunitsToAmount(b.After), ynab.MilliunitsToAmount(b.Before))
+}
+
+// addTBBGuardFlag adds --allow-negative-tbb to a command that assigns
+// money
+func addTBBGuardFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&allowNegativeTBB, "allow-negative-tbb", false, "Assign money even if To Be Budgeted goes negative")
+}
+
+func init() {
+	rootCmd.AddCommand(tbbCmd)
+	tbbCmd.AddCommand(tbbHistoryCmd)
+
+	tbbHistoryCmd.Flags().StringVar(&tbbSnapshotDir, "snapshots", "", "Directory with snapshots written by \"ynabctl snapshot\" (required)")
+	_ = tbbHistoryCmd.MarkFlagRequired("snapshots")
+}
@@ -2,10 +2,16 @@ package cmd
 
 import (
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
 	"time"
 
-	"github.com/langtind/ynabctl/internal/client"
+	"github.com/langtind/ynabctl/internal/calc"
 	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/langtind/ynabctl/internal/tags"
+	"github.com/langtind/ynabctl/pkg/ynab"
 	"github.com/spf13/cobra"
 )
 
@@ -17,10 +23,20 @@ var transactionsCmd = &cobra.Command{
 
 var (
 	txnSinceDate  string
+	txnUntilDate  string
+	txnBeforeDate string
 	txnType       string
-	txnAccountID  string
-	txnCategoryID string
+	txnAccounts   []string
+	txnCategories []string
 	txnPayeeID    string
+	txnTag        string
+	txnReverse    bool
+	txnHead       int
+	txnTail       int
+	txnMinAmount  float64
+	txnMaxAmount  float64
+	txnCollapse   bool
+	txnBucket     string
 )
 
 var transactionsListCmd = &cobra.Command{
@@ -30,27 +46,108 @@ var transactionsListCmd = &cobra.Command{
 
 Use filters to narrow down results:
   --since: Only return transactions on or after this date (YYYY-MM-DD)
+  --until: Only return transactions on or before this date
+  --before: Only return transactions before this date
   --type: Filter by transaction type (uncategorized, unapproved)
-  --account: Filter by account ID
-  --category: Filter by category ID
-  --payee: Filter by payee ID`,
+  --account: Filter by account ID or name (repeat for several accounts)
+  --category: Filter by category ID or name (repeat for several)
+  --payee: Filter by payee ID
+  --tag: Only return transactions whose memo carries this #tag
+  --min-amount, --max-amount: Only return transactions with an amount in
+    this range (inclusive)
+
+Amounts are signed: outflows are negative and inflows positive, so
+"--min-amount -100 --max-amount -20" finds spending between 20 and 100,
+and "--min-amount 0" finds only inflows. The API cannot filter by amount,
+so these flags work on a local copy of the budget's transactions that is
+updated with only the changes since the last run; the first run fetches
+everything.
+
+--collapse-transfers shows a transfer between two listed accounts as
+one row instead of two: the outflow half is kept, with "From → To" as
+its payee, and the inflow half is dropped. A transfer whose other half
+is not in the list (e.g. with --account) is shown as it is.
+
+Transactions are sorted by date, oldest first. Use --reverse for newest
+first, and --head/--tail to keep only the first or last N after sorting.
+
+--bucket day|week|month groups the list by period: tables get a header
+row and a subtotal per day, ISO week or month, and JSON becomes a list
+of buckets, each with its count, total and transactions.`,
+	Example: `  ynabctl transactions list --tail 20 -f table
+  ynabctl transactions list --reverse --head 10
+  ynabctl transactions list --since 2025-07-01 --before 2025-08-01
+  ynabctl transactions list --account Checking --account Savings --since 2025-01-01
+  ynabctl transactions list --min-amount -500 --max-amount -100 --since 2025-01-01
+  ynabctl transactions list --since 2025-07-01 --collapse-transfers -f table
+  ynabctl transactions list --since 2025-01-01 --bucket month -f table`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		budgetID, err := getBudgetID()
 		if err != nil {
 			return err
 		}
+		if txnBucket != "" {
+			if _, err := report.BucketTransactions(nil, txnBucket); err != nil {
+				return err
+			}
+		}
+
+		if txnHead > 0 && txnTail > 0 {
+			return fmt.Errorf("use either --head or --tail, not both")
+		}
+		until, err := untilDate(txnUntilDate, txnBeforeDate)
+		if err != nil {
+			return err
+		}
 
-		var transactions []client.Transaction
+		if txnPayeeID, err = resolvePayeeID(budgetID, txnPayeeID); err != nil {
+			return err
+		}
+		for i := range txnAccounts {
+			if txnAccounts[i], err = resolveAccountID(budgetID, txnAccounts[i]); err != nil {
+				return err
+			}
+		}
+		for i := range txnCategories {
+			if txnCategories[i], err = resolveCategoryID(budgetID, txnCategories[i]); err != nil {
+				return err
+			}
+		}
 
-		// Use specific endpoint if filtering by account, category, or payee
-		if txnAccountID != "" {
-			transactions, err = apiClient.GetTransactionsByAccount(budgetID, txnAccountID, txnSinceDate)
-		} else if txnCategoryID != "" {
-			transactions, err = apiClient.GetTransactionsByCategory(budgetID, txnCategoryID, txnSinceDate)
+		var transactions []ynab.Transaction
+		amountRange := cmd.Flags().Changed("min-amount") || cmd.Flags().Changed("max-amount")
+		minAmount, maxAmount := int64(math.MinInt64), int64(math.MaxInt64)
+		if cmd.Flags().Changed("min-amount") {
+			minAmount = ynab.AmountToMilliunits(txnMinAmount)
+		}
+		if cmd.Flags().Changed("max-amount") {
+			maxAmount = ynab.AmountToMilliunits(txnMaxAmount)
+		}
+		if minAmount > maxAmount {
+			return fmt.Errorf("--min-amount must not be greater than --max-amount")
+		}
+
+		// Use specific endpoints if filtering by account, category, or
+		// payee, one request per account or category; amount ranges are
+		// filtered locally, as is everything when offline or with --delta
+		local := amountRange || offlineMode || listDelta
+		if local {
+			transactions, err = cachedTransactions(budgetID)
+		} else if len(txnAccounts) > 0 {
+			transactions, err = fetchEach(txnAccounts, func(id string) ([]ynab.Transaction, error) {
+				return apiClient.GetTransactionsByAccount(budgetID, id, txnSinceDate)
+			})
+			if len(txnCategories) > 0 {
+				transactions = filterInCategories(transactions, txnCategories)
+			}
+		} else if len(txnCategories) > 0 {
+			transactions, err = fetchEach(txnCategories, func(id string) ([]ynab.Transaction, error) {
+				return apiClient.GetTransactionsByCategory(budgetID, id, txnSinceDate)
+			})
 		} else if txnPayeeID != "" {
 			transactions, err = apiClient.GetTransactionsByPayee(budgetID, txnPayeeID, txnSinceDate)
 		} else {
-			filter := &client.TransactionFilter{
+			filter := &ynab.TransactionFilter{
 				SinceDate: txnSinceDate,
 				Type:      txnType,
 			}
@@ -61,7 +158,30 @@ Use filters to narrow down results:
 			return fmt.Errorf("failed to get transactions: %w", err)
 		}
 
+		if local {
+			transactions = filterCached(transactions, minAmount, maxAmount)
+		}
+		// The API only supports since_date, so the end of the range is
+		// applied here
+		if until != "" {
+			transactions = filterUntil(transactions, until)
+		}
+		if txnTag != "" {
+			transactions = filterByTag(transactions, txnTag)
+		}
+		if txnCollapse {
+			transactions = collapseTransfers(transactions)
+		}
+		transactions = orderTransactions(transactions, txnReverse, txnHead, txnTail)
+
 		formatter := output.New(getOutputFormat())
+		if txnBucket != "" {
+			buckets, err := report.BucketTransactions(transactions, txnBucket)
+			if err != nil {
+				return err
+			}
+			return formatter.Print(buckets)
+		}
 		return formatter.Print(transactions)
 	},
 }
@@ -76,6 +196,9 @@ var transactionsGetCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		if args[0], err = resolveTransactionID(budgetID, args[0]); err != nil {
+			return err
+		}
 
 		transaction, err := apiClient.GetTransaction(budgetID, args[0])
 		if err != nil {
@@ -113,11 +236,17 @@ Optional flags:
   --date: Transaction date (YYYY-MM-DD, default: today)
   --payee-id: Payee ID
   --payee-name: Payee name (creates new payee if needed)
-  --category: Category ID
+  --category: Category ID or name
   --memo: Transaction memo
   --cleared: Cleared status (cleared, uncleared, reconciled)
   --approved: Whether the transaction is approved
-  --flag: Flag color (red, orange, yellow, green, blue, purple)`,
+  --flag: Flag color (red, orange, yellow, green, blue, purple)
+
+--amount also takes simple arithmetic (+ - * / and parentheses), so a
+receipt with several identical items needs no calculator: three items
+at 129.90 and one at 45 is --amount "-(3*129.90+45)".`,
+	Example: `  ynabctl transactions create --account <account-id> --amount -50 --payee-name "Coffee Shop"
+  ynabctl transactions create --account <account-id> --amount "-(3*129.90+45)" --payee-name Rema`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		budgetID, err := getBudgetID()
 		if err != nil {
@@ -133,10 +262,21 @@ Optional flags:
 			date = time.Now().Format("2006-01-02")
 		}
 
-		txn := client.SaveTransaction{
+		if newTxnAccountID, err = resolveAccountID(budgetID, newTxnAccountID); err != nil {
+			return err
+		}
+		if newTxnPayeeID, err = resolvePayeeID(budgetID, newTxnPayeeID); err != nil {
+			return err
+		}
+		newTxnCategoryID, err = resolveCategoryID(budgetID, newTxnCategoryID)
+		if err != nil {
+			return err
+		}
+
+		txn := ynab.SaveTransaction{
 			AccountID:  newTxnAccountID,
 			Date:       date,
-			Amount:     client.AmountToMilliunits(newTxnAmount),
+			Amount:     ynab.AmountToMilliunits(newTxnAmount),
 			PayeeID:    newTxnPayeeID,
 			PayeeName:  newTxnPayeeName,
 			CategoryID: newTxnCategoryID,
@@ -156,72 +296,118 @@ Optional flags:
 	},
 }
 
+var txnIfUnmodifiedSince int64
+
 var transactionsUpdateCmd = &cobra.Command{
 	Use:   "update <transaction-id>",
 	Short: "Update a transaction",
 	Long: `Update an existing transaction.
 
-All update flags are optional. Only specified fields will be updated.`,
+All update flags are optional. Only the specified fields are sent to
+YNAB, so concurrent edits to other fields are preserved. The changed
+fields are shown before saving and must be confirmed unless --yes is
+given.
+
+Transactions dated before the last reconciliation of their account (or
+moved there) are only changed with --allow-reconciled.
+
+Before saving, ynabctl checks that the transaction has not been modified
+since it was read. Pass --if-unmodified-since <server-knowledge> to check
+against an earlier read instead; the server knowledge after each update
+is printed to stderr.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		budgetID, err := getBudgetID()
 		if err != nil {
 			return err
 		}
+		if args[0], err = resolveTransactionID(budgetID, args[0]); err != nil {
+			return err
+		}
 
-		// First get the existing transaction
-		existing, err := apiClient.GetTransaction(budgetID, args[0])
+		// Read the current transaction to show what will change
+		existing, knowledge, err := apiClient.GetTransactionWithKnowledge(budgetID, args[0])
 		if err != nil {
 			return fmt.Errorf("failed to get existing transaction: %w", err)
 		}
-
-		// Build update with existing values, override with any provided flags
-		txn := client.SaveTransaction{
-			AccountID:  existing.AccountID,
-			Date:       existing.Date,
-			Amount:     existing.Amount,
-			PayeeID:    existing.PayeeID,
-			CategoryID: existing.CategoryID,
-			Memo:       existing.Memo,
-			Cleared:    existing.Cleared,
-			Approved:   existing.Approved,
-			FlagColor:  existing.FlagColor,
+		if cmd.Flags().Changed("if-unmodified-since") {
+			knowledge = txnIfUnmodifiedSince
 		}
 
+		patch := ynab.PatchTransaction{ID: args[0]}
+		var changes output.Changes
+
 		if cmd.Flags().Changed("account") {
-			txn.AccountID = newTxnAccountID
+			if newTxnAccountID, err = resolveAccountID(budgetID, newTxnAccountID); err != nil {
+				return err
+			}
+			patch.AccountID = &newTxnAccountID
+			changes.Add("account", existing.AccountID, newTxnAccountID)
 		}
 		if cmd.Flags().Changed("date") {
-			txn.Date = newTxnDate
+			patch.Date = &newTxnDate
+			changes.Add("date", existing.Date, newTxnDate)
 		}
 		if cmd.Flags().Changed("amount") {
-			txn.Amount = client.AmountToMilliunits(newTxnAmount)
+			amount := ynab.AmountToMilliunits(newTxnAmount)
+			patch.Amount = &amount
+			changes.AddAmount("amount", existing.Amount, amount)
 		}
 		if cmd.Flags().Changed("payee-id") {
-			txn.PayeeID = newTxnPayeeID
+			if newTxnPayeeID, err = resolvePayeeID(budgetID, newTxnPayeeID); err != nil {
+				return err
+			}
+			patch.PayeeID = &newTxnPayeeID
+			changes.Add("payee_id", existing.PayeeID, newTxnPayeeID)
 		}
 		if cmd.Flags().Changed("payee-name") {
-			txn.PayeeName = newTxnPayeeName
+			patch.PayeeName = &newTxnPayeeName
+			changes.Add("payee_name", existing.PayeeName, newTxnPayeeName)
 		}
 		if cmd.Flags().Changed("category") {
-			txn.CategoryID = newTxnCategoryID
+			newTxnCategoryID, err = resolveCategoryID(budgetID, newTxnCategoryID)
+			if err != nil {
+				return err
+			}
+			patch.CategoryID = &newTxnCategoryID
+			changes.Add("category", existing.CategoryID, newTxnCategoryID)
 		}
 		if cmd.Flags().Changed("memo") {
-			txn.Memo = newTxnMemo
+			patch.Memo = &newTxnMemo
+			changes.Add("memo", existing.Memo, newTxnMemo)
 		}
 		if cmd.Flags().Changed("cleared") {
-			txn.Cleared = newTxnCleared
+			patch.Cleared = &newTxnCleared
+			changes.Add("cleared", existing.Cleared, newTxnCleared)
 		}
 		if cmd.Flags().Changed("approved") {
-			txn.Approved = newTxnApproved
+			patch.Approved = &newTxnApproved
+			changes.Add("approved", existing.Approved, newTxnApproved)
 		}
 		if cmd.Flags().Changed("flag") {
-			txn.FlagColor = newTxnFlagColor
+			patch.FlagColor = &newTxnFlagColor
+			changes.Add("flag", existing.FlagColor, newTxnFlagColor)
 		}
 
-		transaction, err := apiClient.UpdateTransaction(budgetID, args[0], txn)
+		accountID, date := existing.AccountID, existing.Date
+		if patch.AccountID != nil {
+			accountID = *patch.AccountID
+		}
+		if patch.Date != nil {
+			date = *patch.Date
+		}
+		if err := guardReconciled(budgetID, existing, accountID, date); err != nil {
+			return err
+		}
+
+		ok, err := confirmChanges("transaction "+args[0], changes)
+		if err != nil || !ok {
+			return err
+		}
+
+		transaction, err := patchTransactionIfUnmodified(budgetID, patch, knowledge)
 		if err != nil {
-			return fmt.Errorf("failed to update transaction: %w", err)
+			return err
 		}
 
 		formatter := output.New(getOutputFormat())
@@ -229,16 +415,55 @@ All update flags are optional. Only specified fields will be updated.`,
 	},
 }
 
+// patchTransactionIfUnmodified applies patch unless the transaction has
+// changed on the server since the given server knowledge
+func patchTransactionIfUnmodified(budgetID string, patch ynab.PatchTransaction, knowledge int64) (*ynab.Transaction, error) {
+	changed, current, err := apiClient.GetTransactionsSince(budgetID, knowledge)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for concurrent changes: %w", err)
+	}
+	for _, t := range changed {
+		if t.ID == patch.ID {
+			return nil, fmt.Errorf("transaction %s was modified since server knowledge %d (now %d); re-run to review the latest version", patch.ID, knowledge, current)
+		}
+	}
+
+	updated, after, err := apiClient.PatchTransactions(budgetID, []ynab.PatchTransaction{patch})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update transaction: %w", err)
+	}
+	if len(updated) == 0 {
+		return nil, fmt.Errorf("failed to update transaction: no transaction returned")
+	}
+
+	infof("server knowledge: %d\n", after)
+	return &updated[0], nil
+}
+
 var transactionsDeleteCmd = &cobra.Command{
 	Use:   "delete <transaction-id>",
 	Short: "Delete a transaction",
-	Long:  `Delete a transaction from the budget.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Delete a transaction from the budget.
+
+A transaction dated before the last reconciliation of its account is
+only deleted with --allow-reconciled.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		budgetID, err := getBudgetID()
 		if err != nil {
 			return err
 		}
+		if args[0], err = resolveTransactionID(budgetID, args[0]); err != nil {
+			return err
+		}
+
+		existing, err := apiClient.GetTransaction(budgetID, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get transaction: %w", err)
+		}
+		if err := guardReconciled(budgetID, existing, existing.AccountID, existing.Date); err != nil {
+			return err
+		}
 
 		transaction, err := apiClient.DeleteTransaction(budgetID, args[0])
 		if err != nil {
@@ -250,6 +475,226 @@ var transactionsDeleteCmd = &cobra.Command{
 	},
 }
 
+// untilDate returns the last date (YYYY-MM-DD) included by --until or
+// --before, or "" if neither is set
+func untilDate(until, before string) (string, error) {
+	if until != "" && before != "" {
+		return "", fmt.Errorf("use either --until or --before, not both")
+	}
+	if until != "" {
+		if _, err := time.Parse("2006-01-02", until); err != nil {
+			return "", fmt.Errorf("invalid --until date %q (want YYYY-MM-DD)", until)
+		}
+		return until, nil
+	}
+	if before != "" {
+		t, err := time.Parse("2006-01-02", before)
+		if err != nil {
+			return "", fmt.Errorf("invalid --before date %q (want YYYY-MM-DD)", before)
+		}
+		return t.AddDate(0, 0, -1).Format("2006-01-02"), nil
+	}
+	return "", nil
+}
+
+// filterUntil keeps transactions dated on or before until
+func filterUntil(transactions []ynab.Transaction, until string) []ynab.Transaction {
+	filtered := []ynab.Transaction{}
+	for _, t := range transactions {
+		if t.Date <= until {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+// cachedTransactions returns all transactions of the budget by date from
+// the local copy, after fetching the changes since it was last updated
+func cachedTransactions(budgetID string) ([]ynab.Transaction, error) {
+	transactions, err := deltaItems(budgetID, "transactions", func(k int64) ([]ynab.Transaction, int64, error) {
+		return apiClient.GetTransactionsSince(budgetID, k)
+	}, func(t ynab.Transaction) string { return t.ID }, func(t ynab.Transaction) bool { return t.Deleted }, nil)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(transactions, func(i, j int) bool {
+		return transactions[i].Date < transactions[j].Date
+	})
+	return transactions, nil
+}
+
+// filterCached applies the amount range and the filters the API would
+// otherwise apply (--since, --type, --account, --category, --payee) to
+// cached transactions
+func filterCached(transactions []ynab.Transaction, minAmount, maxAmount int64) []ynab.Transaction {
+	filtered := []ynab.Transaction{}
+	for _, t := range transactions {
+		if t.Amount < minAmount || t.Amount > maxAmount {
+			continue
+		}
+		if txnSinceDate != "" && t.Date < txnSinceDate {
+			continue
+		}
+		if len(txnAccounts) > 0 && !contains(txnAccounts, t.AccountID) {
+			continue
+		}
+		if txnPayeeID != "" && t.PayeeID != txnPayeeID {
+			continue
+		}
+		if len(txnCategories) > 0 && !inCategory(t, txnCategories) {
+			continue
+		}
+		switch txnType {
+		case "unapproved":
+			if t.Approved {
+				continue
+			}
+		case "uncategorized":
+			if t.CategoryID != "" && t.CategoryName != "Uncategorized" {
+				continue
+			}
+		}
+		filtered = append(filtered, t)
+	}
+	return filtered
+}
+
+// inCategory reports whether t or one of its subtransactions is in one
+// of the categories
+func inCategory(t ynab.Transaction, categoryIDs []string) bool {
+	if contains(categoryIDs, t.CategoryID) {
+		return true
+	}
+	for _, st := range t.Subtransactions {
+		if contains(categoryIDs, st.CategoryID) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterInCategories keeps transactions in one of the categories
+func filterInCategories(transactions []ynab.Transaction, categoryIDs []string) []ynab.Transaction {
+	filtered := []ynab.Transaction{}
+	for _, t := range transactions {
+		if inCategory(t, categoryIDs) {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+// fetchEach calls fetch for every ID and merges the results, listing a
+// transaction found by several IDs (e.g. a split over two categories)
+// once
+func fetchEach(ids []string, fetch func(id string) ([]ynab.Transaction, error)) ([]ynab.Transaction, error) {
+	var merged []ynab.Transaction
+	seen := map[string]bool{}
+	for _, id := range ids {
+		transactions, err := fetch(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range transactions {
+			if !seen[t.ID] {
+				seen[t.ID] = true
+				merged = append(merged, t)
+			}
+		}
+	}
+	return merged, nil
+}
+
+func contains(values []string, v string) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// filterByTag keeps transactions whose memo, or the memo of one of
+// their subtransactions, carries tag
+func filterByTag(transactions []ynab.Transaction, tag string) []ynab.Transaction {
+	filtered := []ynab.Transaction{}
+	for _, t := range transactions {
+		match := tags.Has(t.Memo, tag)
+		for _, st := range t.Subtransactions {
+			match = match || tags.Has(st.Memo, tag)
+		}
+		if match {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+// collapseTransfers merges the two halves of each transfer into the
+// outflow half, labelled "From → To". Halves without their counterpart
+// in the list, and transfers in split transactions, are kept as they
+// are.
+func collapseTransfers(transactions []ynab.Transaction) []ynab.Transaction {
+	byID := make(map[string]ynab.Transaction, len(transactions))
+	for _, t := range transactions {
+		byID[t.ID] = t
+	}
+	collapsed := make([]ynab.Transaction, 0, len(transactions))
+	for _, t := range transactions {
+		other, ok := byID[t.TransferTransactionID]
+		if t.TransferTransactionID == "" || !ok || other.TransferTransactionID != t.ID {
+			collapsed = append(collapsed, t)
+			continue
+		}
+		// keep the outflow half; a zero amount transfer keeps the lower ID
+		if t.Amount > 0 || (t.Amount == 0 && t.ID > other.ID) {
+			continue
+		}
+		t.PayeeName = t.AccountName + " → " + other.AccountName
+		collapsed = append(collapsed, t)
+	}
+	return collapsed
+}
+
+// orderTransactions sorts by date (oldest first, or newest first when
+// reverse is set) and keeps the first head or last tail transactions
+func orderTransactions(transactions []ynab.Transaction, reverse bool, head, tail int) []ynab.Transaction {
+	sort.SliceStable(transactions, func(i, j int) bool {
+		if reverse {
+			return transactions[i].Date > transactions[j].Date
+		}
+		return transactions[i].Date < transactions[j].Date
+	})
+	if head > 0 && len(transactions) > head {
+		transactions = transactions[:head]
+	}
+	if tail > 0 && len(transactions) > tail {
+		transactions = transactions[len(transactions)-tail:]
+	}
+	return transactions
+}
+
+// amountValue is a float64 flag that also accepts arithmetic
+// expressions, evaluated when the flag is parsed
+type amountValue float64
+
+func (a *amountValue) Set(s string) error {
+	v, err := calc.Eval(s)
+	if err != nil {
+		return fmt.Errorf("invalid amount %q: %w", s, err)
+	}
+	*a = amountValue(v)
+	return nil
+}
+
+func (a *amountValue) String() string {
+	return strconv.FormatFloat(float64(*a), 'f', -1, 64)
+}
+
+func (a *amountValue) Type() string {
+	return "amount"
+}
+
 func init() {
 	rootCmd.AddCommand(transactionsCmd)
 	transactionsCmd.AddCommand(transactionsListCmd)
@@ -259,32 +704,48 @@ func init() {
 	transactionsCmd.AddCommand(transactionsDeleteCmd)
 
 	// List filters
+	addDeltaFlag(transactionsListCmd)
 	transactionsListCmd.Flags().StringVar(&txnSinceDate, "since", "", "Filter transactions since date (YYYY-MM-DD)")
+	transactionsListCmd.Flags().StringVar(&txnUntilDate, "until", "", "Filter transactions until date, inclusive (YYYY-MM-DD)")
+	transactionsListCmd.Flags().StringVar(&txnBeforeDate, "before", "", "Filter transactions before date, exclusive (YYYY-MM-DD)")
 	transactionsListCmd.Flags().StringVar(&txnType, "type", "", "Filter by type (uncategorized, unapproved)")
-	transactionsListCmd.Flags().StringVar(&txnAccountID, "account", "", "Filter by account ID")
-	transactionsListCmd.Flags().StringVar(&txnCategoryID, "category", "", "Filter by category ID")
-	transactionsListCmd.Flags().StringVar(&txnPayeeID, "payee", "", "Filter by payee ID")
+	transactionsListCmd.Flags().StringArrayVar(&txnAccounts, "account", nil, "Filter by account ID or name (repeatable)")
+	transactionsListCmd.Flags().StringArrayVar(&txnCategories, "category", nil, "Filter by category ID or name (repeatable)")
+	transactionsListCmd.Flags().StringVar(&txnPayeeID, "payee", "", "Filter by payee ID or name")
+	transactionsListCmd.Flags().StringVar(&txnTag, "tag", "", "Filter by memo #tag")
+	transactionsListCmd.Flags().BoolVar(&txnReverse, "reverse", false, "Sort newest first")
+	transactionsListCmd.Flags().IntVar(&txnHead, "head", 0, "Only show the first N transactions after sorting")
+	transactionsListCmd.Flags().IntVar(&txnTail, "tail", 0, "Only show the last N transactions after sorting")
+	transactionsListCmd.Flags().Float64Var(&txnMinAmount, "min-amount", 0, "Only show transactions with at least this amount (outflows are negative)")
+	transactionsListCmd.Flags().Float64Var(&txnMaxAmount, "max-amount", 0, "Only show transactions with at most this amount (outflows are negative)")
+	transactionsListCmd.Flags().StringVar(&txnBucket, "bucket", "", "Group by period with subtotals: day|week|month")
+	transactionsListCmd.Flags().BoolVar(&txnCollapse, "collapse-transfers", false, "Show both halves of a transfer as one row (From → To)")
 
 	// Create/Update flags
-	transactionsCreateCmd.Flags().StringVar(&newTxnAccountID, "account", "", "Account ID (required)")
+	transactionsCreateCmd.Flags().StringVar(&newTxnAccountID, "account", "", "Account ID or name (required)")
 	transactionsCreateCmd.Flags().StringVar(&newTxnDate, "date", "", "Transaction date (YYYY-MM-DD)")
-	transactionsCreateCmd.Flags().Float64Var(&newTxnAmount, "amount", 0, "Amount (positive=inflow, negative=outflow)")
-	transactionsCreateCmd.Flags().StringVar(&newTxnPayeeID, "payee-id", "", "Payee ID")
+	transactionsCreateCmd.Flags().Var((*amountValue)(&newTxnAmount), "amount", "Amount (positive=inflow, negative=outflow; arithmetic like \"-(3*129.90+45)\" allowed)")
+	transactionsCreateCmd.Flags().StringVar(&newTxnPayeeID, "payee-id", "", "Existing payee ID or name")
 	transactionsCreateCmd.Flags().StringVar(&newTxnPayeeName, "payee-name", "", "Payee name")
-	transactionsCreateCmd.Flags().StringVar(&newTxnCategoryID, "category", "", "Category ID")
+	transactionsCreateCmd.Flags().StringVar(&newTxnCategoryID, "category", "", "Category ID or name")
 	transactionsCreateCmd.Flags().StringVar(&newTxnMemo, "memo", "", "Memo")
 	transactionsCreateCmd.Flags().StringVar(&newTxnCleared, "cleared", "", "Cleared status")
 	transactionsCreateCmd.Flags().BoolVar(&newTxnApproved, "approved", false, "Approved")
 	transactionsCreateCmd.Flags().StringVar(&newTxnFlagColor, "flag", "", "Flag color")
+	addFromFileFlag(transactionsCreateCmd)
 
-	transactionsUpdateCmd.Flags().StringVar(&newTxnAccountID, "account", "", "Account ID")
+	transactionsUpdateCmd.Flags().StringVar(&newTxnAccountID, "account", "", "Account ID or name")
 	transactionsUpdateCmd.Flags().StringVar(&newTxnDate, "date", "", "Transaction date (YYYY-MM-DD)")
-	transactionsUpdateCmd.Flags().Float64Var(&newTxnAmount, "amount", 0, "Amount")
-	transactionsUpdateCmd.Flags().StringVar(&newTxnPayeeID, "payee-id", "", "Payee ID")
+	transactionsUpdateCmd.Flags().Var((*amountValue)(&newTxnAmount), "amount", "Amount (arithmetic like \"-(3*129.90+45)\" allowed)")
+	transactionsUpdateCmd.Flags().StringVar(&newTxnPayeeID, "payee-id", "", "Existing payee ID or name")
 	transactionsUpdateCmd.Flags().StringVar(&newTxnPayeeName, "payee-name", "", "Payee name")
-	transactionsUpdateCmd.Flags().StringVar(&newTxnCategoryID, "category", "", "Category ID")
+	transactionsUpdateCmd.Flags().StringVar(&newTxnCategoryID, "category", "", "Category ID or name")
 	transactionsUpdateCmd.Flags().StringVar(&newTxnMemo, "memo", "", "Memo")
 	transactionsUpdateCmd.Flags().StringVar(&newTxnCleared, "cleared", "", "Cleared status")
 	transactionsUpdateCmd.Flags().BoolVar(&newTxnApproved, "approved", false, "Approved")
 	transactionsUpdateCmd.Flags().StringVar(&newTxnFlagColor, "flag", "", "Flag color")
+	transactionsUpdateCmd.Flags().Int64Var(&txnIfUnmodifiedSince, "if-unmodified-since", 0, "Fail if the transaction changed after this server knowledge")
+	addFromFileFlag(transactionsUpdateCmd)
+	addAllowReconciledFlag(transactionsUpdateCmd)
+	addAllowReconciledFlag(transactionsDeleteCmd)
 }
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/langtind/ynabctl/internal/allocplan"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/period"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	allocatePlan   string
+	allocateMonth  string
+	allocateDryRun bool
+)
+
+var transactionsAllocateCmd = &cobra.Command{
+	Use:   "allocate <inflow-transaction-id> --plan <file>",
+	Short: "Budget an income transaction by an allocation plan",
+	Long: `Spread an inflow, e.g. a paycheck that just came in, over categories
+by a plan: fixed amounts, percentages of the inflow, and optionally one
+category that takes what is left. Each category's budgeted amount in the
+transaction's month (or --month) is raised by its share.
+
+  # paycheck.yaml
+  allocations:
+    - category: Rent
+      amount: 12000
+    - category: Savings
+      percent: 10
+    - category: "Everyday: Groceries"
+      percent: 15
+    - category: Fun
+      rest: true
+
+Percentages are rounded to whole cents. Without a rest entry, what is
+not allocated stays in Ready to Assign. The changes are shown and must
+be confirmed unless --yes is given; --dry-run only shows them. Assigning
+more than To Be Budgeted holds is refused unless --allow-negative-tbb
+is given.`,
+	Example: `  ynabctl transactions allocate <txn-id> --plan paycheck.yaml --dry-run -f table
+  ynabctl transactions allocate <txn-id> --plan paycheck.yaml --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		plan, err := allocplan.Load(allocatePlan)
+		if err != nil {
+			return fmt.Errorf("failed to load allocation plan: %w", err)
+		}
+
+		txnID, err := resolveTransactionID(budgetID, args[0])
+		if err != nil {
+			return err
+		}
+		txn, err := apiClient.GetTransaction(budgetID, txnID)
+		if err != nil {
+			return fmt.Errorf("failed to get transaction: %w", err)
+		}
+		if txn.Amount <= 0 {
+			return fmt.Errorf("transaction %s is not an inflow", txn.ID)
+		}
+		if !strings.HasPrefix(txn.CategoryName, "Inflow:") {
+			fmt.Fprintf(os.Stderr, "Warning: transaction %s is categorized as %q, not Ready to Assign\n", txn.ID, txn.CategoryName)
+		}
+		monthArg := allocateMonth
+		if monthArg == "" {
+			monthArg = txn.Date[:7]
+		}
+		month, err := parseMonthArg(monthArg)
+		if err != nil {
+			return err
+		}
+
+		shares, err := plan.Split(txn.Amount)
+		if err != nil {
+			return err
+		}
+		m, err := apiClient.GetMonth(budgetID, month)
+		if err != nil {
+			return fmt.Errorf("failed to get month: %w", err)
+		}
+		byID := map[string]ynab.Category{}
+		for _, c := range m.Categories {
+			byID[c.ID] = c
+		}
+
+		alloc := &allocplan.Allocation{
+			TransactionID: txn.ID,
+			Date:          txn.Date,
+			Payee:         txn.PayeeName,
+			Month:         m.Month,
+			Income:        txn.Amount,
+			Items:         []allocplan.Item{},
+		}
+		// budgeted is the new budgeted amount of every category, so a
+		// category listed twice gets both shares
+		budgeted := map[string]int64{}
+		var order []string
+		for _, s := range shares {
+			id, err := resolveCategoryID(budgetID, s.Entry.Category)
+			if err != nil {
+				return err
+			}
+			c, ok := byID[id]
+			if !ok {
+				return fmt.Errorf("category %q not found in %s", s.Entry.Category, m.Month[:7])
+			}
+			if _, ok := budgeted[id]; !ok {
+				budgeted[id] = c.Budgeted
+				order = append(order, id)
+			}
+			budgeted[id] += s.Amount
+			alloc.Items = append(alloc.Items, allocplan.Item{
+				CategoryID: id,
+				Name:       c.Name,
+				Kind:       s.Entry.Kind(),
+				Percent:    s.Entry.Percent,
+				Amount:     s.Amount,
+				Budgeted:   budgeted[id],
+			})
+			alloc.Allocated += s.Amount
+		}
+		alloc.LeftOver = alloc.Income - alloc.Allocated
+
+		formatter := output.New(getOutputFormat())
+		if allocateDryRun || alloc.Allocated == 0 {
+			return formatter.Print(alloc)
+		}
+		if err := guardTBB(map[string]int64{m.Month: m.ToBeBudgeted}, map[string]int64{m.Month: alloc.Allocated}); err != nil {
+			return err
+		}
+
+		var changes output.Changes
+		for _, id := range order {
+			c := byID[id]
+			changes.AddAmount(c.Name, c.Budgeted, budgeted[id])
+		}
+		ok, err := confirmChanges(fmt.Sprintf("the budgeted amounts of %s", m.Month[:7]), changes)
+		if err != nil || !ok {
+			return err
+		}
+
+		log := output.NewChangelog(cmd.CommandPath())
+		defer finishChangelog(log)
+		for _, id := range order {
+			c := byID[id]
+			if budgeted[id] == c.Budgeted {
+				continue
+			}
+			_, err := apiClient.UpdateCategory(budgetID, id, m.Month, budgeted[id])
+			log.Record("allocate", id, c.Name, budgeted[id]-c.Budgeted, err)
+			if err != nil {
+				return fmt.Errorf("failed to allocate to %s: %w", c.Name, err)
+			}
+		}
+		return formatter.Print(alloc)
+	},
+}
+
+func init() {
+	transactionsCmd.AddCommand(transactionsAllocateCmd)
+
+	transactionsAllocateCmd.Flags().StringVar(&allocatePlan, "plan", "", "Allocation plan YAML file (required)")
+	transactionsAllocateCmd.Flags().StringVar(&allocateMonth, "month", "", "Budget month ("+period.MonthHelp+"; default: the transaction's month)")
+	transactionsAllocateCmd.Flags().BoolVar(&allocateDryRun, "dry-run", false, "Only show how the inflow would be allocated")
+	addTBBGuardFlag(transactionsAllocateCmd)
+	_ = transactionsAllocateCmd.MarkFlagRequired("plan")
+}
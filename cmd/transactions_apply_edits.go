@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/langtind/ynabctl/internal/editor"
+	"github.com/langtind/ynabctl/internal/export"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var applyEditsDryRun bool
+
+var transactionsApplyEditsCmd = &cobra.Command{
+	Use:   "apply-edits <file.csv>",
+	Short: "Apply a CSV edited in a spreadsheet to the transactions",
+	Long: `Read a CSV written by 'transactions export --editable', compare its
+category, memo and flag columns with the transactions as they are now, and
+update only the transactions that differ, in one request. Rows that were
+not changed, or removed from the file, are left alone, and so are columns
+removed from it.
+
+Categories are given by name as in the export; use "Group: Category"
+when a name exists in several groups. The category of a split
+transaction cannot be changed this way. Flags are red, orange, yellow,
+green, blue, purple or empty.
+
+The changes are shown and must be confirmed unless --yes is given;
+--dry-run only shows them.`,
+	Example: `  ynabctl transactions export --since 2025-01-01 --editable edit.csv
+  ynabctl transactions apply-edits edit.csv --dry-run
+  ynabctl transactions apply-edits edit.csv`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		edits, err := export.ReadEdits(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", args[0], err)
+		}
+
+		transactions, err := cachedTransactions(budgetID)
+		if err != nil {
+			return fmt.Errorf("failed to get transactions: %w", err)
+		}
+		byID := map[string]ynab.Transaction{}
+		for _, t := range transactions {
+			byID[t.ID] = t
+		}
+
+		resolver := editor.Resolver{
+			Category: func(name string) (string, error) { return resolveCategoryID(budgetID, name) },
+		}
+		var patches []ynab.PatchTransaction
+		var changes output.Changes
+		labels := map[string]string{}
+		for _, e := range edits {
+			t, ok := byID[e.ID]
+			if !ok || t.Deleted {
+				return fmt.Errorf("line %d: no transaction %s", e.Line, e.ID)
+			}
+			before := editor.FromTransaction(t)
+			after := before
+			if v, ok := e.Fields["category"]; ok {
+				after.Category = v
+			}
+			if v, ok := e.Fields["memo"]; ok {
+				after.Memo = v
+			}
+			if v, ok := e.Fields["flag"]; ok {
+				after.Flag = strings.ToLower(v)
+				if !editor.ValidFlag(after.Flag) {
+					return fmt.Errorf("line %d: invalid flag %q", e.Line, v)
+				}
+			}
+			if after.Category != before.Category && len(t.Subtransactions) > 0 {
+				return fmt.Errorf("line %d: %s is a split transaction; change its categories in YNAB", e.Line, t.ID)
+			}
+
+			patch, c, err := editor.Patch(before, after, resolver)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", e.Line, err)
+			}
+			if len(c) == 0 {
+				continue
+			}
+			label := t.Date + " " + t.PayeeName
+			labels[t.ID] = label
+			for _, ch := range c {
+				ch.Field = label + ": " + ch.Field
+				changes = append(changes, ch)
+			}
+			patches = append(patches, patch)
+		}
+
+		if applyEditsDryRun {
+			if len(patches) == 0 {
+				infof("No changes in %s.\n", args[0])
+				return nil
+			}
+			output.PrintDiff(os.Stdout, changes, output.UseColor(os.Stdout))
+			infof("%d of %d transactions would be updated\n", len(patches), len(edits))
+			return nil
+		}
+		ok, err := confirmChanges(fmt.Sprintf("%d transactions", len(patches)), changes)
+		if err != nil || !ok {
+			return err
+		}
+
+		log := output.NewChangelog(cmd.CommandPath())
+		defer finishChangelog(log)
+		updated, _, err := apiClient.PatchTransactions(budgetID, patches)
+		for _, p := range patches {
+			log.Record("edit", p.ID, labels[p.ID], 0, err)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update transactions: %w", err)
+		}
+		infof("updated %d transactions\n", len(updated))
+
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(updated)
+	},
+}
+
+func init() {
+	transactionsCmd.AddCommand(transactionsApplyEditsCmd)
+
+	transactionsApplyEditsCmd.Flags().BoolVar(&applyEditsDryRun, "dry-run", false, "Only show what would change")
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/internal/trust"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	autoApproveTrustFile string
+	autoApproveMaxAmount float64
+	autoApproveDryRun    bool
+)
+
+var transactionsAutoApproveCmd = &cobra.Command{
+	Use:   "autoapprove",
+	Short: "Approve imported transactions from trusted payees",
+	Long: `Approve imported, unapproved transactions whose payee is on a trust list
+and whose amount is within the allowed limits. Everything else is left for
+manual review.
+
+The trust list has one payee per line. Blank lines and lines starting with
+# are ignored. A payee may carry its own limit after a "|":
+
+  Netflix
+  Spotify | 15
+  REMA 1000 | 800
+
+Each decision is logged to stderr. The approved transactions are printed
+to stdout.`,
+	Example: `  ynabctl transactions autoapprove --trusted-payees payees.txt
+  ynabctl transactions autoapprove --trusted-payees payees.txt --max-amount 100 --dry-run`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+
+		trusted, err := trust.Load(autoApproveTrustFile)
+		if err != nil {
+			return fmt.Errorf("failed to load trusted payees: %w", err)
+		}
+
+		spinner := progress.Start("fetching unapproved transactions")
+		transactions, err := apiClient.GetTransactions(budgetID, &ynab.TransactionFilter{Type: "unapproved"})
+		spinner.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to get transactions: %w", err)
+		}
+
+		maxAmount := ynab.AmountToMilliunits(autoApproveMaxAmount)
+		var ids []string
+		approved := []ynab.Transaction{}
+		for _, t := range transactions {
+			if t.Deleted || t.Approved || t.ImportID == "" {
+				continue
+			}
+			ok, reason := trusted.Check(t.PayeeName, t.Amount, maxAmount)
+			if !ok {
+				infof("skip     %s  %-30s %10.2f  (%s)\n",
+					t.Date, t.PayeeName, ynab.MilliunitsToAmount(t.Amount), reason)
+				continue
+			}
+			infof("approve  %s  %-30s %10.2f\n",
+				t.Date, t.PayeeName, ynab.MilliunitsToAmount(t.Amount))
+			ids = append(ids, t.ID)
+			approved = append(approved, t)
+		}
+
+		if len(ids) > 0 && !autoApproveDryRun {
+			log := output.NewChangelog(cmd.CommandPath())
+			defer finishChangelog(log)
+			candidates := approved
+			approved, err = apiClient.ApproveTransactions(budgetID, ids)
+			for _, t := range candidates {
+				log.Record("approve", t.ID, t.Date+" "+t.PayeeName, t.Amount, err)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to approve transactions: %w", err)
+			}
+		}
+
+		verb := "approved"
+		if autoApproveDryRun {
+			verb = "would approve"
+		}
+		infof("%s %d of %d unapproved transactions\n", verb, len(ids), len(transactions))
+
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(approved)
+	},
+}
+
+func init() {
+	transactionsCmd.AddCommand(transactionsAutoApproveCmd)
+
+	transactionsAutoApproveCmd.Flags().StringVar(&autoApproveTrustFile, "trusted-payees", "", "File listing trusted payees (required)")
+	transactionsAutoApproveCmd.Flags().Float64Var(&autoApproveMaxAmount, "max-amount", 0, "Never approve transactions larger than this amount")
+	transactionsAutoApproveCmd.Flags().BoolVar(&autoApproveDryRun, "dry-run", false, "Log decisions without approving anything")
+	_ = transactionsAutoApproveCmd.MarkFlagRequired("trusted-payees")
+}
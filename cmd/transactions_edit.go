@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/langtind/ynabctl/internal/editor"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var transactionsEditCmd = &cobra.Command{
+	Use:   "edit <transaction-id>",
+	Short: "Edit a transaction in $EDITOR",
+	Long: `Open a transaction as JSON in $VISUAL or $EDITOR (default vi). After
+saving, the document is validated, the changed fields are shown and, once
+confirmed, only those fields are sent to YNAB.
+
+Amounts are in currency units, and account and category are given by
+name (or ID). If the document does not validate you can go back to the
+editor to fix it. Subtransactions of a split cannot be edited this way.
+
+As with update, the save is refused if the transaction was modified on
+the server while it was being edited, or if it is dated before the last
+reconciliation of its account and --allow-reconciled is not given.`,
+	Example: `  ynabctl transactions edit <transaction-id>
+  EDITOR="code --wait" ynabctl transactions edit <transaction-id>`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		if args[0], err = resolveTransactionID(budgetID, args[0]); err != nil {
+			return err
+		}
+		if !output.IsTerminal(os.Stdin) {
+			return fmt.Errorf("transactions edit needs a terminal; use transactions update instead")
+		}
+
+		existing, knowledge, err := apiClient.GetTransactionWithKnowledge(budgetID, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get transaction: %w", err)
+		}
+
+		before := editor.FromTransaction(*existing)
+		content, err := before.Marshal()
+		if err != nil {
+			return err
+		}
+
+		var after editor.Transaction
+		for {
+			edited, err := editor.Edit(content, "ynabctl-transaction-*.json")
+			if err != nil {
+				return err
+			}
+			after, err = editor.ParseTransaction(edited)
+			if err == nil {
+				break
+			}
+
+			// --yes would make this loop forever, so give up instead
+			if assumeYes {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			again, cerr := confirm("Edit again?")
+			if cerr != nil || !again {
+				return err
+			}
+			content = edited
+		}
+
+		patch, changes, err := editor.Patch(before, after, editor.Resolver{
+			Account:  func(name string) (string, error) { return resolveAccountID(budgetID, name) },
+			Category: func(name string) (string, error) { return resolveCategoryID(budgetID, name) },
+		})
+		if err != nil {
+			return err
+		}
+
+		accountID, date := existing.AccountID, existing.Date
+		if patch.AccountID != nil {
+			accountID = *patch.AccountID
+		}
+		if patch.Date != nil {
+			date = *patch.Date
+		}
+		if err := guardReconciled(budgetID, existing, accountID, date); err != nil {
+			return err
+		}
+
+		ok, err := confirmChanges("transaction "+args[0], changes)
+		if err != nil || !ok {
+			return err
+		}
+
+		transaction, err := patchTransactionIfUnmodified(budgetID, patch, knowledge)
+		if err != nil {
+			return err
+		}
+
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(transaction)
+	},
+}
+
+func init() {
+	transactionsCmd.AddCommand(transactionsEditCmd)
+
+	addAllowReconciledFlag(transactionsEditCmd)
+}
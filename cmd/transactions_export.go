@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"github.com/langtind/ynabctl/internal/export"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat       string
+	exportDelimiter    string
+	exportEncoding     string
+	exportDecimalComma bool
+	exportSince        string
+	exportUntil        string
+	exportAccountID    string
+	exportOut          string
+	exportEditable     string
+)
+
+var transactionsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export transactions to CSV or JSON",
+	Long: `Export transactions for spreadsheets and other tools.
+
+CSV output can be adapted to European conventions:
+  --delimiter: Field separator (e.g. ';' or 'tab')
+  --decimal-comma: Write amounts as 1234,56
+  --encoding: utf-8 (default), iso-8859-1, or windows-1252
+
+Writes to stdout unless --out is given.
+
+--editable <file> instead writes a CSV meant to be changed in a
+spreadsheet and applied back with 'transactions apply-edits': the
+category, memo and flag columns can be edited, the other columns only
+identify the transactions. It is always UTF-8.`,
+	Example: `  ynabctl transactions export --since 2025-01-01 --out 2025.csv
+  ynabctl transactions export --delimiter ';' --decimal-comma --encoding iso-8859-1
+  ynabctl transactions export --format json --account <account-id>
+  ynabctl transactions export --since 2025-01-01 --editable edit.csv`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		if exportEditable != "" && (exportOut != "" || exportFormat != "csv") {
+			return fmt.Errorf("--editable writes its own CSV file; it cannot be combined with --out or --format")
+		}
+
+		delimiter, err := parseDelimiter(exportDelimiter)
+		if err != nil {
+			return err
+		}
+
+		if exportAccountID, err = resolveAccountID(budgetID, exportAccountID); err != nil {
+			return err
+		}
+		spinner := progress.Start("fetching transactions")
+		var transactions []ynab.Transaction
+		if exportAccountID != "" {
+			transactions, err = apiClient.GetTransactionsByAccount(budgetID, exportAccountID, exportSince)
+		} else {
+			transactions, err = apiClient.GetTransactions(budgetID, &ynab.TransactionFilter{SinceDate: exportSince})
+		}
+		spinner.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to get transactions: %w", err)
+		}
+		if exportUntil != "" {
+			filtered := transactions[:0]
+			for _, t := range transactions {
+				if t.Date <= exportUntil {
+					filtered = append(filtered, t)
+				}
+			}
+			transactions = filtered
+		}
+
+		if exportEditable != "" {
+			f, err := os.Create(exportEditable)
+			if err != nil {
+				return fmt.Errorf("create %s: %w", exportEditable, err)
+			}
+			defer f.Close()
+			err = export.WriteEditable(f, transactions, export.CSVOptions{
+				Delimiter:    delimiter,
+				DecimalComma: exportDecimalComma,
+			})
+			if err != nil {
+				return err
+			}
+			infof("exported %d transactions to %s; edit it and run 'ynabctl transactions apply-edits %s'\n",
+				len(transactions), exportEditable, exportEditable)
+			return nil
+		}
+
+		var w io.Writer = os.Stdout
+		if exportOut != "" {
+			f, err := os.Create(exportOut)
+			if err != nil {
+				return fmt.Errorf("create %s: %w", exportOut, err)
+			}
+			defer f.Close()
+			w = f
+		}
+
+		switch exportFormat {
+		case "csv":
+			err = export.WriteCSV(w, transactions, export.CSVOptions{
+				Delimiter:    delimiter,
+				DecimalComma: exportDecimalComma,
+				Encoding:     exportEncoding,
+			})
+		case "json":
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			err = enc.Encode(transactions)
+		default:
+			return fmt.Errorf("invalid export format: %s (must be 'csv' or 'json')", exportFormat)
+		}
+		if err != nil {
+			return err
+		}
+
+		if exportOut != "" {
+			infof("exported %d transactions to %s\n", len(transactions), exportOut)
+		}
+		return nil
+	},
+}
+
+// parseDelimiter accepts a single character or the name "tab"
+func parseDelimiter(s string) (rune, error) {
+	switch s {
+	case "tab", `\t`:
+		return '\t', nil
+	}
+	if utf8.RuneCountInString(s) != 1 {
+		return 0, fmt.Errorf("delimiter must be a single character, got %q", s)
+	}
+	r, _ := utf8.DecodeRuneInString(s)
+	return r, nil
+}
+
+func init() {
+	transactionsCmd.AddCommand(transactionsExportCmd)
+
+	transactionsExportCmd.Flags().StringVarP(&exportFormat, "format", "f", "csv", "Export format (csv, json)")
+	transactionsExportCmd.Flags().StringVar(&exportDelimiter, "delimiter", ",", "CSV field delimiter (single character or 'tab')")
+	transactionsExportCmd.Flags().StringVar(&exportEncoding, "encoding", "utf-8", "CSV encoding (utf-8, iso-8859-1, windows-1252)")
+	transactionsExportCmd.Flags().BoolVar(&exportDecimalComma, "decimal-comma", false, "Use a comma as decimal separator in amounts")
+	transactionsExportCmd.Flags().StringVar(&exportSince, "since", "", "Export transactions since date (YYYY-MM-DD)")
+	transactionsExportCmd.Flags().StringVar(&exportUntil, "until", "", "Export transactions up to and including date (YYYY-MM-DD)")
+	transactionsExportCmd.Flags().StringVar(&exportAccountID, "account", "", "Only export transactions for this account (ID or name)")
+	transactionsExportCmd.Flags().StringVar(&exportOut, "out", "", "Write to this file instead of stdout")
+	transactionsExportCmd.Flags().StringVar(&exportEditable, "editable", "", "Write a CSV for 'transactions apply-edits' to this file")
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fxFrom       string
+	fxTo         string
+	fxFromAmount float64
+	fxToAmount   float64
+	fxDate       string
+	fxPayeeName  string
+	fxCategoryID string
+	fxMemo       string
+)
+
+var transactionsFxCmd = &cobra.Command{
+	Use:   "fx",
+	Short: "Book a cross-currency transfer between two accounts",
+	Long: `Book both sides of a currency exchange between accounts held in
+different currencies.
+
+YNAB transfers always move the same amount, so an exchange is booked as
+two linked transactions instead: an outflow of --from-amount on the
+--from account and an inflow of --to-amount on the --to account. Both
+get a memo with the implied exchange rate and share an import ID
+(YNABCTL:FX:...) that links them.
+
+Amounts are in each account's own units; the sign is implied. Use
+--category to put both sides in one category (e.g. "Currency exchange")
+so they net out in the budget.`,
+	Example: `  ynabctl transactions fx --from "USD Account" --to "NOK Account" --from-amount -100 --to-amount 1085
+  ynabctl transactions fx --from Wise --to Checking --from-amount 250 --to-amount 2712.50 --category "Currency exchange"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+
+		if fxFrom == "" || fxTo == "" {
+			return fmt.Errorf("both --from and --to are required")
+		}
+		if fxFromAmount == 0 || fxToAmount == 0 {
+			return fmt.Errorf("both --from-amount and --to-amount are required")
+		}
+
+		from, err := resolveAccount(budgetID, fxFrom)
+		if err != nil {
+			return err
+		}
+		to, err := resolveAccount(budgetID, fxTo)
+		if err != nil {
+			return err
+		}
+		if from.ID == to.ID {
+			return fmt.Errorf("--from and --to are the same account")
+		}
+
+		categoryID, err := resolveCategoryID(budgetID, fxCategoryID)
+		if err != nil {
+			return err
+		}
+
+		date := fxDate
+		if date == "" {
+			date = time.Now().Format("2006-01-02")
+		}
+
+		out := math.Abs(fxFromAmount)
+		in := math.Abs(fxToAmount)
+		memo := fmt.Sprintf("FX %.2f %s -> %.2f %s @ %.4f", out, from.Name, in, to.Name, in/out)
+		if fxMemo != "" {
+			memo = fxMemo + " | " + memo
+		}
+
+		link, err := fxLinkID()
+		if err != nil {
+			return err
+		}
+
+		txns := []ynab.SaveTransaction{
+			{
+				AccountID:  from.ID,
+				Date:       date,
+				Amount:     -ynab.AmountToMilliunits(out),
+				PayeeName:  fxPayeeName,
+				CategoryID: categoryID,
+				Memo:       memo,
+				Approved:   true,
+				ImportID:   link,
+			},
+			{
+				AccountID:  to.ID,
+				Date:       date,
+				Amount:     ynab.AmountToMilliunits(in),
+				PayeeName:  fxPayeeName,
+				CategoryID: categoryID,
+				Memo:       memo,
+				Approved:   true,
+				ImportID:   link,
+			},
+		}
+
+		result, err := apiClient.CreateTransactions(budgetID, txns)
+		if err != nil {
+			return fmt.Errorf("failed to create exchange transactions: %w", err)
+		}
+
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(result.Transactions)
+	},
+}
+
+// fxLinkID returns a random import ID shared by both sides of an exchange
+func fxLinkID() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate link ID: %w", err)
+	}
+	return "YNABCTL:FX:" + hex.EncodeToString(b), nil
+}
+
+func init() {
+	transactionsCmd.AddCommand(transactionsFxCmd)
+	transactionsFxCmd.Flags().StringVar(&fxFrom, "from", "", "Account the money leaves (name or ID)")
+	transactionsFxCmd.Flags().StringVar(&fxTo, "to", "", "Account the exchanged money arrives in (name or ID)")
+	transactionsFxCmd.Flags().Float64Var(&fxFromAmount, "from-amount", 0, "Amount leaving --from, in its currency")
+	transactionsFxCmd.Flags().Float64Var(&fxToAmount, "to-amount", 0, "Amount arriving in --to, in its currency")
+	transactionsFxCmd.Flags().StringVar(&fxDate, "date", "", "Exchange date (YYYY-MM-DD, default: today)")
+	transactionsFxCmd.Flags().StringVar(&fxPayeeName, "payee-name", "Currency exchange", "Payee for both transactions")
+	transactionsFxCmd.Flags().StringVar(&fxCategoryID, "category", "", "Category ID or name for both transactions")
+	transactionsFxCmd.Flags().StringVar(&fxMemo, "memo", "", "Extra memo text placed before the rate")
+}
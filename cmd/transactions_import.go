@@ -0,0 +1,289 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/langtind/ynabctl/internal/export"
+	"github.com/langtind/ynabctl/internal/i18n"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+// importBatchSize is the number of transactions created per request
+const importBatchSize = 1000
+
+var (
+	importFile         string
+	importAccount      string
+	importColumns      []string
+	importDateFormat   string
+	importDecimalComma bool
+	importDelimiter    string
+	importEncoding     string
+	importCleared      string
+	importApproved     bool
+	importDryRun       bool
+)
+
+var transactionsImportCmd = &cobra.Command{
+	Use:   "import --file <transactions.csv>",
+	Short: "Create many transactions from a CSV in one request",
+	Long: `Create the transactions of a CSV file, e.g. a bank export, with YNAB's
+bulk endpoint instead of one request per transaction.
+
+The file needs a header line. Columns are found by name: date, amount
+(or outflow and inflow), payee, memo, category and account, ignoring
+case, so a file written by 'transactions export' imports as it is. Map
+other headers with --column field=Header:
+
+  --column date=Dato,payee=Tekst,outflow=Ut,inflow=Inn
+
+Categories and accounts are given by ID, name or @alias. Rows without an
+account column go to --account. Dates are read with --date-format, a Go
+layout or a pattern like DD.MM.YYYY.
+
+Every transaction gets an import_id derived from its content, so
+importing the same file twice creates nothing the second time. Before
+anything is created, each row is classified as new, duplicate (its
+import_id is already in the budget) or error (an invalid date or amount,
+or an unknown account or category). --dry-run prints this preview and
+stops. Otherwise the preview is shown as a table on stderr, the number
+and total of the new rows must be confirmed unless --yes is given, and
+rows with errors are left out.
+
+Afterwards the file total is reconciled with what was created, what
+already existed and what was skipped. The exit code is 2 when rows were
+skipped or the totals do not add up.`,
+	Example: `  ynabctl transactions import --file march.csv --account Checking --dry-run -f table
+  ynabctl transactions import --file bank.csv --account @visa --delimiter ';' --decimal-comma \
+    --date-format DD.MM.YYYY --column date=Dato,payee=Tekst,outflow=Ut,inflow=Inn --encoding iso-8859-1`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		columns, err := export.ParseColumns(importColumns)
+		if err != nil {
+			return err
+		}
+		opts := export.ImportOptions{
+			Columns:      columns,
+			DateFormat:   importDateFormat,
+			DecimalComma: importDecimalComma,
+			Encoding:     importEncoding,
+		}
+		if importDelimiter != "" {
+			if opts.Delimiter, err = parseDelimiter(importDelimiter); err != nil {
+				return err
+			}
+		}
+
+		f, err := os.Open(importFile)
+		if err != nil {
+			return err
+		}
+		rows, err := export.ReadImport(f, opts)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", importFile, err)
+		}
+		if len(rows) == 0 {
+			infof("No transactions in %s.\n", importFile)
+			return nil
+		}
+
+		accounts, err := apiClient.GetAccounts(budgetID)
+		if err != nil {
+			return fmt.Errorf("failed to get accounts: %w", err)
+		}
+		accountNames := map[string]string{}
+		for _, a := range accounts {
+			accountNames[a.ID] = a.Name
+		}
+		defaultAccount := ""
+		if importAccount != "" {
+			if defaultAccount, err = resolveAccountID(budgetID, importAccount); err != nil {
+				return err
+			}
+		}
+
+		// names are resolved once each; files repeat them on every row
+		accountIDs := map[string]resolved{}
+		categoryIDs := map[string]resolved{}
+		resolve := func(cache map[string]resolved, name string, lookup func(budgetID, value string) (string, error)) (string, error) {
+			r, ok := cache[name]
+			if !ok {
+				r.id, r.err = lookup(budgetID, name)
+				cache[name] = r
+			}
+			return r.id, r.err
+		}
+
+		preview := &export.Preview{File: importFile, Rows: make([]export.PreviewRow, len(rows))}
+		txns := make([]ynab.SaveTransaction, len(rows))
+		occurrences := map[string]int{}
+		since := ""
+		for i, row := range rows {
+			p := &preview.Rows[i]
+			*p = export.PreviewRow{
+				Line:     row.Line,
+				Status:   export.StatusNew,
+				Date:     row.Date,
+				Account:  row.Account,
+				Payee:    row.Payee,
+				Category: row.Category,
+				Memo:     row.Memo,
+				Amount:   row.Amount,
+			}
+			accountID, categoryID, err := defaultAccount, "", row.Err
+			if err == nil && row.Account != "" {
+				accountID, err = resolve(accountIDs, row.Account, resolveAccountID)
+			}
+			if err == nil && accountID == "" {
+				err = fmt.Errorf("no account; give one with --account or an account column")
+			}
+			if err == nil && row.Category != "" {
+				categoryID, err = resolve(categoryIDs, row.Category, resolveCategoryID)
+			}
+			if err != nil {
+				p.Status = export.StatusError
+				p.Error = err.Error()
+				continue
+			}
+			p.Account = accountNames[accountID]
+
+			key := fmt.Sprintf("%s|%s|%d|%s|%s", accountID, row.Date, row.Amount, row.Payee, row.Memo)
+			p.ImportID = export.ImportID(accountID, row, occurrences[key])
+			occurrences[key]++
+			if since == "" || row.Date < since {
+				since = row.Date
+			}
+
+			txns[i] = ynab.SaveTransaction{
+				AccountID:  accountID,
+				Date:       row.Date,
+				Amount:     row.Amount,
+				PayeeName:  row.Payee,
+				CategoryID: categoryID,
+				Memo:       row.Memo,
+				Cleared:    importCleared,
+				Approved:   importApproved,
+				ImportID:   p.ImportID,
+			}
+		}
+
+		// rows imported before are skipped, as YNAB would
+		if since != "" {
+			spinner := progress.Start("checking for earlier imports")
+			existing, err := apiClient.GetTransactions(budgetID, &ynab.TransactionFilter{SinceDate: since})
+			spinner.Stop()
+			if err != nil {
+				return fmt.Errorf("failed to get transactions: %w", err)
+			}
+			imported := map[string]bool{}
+			for _, t := range existing {
+				if t.ImportID != "" {
+					imported[t.ImportID] = true
+				}
+			}
+			for i := range preview.Rows {
+				if p := &preview.Rows[i]; p.Status == export.StatusNew && imported[p.ImportID] {
+					p.Status = export.StatusDuplicate
+				}
+			}
+		}
+
+		var create []ynab.SaveTransaction
+		for i, p := range preview.Rows {
+			if p.Status == export.StatusNew {
+				create = append(create, txns[i])
+			}
+		}
+
+		formatter := output.New(getOutputFormat())
+		newRows, newTotal := preview.Count(export.StatusNew)
+		duplicates, _ := preview.Count(export.StatusDuplicate)
+		errorRows, _ := preview.Count(export.StatusError)
+		if importDryRun {
+			infof("would import %d transactions (total %.2f); %d already imported, %d with errors\n",
+				newRows, ynab.MilliunitsToAmount(newTotal), duplicates, errorRows)
+			return formatter.Print(preview)
+		}
+
+		if err := output.NewPreview(os.Stderr).Print(preview); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Importing %d transactions from %s, total %.2f; %d already imported, %d with errors.\n",
+			newRows, importFile, ynab.MilliunitsToAmount(newTotal), duplicates, errorRows)
+		created := []ynab.Transaction{}
+		var duplicateIDs []string
+		if len(create) > 0 {
+			ok, err := confirmOrAbort(i18n.T("Create these transactions?"))
+			if err != nil || !ok {
+				return err
+			}
+
+			log := output.NewChangelog(cmd.CommandPath())
+			defer finishChangelog(log)
+			bar := progress.NewBar("creating transactions", len(create))
+			for start := 0; start < len(create); start += importBatchSize {
+				batch := create[start:min(start+importBatchSize, len(create))]
+				result, err := apiClient.CreateTransactions(budgetID, batch)
+				var amount int64
+				for _, t := range batch {
+					amount += t.Amount
+				}
+				log.Record("import", "", fmt.Sprintf("%d transactions from %s", len(batch), importFile), amount, err)
+				if err != nil {
+					bar.Done()
+					return fmt.Errorf("failed to create transactions: %w", err)
+				}
+				created = append(created, result.Transactions...)
+				duplicateIDs = append(duplicateIDs, result.DuplicateImportIDs...)
+				bar.Add(len(batch))
+			}
+			bar.Done()
+		}
+
+		rec := export.Reconcile(preview, created, duplicateIDs)
+		infof("imported %d transactions (%d already existed, %d skipped)\n", rec.Created, rec.Duplicates, rec.Skipped)
+		if err := formatter.Print(rec); err != nil {
+			return err
+		}
+		if !rec.Balanced() {
+			return &exitError{code: 2, err: fmt.Errorf("the file total %.2f does not match the import: %.2f created, %.2f already existed, %.2f skipped",
+				ynab.MilliunitsToAmount(rec.FileTotal), ynab.MilliunitsToAmount(rec.CreatedTotal),
+				ynab.MilliunitsToAmount(rec.DuplicateTotal), ynab.MilliunitsToAmount(rec.SkippedTotal))}
+		}
+		if rec.Skipped > 0 {
+			return &exitError{code: 2, err: fmt.Errorf("%d rows of %s were not imported", rec.Skipped, importFile)}
+		}
+		return nil
+	},
+}
+
+// resolved is the ID a name resolved to, or why it did not
+type resolved struct {
+	id  string
+	err error
+}
+
+func init() {
+	transactionsCmd.AddCommand(transactionsImportCmd)
+
+	transactionsImportCmd.Flags().StringVar(&importFile, "file", "", "CSV file to import (required)")
+	transactionsImportCmd.Flags().StringVar(&importAccount, "account", "", "Account of rows without an account column (ID, name or @alias)")
+	transactionsImportCmd.Flags().StringSliceVar(&importColumns, "column", nil, "Map a field to a CSV header, e.g. date=Dato (repeatable)")
+	transactionsImportCmd.Flags().StringVar(&importDateFormat, "date-format", "YYYY-MM-DD", "Date format, e.g. DD.MM.YYYY or a Go layout")
+	transactionsImportCmd.Flags().BoolVar(&importDecimalComma, "decimal-comma", false, "Read amounts as 1.234,56")
+	transactionsImportCmd.Flags().StringVar(&importDelimiter, "delimiter", "", "CSV field delimiter (single character or 'tab'; default: detected)")
+	transactionsImportCmd.Flags().StringVar(&importEncoding, "encoding", "utf-8", "CSV encoding (utf-8, iso-8859-1, windows-1252)")
+	transactionsImportCmd.Flags().StringVar(&importCleared, "cleared", "cleared", "Cleared status of the new transactions (cleared, uncleared)")
+	transactionsImportCmd.Flags().BoolVar(&importApproved, "approved", false, "Mark the new transactions approved")
+	transactionsImportCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Only show the transactions that would be created")
+	_ = transactionsImportCmd.MarkFlagRequired("file")
+}
@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/matching"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	matchAccount   string
+	matchSinceDate string
+	matchDays      int
+	matchTolerance float64
+	matchAction    string
+	matchDryRun    bool
+)
+
+var transactionsMatchCmd = &cobra.Command{
+	Use:   "match",
+	Short: "Pair imported transactions with manually entered ones",
+	Long: `Find unapproved imported transactions that probably duplicate a
+transaction entered by hand: same account, same direction, an amount
+within --tolerance and a date within --days. Each transaction is paired
+at most once, closest amount first.
+
+On a terminal every pair is shown with a choice of:
+
+  m  match    keep the manual transaction, mark it cleared and approved
+              (taking the bank's amount if it differs) and delete the import
+  a  approve  keep the imported transaction, approve it (taking category
+              and memo from the manual one where it has none) and delete
+              the manual one
+  u  unmatch  they are different payments: approve the import, keep both,
+              and never offer this pair again
+  s  skip     decide later
+  q  quit
+
+The YNAB API cannot link two transactions the way the app's matching
+does, so match and approve merge the pair by deleting one side. Without
+a terminal, or to apply the same choice to every pair, use --action
+(confirmed unless --yes). --dry-run only lists the pairs.`,
+	Example: `  ynabctl transactions match
+  ynabctl transactions match --account Checking --days 5 --tolerance 1
+  ynabctl transactions match --dry-run -f table
+  ynabctl transactions match --action match --yes`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		switch matchAction {
+		case "", "match", "approve", "unmatch":
+		default:
+			return fmt.Errorf("invalid --action %q: use match, approve or unmatch", matchAction)
+		}
+		if matchDays < 0 || matchTolerance < 0 {
+			return fmt.Errorf("--days and --tolerance must not be negative")
+		}
+
+		filter := &ynab.TransactionFilter{SinceDate: matchSinceDate}
+		if filter.SinceDate == "" {
+			filter.SinceDate = time.Now().AddDate(0, 0, -30).Format("2006-01-02")
+		}
+		if matchAccount != "" {
+			filter.AccountID, err = resolveAccountID(budgetID, matchAccount)
+			if err != nil {
+				return err
+			}
+		}
+
+		spinner := progress.Start("fetching transactions")
+		transactions, err := apiClient.GetTransactions(budgetID, filter)
+		spinner.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to get transactions: %w", err)
+		}
+
+		unmatched, err := matching.LoadUnmatched(matching.UnmatchedPath(budgetID))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring unmatched pairs: %v\n", err)
+		}
+		pairs := matching.FindPairs(transactions, matching.Options{
+			MaxDays:   matchDays,
+			Tolerance: ynab.AmountToMilliunits(matchTolerance),
+			Unmatched: unmatched,
+		})
+
+		formatter := output.New(getOutputFormat())
+		if len(pairs) == 0 {
+			infof("No imported transactions to match.\n")
+			return formatter.Print(pairs)
+		}
+		interactive := matchAction == "" && output.IsTerminal(os.Stdin)
+		if matchDryRun || (matchAction == "" && !interactive) {
+			infof("%d possible matches\n", len(pairs))
+			return formatter.Print(pairs)
+		}
+
+		log := output.NewChangelog(cmd.CommandPath())
+		defer finishChangelog(log)
+
+		if !interactive {
+			for _, p := range pairs {
+				printPair(p)
+			}
+			ok, err := confirmOrAbort(fmt.Sprintf("Apply %q to these %d pairs?", matchAction, len(pairs)))
+			if err != nil || !ok {
+				return err
+			}
+			for _, p := range pairs {
+				if err := applyMatchAction(budgetID, matchAction, p, unmatched, log); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		in := bufio.NewReader(os.Stdin)
+		for n, p := range pairs {
+			fmt.Fprintf(os.Stderr, "\nPair %d of %d:\n", n+1, len(pairs))
+			printPair(p)
+			action, err := promptMatchAction(in)
+			if err != nil {
+				return err
+			}
+			switch action {
+			case "quit":
+				return nil
+			case "skip":
+				continue
+			}
+			if err := applyMatchAction(budgetID, action, p, unmatched, log); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// printPair shows both sides of a pair on stderr
+func printPair(p matching.Pair) {
+	for _, side := range []struct {
+		label string
+		t     ynab.Transaction
+	}{{"imported", p.Imported}, {"manual", p.Manual}} {
+		fmt.Fprintf(os.Stderr, "  %-8s  %s  %-30s %10.2f  %s\n", side.label, side.t.Date,
+			side.t.PayeeName, ynab.MilliunitsToAmount(side.t.Amount), side.t.CategoryName)
+	}
+}
+
+// promptMatchAction asks what to do with a pair until it gets a valid
+// answer. End of input counts as quit.
+func promptMatchAction(in *bufio.Reader) (string, error) {
+	for {
+		fmt.Fprint(os.Stderr, "[m]atch, [a]pprove import, [u]nmatch, [s]kip, [q]uit? ")
+		answer, err := in.ReadString('\n')
+		if err != nil && answer == "" {
+			return "quit", nil
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "m", "match":
+			return "match", nil
+		case "a", "approve":
+			return "approve", nil
+		case "u", "unmatch":
+			return "unmatch", nil
+		case "s", "skip", "":
+			return "skip", nil
+		case "q", "quit":
+			return "quit", nil
+		}
+	}
+}
+
+// applyMatchAction carries out a match, approve or unmatch of one pair
+func applyMatchAction(budgetID, action string, p matching.Pair, unmatched *matching.Unmatched, log *output.Changelog) error {
+	approved := true
+	imported, manual := p.Imported, p.Manual
+
+	switch action {
+	case "match":
+		patch := ynab.PatchTransaction{ID: manual.ID, Approved: &approved}
+		if manual.Cleared == "uncleared" {
+			cleared := "cleared"
+			patch.Cleared = &cleared
+		}
+		if manual.Amount != imported.Amount {
+			patch.Amount = &imported.Amount
+		}
+		if err := patchAndDelete(budgetID, patch, manual, imported, log); err != nil {
+			return err
+		}
+
+	case "approve":
+		patch := ynab.PatchTransaction{ID: imported.ID, Approved: &approved}
+		if imported.CategoryID == "" && manual.CategoryID != "" {
+			patch.CategoryID = &manual.CategoryID
+		}
+		if imported.Memo == "" && manual.Memo != "" {
+			patch.Memo = &manual.Memo
+		}
+		if err := patchAndDelete(budgetID, patch, imported, manual, log); err != nil {
+			return err
+		}
+
+	case "unmatch":
+		_, err := apiClient.ApproveTransactions(budgetID, []string{imported.ID})
+		log.Record("approve", imported.ID, imported.Date+" "+imported.PayeeName, imported.Amount, err)
+		if err != nil {
+			return fmt.Errorf("failed to approve transaction %s: %w", imported.ID, err)
+		}
+		if err := unmatched.Add(imported.ID, manual.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to remember unmatched pair: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// patchAndDelete updates the transaction that is kept and then deletes
+// its duplicate. The duplicate is left alone if the update fails.
+func patchAndDelete(budgetID string, patch ynab.PatchTransaction, keep, drop ynab.Transaction, log *output.Changelog) error {
+	_, _, err := apiClient.PatchTransactions(budgetID, []ynab.PatchTransaction{patch})
+	log.Record("update", keep.ID, keep.Date+" "+keep.PayeeName, keep.Amount, err)
+	if err != nil {
+		return fmt.Errorf("failed to update transaction %s: %w", keep.ID, err)
+	}
+	_, err = apiClient.DeleteTransaction(budgetID, drop.ID)
+	log.Record("delete", drop.ID, drop.Date+" "+drop.PayeeName, drop.Amount, err)
+	if err != nil {
+		return fmt.Errorf("failed to delete transaction %s: %w", drop.ID, err)
+	}
+	return nil
+}
+
+func init() {
+	transactionsCmd.AddCommand(transactionsMatchCmd)
+
+	transactionsMatchCmd.Flags().StringVar(&matchAccount, "account", "", "Only match transactions in this account (ID or name)")
+	transactionsMatchCmd.Flags().StringVar(&matchSinceDate, "since", "", "Only consider transactions since date (YYYY-MM-DD, default 30 days ago)")
+	transactionsMatchCmd.Flags().IntVar(&matchDays, "days", 10, "Largest date difference between a pair, in days")
+	transactionsMatchCmd.Flags().Float64Var(&matchTolerance, "tolerance", 0, "Largest amount difference between a pair, in currency units")
+	transactionsMatchCmd.Flags().StringVar(&matchAction, "action", "", "Apply this to every pair without asking: match, approve or unmatch")
+	transactionsMatchCmd.Flags().BoolVar(&matchDryRun, "dry-run", false, "List the pairs without changing anything")
+}
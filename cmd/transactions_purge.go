@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/langtind/ynabctl/internal/i18n"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	purgeImportPrefix string
+	purgeSinceDate    string
+	purgeAccount      string
+	purgeDryRun       bool
+)
+
+var transactionsPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Delete all transactions whose import_id starts with a prefix",
+	Long: `Delete every transaction whose import_id starts with --import-prefix.
+This is the escape hatch after a scripted import went wrong: give the
+imported transactions a recognizable import_id prefix and they can all be
+removed again in one go.
+
+The matching transactions are listed and must be confirmed unless --yes
+is given. Use --dry-run to only list them. Deletes are paced to stay
+under the API rate limit; an interrupted purge can simply be run again.`,
+	Example: `  ynabctl transactions purge --import-prefix "MIGRATION2024" --dry-run
+  ynabctl transactions purge --import-prefix "MIGRATION2024" --since 2024-01-01 --yes`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+
+		if strings.TrimSpace(purgeImportPrefix) == "" {
+			return fmt.Errorf("--import-prefix must not be empty")
+		}
+
+		filter := &ynab.TransactionFilter{SinceDate: purgeSinceDate}
+		if purgeAccount != "" {
+			filter.AccountID, err = resolveAccountID(budgetID, purgeAccount)
+			if err != nil {
+				return err
+			}
+		}
+
+		spinner := progress.Start("fetching transactions")
+		transactions, err := apiClient.GetTransactions(budgetID, filter)
+		spinner.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to get transactions: %w", err)
+		}
+
+		matched := []ynab.Transaction{}
+		byID := map[string]ynab.Transaction{}
+		var ids []string
+		for _, t := range transactions {
+			if t.Deleted || !strings.HasPrefix(t.ImportID, purgeImportPrefix) {
+				continue
+			}
+			matched = append(matched, t)
+			byID[t.ID] = t
+			ids = append(ids, t.ID)
+		}
+
+		formatter := output.New(getOutputFormat())
+		if len(matched) == 0 {
+			infof("No transactions with import_id prefix %q.\n", purgeImportPrefix)
+			return formatter.Print(matched)
+		}
+		if purgeDryRun {
+			infof("would delete %d transactions\n", len(matched))
+			return formatter.Print(matched)
+		}
+
+		for _, t := range matched {
+			fmt.Fprintf(os.Stderr, "  %s  %-30s %10.2f  %s\n",
+				t.Date, t.PayeeName, ynab.MilliunitsToAmount(t.Amount), t.ImportID)
+		}
+		ok, err := confirmOrAbort(i18n.T("Delete these %d transactions?", len(matched)))
+		if err != nil || !ok {
+			return err
+		}
+
+		// the prefix may contain anything, so key the checkpoint by a hash
+		sum := fmt.Sprintf("%x", sha1.Sum([]byte(budgetID+"\x00"+purgeImportPrefix)))
+		job := "transactions-purge-" + sum[:12]
+		log := output.NewChangelog(cmd.CommandPath())
+		defer finishChangelog(log)
+		err = runJob(job, ids, 1, func(id string) error {
+			t := byID[id]
+			_, err := apiClient.DeleteTransaction(budgetID, id)
+			log.Record("delete", id, t.Date+" "+t.PayeeName, t.Amount, err)
+			if err != nil {
+				return fmt.Errorf("failed to delete transaction %s: %w", id, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		infof("deleted %d transactions\n", len(matched))
+		return formatter.Print(matched)
+	},
+}
+
+func init() {
+	transactionsCmd.AddCommand(transactionsPurgeCmd)
+
+	transactionsPurgeCmd.Flags().StringVar(&purgeImportPrefix, "import-prefix", "", "Delete transactions whose import_id starts with this (required)")
+	transactionsPurgeCmd.Flags().StringVar(&purgeSinceDate, "since", "", "Only consider transactions since date (YYYY-MM-DD)")
+	transactionsPurgeCmd.Flags().StringVar(&purgeAccount, "account", "", "Only consider transactions in this account (ID or name)")
+	transactionsPurgeCmd.Flags().BoolVar(&purgeDryRun, "dry-run", false, "List matching transactions without deleting them")
+	addResumeFlag(transactionsPurgeCmd)
+	_ = transactionsPurgeCmd.MarkFlagRequired("import-prefix")
+}
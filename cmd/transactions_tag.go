@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/tags"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var tagRemove bool
+
+var transactionsTagCmd = &cobra.Command{
+	Use:   "tag <transaction-id> <tag>...",
+	Short: "Add or remove memo hashtags on a transaction",
+	Long: `Add tags to a transaction by appending "#tag" to its memo, or remove
+them with --remove. Tags are matched case-insensitively and the leading
+"#" is optional.
+
+Tagged transactions can be listed with 'transactions list --tag <tag>'
+and summarized with 'report spending --group-by tag'.`,
+	Example: `  ynabctl transactions tag <id> vacation2025
+  ynabctl transactions tag <id> vacation2025 --remove`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		if args[0], err = resolveTransactionID(budgetID, args[0]); err != nil {
+			return err
+		}
+
+		existing, knowledge, err := apiClient.GetTransactionWithKnowledge(budgetID, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get existing transaction: %w", err)
+		}
+
+		memo := existing.Memo
+		for _, tag := range args[1:] {
+			if !tags.Valid(tag) {
+				return fmt.Errorf("invalid tag %q: tags are letters, digits, _ and -", tag)
+			}
+			if tagRemove {
+				memo = tags.Remove(memo, tag)
+			} else {
+				memo = tags.Add(memo, tag)
+			}
+		}
+
+		formatter := output.New(getOutputFormat())
+		if memo == existing.Memo {
+			return formatter.Print(existing)
+		}
+
+		transaction, err := patchTransactionIfUnmodified(budgetID, ynab.PatchTransaction{ID: args[0], Memo: &memo}, knowledge)
+		if err != nil {
+			return err
+		}
+
+		return formatter.Print(transaction)
+	},
+}
+
+func init() {
+	transactionsCmd.AddCommand(transactionsTagCmd)
+
+	transactionsTagCmd.Flags().BoolVar(&tagRemove, "remove", false, "Remove the tags instead of adding them")
+}
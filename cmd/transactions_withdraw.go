@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var (
+	withdrawFrom    string
+	withdrawToCash  string
+	withdrawAmount  float64
+	withdrawDate    string
+	withdrawMemo    string
+	withdrawCleared string
+)
+
+var transactionsWithdrawCmd = &cobra.Command{
+	Use:   "withdraw",
+	Short: "Book a cash withdrawal as a transfer to a cash account",
+	Long: `Book an ATM withdrawal in one step.
+
+Creates an outflow of --amount from the --from account with the cash
+account's transfer payee, so YNAB records it as a transfer and adds the
+matching inflow to the --to-cash account. Accounts can be given by name
+or ID.`,
+	Example: `  ynabctl transactions withdraw --from Checking --amount 200 --to-cash Cash
+  ynabctl transactions withdraw --from Checking --amount 50 --to-cash Wallet --date 2025-07-01 --memo "ATM Central Station"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+
+		if withdrawFrom == "" || withdrawToCash == "" {
+			return fmt.Errorf("both --from and --to-cash are required")
+		}
+		if withdrawAmount <= 0 {
+			return fmt.Errorf("--amount must be a positive amount")
+		}
+
+		from, err := resolveAccount(budgetID, withdrawFrom)
+		if err != nil {
+			return err
+		}
+		to, err := resolveAccount(budgetID, withdrawToCash)
+		if err != nil {
+			return err
+		}
+		if from.ID == to.ID {
+			return fmt.Errorf("--from and --to-cash are the same account")
+		}
+		if to.TransferPayeeID == "" {
+			return fmt.Errorf("account %s has no transfer payee", to.Name)
+		}
+		if to.Type != "cash" {
+			fmt.Fprintf(os.Stderr, "Warning: %s is a %s account, not a cash account\n", to.Name, to.Type)
+		}
+
+		date := withdrawDate
+		if date == "" {
+			date = time.Now().Format("2006-01-02")
+		}
+
+		txn := ynab.SaveTransaction{
+			AccountID: from.ID,
+			Date:      date,
+			Amount:    -ynab.AmountToMilliunits(withdrawAmount),
+			PayeeID:   to.TransferPayeeID,
+			Memo:      withdrawMemo,
+			Cleared:   withdrawCleared,
+			Approved:  true,
+		}
+
+		transaction, err := apiClient.CreateTransaction(budgetID, txn)
+		if err != nil {
+			return fmt.Errorf("failed to create withdrawal: %w", err)
+		}
+
+		formatter := output.New(getOutputFormat())
+		return formatter.Print(transaction)
+	},
+}
+
+func init() {
+	transactionsCmd.AddCommand(transactionsWithdrawCmd)
+	transactionsWithdrawCmd.Flags().StringVar(&withdrawFrom, "from", "", "Account the cash is withdrawn from (name or ID)")
+	transactionsWithdrawCmd.Flags().StringVar(&withdrawToCash, "to-cash", "", "Cash account receiving the money (name or ID)")
+	transactionsWithdrawCmd.Flags().Float64Var(&withdrawAmount, "amount", 0, "Amount withdrawn (positive)")
+	transactionsWithdrawCmd.Flags().StringVar(&withdrawDate, "date", "", "Withdrawal date (YYYY-MM-DD, default: today)")
+	transactionsWithdrawCmd.Flags().StringVar(&withdrawMemo, "memo", "", "Memo")
+	transactionsWithdrawCmd.Flags().StringVar(&withdrawCleared, "cleared", "", "Cleared status (cleared, uncleared, reconciled)")
+}
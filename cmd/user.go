@@ -2,26 +2,89 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"time"
 
+	"github.com/langtind/ynabctl/internal/config"
 	"github.com/langtind/ynabctl/internal/output"
 	"github.com/spf13/cobra"
 )
 
+var userStaleDays int
+
 var userCmd = &cobra.Command{
 	Use:   "user",
 	Short: "Get authenticated user information",
-	Long:  `Returns information about the authenticated user.`,
+	Long: `Returns information about the authenticated user, together with the
+configured default budget (resolved to its name), the type of token and
+where it is configured.
+
+ynabctl records when the token was last used on this machine. If that
+was more than --stale-days ago, a warning is shown: a token that is
+rarely used is one that may be forgotten when access should be revoked.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		// read before this command's own requests count as a use
+		lastUsed, known, err := config.LastTokenUse(cfg.Token)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to read token usage: %v\n", err)
+		}
+
 		user, err := apiClient.GetUser()
 		if err != nil {
 			return fmt.Errorf("failed to get user: %w", err)
 		}
 
+		status := &output.UserStatus{
+			ID:          user.ID,
+			TokenType:   tokenTypeName(cfg.TokenType),
+			TokenSource: tokenSource,
+		}
+
+		switch cfg.DefaultBudget {
+		case "":
+		case "last-used", "default":
+			// aliases understood by the API, not budget IDs
+			status.DefaultBudgetID = cfg.DefaultBudget
+			status.DefaultBudget = "(" + cfg.DefaultBudget + ")"
+		default:
+			status.DefaultBudgetID = cfg.DefaultBudget
+			budgets, err := apiClient.GetBudgets()
+			if err != nil {
+				return fmt.Errorf("failed to get budgets: %w", err)
+			}
+			status.DefaultBudget = "(not found)"
+			for _, b := range budgets {
+				if b.ID == cfg.DefaultBudget {
+					status.DefaultBudget = b.Name
+				}
+			}
+		}
+
+		if known {
+			status.TokenLastUsed = &lastUsed
+			if days := int(time.Since(lastUsed).Hours() / 24); userStaleDays > 0 && days > userStaleDays {
+				status.Warnings = append(status.Warnings,
+					fmt.Sprintf("token not used for %d days; revoke it in YNAB's Developer Settings if it is no longer needed", days))
+			}
+		}
+		for _, w := range status.Warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+		}
+
 		formatter := output.New(getOutputFormat())
-		return formatter.Print(user)
+		return formatter.Print(status)
 	},
 }
 
+// tokenTypeName describes the configured token type
+func tokenTypeName(tokenType string) string {
+	if tokenType == "oauth" {
+		return "OAuth access token"
+	}
+	return "personal access token"
+}
+
 func init() {
 	rootCmd.AddCommand(userCmd)
+	userCmd.Flags().IntVar(&userStaleDays, "stale-days", 90, "Warn if the token was last used more than this many days ago (0 to disable)")
 }
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// verifyUpdate is set by --verify: re-read the record just before saving
+// and abort if it changed since it was first read
+var verifyUpdate bool
+
+// abortIfChanged fails if drift, the fields changed by someone else
+// since what was first read, is not empty. The changes are shown on
+// stderr.
+func abortIfChanged(what string, drift output.Changes) error {
+	if len(drift) == 0 {
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "%s was changed meanwhile:\n", what)
+	output.PrintDiff(os.Stderr, drift, output.UseColor(os.Stderr))
+	return fmt.Errorf("%s was modified since it was read; re-run to review the latest version", what)
+}
+
+// scheduledDrift compares the fields of a scheduled transaction that an
+// update writes. The next date is left out, since it moves on by itself.
+func scheduledDrift(before, after *ynab.ScheduledTransaction) output.Changes {
+	var drift output.Changes
+	drift.Add("account", before.AccountID, after.AccountID)
+	drift.Add("date", before.DateFirst, after.DateFirst)
+	drift.Add("frequency", before.Frequency, after.Frequency)
+	drift.AddAmount("amount", before.Amount, after.Amount)
+	drift.Add("payee_id", before.PayeeID, after.PayeeID)
+	drift.Add("category", before.CategoryID, after.CategoryID)
+	drift.Add("memo", before.Memo, after.Memo)
+	drift.Add("flag", before.FlagColor, after.FlagColor)
+	drift.Add("deleted", before.Deleted, after.Deleted)
+	return drift
+}
+
+// categoryDrift compares the budgeted amount of a category month, the
+// field an update writes. Activity is left out: new transactions change
+// it without clobbering anything.
+func categoryDrift(before, after *ynab.Category) output.Changes {
+	var drift output.Changes
+	drift.AddAmount("budgeted", before.Budgeted, after.Budgeted)
+	return drift
+}
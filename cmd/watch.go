@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/notify"
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/internal/progress"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"github.com/spf13/cobra"
+)
+
+var watchRefresh refreshFlags
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Print transactions as they are added or changed",
+	Long: `Keep running and print every transaction that is added, changed or
+deleted in the budget, e.g. by bank import or on the phone. Stop it with
+Ctrl-C.
+
+Changes are found with a delta request. The command checks every
+--interval after a change and waits twice as long after each check that
+found nothing, up to --max-interval (15m, or --interval if longer), and
+slows down further when the API rate limit runs low.
+
+With --notify, every check that found changes also shows a desktop
+notification.`,
+	Example: `  ynabctl watch -f table
+  ynabctl watch --notify --interval 5m
+  ynabctl watch | jq -c '.[] | {date, payee_name, amount}'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		budgetID, err := getBudgetID()
+		if err != nil {
+			return err
+		}
+		refresher, err := watchRefresh.refresher()
+		if err != nil {
+			return err
+		}
+
+		spinner := progress.Start("fetching transactions")
+		_, knowledge, err := apiClient.GetTransactionsSince(budgetID, 0)
+		spinner.Stop()
+		if err != nil {
+			return fmt.Errorf("failed to get transactions: %w", err)
+		}
+
+		formatter := output.New(getOutputFormat())
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+		infof("watching for changed transactions every %s to %s; press Ctrl-C to stop\n", refresher.Min, refresher.Max)
+		return refresher.Run(ctx, func() (bool, error) {
+			changed, current, err := apiClient.Fresh().GetTransactionsSince(budgetID, knowledge)
+			if err != nil {
+				return false, fmt.Errorf("failed to get changed transactions: %w", err)
+			}
+			knowledge = current
+			if len(changed) == 0 {
+				return false, nil
+			}
+			infof("%s: %d changed transactions\n", time.Now().Format("15:04"), len(changed))
+			if notifyFlag {
+				if err := notify.Send(cmd.CommandPath(), watchMessage(changed)); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to show notification: %v\n", err)
+				}
+			}
+			return true, formatter.Print(changed)
+		})
+	},
+}
+
+// watchMessage summarises changed transactions for a notification
+func watchMessage(changed []ynab.Transaction) string {
+	t := changed[0]
+	msg := fmt.Sprintf("%s %s %.2f", t.Date, t.PayeeName, ynab.MilliunitsToAmount(t.Amount))
+	if t.Deleted {
+		msg = "deleted: " + msg
+	}
+	if len(changed) > 1 {
+		msg += fmt.Sprintf(" and %d more", len(changed)-1)
+	}
+	return msg
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	watchRefresh.add(watchCmd, time.Minute, 15*time.Minute, "How often to check for changed transactions")
+}
@@ -0,0 +1,155 @@
+// Package allocplan reads allocation plans: YAML files saying how an
+// income such as a paycheck is spread over categories, by fixed amounts
+// and percentages, so the ritual of budgeting each paycheck can be
+// repeated with one command.
+package allocplan
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"gopkg.in/yaml.v3"
+)
+
+// Kinds of plan entries
+const (
+	Fixed   = "fixed"
+	Percent = "percent"
+	Rest    = "rest"
+)
+
+// Plan is the content of an allocation plan file. Amounts are in
+// currency units.
+type Plan struct {
+	Allocations []Entry `yaml:"allocations"`
+}
+
+// Entry assigns a fixed amount, a percentage of the income, or what is
+// left of it after the other entries, to a category (ID, name, "Group:
+// Name" or @alias)
+type Entry struct {
+	Category string  `yaml:"category"`
+	Amount   float64 `yaml:"amount,omitempty"`
+	Percent  float64 `yaml:"percent,omitempty"`
+	Rest     bool    `yaml:"rest,omitempty"`
+}
+
+// Kind returns whether the entry is fixed, a percentage or the rest
+func (e Entry) Kind() string {
+	switch {
+	case e.Rest:
+		return Rest
+	case e.Percent != 0:
+		return Percent
+	}
+	return Fixed
+}
+
+// Load reads and checks a plan file. Every entry needs a category and
+// exactly one of amount, percent and rest; at most one entry takes the
+// rest, and percentages add up to at most 100.
+func Load(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Plan
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(p.Allocations) == 0 {
+		return nil, fmt.Errorf("%s: no allocations", path)
+	}
+	var percent float64
+	rest := false
+	for i, e := range p.Allocations {
+		if strings.TrimSpace(e.Category) == "" {
+			return nil, fmt.Errorf("%s: allocation %d has no category", path, i+1)
+		}
+		set := 0
+		for _, on := range []bool{e.Amount != 0, e.Percent != 0, e.Rest} {
+			if on {
+				set++
+			}
+		}
+		if set != 1 {
+			return nil, fmt.Errorf("%s: %s needs exactly one of amount, percent and rest", path, e.Category)
+		}
+		if e.Amount < 0 || e.Percent < 0 || e.Percent > 100 {
+			return nil, fmt.Errorf("%s: %s has a negative amount or a percentage outside 0-100", path, e.Category)
+		}
+		if e.Rest {
+			if rest {
+				return nil, fmt.Errorf("%s: only one allocation can take the rest", path)
+			}
+			rest = true
+		}
+		percent += e.Percent
+	}
+	if percent > 100 {
+		return nil, fmt.Errorf("%s: percentages add up to %g%%", path, percent)
+	}
+	return &p, nil
+}
+
+// Share is what one entry of a plan gets of an income, in milliunits
+type Share struct {
+	Entry  Entry
+	Amount int64
+}
+
+// Split sizes each entry for an income in milliunits. Percentages are
+// rounded to whole cents. Fixed amounts and percentages together may not
+// exceed the income; the rest entry gets whatever is left.
+func (p *Plan) Split(income int64) ([]Share, error) {
+	shares := make([]Share, len(p.Allocations))
+	var total int64
+	rest := -1
+	for i, e := range p.Allocations {
+		shares[i].Entry = e
+		switch e.Kind() {
+		case Fixed:
+			shares[i].Amount = ynab.AmountToMilliunits(e.Amount)
+		case Percent:
+			shares[i].Amount = int64(math.Round(float64(income)*e.Percent/100/10)) * 10
+		case Rest:
+			rest = i
+		}
+		total += shares[i].Amount
+	}
+	if total > income {
+		return nil, fmt.Errorf("the plan allocates %.2f, more than the income of %.2f",
+			float64(total)/1000, float64(income)/1000)
+	}
+	if rest >= 0 {
+		shares[rest].Amount = income - total
+	}
+	return shares, nil
+}
+
+// Item is one category of an applied allocation
+type Item struct {
+	CategoryID string  `json:"category_id"`
+	Name       string  `json:"name"`
+	Kind       string  `json:"kind"`
+	Percent    float64 `json:"percent,omitempty"`
+	Amount     int64   `json:"amount" amount:"milliunits"`
+	// Budgeted is the category's budgeted amount after the allocation
+	Budgeted int64 `json:"budgeted" amount:"milliunits"`
+}
+
+// Allocation is an income transaction spread over categories by a plan.
+// LeftOver is what stays in Ready to Assign.
+type Allocation struct {
+	TransactionID string `json:"transaction_id"`
+	Date          string `json:"date"`
+	Payee         string `json:"payee"`
+	Month         string `json:"month"`
+	Income        int64  `json:"income" amount:"milliunits"`
+	Allocated     int64  `json:"allocated" amount:"milliunits"`
+	LeftOver      int64  `json:"left_over" amount:"milliunits"`
+	Items         []Item `json:"items"`
+}
@@ -0,0 +1,69 @@
+package allocplan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writePlan(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "paycheck.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSplit(t *testing.T) {
+	p, err := Load(writePlan(t, `
+allocations:
+  - category: Rent
+    amount: 12000
+  - category: Savings
+    percent: 10
+  - category: "Everyday: Groceries"
+    percent: 15.5
+  - category: Fun
+    rest: true
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares, err := p.Split(31234560)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int64{12000000, 3123460, 4841360, 11269740}
+	var total int64
+	for i, s := range shares {
+		if s.Amount != want[i] {
+			t.Errorf("%s = %d, want %d", s.Entry.Category, s.Amount, want[i])
+		}
+		total += s.Amount
+	}
+	if total != 31234560 {
+		t.Errorf("total = %d, want the whole income", total)
+	}
+
+	if _, err := p.Split(10000000); err == nil {
+		t.Error("Split allocated more than the income")
+	}
+}
+
+func TestLoadRejectsBadPlans(t *testing.T) {
+	for name, content := range map[string]string{
+		"empty":        "allocations: []\n",
+		"no category":  "allocations:\n  - amount: 10\n",
+		"two kinds":    "allocations:\n  - category: A\n    amount: 10\n    percent: 5\n",
+		"no kind":      "allocations:\n  - category: A\n",
+		"two rests":    "allocations:\n  - category: A\n    rest: true\n  - category: B\n    rest: true\n",
+		"over 100%":    "allocations:\n  - category: A\n    percent: 60\n  - category: B\n    percent: 50\n",
+		"negative":     "allocations:\n  - category: A\n    amount: -5\n",
+		"invalid yaml": "allocations: [\n",
+	} {
+		if _, err := Load(writePlan(t, content)); err == nil {
+			t.Errorf("%s: Load succeeded", name)
+		}
+	}
+}
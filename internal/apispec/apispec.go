@@ -0,0 +1,215 @@
+// Package apispec compares YNAB's published OpenAPI specification with
+// the endpoints and record fields the client in pkg/ynab knows about, so
+// the hand-written client does not silently drift from the API.
+package apispec
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"gopkg.in/yaml.v3"
+)
+
+// Spec is the part of an OpenAPI document the comparison needs
+type Spec struct {
+	Version    string                          `yaml:"openapi"`
+	Paths      map[string]map[string]yaml.Node `yaml:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+// Schema is an OpenAPI schema object, reduced to what makes up its
+// properties
+type Schema struct {
+	Ref        string             `yaml:"$ref"`
+	Properties map[string]*Schema `yaml:"properties"`
+	AllOf      []*Schema          `yaml:"allOf"`
+}
+
+var methods = []string{"get", "post", "put", "patch", "delete"}
+
+// Parse reads an OpenAPI document in YAML or JSON
+func Parse(data []byte) (*Spec, error) {
+	var s Spec
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+	if len(s.Paths) == 0 {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: no paths")
+	}
+	return &s, nil
+}
+
+// Endpoints returns the operations of the spec
+func (s *Spec) Endpoints() []ynab.Endpoint {
+	var endpoints []ynab.Endpoint
+	for path, item := range s.Paths {
+		for _, m := range methods {
+			if _, ok := item[m]; ok {
+				endpoints = append(endpoints, ynab.Endpoint{Method: strings.ToUpper(m), Path: path})
+			}
+		}
+	}
+	return endpoints
+}
+
+// Fields returns the property names of a named schema, including those
+// it takes from other schemas with allOf, and whether the schema exists
+func (s *Spec) Fields(name string) ([]string, bool) {
+	schema, ok := s.Components.Schemas[name]
+	if !ok {
+		return nil, false
+	}
+	seen := map[string]bool{}
+	s.collect(schema, seen, map[string]bool{name: true})
+	fields := make([]string, 0, len(seen))
+	for f := range seen {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields, true
+}
+
+func (s *Spec) collect(schema *Schema, fields, visiting map[string]bool) {
+	if schema == nil {
+		return
+	}
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		if visiting[name] {
+			return
+		}
+		visiting[name] = true
+		s.collect(s.Components.Schemas[name], fields, visiting)
+		delete(visiting, name)
+		return
+	}
+	for f := range schema.Properties {
+		fields[f] = true
+	}
+	for _, part := range schema.AllOf {
+		s.collect(part, fields, visiting)
+	}
+}
+
+// Report is the drift between the spec and the client
+type Report struct {
+	SpecVersion string `json:"spec_version,omitempty"`
+	// MissingEndpoints are in the spec but not implemented by the client
+	MissingEndpoints []ynab.Endpoint `json:"missing_endpoints"`
+	// UnknownEndpoints are called by the client but not in the spec
+	UnknownEndpoints []ynab.Endpoint `json:"unknown_endpoints"`
+	Schemas          []SchemaDrift   `json:"schemas"`
+}
+
+// SchemaDrift compares the fields of one schema with the struct that
+// decodes it
+type SchemaDrift struct {
+	Schema string `json:"schema"`
+	// NotInSpec is set when the spec no longer has the schema
+	NotInSpec bool `json:"not_in_spec,omitempty"`
+	// MissingFields are in the spec but not decoded by the client
+	MissingFields []string `json:"missing_fields,omitempty"`
+	// UnknownFields are decoded by the client but not in the spec
+	UnknownFields []string `json:"unknown_fields,omitempty"`
+}
+
+// Drifted reports whether the client differs from the spec
+func (r *Report) Drifted() bool {
+	if len(r.MissingEndpoints) > 0 || len(r.UnknownEndpoints) > 0 {
+		return true
+	}
+	for _, d := range r.Schemas {
+		if d.NotInSpec || len(d.MissingFields) > 0 || len(d.UnknownFields) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+var reParam = regexp.MustCompile(`\{[^}]*\}`)
+
+// Compare reports the drift between the spec and the client's endpoints
+// and decoded fields (by schema name). Path parameters match whatever
+// they are called.
+func Compare(s *Spec, endpoints []ynab.Endpoint, fields map[string][]string) *Report {
+	key := func(e ynab.Endpoint) string {
+		return e.Method + " " + reParam.ReplaceAllString(e.Path, "{}")
+	}
+	r := &Report{
+		SpecVersion:      s.Version,
+		MissingEndpoints: []ynab.Endpoint{},
+		UnknownEndpoints: []ynab.Endpoint{},
+		Schemas:          []SchemaDrift{},
+	}
+
+	specEndpoints := s.Endpoints()
+	inSpec := map[string]bool{}
+	for _, e := range specEndpoints {
+		inSpec[key(e)] = true
+	}
+	inClient := map[string]bool{}
+	for _, e := range endpoints {
+		inClient[key(e)] = true
+		if !inSpec[key(e)] {
+			r.UnknownEndpoints = append(r.UnknownEndpoints, e)
+		}
+	}
+	for _, e := range specEndpoints {
+		if !inClient[key(e)] {
+			r.MissingEndpoints = append(r.MissingEndpoints, e)
+		}
+	}
+	sortEndpoints(r.MissingEndpoints)
+	sortEndpoints(r.UnknownEndpoints)
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		specFields, ok := s.Fields(name)
+		if !ok {
+			r.Schemas = append(r.Schemas, SchemaDrift{Schema: name, NotInSpec: true})
+			continue
+		}
+		d := SchemaDrift{
+			Schema:        name,
+			MissingFields: difference(specFields, fields[name]),
+			UnknownFields: difference(fields[name], specFields),
+		}
+		if len(d.MissingFields) > 0 || len(d.UnknownFields) > 0 {
+			r.Schemas = append(r.Schemas, d)
+		}
+	}
+	return r
+}
+
+// difference returns the elements of a not in b, in a's order
+func difference(a, b []string) []string {
+	in := map[string]bool{}
+	for _, s := range b {
+		in[s] = true
+	}
+	var diff []string
+	for _, s := range a {
+		if !in[s] {
+			diff = append(diff, s)
+		}
+	}
+	return diff
+}
+
+func sortEndpoints(endpoints []ynab.Endpoint) {
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Path != endpoints[j].Path {
+			return endpoints[i].Path < endpoints[j].Path
+		}
+		return endpoints[i].Method < endpoints[j].Method
+	})
+}
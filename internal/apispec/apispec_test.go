@@ -0,0 +1,110 @@
+package apispec
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+const spec = `
+openapi: 3.0.0
+paths:
+  /budgets/{budget_id}/payees:
+    parameters:
+      - name: budget_id
+        in: path
+    get:
+      summary: List payees
+  /budgets/{budget_id}/payees/{payee_id}:
+    get:
+      summary: Single payee
+    patch:
+      summary: Update a payee
+components:
+  schemas:
+    Payee:
+      type: object
+      properties:
+        id: {type: string}
+        name: {type: string}
+        deleted: {type: boolean}
+    TransactionSummary:
+      type: object
+      properties:
+        id: {type: string}
+        amount: {type: integer}
+        import_id: {type: string}
+    TransactionDetail:
+      allOf:
+        - $ref: '#/components/schemas/TransactionSummary'
+        - type: object
+          properties:
+            account_name: {type: string}
+            subtransactions:
+              type: array
+              items:
+                $ref: '#/components/schemas/TransactionDetail'
+`
+
+func TestFields(t *testing.T) {
+	s, err := Parse([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields, ok := s.Fields("TransactionDetail")
+	if !ok || strings.Join(fields, ",") != "account_name,amount,id,import_id,subtransactions" {
+		t.Errorf("TransactionDetail fields = %v, %t", fields, ok)
+	}
+	if _, ok := s.Fields("Nope"); ok {
+		t.Error("Fields(Nope) found a schema")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	s, err := Parse([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := Compare(s, []ynab.Endpoint{
+		{Method: "GET", Path: "/budgets/{b}/payees"},
+		{Method: "GET", Path: "/budgets/{b}/payees/{id}"},
+		{Method: "DELETE", Path: "/budgets/{b}/payees/{id}"},
+	}, map[string][]string{
+		"Payee":             {"deleted", "id", "name", "transfer_account_id"},
+		"TransactionDetail": {"account_name", "amount", "id", "import_id", "subtransactions"},
+		"Month":             {"month"},
+	})
+
+	if !r.Drifted() {
+		t.Fatal("no drift reported")
+	}
+	if len(r.MissingEndpoints) != 1 || r.MissingEndpoints[0].Method != "PATCH" {
+		t.Errorf("missing endpoints = %+v", r.MissingEndpoints)
+	}
+	if len(r.UnknownEndpoints) != 1 || r.UnknownEndpoints[0].Method != "DELETE" {
+		t.Errorf("unknown endpoints = %+v", r.UnknownEndpoints)
+	}
+	if len(r.Schemas) != 2 {
+		t.Fatalf("schemas = %+v", r.Schemas)
+	}
+	if r.Schemas[0].Schema != "Month" || !r.Schemas[0].NotInSpec {
+		t.Errorf("schema 0 = %+v", r.Schemas[0])
+	}
+	if r.Schemas[1].Schema != "Payee" || len(r.Schemas[1].UnknownFields) != 1 || len(r.Schemas[1].MissingFields) != 0 {
+		t.Errorf("schema 1 = %+v", r.Schemas[1])
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	s, err := Parse([]byte(`{"openapi":"3.0.0","paths":{"/user":{"get":{}}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := s.Endpoints(); len(e) != 1 || e[0].Path != "/user" {
+		t.Errorf("endpoints = %+v", e)
+	}
+	if _, err := Parse([]byte("openapi: 3.0.0\n")); err == nil {
+		t.Error("spec without paths parsed")
+	}
+}
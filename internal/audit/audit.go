@@ -0,0 +1,122 @@
+// Package audit keeps a local log of every command that changed data in
+// YNAB. Entries are appended to a JSON-lines file, one line per command.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/config"
+)
+
+// Entry is a single mutating command invocation
+type Entry struct {
+	Time     time.Time `json:"time"`
+	Command  string    `json:"command"`
+	Args     []string  `json:"args"`
+	BudgetID string    `json:"budget_id,omitempty"`
+	IDs      []string  `json:"ids,omitempty"`
+	Requests []string  `json:"requests"`
+	Result   string    `json:"result"`
+}
+
+// OK reports whether the command completed without error
+func (e Entry) OK() bool {
+	return e.Result == "ok"
+}
+
+// Path returns the audit log location, honoring XDG_STATE_HOME and
+// defaulting to ~/.local/state/ynabctl/audit.log
+func Path() string {
+	return filepath.Join(config.StateDir(), "audit.log")
+}
+
+// Append adds an entry to the log at path, creating it if needed
+func Append(path string, e Entry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("mkdir %s: %w", filepath.Dir(path), err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// Read returns all entries in the log, oldest first. A missing log is
+// not an error.
+func Read(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// Filter selects log entries. Zero values match everything.
+type Filter struct {
+	Since   time.Time
+	Until   time.Time
+	Command string // substring of the command path
+	ID      string // an affected record ID
+	Failed  bool   // only entries that ended in an error
+}
+
+// Match reports whether e passes the filter
+func (f Filter) Match(e Entry) bool {
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !e.Time.Before(f.Until) {
+		return false
+	}
+	if f.Command != "" && !strings.Contains(e.Command, f.Command) {
+		return false
+	}
+	if f.Failed && e.OK() {
+		return false
+	}
+	if f.ID != "" {
+		found := false
+		for _, id := range e.IDs {
+			if id == f.ID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
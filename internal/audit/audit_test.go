@@ -0,0 +1,67 @@
+package audit
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAppendRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "audit.log")
+
+	entries, err := Read(path)
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("Read(missing) = %v, %v", entries, err)
+	}
+
+	t0 := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	want := []Entry{
+		{Time: t0, Command: "ynabctl transactions create", IDs: []string{"a"}, Result: "ok"},
+		{Time: t0.Add(time.Hour), Command: "ynabctl transactions delete", IDs: []string{"b"}, Result: "error: 404"},
+	}
+	for _, e := range want {
+		if err := Append(path, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Command != want[0].Command || !got[1].Time.Equal(want[1].Time) {
+		t.Fatalf("Read = %+v", got)
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	t0 := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	e := Entry{Time: t0, Command: "ynabctl transactions delete", IDs: []string{"x", "y"}, Result: "error: boom"}
+
+	tests := []struct {
+		name string
+		f    Filter
+		want bool
+	}{
+		{"empty", Filter{}, true},
+		{"since before", Filter{Since: t0.Add(-time.Hour)}, true},
+		{"since after", Filter{Since: t0.Add(time.Hour)}, false},
+		{"until exclusive", Filter{Until: t0}, false},
+		{"command", Filter{Command: "delete"}, true},
+		{"other command", Filter{Command: "create"}, false},
+		{"id", Filter{ID: "y"}, true},
+		{"other id", Filter{ID: "z"}, false},
+		{"failed", Filter{Failed: true}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.f.Match(e); got != tt.want {
+			t.Errorf("%s: Match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	ok := e
+	ok.Result = "ok"
+	if (Filter{Failed: true}).Match(ok) {
+		t.Error("Failed filter matched a successful entry")
+	}
+}
@@ -0,0 +1,206 @@
+// Package budgetplan reads and writes budget plans: YAML files listing
+// the budgeted amount of each category per month, and category goals,
+// so budget structure can be kept in version control and applied.
+package budgetplan
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"gopkg.in/yaml.v3"
+)
+
+// Plan is the content of a budget plan file. Amounts are in currency
+// units.
+type Plan struct {
+	BudgetID string  `yaml:"budget_id,omitempty"`
+	Months   []Month `yaml:"months"`
+	Goals    []Goal  `yaml:"goals,omitempty"`
+}
+
+// Month lists the budgeted amounts of one month (YYYY-MM)
+type Month struct {
+	Month      string     `yaml:"month"`
+	Categories []Category `yaml:"categories"`
+}
+
+// Category is a budgeted amount. The category is found by ID if given,
+// otherwise by group and name.
+type Category struct {
+	ID       string  `yaml:"id,omitempty"`
+	Group    string  `yaml:"group"`
+	Name     string  `yaml:"name"`
+	Budgeted float64 `yaml:"budgeted"`
+}
+
+// Goal is a category's goal target
+type Goal struct {
+	ID     string  `yaml:"id,omitempty"`
+	Group  string  `yaml:"group"`
+	Name   string  `yaml:"name"`
+	Target float64 `yaml:"target"`
+}
+
+// Load reads a plan file
+func Load(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Plan
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for _, m := range p.Months {
+		if len(m.Month) != 7 {
+			return nil, fmt.Errorf("%s: invalid month %q (want YYYY-MM)", path, m.Month)
+		}
+	}
+	return &p, nil
+}
+
+// Marshal renders the plan as YAML
+func (p *Plan) Marshal() ([]byte, error) {
+	return yaml.Marshal(p)
+}
+
+// Write saves the plan as YAML
+func (p *Plan) Write(path string) error {
+	data, err := p.Marshal()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Export builds a plan from the given months and the budget's category
+// groups. Hidden and deleted categories, and YNAB's internal group, are
+// left out. Goals are exported for categories that have a target.
+func Export(budgetID string, months []*ynab.Month, groups []ynab.CategoryGroup) *Plan {
+	p := &Plan{BudgetID: budgetID}
+	for _, m := range months {
+		pm := Month{Month: m.Month[:7]}
+		for _, c := range m.Categories {
+			if skipCategory(c) {
+				continue
+			}
+			pm.Categories = append(pm.Categories, Category{
+				ID:       c.ID,
+				Group:    c.CategoryGroupName,
+				Name:     c.Name,
+				Budgeted: ynab.MilliunitsToAmount(c.Budgeted),
+			})
+		}
+		p.Months = append(p.Months, pm)
+	}
+	for _, g := range groups {
+		for _, c := range g.Categories {
+			if skipCategory(c) || g.Hidden || g.Deleted || c.GoalType == "" || c.GoalTarget == 0 {
+				continue
+			}
+			p.Goals = append(p.Goals, Goal{
+				ID:     c.ID,
+				Group:  g.Name,
+				Name:   c.Name,
+				Target: ynab.MilliunitsToAmount(c.GoalTarget),
+			})
+		}
+	}
+	return p
+}
+
+func skipCategory(c ynab.Category) bool {
+	return c.Deleted || c.Hidden || c.CategoryGroupName == "Internal Master Category"
+}
+
+// Change is one difference between a plan and the budget. Field is
+// "budgeted" (for Month) or "goal_target".
+type Change struct {
+	Month      string `json:"month,omitempty"`
+	CategoryID string `json:"category_id"`
+	Label      string `json:"category"`
+	Field      string `json:"field"`
+	Before     int64  `json:"before" amount:"milliunits"`
+	After      int64  `json:"after" amount:"milliunits"`
+}
+
+// Diff compares the plan with the current budget. months maps each
+// plan month (YYYY-MM) to its current data; groups are the current
+// category groups, used for goals. Categories in the plan that do not
+// exist in the budget are an error, so typos are not silently skipped.
+func Diff(p *Plan, months map[string]*ynab.Month, groups []ynab.CategoryGroup) ([]Change, error) {
+	changes := []Change{}
+	for _, pm := range p.Months {
+		m := months[pm.Month]
+		if m == nil {
+			return nil, fmt.Errorf("no budget data for month %s", pm.Month)
+		}
+		for _, pc := range pm.Categories {
+			c, err := find(m.Categories, pc.ID, pc.Group, pc.Name)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", pm.Month, err)
+			}
+			after := toMilliunits(pc.Budgeted)
+			if after != c.Budgeted {
+				changes = append(changes, Change{
+					Month: pm.Month, CategoryID: c.ID, Label: label(c.CategoryGroupName, c.Name),
+					Field: "budgeted", Before: c.Budgeted, After: after,
+				})
+			}
+		}
+	}
+
+	var all []ynab.Category
+	for _, g := range groups {
+		for _, c := range g.Categories {
+			c.CategoryGroupName = g.Name
+			all = append(all, c)
+		}
+	}
+	for _, pg := range p.Goals {
+		c, err := find(all, pg.ID, pg.Group, pg.Name)
+		if err != nil {
+			return nil, fmt.Errorf("goals: %w", err)
+		}
+		after := toMilliunits(pg.Target)
+		if after != c.GoalTarget {
+			changes = append(changes, Change{
+				CategoryID: c.ID, Label: label(c.CategoryGroupName, c.Name),
+				Field: "goal_target", Before: c.GoalTarget, After: after,
+			})
+		}
+	}
+	return changes, nil
+}
+
+// find looks a category up by ID, or by group and name ignoring case
+func find(categories []ynab.Category, id, group, name string) (ynab.Category, error) {
+	for _, c := range categories {
+		if c.Deleted {
+			continue
+		}
+		if id != "" && c.ID == id {
+			return c, nil
+		}
+		if id == "" && strings.EqualFold(c.CategoryGroupName, group) && strings.EqualFold(c.Name, name) {
+			return c, nil
+		}
+	}
+	if id != "" {
+		return ynab.Category{}, fmt.Errorf("category %s (%s) not found", id, label(group, name))
+	}
+	return ynab.Category{}, fmt.Errorf("category %q not found", label(group, name))
+}
+
+func label(group, name string) string {
+	if group == "" {
+		return name
+	}
+	return group + ": " + name
+}
+
+func toMilliunits(amount float64) int64 {
+	return ynab.AmountToMilliunits(amount)
+}
@@ -0,0 +1,72 @@
+package budgetplan
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func testBudget() (*ynab.Month, []ynab.CategoryGroup) {
+	month := &ynab.Month{Month: "2025-01-01", Categories: []ynab.Category{
+		{ID: "rent", CategoryGroupName: "Bills", Name: "Rent", Budgeted: 1000000},
+		{ID: "food", CategoryGroupName: "Everyday", Name: "Groceries", Budgeted: 400000},
+		{ID: "rta", CategoryGroupName: "Internal Master Category", Name: "Inflow: Ready to Assign"},
+		{ID: "old", CategoryGroupName: "Everyday", Name: "Old", Hidden: true},
+	}}
+	groups := []ynab.CategoryGroup{
+		{Name: "Bills", Categories: []ynab.Category{{ID: "rent", Name: "Rent", GoalType: "NEED", GoalTarget: 1000000}}},
+		{Name: "Everyday", Categories: []ynab.Category{{ID: "food", Name: "Groceries"}}},
+	}
+	return month, groups
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	month, groups := testBudget()
+	p := Export("b1", []*ynab.Month{month}, groups)
+	if len(p.Months) != 1 || len(p.Months[0].Categories) != 2 || len(p.Goals) != 1 {
+		t.Fatalf("plan = %+v", p)
+	}
+
+	path := filepath.Join(t.TempDir(), "categories.yaml")
+	if err := p.Write(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := Diff(loaded, map[string]*ynab.Month{"2025-01": month}, groups)
+	if err != nil || len(changes) != 0 {
+		t.Errorf("unchanged plan has changes %+v, %v", changes, err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	month, groups := testBudget()
+	p := &Plan{
+		Months: []Month{{Month: "2025-01", Categories: []Category{
+			{Group: "everyday", Name: "groceries", Budgeted: 450.5},
+			{ID: "rent", Budgeted: 1000},
+		}}},
+		Goals: []Goal{{Group: "Bills", Name: "Rent", Target: 1100}},
+	}
+	changes, err := Diff(p, map[string]*ynab.Month{"2025-01": month}, groups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("changes = %+v", changes)
+	}
+	if c := changes[0]; c.CategoryID != "food" || c.Field != "budgeted" || c.After != 450500 || c.Month != "2025-01" {
+		t.Errorf("budgeted change = %+v", c)
+	}
+	if c := changes[1]; c.CategoryID != "rent" || c.Field != "goal_target" || c.Before != 1000000 || c.After != 1100000 {
+		t.Errorf("goal change = %+v", c)
+	}
+
+	p.Months[0].Categories = append(p.Months[0].Categories, Category{Group: "Bills", Name: "Typo"})
+	if _, err := Diff(p, map[string]*ynab.Month{"2025-01": month}, groups); err == nil {
+		t.Error("unknown category should fail")
+	}
+}
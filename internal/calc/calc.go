@@ -0,0 +1,154 @@
+// Package calc evaluates the simple arithmetic accepted for amounts, so
+// "-(3*129.90+45)" can be typed straight from a receipt.
+package calc
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Eval evaluates expr: numbers, + - * /, unary minus and parentheses,
+// with the usual precedence. A decimal comma is accepted as well as a
+// point. The result is rounded to three decimals, the precision of YNAB
+// milliunits.
+func Eval(expr string) (float64, error) {
+	p := &parser{src: strings.ReplaceAll(expr, ",", ".")}
+	p.skipSpace()
+	if p.done() {
+		return 0, fmt.Errorf("empty expression")
+	}
+	v, err := p.expr()
+	if err != nil {
+		return 0, err
+	}
+	if !p.done() {
+		return 0, fmt.Errorf("unexpected %q at position %d", p.src[p.pos], p.pos+1)
+	}
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0, fmt.Errorf("result is not a number")
+	}
+	return math.Round(v*1000) / 1000, nil
+}
+
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) skipSpace() {
+	for !p.done() && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// accept consumes c if it is the next character
+func (p *parser) accept(c byte) bool {
+	if !p.done() && p.src[p.pos] == c {
+		p.pos++
+		p.skipSpace()
+		return true
+	}
+	return false
+}
+
+// expr = term { ("+" | "-") term }
+func (p *parser) expr() (float64, error) {
+	v, err := p.term()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		switch {
+		case p.accept('+'):
+			w, err := p.term()
+			if err != nil {
+				return 0, err
+			}
+			v += w
+		case p.accept('-'):
+			w, err := p.term()
+			if err != nil {
+				return 0, err
+			}
+			v -= w
+		default:
+			return v, nil
+		}
+	}
+}
+
+// term = factor { ("*" | "/") factor }
+func (p *parser) term() (float64, error) {
+	v, err := p.factor()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		switch {
+		case p.accept('*'):
+			w, err := p.factor()
+			if err != nil {
+				return 0, err
+			}
+			v *= w
+		case p.accept('/'):
+			at := p.pos
+			w, err := p.factor()
+			if err != nil {
+				return 0, err
+			}
+			if w == 0 {
+				return 0, fmt.Errorf("division by zero at position %d", at+1)
+			}
+			v /= w
+		default:
+			return v, nil
+		}
+	}
+}
+
+// factor = ("-" | "+") factor | "(" expr ")" | number
+func (p *parser) factor() (float64, error) {
+	switch {
+	case p.accept('-'):
+		v, err := p.factor()
+		return -v, err
+	case p.accept('+'):
+		return p.factor()
+	case p.accept('('):
+		v, err := p.expr()
+		if err != nil {
+			return 0, err
+		}
+		if !p.accept(')') {
+			return 0, fmt.Errorf("missing ) at position %d", p.pos+1)
+		}
+		return v, nil
+	}
+	return p.number()
+}
+
+func (p *parser) number() (float64, error) {
+	start := p.pos
+	for !p.done() && (p.src[p.pos] >= '0' && p.src[p.pos] <= '9' || p.src[p.pos] == '.') {
+		p.pos++
+	}
+	if start == p.pos {
+		if p.done() {
+			return 0, fmt.Errorf("unexpected end of expression")
+		}
+		return 0, fmt.Errorf("unexpected %q at position %d", p.src[p.pos], p.pos+1)
+	}
+	v, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", p.src[start:p.pos])
+	}
+	p.skipSpace()
+	return v, nil
+}
@@ -0,0 +1,36 @@
+package calc
+
+import "testing"
+
+func TestEval(t *testing.T) {
+	cases := map[string]float64{
+		"12.50":           12.5,
+		"-45":             -45,
+		"-(3*129.90+45)":  -434.7,
+		"3 * 129,90":      389.7,
+		"100 - 20 - 30":   50,
+		"2+3*4":           14,
+		"(2+3)*4":         20,
+		"10/3":            3.333,
+		"--5":             5,
+		" -( 1 + 2 ) / 2": -1.5,
+	}
+	for expr, want := range cases {
+		got, err := Eval(expr)
+		if err != nil {
+			t.Errorf("Eval(%q): %v", expr, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("Eval(%q) = %v, want %v", expr, got, want)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	for _, expr := range []string{"", "1+", "(1+2", "1/0", "1.2.3", "2x", "12 13"} {
+		if _, err := Eval(expr); err == nil {
+			t.Errorf("Eval(%q): expected an error", expr)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// Package chart draws simple bar and pie charts as PNG or SVG images,
+// for reports that are shared with people who will not read a table.
+// Everything is drawn in Go; no plotting tools need to be installed.
+package chart
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/langtind/ynabctl/internal/names"
+)
+
+// Chart kinds
+const (
+	KindBar = "bar"
+	KindPie = "pie"
+)
+
+// Item is one labelled value of a chart
+type Item struct {
+	Label string
+	Value float64
+}
+
+// Chart is a bar or pie chart of positive values
+type Chart struct {
+	Title string
+	Kind  string
+	Items []Item
+}
+
+// Layout
+const (
+	width      = 800
+	titleH     = 48
+	barRowH    = 28
+	labelW     = 210
+	barMaxW    = 430
+	pieRadius  = 170
+	legendRowH = 24
+	labelWidth = 30 // label length in characters
+)
+
+// palette is the fill color of successive items
+var palette = []color.RGBA{
+	{0x4e, 0x79, 0xa7, 0xff}, {0xf2, 0x8e, 0x2b, 0xff}, {0xe1, 0x57, 0x59, 0xff},
+	{0x76, 0xb7, 0xb2, 0xff}, {0x59, 0xa1, 0x4f, 0xff}, {0xed, 0xc9, 0x48, 0xff},
+	{0xb0, 0x7a, 0xa1, 0xff}, {0xff, 0x9d, 0xa7, 0xff}, {0x9c, 0x75, 0x5f, 0xff},
+	{0xba, 0xb0, 0xac, 0xff}, {0x86, 0xbc, 0xb6, 0xff}, {0xd3, 0x72, 0x95, 0xff},
+}
+
+// Top keeps the n largest items, sorted largest first, and sums the rest
+// into one item labelled other
+func Top(items []Item, n int, other string) []Item {
+	sorted := append([]Item(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Value > sorted[j].Value })
+	if n <= 0 || len(sorted) <= n {
+		return sorted
+	}
+	rest := Item{Label: other}
+	for _, it := range sorted[n-1:] {
+		rest.Value += it.Value
+	}
+	return append(sorted[:n-1], rest)
+}
+
+// WriteFile writes c to path as PNG or SVG, chosen by its extension
+func WriteFile(path string, c *Chart) error {
+	var write func(io.Writer) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		write = c.PNG
+	case ".svg":
+		write = c.SVG
+	default:
+		return fmt.Errorf("unsupported chart file %q (use .png or .svg)", path)
+	}
+	if err := c.validate(); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (c *Chart) validate() error {
+	switch c.Kind {
+	case KindBar, KindPie:
+	default:
+		return fmt.Errorf("unknown chart type %q (want bar|pie)", c.Kind)
+	}
+	for _, it := range c.Items {
+		if it.Value < 0 {
+			return fmt.Errorf("chart values must not be negative (%s: %.2f)", it.Label, it.Value)
+		}
+	}
+	return nil
+}
+
+// height returns the image height of c
+func (c *Chart) height() int {
+	if c.Kind == KindPie {
+		return max(titleH+2*pieRadius+40, titleH+20+len(c.Items)*legendRowH)
+	}
+	return titleH + len(c.Items)*barRowH + 20
+}
+
+// total returns the sum of the values of c
+func (c *Chart) total() float64 {
+	var t float64
+	for _, it := range c.Items {
+		t += it.Value
+	}
+	return t
+}
+
+// largest returns the largest value of c
+func (c *Chart) largest() float64 {
+	var m float64
+	for _, it := range c.Items {
+		m = max(m, it.Value)
+	}
+	return m
+}
+
+// label shortens an item label to fit its column
+func label(s string) string {
+	return names.Truncate(s, labelWidth)
+}
+
+// legend returns the legend text of an item of a pie chart
+func legend(it Item, total float64) string {
+	pct := 0.0
+	if total > 0 {
+		pct = it.Value / total * 100
+	}
+	return fmt.Sprintf("%s: %.2f (%.0f%%)", label(it.Label), it.Value, pct)
+}
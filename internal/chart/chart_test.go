@@ -0,0 +1,80 @@
+package chart
+
+import (
+	"bytes"
+	"image/png"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTop(t *testing.T) {
+	items := []Item{{"a", 1}, {"b", 5}, {"c", 3}, {"d", 2}}
+	got := Top(items, 3, "Other")
+	if len(got) != 3 || got[0].Label != "b" || got[1].Label != "c" {
+		t.Fatalf("Top = %+v", got)
+	}
+	if got[2].Label != "Other" || got[2].Value != 3 {
+		t.Errorf("rest = %+v, want Other 3", got[2])
+	}
+	if got := Top(items, 10, "Other"); len(got) != 4 {
+		t.Errorf("Top with room for all = %+v", got)
+	}
+}
+
+func TestSVG(t *testing.T) {
+	for _, kind := range []string{KindBar, KindPie} {
+		c := &Chart{Title: "Spending <July>", Kind: kind, Items: []Item{{"🛒 Groceries", 300}, {"Rent & bills", 100}}}
+		var buf bytes.Buffer
+		if err := c.SVG(&buf); err != nil {
+			t.Fatal(err)
+		}
+		svg := buf.String()
+		for _, want := range []string{"<svg", "Spending &lt;July&gt;", "Rent &amp; bills", "🛒 Groceries"} {
+			if !strings.Contains(svg, want) {
+				t.Errorf("%s chart lacks %q", kind, want)
+			}
+		}
+	}
+
+	whole := &Chart{Kind: KindPie, Items: []Item{{"all", 5}}}
+	var buf bytes.Buffer
+	if err := whole.SVG(&buf); err != nil || !strings.Contains(buf.String(), "<circle") {
+		t.Errorf("a single slice should be a full circle: %v", err)
+	}
+}
+
+func TestPNG(t *testing.T) {
+	c := &Chart{Title: "Spending", Kind: KindPie, Items: []Item{{"Mat og drikke", 300}, {"Bolig", 100}}}
+	var buf bytes.Buffer
+	if err := c.PNG(&buf); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != width || b.Dy() != c.height() {
+		t.Errorf("image is %v", b)
+	}
+	// the top right quarter of the pie is the first slice
+	if r, g, b, _ := img.At(20+pieRadius+50, titleH+10+pieRadius-50).RGBA(); r>>8 != uint32(palette[0].R) || g>>8 != uint32(palette[0].G) || b>>8 != uint32(palette[0].B) {
+		t.Errorf("unexpected color in the first slice: %d %d %d", r>>8, g>>8, b>>8)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	c := &Chart{Title: "x", Kind: KindBar, Items: []Item{{"a", 1}}}
+	dir := t.TempDir()
+	for _, name := range []string{"c.png", "c.SVG"} {
+		if err := WriteFile(filepath.Join(dir, name), c); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	if err := WriteFile(filepath.Join(dir, "c.jpg"), c); err == nil {
+		t.Error("expected an error for .jpg")
+	}
+	if err := WriteFile(filepath.Join(dir, "d.png"), &Chart{Kind: "line"}); err == nil {
+		t.Error("expected an error for an unknown kind")
+	}
+}
@@ -0,0 +1,150 @@
+package chart
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"sync"
+
+	"github.com/langtind/ynabctl/internal/names"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+var (
+	faceOnce sync.Once
+	faces    map[float64]font.Face
+	faceErr  error
+)
+
+// face returns the Go Regular font at size points
+func face(size float64) (font.Face, error) {
+	faceOnce.Do(func() {
+		f, err := opentype.Parse(goregular.TTF)
+		if err != nil {
+			faceErr = err
+			return
+		}
+		faces = map[float64]font.Face{}
+		for _, s := range []float64{13, 16} {
+			if faces[s], err = opentype.NewFace(f, &opentype.FaceOptions{Size: s, DPI: 72, Hinting: font.HintingFull}); err != nil {
+				faceErr = err
+				return
+			}
+		}
+	})
+	if faceErr != nil {
+		return nil, fmt.Errorf("failed to load font: %w", faceErr)
+	}
+	return faces[size], nil
+}
+
+// canvas is an image with text drawing
+type canvas struct {
+	img  *image.RGBA
+	text font.Face
+}
+
+// PNG writes c as a PNG image. Emojis are left out of labels, since the
+// font has none.
+func (c *Chart) PNG(w io.Writer) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	text, err := face(13)
+	if err != nil {
+		return err
+	}
+	title, err := face(16)
+	if err != nil {
+		return err
+	}
+
+	cv := &canvas{img: image.NewRGBA(image.Rect(0, 0, width, c.height())), text: text}
+	draw.Draw(cv.img, cv.img.Bounds(), image.White, image.Point{}, draw.Src)
+	cv.write(title, 20, 30, c.Title, false)
+
+	if c.Kind == KindPie {
+		c.pngPie(cv)
+	} else {
+		c.pngBars(cv)
+	}
+	return png.Encode(w, cv.img)
+}
+
+func (c *Chart) pngBars(cv *canvas) {
+	largest := c.largest()
+	for i, it := range c.Items {
+		y := titleH + i*barRowH
+		barW := 0
+		if largest > 0 {
+			barW = int(math.Round(it.Value / largest * barMaxW))
+		}
+		cv.write(cv.text, labelW-10, y+17, label(it.Label), true)
+		cv.fill(image.Rect(labelW, y+4, labelW+barW, y+barRowH-4), palette[0])
+		cv.write(cv.text, labelW+barW+8, y+17, fmt.Sprintf("%.2f", it.Value), false)
+	}
+}
+
+func (c *Chart) pngPie(cv *canvas) {
+	total := c.total()
+	cx, cy := 20+pieRadius, titleH+10+pieRadius
+
+	// the end angle of each slice, clockwise from the top
+	ends := make([]float64, len(c.Items))
+	acc := 0.0
+	for i, it := range c.Items {
+		if total > 0 {
+			acc += it.Value / total * 2 * math.Pi
+		}
+		ends[i] = acc
+	}
+	if total > 0 {
+		for y := cy - pieRadius; y <= cy+pieRadius; y++ {
+			for x := cx - pieRadius; x <= cx+pieRadius; x++ {
+				dx, dy := float64(x-cx), float64(y-cy)
+				if dx*dx+dy*dy > pieRadius*pieRadius {
+					continue
+				}
+				a := math.Atan2(dx, -dy)
+				if a < 0 {
+					a += 2 * math.Pi
+				}
+				for i, end := range ends {
+					if a <= end {
+						cv.img.SetRGBA(x, y, palette[i%len(palette)])
+						break
+					}
+				}
+			}
+		}
+	}
+
+	for i, it := range c.Items {
+		y := titleH + 20 + i*legendRowH
+		cv.fill(image.Rect(2*pieRadius+60, y, 2*pieRadius+74, y+14), palette[i%len(palette)])
+		cv.write(cv.text, 2*pieRadius+82, y+12, legend(it, total), false)
+	}
+}
+
+func (cv *canvas) fill(r image.Rectangle, c color.RGBA) {
+	draw.Draw(cv.img, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// write draws s with its baseline at y, starting at x or, with
+// alignRight, ending at x
+func (cv *canvas) write(f font.Face, x, y int, s string, alignRight bool) {
+	s = names.StripEmoji(s)
+	d := &font.Drawer{Dst: cv.img, Src: image.Black, Face: f}
+	if alignRight {
+		x -= d.MeasureString(s).Round()
+	}
+	d.Dot = fixed.P(x, y)
+	d.DrawString(s)
+}
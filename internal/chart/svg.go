@@ -0,0 +1,82 @@
+package chart
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"image/color"
+	"io"
+	"math"
+)
+
+// SVG writes c as an SVG image
+func (c *Chart) SVG(w io.Writer) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	b := bufio.NewWriter(w)
+	h := c.height()
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="13">`+"\n", width, h, width, h)
+	fmt.Fprintf(b, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", width, h)
+	fmt.Fprintf(b, `<text x="20" y="30" font-size="16" font-weight="bold">%s</text>`+"\n", html.EscapeString(c.Title))
+
+	if c.Kind == KindPie {
+		c.svgPie(b)
+	} else {
+		c.svgBars(b)
+	}
+	fmt.Fprintln(b, "</svg>")
+	return b.Flush()
+}
+
+func (c *Chart) svgBars(b *bufio.Writer) {
+	largest := c.largest()
+	for i, it := range c.Items {
+		y := titleH + i*barRowH
+		barW := 0.0
+		if largest > 0 {
+			barW = it.Value / largest * barMaxW
+		}
+		fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="end">%s</text>`+"\n", labelW-10, y+17, html.EscapeString(label(it.Label)))
+		fmt.Fprintf(b, `<rect x="%d" y="%d" width="%.1f" height="%d" fill="%s"/>`+"\n", labelW, y+4, barW, barRowH-8, hex(palette[0]))
+		fmt.Fprintf(b, `<text x="%.1f" y="%d">%.2f</text>`+"\n", float64(labelW)+barW+8, y+17, it.Value)
+	}
+}
+
+func (c *Chart) svgPie(b *bufio.Writer) {
+	total := c.total()
+	cx, cy := float64(20+pieRadius), float64(titleH+10+pieRadius)
+	start := 0.0
+	for i, it := range c.Items {
+		fill := hex(palette[i%len(palette)])
+		if total > 0 && it.Value > 0 {
+			sweep := it.Value / total * 2 * math.Pi
+			if sweep >= 2*math.Pi-1e-9 {
+				fmt.Fprintf(b, `<circle cx="%.1f" cy="%.1f" r="%d" fill="%s"/>`+"\n", cx, cy, pieRadius, fill)
+			} else {
+				x1, y1 := pointOn(cx, cy, start)
+				x2, y2 := pointOn(cx, cy, start+sweep)
+				large := 0
+				if sweep > math.Pi {
+					large = 1
+				}
+				fmt.Fprintf(b, `<path d="M %.1f %.1f L %.1f %.1f A %d %d 0 %d 1 %.1f %.1f Z" fill="%s"/>`+"\n",
+					cx, cy, x1, y1, pieRadius, pieRadius, large, x2, y2, fill)
+			}
+			start += sweep
+		}
+		y := titleH + 20 + i*legendRowH
+		fmt.Fprintf(b, `<rect x="%d" y="%d" width="14" height="14" fill="%s"/>`+"\n", 2*pieRadius+60, y, fill)
+		fmt.Fprintf(b, `<text x="%d" y="%d">%s</text>`+"\n", 2*pieRadius+82, y+12, html.EscapeString(legend(it, total)))
+	}
+}
+
+// pointOn returns the point of the pie's edge at angle a, measured
+// clockwise from the top
+func pointOn(cx, cy, a float64) (float64, float64) {
+	return cx + pieRadius*math.Sin(a), cy - pieRadius*math.Cos(a)
+}
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
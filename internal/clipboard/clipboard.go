@@ -0,0 +1,46 @@
+// Package clipboard writes text to the system clipboard using the
+// platform's command-line clipboard tool.
+package clipboard
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// commands lists clipboard tools to try, in order, per platform.
+var commands = map[string][][]string{
+	"darwin":  {{"pbcopy"}},
+	"windows": {{"clip.exe"}},
+	"linux": {
+		{"wl-copy"},
+		{"xclip", "-selection", "clipboard"},
+		{"xsel", "--clipboard", "--input"},
+	},
+}
+
+// ErrUnavailable is returned when no clipboard tool is installed.
+var ErrUnavailable = errors.New("no clipboard tool found (install wl-copy, xclip, or xsel)")
+
+// Write replaces the clipboard contents with text.
+func Write(text string) error {
+	candidates, ok := commands[runtime.GOOS]
+	if !ok {
+		candidates = commands["linux"]
+	}
+	for _, c := range candidates {
+		path, err := exec.LookPath(c[0])
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, c[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%s: %w: %s", c[0], err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+	return ErrUnavailable
+}
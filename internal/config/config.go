@@ -4,15 +4,73 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/spf13/viper"
 )
 
 // Config holds the application configuration
 type Config struct {
-	Token         string `mapstructure:"token"`
+	Token string `mapstructure:"token"`
+	// TokenType is "oauth" for an OAuth access token; empty means a
+	// personal access token
+	TokenType     string `mapstructure:"token_type"`
 	DefaultBudget string `mapstructure:"default_budget"`
 	Format        string `mapstructure:"format"`
+	// SavingsCategories are category names or IDs counted as the
+	// emergency fund by "report runway"
+	SavingsCategories []string `mapstructure:"savings_categories"`
+	// FundingPriority are category names or IDs in the order "fund"
+	// gives them money, most important first
+	FundingPriority []string `mapstructure:"funding_priority"`
+	// ExcludeAccounts and ExcludeCategories are account and category
+	// names or IDs left out of reports unless --include-all is given
+	ExcludeAccounts   []string `mapstructure:"exclude_accounts"`
+	ExcludeCategories []string `mapstructure:"exclude_categories"`
+	// ProtectedBudgets are budget IDs that mutating commands only change
+	// with --force or after the budget name is typed to confirm
+	ProtectedBudgets []string `mapstructure:"protected_budgets"`
+	// GroupCaps are monthly spending caps per category group, checked
+	// by "caps status"
+	GroupCaps []GroupCap `mapstructure:"group_caps"`
+	// Allowances map family members to the category holding their
+	// allowance, used by "allowance status" and "allowance credit"
+	Allowances []Allowance `mapstructure:"allowances"`
+	// Lang is the language of messages and table headers ("en" or "nb");
+	// empty follows the locale
+	Lang string `mapstructure:"lang"`
+	// CABundle is a PEM file of extra certificates to trust, e.g. the CA
+	// of a corporate proxy that inspects TLS
+	CABundle string `mapstructure:"ca_bundle"`
+	// CacheTTL is how long saved GET responses are served without a
+	// request, as a duration such as "5m"; empty turns this off
+	CacheTTL string `mapstructure:"cache_ttl"`
+	// Aliases are short names for IDs or names, used as "@name" wherever
+	// a budget, account, category or payee is accepted
+	Aliases map[string]string `mapstructure:"aliases"`
+	// Defaults are flag values per command, in nested tables named after
+	// the command path, e.g. [defaults.transactions.list]
+	Defaults map[string]interface{} `mapstructure:"defaults"`
+}
+
+// GroupCap is the monthly spending cap of a category group, in currency
+// units. WarnAt is the share of the cap (0-1) at which the group is
+// flagged; zero uses the default of 0.8.
+type GroupCap struct {
+	Group  string  `mapstructure:"group"`
+	Cap    float64 `mapstructure:"cap"`
+	WarnAt float64 `mapstructure:"warn_at"`
+}
+
+// Allowance is a family member's weekly allowance, in currency units.
+// Category is a name or ID; From is the category the money is moved
+// from, or empty to assign it from Ready to Assign.
+type Allowance struct {
+	Member   string  `mapstructure:"member"`
+	Category string  `mapstructure:"category"`
+	Weekly   float64 `mapstructure:"weekly"`
+	From     string  `mapstructure:"from"`
 }
 
 var configDir string
@@ -42,8 +100,11 @@ func Load() (*Config, error) {
 
 	// Map environment variables
 	v.BindEnv("token", "YNAB_TOKEN")
+	v.BindEnv("token_type", "YNAB_TOKEN_TYPE")
 	v.BindEnv("default_budget", "YNAB_DEFAULT_BUDGET")
 	v.BindEnv("format", "YNAB_FORMAT")
+	v.BindEnv("ca_bundle", "YNAB_CA_BUNDLE")
+	v.BindEnv("cache_ttl", "YNAB_CACHE_TTL")
 
 	// Set defaults
 	v.SetDefault("format", "json")
@@ -63,6 +124,16 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
 	}
 
+	// YNAB_TOKEN_FILE wins over YNAB_TOKEN and the config file, so
+	// secrets can be mounted as files in CI
+	if path := os.Getenv("YNAB_TOKEN_FILE"); path != "" {
+		token, err := ReadTokenFile(path)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Token = token
+	}
+
 	return &cfg, nil
 }
 
@@ -78,30 +149,112 @@ func Save(cfg *Config) error {
 	v.SetConfigType("toml")
 
 	v.Set("token", cfg.Token)
+	if cfg.TokenType != "" {
+		v.Set("token_type", cfg.TokenType)
+	}
 	v.Set("default_budget", cfg.DefaultBudget)
 	v.Set("format", cfg.Format)
+	if len(cfg.SavingsCategories) > 0 {
+		v.Set("savings_categories", cfg.SavingsCategories)
+	}
+	if len(cfg.FundingPriority) > 0 {
+		v.Set("funding_priority", cfg.FundingPriority)
+	}
+	if len(cfg.ExcludeAccounts) > 0 {
+		v.Set("exclude_accounts", cfg.ExcludeAccounts)
+	}
+	if len(cfg.ExcludeCategories) > 0 {
+		v.Set("exclude_categories", cfg.ExcludeCategories)
+	}
+	if len(cfg.ProtectedBudgets) > 0 {
+		v.Set("protected_budgets", cfg.ProtectedBudgets)
+	}
+	if len(cfg.GroupCaps) > 0 {
+		caps := make([]map[string]interface{}, len(cfg.GroupCaps))
+		for i, c := range cfg.GroupCaps {
+			caps[i] = map[string]interface{}{"group": c.Group, "cap": c.Cap}
+			if c.WarnAt != 0 {
+				caps[i]["warn_at"] = c.WarnAt
+			}
+		}
+		v.Set("group_caps", caps)
+	}
+	if len(cfg.Allowances) > 0 {
+		allowances := make([]map[string]interface{}, len(cfg.Allowances))
+		for i, a := range cfg.Allowances {
+			allowances[i] = map[string]interface{}{"member": a.Member, "category": a.Category, "weekly": a.Weekly}
+			if a.From != "" {
+				allowances[i]["from"] = a.From
+			}
+		}
+		v.Set("allowances", allowances)
+	}
+	if cfg.Lang != "" {
+		v.Set("lang", cfg.Lang)
+	}
+	if cfg.CABundle != "" {
+		v.Set("ca_bundle", cfg.CABundle)
+	}
+	if cfg.CacheTTL != "" {
+		v.Set("cache_ttl", cfg.CacheTTL)
+	}
+	if len(cfg.Aliases) > 0 {
+		v.Set("aliases", cfg.Aliases)
+	}
+	if len(cfg.Defaults) > 0 {
+		v.Set("defaults", cfg.Defaults)
+	}
 
 	if err := v.WriteConfig(); err != nil {
 		// If config file doesn't exist, create it
-		if os.IsNotExist(err) {
-			return v.SafeWriteConfig()
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to write config: %w", err)
+		}
+		if err := v.SafeWriteConfig(); err != nil {
+			return err
 		}
-		return fmt.Errorf("failed to write config: %w", err)
 	}
 
-	return nil
+	// the file holds the token, keep it private
+	return os.Chmod(configFile, 0o600)
 }
 
-// SetToken saves the API token to config
-func SetToken(token string) error {
+// SetToken saves the API token and its type ("" or "oauth") to config
+func SetToken(token, tokenType string) error {
 	cfg, err := Load()
 	if err != nil {
 		cfg = &Config{}
 	}
 	cfg.Token = token
+	cfg.TokenType = tokenType
 	return Save(cfg)
 }
 
+// ReadTokenFile reads a token from a file, ignoring surrounding
+// whitespace
+func ReadTokenFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", path)
+	}
+	return token, nil
+}
+
+// TokenSource describes where the token loaded by Load comes from
+func TokenSource() string {
+	if path := os.Getenv("YNAB_TOKEN_FILE"); path != "" {
+		return path + " (YNAB_TOKEN_FILE)"
+	}
+	if os.Getenv("YNAB_TOKEN") != "" {
+		return "YNAB_TOKEN environment variable"
+	}
+	return configFile
+}
+
 // SetDefaultBudget saves the default budget ID to config
 func SetDefaultBudget(budgetID string) error {
 	cfg, err := Load()
@@ -122,7 +275,193 @@ func SetFormat(format string) error {
 	return Save(cfg)
 }
 
+// SetLang saves the message language to config; empty follows the
+// locale
+func SetLang(lang string) error {
+	cfg, err := Load()
+	if err != nil {
+		cfg = &Config{}
+	}
+	cfg.Lang = lang
+	return Save(cfg)
+}
+
+// SetCABundle saves the CA bundle trusted for API requests; empty
+// trusts the system roots only
+func SetCABundle(path string) error {
+	cfg, err := Load()
+	if err != nil {
+		cfg = &Config{}
+	}
+	cfg.CABundle = path
+	return Save(cfg)
+}
+
+// SetCacheTTL saves how long GET responses are served from the cache;
+// empty turns the cache off
+func SetCacheTTL(ttl string) error {
+	cfg, err := Load()
+	if err != nil {
+		cfg = &Config{}
+	}
+	cfg.CacheTTL = ttl
+	return Save(cfg)
+}
+
+// SetSavingsCategories saves the categories counted as savings by
+// "report runway"
+func SetSavingsCategories(categories []string) error {
+	cfg, err := Load()
+	if err != nil {
+		cfg = &Config{}
+	}
+	cfg.SavingsCategories = categories
+	return Save(cfg)
+}
+
+// SetFundingPriority saves the categories "fund" gives money to, in
+// order
+func SetFundingPriority(categories []string) error {
+	cfg, err := Load()
+	if err != nil {
+		cfg = &Config{}
+	}
+	cfg.FundingPriority = categories
+	return Save(cfg)
+}
+
+// SetReportExclusions saves the accounts and categories left out of
+// reports
+func SetReportExclusions(accounts, categories []string) error {
+	cfg, err := Load()
+	if err != nil {
+		cfg = &Config{}
+	}
+	cfg.ExcludeAccounts = accounts
+	cfg.ExcludeCategories = categories
+	return Save(cfg)
+}
+
+// SetGroupCaps saves the spending caps of category groups
+func SetGroupCaps(caps []GroupCap) error {
+	cfg, err := Load()
+	if err != nil {
+		cfg = &Config{}
+	}
+	cfg.GroupCaps = caps
+	return Save(cfg)
+}
+
+// SetAllowances saves the allowances of family members
+func SetAllowances(allowances []Allowance) error {
+	cfg, err := Load()
+	if err != nil {
+		cfg = &Config{}
+	}
+	cfg.Allowances = allowances
+	return Save(cfg)
+}
+
+// SetProtectedBudgets saves the budget IDs guarded against accidental
+// changes
+func SetProtectedBudgets(budgetIDs []string) error {
+	cfg, err := Load()
+	if err != nil {
+		cfg = &Config{}
+	}
+	cfg.ProtectedBudgets = budgetIDs
+	return Save(cfg)
+}
+
+// reAliasName matches a valid alias name. Dots would nest TOML tables.
+var reAliasName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// SetAlias saves an alias for an ID or name; an empty target removes it
+func SetAlias(name, target string) error {
+	if !reAliasName.MatchString(name) {
+		return fmt.Errorf("invalid alias name %q: use letters, digits, - and _", name)
+	}
+	cfg, err := Load()
+	if err != nil {
+		cfg = &Config{}
+	}
+	if cfg.Aliases == nil {
+		cfg.Aliases = map[string]string{}
+	}
+	if target == "" {
+		delete(cfg.Aliases, strings.ToLower(name))
+	} else {
+		cfg.Aliases[strings.ToLower(name)] = target
+	}
+	return Save(cfg)
+}
+
+// ExpandAlias returns what an "@name" value is an alias for. Alias names
+// are not case sensitive. Other values are returned unchanged.
+func (c *Config) ExpandAlias(value string) (string, error) {
+	name, ok := strings.CutPrefix(value, "@")
+	if !ok {
+		return value, nil
+	}
+	if target, ok := c.Aliases[strings.ToLower(name)]; ok {
+		return target, nil
+	}
+	return "", fmt.Errorf("unknown alias %q (define it with 'ynabctl config set-alias %s <id>')", value, name)
+}
+
+// CommandDefaults returns the default flag values of the command with
+// the given path below the root, e.g. ["transactions", "list"]. Tables
+// of subcommands nested in its section are left out.
+func (c *Config) CommandDefaults(path []string) map[string]interface{} {
+	section := c.Defaults
+	for _, name := range path {
+		next, ok := section[strings.ToLower(name)].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		section = next
+	}
+	flags := map[string]interface{}{}
+	for name, value := range section {
+		if _, table := value.(map[string]interface{}); !table {
+			flags[name] = value
+		}
+	}
+	return flags
+}
+
+// IsProtected reports whether budgetID is a protected budget
+func (c *Config) IsProtected(budgetID string) bool {
+	for _, id := range c.ProtectedBudgets {
+		if id == budgetID {
+			return true
+		}
+	}
+	return false
+}
+
 // GetConfigFile returns the path to the config file
 func GetConfigFile() string {
 	return configFile
 }
+
+// Dir returns the directory holding the config file and other
+// ynabctl state files
+func Dir() string {
+	return configDir
+}
+
+// StateDir returns the directory for ynabctl state such as the audit log
+// and job checkpoints, honoring XDG_STATE_HOME and defaulting to
+// ~/.local/state/ynabctl
+func StateDir() string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			home = "."
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "ynabctl")
+}
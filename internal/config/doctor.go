@@ -0,0 +1,233 @@
+package config
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/langtind/ynabctl/internal/i18n"
+	"github.com/spf13/viper"
+)
+
+// Setting is one effective configuration value and where it came from:
+// "flag", "env", "file" or "default"
+type Setting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Origin string `json:"origin"`
+}
+
+// Problem is something wrong with the configuration. Fixed is set when
+// Diagnose repaired it.
+type Problem struct {
+	Key     string `json:"key"`
+	Message string `json:"message"`
+	Fixed   bool   `json:"fixed,omitempty"`
+}
+
+// Diagnosis is the result of Diagnose
+type Diagnosis struct {
+	ConfigFile string    `json:"config_file"`
+	Settings   []Setting `json:"settings"`
+	Problems   []Problem `json:"problems"`
+}
+
+// Flags are the global flag values that override the configuration
+type Flags struct {
+	Token     string
+	TokenFile string
+	Budget    string
+	Format    string
+	Lang      string
+	CABundle  string
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Diagnose reports the effective configuration with the origin of every
+// value and checks it for common problems. A config file readable by
+// other users is made private (0600) when fix is set.
+func Diagnose(flags Flags, fix bool) *Diagnosis {
+	d := &Diagnosis{ConfigFile: configFile, Settings: []Setting{}, Problems: []Problem{}}
+
+	// the file alone, so values can be told apart from env and defaults
+	file := viper.New()
+	file.SetConfigFile(configFile)
+	file.SetConfigType("toml")
+	fileErr := file.ReadInConfig()
+	if fileErr != nil && !os.IsNotExist(fileErr) {
+		d.Problems = append(d.Problems, Problem{Key: "config_file", Message: fmt.Sprintf("cannot be read: %v", fileErr)})
+	}
+
+	// token: --token, --token-file, YNAB_TOKEN_FILE, YNAB_TOKEN, file.
+	// Token files are trimmed when read, the other values are not.
+	token := Setting{Key: "token", Origin: "default"}
+	rawToken, trimmed := "", false
+	switch {
+	case flags.Token != "":
+		rawToken, token.Origin = flags.Token, "flag (--token)"
+	case flags.TokenFile != "":
+		rawToken, token.Origin, trimmed = readRaw(d, flags.TokenFile), "flag (--token-file "+flags.TokenFile+")", true
+	case os.Getenv("YNAB_TOKEN_FILE") != "":
+		path := os.Getenv("YNAB_TOKEN_FILE")
+		rawToken, token.Origin, trimmed = readRaw(d, path), "env (YNAB_TOKEN_FILE="+path+")", true
+	case os.Getenv("YNAB_TOKEN") != "":
+		rawToken, token.Origin = os.Getenv("YNAB_TOKEN"), "env (YNAB_TOKEN)"
+	case file.InConfig("token"):
+		rawToken, token.Origin = file.GetString("token"), "file"
+	}
+	token.Value = MaskToken(strings.TrimSpace(rawToken))
+	d.Settings = append(d.Settings, token)
+	switch {
+	case strings.TrimSpace(rawToken) == "":
+		d.Problems = append(d.Problems, Problem{Key: "token", Message: "no API token configured; run 'ynabctl config set-token <token>'"})
+	case !trimmed && strings.TrimSpace(rawToken) != rawToken:
+		d.Problems = append(d.Problems, Problem{Key: "token", Message: "token has leading or trailing whitespace and will be rejected by the API"})
+	case strings.ContainsAny(strings.TrimSpace(rawToken), " \t\r\n"):
+		d.Problems = append(d.Problems, Problem{Key: "token", Message: "token contains whitespace; was more than the token pasted?"})
+	}
+
+	d.Settings = append(d.Settings, resolve(file, "token_type", "", "YNAB_TOKEN_TYPE", ""))
+	budget := resolve(file, "default_budget", flags.Budget, "YNAB_DEFAULT_BUDGET", "")
+	d.Settings = append(d.Settings, budget)
+	if b := budget.Value; b != "" && b != "last-used" && b != "default" && !uuidPattern.MatchString(b) {
+		d.Problems = append(d.Problems, Problem{Key: "default_budget", Message: fmt.Sprintf("%q is not a budget ID (UUID), \"last-used\" or \"default\"", b)})
+	}
+	format := resolve(file, "format", flags.Format, "YNAB_FORMAT", "json")
+	d.Settings = append(d.Settings, format)
+	if f := format.Value; f != "json" && f != "table" && f != "markdown" {
+		d.Problems = append(d.Problems, Problem{Key: "format", Message: fmt.Sprintf("%q is not json, table or markdown", f)})
+	}
+	lang := resolve(file, "lang", flags.Lang, "YNAB_LANG", "")
+	d.Settings = append(d.Settings, lang)
+	if l := lang.Value; l != "" {
+		if _, ok := i18n.Normalize(l); !ok {
+			d.Problems = append(d.Problems, Problem{Key: "lang", Message: fmt.Sprintf("%q is not a supported language (%s)", l, strings.Join(i18n.Languages(), ", "))})
+		}
+	}
+	caBundle := resolve(file, "ca_bundle", flags.CABundle, "YNAB_CA_BUNDLE", "")
+	d.Settings = append(d.Settings, caBundle)
+	if path := caBundle.Value; path != "" {
+		if data, err := os.ReadFile(path); err != nil {
+			d.Problems = append(d.Problems, Problem{Key: "ca_bundle", Message: fmt.Sprintf("cannot be read: %v", err)})
+		} else if !x509.NewCertPool().AppendCertsFromPEM(data) {
+			d.Problems = append(d.Problems, Problem{Key: "ca_bundle", Message: fmt.Sprintf("%s holds no PEM certificates", path)})
+		}
+	}
+	// proxies only come from the environment, as for other tools
+	for _, env := range []string{"HTTPS_PROXY", "NO_PROXY"} {
+		s := Setting{Key: strings.ToLower(env), Origin: "default"}
+		for _, name := range []string{env, strings.ToLower(env)} {
+			if v := os.Getenv(name); v != "" {
+				s.Value, s.Origin = stripUserinfo(v), "env ("+name+")"
+				break
+			}
+		}
+		d.Settings = append(d.Settings, s)
+	}
+	for _, key := range []string{"savings_categories", "protected_budgets"} {
+		s := Setting{Key: key, Origin: "default"}
+		if file.InConfig(key) {
+			s.Value, s.Origin = strings.Join(file.GetStringSlice(key), ", "), "file"
+		}
+		d.Settings = append(d.Settings, s)
+	}
+	for _, id := range file.GetStringSlice("protected_budgets") {
+		if !uuidPattern.MatchString(id) {
+			d.Problems = append(d.Problems, Problem{Key: "protected_budgets", Message: fmt.Sprintf("%q is not a budget ID (UUID)", id)})
+		}
+	}
+
+	if err := checkWritable(configDir); err != nil {
+		d.Problems = append(d.Problems, Problem{Key: "config_dir", Message: err.Error()})
+	}
+	if info, err := os.Stat(configFile); err == nil && info.Mode().Perm()&0o077 != 0 {
+		p := Problem{Key: "config_file", Message: fmt.Sprintf("permissions are %04o; the token is readable by other users", info.Mode().Perm())}
+		if fix {
+			if err := os.Chmod(configFile, 0o600); err != nil {
+				p.Message += fmt.Sprintf(" (chmod failed: %v)", err)
+			} else {
+				p.Message += "; changed to 0600"
+				p.Fixed = true
+			}
+		}
+		d.Problems = append(d.Problems, p)
+	}
+
+	return d
+}
+
+// resolve finds the effective value of key, checking the flag, the
+// environment variable, the config file and the default in that order
+func resolve(file *viper.Viper, key, flag, env, def string) Setting {
+	switch {
+	case flag != "":
+		return Setting{Key: key, Value: flag, Origin: "flag"}
+	case env != "" && os.Getenv(env) != "":
+		return Setting{Key: key, Value: os.Getenv(env), Origin: "env (" + env + ")"}
+	case file.InConfig(key):
+		return Setting{Key: key, Value: file.GetString(key), Origin: "file"}
+	}
+	return Setting{Key: key, Value: def, Origin: "default"}
+}
+
+// stripUserinfo hides the credentials in a proxy URL
+func stripUserinfo(proxy string) string {
+	u, err := url.Parse(proxy)
+	if err != nil || u.User == nil {
+		return proxy
+	}
+	u.User = url.User("***")
+	return u.String()
+}
+
+// readRaw reads a token file for Diagnose, recording a problem if it
+// cannot be read
+func readRaw(d *Diagnosis, path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		d.Problems = append(d.Problems, Problem{Key: "token", Message: fmt.Sprintf("cannot read token file: %v", err)})
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// checkWritable reports whether files can be created in dir, or in its
+// nearest existing parent if dir does not exist yet
+func checkWritable(dir string) error {
+	for {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", dir)
+			}
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return fmt.Errorf("no existing parent directory of %s", configDir)
+		}
+		dir = parent
+	}
+	f, err := os.CreateTemp(dir, ".ynabctl-doctor-*")
+	if err != nil {
+		return fmt.Errorf("%s is not writable: %v", dir, err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// MaskToken shows only the ends of a token
+func MaskToken(token string) string {
+	switch {
+	case token == "":
+		return "(not set)"
+	case len(token) > 8:
+		return token[:4] + "..." + token[len(token)-4:]
+	}
+	return "****"
+}
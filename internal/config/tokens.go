@@ -0,0 +1,67 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// tokenUsagePath is the file recording when each token was last used.
+// Tokens are stored as fingerprints, never in clear.
+func tokenUsagePath() string {
+	return filepath.Join(StateDir(), "tokens.json")
+}
+
+// TokenFingerprint identifies a token without revealing it
+func TokenFingerprint(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8])
+}
+
+func readTokenUsage() (map[string]time.Time, error) {
+	usage := map[string]time.Time{}
+	data, err := os.ReadFile(tokenUsagePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return usage, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", tokenUsagePath(), err)
+	}
+	return usage, nil
+}
+
+// LastTokenUse returns when ynabctl last made an API request with token.
+// ok is false if it has no record of the token.
+func LastTokenUse(token string) (last time.Time, ok bool, err error) {
+	usage, err := readTokenUsage()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	last, ok = usage[TokenFingerprint(token)]
+	return last, ok, nil
+}
+
+// RecordTokenUse stores t as the last use of token
+func RecordTokenUse(token string, t time.Time) error {
+	usage, err := readTokenUsage()
+	if err != nil {
+		return err
+	}
+	usage[TokenFingerprint(token)] = t.UTC()
+
+	data, err := json.MarshalIndent(usage, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(StateDir(), 0o700); err != nil {
+		return fmt.Errorf("mkdir %s: %w", StateDir(), err)
+	}
+	return os.WriteFile(tokenUsagePath(), data, 0o600)
+}
@@ -0,0 +1,193 @@
+// Package daemon runs ynabctl commands on schedules in one long-running
+// process, for users who would rather not manage cron or Task Scheduler
+// entries, and reports how the jobs are doing over HTTP.
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Runner runs a job, writing its output to out
+type Runner func(ctx context.Context, job Job, out io.Writer) error
+
+// JobStatus is how a job is doing
+type JobStatus struct {
+	Name     string     `json:"name"`
+	Schedule string     `json:"schedule"`
+	Running  bool       `json:"running"`
+	NextRun  time.Time  `json:"next_run"`
+	LastRun  *time.Time `json:"last_run,omitempty"`
+	// LastDuration is how long the last run took, e.g. "1.2s"
+	LastDuration string `json:"last_duration,omitempty"`
+	LastError    string `json:"last_error,omitempty"`
+	Runs         int    `json:"runs"`
+	Failures     int    `json:"failures"`
+}
+
+// Status is the state of the daemon, as served on /status
+type Status struct {
+	Started time.Time   `json:"started"`
+	Jobs    []JobStatus `json:"jobs"`
+}
+
+// Daemon runs jobs on their schedules. Each job runs at most once at a
+// time: a run that takes past the next due time skips it.
+type Daemon struct {
+	jobs []Job
+	run  Runner
+	log  *log.Logger
+	now  func() time.Time
+
+	mu     sync.Mutex
+	status Status
+}
+
+// New returns a daemon running jobs with run and logging to logger
+func New(jobs []Job, run Runner, logger *log.Logger) *Daemon {
+	d := &Daemon{jobs: jobs, run: run, log: logger, now: time.Now}
+	d.status.Jobs = make([]JobStatus, len(jobs))
+	for i, j := range jobs {
+		d.status.Jobs[i] = JobStatus{Name: j.Name, Schedule: j.Schedule.Spec}
+	}
+	return d
+}
+
+// Start runs the jobs until ctx is done. A job still running then is
+// stopped through its context.
+func (d *Daemon) Start(ctx context.Context) error {
+	d.mu.Lock()
+	d.status.Started = d.now()
+	d.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for i := range d.jobs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			d.loop(ctx, i)
+		}(i)
+	}
+	wg.Wait()
+	return nil
+}
+
+// loop waits for each due time of job i and runs it
+func (d *Daemon) loop(ctx context.Context, i int) {
+	job := d.jobs[i]
+	for {
+		next := job.Schedule.Next(d.now())
+		d.mu.Lock()
+		d.status.Jobs[i].NextRun = next
+		d.mu.Unlock()
+		d.log.Printf("%s: next run %s", job.Name, next.Format("2006-01-02 15:04"))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		d.runJob(ctx, i)
+	}
+}
+
+// runJob runs job i once and records the outcome
+func (d *Daemon) runJob(ctx context.Context, i int) {
+	job := d.jobs[i]
+	start := d.now()
+	d.mu.Lock()
+	d.status.Jobs[i].Running = true
+	d.mu.Unlock()
+	d.log.Printf("%s: started", job.Name)
+
+	out := &lineLogger{log: d.log, prefix: job.Name + ": "}
+	err := d.run(ctx, job, out)
+	out.Flush()
+	took := d.now().Sub(start).Round(100 * time.Millisecond)
+
+	d.mu.Lock()
+	s := &d.status.Jobs[i]
+	s.Running = false
+	s.LastRun = &start
+	s.LastDuration = took.String()
+	s.Runs++
+	s.LastError = ""
+	if err != nil {
+		s.Failures++
+		s.LastError = err.Error()
+	}
+	d.mu.Unlock()
+
+	if err != nil {
+		d.log.Printf("%s: failed after %s: %v", job.Name, took, err)
+		return
+	}
+	d.log.Printf("%s: done in %s", job.Name, took)
+}
+
+// Status returns a copy of the daemon's state
+func (d *Daemon) Status() Status {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	s := Status{Started: d.status.Started, Jobs: make([]JobStatus, len(d.status.Jobs))}
+	copy(s.Jobs, d.status.Jobs)
+	return s
+}
+
+// ServeHTTP serves the status as JSON on / and /status
+func (d *Daemon) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" && r.URL.Path != "/status" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(d.Status())
+}
+
+// lineLogger writes each line of a job's output to the log
+type lineLogger struct {
+	log    *log.Logger
+	prefix string
+	mu     sync.Mutex
+	buf    bytes.Buffer
+}
+
+func (l *lineLogger) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buf.Write(p)
+	for {
+		line, err := l.buf.ReadString('\n')
+		if err != nil {
+			// keep the incomplete line for the next write
+			l.buf.Reset()
+			l.buf.WriteString(line)
+			return len(p), nil
+		}
+		l.log.Print(l.prefix + line)
+	}
+}
+
+// Flush logs what is left of an unterminated last line
+func (l *lineLogger) Flush() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.buf.Len() > 0 {
+		l.log.Print(l.prefix + l.buf.String())
+		l.buf.Reset()
+	}
+}
@@ -0,0 +1,189 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	for _, spec := range []string{"hourly", "Daily", "daily@06:30", "weekly", "weekly@07:00", "15m", "6h"} {
+		if _, err := ParseSchedule(spec); err != nil {
+			t.Errorf("ParseSchedule(%q): %v", spec, err)
+		}
+	}
+	for _, spec := range []string{"", "monthly", "hourly@10:00", "daily@25:00", "10s", "5m@01:00"} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q): expected error", spec)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	loc := time.FixedZone("CET", 3600)
+	// a Wednesday
+	now := time.Date(2025, 3, 12, 14, 20, 0, 0, loc)
+	for _, tt := range []struct {
+		spec string
+		want time.Time
+	}{
+		{"hourly", time.Date(2025, 3, 12, 15, 0, 0, 0, loc)},
+		{"daily", time.Date(2025, 3, 13, 0, 0, 0, 0, loc)},
+		{"daily@18:00", time.Date(2025, 3, 12, 18, 0, 0, 0, loc)},
+		{"daily@06:30", time.Date(2025, 3, 13, 6, 30, 0, 0, loc)},
+		{"daily@14:20", time.Date(2025, 3, 13, 14, 20, 0, 0, loc)},
+		{"weekly", time.Date(2025, 3, 17, 0, 0, 0, 0, loc)},
+		{"weekly@07:00", time.Date(2025, 3, 17, 7, 0, 0, 0, loc)},
+		{"30m", time.Date(2025, 3, 12, 14, 50, 0, 0, loc)},
+	} {
+		s, err := ParseSchedule(tt.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := s.Next(now); !got.Equal(tt.want) {
+			t.Errorf("%s: Next = %s, want %s", tt.spec, got, tt.want)
+		}
+	}
+
+	// on a Monday morning, weekly@07:00 is due the same day
+	monday := time.Date(2025, 3, 17, 6, 0, 0, 0, loc)
+	s, _ := ParseSchedule("weekly@07:00")
+	if got := s.Next(monday); !got.Equal(time.Date(2025, 3, 17, 7, 0, 0, 0, loc)) {
+		t.Errorf("weekly on Monday: Next = %s", got)
+	}
+}
+
+func TestParseJobs(t *testing.T) {
+	jobs, err := ParseJobs([]string{
+		"hourly: payees normalize --rules 'my rules.yaml' --yes",
+		" daily@06:00: snapshot --out-dir data",
+		`30m: transactions list --account Checking,Savings --payee "Bob \"the\" Baker"`,
+		"",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 3 {
+		t.Fatalf("jobs = %+v", jobs)
+	}
+	if jobs[0].Name != "payees normalize --rules 'my rules.yaml' --yes" || jobs[0].Schedule.Spec != "hourly" ||
+		!reflect.DeepEqual(jobs[0].Args, []string{"payees", "normalize", "--rules", "my rules.yaml", "--yes"}) {
+		t.Errorf("job 0 = %+v", jobs[0])
+	}
+	if jobs[1].Args[0] != "snapshot" || jobs[1].Schedule.Spec != "daily@06:00" {
+		t.Errorf("job 1 = %+v", jobs[1])
+	}
+	if want := []string{"transactions", "list", "--account", "Checking,Savings", "--payee", `Bob "the" Baker`}; !reflect.DeepEqual(jobs[2].Args, want) {
+		t.Errorf("job 2 args = %q, want %q", jobs[2].Args, want)
+	}
+
+	for _, entries := range [][]string{{"snapshot"}, {"daily: "}, {"yearly: snapshot"}, {"daily: memo 'open"}, {"snapshot=daily"}, {}} {
+		if _, err := ParseJobs(entries); err == nil {
+			t.Errorf("ParseJobs(%q): expected error", entries)
+		}
+	}
+}
+
+func TestRunJob(t *testing.T) {
+	jobs, _ := ParseJobs([]string{"daily: doctor references", "hourly: snapshot"})
+	var logBuf bytes.Buffer
+	d := New(jobs, func(ctx context.Context, job Job, out io.Writer) error {
+		fmt.Fprint(out, "line one\nline two")
+		if job.Name == "snapshot" {
+			return errors.New("exit status 1")
+		}
+		return nil
+	}, log.New(&logBuf, "", 0))
+
+	d.runJob(context.Background(), 0)
+	d.runJob(context.Background(), 1)
+	d.runJob(context.Background(), 1)
+
+	s := d.Status()
+	if s.Jobs[0].Runs != 1 || s.Jobs[0].Failures != 0 || s.Jobs[0].LastError != "" || s.Jobs[0].LastRun == nil {
+		t.Errorf("job 0 status = %+v", s.Jobs[0])
+	}
+	if s.Jobs[1].Runs != 2 || s.Jobs[1].Failures != 2 || s.Jobs[1].LastError != "exit status 1" {
+		t.Errorf("job 1 status = %+v", s.Jobs[1])
+	}
+	logged := logBuf.String()
+	for _, want := range []string{"doctor references: line one\n", "doctor references: line two\n", "snapshot: failed after"} {
+		if !strings.Contains(logged, want) {
+			t.Errorf("log lacks %q:\n%s", want, logged)
+		}
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	jobs, _ := ParseJobs([]string{"daily: snapshot"})
+	d := New(jobs, nil, log.New(io.Discard, "", 0))
+
+	rec := httptest.NewRecorder()
+	d.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status code = %d", rec.Code)
+	}
+	var s Status
+	if err := json.Unmarshal(rec.Body.Bytes(), &s); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Jobs) != 1 || s.Jobs[0].Name != "snapshot" || s.Jobs[0].Schedule != "daily" {
+		t.Errorf("status = %+v", s)
+	}
+
+	rec = httptest.NewRecorder()
+	d.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/other", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("/other: code = %d", rec.Code)
+	}
+}
+
+func TestStartStops(t *testing.T) {
+	jobs, _ := ParseJobs([]string{"daily: snapshot"})
+	d := New(jobs, nil, log.New(io.Discard, "", 0))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- d.Start(ctx) }()
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Start did not return after cancel")
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	for line, want := range map[string][]string{
+		`snapshot --out-dir data`:     {"snapshot", "--out-dir", "data"},
+		`  a   b  `:                   {"a", "b"},
+		`--rules "my rules.yaml"`:     {"--rules", "my rules.yaml"},
+		`--memo 'it''s' x`:            {"--memo", "its", "x"},
+		`--memo "C:\dir" a\ b`:        {"--memo", `C:\dir`, "a b"},
+		`--memo ""`:                   {"--memo", ""},
+		`--account=Checking,Savings`:  {"--account=Checking,Savings"},
+		`--payee "say \"hi\" \\ now"`: {"--payee", `say "hi" \ now`},
+	} {
+		got, err := splitArgs(line)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("splitArgs(%q) = %q, %v; want %q", line, got, err, want)
+		}
+	}
+	for _, line := range []string{`'open`, `"open`, `trailing\`} {
+		if _, err := splitArgs(line); err == nil {
+			t.Errorf("splitArgs(%q): expected error", line)
+		}
+	}
+}
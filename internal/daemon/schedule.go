@@ -0,0 +1,195 @@
+package daemon
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MinInterval is the shortest interval a schedule may have, so jobs do
+// not eat the API rate limit
+const MinInterval = time.Minute
+
+// Schedule says when a job runs:
+//
+//	hourly          at the start of every hour
+//	daily           every day at midnight
+//	daily@06:30     every day at 06:30
+//	weekly          every Monday at midnight
+//	weekly@07:00    every Monday at 07:00
+//	15m, 6h         every interval, counted from the previous run
+//
+// Times are in the local time zone.
+type Schedule struct {
+	Spec string
+
+	kind  string
+	every time.Duration
+	// at is the time of day of daily and weekly schedules
+	at time.Duration
+}
+
+// ParseSchedule parses a schedule spec
+func ParseSchedule(spec string) (Schedule, error) {
+	s := Schedule{Spec: spec}
+	name, at, hasAt := strings.Cut(strings.ToLower(strings.TrimSpace(spec)), "@")
+	switch name {
+	case "hourly":
+		if hasAt {
+			return s, fmt.Errorf("invalid schedule %q: hourly takes no time", spec)
+		}
+		s.kind = name
+		return s, nil
+	case "daily", "weekly":
+		s.kind = name
+		if hasAt {
+			t, err := time.Parse("15:04", at)
+			if err != nil {
+				return s, fmt.Errorf("invalid schedule %q: want a time like %s@06:30", spec, name)
+			}
+			s.at = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+		}
+		return s, nil
+	}
+
+	d, err := time.ParseDuration(name)
+	if err != nil || hasAt {
+		return s, fmt.Errorf("invalid schedule %q (want hourly, daily, weekly, daily@HH:MM, weekly@HH:MM or an interval like 30m)", spec)
+	}
+	if d < MinInterval {
+		return s, fmt.Errorf("invalid schedule %q: the interval must be at least %s", spec, MinInterval)
+	}
+	s.kind, s.every = "every", d
+	return s, nil
+}
+
+// Next returns the first time after t the schedule is due
+func (s Schedule) Next(t time.Time) time.Time {
+	y, m, d := t.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	switch s.kind {
+	case "hourly":
+		return time.Date(y, m, d, t.Hour()+1, 0, 0, 0, t.Location())
+	case "daily":
+		next := addDay(midnight, 0, s.at)
+		if !next.After(t) {
+			next = addDay(midnight, 1, s.at)
+		}
+		return next
+	case "weekly":
+		days := (int(time.Monday) - int(t.Weekday()) + 7) % 7
+		next := addDay(midnight, days, s.at)
+		if !next.After(t) {
+			next = addDay(midnight, days+7, s.at)
+		}
+		return next
+	}
+	return t.Add(s.every)
+}
+
+// addDay returns the time of day at on the day days after midnight,
+// counted in wall clock time so daylight saving changes do not shift it
+func addDay(midnight time.Time, days int, at time.Duration) time.Time {
+	y, m, d := midnight.AddDate(0, 0, days).Date()
+	return time.Date(y, m, d, int(at/time.Hour), int(at%time.Hour/time.Minute), 0, 0, midnight.Location())
+}
+
+// Job is a ynabctl command run on a schedule
+type Job struct {
+	// Name is the command line as given, e.g. "doctor references"
+	Name     string
+	Args     []string
+	Schedule Schedule
+}
+
+// ParseJobs parses "<schedule>: <command>" entries such as
+// "daily@06:30: snapshot --out-dir data", one job per entry. The command
+// is split into arguments like a shell would, so quoted arguments may
+// hold spaces and commas.
+func ParseJobs(entries []string) ([]Job, error) {
+	var jobs []Job
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		spec, command, ok := strings.Cut(entry, ": ")
+		if !ok {
+			return nil, fmt.Errorf("invalid job %q (want \"<schedule>: <command>\")", entry)
+		}
+		args, err := splitArgs(command)
+		if err != nil {
+			return nil, fmt.Errorf("invalid job %q: %w", entry, err)
+		}
+		if len(args) == 0 {
+			return nil, fmt.Errorf("invalid job %q: no command", entry)
+		}
+		sched, err := ParseSchedule(strings.TrimSpace(spec))
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, Job{Name: strings.TrimSpace(command), Args: args, Schedule: sched})
+	}
+	if len(jobs) == 0 {
+		return nil, fmt.Errorf("no jobs scheduled")
+	}
+	return jobs, nil
+}
+
+// splitArgs splits a command line into arguments as a POSIX shell does,
+// without expanding anything: whitespace separates arguments, single
+// quotes keep everything, double quotes keep everything but \" and \\,
+// and a backslash outside quotes escapes the next character.
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			if quote == '"' && r != '"' && r != '\\' {
+				cur.WriteRune('\\')
+			}
+			cur.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == '\\':
+			escaped, inArg = true, true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote or escape in %q", line)
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
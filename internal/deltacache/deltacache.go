@@ -0,0 +1,122 @@
+// Package deltacache keeps local copies of a budget's transactions,
+// accounts, categories, payees and scheduled transactions that are
+// brought up to date with delta requests (last_knowledge_of_server), one
+// file per budget and endpoint, so client-side filters do not need a
+// full fetch every time.
+package deltacache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// Cache is the items of one endpoint of a budget as of ServerKnowledge
+type Cache[T any] struct {
+	BudgetID        string `json:"budget_id"`
+	Endpoint        string `json:"endpoint"`
+	ServerKnowledge int64  `json:"server_knowledge"`
+	Items           []T    `json:"items"`
+	// UpdatedAt is when the cache was last merged with the API
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+
+	path string
+}
+
+// Dir returns the directory of the cache files in the user cache
+// directory (e.g. ~/.cache/ynabctl/delta)
+func Dir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "ynabctl", "delta")
+}
+
+// Path returns the cache file of an endpoint of a budget
+// (e.g. ~/.cache/ynabctl/delta/<budget-id>/payees.json)
+func Path(budgetID, endpoint string) string {
+	return filepath.Join(Dir(), budgetID, endpoint+".json")
+}
+
+// Load reads the cache at path. A missing file yields an empty cache,
+// which the first Merge fills with every item.
+func Load[T any](path string) (*Cache[T], error) {
+	c := &Cache[T]{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return c, err
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return &Cache[T]{path: path}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Merge applies the items changed since ServerKnowledge: changed ones
+// replace the cached copy in place, new ones are appended and deleted
+// ones are dropped. id and deleted read an item's ID and deleted flag.
+func (c *Cache[T]) Merge(changed []T, knowledge int64, id func(T) string, deleted func(T) bool) {
+	index := make(map[string]int, len(c.Items))
+	for i, item := range c.Items {
+		index[id(item)] = i
+	}
+	for _, item := range changed {
+		if i, ok := index[id(item)]; ok {
+			c.Items[i] = item
+			continue
+		}
+		index[id(item)] = len(c.Items)
+		c.Items = append(c.Items, item)
+	}
+
+	kept := c.Items[:0]
+	for _, item := range c.Items {
+		if !deleted(item) {
+			kept = append(kept, item)
+		}
+	}
+	c.Items = kept
+	c.ServerKnowledge = knowledge
+	c.UpdatedAt = time.Now().UTC()
+}
+
+// Save writes the cache to its file
+func (c *Cache[T]) Save() error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return fmt.Errorf("mkdir %s: %w", filepath.Dir(c.path), err)
+	}
+	return os.WriteFile(c.path, data, 0o600)
+}
+
+// CompleteGroups fills in the categories a delta response leaves out.
+// A changed group only carries its changed categories, so the cached
+// ones it lacks are added back, and deleted categories are dropped.
+func CompleteGroups(cached, changed []ynab.CategoryGroup) []ynab.CategoryGroup {
+	old := make(map[string]ynab.CategoryGroup, len(cached))
+	for _, g := range cached {
+		old[g.ID] = g
+	}
+	complete := make([]ynab.CategoryGroup, len(changed))
+	for i, g := range changed {
+		c := &Cache[ynab.Category]{Items: append([]ynab.Category(nil), old[g.ID].Categories...)}
+		c.Merge(g.Categories, 0, categoryID, categoryDeleted)
+		g.Categories = c.Items
+		complete[i] = g
+	}
+	return complete
+}
+
+func categoryID(c ynab.Category) string    { return c.ID }
+func categoryDeleted(c ynab.Category) bool { return c.Deleted }
@@ -0,0 +1,69 @@
+package deltacache
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func payeeID(p ynab.Payee) string    { return p.ID }
+func payeeDeleted(p ynab.Payee) bool { return p.Deleted }
+
+func TestMergeAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "b", "payees.json")
+	c, err := Load[ynab.Payee](path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.ServerKnowledge != 0 || len(c.Items) != 0 {
+		t.Fatalf("new cache = %+v", c)
+	}
+
+	c.Merge([]ynab.Payee{{ID: "a", Name: "Shop"}, {ID: "b", Name: "Cafe"}}, 10, payeeID, payeeDeleted)
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = Load[ynab.Payee](path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Merge([]ynab.Payee{
+		{ID: "c", Name: "Bakery"},
+		{ID: "a", Name: "Corner Shop"},
+		{ID: "b", Deleted: true},
+	}, 12, payeeID, payeeDeleted)
+
+	if c.ServerKnowledge != 12 {
+		t.Errorf("knowledge = %d", c.ServerKnowledge)
+	}
+	if len(c.Items) != 2 || c.Items[0].Name != "Corner Shop" || c.Items[1].ID != "c" {
+		t.Fatalf("items = %+v", c.Items)
+	}
+}
+
+func TestCompleteGroups(t *testing.T) {
+	cached := []ynab.CategoryGroup{
+		{ID: "g1", Name: "Bills", Categories: []ynab.Category{{ID: "c1", Name: "Rent"}, {ID: "c2", Name: "Power"}}},
+		{ID: "g2", Name: "Fun", Categories: []ynab.Category{{ID: "c3", Name: "Games"}}},
+	}
+	changed := []ynab.CategoryGroup{
+		{ID: "g1", Name: "Bills", Categories: []ynab.Category{{ID: "c2", Deleted: true}, {ID: "c4", Name: "Water"}}},
+		{ID: "g3", Name: "New", Categories: []ynab.Category{{ID: "c5", Name: "Gifts"}}},
+	}
+
+	got := CompleteGroups(cached, changed)
+	if len(got) != 2 {
+		t.Fatalf("groups = %+v", got)
+	}
+	if cats := got[0].Categories; len(cats) != 2 || cats[0].ID != "c1" || cats[1].ID != "c4" {
+		t.Errorf("g1 categories = %+v", cats)
+	}
+	if cats := got[1].Categories; len(cats) != 1 || cats[0].ID != "c5" {
+		t.Errorf("g3 categories = %+v", cats)
+	}
+	if len(cached[0].Categories) != 2 || cached[0].Categories[1].ID != "c2" {
+		t.Errorf("cached groups changed: %+v", cached[0].Categories)
+	}
+}
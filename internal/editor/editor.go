@@ -0,0 +1,51 @@
+// Package editor lets the user edit a document in their text editor.
+package editor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Command returns the editor to run: $VISUAL, then $EDITOR, then vi
+func Command() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if v := strings.TrimSpace(os.Getenv(env)); v != "" {
+			return v
+		}
+	}
+	return "vi"
+}
+
+// Edit writes content to a temporary file named with pattern (see
+// os.CreateTemp), opens it in the editor attached to the terminal and
+// returns the saved content.
+func Edit(content []byte, pattern string) ([]byte, error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	path := f.Name()
+	defer os.Remove(path)
+
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	// The editor setting may carry arguments ("code --wait"), so let the
+	// shell split it
+	cmd := exec.Command("sh", "-c", Command()+` "$1"`, "editor", path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("editor %q failed: %w", Command(), err)
+	}
+
+	return os.ReadFile(path)
+}
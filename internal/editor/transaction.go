@@ -0,0 +1,154 @@
+package editor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/output"
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// Transaction is the editable form of a transaction. Amounts are in
+// currency units and account and category are given by name, so the
+// document can be edited by hand.
+type Transaction struct {
+	ID       string  `json:"id"`
+	Account  string  `json:"account"`
+	Date     string  `json:"date"`
+	Amount   float64 `json:"amount"`
+	Payee    string  `json:"payee"`
+	Category string  `json:"category"`
+	Memo     string  `json:"memo"`
+	Cleared  string  `json:"cleared"`
+	Approved bool    `json:"approved"`
+	Flag     string  `json:"flag_color"`
+}
+
+var (
+	clearedValues = map[string]bool{"cleared": true, "uncleared": true, "reconciled": true}
+	flagValues    = map[string]bool{"": true, "red": true, "orange": true, "yellow": true, "green": true, "blue": true, "purple": true}
+)
+
+// ValidFlag reports whether s is a flag color, or "" for no flag
+func ValidFlag(s string) bool {
+	return flagValues[s]
+}
+
+// FromTransaction returns the editable form of t
+func FromTransaction(t ynab.Transaction) Transaction {
+	return Transaction{
+		ID:       t.ID,
+		Account:  t.AccountName,
+		Date:     t.Date,
+		Amount:   ynab.MilliunitsToAmount(t.Amount),
+		Payee:    t.PayeeName,
+		Category: t.CategoryName,
+		Memo:     t.Memo,
+		Cleared:  t.Cleared,
+		Approved: t.Approved,
+		Flag:     t.FlagColor,
+	}
+}
+
+// Marshal renders the document for editing
+func (t Transaction) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// ParseTransaction reads an edited document and validates its fields.
+// Unknown fields are rejected so typos are not silently ignored.
+func ParseTransaction(data []byte) (Transaction, error) {
+	var t Transaction
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&t); err != nil {
+		return t, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	if _, err := time.Parse("2006-01-02", t.Date); err != nil {
+		return t, fmt.Errorf("invalid date %q (want YYYY-MM-DD)", t.Date)
+	}
+	if !clearedValues[t.Cleared] {
+		return t, fmt.Errorf("invalid cleared %q (want cleared, uncleared or reconciled)", t.Cleared)
+	}
+	if !flagValues[t.Flag] {
+		return t, fmt.Errorf("invalid flag_color %q", t.Flag)
+	}
+	return t, nil
+}
+
+// Resolver turns the account and category names of an edited document
+// into IDs
+type Resolver struct {
+	Account  func(name string) (string, error)
+	Category func(name string) (string, error)
+}
+
+// Patch compares an edited document with the original and returns a
+// patch containing only the changed fields, along with the changes for
+// display. Names are resolved only when they were changed.
+func Patch(before, after Transaction, resolve Resolver) (ynab.PatchTransaction, output.Changes, error) {
+	patch := ynab.PatchTransaction{ID: before.ID}
+	var changes output.Changes
+
+	if after.ID != before.ID {
+		return patch, nil, fmt.Errorf("the id cannot be changed")
+	}
+
+	if after.Account != before.Account {
+		id, err := resolve.Account(after.Account)
+		if err != nil {
+			return patch, nil, err
+		}
+		patch.AccountID = &id
+		changes.Add("account", before.Account, after.Account)
+	}
+	if after.Date != before.Date {
+		patch.Date = &after.Date
+		changes.Add("date", before.Date, after.Date)
+	}
+	if after.Amount != before.Amount {
+		amount := ynab.AmountToMilliunits(after.Amount)
+		patch.Amount = &amount
+		changes.AddAmount("amount", ynab.AmountToMilliunits(before.Amount), amount)
+	}
+	if after.Payee != before.Payee {
+		patch.PayeeName = &after.Payee
+		changes.Add("payee", before.Payee, after.Payee)
+	}
+	if after.Category != before.Category {
+		if after.Category == "" {
+			return patch, nil, fmt.Errorf("the category cannot be cleared")
+		}
+		id, err := resolve.Category(after.Category)
+		if err != nil {
+			return patch, nil, err
+		}
+		patch.CategoryID = &id
+		changes.Add("category", before.Category, after.Category)
+	}
+	if after.Memo != before.Memo {
+		patch.Memo = &after.Memo
+		changes.Add("memo", before.Memo, after.Memo)
+	}
+	if after.Cleared != before.Cleared {
+		patch.Cleared = &after.Cleared
+		changes.Add("cleared", before.Cleared, after.Cleared)
+	}
+	if after.Approved != before.Approved {
+		patch.Approved = &after.Approved
+		changes.Add("approved", before.Approved, after.Approved)
+	}
+	if after.Flag != before.Flag {
+		patch.FlagColor = &after.Flag
+		changes.Add("flag", before.Flag, after.Flag)
+	}
+
+	return patch, changes, nil
+}
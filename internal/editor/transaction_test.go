@@ -0,0 +1,73 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestParseTransaction(t *testing.T) {
+	orig := FromTransaction(ynab.Transaction{ID: "t1", Date: "2025-01-02", Amount: -12340, Cleared: "cleared"})
+	data, err := orig.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ParseTransaction(data); err != nil || got != orig {
+		t.Fatalf("round trip = %+v, %v", got, err)
+	}
+
+	bad := map[string]string{
+		"date":    `{"date": "02.01.2025", "cleared": "cleared"}`,
+		"cleared": `{"date": "2025-01-02", "cleared": "yes"}`,
+		"flag":    `{"date": "2025-01-02", "cleared": "cleared", "flag_color": "pink"}`,
+		"unknown": `{"date": "2025-01-02", "cleared": "cleared", "payee_name": "x"}`,
+	}
+	for name, doc := range bad {
+		if _, err := ParseTransaction([]byte(doc)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestPatch(t *testing.T) {
+	before := FromTransaction(ynab.Transaction{
+		ID: "t1", AccountName: "Checking", Date: "2025-01-02", Amount: -290,
+		PayeeName: "Shop", CategoryName: "Groceries", Cleared: "uncleared",
+	})
+	resolve := Resolver{
+		Account:  func(string) (string, error) { t.Fatal("account resolved"); return "", nil },
+		Category: func(name string) (string, error) { return "cat-" + strings.ToLower(name), nil },
+	}
+
+	after := before
+	after.Amount = -0.29 // unchanged
+	after.Category = "Dining"
+	after.Memo = "lunch"
+	patch, changes, err := Patch(before, after, resolve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if patch.ID != "t1" || patch.CategoryID == nil || *patch.CategoryID != "cat-dining" || patch.Memo == nil || *patch.Memo != "lunch" {
+		t.Errorf("patch = %+v", patch)
+	}
+	if patch.Amount != nil || patch.Date != nil || patch.AccountID != nil || patch.PayeeName != nil {
+		t.Errorf("unchanged fields in patch: %+v", patch)
+	}
+	if len(changes) != 2 {
+		t.Errorf("changes = %+v", changes)
+	}
+
+	after = before
+	after.Amount = -1.005
+	patch, _, _ = Patch(before, after, resolve)
+	if patch.Amount == nil || *patch.Amount != -1005 {
+		t.Errorf("amount = %v", patch.Amount)
+	}
+
+	after = before
+	after.ID = "t2"
+	if _, _, err := Patch(before, after, resolve); err == nil {
+		t.Error("changing the id should fail")
+	}
+}
@@ -0,0 +1,123 @@
+// Package export writes transactions in spreadsheet-friendly formats and
+// reads them back from spreadsheets and bank CSV files.
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// CSVOptions controls the dialect of the CSV output.
+type CSVOptions struct {
+	// Delimiter separates fields; defaults to ','
+	Delimiter rune
+	// DecimalComma writes amounts as 1234,56 instead of 1234.56
+	DecimalComma bool
+	// Encoding is utf-8 (default), iso-8859-1, or windows-1252
+	Encoding string
+}
+
+// Header is the column order of exported transactions.
+var Header = []string{
+	"id", "date", "account", "payee", "category", "memo",
+	"amount", "cleared", "approved", "flag", "import_id",
+}
+
+// WriteCSV writes txns as CSV to w. Deleted transactions are skipped.
+func WriteCSV(w io.Writer, txns []ynab.Transaction, opts CSVOptions) error {
+	enc, err := encoder(opts.Encoding)
+	if err != nil {
+		return err
+	}
+	if opts.Delimiter == 0 {
+		opts.Delimiter = ','
+	}
+	if opts.DecimalComma && opts.Delimiter == ',' {
+		return fmt.Errorf("--decimal-comma needs a delimiter other than ','")
+	}
+
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+	cw.Comma = opts.Delimiter
+	if err := cw.Write(Header); err != nil {
+		return err
+	}
+	for _, t := range txns {
+		if t.Deleted {
+			continue
+		}
+		record := []string{
+			t.ID, t.Date, t.AccountName, t.PayeeName, t.CategoryName, t.Memo,
+			FormatAmount(t.Amount, opts.DecimalComma),
+			t.Cleared, strconv.FormatBool(t.Approved), t.FlagColor, t.ImportID,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+
+	_, err = w.Write(enc(buf.Bytes()))
+	return err
+}
+
+// FormatAmount renders milliunits as a decimal with two places.
+func FormatAmount(milliunits int64, decimalComma bool) string {
+	s := strconv.FormatFloat(float64(milliunits)/1000, 'f', 2, 64)
+	if decimalComma {
+		s = strings.Replace(s, ".", ",", 1)
+	}
+	return s
+}
+
+// encoder returns a function converting UTF-8 to the named encoding.
+// Characters that cannot be represented become '?'.
+func encoder(name string) (func([]byte) []byte, error) {
+	switch strings.ToLower(strings.ReplaceAll(name, "_", "-")) {
+	case "", "utf-8", "utf8":
+		return func(b []byte) []byte { return b }, nil
+	case "iso-8859-1", "latin1", "latin-1":
+		return singleByte(nil), nil
+	case "windows-1252", "cp1252":
+		return singleByte(cp1252), nil
+	}
+	return nil, fmt.Errorf("unsupported encoding %q (want utf-8, iso-8859-1, windows-1252)", name)
+}
+
+// cp1252 maps the characters windows-1252 places in 0x80-0x9F.
+var cp1252 = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+func singleByte(extra map[rune]byte) func([]byte) []byte {
+	return func(b []byte) []byte {
+		out := make([]byte, 0, len(b))
+		for len(b) > 0 {
+			r, size := utf8.DecodeRune(b)
+			b = b[size:]
+			if c, ok := extra[r]; ok {
+				out = append(out, c)
+				continue
+			}
+			if r < 0x100 && (extra == nil || r < 0x80 || r > 0x9F) {
+				out = append(out, byte(r))
+				continue
+			}
+			out = append(out, '?')
+		}
+		return out
+	}
+}
@@ -0,0 +1,98 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestWriteCSVEuropean(t *testing.T) {
+	txns := []ynab.Transaction{
+		{ID: "t1", Date: "2025-03-01", AccountName: "Brukskonto", PayeeName: "Bæker & Co", CategoryName: "Mat", Memo: "brød; melk", Amount: -1234560, Cleared: "cleared", Approved: true},
+		{ID: "t2", Deleted: true},
+	}
+	var buf bytes.Buffer
+	err := WriteCSV(&buf, txns, CSVOptions{Delimiter: ';', DecimalComma: true, Encoding: "iso-8859-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "id;date;account;payee;category;memo;amount;cleared;approved;flag;import_id\n" +
+		"t1;2025-03-01;Brukskonto;B\xe6ker & Co;Mat;\"br\xf8d; melk\";-1234,56;cleared;true;;\n"
+	if buf.String() != want {
+		t.Errorf("got\n%q\nwant\n%q", buf.String(), want)
+	}
+}
+
+func TestWriteCSVRejects(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, nil, CSVOptions{DecimalComma: true}); err == nil {
+		t.Error("expected error for decimal comma with comma delimiter")
+	}
+	if err := WriteCSV(&buf, nil, CSVOptions{Encoding: "ebcdic"}); err == nil {
+		t.Error("expected error for unknown encoding")
+	}
+}
+
+func TestWindows1252(t *testing.T) {
+	enc, _ := encoder("windows-1252")
+	if got := enc([]byte("€5 – ok ✓")); !bytes.Equal(got, []byte("\x805 \x96 ok ?")) {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestEditableRoundTrip(t *testing.T) {
+	txns := []ynab.Transaction{
+		{ID: "t1", Date: "2025-03-01", AccountName: "Brukskonto", PayeeName: "Rema", CategoryName: "Mat", Memo: "brød; melk", Amount: -123450, FlagColor: "red"},
+		{ID: "t2", Deleted: true},
+	}
+	var buf bytes.Buffer
+	if err := WriteEditable(&buf, txns, CSVOptions{Delimiter: ';', DecimalComma: true}); err != nil {
+		t.Fatal(err)
+	}
+	want := "\ufeffid;date;account;payee;amount;category;memo;flag\n" +
+		"t1;2025-03-01;Brukskonto;Rema;-123,45;Mat;\"brød; melk\";red\n"
+	if buf.String() != want {
+		t.Fatalf("got\n%q\nwant\n%q", buf.String(), want)
+	}
+
+	edits, err := ReadEdits(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edits) != 1 || edits[0].ID != "t1" || edits[0].Line != 2 {
+		t.Fatalf("edits = %+v", edits)
+	}
+	f := edits[0].Fields
+	if f["category"] != "Mat" || f["memo"] != "brød; melk" || f["flag"] != "red" {
+		t.Errorf("fields = %+v", f)
+	}
+}
+
+func TestReadEditsColumns(t *testing.T) {
+	// reordered, memo column dropped, trailing blank line
+	in := "category,ID\nGroceries,t1\nRent,t2\n\n"
+	edits, err := ReadEdits(bytes.NewBufferString(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edits) != 2 || edits[1].ID != "t2" || edits[1].Fields["category"] != "Rent" {
+		t.Fatalf("edits = %+v", edits)
+	}
+	if _, ok := edits[0].Fields["memo"]; ok {
+		t.Error("a dropped column should not be applied")
+	}
+}
+
+func TestReadEditsRejects(t *testing.T) {
+	for name, in := range map[string]string{
+		"no id column": "category,memo\nFood,x\n",
+		"missing id":   "id,memo\n,x\n",
+		"duplicate":    "id,memo\nt1,a\nt1,b\n",
+		"empty":        "",
+	} {
+		if _, err := ReadEdits(bytes.NewBufferString(in)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
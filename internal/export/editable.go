@@ -0,0 +1,154 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// EditableHeader is the column order of an editable export. Only the
+// category, memo and flag columns are applied back; the others are there
+// to recognize the transactions.
+var EditableHeader = []string{
+	"id", "date", "account", "payee", "amount", "category", "memo", "flag",
+}
+
+// EditableColumns are the columns ReadEdits applies
+var EditableColumns = []string{"category", "memo", "flag"}
+
+// bom makes spreadsheet programs read the file as UTF-8
+const bom = "\ufeff"
+
+// WriteEditable writes txns as an editable CSV: UTF-8 with a byte order
+// mark, in the given delimiter. Deleted transactions are skipped.
+func WriteEditable(w io.Writer, txns []ynab.Transaction, opts CSVOptions) error {
+	if opts.Delimiter == 0 {
+		opts.Delimiter = ','
+	}
+	if opts.DecimalComma && opts.Delimiter == ',' {
+		return fmt.Errorf("--decimal-comma needs a delimiter other than ','")
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(bom)
+	cw := csv.NewWriter(&buf)
+	cw.Comma = opts.Delimiter
+	if err := cw.Write(EditableHeader); err != nil {
+		return err
+	}
+	for _, t := range txns {
+		if t.Deleted {
+			continue
+		}
+		record := []string{
+			t.ID, t.Date, t.AccountName, t.PayeeName,
+			FormatAmount(t.Amount, opts.DecimalComma),
+			t.CategoryName, t.Memo, t.FlagColor,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Edit is one row of an edited CSV. Fields holds the editable columns
+// present in the file, so a column the user removed is left alone.
+type Edit struct {
+	Line   int
+	ID     string
+	Fields map[string]string
+}
+
+// ReadEdits reads an editable CSV back. Columns are found by their header,
+// so they may be reordered or dropped, and the delimiter is detected from
+// the header line. Every row needs an id, and an id may appear only once.
+func ReadEdits(r io.Reader) ([]Edit, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(4096)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	first, _, _ := strings.Cut(strings.TrimPrefix(string(header), bom), "\n")
+
+	cr := csv.NewReader(br)
+	cr.Comma = sniffDelimiter(first)
+	cr.FieldsPerRecord = -1
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("empty file")
+	}
+
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, bom)))
+		columns[name] = i
+	}
+	idCol, ok := columns["id"]
+	if !ok {
+		return nil, fmt.Errorf("no id column")
+	}
+
+	var edits []Edit
+	seen := map[string]int{}
+	for i, record := range records[1:] {
+		line := i + 2
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		id := ""
+		if idCol < len(record) {
+			id = strings.TrimSpace(record[idCol])
+		}
+		if id == "" {
+			return nil, fmt.Errorf("line %d: no id", line)
+		}
+		if prev, ok := seen[id]; ok {
+			return nil, fmt.Errorf("line %d: transaction %s is also on line %d", line, id, prev)
+		}
+		seen[id] = line
+
+		e := Edit{Line: line, ID: id, Fields: map[string]string{}}
+		for _, name := range EditableColumns {
+			if c, ok := columns[name]; ok {
+				value := ""
+				if c < len(record) {
+					value = record[c]
+				}
+				// a memo is kept as typed so it compares equal to
+				// the exported one
+				if name != "memo" {
+					value = strings.TrimSpace(value)
+				}
+				e.Fields[name] = value
+			}
+		}
+		edits = append(edits, e)
+	}
+	return edits, nil
+}
+
+// sniffDelimiter returns the one of ',', ';' and tab that occurs most in
+// a header line
+func sniffDelimiter(line string) rune {
+	best, count := ',', strings.Count(line, ",")
+	for _, d := range []rune{';', '\t'} {
+		if n := strings.Count(line, string(d)); n > count {
+			best, count = d, n
+		}
+	}
+	return best
+}
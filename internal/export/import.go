@@ -0,0 +1,277 @@
+package export
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// Fields of an imported transaction that can be mapped to CSV columns.
+// Amount may be given as one signed column or as separate outflow and
+// inflow columns.
+const (
+	FieldDate     = "date"
+	FieldAmount   = "amount"
+	FieldOutflow  = "outflow"
+	FieldInflow   = "inflow"
+	FieldPayee    = "payee"
+	FieldMemo     = "memo"
+	FieldCategory = "category"
+	FieldAccount  = "account"
+)
+
+var importFields = []string{FieldDate, FieldAmount, FieldOutflow, FieldInflow, FieldPayee, FieldMemo, FieldCategory, FieldAccount}
+
+// ImportOptions says how to read a CSV of transactions
+type ImportOptions struct {
+	// Columns maps fields to column headers. Unmapped fields use the
+	// column named like the field, if any; headers ignore case.
+	Columns map[string]string
+	// DateFormat is a Go layout such as "02.01.2006", or the same
+	// written as "DD.MM.YYYY"; defaults to "2006-01-02"
+	DateFormat string
+	// DecimalComma reads amounts as 1.234,56
+	DecimalComma bool
+	// Delimiter separates fields; 0 detects ',', ';' or tab
+	Delimiter rune
+	// Encoding is utf-8 (default), iso-8859-1, or windows-1252
+	Encoding string
+}
+
+// ImportRow is one transaction read from a CSV. Amount is in milliunits.
+type ImportRow struct {
+	Line     int
+	Date     string
+	Amount   int64
+	Payee    string
+	Memo     string
+	Category string
+	Account  string
+	// Err is why the row could not be read, e.g. an invalid date
+	Err error
+}
+
+// ParseColumns parses "field=Header" mappings
+func ParseColumns(mappings []string) (map[string]string, error) {
+	columns := map[string]string{}
+	for _, m := range mappings {
+		field, header, ok := strings.Cut(m, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !ok || strings.TrimSpace(header) == "" {
+			return nil, fmt.Errorf("invalid column mapping %q (want field=Header)", m)
+		}
+		known := false
+		for _, f := range importFields {
+			known = known || f == field
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown field %q in %q (want one of %s)", field, m, strings.Join(importFields, ", "))
+		}
+		columns[field] = strings.TrimSpace(header)
+	}
+	return columns, nil
+}
+
+// ReadImport reads transactions from a CSV with a header line. Every row
+// needs a date and an amount; rows without are returned with Err set, so
+// they can be reported with the rest. Blank rows are skipped.
+func ReadImport(r io.Reader, opts ImportOptions) ([]ImportRow, error) {
+	decode, err := decoder(opts.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(decode(data), []byte(bom))
+
+	layout := dateLayout(opts.DateFormat)
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.Comma = opts.Delimiter
+	if cr.Comma == 0 {
+		first, _, _ := strings.Cut(string(data), "\n")
+		cr.Comma = sniffDelimiter(first)
+	}
+	cr.FieldsPerRecord = -1
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("empty file")
+	}
+
+	headers := map[string]int{}
+	for i, h := range records[0] {
+		headers[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	col := map[string]int{}
+	for _, f := range importFields {
+		name, mapped := opts.Columns[f]
+		if !mapped {
+			name = f
+		}
+		i, ok := headers[strings.ToLower(name)]
+		if !ok {
+			if mapped {
+				return nil, fmt.Errorf("no column %q for %s", name, f)
+			}
+			continue
+		}
+		col[f] = i
+	}
+	if _, ok := col[FieldDate]; !ok {
+		return nil, fmt.Errorf("no date column; map one with date=<header>")
+	}
+	_, hasAmount := col[FieldAmount]
+	_, hasOutflow := col[FieldOutflow]
+	_, hasInflow := col[FieldInflow]
+	if !hasAmount && !hasOutflow && !hasInflow {
+		return nil, fmt.Errorf("no amount column; map one with amount=<header>, or outflow= and inflow=")
+	}
+
+	var rows []ImportRow
+	for i, record := range records[1:] {
+		line := i + 2
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		get := func(f string) string {
+			if c, ok := col[f]; ok && c < len(record) {
+				return strings.TrimSpace(record[c])
+			}
+			return ""
+		}
+
+		row := ImportRow{Line: line, Payee: get(FieldPayee), Memo: get(FieldMemo), Category: get(FieldCategory), Account: get(FieldAccount)}
+		rows = append(rows, readRow(row, get, layout, opts.DecimalComma))
+	}
+	return rows, nil
+}
+
+// readRow sets the date and amount of row from the fields get returns,
+// or its Err
+func readRow(row ImportRow, get func(field string) string, layout string, decimalComma bool) ImportRow {
+	d, err := time.Parse(layout, get(FieldDate))
+	if err != nil {
+		row.Err = fmt.Errorf("invalid date %q (want %s)", get(FieldDate), layout)
+		return row
+	}
+	row.Date = d.Format("2006-01-02")
+
+	set := false
+	for _, f := range []string{FieldAmount, FieldInflow, FieldOutflow} {
+		s := get(f)
+		if s == "" {
+			continue
+		}
+		amount, err := ParseAmount(s, decimalComma)
+		if err != nil {
+			row.Err = err
+			return row
+		}
+		if f == FieldOutflow {
+			amount = -abs(amount)
+		}
+		row.Amount += amount
+		set = true
+	}
+	if !set {
+		row.Err = fmt.Errorf("no amount")
+	}
+	return row
+}
+
+// ParseAmount reads a decimal amount such as "-1,234.56" or, with
+// decimalComma, "-1.234,56" or "1 234,56", as milliunits
+func ParseAmount(s string, decimalComma bool) (int64, error) {
+	clean := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\u00a0', '\u202f', '\'':
+			return -1
+		}
+		return r
+	}, s)
+	if decimalComma {
+		clean = strings.ReplaceAll(clean, ".", "")
+		clean = strings.Replace(clean, ",", ".", 1)
+	} else {
+		clean = strings.ReplaceAll(clean, ",", "")
+	}
+	// accounting style: (12.50) is negative
+	if strings.HasPrefix(clean, "(") && strings.HasSuffix(clean, ")") {
+		clean = "-" + clean[1:len(clean)-1]
+	}
+	f, err := strconv.ParseFloat(clean, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	return ynab.AmountToMilliunits(f), nil
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// dateLayout turns a format like "DD.MM.YYYY" into a Go layout; Go
+// layouts are returned as they are
+func dateLayout(format string) string {
+	if format == "" {
+		return "2006-01-02"
+	}
+	return strings.NewReplacer("YYYY", "2006", "YY", "06", "MM", "01", "DD", "02").Replace(format)
+}
+
+// ImportID returns the import_id of an imported row in an account. It is
+// derived from the row's content and occurrence, the number of identical
+// rows before it, so importing the same file again creates nothing new:
+// YNAB skips transactions whose import_id already exists.
+func ImportID(accountID string, row ImportRow, occurrence int) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%s|%d|%s|%s|%d", accountID, row.Date, row.Amount, row.Payee, row.Memo, occurrence)))
+	// YNAB limits import_id to 36 characters
+	return "YNABCTL:" + hex.EncodeToString(sum[:])[:28]
+}
+
+// decoder returns a function converting the named encoding to UTF-8
+func decoder(name string) (func([]byte) []byte, error) {
+	if _, err := encoder(name); err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(strings.ReplaceAll(name, "_", "-")) {
+	case "iso-8859-1", "latin1", "latin-1":
+		return fromSingleByte(nil), nil
+	case "windows-1252", "cp1252":
+		return fromSingleByte(cp1252), nil
+	}
+	return func(b []byte) []byte { return b }, nil
+}
+
+func fromSingleByte(extra map[rune]byte) func([]byte) []byte {
+	reverse := map[byte]rune{}
+	for r, c := range extra {
+		reverse[c] = r
+	}
+	return func(b []byte) []byte {
+		var out strings.Builder
+		for _, c := range b {
+			if r, ok := reverse[c]; ok {
+				out.WriteRune(r)
+				continue
+			}
+			out.WriteRune(rune(c))
+		}
+		return []byte(out.String())
+	}
+}
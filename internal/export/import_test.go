@@ -0,0 +1,114 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadImportMapped(t *testing.T) {
+	in := "Dato;Tekst;Ut;Inn;Notat\n" +
+		"14.03.2025;REMA 1000;1.234,50;;br\xf8d\n" +
+		"15.03.2025;L\xf8nn;;30 000,00;\n" +
+		";;;;\n"
+	columns, err := ParseColumns([]string{"date=Dato", "payee=tekst", "outflow=Ut", "inflow=Inn", "memo=Notat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := ReadImport(strings.NewReader(in), ImportOptions{
+		Columns:      columns,
+		DateFormat:   "DD.MM.YYYY",
+		DecimalComma: true,
+		Encoding:     "iso-8859-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ImportRow{
+		{Line: 2, Date: "2025-03-14", Amount: -1234500, Payee: "REMA 1000", Memo: "brød"},
+		{Line: 3, Date: "2025-03-15", Amount: 30000000, Payee: "Lønn"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %+v", rows)
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, rows[i], want[i])
+		}
+	}
+}
+
+func TestReadImportDefaultColumns(t *testing.T) {
+	// the columns of 'transactions export' are read without a mapping
+	var buf bytes.Buffer
+	buf.WriteString("id,date,account,payee,category,memo,amount\n,2025-03-01,Visa,Cafe,Coffee,,\"(4.50)\"\n")
+	rows, err := ReadImport(&buf, ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0].Amount != -4500 || rows[0].Account != "Visa" || rows[0].Category != "Coffee" {
+		t.Errorf("rows = %+v", rows)
+	}
+}
+
+func TestReadImportRejects(t *testing.T) {
+	for name, tt := range map[string]struct {
+		in      string
+		columns map[string]string
+	}{
+		"no date column":   {in: "when,amount\n2025-01-01,1\n"},
+		"no amount column": {in: "date,payee\n2025-01-01,x\n"},
+		"mapped missing":   {in: "date,amount\n2025-01-01,1\n", columns: map[string]string{"payee": "Tekst"}},
+	} {
+		if _, err := ReadImport(strings.NewReader(tt.in), ImportOptions{Columns: tt.columns}); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	// invalid rows are returned with the error, among the valid ones
+	rows, err := ReadImport(strings.NewReader("date,amount,payee\n01.01.2025,1,x\n2025-01-01,abc,y\n2025-01-01,,z\n2025-01-02,5,ok\n"), ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 || rows[3].Err != nil || rows[3].Amount != 5000 {
+		t.Fatalf("rows = %+v", rows)
+	}
+	for _, row := range rows[:3] {
+		if row.Err == nil {
+			t.Errorf("line %d (%s): expected error", row.Line, row.Payee)
+		}
+	}
+
+	if _, err := ParseColumns([]string{"price=Pris"}); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	for _, tt := range []struct {
+		in           string
+		decimalComma bool
+		want         int64
+	}{
+		{"-1,234.56", false, -1234560},
+		{"+12", false, 12000},
+		{"1 234,5", true, 1234500},
+		{"-1.234,56", true, -1234560},
+		{"(10.00)", false, -10000},
+	} {
+		got, err := ParseAmount(tt.in, tt.decimalComma)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseAmount(%q, %v) = %d, %v; want %d", tt.in, tt.decimalComma, got, err, tt.want)
+		}
+	}
+}
+
+func TestImportID(t *testing.T) {
+	row := ImportRow{Date: "2025-03-14", Amount: -4500, Payee: "Cafe"}
+	a, b := ImportID("acc", row, 0), ImportID("acc", row, 1)
+	if a == b || len(a) > 36 || !strings.HasPrefix(a, "YNABCTL:") {
+		t.Errorf("import IDs %q, %q", a, b)
+	}
+	if ImportID("acc", row, 0) != a {
+		t.Error("import ID is not stable")
+	}
+}
@@ -0,0 +1,96 @@
+package export
+
+import "github.com/langtind/ynabctl/pkg/ynab"
+
+// What an import does with a row of the file
+const (
+	// StatusNew rows are created
+	StatusNew = "new"
+	// StatusDuplicate rows have an import_id that already exists in the
+	// budget, so YNAB skips them
+	StatusDuplicate = "duplicate"
+	// StatusError rows could not be read or resolved and are not imported
+	StatusError = "error"
+)
+
+// PreviewRow is a row of an import file and what importing it does
+type PreviewRow struct {
+	Line     int    `json:"line"`
+	Status   string `json:"status"`
+	Date     string `json:"date,omitempty"`
+	Account  string `json:"account,omitempty"`
+	Payee    string `json:"payee,omitempty"`
+	Category string `json:"category,omitempty"`
+	Memo     string `json:"memo,omitempty"`
+	Amount   int64  `json:"amount" amount:"milliunits"`
+	ImportID string `json:"import_id,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Preview is the rows of an import file, classified before importing
+type Preview struct {
+	File string       `json:"file"`
+	Rows []PreviewRow `json:"rows"`
+}
+
+// Count returns the number of rows with status and their total
+func (p *Preview) Count(status string) (int, int64) {
+	n, total := 0, int64(0)
+	for _, r := range p.Rows {
+		if r.Status == status {
+			n++
+			total += r.Amount
+		}
+	}
+	return n, total
+}
+
+// Reconciliation compares the total of an import file with what the
+// import made of it: every row is created, a duplicate or skipped for an
+// error, so Difference is 0 unless rows went missing.
+type Reconciliation struct {
+	File           string             `json:"file"`
+	FileRows       int                `json:"file_rows"`
+	FileTotal      int64              `json:"file_total" amount:"milliunits"`
+	Created        int                `json:"created"`
+	CreatedTotal   int64              `json:"created_total" amount:"milliunits"`
+	Duplicates     int                `json:"duplicates"`
+	DuplicateTotal int64              `json:"duplicate_total" amount:"milliunits"`
+	Skipped        int                `json:"skipped"`
+	SkippedTotal   int64              `json:"skipped_total" amount:"milliunits"`
+	Difference     int64              `json:"difference" amount:"milliunits"`
+	Transactions   []ynab.Transaction `json:"transactions"`
+}
+
+// Balanced reports whether every row of the file is accounted for
+func (r *Reconciliation) Balanced() bool {
+	return r.Difference == 0 && r.FileRows == r.Created+r.Duplicates+r.Skipped
+}
+
+// Reconcile compares the preview of a file with the transactions YNAB
+// created from its new rows and the import_ids it reported as
+// duplicates
+func Reconcile(p *Preview, created []ynab.Transaction, duplicateIDs []string) *Reconciliation {
+	r := &Reconciliation{File: p.File, FileRows: len(p.Rows), Transactions: created}
+	duplicate := map[string]bool{}
+	for _, id := range duplicateIDs {
+		duplicate[id] = true
+	}
+	for _, row := range p.Rows {
+		r.FileTotal += row.Amount
+		switch {
+		case row.Status == StatusError:
+			r.Skipped++
+			r.SkippedTotal += row.Amount
+		case row.Status == StatusDuplicate || duplicate[row.ImportID]:
+			r.Duplicates++
+			r.DuplicateTotal += row.Amount
+		}
+	}
+	r.Created = len(created)
+	for _, t := range created {
+		r.CreatedTotal += t.Amount
+	}
+	r.Difference = r.FileTotal - r.CreatedTotal - r.DuplicateTotal - r.SkippedTotal
+	return r
+}
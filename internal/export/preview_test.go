@@ -0,0 +1,34 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestReconcile(t *testing.T) {
+	p := &Preview{File: "bank.csv", Rows: []PreviewRow{
+		{Line: 2, Status: StatusNew, Amount: -1000, ImportID: "a"},
+		{Line: 3, Status: StatusNew, Amount: -2000, ImportID: "b"},
+		{Line: 4, Status: StatusDuplicate, Amount: -3000, ImportID: "c"},
+		{Line: 5, Status: StatusError, Amount: 0, Error: "invalid date"},
+	}}
+	if n, total := p.Count(StatusNew); n != 2 || total != -3000 {
+		t.Errorf("Count(new) = %d, %d", n, total)
+	}
+
+	// YNAB created a, and reported b as a duplicate
+	r := Reconcile(p, []ynab.Transaction{{ID: "t1", Amount: -1000, ImportID: "a"}}, []string{"b"})
+	if r.FileTotal != -6000 || r.Created != 1 || r.Duplicates != 2 || r.DuplicateTotal != -5000 || r.Skipped != 1 {
+		t.Errorf("reconciliation = %+v", r)
+	}
+	if !r.Balanced() {
+		t.Errorf("expected balanced: %+v", r)
+	}
+
+	// b neither created nor reported
+	r = Reconcile(p, []ynab.Transaction{{ID: "t1", Amount: -1000, ImportID: "a"}}, nil)
+	if r.Balanced() || r.Difference != -2000 {
+		t.Errorf("expected a difference of -2000: %+v", r)
+	}
+}
@@ -0,0 +1,140 @@
+// Package i18n translates user-facing messages. Messages are looked up
+// by their English text, so code reads naturally and a missing
+// translation falls back to English.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// English is the language of the message keys
+const English = "en"
+
+var (
+	mu      sync.RWMutex
+	current = English
+)
+
+// bundles holds the translations per language. English needs none.
+var bundles = map[string]map[string]string{
+	English: {},
+	"nb":    norwegian,
+}
+
+// aliases map other codes to a bundle
+var aliases = map[string]string{
+	"no": "nb",
+	"nn": "nb",
+}
+
+// Languages returns the supported language codes
+func Languages() []string {
+	var langs []string
+	for l := range bundles {
+		langs = append(langs, l)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Normalize turns a language setting or locale ("nb_NO.UTF-8", "no",
+// "en-US") into a supported language code. ok is false if the language
+// is not supported.
+func Normalize(lang string) (string, bool) {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+		lang = lang[:i]
+	}
+	if a, ok := aliases[lang]; ok {
+		lang = a
+	}
+	_, ok := bundles[lang]
+	return lang, ok
+}
+
+// Detect picks the language from, in order, the --lang flag, YNAB_LANG,
+// the config file and the locale (LC_ALL, LC_MESSAGES, LANG). An
+// explicitly chosen language that is not supported is an error; an
+// unsupported locale falls back to English.
+func Detect(flag, configured string) (string, error) {
+	for _, explicit := range []string{flag, os.Getenv("YNAB_LANG"), configured} {
+		if explicit == "" {
+			continue
+		}
+		lang, ok := Normalize(explicit)
+		if !ok {
+			return English, fmt.Errorf("unsupported language %q (supported: %s)", explicit, strings.Join(Languages(), ", "))
+		}
+		return lang, nil
+	}
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(env); v != "" {
+			if lang, ok := Normalize(v); ok {
+				return lang, nil
+			}
+			return English, nil
+		}
+	}
+	return English, nil
+}
+
+// SetLanguage selects the language of T. Unsupported languages select
+// English.
+func SetLanguage(lang string) {
+	lang, ok := Normalize(lang)
+	if !ok {
+		lang = English
+	}
+	mu.Lock()
+	current = lang
+	mu.Unlock()
+}
+
+// Language returns the selected language
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// T translates msg into the selected language and formats it with args
+// like fmt.Sprintf. Untranslated messages are used as they are.
+func T(msg string, args ...interface{}) string {
+	mu.RLock()
+	if tr, ok := bundles[current][msg]; ok {
+		msg = tr
+	}
+	mu.RUnlock()
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Header translates a tab-separated table header row column by column
+func Header(row string) string {
+	cols := strings.Split(row, "\t")
+	for i, c := range cols {
+		cols[i] = T(c)
+	}
+	return strings.Join(cols, "\t")
+}
+
+// IsYes reports whether answer to a yes/no prompt means yes in English
+// or the selected language
+func IsYes(answer string) bool {
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer == "y" || answer == "yes" {
+		return true
+	}
+	for _, yes := range strings.Split(T("y|yes"), "|") {
+		if answer == yes {
+			return true
+		}
+	}
+	return false
+}
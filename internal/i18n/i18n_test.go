@@ -0,0 +1,123 @@
+package i18n
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"en", "en", true},
+		{"en_US.UTF-8", "en", true},
+		{"nb_NO.UTF-8", "nb", true},
+		{"no", "nb", true},
+		{"NN-no", "nb", true},
+		{"de_DE", "de", false},
+		{"C", "c", false},
+	}
+	for _, tt := range tests {
+		got, ok := Normalize(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Normalize(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	for _, env := range []string{"YNAB_LANG", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		t.Setenv(env, "")
+	}
+
+	if lang, _ := Detect("", ""); lang != English {
+		t.Errorf("no settings: got %q, want en", lang)
+	}
+	t.Setenv("LANG", "nb_NO.UTF-8")
+	if lang, _ := Detect("", ""); lang != "nb" {
+		t.Errorf("LANG: got %q, want nb", lang)
+	}
+	t.Setenv("LC_ALL", "de_DE.UTF-8")
+	if lang, err := Detect("", ""); lang != English || err != nil {
+		t.Errorf("unsupported locale: got %q, %v; want en without error", lang, err)
+	}
+	if lang, _ := Detect("", "no"); lang != "nb" {
+		t.Errorf("config: got %q, want nb", lang)
+	}
+	t.Setenv("YNAB_LANG", "en")
+	if lang, _ := Detect("", "nb"); lang != English {
+		t.Errorf("YNAB_LANG should win over config: got %q", lang)
+	}
+	if lang, _ := Detect("nb", ""); lang != "nb" {
+		t.Errorf("flag should win: got %q", lang)
+	}
+	if _, err := Detect("fr", ""); err == nil {
+		t.Error("expected an error for an unsupported --lang")
+	}
+}
+
+func TestT(t *testing.T) {
+	defer SetLanguage(English)
+
+	SetLanguage("en")
+	if got := T("Delete these %d transactions?", 3); got != "Delete these 3 transactions?" {
+		t.Errorf("en: got %q", got)
+	}
+	SetLanguage("nb_NO.UTF-8")
+	if Language() != "nb" {
+		t.Fatalf("Language() = %q, want nb", Language())
+	}
+	if got := T("Delete these %d transactions?", 3); got != "Slette disse 3 transaksjonene?" {
+		t.Errorf("nb: got %q", got)
+	}
+	if got := T("not translated %s", "x"); got != "not translated x" {
+		t.Errorf("fallback: got %q", got)
+	}
+	if got := Header("DATE\tPAYEE\tSOMETHING NEW"); got != "DATO\tMOTTAKER\tSOMETHING NEW" {
+		t.Errorf("Header: got %q", got)
+	}
+	SetLanguage("xx")
+	if Language() != English {
+		t.Errorf("unsupported language should select English, got %q", Language())
+	}
+}
+
+func TestIsYes(t *testing.T) {
+	defer SetLanguage(English)
+
+	SetLanguage("en")
+	if !IsYes("Y\n") || IsYes("ja") {
+		t.Error("en: want y accepted and ja refused")
+	}
+	SetLanguage("nb")
+	for _, a := range []string{"j", "ja\n", "y", "YES"} {
+		if !IsYes(a) {
+			t.Errorf("nb: %q should mean yes", a)
+		}
+	}
+	if IsYes("") || IsYes("n") || IsYes("nei") {
+		t.Error("nb: empty, n and nei should mean no")
+	}
+}
+
+// every translation must keep the format verbs of its key, or T would
+// print %!d(MISSING) and the like
+func TestBundleVerbs(t *testing.T) {
+	for lang, bundle := range bundles {
+		for key, tr := range bundle {
+			if verbs(key) != verbs(tr) {
+				t.Errorf("%s: %q and %q use different format verbs", lang, key, tr)
+			}
+		}
+	}
+}
+
+func verbs(s string) string {
+	var v []byte
+	for i := 0; i < len(s)-1; i++ {
+		if s[i] == '%' {
+			v = append(v, s[i+1])
+			i++
+		}
+	}
+	return string(v)
+}
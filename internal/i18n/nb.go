@@ -0,0 +1,116 @@
+package i18n
+
+// norwegian is the Norwegian (bokmål) bundle
+var norwegian = map[string]string{
+	// prompts
+	"y|yes":                         "j|ja",
+	"[y/N]":                         "[j/N]",
+	"Apply these changes?":          "Vil du lagre disse endringene?",
+	"Aborted.":                      "Avbrutt.",
+	"No changes to %s.\n":           "Ingen endringer i %s.\n",
+	"Changes to %s:\n":              "Endringer i %s:\n",
+	"Delete these %d transactions?": "Slette disse %d transaksjonene?",
+	"Merge these payees?":           "Slå sammen disse mottakerne?",
+	"Create these transactions?":    "Opprette disse transaksjonene?",
+	"Budget %q is protected. Type its name to allow changes: ": "Budsjettet %q er beskyttet. Skriv navnet for å tillate endringer: ",
+
+	// errors
+	"confirmation required but stdin is not a terminal; pass --yes to proceed":                                "bekreftelse kreves, men stdin er ikke en terminal; bruk --yes for å fortsette",
+	"no budget specified. Use --budget flag or set a default with 'ynabctl config set-default-budget <id>'":   "ingen budsjett valgt. Bruk --budget eller velg et standardbudsjett med 'ynabctl config set-default-budget <id>'",
+	"YNAB API token not configured. Run 'ynabctl config set-token <token>' to set it":                         "YNAB API-nøkkel er ikke satt opp. Kjør 'ynabctl config set-token <nøkkel>'",
+	"budget %q is protected; pass --force to change it":                                                       "budsjettet %q er beskyttet; bruk --force for å endre det",
+	"aborted: protected budget %q not confirmed":                                                              "avbrutt: beskyttet budsjett %q ble ikke bekreftet",
+	"aborted: %q does not match the budget name":                                                              "avbrutt: %q er ikke navnet på budsjettet",
+	"Hint: check your API token with 'ynabctl config show' or set a new one with 'ynabctl config set-token'.": "Tips: sjekk API-nøkkelen med 'ynabctl config show' eller sett en ny med 'ynabctl config set-token'.",
+	"Hint: YNAB allows 200 requests per hour; wait a while and try again.":                                    "Tips: YNAB tillater 200 forespørsler i timen; vent litt og prøv igjen.",
+	"Hint: could not reach the YNAB API; check your network connection.":                                      "Tips: fikk ikke kontakt med YNAB; sjekk nettverkstilkoblingen.",
+	"Hint: run the command once without --offline to make its data available offline.":                        "Tips: kjør kommandoen én gang uten --offline for å gjøre dataene tilgjengelige frakoblet.",
+	"Hint: a field changed type in the API; run 'ynabctl api capabilities' and update ynabctl.":               "Tips: et felt har endret type i API-et; kjør 'ynabctl api capabilities' og oppdater ynabctl.",
+	"Offline: data as of %s (%s old)":                                                                         "Frakoblet: data fra %s (%s gamle)",
+
+	// table headers
+	"ACCOUNT":           "KONTO",
+	"ACCOUNTS":          "KONTOER",
+	"ACTION":            "HANDLING",
+	"ACTIVITY":          "AKTIVITET",
+	"AFTER":             "ETTER",
+	"AGE":               "ALDER",
+	"AMOUNT":            "BELØP",
+	"AVAILABLE":         "TILGJENGELIG",
+	"BALANCE":           "SALDO",
+	"BEFORE":            "FØR",
+	"BUDGET":            "BUDSJETT",
+	"BUDGETED":          "BUDSJETTERT",
+	"CAP":               "TAK",
+	"CATEGORY":          "KATEGORI",
+	"CHANGE":            "ENDRING",
+	"CLEARED":           "AVSTEMT",
+	"CLOSED":            "LUKKET",
+	"COMMAND":           "KOMMANDO",
+	"COUNT":             "ANTALL",
+	"CURRENCY":          "VALUTA",
+	"DATE":              "DATO",
+	"DATE NEXT":         "NESTE DATO",
+	"DAYS":              "DAGER",
+	"DIFF":              "DIFF",
+	"FIELD":             "FELT",
+	"FIX":               "RETTING",
+	"FIRST MONTH":       "FØRSTE MÅNED",
+	"FREQUENCY":         "FREKVENS",
+	"FUNDED":            "FINANSIERT",
+	"GROUP":             "GRUPPE",
+	"IMPORTED":          "IMPORTERT",
+	"IMPORTED PAYEE":    "IMPORTERT MOTTAKER",
+	"INCOME":            "INNTEKT",
+	"INTEREST":          "RENTER",
+	"KEY":               "NØKKEL",
+	"KIND":              "SORT",
+	"LAST":              "SISTE",
+	"LAST ACTIVITY":     "SIST AKTIV",
+	"LAST MODIFIED":     "SIST ENDRET",
+	"LAST MONTH":        "FORRIGE MÅNED",
+	"LAST RECONCILED":   "SIST AVSTEMT",
+	"LINE":              "LINJE",
+	"MANUAL":            "MANUELL",
+	"MANUAL PAYEE":      "MANUELL MOTTAKER",
+	"MEMBER":            "MEDLEM",
+	"MEMO":              "NOTAT",
+	"MESSAGE":           "MELDING",
+	"MONTH":             "MÅNED",
+	"MONTHLY":           "MÅNEDLIG",
+	"NAME":              "NAVN",
+	"NEED":              "BEHOV",
+	"NET WORTH":         "NETTOFORMUE",
+	"NEXT":              "NESTE",
+	"ON BUDGET":         "I BUDSJETT",
+	"ON-BUDGET BALANCE": "SALDO I BUDSJETT",
+	"PAYEE":             "MOTTAKER",
+	"PAYMENT":           "BETALING",
+	"PRINCIPAL":         "AVDRAG",
+	"PROBLEM":           "PROBLEM",
+	"RATE":              "KURS",
+	"REASON":            "GRUNN",
+	"REFERENCE":         "REFERANSE",
+	"REMAINING":         "IGJEN",
+	"RESULT":            "RESULTAT",
+	"RULE":              "REGEL",
+	"SCHEDULED":         "PLANLAGT",
+	"SETTING":           "INNSTILLING",
+	"SEVERITY":          "ALVORLIGHET",
+	"SHORT":             "MANGLER",
+	"SHORTFALL":         "MANGLER",
+	"SINCE":             "SIDEN",
+	"SOURCE":            "KILDE",
+	"SPENDING":          "FORBRUK",
+	"SPENT":             "BRUKT",
+	"STATE":             "STATUS",
+	"TIME":              "TID",
+	"TO BE BUDGETED":    "TIL BUDSJETTERING",
+	"TOTAL":             "TOTALT",
+	"TRANSFER ACCOUNT":  "OVERFØRINGSKONTO",
+	"TYPE":              "TYPE",
+	"UPCOMING":          "KOMMENDE",
+	"USED":              "ANDEL",
+	"VALUE":             "VERDI",
+	"WEEKLY":            "UKENTLIG",
+}
@@ -0,0 +1,146 @@
+// Package idcache keeps a persisted map from YNAB IDs to names.
+//
+// The map is filled opportunistically from API responses the CLI fetches
+// anyway, and is used to show names where a payload only carries an ID
+// and to answer "what is this UUID?" without an API call.
+package idcache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Entry is what is known about one ID
+type Entry struct {
+	ID       string `json:"id"`
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	BudgetID string `json:"budget_id,omitempty"`
+}
+
+// Cache is an ID to name map backed by a JSON file. Methods are safe for
+// concurrent use and on a nil Cache.
+type Cache struct {
+	path    string
+	mu      sync.Mutex
+	entries map[string]Entry
+	dirty   bool
+}
+
+// Path returns the default cache file location in the user cache
+// directory (e.g. ~/.cache/ynabctl/ids.json)
+func Path() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "ynabctl", "ids.json")
+}
+
+// New returns an empty cache that saves to path
+func New(path string) *Cache {
+	return &Cache{path: path, entries: map[string]Entry{}}
+}
+
+// Load reads the cache at path. A missing file yields an empty cache.
+func Load(path string) (*Cache, error) {
+	c := New(path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return c, err
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return c, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for _, e := range entries {
+		c.entries[e.ID] = e
+	}
+	return c, nil
+}
+
+// Save writes the cache back to its file if anything changed
+func (c *Cache) Save() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.dirty {
+		return nil
+	}
+
+	entries := make([]Entry, 0, len(c.entries))
+	for _, e := range c.entries {
+		entries = append(entries, e)
+	}
+	sortEntries(entries)
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return fmt.Errorf("mkdir %s: %w", filepath.Dir(c.path), err)
+	}
+	if err := os.WriteFile(c.path, data, 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", c.path, err)
+	}
+	c.dirty = false
+	return nil
+}
+
+// Put records a name for id
+func (c *Cache) Put(e Entry) {
+	if c == nil || e.ID == "" || e.Name == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if old, ok := c.entries[e.ID]; ok && old == e {
+		return
+	}
+	c.entries[e.ID] = e
+	c.dirty = true
+}
+
+// Lookup returns the entry for id
+func (c *Cache) Lookup(id string) (Entry, bool) {
+	if c == nil {
+		return Entry{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[id]
+	return e, ok
+}
+
+// Name returns the cached name for id, or "" if unknown
+func (c *Cache) Name(id string) string {
+	e, _ := c.Lookup(id)
+	return e.Name
+}
+
+// Prefix returns the entries whose ID starts with prefix, ignoring case
+func (c *Cache) Prefix(prefix string) []Entry {
+	if c == nil {
+		return nil
+	}
+	prefix = strings.ToLower(prefix)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var entries []Entry
+	for id, e := range c.entries {
+		if strings.HasPrefix(strings.ToLower(id), prefix) {
+			entries = append(entries, e)
+		}
+	}
+	sortEntries(entries)
+	return entries
+}
@@ -0,0 +1,83 @@
+package idcache
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLearn(t *testing.T) {
+	c := New("")
+	c.Learn("b1", []byte(`{"data":{
+		"category_groups":[{"id":"g1","name":"Bills","categories":[
+			{"id":"c1","name":"Rent","category_group_id":"g1","category_group_name":"Bills"}
+		]}],
+		"transactions":[{"id":"t1","payee_id":"p1","payee_name":"Landlord","category_id":"c1","category_name":null,"account_id":"a1","account_name":"Checking"}]
+	}}`))
+
+	want := map[string]Entry{
+		"g1": {ID: "g1", Kind: "category_group", Name: "Bills", BudgetID: "b1"},
+		"c1": {ID: "c1", Kind: "category", Name: "Rent", BudgetID: "b1"},
+		"p1": {ID: "p1", Kind: "payee", Name: "Landlord", BudgetID: "b1"},
+		"a1": {ID: "a1", Kind: "account", Name: "Checking", BudgetID: "b1"},
+	}
+	for id, e := range want {
+		got, ok := c.Lookup(id)
+		if !ok || got != e {
+			t.Errorf("Lookup(%s) = %+v, %v; want %+v", id, got, ok, e)
+		}
+	}
+	if _, ok := c.Lookup("t1"); ok {
+		t.Error("transactions have no name and should not be cached")
+	}
+
+	c.Learn("", []byte(`{"data":{"budgets":[{"id":"b1","name":"Home"}]}}`))
+	if got, _ := c.Lookup("b1"); got.Kind != "budget" || got.BudgetID != "" {
+		t.Errorf("budget entry = %+v", got)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "ids.json")
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Put(Entry{ID: "x", Kind: "payee", Name: "Shop"})
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Name("x") != "Shop" {
+		t.Errorf("Name(x) = %q after reload", c.Name("x"))
+	}
+
+	var nilCache *Cache
+	if nilCache.Name("x") != "" || nilCache.Save() != nil {
+		t.Error("nil cache should be a no-op")
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	c := New("")
+	c.Put(Entry{ID: "3fa85f64-5717-4562-b3fc-2c963f66afa6", Kind: "payee", Name: "Landlord"})
+	c.Put(Entry{ID: "3fa85f99-1111-4562-b3fc-2c963f66afa6", Kind: "account", Name: "Checking"})
+	c.Put(Entry{ID: "7c1e0b2a-5717-4562-b3fc-2c963f66afa6", Kind: "payee", Name: "Grocer"})
+
+	if got := c.Prefix("3FA85F6"); len(got) != 1 || got[0].Name != "Landlord" {
+		t.Errorf("Prefix(3FA85F6) = %+v", got)
+	}
+	if got := c.Prefix("3fa85f"); len(got) != 2 || got[0].Kind != "account" {
+		t.Errorf("Prefix(3fa85f) = %+v", got)
+	}
+	if got := c.Prefix("ffff"); len(got) != 0 {
+		t.Errorf("Prefix(ffff) = %+v", got)
+	}
+	var nilCache *Cache
+	if got := nilCache.Prefix("3fa"); got != nil {
+		t.Errorf("nil cache Prefix = %+v", got)
+	}
+}
@@ -0,0 +1,74 @@
+package idcache
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// kindOf maps the JSON key holding a record (or list of records) to the
+// kind of record it is
+var kindOf = map[string]string{
+	"budgets":         "budget",
+	"budget":          "budget",
+	"accounts":        "account",
+	"account":         "account",
+	"category_groups": "category_group",
+	"categories":      "category",
+	"category":        "category",
+	"payees":          "payee",
+	"payee":           "payee",
+}
+
+// refFields are "<kind>_id"/"<kind>_name" pairs that records carry for
+// the entities they reference, e.g. a transaction's payee
+var refFields = []string{"account", "payee", "category", "category_group"}
+
+// Learn records every ID/name pair found in an API response body: named
+// records such as accounts and payees, and the references carried by
+// transactions and categories. Malformed bodies are ignored.
+func (c *Cache) Learn(budgetID string, body []byte) {
+	if c == nil {
+		return
+	}
+	var v interface{}
+	if json.Unmarshal(body, &v) != nil {
+		return
+	}
+	c.walk(budgetID, "", v)
+}
+
+func (c *Cache) walk(budgetID, kind string, v interface{}) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		if kind != "" {
+			id, _ := x["id"].(string)
+			name, _ := x["name"].(string)
+			entryBudget := budgetID
+			if kind == "budget" {
+				entryBudget = ""
+			}
+			c.Put(Entry{ID: id, Kind: kind, Name: name, BudgetID: entryBudget})
+		}
+		for _, ref := range refFields {
+			id, _ := x[ref+"_id"].(string)
+			name, _ := x[ref+"_name"].(string)
+			c.Put(Entry{ID: id, Kind: ref, Name: name, BudgetID: budgetID})
+		}
+		for k, child := range x {
+			c.walk(budgetID, kindOf[k], child)
+		}
+	case []interface{}:
+		for _, child := range x {
+			c.walk(budgetID, kind, child)
+		}
+	}
+}
+
+func sortEntries(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Kind != entries[j].Kind {
+			return entries[i].Kind < entries[j].Kind
+		}
+		return entries[i].ID < entries[j].ID
+	})
+}
@@ -0,0 +1,286 @@
+// Package ledger mirrors YNAB transactions as one JSON file per month
+// in a local directory, so the budget can be kept under version control
+// and new transactions can be written offline.
+//
+// Remote transactions are the source of truth for entries that carry an
+// id. Entries without an id are new local transactions waiting to be
+// pushed; their import_id identifies them once YNAB has created them.
+package ledger
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// Entry is a transaction as stored in a month file. Amount is a decimal
+// currency value (negative for outflows) so files are easy to edit.
+type Entry struct {
+	ID         string  `json:"id,omitempty"`
+	ImportID   string  `json:"import_id,omitempty"`
+	Date       string  `json:"date"`
+	AccountID  string  `json:"account_id"`
+	Account    string  `json:"account,omitempty"`
+	PayeeName  string  `json:"payee,omitempty"`
+	CategoryID string  `json:"category_id,omitempty"`
+	Category   string  `json:"category,omitempty"`
+	Memo       string  `json:"memo,omitempty"`
+	Amount     float64 `json:"amount"`
+	Cleared    string  `json:"cleared,omitempty"`
+	Approved   bool    `json:"approved"`
+}
+
+// State is persisted between syncs in the ledger directory.
+type State struct {
+	BudgetID        string `json:"budget_id"`
+	ServerKnowledge int64  `json:"server_knowledge"`
+}
+
+const (
+	stateFile = ".sync-state.json"
+	undated   = "undated"
+)
+
+var (
+	reMonth = regexp.MustCompile(`^\d{4}-\d{2}$`)
+	// reMonthFile matches the names of the files the ledger owns; other
+	// files in the directory are left alone
+	reMonthFile = regexp.MustCompile(`^(\d{4}-\d{2}|` + undated + `)\.json$`)
+)
+
+// Month returns the YYYY-MM file key for an entry.
+func (e Entry) Month() string {
+	if len(e.Date) < 7 || !reMonth.MatchString(e.Date[:7]) {
+		return undated
+	}
+	return e.Date[:7]
+}
+
+// Validate reports why a new local entry cannot be pushed to YNAB.
+func (e Entry) Validate() error {
+	if e.Date == "" || e.AccountID == "" {
+		return fmt.Errorf("needs date and account_id")
+	}
+	if _, err := time.Parse("2006-01-02", e.Date); err != nil {
+		return fmt.Errorf("date %q is not YYYY-MM-DD", e.Date)
+	}
+	return nil
+}
+
+// FromTransaction converts a YNAB transaction to a ledger entry.
+func FromTransaction(t ynab.Transaction) Entry {
+	return Entry{
+		ID:         t.ID,
+		ImportID:   t.ImportID,
+		Date:       t.Date,
+		AccountID:  t.AccountID,
+		Account:    t.AccountName,
+		PayeeName:  t.PayeeName,
+		CategoryID: t.CategoryID,
+		Category:   t.CategoryName,
+		Memo:       t.Memo,
+		Amount:     ynab.MilliunitsToAmount(t.Amount),
+		Cleared:    t.Cleared,
+		Approved:   t.Approved,
+	}
+}
+
+// ToSave converts a new local entry to a create request.
+func (e Entry) ToSave() ynab.SaveTransaction {
+	return ynab.SaveTransaction{
+		AccountID:  e.AccountID,
+		Date:       e.Date,
+		Amount:     ynab.AmountToMilliunits(e.Amount),
+		PayeeName:  e.PayeeName,
+		CategoryID: e.CategoryID,
+		Memo:       e.Memo,
+		Cleared:    e.Cleared,
+		Approved:   e.Approved,
+		ImportID:   e.ImportID,
+	}
+}
+
+// AssignImportIDs gives every new entry without an import_id one derived
+// from its content. Callers save the entries before pushing so that a
+// retried push reuses the same import_id and YNAB skips duplicates.
+func AssignImportIDs(entries []Entry) {
+	for i := range entries {
+		e := &entries[i]
+		if e.ID != "" || e.ImportID != "" {
+			continue
+		}
+		sum := sha1.Sum([]byte(fmt.Sprintf("%s|%s|%.3f|%s|%s|%d", e.AccountID, e.Date, e.Amount, e.PayeeName, e.Memo, i)))
+		// YNAB limits import_id to 36 characters
+		e.ImportID = "YNABCTL:" + hex.EncodeToString(sum[:])[:28]
+	}
+}
+
+// Pending returns the entries that have not been pushed to YNAB yet.
+func Pending(entries []Entry) []Entry {
+	var out []Entry
+	for _, e := range entries {
+		if e.ID == "" {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Merge applies remote transactions to local entries. Remote entries
+// replace local ones with the same id, or with the same import_id for
+// entries that were pushed but not yet pulled. Deleted remote
+// transactions are removed.
+func Merge(entries []Entry, remote []ynab.Transaction) []Entry {
+	byID := map[string]int{}
+	byImport := map[string]int{}
+	for i, e := range entries {
+		if e.ID != "" {
+			byID[e.ID] = i
+		} else if e.ImportID != "" {
+			byImport[e.ImportID] = i
+		}
+	}
+
+	removed := map[int]bool{}
+	for _, t := range remote {
+		idx, ok := byID[t.ID]
+		if !ok && t.ImportID != "" {
+			idx, ok = byImport[t.ImportID]
+		}
+		if t.Deleted {
+			if ok {
+				removed[idx] = true
+			}
+			continue
+		}
+		if ok {
+			entries[idx] = FromTransaction(t)
+			byID[t.ID] = idx
+			continue
+		}
+		entries = append(entries, FromTransaction(t))
+		byID[t.ID] = len(entries) - 1
+	}
+
+	out := make([]Entry, 0, len(entries))
+	for i, e := range entries {
+		if !removed[i] {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// monthFiles lists the month files in dir, i.e. YYYY-MM.json and
+// undated.json
+func monthFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, f := range files {
+		if reMonthFile.MatchString(filepath.Base(f)) {
+			out = append(out, f)
+		}
+	}
+	return out, nil
+}
+
+// Load reads every month file in dir. A missing dir is treated as empty;
+// files not named after a month are ignored.
+func Load(dir string) ([]Entry, error) {
+	files, err := monthFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		var month []Entry
+		if err := json.Unmarshal(data, &month); err != nil {
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+		entries = append(entries, month...)
+	}
+	return entries, nil
+}
+
+// Save writes entries to one file per month, sorted by date, and removes
+// month files that no longer have entries.
+func Save(dir string, entries []Entry) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	months := map[string][]Entry{}
+	for _, e := range entries {
+		months[e.Month()] = append(months[e.Month()], e)
+	}
+
+	existing, err := monthFiles(dir)
+	if err != nil {
+		return err
+	}
+	for _, f := range existing {
+		name := strings.TrimSuffix(filepath.Base(f), ".json")
+		if _, ok := months[name]; !ok {
+			if err := os.Remove(f); err != nil {
+				return err
+			}
+		}
+	}
+
+	for month, list := range months {
+		sort.SliceStable(list, func(i, j int) bool {
+			if list[i].Date != list[j].Date {
+				return list[i].Date < list[j].Date
+			}
+			return list[i].ID < list[j].ID
+		})
+		data, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, month+".json"), append(data, '\n'), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadState reads the sync state from dir. A missing file yields an
+// empty state.
+func LoadState(dir string) (State, error) {
+	var st State
+	data, err := os.ReadFile(filepath.Join(dir, stateFile))
+	if os.IsNotExist(err) {
+		return st, nil
+	}
+	if err != nil {
+		return st, err
+	}
+	err = json.Unmarshal(data, &st)
+	return st, err
+}
+
+// SaveState writes the sync state to dir.
+func SaveState(dir string, st State) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, stateFile), append(data, '\n'), 0o644)
+}
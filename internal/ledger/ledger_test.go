@@ -0,0 +1,121 @@
+package ledger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestMerge(t *testing.T) {
+	entries := []Entry{
+		{ID: "a", Date: "2025-01-05", Amount: -10},
+		{ID: "b", Date: "2025-01-06", Amount: -20},
+		{ImportID: "YNABCTL:new", Date: "2025-02-01", Amount: -5},
+	}
+	remote := []ynab.Transaction{
+		{ID: "a", Date: "2025-01-05", Amount: -12000},
+		{ID: "b", Deleted: true},
+		{ID: "c", ImportID: "YNABCTL:new", Date: "2025-02-01", Amount: -5000},
+		{ID: "d", Date: "2025-03-01", Amount: 100000},
+	}
+
+	got := Merge(entries, remote)
+	if len(got) != 3 {
+		t.Fatalf("got %d entries, want 3: %+v", len(got), got)
+	}
+	if got[0].ID != "a" || got[0].Amount != -12 {
+		t.Errorf("entry a not updated: %+v", got[0])
+	}
+	if got[1].ID != "c" {
+		t.Errorf("pushed entry not linked by import_id: %+v", got[1])
+	}
+	if got[2].ID != "d" || got[2].Month() != "2025-03" {
+		t.Errorf("new remote entry not added: %+v", got[2])
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	entries := []Entry{
+		{ID: "b", Date: "2025-01-06", Amount: -20},
+		{ID: "a", Date: "2025-01-05", Amount: -10},
+		{Date: "2025-02-01", Amount: -5, AccountID: "acc"},
+	}
+	AssignImportIDs(entries)
+	if entries[2].ImportID == "" || len(entries[2].ImportID) > 36 {
+		t.Errorf("bad import id %q", entries[2].ImportID)
+	}
+	if err := Save(dir, entries); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveState(dir, State{ServerKnowledge: 42}); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 3 || loaded[0].ID != "a" {
+		t.Errorf("unexpected entries: %+v", loaded)
+	}
+	if len(Pending(loaded)) != 1 {
+		t.Errorf("want 1 pending entry")
+	}
+
+	if err := Save(dir, loaded[:2]); err != nil {
+		t.Fatal(err)
+	}
+	loaded, _ = Load(dir)
+	if len(loaded) != 2 {
+		t.Errorf("stale month file not removed: %+v", loaded)
+	}
+	st, err := LoadState(dir)
+	if err != nil || st.ServerKnowledge != 42 {
+		t.Errorf("state lost: %+v %v", st, err)
+	}
+}
+
+func TestSaveLeavesOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	other := filepath.Join(dir, "rules.json")
+	if err := os.WriteFile(other, []byte(`[{"match": "Bakery"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Save(dir, []Entry{{ID: "a", Date: "2025-01-05"}, {Date: "soon", AccountID: "acc"}}); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 2 {
+		t.Errorf("got %d entries, want 2: %+v", len(loaded), loaded)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "undated.json")); err != nil {
+		t.Errorf("entry without a month not saved to undated.json: %v", err)
+	}
+	if err := Save(dir, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("unrelated file removed: %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, e := range []Entry{
+		{AccountID: "acc"},
+		{Date: "2025-03-14"},
+		{Date: "14.03.2025", AccountID: "acc"},
+	} {
+		if e.Validate() == nil {
+			t.Errorf("%+v: expected an error", e)
+		}
+	}
+	if err := (Entry{Date: "2025-03-14", AccountID: "acc"}).Validate(); err != nil {
+		t.Error(err)
+	}
+}
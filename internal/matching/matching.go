@@ -0,0 +1,112 @@
+// Package matching pairs imported transactions with manually entered
+// ones that record the same payment, as YNAB does when a bank import
+// arrives.
+package matching
+
+import (
+	"sort"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// Pair is an unapproved imported transaction and the manually entered
+// transaction it probably duplicates
+type Pair struct {
+	Imported   ynab.Transaction `json:"imported"`
+	Manual     ynab.Transaction `json:"manual"`
+	DaysApart  int              `json:"days_apart"`
+	AmountDiff int64            `json:"amount_diff" amount:"milliunits"`
+}
+
+// Options limit how far apart a pair may be
+type Options struct {
+	// MaxDays is the largest date difference in days
+	MaxDays int
+	// Tolerance is the largest amount difference in milliunits
+	Tolerance int64
+	// Unmatched lists pairs the user said are not the same payment
+	Unmatched *Unmatched
+}
+
+// FindPairs pairs unapproved imported transactions with manual ones in
+// the same account. Closest amounts are paired first, then closest
+// dates; every transaction is used at most once. Transactions YNAB has
+// already matched, transfers' imported sides and deleted ones are
+// ignored.
+func FindPairs(txns []ynab.Transaction, opts Options) []Pair {
+	var imported, manual []ynab.Transaction
+	for _, t := range txns {
+		if t.Deleted || t.MatchedTransactionID != "" {
+			continue
+		}
+		switch {
+		case t.ImportID != "" && !t.Approved:
+			imported = append(imported, t)
+		case t.ImportID == "":
+			manual = append(manual, t)
+		}
+	}
+
+	var candidates []Pair
+	for _, i := range imported {
+		for _, m := range manual {
+			if i.AccountID != m.AccountID || (i.Amount < 0) != (m.Amount < 0) {
+				continue
+			}
+			if opts.Unmatched.Has(i.ID, m.ID) {
+				continue
+			}
+			days, ok := daysBetween(i.Date, m.Date)
+			if !ok || days > opts.MaxDays {
+				continue
+			}
+			diff := i.Amount - m.Amount
+			if diff < 0 {
+				diff = -diff
+			}
+			if diff > opts.Tolerance {
+				continue
+			}
+			candidates = append(candidates, Pair{Imported: i, Manual: m, DaysApart: days, AmountDiff: diff})
+		}
+	}
+	sort.SliceStable(candidates, func(a, b int) bool {
+		if candidates[a].AmountDiff != candidates[b].AmountDiff {
+			return candidates[a].AmountDiff < candidates[b].AmountDiff
+		}
+		if candidates[a].DaysApart != candidates[b].DaysApart {
+			return candidates[a].DaysApart < candidates[b].DaysApart
+		}
+		return candidates[a].Imported.Date < candidates[b].Imported.Date
+	})
+
+	used := map[string]bool{}
+	pairs := []Pair{}
+	for _, p := range candidates {
+		if used[p.Imported.ID] || used[p.Manual.ID] {
+			continue
+		}
+		used[p.Imported.ID] = true
+		used[p.Manual.ID] = true
+		pairs = append(pairs, p)
+	}
+	sort.SliceStable(pairs, func(a, b int) bool { return pairs[a].Imported.Date < pairs[b].Imported.Date })
+	return pairs
+}
+
+func daysBetween(a, b string) (int, bool) {
+	ta, err := time.Parse("2006-01-02", a)
+	if err != nil {
+		return 0, false
+	}
+	tb, err := time.Parse("2006-01-02", b)
+	if err != nil {
+		return 0, false
+	}
+	days := int(ta.Sub(tb).Hours() / 24)
+	if days < 0 {
+		days = -days
+	}
+	return days, true
+}
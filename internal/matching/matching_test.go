@@ -0,0 +1,69 @@
+package matching
+
+import (
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestFindPairs(t *testing.T) {
+	txns := []ynab.Transaction{
+		// imported, unapproved
+		{ID: "i1", AccountID: "chk", Date: "2025-01-05", Amount: -42000, ImportID: "YNAB:-42000:2025-01-05:1"},
+		{ID: "i2", AccountID: "chk", Date: "2025-01-06", Amount: -42500, ImportID: "YNAB:-42500:2025-01-06:1"},
+		{ID: "i3", AccountID: "chk", Date: "2025-02-20", Amount: -9900, ImportID: "YNAB:-9900:2025-02-20:1"},
+		{ID: "i4", AccountID: "chk", Date: "2025-01-05", Amount: -100, ImportID: "x", Approved: true},
+		// manual
+		{ID: "m1", AccountID: "chk", Date: "2025-01-04", Amount: -42500},
+		{ID: "m2", AccountID: "chk", Date: "2025-01-03", Amount: -42000},
+		{ID: "m3", AccountID: "sav", Date: "2025-02-20", Amount: -9900},
+		{ID: "m4", AccountID: "chk", Date: "2025-01-05", Amount: 42000}, // inflow
+	}
+
+	pairs := FindPairs(txns, Options{MaxDays: 7, Tolerance: 1000})
+	if len(pairs) != 2 {
+		t.Fatalf("pairs = %+v", pairs)
+	}
+	// exact amounts are preferred over the closer date
+	if pairs[0].Imported.ID != "i1" || pairs[0].Manual.ID != "m2" || pairs[0].DaysApart != 2 {
+		t.Errorf("first pair = %s/%s", pairs[0].Imported.ID, pairs[0].Manual.ID)
+	}
+	if pairs[1].Imported.ID != "i2" || pairs[1].Manual.ID != "m1" || pairs[1].AmountDiff != 0 {
+		t.Errorf("second pair = %s/%s", pairs[1].Imported.ID, pairs[1].Manual.ID)
+	}
+
+	if pairs := FindPairs(txns, Options{MaxDays: 1, Tolerance: 0}); len(pairs) != 0 {
+		t.Errorf("tight limits should find nothing: %+v", pairs)
+	}
+}
+
+func TestUnmatched(t *testing.T) {
+	path := t.TempDir() + "/unmatched.json"
+	u, err := LoadUnmatched(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Add("i1", "m1"); err != nil {
+		t.Fatal(err)
+	}
+
+	u, err = LoadUnmatched(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !u.Has("i1", "m1") || u.Has("m1", "i1") {
+		t.Errorf("pairs = %v", u.Pairs)
+	}
+
+	txns := []ynab.Transaction{
+		{ID: "i1", AccountID: "chk", Date: "2025-01-05", Amount: -1000, ImportID: "x"},
+		{ID: "m1", AccountID: "chk", Date: "2025-01-05", Amount: -1000},
+	}
+	if pairs := FindPairs(txns, Options{MaxDays: 3, Unmatched: u}); len(pairs) != 0 {
+		t.Errorf("unmatched pair offered again: %+v", pairs)
+	}
+	var none *Unmatched
+	if none.Has("i1", "m1") {
+		t.Error("nil Unmatched has pairs")
+	}
+}
@@ -0,0 +1,62 @@
+package matching
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/langtind/ynabctl/internal/config"
+)
+
+// Unmatched remembers pairs that were rejected, so they are not offered
+// again
+type Unmatched struct {
+	Pairs map[string]bool `json:"pairs"`
+
+	path string
+}
+
+// UnmatchedPath returns the file holding the rejected pairs of a budget
+func UnmatchedPath(budgetID string) string {
+	return filepath.Join(config.StateDir(), "unmatched", budgetID+".json")
+}
+
+// LoadUnmatched reads the rejected pairs from path. A missing file is
+// not an error.
+func LoadUnmatched(path string) (*Unmatched, error) {
+	u := &Unmatched{Pairs: map[string]bool{}, path: path}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return u, nil
+	}
+	if err != nil {
+		return u, err
+	}
+	if err := json.Unmarshal(data, u); err != nil {
+		return u, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if u.Pairs == nil {
+		u.Pairs = map[string]bool{}
+	}
+	return u, nil
+}
+
+// Has reports whether the pair was rejected. It is safe on a nil
+// Unmatched.
+func (u *Unmatched) Has(importedID, manualID string) bool {
+	return u != nil && u.Pairs[importedID+":"+manualID]
+}
+
+// Add records a rejected pair and saves the file
+func (u *Unmatched) Add(importedID, manualID string) error {
+	u.Pairs[importedID+":"+manualID] = true
+	data, err := json.MarshalIndent(u, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(u.path), 0o700); err != nil {
+		return fmt.Errorf("mkdir %s: %w", filepath.Dir(u.path), err)
+	}
+	return os.WriteFile(u.path, data, 0o600)
+}
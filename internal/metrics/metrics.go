@@ -0,0 +1,89 @@
+// Package metrics renders budget figures in the Prometheus text format,
+// so balances can be graphed and alerted on next to other metrics.
+// Amounts are in the budget's currency, not milliunits.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// Write writes the metrics of a budget: the balances of its open
+// accounts and the figures of the given month and its categories
+func Write(w io.Writer, budget string, accounts []ynab.Account, month *ynab.Month) error {
+	m := &writer{w: w, budget: budget}
+
+	m.help("ynab_account_balance", "Balance of an open account")
+	for _, a := range accounts {
+		if !a.Closed && !a.Deleted {
+			m.amount("ynab_account_balance", a.Balance, "account", a.Name, "type", a.Type, "on_budget", fmt.Sprint(a.OnBudget))
+		}
+	}
+	m.help("ynab_account_cleared_balance", "Cleared balance of an open account")
+	for _, a := range accounts {
+		if !a.Closed && !a.Deleted {
+			m.amount("ynab_account_cleared_balance", a.ClearedBalance, "account", a.Name, "type", a.Type, "on_budget", fmt.Sprint(a.OnBudget))
+		}
+	}
+
+	if month != nil {
+		m.help("ynab_to_be_budgeted", "Money not assigned to a category this month")
+		m.amount("ynab_to_be_budgeted", month.ToBeBudgeted, "month", month.Month)
+		m.help("ynab_age_of_money_days", "Age of money in days")
+		m.value("ynab_age_of_money_days", fmt.Sprint(month.AgeOfMoney), "month", month.Month)
+		for _, metric := range []struct {
+			name, help string
+			value      func(ynab.Category) int64
+		}{
+			{"ynab_category_budgeted", "Amount assigned to a category this month", func(c ynab.Category) int64 { return c.Budgeted }},
+			{"ynab_category_activity", "Spending and income in a category this month", func(c ynab.Category) int64 { return c.Activity }},
+			{"ynab_category_balance", "Available balance of a category", func(c ynab.Category) int64 { return c.Balance }},
+		} {
+			m.help(metric.name, metric.help)
+			for _, c := range month.Categories {
+				if !c.Hidden && !c.Deleted {
+					m.amount(metric.name, metric.value(c), "month", month.Month, "group", c.CategoryGroupName, "category", c.Name)
+				}
+			}
+		}
+	}
+	return m.err
+}
+
+// writer writes metric lines, keeping the first error
+type writer struct {
+	w      io.Writer
+	budget string
+	err    error
+}
+
+func (m *writer) help(name, help string) {
+	m.printf("# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+func (m *writer) amount(name string, milliunits int64, labels ...string) {
+	m.value(name, fmt.Sprintf("%.3f", ynab.MilliunitsToAmount(milliunits)), labels...)
+}
+
+// value writes one sample; labels are name, value pairs and every sample
+// is labelled with the budget
+func (m *writer) value(name, value string, labels ...string) {
+	pairs := []string{`budget="` + labelEscaper.Replace(m.budget) + `"`}
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, labels[i]+`="`+labelEscaper.Replace(labels[i+1])+`"`)
+	}
+	m.printf("%s{%s} %s\n", name, strings.Join(pairs, ","), value)
+}
+
+func (m *writer) printf(format string, args ...any) {
+	if m.err == nil {
+		_, m.err = fmt.Fprintf(m.w, format, args...)
+	}
+}
+
+// labelEscaper escapes the characters the text format does not allow in
+// label values
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestWrite(t *testing.T) {
+	accounts := []ynab.Account{
+		{Name: "Checking", Type: "checking", OnBudget: true, Balance: 1234560, ClearedBalance: 1000000},
+		{Name: "Old card", Type: "creditCard", Closed: true, Balance: 0},
+	}
+	month := &ynab.Month{
+		Month:        "2025-03-01",
+		ToBeBudgeted: 2010,
+		AgeOfMoney:   42,
+		Categories: []ynab.Category{
+			{CategoryGroupName: "Everyday", Name: `Food "out"`, Budgeted: 300000, Activity: -125500, Balance: 174500},
+			{CategoryGroupName: "Everyday", Name: "Hidden", Hidden: true},
+		},
+	}
+	var b strings.Builder
+	if err := Write(&b, "Home", accounts, month); err != nil {
+		t.Fatal(err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		"# TYPE ynab_account_balance gauge\n",
+		`ynab_account_balance{budget="Home",account="Checking",type="checking",on_budget="true"} 1234.560` + "\n",
+		`ynab_account_cleared_balance{budget="Home",account="Checking",type="checking",on_budget="true"} 1000.000` + "\n",
+		`ynab_to_be_budgeted{budget="Home",month="2025-03-01"} 2.010` + "\n",
+		`ynab_age_of_money_days{budget="Home",month="2025-03-01"} 42` + "\n",
+		`ynab_category_activity{budget="Home",month="2025-03-01",group="Everyday",category="Food \"out\""} -125.500` + "\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in\n%s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"Old card", "Hidden"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("%s should not be exported:\n%s", unwanted, got)
+		}
+	}
+}
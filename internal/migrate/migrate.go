@@ -0,0 +1,242 @@
+// Package migrate plans copying the structure of one budget into
+// another: category groups and categories, scheduled transactions and
+// payees. Records are matched by name, so running a migration again
+// only creates what is still missing.
+package migrate
+
+import (
+	"fmt"
+
+	"github.com/langtind/ynabctl/internal/names"
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// Step kinds
+const (
+	KindCategoryGroup = "category_group"
+	KindCategory      = "category"
+	KindScheduled     = "scheduled"
+	KindPayee         = "payee"
+)
+
+// Step actions
+const (
+	ActionCreate = "create"
+	ActionExists = "exists"
+	ActionSkip   = "skip"
+)
+
+// internalGroups are managed by YNAB and never migrated: credit card
+// payment categories are created with their accounts
+var internalGroups = []string{"Internal Master Category", "Credit Card Payments", "Hidden Categories"}
+
+// Step is one record of the source budget and what the migration does
+// with it. Group and Parent are the name and source ID of a category's
+// group.
+type Step struct {
+	Kind     string `json:"kind"`
+	Group    string `json:"category_group,omitempty"`
+	Name     string `json:"name"`
+	SourceID string `json:"source_id"`
+	TargetID string `json:"target_id,omitempty"`
+	Parent   string `json:"parent,omitempty"`
+	Action   string `json:"action"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// Mapping maps IDs of the source budget to IDs of the target budget
+type Mapping struct {
+	CategoryGroups map[string]string `json:"category_groups"`
+	Categories     map[string]string `json:"categories"`
+	Accounts       map[string]string `json:"accounts"`
+	Payees         map[string]string `json:"payees"`
+}
+
+// NewMapping matches accounts and payees by name. Transfer payees follow
+// their account, since their names differ when an account is renamed.
+func NewMapping(srcAccounts, dstAccounts []ynab.Account, srcPayees, dstPayees []ynab.Payee) *Mapping {
+	m := &Mapping{
+		CategoryGroups: map[string]string{},
+		Categories:     map[string]string{},
+		Accounts:       map[string]string{},
+		Payees:         map[string]string{},
+	}
+	transferPayee := map[string]string{}
+	for _, a := range srcAccounts {
+		if a.Deleted {
+			continue
+		}
+		for _, d := range dstAccounts {
+			if !d.Deleted && !d.Closed && names.Equal(a.Name, d.Name) {
+				m.Accounts[a.ID] = d.ID
+				transferPayee[a.TransferPayeeID] = d.TransferPayeeID
+				break
+			}
+		}
+	}
+	for _, p := range srcPayees {
+		if p.Deleted {
+			continue
+		}
+		if p.TransferAccountID != "" {
+			if id, ok := transferPayee[p.ID]; ok {
+				m.Payees[p.ID] = id
+			}
+			continue
+		}
+		if d := findPayee(dstPayees, p.Name); d != nil {
+			m.Payees[p.ID] = d.ID
+		}
+	}
+	return m
+}
+
+// PlanCategories matches the category groups and categories of src to
+// dst by name, recording matches in m, and returns a step per group and
+// category. Hidden and internal groups and categories are skipped.
+func PlanCategories(src, dst []ynab.CategoryGroup, m *Mapping) []Step {
+	var steps []Step
+	for _, g := range src {
+		if g.Deleted || isInternal(g.Name) {
+			continue
+		}
+		step := Step{Kind: KindCategoryGroup, Name: g.Name, SourceID: g.ID, Action: ActionCreate}
+		if g.Hidden {
+			step.Action, step.Reason = ActionSkip, "hidden"
+			steps = append(steps, step)
+			continue
+		}
+		target := findGroup(dst, g.Name)
+		if target != nil {
+			step.Action, step.TargetID = ActionExists, target.ID
+			m.CategoryGroups[g.ID] = target.ID
+		}
+		steps = append(steps, step)
+
+		for _, c := range g.Categories {
+			if c.Deleted {
+				continue
+			}
+			cs := Step{Kind: KindCategory, Group: g.Name, Name: c.Name, SourceID: c.ID, Parent: g.ID, Action: ActionCreate}
+			switch {
+			case c.Hidden:
+				cs.Action, cs.Reason = ActionSkip, "hidden"
+			case target != nil:
+				for _, d := range target.Categories {
+					if !d.Deleted && names.Equal(c.Name, d.Name) {
+						cs.Action, cs.TargetID = ActionExists, d.ID
+						m.Categories[c.ID] = d.ID
+						break
+					}
+				}
+			}
+			steps = append(steps, cs)
+		}
+	}
+	return steps
+}
+
+// PlanPayees returns a step per payee of src. The API cannot create a
+// payee on its own, so payees missing from the target are skipped; YNAB
+// creates them when a transaction or scheduled transaction first uses
+// their name.
+func PlanPayees(src []ynab.Payee, m *Mapping) []Step {
+	var steps []Step
+	for _, p := range src {
+		if p.Deleted || p.TransferAccountID != "" {
+			continue
+		}
+		step := Step{Kind: KindPayee, Name: p.Name, SourceID: p.ID, Action: ActionExists, TargetID: m.Payees[p.ID]}
+		if step.TargetID == "" {
+			step.Action, step.Reason = ActionSkip, "payees are created when first used"
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// PlanScheduled returns a step per scheduled transaction of src.
+// Scheduled transactions in accounts missing from the target are
+// skipped, as are those the target already has (same account,
+// frequency, amount and payee).
+func PlanScheduled(src, dst []ynab.ScheduledTransaction, m *Mapping) []Step {
+	var steps []Step
+	for _, st := range src {
+		if st.Deleted {
+			continue
+		}
+		step := Step{Kind: KindScheduled, Name: scheduledName(st), SourceID: st.ID, Action: ActionCreate}
+		account, ok := m.Accounts[st.AccountID]
+		switch {
+		case !ok:
+			step.Action, step.Reason = ActionSkip, fmt.Sprintf("no account named %q in the target budget", st.AccountName)
+		case st.TransferAccountID != "" && m.Accounts[st.TransferAccountID] == "":
+			step.Action, step.Reason = ActionSkip, "transfer account missing in the target budget"
+		case len(st.Subtransactions) > 0:
+			step.Action, step.Reason = ActionSkip, "split scheduled transactions cannot be created through the API"
+		default:
+			for _, d := range dst {
+				if !d.Deleted && d.AccountID == account && d.Frequency == st.Frequency &&
+					d.Amount == st.Amount && names.Equal(d.PayeeName, st.PayeeName) {
+					step.Action, step.TargetID = ActionExists, d.ID
+					break
+				}
+			}
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// SaveScheduled translates a scheduled transaction of the source budget
+// for the target, starting at its next date. Payees the target lacks are
+// given by name, so YNAB creates them; a category the target lacks is
+// left out.
+func SaveScheduled(st ynab.ScheduledTransaction, m *Mapping) ynab.SaveScheduledTransaction {
+	save := ynab.SaveScheduledTransaction{
+		AccountID:  m.Accounts[st.AccountID],
+		Date:       st.DateNext,
+		Frequency:  st.Frequency,
+		Amount:     st.Amount,
+		CategoryID: m.Categories[st.CategoryID],
+		Memo:       st.Memo,
+		FlagColor:  st.FlagColor,
+	}
+	if id, ok := m.Payees[st.PayeeID]; ok {
+		save.PayeeID = id
+	} else {
+		save.PayeeName = st.PayeeName
+	}
+	return save
+}
+
+func scheduledName(st ynab.ScheduledTransaction) string {
+	return fmt.Sprintf("%s %s %.2f (%s)", st.PayeeName, st.Frequency, ynab.MilliunitsToAmount(st.Amount), st.AccountName)
+}
+
+func isInternal(group string) bool {
+	for _, g := range internalGroups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+func findGroup(groups []ynab.CategoryGroup, name string) *ynab.CategoryGroup {
+	for i := range groups {
+		if !groups[i].Deleted && names.Equal(groups[i].Name, name) {
+			return &groups[i]
+		}
+	}
+	return nil
+}
+
+func findPayee(payees []ynab.Payee, name string) *ynab.Payee {
+	for i := range payees {
+		if !payees[i].Deleted && payees[i].TransferAccountID == "" && names.Equal(payees[i].Name, name) {
+			return &payees[i]
+		}
+	}
+	return nil
+}
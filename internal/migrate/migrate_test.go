@@ -0,0 +1,110 @@
+package migrate
+
+import (
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func actions(steps []Step) map[string]string {
+	out := map[string]string{}
+	for _, s := range steps {
+		out[s.Kind+" "+s.Name] = s.Action
+	}
+	return out
+}
+
+func TestPlanCategories(t *testing.T) {
+	src := []ynab.CategoryGroup{
+		{ID: "g1", Name: "Internal Master Category", Categories: []ynab.Category{{ID: "tbb", Name: "Inflow: Ready to Assign"}}},
+		{ID: "g2", Name: "🏠 Bills", Categories: []ynab.Category{
+			{ID: "c1", Name: "Rent"},
+			{ID: "c2", Name: "Power"},
+			{ID: "c3", Name: "Old", Hidden: true},
+		}},
+		{ID: "g3", Name: "Fun", Categories: []ynab.Category{{ID: "c4", Name: "Games"}}},
+		{ID: "g4", Name: "Archive", Hidden: true},
+	}
+	dst := []ynab.CategoryGroup{
+		{ID: "d2", Name: "Bills", Categories: []ynab.Category{{ID: "e1", Name: "rent"}}},
+	}
+	m := NewMapping(nil, nil, nil, nil)
+
+	got := actions(PlanCategories(src, dst, m))
+	want := map[string]string{
+		"category_group 🏠 Bills": ActionExists,
+		"category Rent":          ActionExists,
+		"category Power":         ActionCreate,
+		"category Old":           ActionSkip,
+		"category_group Fun":     ActionCreate,
+		"category Games":         ActionCreate,
+		"category_group Archive": ActionSkip,
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d steps, want %d: %v", len(got), len(want), got)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s: action %q, want %q", k, got[k], v)
+		}
+	}
+	if m.CategoryGroups["g2"] != "d2" || m.Categories["c1"] != "e1" {
+		t.Errorf("mapping not recorded: %+v", m)
+	}
+}
+
+func TestPlanScheduled(t *testing.T) {
+	srcAccounts := []ynab.Account{
+		{ID: "a1", Name: "Checking", TransferPayeeID: "tp1"},
+		{ID: "a2", Name: "Savings", TransferPayeeID: "tp2"},
+		{ID: "a3", Name: "Old card", TransferPayeeID: "tp3"},
+	}
+	dstAccounts := []ynab.Account{
+		{ID: "b1", Name: "checking", TransferPayeeID: "up1"},
+		{ID: "b2", Name: "Savings", TransferPayeeID: "up2"},
+	}
+	srcPayees := []ynab.Payee{
+		{ID: "p1", Name: "Landlord"},
+		{ID: "p2", Name: "Gym"},
+		{ID: "tp2", Name: "Transfer : Savings", TransferAccountID: "a2"},
+	}
+	dstPayees := []ynab.Payee{{ID: "q1", Name: "landlord"}}
+	m := NewMapping(srcAccounts, dstAccounts, srcPayees, dstPayees)
+	m.Categories["c1"] = "e1"
+
+	src := []ynab.ScheduledTransaction{
+		{ID: "s1", AccountID: "a1", PayeeID: "p1", PayeeName: "Landlord", CategoryID: "c1", Frequency: "monthly", Amount: -10000000, DateNext: "2026-11-01"},
+		{ID: "s2", AccountID: "a1", PayeeID: "p2", PayeeName: "Gym", CategoryID: "c9", Frequency: "monthly", Amount: -300000, DateNext: "2026-11-05"},
+		{ID: "s3", AccountID: "a1", PayeeID: "tp2", PayeeName: "Transfer : Savings", TransferAccountID: "a2", Frequency: "monthly", Amount: -1000000},
+		{ID: "s4", AccountID: "a3", PayeeName: "Bank", AccountName: "Old card", Frequency: "monthly"},
+		{ID: "s5", AccountID: "a1", PayeeName: "Split", Frequency: "weekly", Subtransactions: []ynab.ScheduledSubtransaction{{ID: "x"}}},
+	}
+	dst := []ynab.ScheduledTransaction{
+		{ID: "t3", AccountID: "b1", PayeeName: "Transfer : Savings", Frequency: "monthly", Amount: -1000000},
+	}
+
+	steps := PlanScheduled(src, dst, m)
+	want := []string{ActionCreate, ActionCreate, ActionExists, ActionSkip, ActionSkip}
+	for i, s := range steps {
+		if s.Action != want[i] {
+			t.Errorf("%s: action %q, want %q (%s)", s.SourceID, s.Action, want[i], s.Reason)
+		}
+	}
+
+	save := SaveScheduled(src[0], m)
+	if save.AccountID != "b1" || save.PayeeID != "q1" || save.CategoryID != "e1" || save.Date != "2026-11-01" {
+		t.Errorf("unexpected translation: %+v", save)
+	}
+	save = SaveScheduled(src[1], m)
+	if save.PayeeID != "" || save.PayeeName != "Gym" || save.CategoryID != "" {
+		t.Errorf("missing payee should be given by name and missing category left out: %+v", save)
+	}
+	if got := SaveScheduled(src[2], m).PayeeID; got != "up2" {
+		t.Errorf("transfer payee = %q, want up2", got)
+	}
+
+	payees := actions(PlanPayees(srcPayees, m))
+	if payees["payee Landlord"] != ActionExists || payees["payee Gym"] != ActionSkip || len(payees) != 2 {
+		t.Errorf("unexpected payee steps: %v", payees)
+	}
+}
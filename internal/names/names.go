@@ -0,0 +1,171 @@
+// Package names normalizes YNAB entity names for display and matching.
+// Category names often start with emojis ("🛒 Groceries"); these are
+// ignored when matching a name typed on the command line.
+package names
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// IsEmoji reports whether r is an emoji or a character only used to
+// build emoji sequences (variation selectors, joiners, skin tones).
+func IsEmoji(r rune) bool {
+	switch {
+	case r == 0x200D, r == 0xFE0E, r == 0xFE0F, r == 0x20E3:
+		return true
+	case r >= 0x1F000 && r <= 0x1FAFF: // pictographs, emoticons, flags, skin tones
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // misc symbols and dingbats
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF: // stars, arrows used as emoji
+		return true
+	case r >= 0xE0020 && r <= 0xE007F: // tag sequences
+		return true
+	}
+	return false
+}
+
+// IsWide reports whether r occupies two terminal columns.
+func IsWide(r rune) bool {
+	switch {
+	case r >= 0x1F300 && r <= 0x1FAFF:
+		return true
+	case r >= 0x1100 && r <= 0x115F, r >= 0x2E80 && r <= 0xA4CF,
+		r >= 0xAC00 && r <= 0xD7A3, r >= 0xF900 && r <= 0xFAFF,
+		r >= 0xFE30 && r <= 0xFE4F, r >= 0xFF00 && r <= 0xFF60,
+		r >= 0xFFE0 && r <= 0xFFE6:
+		return true
+	}
+	return false
+}
+
+// IsZeroWidth reports whether r takes no terminal column.
+func IsZeroWidth(r rune) bool {
+	return r == 0x200D || r == 0xFE0E || r == 0xFE0F ||
+		(r >= 0x1F3FB && r <= 0x1F3FF) || unicode.Is(unicode.Mn, r)
+}
+
+// StripEmoji removes emojis from s and trims the space they leave.
+func StripEmoji(s string) string {
+	if s == "" {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if !IsEmoji(r) {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == len(s) {
+		return s
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// Fold returns the form of name used for matching: emojis removed,
+// whitespace collapsed, and case folded.
+func Fold(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(StripEmoji(name)), " "))
+}
+
+// Equal reports whether two names match after folding.
+func Equal(a, b string) bool {
+	return Fold(a) == Fold(b)
+}
+
+// Contains reports whether part occurs in name after folding both.
+func Contains(name, part string) bool {
+	p := Fold(part)
+	return p != "" && strings.Contains(Fold(name), p)
+}
+
+// Closest returns the names closest to query after folding, nearest
+// first, leaving out those too different to be a typo of it.
+func Closest(query string, candidates []string, max int) []string {
+	q := []rune(Fold(query))
+	limit := 1 + len(q)/4
+	type scored struct {
+		name string
+		dist int
+	}
+	var near []scored
+	seen := map[string]bool{}
+	for _, c := range candidates {
+		if seen[c] {
+			continue
+		}
+		seen[c] = true
+		if d := distance(q, []rune(Fold(c))); d <= limit {
+			near = append(near, scored{c, d})
+		}
+	}
+	sort.SliceStable(near, func(i, j int) bool { return near[i].dist < near[j].dist })
+	var closest []string
+	for i := 0; i < len(near) && i < max; i++ {
+		closest = append(closest, near[i].name)
+	}
+	return closest
+}
+
+// distance is the Levenshtein distance between a and b
+func distance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// Width returns the number of terminal columns s occupies.
+func Width(s string) int {
+	w := 0
+	for _, r := range s {
+		switch {
+		case IsZeroWidth(r):
+		case IsWide(r):
+			w += 2
+		default:
+			w++
+		}
+	}
+	return w
+}
+
+// Truncate shortens s to at most width terminal columns, ending with
+// "..." when cut.
+func Truncate(s string, width int) string {
+	if Width(s) <= width {
+		return s
+	}
+	limit := width - 3
+	w := 0
+	var b strings.Builder
+	for _, r := range s {
+		rw := 1
+		if IsZeroWidth(r) {
+			rw = 0
+		} else if IsWide(r) {
+			rw = 2
+		}
+		if w+rw > limit {
+			break
+		}
+		b.WriteRune(r)
+		w += rw
+	}
+	return b.String() + "..."
+}
@@ -0,0 +1,53 @@
+package names
+
+import "testing"
+
+func TestFold(t *testing.T) {
+	cases := []struct{ a, b string }{
+		{"🛒 Groceries", "groceries"},
+		{"Groceries 🛒", "Groceries"},
+		{"❤️ Gifts  &  Giving", "gifts & giving"},
+		{"👨‍👩‍👧 Kids", "kids"},
+	}
+	for _, c := range cases {
+		if !Equal(c.a, c.b) {
+			t.Errorf("Equal(%q, %q) = false, folds %q vs %q", c.a, c.b, Fold(c.a), Fold(c.b))
+		}
+	}
+	if Equal("🛒 Groceries", "Grocery") {
+		t.Error("unexpected match")
+	}
+}
+
+func TestWidthAndTruncate(t *testing.T) {
+	if got := Width("🛒 Food"); got != 7 {
+		t.Errorf("Width = %d, want 7", got)
+	}
+	if got := Width("Café"); got != 4 {
+		t.Errorf("Width = %d, want 4", got)
+	}
+	if got := Truncate("🛒 Groceries and household", 12); got != "🛒 Grocer..." {
+		t.Errorf("Truncate = %q", got)
+	}
+	if got := Truncate("Ærlig talt", 20); got != "Ærlig talt" {
+		t.Errorf("Truncate short = %q", got)
+	}
+}
+
+func TestContainsAndClosest(t *testing.T) {
+	if !Contains("🛒 Groceries", "groc") || Contains("Groceries", "") || Contains("Rent", "groc") {
+		t.Error("Contains mismatch")
+	}
+
+	candidates := []string{"Checking", "🏦 Chequing", "Savings", "Credit Card"}
+	got := Closest("Chekcing", candidates, 3)
+	if len(got) != 2 || got[0] != "Checking" {
+		t.Errorf("Closest(Chekcing) = %q", got)
+	}
+	if got := Closest("chequing", candidates, 3); len(got) < 1 || got[0] != "🏦 Chequing" {
+		t.Errorf("Closest(chequing) = %q", got)
+	}
+	if got := Closest("Mortgage", candidates, 3); len(got) != 0 {
+		t.Errorf("Closest(Mortgage) = %q", got)
+	}
+}
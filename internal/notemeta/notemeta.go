@@ -0,0 +1,133 @@
+// Package notemeta keeps key/value metadata in a fenced block of an
+// account note, since YNAB has nowhere else to store it:
+//
+//	Main credit card
+//	```ynabctl
+//	limit=50000
+//	statement_day=15
+//	```
+//
+// Text outside the block is left alone.
+package notemeta
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	fenceOpen  = "```ynabctl"
+	fenceClose = "```"
+)
+
+var reKey = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// find returns the start and end offsets of the block in note, end
+// being just past the closing fence. ok is false if there is no
+// complete block.
+func find(note string) (start, end int, ok bool) {
+	start = strings.Index(note, fenceOpen+"\n")
+	if start < 0 {
+		return 0, 0, false
+	}
+	body := start + len(fenceOpen) + 1
+	rel := strings.Index(note[body:], fenceClose)
+	if rel < 0 {
+		return 0, 0, false
+	}
+	return start, body + rel + len(fenceClose), true
+}
+
+// Parse returns the metadata in note, or nil if it has no block. Lines
+// without "=" and lines starting with "#" are ignored.
+func Parse(note string) map[string]string {
+	note = strings.ReplaceAll(note, "\r\n", "\n")
+	start, end, ok := find(note)
+	if !ok {
+		return nil
+	}
+	meta := map[string]string{}
+	body := note[start+len(fenceOpen)+1 : end-len(fenceClose)]
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if k, v, ok := strings.Cut(line, "="); ok {
+			meta[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return meta
+}
+
+// Set returns note with values merged into its block. An empty value
+// removes the key; the block is dropped when no keys are left.
+func Set(note string, values map[string]string) string {
+	note = strings.ReplaceAll(note, "\r\n", "\n")
+	meta := Parse(note)
+	if meta == nil {
+		meta = map[string]string{}
+	}
+	for k, v := range values {
+		if v == "" {
+			delete(meta, k)
+		} else {
+			meta[k] = v
+		}
+	}
+
+	text := note
+	if start, end, ok := find(note); ok {
+		text = note[:start] + note[end:]
+	}
+	text = strings.TrimSpace(text)
+	if len(meta) == 0 {
+		return text
+	}
+
+	keys := make([]string, 0, len(meta))
+	for k := range meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	if text != "" {
+		b.WriteString(text + "\n")
+	}
+	b.WriteString(fenceOpen + "\n")
+	for _, k := range keys {
+		b.WriteString(k + "=" + meta[k] + "\n")
+	}
+	b.WriteString(fenceClose)
+	return b.String()
+}
+
+// ParseAssignments turns "key=value" arguments into values for Set.
+// Keys are lowercase letters, digits and underscores; "key=" removes a
+// key.
+func ParseAssignments(args []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, a := range args {
+		k, v, ok := strings.Cut(a, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q is not key=value", a)
+		}
+		if !reKey.MatchString(k) {
+			return nil, fmt.Errorf("invalid key %q: use lowercase letters, digits and underscores", k)
+		}
+		if strings.ContainsAny(v, "\n\r") || strings.Contains(v, fenceClose) {
+			return nil, fmt.Errorf("value of %s must be a single line without ```", k)
+		}
+		values[k] = strings.TrimSpace(v)
+	}
+	return values, nil
+}
+
+// Account is the metadata of one account
+type Account struct {
+	AccountID   string            `json:"account_id"`
+	AccountName string            `json:"account_name"`
+	Meta        map[string]string `json:"meta"`
+}
@@ -0,0 +1,64 @@
+package notemeta
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		note string
+		want map[string]string
+	}{
+		{"", nil},
+		{"just a note", nil},
+		{"```ynabctl\nlimit=50000\n", nil},
+		{"Card\n```ynabctl\nlimit = 50000\n# comment\nstatement_day=15\n```\nmore text",
+			map[string]string{"limit": "50000", "statement_day": "15"}},
+		{"```ynabctl\r\nlimit=1\r\n```", map[string]string{"limit": "1"}},
+	}
+	for _, c := range cases {
+		if got := Parse(c.note); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Parse(%q) = %v, want %v", c.note, got, c.want)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	got := Set("Main card", map[string]string{"statement_day": "15", "limit": "50000"})
+	want := "Main card\n```ynabctl\nlimit=50000\nstatement_day=15\n```"
+	if got != want {
+		t.Errorf("Set on plain note:\n%s\nwant:\n%s", got, want)
+	}
+
+	got = Set(want, map[string]string{"limit": "60000"})
+	want = "Main card\n```ynabctl\nlimit=60000\nstatement_day=15\n```"
+	if got != want {
+		t.Errorf("Set existing key:\n%s\nwant:\n%s", got, want)
+	}
+
+	got = Set(want, map[string]string{"limit": "", "statement_day": ""})
+	if got != "Main card" {
+		t.Errorf("removing every key should drop the block, got %q", got)
+	}
+
+	if got := Set("", map[string]string{"limit": "1"}); got != "```ynabctl\nlimit=1\n```" {
+		t.Errorf("Set on empty note: got %q", got)
+	}
+}
+
+func TestParseAssignments(t *testing.T) {
+	got, err := ParseAssignments([]string{"statement_day=15", "limit=", "apr=19.9"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"statement_day": "15", "limit": "", "apr": "19.9"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	for _, bad := range []string{"limit", "Limit=1", "a b=1", "x=```"} {
+		if _, err := ParseAssignments([]string{bad}); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
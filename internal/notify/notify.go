@@ -0,0 +1,60 @@
+// Package notify shows desktop notifications using the platform's
+// command-line tools: osascript on macOS, notify-send on Linux and
+// PowerShell on Windows.
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrUnavailable is returned when the platform's notification tool is
+// not installed.
+var ErrUnavailable = errors.New("no notification tool found (install notify-send)")
+
+// The title and message reach osascript and PowerShell in the
+// environment, so they need no quoting in the scripts below.
+
+const (
+	envTitle   = "YNABCTL_NOTIFY_TITLE"
+	envMessage = "YNABCTL_NOTIFY_MESSAGE"
+)
+
+const appleScript = `display notification (system attribute "` + envMessage + `") with title (system attribute "` + envTitle + `")`
+
+const powerShellScript = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $xml.GetElementsByTagName('text')
+$text.Item(0).AppendChild($xml.CreateTextNode($env:` + envTitle + `)) > $null
+$text.Item(1).AppendChild($xml.CreateTextNode($env:` + envMessage + `)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('ynabctl').Show([Windows.UI.Notifications.ToastNotification]::new($xml))`
+
+// command returns the command line showing a notification on goos
+func command(goos, title, message string) []string {
+	switch goos {
+	case "darwin":
+		return []string{"osascript", "-e", appleScript}
+	case "windows":
+		return []string{"powershell.exe", "-NoProfile", "-NonInteractive", "-Command", powerShellScript}
+	}
+	return []string{"notify-send", "--app-name=ynabctl", title, message}
+}
+
+// Send shows a notification with title and message
+func Send(title, message string) error {
+	args := command(runtime.GOOS, title, message)
+	path, err := exec.LookPath(args[0])
+	if err != nil {
+		return ErrUnavailable
+	}
+	cmd := exec.Command(path, args[1:]...)
+	cmd.Env = append(os.Environ(), envTitle+"="+title, envMessage+"="+message)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	args := command("linux", "ynabctl import", `Finished "ok"`)
+	if args[0] != "notify-send" || args[len(args)-2] != "ynabctl import" || args[len(args)-1] != `Finished "ok"` {
+		t.Errorf("linux command = %q", args)
+	}
+
+	// The text is passed in the environment, never in the script
+	for _, goos := range []string{"darwin", "windows"} {
+		args := command(goos, "ynabctl import", "Finished")
+		script := args[len(args)-1]
+		if strings.Contains(script, "Finished") {
+			t.Errorf("%s script contains the message: %s", goos, script)
+		}
+		if !strings.Contains(script, envTitle) || !strings.Contains(script, envMessage) {
+			t.Errorf("%s script does not read the environment: %s", goos, script)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// ChangelogEntry is one change made (or attempted) by a bulk command
+type ChangelogEntry struct {
+	Time        time.Time `json:"time"`
+	Action      string    `json:"action"`
+	ID          string    `json:"id"`
+	Description string    `json:"description,omitempty"`
+	Amount      int64     `json:"amount,omitempty" amount:"milliunits"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// Changelog collects the changes of a bulk command for a summary at the
+// end and, optionally, a JSON file for later auditing
+type Changelog struct {
+	Command string           `json:"command"`
+	Started time.Time        `json:"started"`
+	Entries []ChangelogEntry `json:"entries"`
+}
+
+// NewChangelog starts a changelog for command
+func NewChangelog(command string) *Changelog {
+	return &Changelog{Command: command, Started: time.Now().UTC(), Entries: []ChangelogEntry{}}
+}
+
+// Record adds a change. A non-nil err marks it as failed.
+func (c *Changelog) Record(action, id, description string, amount int64, err error) {
+	e := ChangelogEntry{
+		Time:        time.Now().UTC(),
+		Action:      action,
+		ID:          id,
+		Description: description,
+		Amount:      amount,
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	c.Entries = append(c.Entries, e)
+}
+
+// PrintSummary writes the number of changes and the total amount (of
+// absolute values) per action to w, followed by the failed items and
+// their reasons. Successful actions are green and failures red when
+// color is true.
+func (c *Changelog) PrintSummary(w io.Writer, color bool) {
+	if len(c.Entries) == 0 {
+		return
+	}
+
+	type total struct {
+		count  int
+		amount int64
+	}
+	totals := map[string]*total{}
+	var failed []ChangelogEntry
+	for _, e := range c.Entries {
+		if e.Error != "" {
+			failed = append(failed, e)
+			continue
+		}
+		t := totals[e.Action]
+		if t == nil {
+			t = &total{}
+			totals[e.Action] = t
+		}
+		t.count++
+		if e.Amount < 0 {
+			t.amount -= e.Amount
+		} else {
+			t.amount += e.Amount
+		}
+	}
+	actions := make([]string, 0, len(totals))
+	for a := range totals {
+		actions = append(actions, a)
+	}
+	sort.Strings(actions)
+
+	paint := func(s, code string) string {
+		if color {
+			return code + s + colorReset
+		}
+		return s
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  ACTION\tCOUNT\tAMOUNT")
+	for _, a := range actions {
+		fmt.Fprintf(tw, "  %s\t%d\t%.2f\n", paint(a, colorGreen), totals[a].count, float64(totals[a].amount)/1000)
+	}
+	if len(failed) > 0 {
+		fmt.Fprintf(tw, "  %s\t%d\t\n", paint("failed", colorRed), len(failed))
+	}
+	tw.Flush()
+
+	for _, e := range failed {
+		what := e.ID
+		if e.Description != "" {
+			what += " (" + e.Description + ")"
+		}
+		fmt.Fprintf(w, "  %s %s: %s\n", paint(e.Action, colorRed), what, e.Error)
+	}
+}
+
+// Counts returns the number of changes made and of changes that failed
+func (c *Changelog) Counts() (done, failed int) {
+	for _, e := range c.Entries {
+		if e.Error != "" {
+			failed++
+		} else {
+			done++
+		}
+	}
+	return done, failed
+}
+
+// WriteFile saves the changelog as indented JSON
+func (c *Changelog) WriteFile(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
@@ -0,0 +1,31 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestChangelogSummary(t *testing.T) {
+	c := NewChangelog("ynabctl transactions purge")
+	c.Record("delete", "t1", "Shop", -12500, nil)
+	c.Record("delete", "t2", "Refund", 2500, nil)
+	c.Record("rename", "p1", "Netflix", 0, nil)
+	c.Record("delete", "t3", "Cafe", -4000, errors.New("404 not found"))
+
+	var buf bytes.Buffer
+	c.PrintSummary(&buf, false)
+	out := buf.String()
+
+	for _, want := range []string{
+		"delete  2      15.00",
+		"rename  1      0.00",
+		"failed  1",
+		"delete t3 (Cafe): 404 not found",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary missing %q:\n%s", want, out)
+		}
+	}
+}
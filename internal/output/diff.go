@@ -0,0 +1,83 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+)
+
+// Change describes a single field whose value differs between the
+// current record and the one about to be saved.
+type Change struct {
+	Field  string
+	Before string
+	After  string
+}
+
+// Changes accumulates field changes, ignoring fields whose value is
+// unchanged.
+type Changes []Change
+
+// Add records a change for field if before and after differ.
+func (c *Changes) Add(field string, before, after interface{}) {
+	b, a := fmt.Sprint(before), fmt.Sprint(after)
+	if b == a {
+		return
+	}
+	*c = append(*c, Change{Field: field, Before: b, After: a})
+}
+
+// AddAmount records a change for a milliunit amount field, formatted as
+// a decimal currency value.
+func (c *Changes) AddAmount(field string, before, after int64) {
+	c.Add(field, fmt.Sprintf("%.2f", float64(before)/1000), fmt.Sprintf("%.2f", float64(after)/1000))
+}
+
+const (
+	colorRed   = "\033[31m"
+	colorGreen = "\033[32m"
+	colorReset = "\033[0m"
+)
+
+// PrintDiff writes a before/after view of changes to w. Removed values
+// are shown in red and new values in green when color is true.
+func PrintDiff(w io.Writer, changes Changes, color bool) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	defer tw.Flush()
+
+	for _, ch := range changes {
+		before, after := valueOrEmpty(ch.Before), valueOrEmpty(ch.After)
+		if color {
+			before = colorRed + before + colorReset
+			after = colorGreen + after + colorReset
+		}
+		fmt.Fprintf(tw, "  %s\t- %s\n", ch.Field, before)
+		fmt.Fprintf(tw, "  \t+ %s\n", after)
+	}
+}
+
+// UseColor reports whether ANSI colors should be written to f: it must
+// be a terminal and NO_COLOR must not be set.
+func UseColor(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return IsTerminal(f)
+}
+
+// IsTerminal reports whether f is attached to a character device.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func valueOrEmpty(s string) string {
+	if s == "" {
+		return "(empty)"
+	}
+	return s
+}
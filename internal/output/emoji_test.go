@@ -0,0 +1,44 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestEmojiStripperKeepsSeparators(t *testing.T) {
+	var out bytes.Buffer
+	w := &emojiStripper{w: &out}
+	for _, p := range []string{"abc\t🛒 Groceries\t12.00\n", "def\t🏠", " Rent\t3.00\n"} {
+		w.Write([]byte(p))
+	}
+	if want := "abc\tGroceries\t12.00\ndef\tRent\t3.00\n"; out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+}
+
+func TestPrintStripEmoji(t *testing.T) {
+	var out bytes.Buffer
+	f := &Formatter{format: "table", writer: &out, opts: Options{StripEmoji: true, Width: 200}}
+	accounts := []ynab.Account{
+		{ID: "a1", Name: "💳 Visa", Type: "creditCard", Balance: -12000},
+		{ID: "a2", Name: "Checking", Type: "checking", Balance: 345000},
+	}
+	if err := f.Print(accounts); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), out.String())
+	}
+	if strings.Contains(lines[1], "💳") || !strings.Contains(lines[1], "Visa") {
+		t.Errorf("emoji not stripped: %q", lines[1])
+	}
+	// the columns still line up
+	col := strings.Index(lines[0], "TYPE")
+	if strings.Index(lines[1], "creditCard") != col || strings.Index(lines[2], "checking") != col {
+		t.Errorf("columns do not line up:\n%s", out.String())
+	}
+}
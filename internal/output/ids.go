@@ -0,0 +1,71 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/langtind/ynabctl/internal/export"
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// printIDs writes the IDs of the printed records, one per line, for
+// --ids
+func (f *Formatter) printIDs(data interface{}) error {
+	ids, err := recordIDs(data)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err = fmt.Fprintln(f.writer, strings.Join(ids, "\n"))
+	return err
+}
+
+// recordIDs returns the "id" of every record in a list, or of a single
+// record. Deleted records are left out; category groups stand for the
+// IDs of their categories, and an import reconciliation for the
+// transactions it created.
+func recordIDs(data interface{}) ([]string, error) {
+	if r, ok := data.(*export.Reconciliation); ok {
+		data = r.Transactions
+	}
+	if groups, ok := data.([]ynab.CategoryGroup); ok {
+		var categories []ynab.Category
+		for _, g := range groups {
+			if !g.Deleted {
+				categories = append(categories, g.Categories...)
+			}
+		}
+		data = categories
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	type record struct {
+		ID      string `json:"id"`
+		Deleted bool   `json:"deleted"`
+	}
+	var records []record
+	if err := json.Unmarshal(raw, &records); err != nil {
+		var one record
+		if err := json.Unmarshal(raw, &one); err != nil || one.ID == "" {
+			return nil, fmt.Errorf("--ids needs a command that prints records with IDs")
+		}
+		records = []record{one}
+	}
+
+	ids := []string{}
+	for _, r := range records {
+		if r.ID != "" && !r.Deleted {
+			ids = append(ids, r.ID)
+		}
+	}
+	if len(ids) == 0 && len(records) > 0 {
+		return nil, fmt.Errorf("--ids needs a command that prints records with IDs")
+	}
+	return ids, nil
+}
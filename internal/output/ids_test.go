@@ -0,0 +1,52 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestPrintIDs(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		data interface{}
+		want string
+	}{
+		{"list", []ynab.Payee{{ID: "p1"}, {ID: "p2", Deleted: true}, {ID: "p3"}}, "p1\np3\n"},
+		{"empty list", []ynab.Transaction{}, ""},
+		{"single record", &ynab.Account{ID: "a1"}, "a1\n"},
+		{"category groups", []ynab.CategoryGroup{
+			{ID: "g1", Categories: []ynab.Category{{ID: "c1"}, {ID: "c2", Deleted: true}}},
+			{ID: "g2", Deleted: true, Categories: []ynab.Category{{ID: "c3"}}},
+		}, "c1\n"},
+	} {
+		var buf bytes.Buffer
+		f := &Formatter{format: "table", writer: &buf, opts: Options{IDsOnly: true}}
+		if err := f.Print(tt.data); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%s: printed %q, want %q", tt.name, buf.String(), tt.want)
+		}
+	}
+
+	f := &Formatter{writer: &bytes.Buffer{}, opts: Options{IDsOnly: true}}
+	if err := f.Print([]ynab.Month{{Month: "2025-01-01"}}); err == nil {
+		t.Error("expected an error for records without IDs")
+	}
+}
+
+func TestNewPreview(t *testing.T) {
+	defer Configure(defaultOptions)
+	Configure(Options{IDsOnly: true, Pager: true})
+
+	var buf bytes.Buffer
+	if err := NewPreview(&buf).Print([]ynab.Payee{{ID: "p1", Name: "Bakery"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); !bytes.Contains(buf.Bytes(), []byte("Bakery")) {
+		t.Errorf("preview printed %q, want a table", got)
+	}
+}
@@ -0,0 +1,220 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/langtind/ynabctl/internal/names"
+)
+
+// Table layouts. The default fits tables into the terminal when it is
+// known to be too narrow; wide never truncates and adds columns such as
+// IDs; narrow always fits NarrowWidth columns.
+const (
+	LayoutAuto   = ""
+	LayoutWide   = "wide"
+	LayoutNarrow = "narrow"
+)
+
+// NarrowWidth is the width --narrow fits tables into
+const NarrowWidth = 80
+
+// minColumnWidth is how far a column is shrunk at most when fitting
+const minColumnWidth = 10
+
+// headerAbbreviations shorten table headers when a table must fit
+var headerAbbreviations = map[string]string{
+	"ACCOUNT":           "ACCT",
+	"ACCOUNTS":          "ACCTS",
+	"ACTIVITY":          "ACTIV",
+	"AMOUNT":            "AMT",
+	"AVAILABLE":         "AVAIL",
+	"BALANCE":           "BAL",
+	"BUDGETED":          "BUDG",
+	"CATEGORY":          "CAT",
+	"CLEARED":           "CLR",
+	"DATE NEXT":         "NEXT",
+	"FIRST MONTH":       "FIRST",
+	"FREQUENCY":         "FREQ",
+	"IMPORTED PAYEE":    "IMP PAYEE",
+	"INTEREST":          "INT",
+	"LAST ACTIVITY":     "ACTIVE",
+	"LAST MODIFIED":     "MODIFIED",
+	"LAST MONTH":        "LAST",
+	"LAST RECONCILED":   "RECONCILED",
+	"MANUAL PAYEE":      "MAN PAYEE",
+	"ON BUDGET":         "ON BUDG",
+	"ON-BUDGET BALANCE": "BUDG BAL",
+	"PRINCIPAL":         "PRINC",
+	"REMAINING":         "LEFT",
+	"SHORTFALL":         "SHORT",
+	"TRANSFER ACCOUNT":  "XFER ACCT",
+}
+
+// TerminalWidth returns the number of columns of the terminal f is
+// attached to, falling back to $COLUMNS, or 0 when it is unknown
+func TerminalWidth(f *os.File) int {
+	if w := terminalWidth(f); w > 0 {
+		return w
+	}
+	w, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err != nil || w < 0 {
+		return 0
+	}
+	return w
+}
+
+// tableWidth returns the width tables must fit into, 0 for no limit
+func (f *Formatter) tableWidth() int {
+	switch f.opts.Layout {
+	case LayoutWide:
+		return 0
+	case LayoutNarrow:
+		if f.opts.Width > 0 && f.opts.Width < NarrowWidth {
+			return f.opts.Width
+		}
+		return NarrowWidth
+	}
+	return f.opts.Width
+}
+
+// truncate shortens a string to the given display width, except in the
+// wide layout
+func (f *Formatter) truncate(s string, length int) string {
+	if f.opts.Layout == LayoutWide {
+		return s
+	}
+	return names.Truncate(s, length)
+}
+
+// fitWriter collects tab-separated rows and, on Flush, passes them on
+// shrunk to fit width: each block of rows that is too wide gets its
+// headers abbreviated, then its widest text columns truncated. Numeric
+// columns are never cut.
+type fitWriter struct {
+	w     io.Writer
+	width int
+	buf   bytes.Buffer
+}
+
+func (fw *fitWriter) Write(p []byte) (int, error) {
+	return fw.buf.Write(p)
+}
+
+// Flush writes the fitted rows
+func (fw *fitWriter) Flush() error {
+	var block [][]string
+	var out bytes.Buffer
+	for _, line := range strings.SplitAfter(fw.buf.String(), "\n") {
+		if line == "" {
+			continue
+		}
+		row := strings.TrimSuffix(line, "\n")
+		if strings.Contains(row, "\t") {
+			block = append(block, strings.Split(row, "\t"))
+			continue
+		}
+		writeBlock(&out, fitRows(block, fw.width))
+		block = nil
+		out.WriteString(line)
+	}
+	writeBlock(&out, fitRows(block, fw.width))
+	fw.buf.Reset()
+	_, err := fw.w.Write(out.Bytes())
+	return err
+}
+
+func writeBlock(out *bytes.Buffer, rows [][]string) {
+	for _, row := range rows {
+		out.WriteString(strings.Join(row, "\t"))
+		out.WriteByte('\n')
+	}
+}
+
+// fitRows shrinks a block of rows, the first being its header, to width
+// as laid out by a tabwriter with a padding of 2
+func fitRows(rows [][]string, width int) [][]string {
+	if len(rows) == 0 {
+		return rows
+	}
+	widths := columnWidths(rows)
+	if lineWidth(widths) <= width {
+		return rows
+	}
+	for i, h := range rows[0] {
+		if short, ok := headerAbbreviations[h]; ok {
+			rows[0][i] = short
+		}
+	}
+	widths = columnWidths(rows)
+
+	numeric := make([]bool, len(widths))
+	for c := range widths {
+		numeric[c] = isNumericColumn(rows[1:], c)
+	}
+	for total := lineWidth(widths); total > width; total-- {
+		widest := -1
+		for c, w := range widths {
+			if !numeric[c] && w > minColumnWidth && (widest < 0 || w > widths[widest]) {
+				widest = c
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		widths[widest]--
+	}
+	for _, row := range rows {
+		for c, cell := range row {
+			row[c] = names.Truncate(cell, widths[c])
+		}
+	}
+	return rows
+}
+
+// columnWidths returns the display width of each column of rows
+func columnWidths(rows [][]string) []int {
+	var widths []int
+	for _, row := range rows {
+		for c, cell := range row {
+			if c == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[c] = max(widths[c], names.Width(cell))
+		}
+	}
+	return widths
+}
+
+// lineWidth is the width of a row with columns of widths
+func lineWidth(widths []int) int {
+	total := 0
+	for i, w := range widths {
+		total += w
+		if i < len(widths)-1 {
+			total += 2
+		}
+	}
+	return total
+}
+
+// isNumericColumn reports whether column c holds only numbers (and
+// blanks)
+func isNumericColumn(rows [][]string, c int) bool {
+	for _, row := range rows {
+		if c >= len(row) {
+			continue
+		}
+		cell := strings.TrimSuffix(strings.TrimSpace(row[c]), "%")
+		if cell == "" {
+			continue
+		}
+		if _, err := strconv.ParseFloat(cell, 64); err != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/langtind/ynabctl/internal/names"
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func testTransactions() []ynab.Transaction {
+	return []ynab.Transaction{{
+		ID:           "3fa85f64-5717-4562-b3fc-2c963f66afa6",
+		Date:         "2026-10-01",
+		PayeeName:    "A payee with a very long name indeed",
+		CategoryName: "Groceries and household supplies",
+		Memo:         "weekly shopping including the things we forgot last week",
+		Amount:       -1234560,
+		Cleared:      "cleared",
+		FlagColor:    "red",
+	}}
+}
+
+func TestNarrowLayoutFitsWidth(t *testing.T) {
+	var out bytes.Buffer
+	f := &Formatter{format: "table", writer: &out, opts: Options{Layout: LayoutNarrow}}
+	if err := f.Print(testTransactions()); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	for _, l := range lines {
+		if w := names.Width(strings.TrimRight(l, " ")); w > NarrowWidth {
+			t.Errorf("line is %d columns wide: %q", w, l)
+		}
+	}
+	if !strings.HasPrefix(lines[0], "DATE") || !strings.Contains(lines[0], "CAT") || strings.Contains(lines[0], "CATEGORY") {
+		t.Errorf("header = %q, want abbreviated", lines[0])
+	}
+	if !strings.Contains(lines[1], "-1234.56") {
+		t.Errorf("amount was cut: %q", lines[1])
+	}
+}
+
+func TestWideLayoutAddsColumns(t *testing.T) {
+	var out bytes.Buffer
+	f := &Formatter{format: "table", writer: &out, opts: Options{Layout: LayoutWide, Width: 40}}
+	if err := f.Print(testTransactions()); err != nil {
+		t.Fatal(err)
+	}
+	s := out.String()
+	for _, want := range []string{"FLAG", "ID", "3fa85f64-5717-4562-b3fc-2c963f66afa6", "things we forgot last week"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("wide output lacks %q:\n%s", want, s)
+		}
+	}
+}
+
+func TestAutoLayoutLeavesFittingTables(t *testing.T) {
+	rows := [][]string{{"NAME", "BALANCE"}, {"Checking", "12.00"}}
+	got := fitRows(rows, 80)
+	if got[0][1] != "BALANCE" || got[1][0] != "Checking" {
+		t.Errorf("fitting table changed: %q", got)
+	}
+}
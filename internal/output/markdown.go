@@ -0,0 +1,80 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// markdownWriter turns tab-separated rows into Markdown tables. The first
+// row of each table is its header; a blank line starts a new table.
+type markdownWriter struct {
+	w       io.Writer
+	buf     bytes.Buffer
+	columns int // columns of the current table, 0 before its header
+}
+
+func (m *markdownWriter) Write(p []byte) (int, error) {
+	m.buf.Write(p)
+	for {
+		line, err := m.buf.ReadString('\n')
+		if err != nil {
+			// incomplete line; keep it for the next write
+			m.buf.Reset()
+			m.buf.WriteString(line)
+			return len(p), nil
+		}
+		if err := m.writeLine(strings.TrimSuffix(line, "\n")); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Flush writes a trailing line without a newline
+func (m *markdownWriter) Flush() error {
+	if m.buf.Len() == 0 {
+		return nil
+	}
+	line := m.buf.String()
+	m.buf.Reset()
+	return m.writeLine(line)
+}
+
+func (m *markdownWriter) writeLine(line string) error {
+	if strings.TrimSpace(line) == "" {
+		m.columns = 0
+		_, err := io.WriteString(m.w, "\n")
+		return err
+	}
+
+	cells := strings.Split(line, "\t")
+	var b strings.Builder
+	if m.columns == 0 {
+		m.columns = len(cells)
+		b.WriteString(markdownRow(cells, m.columns))
+		b.WriteString("|" + strings.Repeat(" --- |", m.columns) + "\n")
+	} else {
+		b.WriteString(markdownRow(cells, m.columns))
+	}
+	_, err := io.WriteString(m.w, b.String())
+	return err
+}
+
+// markdownRow formats cells as a table row padded to n columns; extra
+// cells are joined into the last column
+func markdownRow(cells []string, n int) string {
+	if len(cells) > n {
+		cells = append(cells[:n-1], strings.Join(cells[n-1:], " "))
+	}
+	for len(cells) < n {
+		cells = append(cells, "")
+	}
+	var b strings.Builder
+	b.WriteString("|")
+	for _, c := range cells {
+		c = strings.ReplaceAll(strings.TrimSpace(c), "|", `\|`)
+		b.WriteString(" " + c + " |")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
@@ -0,0 +1,29 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestMarkdownWriter(t *testing.T) {
+	var out bytes.Buffer
+	mw := &markdownWriter{w: &out}
+	fmt.Fprintln(mw, "NAME\tAMOUNT")
+	fmt.Fprintf(mw, "Rent | flat\t%.2f\n", 1000.0)
+	fmt.Fprint(mw, "Food")
+	fmt.Fprint(mw, "\t12.50\textra\n")
+	fmt.Fprintf(mw, "TOTAL\n")
+	if err := mw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "| NAME | AMOUNT |\n" +
+		"| --- | --- |\n" +
+		"| Rent \\| flat | 1000.00 |\n" +
+		"| Food | 12.50 extra |\n" +
+		"| TOTAL |  |\n"
+	if out.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
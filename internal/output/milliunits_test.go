@@ -0,0 +1,58 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestPrintJSONAddsDecimalsForTaggedFields(t *testing.T) {
+	type days struct {
+		Change int `json:"change"`
+	}
+	type inner struct {
+		Amount int64 `json:"amount" amount:"milliunits"`
+	}
+	type record struct {
+		inner
+		Change  int64           `json:"change" amount:"milliunits"`
+		Total   *int64          `json:"total,omitempty" amount:"milliunits"`
+		Count   int64           `json:"count"`
+		Days    days            `json:"days"`
+		ByMonth map[string]days `json:"by_month"`
+	}
+	total := int64(-12340)
+	data := []record{{
+		inner:   inner{Amount: 5000},
+		Change:  1500,
+		Total:   &total,
+		Count:   7,
+		Days:    days{Change: 5},
+		ByMonth: map[string]days{"2026-10": {Change: 3}},
+	}}
+
+	var out bytes.Buffer
+	f := &Formatter{format: "json", writer: &out}
+	if err := f.Print(data); err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	r := got[0]
+	for key, want := range map[string]float64{"amount_decimal": 5, "change_decimal": 1.5, "total_decimal": -12.34} {
+		if r[key] != want {
+			t.Errorf("%s = %v, want %v", key, r[key], want)
+		}
+	}
+	if _, ok := r["count_decimal"]; ok {
+		t.Error("untagged count got a decimal")
+	}
+	if _, ok := r["days"].(map[string]interface{})["change_decimal"]; ok {
+		t.Error("a change in days got a decimal")
+	}
+	if _, ok := r["by_month"].(map[string]interface{})["2026-10"].(map[string]interface{})["change_decimal"]; ok {
+		t.Error("a change in days in a map got a decimal")
+	}
+}
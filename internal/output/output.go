@@ -6,15 +6,89 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"reflect"
+	"sort"
+	"strings"
 	"text/tabwriter"
+	"time"
 
-	"github.com/langtind/ynabctl/internal/client"
+	"github.com/langtind/ynabctl/internal/allocplan"
+	"github.com/langtind/ynabctl/internal/apispec"
+	"github.com/langtind/ynabctl/internal/audit"
+	"github.com/langtind/ynabctl/internal/budgetplan"
+	"github.com/langtind/ynabctl/internal/clipboard"
+	"github.com/langtind/ynabctl/internal/export"
+	"github.com/langtind/ynabctl/internal/i18n"
+	"github.com/langtind/ynabctl/internal/idcache"
+	"github.com/langtind/ynabctl/internal/matching"
+	"github.com/langtind/ynabctl/internal/migrate"
+	"github.com/langtind/ynabctl/internal/names"
+	"github.com/langtind/ynabctl/internal/notemeta"
+	"github.com/langtind/ynabctl/internal/pacing"
+	"github.com/langtind/ynabctl/internal/policy"
+	"github.com/langtind/ynabctl/internal/report"
+	"github.com/langtind/ynabctl/internal/schedplan"
+	"github.com/langtind/ynabctl/internal/seed"
+	"github.com/langtind/ynabctl/internal/slugs"
+	"github.com/langtind/ynabctl/pkg/ynab"
 )
 
 // Formatter handles output formatting
 type Formatter struct {
 	format string
 	writer io.Writer
+	opts   Options
+}
+
+// Options are process-wide output settings applied to every Formatter
+// created by New. They are set once from the global flags.
+type Options struct {
+	// Copy also writes everything printed to the system clipboard
+	Copy bool
+	// CopyID writes only the "id" of a single printed record to the clipboard
+	CopyID bool
+	// IDsOnly prints only the "id" of each record, one per line, in
+	// place of the formatted output
+	IDsOnly bool
+	// StripEmoji removes emojis from table output so columns line up
+	StripEmoji bool
+	// LookupName returns a known name for an ID; table output uses it
+	// when a payload carries an ID without its name
+	LookupName func(id string) string
+	// LookupSlug returns the slug of an account, category or payee ID,
+	// or "" if it has none; JSON output adds it as "slug" next to "id"
+	LookupSlug func(id string) string
+	// WithMeta wraps JSON output in {"data": ..., "meta": ...}
+	WithMeta bool
+	// Meta supplies the budget and rate limit fields of the envelope
+	Meta func() Meta
+	// Pager shows table and Markdown output through a pager when it is
+	// longer than the screen
+	Pager bool
+	// Layout is LayoutAuto, LayoutWide or LayoutNarrow
+	Layout string
+	// Width is the terminal width tables are fitted into by default, 0
+	// when output is not a terminal
+	Width int
+}
+
+// Meta is the provenance attached to JSON output by --with-meta
+type Meta struct {
+	BudgetID           string `json:"budget_id,omitempty"`
+	GeneratedAt        string `json:"generated_at"`
+	Count              int    `json:"count"`
+	RateLimitRemaining *int   `json:"rate_limit_remaining,omitempty"`
+	// Offline and DataAsOf mark output served from the local cache and
+	// when its oldest data was fetched
+	Offline  bool   `json:"offline,omitempty"`
+	DataAsOf string `json:"data_as_of,omitempty"`
+}
+
+var defaultOptions Options
+
+// Configure sets the options used by subsequently created formatters
+func Configure(opts Options) {
+	defaultOptions = opts
 }
 
 // New creates a new output formatter
@@ -22,58 +96,183 @@ func New(format string) *Formatter {
 	return &Formatter{
 		format: format,
 		writer: os.Stdout,
+		opts:   defaultOptions,
 	}
 }
 
+// NewPreview creates a table formatter writing to w, for showing records
+// next to a confirmation prompt on stderr while standard output is kept
+// for the result. It never pages, copies or prints only IDs.
+func NewPreview(w io.Writer) *Formatter {
+	f := New("table")
+	f.writer = w
+	f.opts.Pager, f.opts.Copy, f.opts.CopyID, f.opts.IDsOnly = false, false, false, false
+	return f
+}
+
 // Print outputs data in the configured format
 func (f *Formatter) Print(data interface{}) error {
-	if f.format == "table" {
-		return f.printTable(data)
+	if f.opts.IDsOnly {
+		return f.printIDs(data)
+	}
+	var captured, paged bytes.Buffer
+	out := f.writer
+	defer func() { f.writer = out }()
+	usePager := f.opts.Pager && (f.format == "table" || f.format == "markdown")
+	if usePager {
+		f.writer = &paged
+	}
+	if f.opts.Copy {
+		f.writer = io.MultiWriter(f.writer, &captured)
+	}
+
+	var err error
+	switch f.format {
+	case "table":
+		err = f.printTable(data)
+	case "markdown":
+		err = f.printMarkdown(data)
+	default:
+		err = f.printJSON(data)
+	}
+	if err != nil {
+		return err
+	}
+	if usePager {
+		if err := page(out, paged.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case f.opts.CopyID:
+		id, err := recordID(data)
+		if err != nil {
+			return err
+		}
+		return copyToClipboard(id, "ID "+id)
+	case f.opts.Copy:
+		return copyToClipboard(captured.String(), "output")
+	}
+	return nil
+}
+
+// recordID returns the "id" field of a single record
+func recordID(data interface{}) (string, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return "", err
 	}
-	return f.printJSON(data)
+	var record struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(raw, &record); err != nil || record.ID == "" {
+		return "", fmt.Errorf("--copy-id needs a command that prints a single record with an ID")
+	}
+	return record.ID, nil
 }
 
-// milliunitKeys are JSON field names whose integer value is expressed in
-// YNAB milliunits. For each match we emit a sibling "<key>_decimal" with
-// the value divided by 1000, so agents and humans can read amounts
-// without converting and without losing the milliunit precision.
-var milliunitKeys = map[string]struct{}{
-	"balance":               {},
-	"cleared_balance":       {},
-	"uncleared_balance":     {},
-	"budgeted":              {},
-	"activity":              {},
-	"income":                {},
-	"to_be_budgeted":        {},
-	"amount":                {},
-	"debt_original_balance": {},
-	"goal_target":           {},
-	"goal_under_funded":     {},
-	"goal_overall_funded":   {},
-	"goal_overall_left":     {},
+func copyToClipboard(text, what string) error {
+	if err := clipboard.Write(text); err != nil {
+		return fmt.Errorf("failed to copy to clipboard: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Copied %s to clipboard.\n", what)
+	return nil
 }
 
-func enrichMilliunits(v interface{}) interface{} {
+// enrichMilliunits adds, next to every field tagged amount:"milliunits",
+// e.g.
+//
+//	Balance int64 `json:"balance" amount:"milliunits"`
+//
+// a sibling "<name>_decimal" with the value divided by 1000, so agents
+// and humans can read amounts without converting and without losing the
+// milliunit precision. v is the value that was marshalled to parsed;
+// walking both keeps a field name from meaning an amount in one record
+// and days or counts in another.
+func enrichMilliunits(v reflect.Value, parsed interface{}) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		if obj, ok := parsed.(map[string]interface{}); ok {
+			enrichStruct(v, obj)
+		}
+	case reflect.Slice, reflect.Array:
+		list, ok := parsed.([]interface{})
+		if !ok || len(list) != v.Len() {
+			return
+		}
+		for i := range list {
+			enrichMilliunits(v.Index(i), list[i])
+		}
+	case reflect.Map:
+		obj, ok := parsed.(map[string]interface{})
+		if !ok {
+			return
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			if val, ok := obj[fmt.Sprint(iter.Key().Interface())]; ok {
+				enrichMilliunits(iter.Value(), val)
+			}
+		}
+	}
+}
+
+func enrichStruct(v reflect.Value, obj map[string]interface{}) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.Anonymous && name == "" {
+			// the fields of an embedded struct are in obj itself
+			enrichMilliunits(v.Field(i), obj)
+			continue
+		}
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		val, ok := obj[name]
+		if !ok {
+			continue
+		}
+		if field.Tag.Get("amount") != "milliunits" {
+			enrichMilliunits(v.Field(i), val)
+			continue
+		}
+		if n, isNum := val.(json.Number); isNum {
+			if i, err := n.Int64(); err == nil {
+				obj[name+"_decimal"] = float64(i) / 1000
+			}
+		}
+	}
+}
+
+// addSlugs sets "slug" on every object whose "id" has one
+func addSlugs(v interface{}, lookup func(id string) string) {
 	switch x := v.(type) {
 	case map[string]interface{}:
-		for k, val := range x {
-			x[k] = enrichMilliunits(val)
-			if _, ok := milliunitKeys[k]; ok {
-				if n, isNum := val.(json.Number); isNum {
-					if i, err := n.Int64(); err == nil {
-						x[k+"_decimal"] = float64(i) / 1000
-					}
-				}
+		for _, val := range x {
+			addSlugs(val, lookup)
+		}
+		if id, ok := x["id"].(string); ok {
+			if slug := lookup(id); slug != "" {
+				x["slug"] = slug
 			}
 		}
-		return x
 	case []interface{}:
-		for i, val := range x {
-			x[i] = enrichMilliunits(val)
+		for _, val := range x {
+			addSlugs(val, lookup)
 		}
-		return x
 	}
-	return v
 }
 
 // printJSON outputs data as pretty-printed JSON, enriching milliunit
@@ -89,64 +288,231 @@ func (f *Formatter) printJSON(data interface{}) error {
 	if err := dec.Decode(&parsed); err != nil {
 		return err
 	}
-	enriched := enrichMilliunits(parsed)
+	enrichMilliunits(reflect.ValueOf(data), parsed)
+	enriched := parsed
+	if f.opts.LookupSlug != nil {
+		addSlugs(enriched, f.opts.LookupSlug)
+	}
+	if f.opts.WithMeta {
+		enriched = map[string]interface{}{
+			"data": enriched,
+			"meta": f.meta(enriched),
+		}
+	}
 	encoder := json.NewEncoder(f.writer)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(enriched)
 }
 
+// meta builds the --with-meta envelope fields. count is the number of
+// records for a list and 1 for a single record.
+func (f *Formatter) meta(data interface{}) Meta {
+	var m Meta
+	if f.opts.Meta != nil {
+		m = f.opts.Meta()
+	}
+	m.GeneratedAt = time.Now().UTC().Format(time.RFC3339)
+	switch x := data.(type) {
+	case []interface{}:
+		m.Count = len(x)
+	case nil:
+		m.Count = 0
+	default:
+		m.Count = 1
+	}
+	return m
+}
+
+// emojiStripper removes emojis, and the single space following each, from
+// everything written through it. Tabs and newlines are kept, so the rows
+// still line up; a space may arrive in the Write after its emoji.
+type emojiStripper struct {
+	w          io.Writer
+	afterEmoji bool
+}
+
+func (e *emojiStripper) Write(p []byte) (int, error) {
+	var b strings.Builder
+	for _, r := range string(p) {
+		switch {
+		case names.IsEmoji(r):
+			e.afterEmoji = true
+			continue
+		case r == ' ' && e.afterEmoji:
+		default:
+			b.WriteRune(r)
+		}
+		e.afterEmoji = false
+	}
+	if _, err := io.WriteString(e.w, b.String()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
 // printTable outputs data in tabular format
 func (f *Formatter) printTable(data interface{}) error {
-	w := tabwriter.NewWriter(f.writer, 0, 0, 2, ' ', 0)
-	defer w.Flush()
+	tw := tabwriter.NewWriter(f.writer, 0, 0, 2, ' ', 0)
+	defer tw.Flush()
+	width := f.tableWidth()
+	if width == 0 {
+		return f.writeRows(tw, data)
+	}
+	fw := &fitWriter{w: tw, width: width}
+	if err := f.writeRows(fw, data); err != nil {
+		return err
+	}
+	return fw.Flush()
+}
+
+// printMarkdown outputs the table rows as GitHub-flavored Markdown tables
+func (f *Formatter) printMarkdown(data interface{}) error {
+	mw := &markdownWriter{w: f.writer}
+	if err := f.writeRows(mw, data); err != nil {
+		return err
+	}
+	return mw.Flush()
+}
+
+// headerTranslator translates the first row written through it, the
+// table header, into the selected language
+type headerTranslator struct {
+	w    io.Writer
+	done bool
+}
+
+func (h *headerTranslator) Write(p []byte) (int, error) {
+	if h.done {
+		return h.w.Write(p)
+	}
+	h.done = true
+	row := strings.TrimSuffix(string(p), "\n")
+	if _, err := io.WriteString(h.w, i18n.Header(row)+strings.TrimPrefix(string(p), row)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// transactionHeader returns the header of transaction tables; the wide
+// layout adds the account, flag and ID
+func (f *Formatter) transactionHeader() string {
+	if f.opts.Layout == LayoutWide {
+		return "DATE\tPAYEE\tCATEGORY\tMEMO\tAMOUNT\tCLEARED\tACCOUNT\tFLAG\tID"
+	}
+	return "DATE\tPAYEE\tCATEGORY\tMEMO\tAMOUNT\tCLEARED"
+}
+
+// writeTransaction writes the row of t in a transaction table
+func (f *Formatter) writeTransaction(w io.Writer, t ynab.Transaction) {
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.2f\t%s",
+		t.Date, f.nameOf(t.PayeeName, t.PayeeID), f.nameOf(t.CategoryName, t.CategoryID),
+		f.truncate(t.Memo, 30),
+		ynab.MilliunitsToAmount(t.Amount), t.Cleared)
+	if f.opts.Layout == LayoutWide {
+		fmt.Fprintf(w, "\t%s\t%s\t%s", f.nameOf(t.AccountName, t.AccountID), t.FlagColor, t.ID)
+	}
+	fmt.Fprintln(w)
+}
+
+// writeRows writes data as tab-separated rows, the first being a header
+func (f *Formatter) writeRows(out io.Writer, data interface{}) error {
+	var w io.Writer = out
+	if f.opts.StripEmoji {
+		w = &emojiStripper{w: out}
+	}
+	if i18n.Language() != i18n.English {
+		w = &headerTranslator{w: w}
+	}
 
 	switch v := data.(type) {
-	case *client.User:
+	case *ynab.User:
 		fmt.Fprintln(w, "ID")
 		fmt.Fprintf(w, "%s\n", v.ID)
 
-	case []client.Budget:
+	case *UserStatus:
+		fmt.Fprintln(w, "FIELD\tVALUE")
+		fmt.Fprintf(w, "ID\t%s\n", v.ID)
+		if v.DefaultBudgetID != "" {
+			fmt.Fprintf(w, "Default Budget\t%s (%s)\n", v.DefaultBudget, v.DefaultBudgetID)
+		}
+		fmt.Fprintf(w, "Token Type\t%s\n", v.TokenType)
+		fmt.Fprintf(w, "Token Source\t%s\n", v.TokenSource)
+		if v.TokenLastUsed != nil {
+			fmt.Fprintf(w, "Token Last Used\t%s\n", v.TokenLastUsed.Local().Format("2006-01-02 15:04"))
+		}
+		for _, warning := range v.Warnings {
+			fmt.Fprintf(w, "Warning\t%s\n", warning)
+		}
+
+	case []ynab.Budget:
 		fmt.Fprintln(w, "ID\tNAME\tLAST MODIFIED")
 		for _, b := range v {
 			fmt.Fprintf(w, "%s\t%s\t%s\n", b.ID, b.Name, b.LastModifiedOn)
 		}
 
-	case *client.Budget:
+	case []report.BudgetSummary:
+		fmt.Fprintln(w, "ID\tNAME\tACCOUNTS\tON-BUDGET BALANCE\tLAST ACTIVITY")
+		for _, b := range v {
+			if b.Error != "" {
+				fmt.Fprintf(w, "%s\t%s\t-\t-\terror: %s\n", b.ID, b.Name, b.Error)
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%.2f\t%s\n",
+				b.ID, b.Name, b.AccountCount,
+				ynab.MilliunitsToAmount(b.OnBudgetBalance), b.LastActivity)
+		}
+
+	case *ynab.Budget:
 		fmt.Fprintln(w, "ID\tNAME\tFIRST MONTH\tLAST MONTH")
 		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", v.ID, v.Name, v.FirstMonth, v.LastMonth)
 
-	case *client.BudgetSettings:
+	case *ynab.BudgetSettings:
 		fmt.Fprintln(w, "SETTING\tVALUE")
 		fmt.Fprintf(w, "Date Format\t%s\n", v.DateFormat.Format)
 		fmt.Fprintf(w, "Currency\t%s\n", v.CurrencyFormat.ISOCode)
 		fmt.Fprintf(w, "Currency Symbol\t%s\n", v.CurrencyFormat.CurrencySymbol)
 		fmt.Fprintf(w, "Decimal Digits\t%d\n", v.CurrencyFormat.DecimalDigits)
 
-	case []client.Account:
-		fmt.Fprintln(w, "ID\tNAME\tTYPE\tBALANCE\tON BUDGET\tCLOSED")
+	case []ynab.Account:
+		fmt.Fprintln(w, "ID\tSLUG\tNAME\tTYPE\tBALANCE\tON BUDGET\tCLOSED\tLAST RECONCILED")
 		for _, a := range v {
-			fmt.Fprintf(w, "%s\t%s\t%s\t%.2f\t%t\t%t\n",
-				a.ID, a.Name, a.Type,
-				client.MilliunitsToAmount(a.Balance),
-				a.OnBudget, a.Closed)
+			reconciled := report.ReconciledDate(a)
+			if reconciled == "" {
+				reconciled = "never"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.2f\t%t\t%t\t%s\n",
+				a.ID, f.slugOf(a.ID), a.Name, a.Type,
+				ynab.MilliunitsToAmount(a.Balance),
+				a.OnBudget, a.Closed, reconciled)
 		}
 
-	case *client.Account:
+	case *ynab.Account:
 		fmt.Fprintln(w, "FIELD\tVALUE")
 		fmt.Fprintf(w, "ID\t%s\n", v.ID)
 		fmt.Fprintf(w, "Name\t%s\n", v.Name)
 		fmt.Fprintf(w, "Type\t%s\n", v.Type)
-		fmt.Fprintf(w, "Balance\t%.2f\n", client.MilliunitsToAmount(v.Balance))
-		fmt.Fprintf(w, "Cleared Balance\t%.2f\n", client.MilliunitsToAmount(v.ClearedBalance))
-		fmt.Fprintf(w, "Uncleared Balance\t%.2f\n", client.MilliunitsToAmount(v.UnclearedBalance))
+		fmt.Fprintf(w, "Balance\t%.2f\n", ynab.MilliunitsToAmount(v.Balance))
+		fmt.Fprintf(w, "Cleared Balance\t%.2f\n", ynab.MilliunitsToAmount(v.ClearedBalance))
+		fmt.Fprintf(w, "Uncleared Balance\t%.2f\n", ynab.MilliunitsToAmount(v.UnclearedBalance))
 		fmt.Fprintf(w, "On Budget\t%t\n", v.OnBudget)
 		fmt.Fprintf(w, "Closed\t%t\n", v.Closed)
+		if d := report.ReconciledDate(*v); d != "" {
+			fmt.Fprintf(w, "Last Reconciled\t%s\n", d)
+		}
+		if v.TransferPayeeID != "" {
+			fmt.Fprintf(w, "Transfer Payee ID\t%s\n", v.TransferPayeeID)
+		}
 		if v.Note != "" {
 			fmt.Fprintf(w, "Note\t%s\n", v.Note)
 		}
 
-	case []client.CategoryGroup:
-		fmt.Fprintln(w, "GROUP\tCATEGORY\tBUDGETED\tACTIVITY\tBALANCE")
+	case []ynab.CategoryGroup:
+		wide := f.opts.Layout == LayoutWide
+		if wide {
+			fmt.Fprintln(w, "GROUP\tCATEGORY\tSLUG\tBUDGETED\tACTIVITY\tBALANCE\tGOAL\tID")
+		} else {
+			fmt.Fprintln(w, "GROUP\tCATEGORY\tSLUG\tBUDGETED\tACTIVITY\tBALANCE")
+		}
 		for _, g := range v {
 			if g.Deleted || g.Hidden {
 				continue
@@ -155,50 +521,61 @@ func (f *Formatter) printTable(data interface{}) error {
 				if c.Deleted || c.Hidden {
 					continue
 				}
-				fmt.Fprintf(w, "%s\t%s\t%.2f\t%.2f\t%.2f\n",
-					g.Name, c.Name,
-					client.MilliunitsToAmount(c.Budgeted),
-					client.MilliunitsToAmount(c.Activity),
-					client.MilliunitsToAmount(c.Balance))
+				fmt.Fprintf(w, "%s\t%s\t%s\t%.2f\t%.2f\t%.2f",
+					g.Name, c.Name, f.slugOf(c.ID),
+					ynab.MilliunitsToAmount(c.Budgeted),
+					ynab.MilliunitsToAmount(c.Activity),
+					ynab.MilliunitsToAmount(c.Balance))
+				if wide {
+					fmt.Fprintf(w, "\t%s\t%s", c.GoalType, c.ID)
+				}
+				fmt.Fprintln(w)
 			}
 		}
 
-	case *client.Category:
+	case *ynab.Category:
 		fmt.Fprintln(w, "FIELD\tVALUE")
 		fmt.Fprintf(w, "ID\t%s\n", v.ID)
 		fmt.Fprintf(w, "Name\t%s\n", v.Name)
-		fmt.Fprintf(w, "Group\t%s\n", v.CategoryGroupName)
-		fmt.Fprintf(w, "Budgeted\t%.2f\n", client.MilliunitsToAmount(v.Budgeted))
-		fmt.Fprintf(w, "Activity\t%.2f\n", client.MilliunitsToAmount(v.Activity))
-		fmt.Fprintf(w, "Balance\t%.2f\n", client.MilliunitsToAmount(v.Balance))
+		fmt.Fprintf(w, "Group\t%s\n", f.nameOf(v.CategoryGroupName, v.CategoryGroupID))
+		fmt.Fprintf(w, "Budgeted\t%.2f\n", ynab.MilliunitsToAmount(v.Budgeted))
+		fmt.Fprintf(w, "Activity\t%.2f\n", ynab.MilliunitsToAmount(v.Activity))
+		fmt.Fprintf(w, "Balance\t%.2f\n", ynab.MilliunitsToAmount(v.Balance))
 		if v.GoalType != "" {
 			fmt.Fprintf(w, "Goal Type\t%s\n", v.GoalType)
-			fmt.Fprintf(w, "Goal Target\t%.2f\n", client.MilliunitsToAmount(v.GoalTarget))
+			fmt.Fprintf(w, "Goal Target\t%.2f\n", ynab.MilliunitsToAmount(v.GoalTarget))
 		}
 		if v.Note != "" {
 			fmt.Fprintf(w, "Note\t%s\n", v.Note)
 		}
 
-	case []client.Transaction:
-		fmt.Fprintln(w, "DATE\tPAYEE\tCATEGORY\tMEMO\tAMOUNT\tCLEARED")
+	case []ynab.Transaction:
+		fmt.Fprintln(w, f.transactionHeader())
 		for _, t := range v {
-			if t.Deleted {
-				continue
+			if !t.Deleted {
+				f.writeTransaction(w, t)
 			}
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.2f\t%s\n",
-				t.Date, t.PayeeName, t.CategoryName,
-				truncate(t.Memo, 30),
-				client.MilliunitsToAmount(t.Amount), t.Cleared)
 		}
 
-	case *client.Transaction:
+	case []report.TransactionBucket:
+		// one table, so the columns line up across buckets
+		fmt.Fprintln(w, f.transactionHeader())
+		for _, b := range v {
+			fmt.Fprintf(w, "== %s ==\t\t\t\t\t\n", b.Bucket)
+			for _, t := range b.Transactions {
+				f.writeTransaction(w, t)
+			}
+			fmt.Fprintf(w, "\tSubtotal (%d)\t\t\t%.2f\t\n", b.Count, ynab.MilliunitsToAmount(b.Total))
+		}
+
+	case *ynab.Transaction:
 		fmt.Fprintln(w, "FIELD\tVALUE")
 		fmt.Fprintf(w, "ID\t%s\n", v.ID)
 		fmt.Fprintf(w, "Date\t%s\n", v.Date)
-		fmt.Fprintf(w, "Amount\t%.2f\n", client.MilliunitsToAmount(v.Amount))
-		fmt.Fprintf(w, "Payee\t%s\n", v.PayeeName)
-		fmt.Fprintf(w, "Category\t%s\n", v.CategoryName)
-		fmt.Fprintf(w, "Account\t%s\n", v.AccountName)
+		fmt.Fprintf(w, "Amount\t%.2f\n", ynab.MilliunitsToAmount(v.Amount))
+		fmt.Fprintf(w, "Payee\t%s\n", f.nameOf(v.PayeeName, v.PayeeID))
+		fmt.Fprintf(w, "Category\t%s\n", f.nameOf(v.CategoryName, v.CategoryID))
+		fmt.Fprintf(w, "Account\t%s\n", f.nameOf(v.AccountName, v.AccountID))
 		fmt.Fprintf(w, "Cleared\t%s\n", v.Cleared)
 		fmt.Fprintf(w, "Approved\t%t\n", v.Approved)
 		if v.Memo != "" {
@@ -208,16 +585,16 @@ func (f *Formatter) printTable(data interface{}) error {
 			fmt.Fprintf(w, "Flag\t%s\n", v.FlagColor)
 		}
 
-	case []client.Payee:
-		fmt.Fprintln(w, "ID\tNAME\tTRANSFER ACCOUNT")
+	case []ynab.Payee:
+		fmt.Fprintln(w, "ID\tSLUG\tNAME\tTRANSFER ACCOUNT")
 		for _, p := range v {
 			if p.Deleted {
 				continue
 			}
-			fmt.Fprintf(w, "%s\t%s\t%s\n", p.ID, p.Name, p.TransferAccountID)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.ID, f.slugOf(p.ID), p.Name, p.TransferAccountID)
 		}
 
-	case *client.Payee:
+	case *ynab.Payee:
 		fmt.Fprintln(w, "FIELD\tVALUE")
 		fmt.Fprintf(w, "ID\t%s\n", v.ID)
 		fmt.Fprintf(w, "Name\t%s\n", v.Name)
@@ -225,32 +602,41 @@ func (f *Formatter) printTable(data interface{}) error {
 			fmt.Fprintf(w, "Transfer Account ID\t%s\n", v.TransferAccountID)
 		}
 
-	case []client.ScheduledTransaction:
-		fmt.Fprintln(w, "DATE NEXT\tFREQUENCY\tPAYEE\tCATEGORY\tAMOUNT")
+	case []ynab.ScheduledTransaction:
+		wide := f.opts.Layout == LayoutWide
+		if wide {
+			fmt.Fprintln(w, "DATE NEXT\tFREQUENCY\tPAYEE\tCATEGORY\tAMOUNT\tACCOUNT\tMEMO\tFLAG\tID")
+		} else {
+			fmt.Fprintln(w, "DATE NEXT\tFREQUENCY\tPAYEE\tCATEGORY\tAMOUNT")
+		}
 		for _, st := range v {
 			if st.Deleted {
 				continue
 			}
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.2f\n",
-				st.DateNext, st.Frequency, st.PayeeName, st.CategoryName,
-				client.MilliunitsToAmount(st.Amount))
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.2f",
+				st.DateNext, st.Frequency, f.nameOf(st.PayeeName, st.PayeeID), f.nameOf(st.CategoryName, st.CategoryID),
+				ynab.MilliunitsToAmount(st.Amount))
+			if wide {
+				fmt.Fprintf(w, "\t%s\t%s\t%s\t%s", f.nameOf(st.AccountName, st.AccountID), st.Memo, st.FlagColor, st.ID)
+			}
+			fmt.Fprintln(w)
 		}
 
-	case *client.ScheduledTransaction:
+	case *ynab.ScheduledTransaction:
 		fmt.Fprintln(w, "FIELD\tVALUE")
 		fmt.Fprintf(w, "ID\t%s\n", v.ID)
 		fmt.Fprintf(w, "Date First\t%s\n", v.DateFirst)
 		fmt.Fprintf(w, "Date Next\t%s\n", v.DateNext)
 		fmt.Fprintf(w, "Frequency\t%s\n", v.Frequency)
-		fmt.Fprintf(w, "Amount\t%.2f\n", client.MilliunitsToAmount(v.Amount))
-		fmt.Fprintf(w, "Payee\t%s\n", v.PayeeName)
-		fmt.Fprintf(w, "Category\t%s\n", v.CategoryName)
-		fmt.Fprintf(w, "Account\t%s\n", v.AccountName)
+		fmt.Fprintf(w, "Amount\t%.2f\n", ynab.MilliunitsToAmount(v.Amount))
+		fmt.Fprintf(w, "Payee\t%s\n", f.nameOf(v.PayeeName, v.PayeeID))
+		fmt.Fprintf(w, "Category\t%s\n", f.nameOf(v.CategoryName, v.CategoryID))
+		fmt.Fprintf(w, "Account\t%s\n", f.nameOf(v.AccountName, v.AccountID))
 		if v.Memo != "" {
 			fmt.Fprintf(w, "Memo\t%s\n", v.Memo)
 		}
 
-	case []client.Month:
+	case []ynab.Month:
 		fmt.Fprintln(w, "MONTH\tINCOME\tBUDGETED\tACTIVITY\tTO BE BUDGETED")
 		for _, m := range v {
 			if m.Deleted {
@@ -258,19 +644,19 @@ func (f *Formatter) printTable(data interface{}) error {
 			}
 			fmt.Fprintf(w, "%s\t%.2f\t%.2f\t%.2f\t%.2f\n",
 				m.Month,
-				client.MilliunitsToAmount(m.Income),
-				client.MilliunitsToAmount(m.Budgeted),
-				client.MilliunitsToAmount(m.Activity),
-				client.MilliunitsToAmount(m.ToBeBudgeted))
+				ynab.MilliunitsToAmount(m.Income),
+				ynab.MilliunitsToAmount(m.Budgeted),
+				ynab.MilliunitsToAmount(m.Activity),
+				ynab.MilliunitsToAmount(m.ToBeBudgeted))
 		}
 
-	case *client.Month:
+	case *ynab.Month:
 		fmt.Fprintln(w, "FIELD\tVALUE")
 		fmt.Fprintf(w, "Month\t%s\n", v.Month)
-		fmt.Fprintf(w, "Income\t%.2f\n", client.MilliunitsToAmount(v.Income))
-		fmt.Fprintf(w, "Budgeted\t%.2f\n", client.MilliunitsToAmount(v.Budgeted))
-		fmt.Fprintf(w, "Activity\t%.2f\n", client.MilliunitsToAmount(v.Activity))
-		fmt.Fprintf(w, "To Be Budgeted\t%.2f\n", client.MilliunitsToAmount(v.ToBeBudgeted))
+		fmt.Fprintf(w, "Income\t%.2f\n", ynab.MilliunitsToAmount(v.Income))
+		fmt.Fprintf(w, "Budgeted\t%.2f\n", ynab.MilliunitsToAmount(v.Budgeted))
+		fmt.Fprintf(w, "Activity\t%.2f\n", ynab.MilliunitsToAmount(v.Activity))
+		fmt.Fprintf(w, "To Be Budgeted\t%.2f\n", ynab.MilliunitsToAmount(v.ToBeBudgeted))
 		if v.AgeOfMoney > 0 {
 			fmt.Fprintf(w, "Age of Money\t%d days\n", v.AgeOfMoney)
 		}
@@ -278,6 +664,452 @@ func (f *Formatter) printTable(data interface{}) error {
 			fmt.Fprintf(w, "Note\t%s\n", v.Note)
 		}
 
+	case []ynab.Capability:
+		fmt.Fprintln(w, "ENDPOINT\tAVAILABLE\tRECORDS\tNEW FIELDS\tMISSING FIELDS")
+		for _, c := range v {
+			if c.Error != "" {
+				fmt.Fprintf(w, "%s\t%t\t-\terror: %s\t\n", c.Endpoint, c.Available, c.Error)
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%t\t%d\t%s\t%s\n",
+				c.Endpoint, c.Available, c.Records,
+				strings.Join(c.NewFields, ","), strings.Join(c.MissingFields, ","))
+		}
+
+	case *export.Preview:
+		fmt.Fprintln(w, "LINE\tSTATE\tDATE\tACCOUNT\tPAYEE\tCATEGORY\tAMOUNT\tPROBLEM")
+		for _, r := range v.Rows {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%.2f\t%s\n", r.Line, r.Status, r.Date, r.Account,
+				r.Payee, r.Category, ynab.MilliunitsToAmount(r.Amount), r.Error)
+		}
+
+	case *export.Reconciliation:
+		fmt.Fprintln(w, "RESULT\tCOUNT\tTOTAL")
+		fmt.Fprintf(w, "in file\t%d\t%.2f\n", v.FileRows, ynab.MilliunitsToAmount(v.FileTotal))
+		fmt.Fprintf(w, "created\t%d\t%.2f\n", v.Created, ynab.MilliunitsToAmount(v.CreatedTotal))
+		fmt.Fprintf(w, "already existed\t%d\t%.2f\n", v.Duplicates, ynab.MilliunitsToAmount(v.DuplicateTotal))
+		fmt.Fprintf(w, "skipped\t%d\t%.2f\n", v.Skipped, ynab.MilliunitsToAmount(v.SkippedTotal))
+		fmt.Fprintf(w, "difference\t\t%.2f\n", ynab.MilliunitsToAmount(v.Difference))
+
+	case *apispec.Report:
+		fmt.Fprintln(w, "KIND\tNAME\tDRIFT")
+		for _, e := range v.MissingEndpoints {
+			fmt.Fprintf(w, "endpoint\t%s %s\tnot implemented\n", e.Method, e.Path)
+		}
+		for _, e := range v.UnknownEndpoints {
+			fmt.Fprintf(w, "endpoint\t%s %s\tnot in spec\n", e.Method, e.Path)
+		}
+		for _, d := range v.Schemas {
+			if d.NotInSpec {
+				fmt.Fprintf(w, "schema\t%s\tnot in spec\n", d.Schema)
+			}
+			if len(d.MissingFields) > 0 {
+				fmt.Fprintf(w, "schema\t%s\tnot decoded: %s\n", d.Schema, strings.Join(d.MissingFields, ","))
+			}
+			if len(d.UnknownFields) > 0 {
+				fmt.Fprintf(w, "schema\t%s\tnot in spec: %s\n", d.Schema, strings.Join(d.UnknownFields, ","))
+			}
+		}
+
+	case *report.Commitments:
+		fmt.Fprintf(w, "%s\tCOUNT\tMONTHLY\n", strings.ToUpper(v.GroupBy))
+		for _, r := range v.Rows {
+			fmt.Fprintf(w, "%s\t%d\t%.2f\n", r.Name, r.Count, ynab.MilliunitsToAmount(r.Monthly))
+		}
+		fmt.Fprintf(w, "TOTAL\t\t%.2f\n", ynab.MilliunitsToAmount(v.Total))
+		if v.MonthlyIncome > 0 {
+			fmt.Fprintf(w, "SCHEDULED INCOME\t\t%.2f\n", ynab.MilliunitsToAmount(v.MonthlyIncome))
+		}
+
+	case *report.Spending:
+		fmt.Fprintf(w, "%s\tCOUNT\tAMOUNT\n", strings.ToUpper(v.GroupBy))
+		for _, r := range v.Rows {
+			fmt.Fprintf(w, "%s\t%d\t%.2f\n", r.Name, r.Count, ynab.MilliunitsToAmount(r.Amount))
+		}
+		fmt.Fprintf(w, "TOTAL\t\t%.2f\n", ynab.MilliunitsToAmount(v.Total))
+
+	case *report.AgeOfMoneyTrend:
+		fmt.Fprintln(w, "DATE\tAGE\tCHANGE\tSOURCE")
+		for i, p := range v.Points {
+			change := ""
+			if i > 0 {
+				change = fmt.Sprintf("%+d", p.AgeOfMoney-v.Points[i-1].AgeOfMoney)
+			}
+			fmt.Fprintf(w, "%s\t%d days\t%s\t%s\n", p.Date, p.AgeOfMoney, change, p.Source)
+		}
+		if len(v.Points) > 0 {
+			fmt.Fprintf(w, "CURRENT\t%d days\t%+d\tmin %d, max %d\n", v.Current, v.Change, v.Min, v.Max)
+		}
+
+	case *report.TBB:
+		fmt.Fprintln(w, "FIELD\tVALUE")
+		fmt.Fprintf(w, "Month\t%s\n", v.Month)
+		fmt.Fprintf(w, "To Be Budgeted\t%.2f\n", ynab.MilliunitsToAmount(v.ToBeBudgeted))
+		fmt.Fprintf(w, "Income\t%.2f\n", ynab.MilliunitsToAmount(v.Income))
+		fmt.Fprintf(w, "Budgeted\t%.2f\n", ynab.MilliunitsToAmount(v.Budgeted))
+
+	case *report.TBBHistory:
+		fmt.Fprintln(w, "DATE\tMONTH\tTO BE BUDGETED\tCHANGE\tSOURCE")
+		for i, p := range v.Points {
+			change := ""
+			if i > 0 {
+				change = fmt.Sprintf("%+.2f", ynab.MilliunitsToAmount(p.ToBeBudgeted-v.Points[i-1].ToBeBudgeted))
+			}
+			fmt.Fprintf(w, "%s\t%s\t%.2f\t%s\t%s\n", p.Date, p.Month[:7], ynab.MilliunitsToAmount(p.ToBeBudgeted), change, p.Source)
+		}
+		if len(v.Points) > 0 {
+			fmt.Fprintf(w, "CURRENT\t\t%.2f\t%+.2f\tmin %.2f, max %.2f\n", ynab.MilliunitsToAmount(v.Current),
+				ynab.MilliunitsToAmount(v.Change), ynab.MilliunitsToAmount(v.Min), ynab.MilliunitsToAmount(v.Max))
+		}
+
+	case []report.Subscription:
+		fmt.Fprintln(w, "PAYEE\tAMOUNT\tFREQUENCY\tCOUNT\tLAST\tNEXT\tACCOUNT\tCATEGORY")
+		for _, sub := range v {
+			fmt.Fprintf(w, "%s\t%.2f\t%s\t%d\t%s\t%s\t%s\t%s\n", sub.PayeeName, ynab.MilliunitsToAmount(sub.Amount),
+				sub.Frequency, sub.Count, sub.LastDate, sub.NextDate, sub.AccountName, sub.CategoryName)
+		}
+
+	case *report.Funding:
+		fmt.Fprintln(w, "CATEGORY\tNEED\tFUNDED\tSHORT\tBUDGETED")
+		for _, it := range v.Items {
+			fmt.Fprintf(w, "%s\t%.2f\t%.2f\t%.2f\t%.2f\n", it.Name, ynab.MilliunitsToAmount(it.Need),
+				ynab.MilliunitsToAmount(it.Funded), ynab.MilliunitsToAmount(it.Short), ynab.MilliunitsToAmount(it.Budgeted))
+		}
+		fmt.Fprintf(w, "TOTAL\t\t%.2f\t%.2f\tleft over %.2f\n", ynab.MilliunitsToAmount(v.Funded),
+			ynab.MilliunitsToAmount(v.Short), ynab.MilliunitsToAmount(v.LeftOver))
+
+	case *allocplan.Allocation:
+		fmt.Fprintln(w, "CATEGORY\tKIND\tAMOUNT\tBUDGETED")
+		for _, it := range v.Items {
+			kind := it.Kind
+			if it.Kind == allocplan.Percent {
+				kind = fmt.Sprintf("%g%%", it.Percent)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%.2f\t%.2f\n", it.Name, kind,
+				ynab.MilliunitsToAmount(it.Amount), ynab.MilliunitsToAmount(it.Budgeted))
+		}
+		fmt.Fprintf(w, "TOTAL\tof %.2f\t%.2f\tleft over %.2f\n", ynab.MilliunitsToAmount(v.Income),
+			ynab.MilliunitsToAmount(v.Allocated), ynab.MilliunitsToAmount(v.LeftOver))
+
+	case *seed.Summary:
+		fmt.Fprintln(w, "FIELD\tVALUE")
+		fmt.Fprintf(w, "Seed\t%d\n", v.Seed)
+		fmt.Fprintf(w, "Accounts\t%d\n", v.Accounts)
+		fmt.Fprintf(w, "Categories\t%d\n", v.Categories)
+		fmt.Fprintf(w, "Transactions\t%d\n", v.Transactions)
+		fmt.Fprintf(w, "Duplicates\t%d\n", v.Duplicates)
+		fmt.Fprintf(w, "Dates\t%s – %s\n", v.FirstDate, v.LastDate)
+		fmt.Fprintf(w, "Import prefix\t%s\n", v.ImportPrefix)
+
+	case *pacing.Plan:
+		fmt.Fprintln(w, "FIELD\tVALUE")
+		fmt.Fprintf(w, "Job\t%s (%s)\n", v.Job, v.Kind)
+		fmt.Fprintf(w, "Items\t%d\n", v.Items)
+		fmt.Fprintf(w, "Requests\t%d\n", v.Requests)
+		fmt.Fprintf(w, "Remaining\t%d of %d\n", v.Remaining, v.Limit)
+		if v.Throttled {
+			wait := time.Duration(v.WaitSeconds) * time.Second
+			fmt.Fprintf(w, "Throttled\tafter %d requests\n", v.ThrottledAfter)
+			fmt.Fprintf(w, "Wait\tabout %s\n", wait.Round(time.Minute))
+			fmt.Fprintf(w, "Finish\taround %s\n", v.Finish.Local().Format("2006-01-02 15:04"))
+		} else {
+			fmt.Fprintln(w, "Throttled\tno")
+		}
+
+	case []budgetplan.Change:
+		fmt.Fprintln(w, "MONTH\tCATEGORY\tFIELD\tBEFORE\tAFTER")
+		for _, c := range v {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%.2f\t%.2f\n", c.Month, c.Label, c.Field,
+				ynab.MilliunitsToAmount(c.Before), ynab.MilliunitsToAmount(c.After))
+		}
+
+	case []schedplan.Change:
+		fmt.Fprintln(w, "ACTION\tSCHEDULED\tFIELD\tBEFORE\tAFTER")
+		for _, c := range v {
+			for _, fc := range c.Fields {
+				fmt.Fprintf(w, "%s\t%s (%s)\t%s\t%s\t%s\n", c.Action, c.Payee, c.Account, fc.Name, fc.Before, fc.After)
+			}
+		}
+
+	case []matching.Pair:
+		fmt.Fprintln(w, "ACCOUNT\tIMPORTED\tMANUAL\tIMPORTED PAYEE\tMANUAL PAYEE\tAMOUNT\tDIFF\tDAYS")
+		for _, p := range v {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%.2f\t%.2f\t%d\n", p.Imported.AccountName,
+				p.Imported.Date, p.Manual.Date, p.Imported.PayeeName, p.Manual.PayeeName,
+				ynab.MilliunitsToAmount(p.Imported.Amount), ynab.MilliunitsToAmount(p.AmountDiff), p.DaysApart)
+		}
+
+	case []policy.Violation:
+		fmt.Fprintln(w, "SEVERITY\tRULE\tMESSAGE")
+		for _, pv := range v {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", pv.Severity, pv.Rule, pv.Message)
+		}
+
+	case []migrate.Step:
+		fmt.Fprintln(w, "KIND\tGROUP\tNAME\tACTION\tREASON")
+		for _, s := range v {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.Kind, f.truncate(s.Group, 25), f.truncate(s.Name, 40), s.Action, s.Reason)
+		}
+
+	case *report.Status:
+		fmt.Fprintln(w, "FIELD\tVALUE")
+		fmt.Fprintf(w, "Month\t%s\n", v.Month)
+		fmt.Fprintf(w, "To Be Budgeted\t%.2f\n", ynab.MilliunitsToAmount(v.ToBeBudgeted))
+		fmt.Fprintf(w, "Unapproved\t%d\n", v.Unapproved)
+		fmt.Fprintf(w, "Uncategorized\t%d\n", v.Uncategorized)
+		fmt.Fprintf(w, "Age of Money\t%d days\n", v.AgeOfMoney)
+		fmt.Fprintf(w, "Updated\t%s\n", v.UpdatedAt.Local().Format("2006-01-02 15:04"))
+
+	case *report.Amortization:
+		fmt.Fprintln(w, "FIELD\tVALUE")
+		fmt.Fprintf(w, "Account\t%s\n", v.AccountName)
+		fmt.Fprintf(w, "Balance\t%.2f\n", ynab.MilliunitsToAmount(v.Balance))
+		fmt.Fprintf(w, "Interest rate\t%g%%\n", v.InterestRate)
+		fmt.Fprintf(w, "Payment\t%.2f\n", ynab.MilliunitsToAmount(v.Payment))
+		fmt.Fprintf(w, "Escrow\t%.2f\n", ynab.MilliunitsToAmount(v.Escrow))
+		fmt.Fprintf(w, "Paid off\t%s (%d months)\n", v.Baseline.PayoffMonth, v.Baseline.Months)
+		fmt.Fprintf(w, "Total interest\t%.2f\n", ynab.MilliunitsToAmount(v.Baseline.TotalInterest))
+		fmt.Fprintf(w, "Total paid\t%.2f\n", ynab.MilliunitsToAmount(v.Baseline.TotalPaid))
+		if x := v.WithExtra; x != nil {
+			fmt.Fprintf(w, "Extra payment\t%.2f\n", ynab.MilliunitsToAmount(v.Extra))
+			fmt.Fprintf(w, "Paid off with extra\t%s (%d months)\n", x.PayoffMonth, x.Months)
+			fmt.Fprintf(w, "Total interest with extra\t%.2f\n", ynab.MilliunitsToAmount(x.TotalInterest))
+			fmt.Fprintf(w, "Total paid with extra\t%.2f\n", ynab.MilliunitsToAmount(x.TotalPaid))
+			fmt.Fprintf(w, "Months saved\t%d\n", v.MonthsSaved)
+			fmt.Fprintf(w, "Interest saved\t%.2f\n", ynab.MilliunitsToAmount(v.InterestSaved))
+		}
+
+	case []report.ReferenceProblem:
+		fmt.Fprintln(w, "PROBLEM\tDATE\tACCOUNT\tPAYEE\tAMOUNT\tREFERENCE\tFIX")
+		for _, p := range v {
+			ref := p.RefName
+			if ref == "" {
+				ref = p.RefID
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.2f\t%s\t%s\n", p.Kind, p.Date, p.Account,
+				f.truncate(p.Payee, 30), ynab.MilliunitsToAmount(p.Amount), ref, p.Fix)
+		}
+
+	case []report.Allowance:
+		fmt.Fprintln(w, "MEMBER\tCATEGORY\tBALANCE\tWEEKLY\tSPENT\tSINCE")
+		for _, a := range v {
+			fmt.Fprintf(w, "%s\t%s\t%.2f\t%.2f\t%.2f\t%s\n", a.Member, a.CategoryName,
+				ynab.MilliunitsToAmount(a.Balance), ynab.MilliunitsToAmount(a.Weekly),
+				ynab.MilliunitsToAmount(a.Spent), a.Since)
+		}
+
+	case *report.Allowance:
+		if err := f.writeRows(out, []report.Allowance{*v}); err != nil {
+			return err
+		}
+		if len(v.Transactions) > 0 {
+			fmt.Fprintln(w)
+			if err := f.writeRows(out, v.Transactions); err != nil {
+				return err
+			}
+		}
+
+	case *report.Consolidated:
+		fmt.Fprintf(w, "BUDGET\tCURRENCY\tRATE\tNET WORTH\tSPENDING\tUPCOMING\n")
+		for _, b := range v.Budgets {
+			fmt.Fprintf(w, "%s\t%s\t%g\t%.2f\t%.2f\t%.2f\n", b.Name, b.Currency, b.Rate,
+				ynab.MilliunitsToAmount(b.NetWorth), ynab.MilliunitsToAmount(b.Spending), ynab.MilliunitsToAmount(b.Upcoming))
+		}
+		fmt.Fprintf(w, "TOTAL\t%s\t\t%.2f\t%.2f\t%.2f\n", v.Currency,
+			ynab.MilliunitsToAmount(v.NetWorth), ynab.MilliunitsToAmount(v.Spending.Total), ynab.MilliunitsToAmount(v.UpcomingTotal))
+		fmt.Fprintln(w)
+		if err := f.writeRows(out, v.Spending); err != nil {
+			return err
+		}
+		if len(v.Upcoming) > 0 {
+			fmt.Fprintln(w)
+			if err := f.writeRows(out, v.Upcoming); err != nil {
+				return err
+			}
+		}
+
+	case []report.UpcomingBill:
+		fmt.Fprintln(w, "DATE\tBUDGET\tACCOUNT\tPAYEE\tAMOUNT")
+		for _, b := range v {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.2f\n", b.Date, b.Budget, b.Account, f.truncate(b.Payee, 30), ynab.MilliunitsToAmount(b.Amount))
+		}
+
+	case *report.CategoryInspection:
+		c := v.Category
+		fmt.Fprintln(w, "FIELD\tVALUE")
+		fmt.Fprintf(w, "Name\t%s\n", c.Name)
+		fmt.Fprintf(w, "Group\t%s\n", f.nameOf(c.CategoryGroupName, c.CategoryGroupID))
+		fmt.Fprintf(w, "Month\t%s\n", v.Month[:7])
+		fmt.Fprintf(w, "Budgeted\t%.2f\n", ynab.MilliunitsToAmount(c.Budgeted))
+		fmt.Fprintf(w, "Activity\t%.2f\n", ynab.MilliunitsToAmount(c.Activity))
+		fmt.Fprintf(w, "Balance\t%.2f\n", ynab.MilliunitsToAmount(c.Balance))
+		if c.GoalType != "" {
+			fmt.Fprintf(w, "Goal\t%s, target %.2f\n", c.GoalType, ynab.MilliunitsToAmount(c.GoalTarget))
+			if c.GoalTargetMonth != "" {
+				fmt.Fprintf(w, "Goal Month\t%s\n", c.GoalTargetMonth)
+			}
+			fmt.Fprintf(w, "Goal Progress\t%d%%\n", c.GoalPercentageComplete)
+			fmt.Fprintf(w, "Underfunded\t%.2f\n", ynab.MilliunitsToAmount(c.GoalUnderFunded))
+			fmt.Fprintf(w, "Left Overall\t%.2f\n", ynab.MilliunitsToAmount(c.GoalOverallLeft))
+		}
+		if v.History != nil {
+			fmt.Fprintln(w)
+			if err := f.writeRows(out, v.History); err != nil {
+				return err
+			}
+		}
+		if len(v.Transactions) > 0 {
+			fmt.Fprintln(w)
+			if err := f.writeRows(out, v.Transactions); err != nil {
+				return err
+			}
+		}
+		if len(v.Scheduled) > 0 {
+			fmt.Fprintln(w)
+			if err := f.writeRows(out, v.Scheduled); err != nil {
+				return err
+			}
+		}
+
+	case *report.CategoryHistory:
+		fmt.Fprintln(w, "MONTH\tBUDGETED\tACTIVITY\tBALANCE")
+		for _, m := range v.Months {
+			fmt.Fprintf(w, "%s\t%.2f\t%.2f\t%.2f\n", m.Month[:7],
+				ynab.MilliunitsToAmount(m.Budgeted), ynab.MilliunitsToAmount(m.Activity), ynab.MilliunitsToAmount(m.Balance))
+		}
+		fmt.Fprintf(w, "Total\t%.2f\t%.2f\t\n", ynab.MilliunitsToAmount(v.TotalBudgeted), ynab.MilliunitsToAmount(v.TotalActivity))
+		fmt.Fprintf(w, "Average\t%.2f\t%.2f\t\n", ynab.MilliunitsToAmount(v.AverageBudgeted), ynab.MilliunitsToAmount(v.AverageActivity))
+
+	case []report.AmortizationRow:
+		fmt.Fprintln(w, "MONTH\tPAYMENT\tINTEREST\tPRINCIPAL\tBALANCE")
+		for _, r := range v {
+			fmt.Fprintf(w, "%s\t%.2f\t%.2f\t%.2f\t%.2f\n", r.Month,
+				ynab.MilliunitsToAmount(r.Payment), ynab.MilliunitsToAmount(r.Interest),
+				ynab.MilliunitsToAmount(r.Principal), ynab.MilliunitsToAmount(r.Balance))
+		}
+
+	case *report.CapsReport:
+		fmt.Fprintln(w, "GROUP\tSPENT\tCAP\tREMAINING\tUSED\tSTATE")
+		for _, g := range v.Groups {
+			fmt.Fprintf(w, "%s\t%.2f\t%.2f\t%.2f\t%.0f%%\t%s\n", f.truncate(g.Group, 30),
+				ynab.MilliunitsToAmount(g.Spent), ynab.MilliunitsToAmount(g.Cap),
+				ynab.MilliunitsToAmount(g.Remaining), g.Percent, g.State)
+		}
+
+	case []notemeta.Account:
+		fmt.Fprintln(w, "ACCOUNT\tKEY\tVALUE")
+		for _, a := range v {
+			keys := make([]string, 0, len(a.Meta))
+			for k := range a.Meta {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", f.truncate(a.AccountName, 30), k, a.Meta[k])
+			}
+		}
+
+	case *report.Weekly:
+		fmt.Fprint(w, "GROUP\tCATEGORY\tBUDGETED")
+		for _, wk := range v.Weeks {
+			fmt.Fprintf(w, "\t%s..%s", wk.Start[5:], wk.End[5:])
+		}
+		fmt.Fprintln(w)
+		for _, r := range v.Rows {
+			fmt.Fprintf(w, "%s\t%s\t%.2f", r.Group, r.Category, ynab.MilliunitsToAmount(r.Budgeted))
+			for i := range v.Weeks {
+				mark := ""
+				if r.Spent[i] > r.Prorated[i] {
+					mark = "!"
+				}
+				fmt.Fprintf(w, "\t%.2f/%.2f%s", ynab.MilliunitsToAmount(r.Spent[i]),
+					ynab.MilliunitsToAmount(r.Prorated[i]), mark)
+			}
+			fmt.Fprintln(w)
+		}
+
+	case *report.GoalSchedule:
+		fmt.Fprintln(w, "MONTH\tREQUIRED\tINCOME\tSHORTFALL")
+		for i, m := range v.Months {
+			shortfall := ""
+			if v.Income > 0 && v.Totals[i] > v.Income {
+				shortfall = fmt.Sprintf("%.2f", ynab.MilliunitsToAmount(v.Totals[i]-v.Income))
+			}
+			fmt.Fprintf(w, "%s\t%.2f\t%.2f\t%s\n", m[:7],
+				ynab.MilliunitsToAmount(v.Totals[i]), ynab.MilliunitsToAmount(v.Income), shortfall)
+		}
+
+	case *report.Moves:
+		fmt.Fprintf(w, "GROUP\tCATEGORY\tBEFORE\tAFTER\tCHANGE\n")
+		for _, r := range v.Rows {
+			fmt.Fprintf(w, "%s\t%s\t%.2f\t%.2f\t%+.2f\n", r.Group, r.Category,
+				ynab.MilliunitsToAmount(r.Before), ynab.MilliunitsToAmount(r.After),
+				ynab.MilliunitsToAmount(r.Change))
+		}
+		fmt.Fprintf(w, "MOVED IN\t\t\t\t%.2f\n", ynab.MilliunitsToAmount(v.MovedIn))
+		fmt.Fprintf(w, "MOVED OUT\t\t\t\t%.2f\n", ynab.MilliunitsToAmount(v.MovedOut))
+
+	case *report.AccountsSummary:
+		fmt.Fprintln(w, "GROUP\tACCOUNT\tTYPE\tBALANCE")
+		for _, g := range v.Groups {
+			for _, a := range g.Accounts {
+				name := a.Name
+				if a.Closed {
+					name += " (closed)"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%.2f\n", g.Group, name, a.Type, ynab.MilliunitsToAmount(a.Balance))
+			}
+			fmt.Fprintf(w, "%s\tSubtotal\t\t%.2f\n", g.Group, ynab.MilliunitsToAmount(g.Subtotal))
+		}
+		fmt.Fprintf(w, "NET\t\t\t%.2f\n", ynab.MilliunitsToAmount(v.Net))
+
+	case *report.Projection:
+		fmt.Fprintf(w, "DATE\tPAYEE\tAMOUNT\tBALANCE\n")
+		fmt.Fprintf(w, "\tCleared balance (%s)\t\t%.2f\n", v.Account, ynab.MilliunitsToAmount(v.ClearedBalance))
+		for _, it := range v.Items {
+			fmt.Fprintf(w, "%s\t%s\t%.2f\t%.2f\n", it.Date, f.truncate(it.Payee, 30),
+				ynab.MilliunitsToAmount(it.Amount), ynab.MilliunitsToAmount(it.Balance))
+		}
+		fmt.Fprintf(w, "LOWEST\t%s\t\t%.2f\n", v.LowestDate, ynab.MilliunitsToAmount(v.LowestBalance))
+		if v.Shortfall > 0 {
+			fmt.Fprintf(w, "SHORTFALL\t\t\t%.2f\n", ynab.MilliunitsToAmount(v.Shortfall))
+		}
+
+	case *report.Runway:
+		fmt.Fprintf(w, "Averaged Months\t%d (%s to %s)\n", v.MonthsAveraged, v.FromMonth, v.ToMonth)
+		fmt.Fprintf(w, "Average Expenses\t%.2f\n", ynab.MilliunitsToAmount(v.AverageExpenses))
+		fmt.Fprintf(w, "Cash\t%.2f\n", ynab.MilliunitsToAmount(v.Cash))
+		fmt.Fprintf(w, "Cash Runway\t%.1f months\n", v.CashMonths)
+		if len(v.SavingsCategories) > 0 {
+			fmt.Fprintf(w, "Savings (%s)\t%.2f\n", strings.Join(v.SavingsCategories, ", "), ynab.MilliunitsToAmount(v.Savings))
+			fmt.Fprintf(w, "Savings Runway\t%.1f months\n", v.SavingsMonths)
+		}
+
+	case *idcache.Entry:
+		fmt.Fprintln(w, "FIELD\tVALUE")
+		fmt.Fprintf(w, "ID\t%s\n", v.ID)
+		fmt.Fprintf(w, "Kind\t%s\n", v.Kind)
+		fmt.Fprintf(w, "Name\t%s\n", v.Name)
+		if v.BudgetID != "" {
+			fmt.Fprintf(w, "Budget\t%s\n", f.nameOf("", v.BudgetID))
+		}
+
+	case []slugs.Entry:
+		fmt.Fprintln(w, "KIND\tSLUG\tNAME\tID")
+		for _, e := range v {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Kind, e.Slug, f.nameOf("", e.ID), e.ID)
+		}
+
+	case []audit.Entry:
+		fmt.Fprintln(w, "TIME\tCOMMAND\tIDS\tRESULT")
+		for _, e := range v {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+				e.Time.Local().Format("2006-01-02 15:04:05"),
+				f.truncate(strings.Join(e.Args, " "), 50),
+				f.truncate(strings.Join(e.IDs, ","), 40),
+				f.truncate(e.Result, 40))
+		}
+
 	default:
 		// Fall back to JSON for unknown types
 		return f.printJSON(data)
@@ -286,10 +1118,19 @@ func (f *Formatter) printTable(data interface{}) error {
 	return nil
 }
 
-// truncate shortens a string to the given length
-func truncate(s string, length int) string {
-	if len(s) <= length {
-		return s
+// nameOf returns name, or the cached name for id when the payload
+// carried only the ID
+func (f *Formatter) nameOf(name, id string) string {
+	if name != "" || id == "" || f.opts.LookupName == nil {
+		return name
+	}
+	return f.opts.LookupName(id)
+}
+
+// slugOf returns the slug of id, or "" without a slug lookup
+func (f *Formatter) slugOf(id string) string {
+	if f.opts.LookupSlug == nil {
+		return ""
 	}
-	return s[:length-3] + "..."
+	return f.opts.LookupSlug(id)
 }
@@ -0,0 +1,69 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+)
+
+// pagerCommand returns the pager to run: $YNABCTL_PAGER, $PAGER or less.
+// An empty value or "cat" turns paging off.
+func pagerCommand(lookup func(string) (string, bool)) []string {
+	pager := "less"
+	for _, key := range []string{"YNABCTL_PAGER", "PAGER"} {
+		if v, ok := lookup(key); ok {
+			pager = v
+			break
+		}
+	}
+	args := strings.Fields(pager)
+	if len(args) == 0 || args[0] == "cat" {
+		return nil
+	}
+	return args
+}
+
+// fitsScreen reports whether text is known to fit the terminal, going by
+// $LINES. Without it, less (started with -F) decides by itself.
+func fitsScreen(text []byte) bool {
+	height, err := strconv.Atoi(os.Getenv("LINES"))
+	return err == nil && height > 0 && bytes.Count(text, []byte("\n")) < height
+}
+
+// page writes text to out through the pager, like git does. less gets
+// LESS=FRX unless set, so it exits at once when the text fits the
+// screen, keeps colors and leaves the text on screen. Without a pager
+// the text is written directly.
+func page(out io.Writer, text []byte) error {
+	args := pagerCommand(os.LookupEnv)
+	if args == nil || fitsScreen(text) {
+		_, err := out.Write(text)
+		return err
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(text)
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(cmd.Env, "LESS=FRX")
+	}
+
+	// Ctrl-C is for the pager; the output is already complete
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	if err := cmd.Start(); err != nil {
+		_, err := out.Write(text)
+		return err
+	}
+	// quitting the pager before the end is not an error
+	_ = cmd.Wait()
+	return nil
+}
@@ -0,0 +1,60 @@
+package output
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestPagerCommand(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want []string
+	}{
+		{map[string]string{}, []string{"less"}},
+		{map[string]string{"PAGER": "more -d"}, []string{"more", "-d"}},
+		{map[string]string{"PAGER": "more", "YNABCTL_PAGER": "less -S"}, []string{"less", "-S"}},
+		{map[string]string{"PAGER": ""}, nil},
+		{map[string]string{"PAGER": "cat"}, nil},
+	}
+	for _, tt := range tests {
+		lookup := func(key string) (string, bool) {
+			v, ok := tt.env[key]
+			return v, ok
+		}
+		if got := pagerCommand(lookup); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("pagerCommand(%v) = %q, want %q", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestPage(t *testing.T) {
+	t.Setenv("YNABCTL_PAGER", "tr a-z A-Z")
+	t.Setenv("LINES", "")
+
+	var out bytes.Buffer
+	if err := page(&out, []byte("one\ntwo\n")); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "ONE\nTWO\n" {
+		t.Errorf("paged output = %q", out.String())
+	}
+
+	// short enough for the screen: no pager
+	t.Setenv("LINES", "10")
+	out.Reset()
+	if err := page(&out, []byte("one\ntwo\n")); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "one\ntwo\n" {
+		t.Errorf("direct output = %q", out.String())
+	}
+
+	// a missing pager falls back to writing directly
+	t.Setenv("LINES", "")
+	t.Setenv("YNABCTL_PAGER", "no-such-pager-xyz")
+	out.Reset()
+	if err := page(&out, []byte("one\n")); err != nil || out.String() != "one\n" {
+		t.Errorf("fallback output = %q, %v", out.String(), err)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package output
+
+import "os"
+
+// terminalWidth is unknown on this platform; $COLUMNS is used instead
+func terminalWidth(f *os.File) int {
+	return 0
+}
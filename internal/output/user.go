@@ -0,0 +1,15 @@
+package output
+
+import "time"
+
+// UserStatus is the authenticated user together with how ynabctl is set
+// up to act for them. Everything but the ID is assembled locally.
+type UserStatus struct {
+	ID              string     `json:"id"`
+	DefaultBudgetID string     `json:"default_budget_id,omitempty"`
+	DefaultBudget   string     `json:"default_budget,omitempty"`
+	TokenType       string     `json:"token_type"`
+	TokenSource     string     `json:"token_source"`
+	TokenLastUsed   *time.Time `json:"token_last_used,omitempty"`
+	Warnings        []string   `json:"warnings,omitempty"`
+}
@@ -0,0 +1,150 @@
+// Package pacing keeps bulk jobs under the YNAB API rate limit of 200
+// requests per rolling hour, estimates their completion time, and
+// checkpoints finished work so an interrupted job can resume.
+package pacing
+
+import (
+	"sync"
+	"time"
+)
+
+// Default YNAB rate limit
+const (
+	DefaultLimit  = 200
+	DefaultWindow = time.Hour
+)
+
+// Pacer delays requests so no more than limit are sent in any window.
+// It counts the requests it has seen and trusts the API's own count
+// (Observe) when that is higher, e.g. because another script shares the
+// token.
+type Pacer struct {
+	mu         sync.Mutex
+	limit      int
+	window     time.Duration
+	sent       []time.Time
+	serverUsed int
+
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// New creates a pacer for limit requests per window
+func New(limit int, window time.Duration) *Pacer {
+	return &Pacer{limit: limit, window: window, now: time.Now, sleep: time.Sleep}
+}
+
+// Observe records the usage reported by the API
+func (p *Pacer) Observe(used, limit int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.serverUsed = used
+	if limit > 0 {
+		p.limit = limit
+	}
+}
+
+// Wait blocks until a request may be sent and counts it
+func (p *Pacer) Wait() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for {
+		if p.usedLocked() < p.limit {
+			p.sent = append(p.sent, p.now())
+			p.serverUsed++
+			return
+		}
+
+		// Wait for our oldest request to leave the window. Requests only
+		// the API knows about age out at an unknown time, so assume one
+		// does per average interval.
+		delay := p.window / time.Duration(p.limit)
+		if len(p.sent) >= p.limit {
+			delay = p.sent[0].Add(p.window).Sub(p.now())
+		} else {
+			p.serverUsed--
+		}
+		p.mu.Unlock()
+		p.sleep(delay)
+		p.mu.Lock()
+	}
+}
+
+// Available returns how many requests can be sent right now
+func (p *Pacer) Available() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if n := p.limit - p.usedLocked(); n > 0 {
+		return n
+	}
+	return 0
+}
+
+// ETA estimates how long n more requests will take, ignoring the time
+// of the requests themselves
+func (p *Pacer) ETA(n int) time.Duration {
+	avail := p.Available()
+	if n <= avail {
+		return 0
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return time.Duration(n-avail) * p.window / time.Duration(p.limit)
+}
+
+// usedLocked prunes requests older than the window and returns the
+// number of requests counted against the limit
+func (p *Pacer) usedLocked() int {
+	cutoff := p.now().Add(-p.window)
+	i := 0
+	for i < len(p.sent) && !p.sent[i].After(cutoff) {
+		i++
+	}
+	p.sent = p.sent[i:]
+	// requests that left our window left the API's window too
+	p.serverUsed -= i
+	if p.serverUsed < 0 {
+		p.serverUsed = 0
+	}
+	if p.serverUsed > len(p.sent) {
+		return p.serverUsed
+	}
+	return len(p.sent)
+}
+
+// Plan predicts how a job fares against the rate limit before it starts
+type Plan struct {
+	// Job, Kind and Items describe the job; they are set by the caller
+	Job       string `json:"job,omitempty"`
+	Kind      string `json:"kind,omitempty"`
+	Items     int    `json:"items"`
+	Requests  int    `json:"requests"`
+	Remaining int    `json:"remaining_requests"`
+	Limit     int    `json:"limit"`
+	// Throttled is whether the job will have to wait, which happens
+	// after ThrottledAfter of its requests
+	Throttled      bool      `json:"throttled"`
+	ThrottledAfter int       `json:"throttled_after,omitempty"`
+	WaitSeconds    int64     `json:"wait_seconds"`
+	Finish         time.Time `json:"finish"`
+}
+
+// Plan predicts how n more requests fare against the rate limit
+func (p *Pacer) Plan(n int) *Plan {
+	remaining := p.Available()
+	eta := p.ETA(n)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	plan := &Plan{
+		Requests:    n,
+		Remaining:   remaining,
+		Limit:       p.limit,
+		Throttled:   n > remaining,
+		WaitSeconds: int64(eta / time.Second),
+		Finish:      p.now().Add(eta),
+	}
+	if plan.Throttled {
+		plan.ThrottledAfter = remaining
+	}
+	return plan
+}
@@ -0,0 +1,122 @@
+package pacing
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock returns a pacer whose sleeps advance a fake clock
+func fakeClock(limit int, window time.Duration) (*Pacer, *time.Time, *time.Duration) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	var slept time.Duration
+	p := New(limit, window)
+	p.now = func() time.Time { return now }
+	p.sleep = func(d time.Duration) {
+		slept += d
+		now = now.Add(d)
+	}
+	return p, &now, &slept
+}
+
+func TestPacerWaitsForWindow(t *testing.T) {
+	p, _, slept := fakeClock(3, time.Hour)
+	for i := 0; i < 3; i++ {
+		p.Wait()
+	}
+	if *slept != 0 {
+		t.Fatalf("slept %v within the limit", *slept)
+	}
+	if p.Available() != 0 {
+		t.Fatalf("Available = %d, want 0", p.Available())
+	}
+
+	p.Wait()
+	if *slept != time.Hour {
+		t.Errorf("slept %v, want 1h for the first request to expire", *slept)
+	}
+}
+
+func TestPacerTrustsServerCount(t *testing.T) {
+	p, _, slept := fakeClock(200, time.Hour)
+	p.Observe(200, 200)
+	if p.Available() != 0 {
+		t.Fatalf("Available = %d, want 0", p.Available())
+	}
+	p.Wait()
+	if *slept != 18*time.Second {
+		t.Errorf("slept %v, want one average interval (18s)", *slept)
+	}
+}
+
+func TestPacerETA(t *testing.T) {
+	p, _, _ := fakeClock(200, time.Hour)
+	p.Observe(150, 200)
+	if eta := p.ETA(50); eta != 0 {
+		t.Errorf("ETA(50) = %v, want 0", eta)
+	}
+	if eta := p.ETA(60); eta != 3*time.Minute {
+		t.Errorf("ETA(60) = %v, want 3m", eta)
+	}
+}
+
+func TestQueueResumes(t *testing.T) {
+	dir := t.TempDir()
+	keys := []string{"a", "b", "c"}
+
+	cp, err := LoadCheckpoint(dir, "job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ran []string
+	q := &Queue{Checkpoint: cp}
+	err = q.Run(keys, func(key string) error {
+		if key == "b" {
+			return errors.New("interrupted")
+		}
+		ran = append(ran, key)
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected the error from b")
+	}
+
+	cp, err = OpenCheckpoint(cp.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.Job != "job" || cp.InFlight != "b" {
+		t.Errorf("checkpoint = %+v, want job with b in flight", cp)
+	}
+	q = &Queue{Checkpoint: cp}
+	if got := q.Pending(keys); len(got) != 2 || got[0] != "b" {
+		t.Fatalf("Pending = %v, want [b c]", got)
+	}
+	if err := q.Run(keys, func(key string) error { ran = append(ran, key); return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if len(ran) != 3 || ran[1] != "b" || ran[2] != "c" {
+		t.Errorf("ran %v, want [a b c]", ran)
+	}
+
+	cp, _ = LoadCheckpoint(dir, "job")
+	if len(cp.Done) != 0 {
+		t.Errorf("checkpoint not removed after completion: %v", cp.Done)
+	}
+}
+
+func TestPacerPlan(t *testing.T) {
+	p, now, _ := fakeClock(200, time.Hour)
+	p.Observe(180, 200)
+	plan := p.Plan(10)
+	if plan.Throttled || plan.WaitSeconds != 0 || plan.Remaining != 20 {
+		t.Errorf("Plan(10) = %+v, want 20 remaining and no wait", plan)
+	}
+	plan = p.Plan(80)
+	if !plan.Throttled || plan.ThrottledAfter != 20 {
+		t.Errorf("Plan(80) = %+v, want throttled after 20 requests", plan)
+	}
+	if plan.WaitSeconds != 18*60 || !plan.Finish.Equal(now.Add(18*time.Minute)) {
+		t.Errorf("Plan(80) waits %ds until %v, want 18m", plan.WaitSeconds, plan.Finish)
+	}
+}
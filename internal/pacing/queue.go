@@ -0,0 +1,171 @@
+package pacing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/config"
+)
+
+// Checkpoint records which items of a job are done. It is written ahead
+// of every item (InFlight) and after it, so an interrupted job resumes
+// where it stopped and an item that may have been half applied is known.
+type Checkpoint struct {
+	Job      string          `json:"job"`
+	Started  time.Time       `json:"started"`
+	Done     map[string]bool `json:"done"`
+	InFlight string          `json:"in_flight,omitempty"`
+
+	path string
+}
+
+// CheckpointDir returns the directory holding job checkpoints, honoring
+// XDG_STATE_HOME and defaulting to ~/.local/state/ynabctl/jobs
+func CheckpointDir() string {
+	return filepath.Join(config.StateDir(), "jobs")
+}
+
+// LoadCheckpoint returns the checkpoint of job in dir, or a new one if
+// the job has not run before
+func LoadCheckpoint(dir, job string) (*Checkpoint, error) {
+	c, err := OpenCheckpoint(filepath.Join(dir, job+".json"))
+	if os.IsNotExist(err) {
+		return &Checkpoint{
+			Job:     job,
+			Started: time.Now().UTC(),
+			Done:    map[string]bool{},
+			path:    filepath.Join(dir, job+".json"),
+		}, nil
+	}
+	return c, err
+}
+
+// OpenCheckpoint reads an existing checkpoint file, e.g. one given with
+// --resume. A missing file is reported as an os.IsNotExist error.
+func OpenCheckpoint(path string) (*Checkpoint, error) {
+	c := &Checkpoint{Done: map[string]bool{}, path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", c.path, err)
+	}
+	if c.Done == nil {
+		c.Done = map[string]bool{}
+	}
+	return c, nil
+}
+
+// Path returns the file the checkpoint is saved to
+func (c *Checkpoint) Path() string {
+	return c.path
+}
+
+// Begin records that key is about to be processed and saves the
+// checkpoint, before any request for it is made
+func (c *Checkpoint) Begin(key string) error {
+	c.InFlight = key
+	return c.save()
+}
+
+// MarkDone records key as finished and saves the checkpoint
+func (c *Checkpoint) MarkDone(key string) error {
+	c.Done[key] = true
+	c.InFlight = ""
+	return c.save()
+}
+
+func (c *Checkpoint) save() error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return fmt.Errorf("mkdir %s: %w", filepath.Dir(c.path), err)
+	}
+	return os.WriteFile(c.path, data, 0o600)
+}
+
+// Remove deletes the checkpoint once the job is complete
+func (c *Checkpoint) Remove() error {
+	err := os.Remove(c.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Queue runs the items of a bulk job one at a time, paced and
+// checkpointed
+type Queue struct {
+	Pacer      *Pacer
+	Checkpoint *Checkpoint
+	// RequestsPerItem is how many API requests one item costs (default 1);
+	// it is only used for the completion estimate
+	RequestsPerItem int
+	// OnProgress is called before each item with the number of items
+	// done, the total, and the estimated time left
+	OnProgress func(done, total int, eta time.Duration)
+}
+
+// Pending returns the keys not yet done in the checkpoint
+func (q *Queue) Pending(keys []string) []string {
+	if q.Checkpoint == nil {
+		return keys
+	}
+	var pending []string
+	for _, k := range keys {
+		if !q.Checkpoint.Done[k] {
+			pending = append(pending, k)
+		}
+	}
+	return pending
+}
+
+// Run calls fn for every key not done yet. It stops at the first error,
+// leaving the checkpoint in place so the job can be resumed; after the
+// last item the checkpoint is removed.
+func (q *Queue) Run(keys []string, fn func(key string) error) error {
+	pending := q.Pending(keys)
+	done := len(keys) - len(pending)
+	perItem := q.RequestsPerItem
+	if perItem <= 0 {
+		perItem = 1
+	}
+
+	for _, key := range pending {
+		if q.OnProgress != nil {
+			var eta time.Duration
+			if q.Pacer != nil {
+				eta = q.Pacer.ETA((len(keys) - done) * perItem)
+			}
+			q.OnProgress(done, len(keys), eta)
+		}
+		if q.Checkpoint != nil {
+			if err := q.Checkpoint.Begin(key); err != nil {
+				return fmt.Errorf("failed to save checkpoint: %w", err)
+			}
+		}
+		if err := fn(key); err != nil {
+			return err
+		}
+		if q.Checkpoint != nil {
+			if err := q.Checkpoint.MarkDone(key); err != nil {
+				return fmt.Errorf("failed to save checkpoint: %w", err)
+			}
+		}
+		done++
+	}
+
+	if q.OnProgress != nil {
+		q.OnProgress(done, len(keys), 0)
+	}
+	if q.Checkpoint != nil {
+		return q.Checkpoint.Remove()
+	}
+	return nil
+}
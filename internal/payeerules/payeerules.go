@@ -0,0 +1,121 @@
+// Package payeerules parses payee rename rules used to normalize the
+// payees of imported transactions.
+//
+// A rules file has one rule per line: the clean payee name, "=", and the
+// raw names that should become it, separated by "|". A pattern between
+// slashes is a regular expression, matched ignoring case; other patterns
+// match the whole name, ignoring case and emojis. Blank lines and lines
+// starting with # are ignored.
+//
+//	Amazon = AMZN Mktp | Amazon.com | /^AMZN\b/
+//	Spotify = /^spotify/
+package payeerules
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/langtind/ynabctl/internal/names"
+)
+
+// rule renames the payees matching any of its patterns to name
+type rule struct {
+	name    string
+	exact   []string
+	regexps []*regexp.Regexp
+}
+
+// Rules is an ordered list of rename rules; the first match wins
+type Rules struct {
+	rules []rule
+}
+
+// Load reads rules from a file.
+func Load(path string) (*Rules, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse reads rules from r.
+func Parse(r io.Reader) (*Rules, error) {
+	rs := &Rules{}
+	sc := bufio.NewScanner(r)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, patterns, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("line %d: want \"Payee = pattern | pattern\"", lineNo)
+		}
+		ru := rule{name: strings.TrimSpace(name)}
+		for _, p := range strings.Split(patterns, "|") {
+			p = strings.TrimSpace(p)
+			switch {
+			case p == "":
+				return nil, fmt.Errorf("line %d: empty pattern", lineNo)
+			case len(p) > 1 && strings.HasPrefix(p, "/") && strings.HasSuffix(p, "/"):
+				re, err := regexp.Compile("(?i)" + p[1:len(p)-1])
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", lineNo, err)
+				}
+				ru.regexps = append(ru.regexps, re)
+			default:
+				ru.exact = append(ru.exact, p)
+			}
+		}
+		rs.rules = append(rs.rules, ru)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// Len returns the number of rules
+func (rs *Rules) Len() int {
+	return len(rs.rules)
+}
+
+// Rename returns the clean name for payee, or false if no rule matches
+// or payee already has its clean name.
+func (rs *Rules) Rename(payee string) (string, bool) {
+	if payee == "" {
+		return "", false
+	}
+	for _, ru := range rs.rules {
+		if !ru.matches(payee) {
+			continue
+		}
+		if names.Equal(payee, ru.name) {
+			return "", false
+		}
+		return ru.name, true
+	}
+	return "", false
+}
+
+func (ru rule) matches(payee string) bool {
+	for _, e := range ru.exact {
+		if names.Equal(payee, e) {
+			return true
+		}
+	}
+	for _, re := range ru.regexps {
+		if re.MatchString(payee) {
+			return true
+		}
+	}
+	return false
+}
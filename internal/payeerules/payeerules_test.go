@@ -0,0 +1,49 @@
+package payeerules
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRename(t *testing.T) {
+	rules, err := Parse(strings.NewReader(`
+# shops
+Amazon = AMZN Mktp | Amazon.com | /^AMZN\b/
+Spotify = /^spotify/
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules.Len() != 2 {
+		t.Fatalf("got %d rules", rules.Len())
+	}
+
+	tests := []struct {
+		payee, want string
+		ok          bool
+	}{
+		{"amzn mktp", "Amazon", true},
+		{"🛒 Amazon.com", "Amazon", true},
+		{"AMZN *2K4LP0", "Amazon", true},
+		{"AMZNX", "", false},
+		{"SPOTIFY P1234", "Spotify", true},
+		{"spotify", "", false},
+		{"Amazon", "", false},
+		{"Netflix", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := rules.Rename(tt.payee)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Rename(%q) = %q, %v; want %q, %v", tt.payee, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, in := range []string{"Amazon", "= AMZN", "Amazon = AMZN |", "Amazon = /[/"} {
+		if _, err := Parse(strings.NewReader(in)); err == nil {
+			t.Errorf("Parse(%q) should fail", in)
+		}
+	}
+}
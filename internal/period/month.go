@@ -0,0 +1,81 @@
+package period
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MonthHelp describes the month arguments ParseMonth accepts, for flag
+// help texts
+const MonthHelp = "YYYY-MM, current, last, next, -1 or a month name"
+
+// monthNames maps English and Norwegian month names and their common
+// abbreviations to months
+var monthNames = map[string]time.Month{
+	"january": 1, "jan": 1, "januar": 1,
+	"february": 2, "feb": 2, "februar": 2,
+	"march": 3, "mar": 3, "mars": 3,
+	"april": 4, "apr": 4,
+	"may": 5, "mai": 5,
+	"june": 6, "jun": 6, "juni": 6,
+	"july": 7, "jul": 7, "juli": 7,
+	"august": 8, "aug": 8,
+	"september": 9, "sep": 9, "sept": 9,
+	"october": 10, "oct": 10, "oktober": 10, "okt": 10,
+	"november": 11, "nov": 11,
+	"december": 12, "dec": 12, "desember": 12, "des": 12,
+}
+
+var (
+	reRelativeMonth = regexp.MustCompile(`^[+-]\d{1,3}$`)
+	reMonthDate     = regexp.MustCompile(`^(\d{4})-(\d{1,2})(?:-\d{1,2})?$`)
+	reNamedMonth    = regexp.MustCompile(`^([a-z]+)\.?(?:[ -]?(\d{4}))?$`)
+)
+
+// ParseMonth returns the first day of the budget month s stands for,
+// relative to now, as YYYY-MM-DD. It accepts:
+//
+//	"", current, this    the month of now
+//	last, previous, next the month before or after it
+//	-1, +2               months before or after it
+//	2025-03, 2025-03-15  that month
+//	march, mar 2025      a month by name, in now's year unless given
+func ParseMonth(s string, now time.Time) (string, error) {
+	v := strings.ToLower(strings.TrimSpace(s))
+	this := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	format := func(t time.Time) string { return t.Format(dateFmt) }
+
+	switch v {
+	case "", "current", "this":
+		return format(this), nil
+	case "last", "previous", "prev":
+		return format(this.AddDate(0, -1, 0)), nil
+	case "next":
+		return format(this.AddDate(0, 1, 0)), nil
+	}
+	if reRelativeMonth.MatchString(v) {
+		n, _ := strconv.Atoi(v)
+		return format(this.AddDate(0, n, 0)), nil
+	}
+	if m := reMonthDate.FindStringSubmatch(v); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		if month < 1 || month > 12 {
+			return "", fmt.Errorf("invalid month %q", s)
+		}
+		return format(time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)), nil
+	}
+	if m := reNamedMonth.FindStringSubmatch(v); m != nil {
+		if month, ok := monthNames[m[1]]; ok {
+			year := now.Year()
+			if m[2] != "" {
+				year, _ = strconv.Atoi(m[2])
+			}
+			return format(time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)), nil
+		}
+	}
+	return "", fmt.Errorf("invalid month %q (want %s)", s, MonthHelp)
+}
@@ -98,7 +98,12 @@ func parseSpecific(kind, value string) (Range, error) {
 	case "month":
 		m := reMonth.FindStringSubmatch(value)
 		if m == nil {
-			return Range{}, fmt.Errorf("month format: YYYY-MM (e.g. 2026-03), got %q", value)
+			// shortcuts such as "last" or "march"
+			start, err := ParseMonth(value, time.Now())
+			if err != nil {
+				return Range{}, fmt.Errorf("month format: %s (e.g. 2026-03), got %q", MonthHelp, value)
+			}
+			m = []string{value, start[:4], start[5:7]}
 		}
 		year, _ := strconv.Atoi(m[1])
 		month, _ := strconv.Atoi(m[2])
@@ -1,6 +1,9 @@
 package period
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestParseSpecific(t *testing.T) {
 	cases := []struct {
@@ -32,3 +35,46 @@ func TestInvalid(t *testing.T) {
 		t.Error("expected error for bad month format")
 	}
 }
+
+func TestParseMonth(t *testing.T) {
+	now := time.Date(2026, 1, 20, 15, 0, 0, 0, time.Local)
+	cases := map[string]string{
+		"":           "2026-01-01",
+		"current":    "2026-01-01",
+		"Last":       "2025-12-01",
+		"next":       "2026-02-01",
+		"-1":         "2025-12-01",
+		"-13":        "2024-12-01",
+		"+2":         "2026-03-01",
+		"2025-03":    "2025-03-01",
+		"2025-3":     "2025-03-01",
+		"2025-03-15": "2025-03-01",
+		"march":      "2026-03-01",
+		"Mar":        "2026-03-01",
+		"mars":       "2026-03-01",
+		"okt 2024":   "2024-10-01",
+		"dec-2025":   "2025-12-01",
+		"sept.":      "2026-09-01",
+	}
+	for in, want := range cases {
+		got, err := ParseMonth(in, now)
+		if err != nil || got != want {
+			t.Errorf("ParseMonth(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"2025-13", "marsh", "1", "yesterday", "2025"} {
+		if _, err := ParseMonth(in, now); err == nil {
+			t.Errorf("ParseMonth(%q): expected error", in)
+		}
+	}
+}
+
+func TestComputeMonthShortcut(t *testing.T) {
+	got, err := Compute("month", "2026-03")
+	if err != nil || got.StartDate != "2026-03-01" {
+		t.Fatalf("Compute(month, 2026-03) = %+v, %v", got, err)
+	}
+	if _, err := Compute("month", "last"); err != nil {
+		t.Errorf("Compute(month, last): %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+// Package policy checks a budget against rules kept in a YAML file, such
+// as "nothing uncategorized for more than a week", so budget discipline
+// can be enforced from cron or CI.
+package policy
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"gopkg.in/yaml.v3"
+)
+
+// Rule types
+const (
+	UncategorizedAge  = "uncategorized_age"
+	UnapprovedAge     = "unapproved_age"
+	NegativeBalance   = "negative_balance"
+	CreditCardsFunded = "credit_cards_funded"
+)
+
+// Severities; only errors fail a check
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Rule is one invariant of a policy file
+type Rule struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+	// Severity is error (the default) or warning
+	Severity string `yaml:"severity,omitempty"`
+	// MaxDays is the age allowed by the *_age rules
+	MaxDays int `yaml:"max_days,omitempty"`
+	// Exclude lists category names (negative_balance) or account names
+	// (credit_cards_funded) the rule does not apply to
+	Exclude []string `yaml:"exclude,omitempty"`
+}
+
+// Policy is the content of a policy file
+type Policy struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Load reads and validates a policy file
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Policy
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(p.Rules) == 0 {
+		return nil, fmt.Errorf("%s: no rules", path)
+	}
+	for i := range p.Rules {
+		r := &p.Rules[i]
+		if r.Name == "" {
+			r.Name = r.Type
+		}
+		switch r.Type {
+		case UncategorizedAge, UnapprovedAge:
+			if r.MaxDays < 0 {
+				return nil, fmt.Errorf("%s: rule %s: max_days must not be negative", path, r.Name)
+			}
+		case NegativeBalance, CreditCardsFunded:
+		default:
+			return nil, fmt.Errorf("%s: rule %s: unknown type %q (want %s, %s, %s or %s)", path, r.Name, r.Type,
+				UncategorizedAge, UnapprovedAge, NegativeBalance, CreditCardsFunded)
+		}
+		switch r.Severity {
+		case "":
+			r.Severity = SeverityError
+		case SeverityError, SeverityWarning:
+		default:
+			return nil, fmt.Errorf("%s: rule %s: severity must be error or warning", path, r.Name)
+		}
+	}
+	return &p, nil
+}
+
+// Needs reports whether any rule has type t, so only the data the
+// policy uses is fetched
+func (p *Policy) Needs(t string) bool {
+	for _, r := range p.Rules {
+		if r.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Budget is the data rules are checked against. Categories are those of
+// the current month.
+type Budget struct {
+	Accounts     []ynab.Account
+	Categories   []ynab.Category
+	Transactions []ynab.Transaction
+	Today        time.Time
+}
+
+// Violation is a rule broken by one transaction, category or account
+type Violation struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	ID       string `json:"id,omitempty"`
+	Message  string `json:"message"`
+}
+
+// Check evaluates every rule and returns the violations, errors first
+func (p *Policy) Check(b Budget) []Violation {
+	violations := []Violation{}
+	for _, r := range p.Rules {
+		add := func(id, format string, args ...interface{}) {
+			violations = append(violations, Violation{Rule: r.Name, Severity: r.Severity, ID: id, Message: fmt.Sprintf(format, args...)})
+		}
+		switch r.Type {
+		case UncategorizedAge, UnapprovedAge:
+			checkAge(r, b, add)
+		case NegativeBalance:
+			for _, c := range b.Categories {
+				if c.Deleted || c.Hidden || c.Balance >= 0 || excluded(r, c.Name) {
+					continue
+				}
+				add(c.ID, "%s: %s is overspent by %.2f", c.CategoryGroupName, c.Name, ynab.MilliunitsToAmount(-c.Balance))
+			}
+		case CreditCardsFunded:
+			payments := map[string]int64{}
+			for _, c := range b.Categories {
+				if c.CategoryGroupName == "Credit Card Payments" && !c.Deleted {
+					payments[c.Name] = c.Balance
+				}
+			}
+			for _, a := range b.Accounts {
+				if a.Type != "creditCard" || a.Closed || a.Deleted || a.Balance >= 0 || excluded(r, a.Name) {
+					continue
+				}
+				if available := payments[a.Name]; available < -a.Balance {
+					add(a.ID, "%s owes %.2f but only %.2f is set aside for the payment", a.Name,
+						ynab.MilliunitsToAmount(-a.Balance), ynab.MilliunitsToAmount(available))
+				}
+			}
+		}
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Severity == SeverityError && violations[j].Severity != SeverityError
+	})
+	return violations
+}
+
+func checkAge(r Rule, b Budget, add func(id, format string, args ...interface{})) {
+	onBudget := map[string]bool{}
+	for _, a := range b.Accounts {
+		onBudget[a.ID] = a.OnBudget
+	}
+	cutoff := b.Today.AddDate(0, 0, -r.MaxDays).Format("2006-01-02")
+	for _, t := range b.Transactions {
+		if t.Deleted || t.Date >= cutoff {
+			continue
+		}
+		switch r.Type {
+		case UncategorizedAge:
+			// transfers between budget accounts and tracking accounts
+			// have no category by design
+			if !onBudget[t.AccountID] || t.TransferAccountID != "" || len(t.Subtransactions) > 0 ||
+				(t.CategoryID != "" && t.CategoryName != "Uncategorized") {
+				continue
+			}
+			add(t.ID, "%s %s %.2f has been uncategorized for more than %d days", t.Date, t.PayeeName, ynab.MilliunitsToAmount(t.Amount), r.MaxDays)
+		case UnapprovedAge:
+			if t.Approved {
+				continue
+			}
+			add(t.ID, "%s %s %.2f has been unapproved for more than %d days", t.Date, t.PayeeName, ynab.MilliunitsToAmount(t.Amount), r.MaxDays)
+		}
+	}
+}
+
+func excluded(r Rule, name string) bool {
+	for _, e := range r.Exclude {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}
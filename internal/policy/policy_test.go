@@ -0,0 +1,88 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "policy.yaml")
+	os.WriteFile(path, []byte(`rules:
+  - type: uncategorized_age
+    max_days: 7
+  - name: no overspending
+    type: negative_balance
+    severity: warning
+`), 0o644)
+
+	p, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Rules[0].Name != UncategorizedAge || p.Rules[0].Severity != SeverityError {
+		t.Errorf("defaults not applied: %+v", p.Rules[0])
+	}
+	if !p.Needs(NegativeBalance) || p.Needs(CreditCardsFunded) {
+		t.Error("Needs is wrong")
+	}
+
+	os.WriteFile(path, []byte("rules:\n  - type: no_fun\n"), 0o644)
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "unknown type") {
+		t.Errorf("unknown type: %v", err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	p := &Policy{Rules: []Rule{
+		{Name: "categorize", Type: UncategorizedAge, MaxDays: 7, Severity: SeverityError},
+		{Name: "overspending", Type: NegativeBalance, Severity: SeverityWarning, Exclude: []string{"Fun"}},
+		{Name: "cards", Type: CreditCardsFunded, Severity: SeverityError},
+	}}
+	b := Budget{
+		Today: time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC),
+		Accounts: []ynab.Account{
+			{ID: "chk", Name: "Checking", Type: "checking", OnBudget: true},
+			{ID: "trk", Name: "House", Type: "otherAsset"},
+			{ID: "visa", Name: "Visa", Type: "creditCard", OnBudget: true, Balance: -500000},
+			{ID: "amex", Name: "Amex", Type: "creditCard", OnBudget: true, Balance: -100000},
+		},
+		Categories: []ynab.Category{
+			{ID: "c1", CategoryGroupName: "Food", Name: "Dining", Balance: -20000},
+			{ID: "c2", CategoryGroupName: "Food", Name: "Fun", Balance: -5000},
+			{ID: "c3", CategoryGroupName: "Credit Card Payments", Name: "Visa", Balance: 300000},
+			{ID: "c4", CategoryGroupName: "Credit Card Payments", Name: "Amex", Balance: 100000},
+		},
+		Transactions: []ynab.Transaction{
+			{ID: "t1", AccountID: "chk", Date: "2025-03-01", Amount: -1000},
+			{ID: "t2", AccountID: "chk", Date: "2025-03-18", Amount: -1000},
+			{ID: "t3", AccountID: "trk", Date: "2025-03-01", Amount: 5000},
+			{ID: "t4", AccountID: "chk", Date: "2025-03-01", Amount: -1000, TransferAccountID: "visa"},
+			{ID: "t5", AccountID: "chk", Date: "2025-03-01", Amount: -1000, CategoryID: "x", CategoryName: "Uncategorized"},
+		},
+	}
+
+	got := map[string]string{}
+	for _, v := range p.Check(b) {
+		got[v.ID] = v.Severity
+	}
+	want := map[string]string{"t1": SeverityError, "t5": SeverityError, "visa": SeverityError, "c1": SeverityWarning}
+	if len(got) != len(want) {
+		t.Fatalf("violations = %v, want %v", got, want)
+	}
+	for id, sev := range want {
+		if got[id] != sev {
+			t.Errorf("%s: severity %q, want %q", id, got[id], sev)
+		}
+	}
+
+	vs := p.Check(b)
+	if vs[len(vs)-1].Severity != SeverityWarning {
+		t.Errorf("errors should come first: %+v", vs)
+	}
+}
@@ -0,0 +1,118 @@
+// Package progress draws spinners and progress bars on stderr for
+// long-running commands. Nothing is drawn unless progress is enabled,
+// which callers do only when stderr is a terminal.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	enabled bool
+	out     io.Writer = os.Stderr
+)
+
+// SetEnabled turns progress output on or off for the whole process.
+func SetEnabled(on bool) {
+	enabled = on
+}
+
+// Enabled reports whether progress output is drawn.
+func Enabled() bool {
+	return enabled
+}
+
+const clearLine = "\r\033[K"
+
+// Spinner shows an animated message until stopped. It is meant for
+// single calls whose duration is unknown.
+type Spinner struct {
+	msg  string
+	stop chan struct{}
+	done sync.WaitGroup
+}
+
+// Start begins a spinner with msg. The returned spinner is a no-op when
+// progress is disabled.
+func Start(msg string) *Spinner {
+	s := &Spinner{msg: msg}
+	if !enabled {
+		return s
+	}
+	s.stop = make(chan struct{})
+	s.done.Add(1)
+	go func() {
+		defer s.done.Done()
+		frames := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			fmt.Fprintf(out, "%s%s %s", clearLine, frames[i%len(frames)], s.msg)
+			select {
+			case <-s.stop:
+				fmt.Fprint(out, clearLine)
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return s
+}
+
+// Stop removes the spinner from the terminal.
+func (s *Spinner) Stop() {
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	s.done.Wait()
+	s.stop = nil
+}
+
+// Bar shows "label [=====     ] n/total" for loops with a known count.
+type Bar struct {
+	mu      sync.Mutex
+	label   string
+	total   int
+	current int
+}
+
+// NewBar creates a progress bar for total steps and draws it.
+func NewBar(label string, total int) *Bar {
+	b := &Bar{label: label, total: total}
+	b.draw()
+	return b
+}
+
+// Add advances the bar by n steps.
+func (b *Bar) Add(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.current += n
+	b.draw()
+}
+
+// Done removes the bar from the terminal.
+func (b *Bar) Done() {
+	if enabled {
+		fmt.Fprint(out, clearLine)
+	}
+}
+
+func (b *Bar) draw() {
+	if !enabled || b.total <= 0 {
+		return
+	}
+	const width = 30
+	filled := b.current * width / b.total
+	if filled > width {
+		filled = width
+	}
+	fmt.Fprintf(out, "%s%s [%s%s] %d/%d", clearLine, b.label,
+		strings.Repeat("=", filled), strings.Repeat(" ", width-filled), b.current, b.total)
+}
@@ -0,0 +1,31 @@
+package progress
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestBar(t *testing.T) {
+	var buf bytes.Buffer
+	out = &buf
+	defer func() { out = os.Stderr; enabled = false }()
+
+	SetEnabled(false)
+	b := NewBar("fetching", 4)
+	b.Add(1)
+	b.Done()
+	Start("waiting").Stop()
+	if buf.Len() != 0 {
+		t.Errorf("disabled progress wrote %q", buf.String())
+	}
+
+	SetEnabled(true)
+	b = NewBar("fetching", 4)
+	b.Add(2)
+	if !strings.Contains(buf.String(), "fetching [===============               ] 2/4") {
+		t.Errorf("unexpected bar output %q", buf.String())
+	}
+	b.Done()
+}
@@ -0,0 +1,101 @@
+// Package refresh runs the polling loop of long-running commands. The
+// interval adapts: it doubles while nothing changes, up to a maximum,
+// falls back to the minimum as soon as something does, and stretches so
+// polling never takes the API rate limit a command shares with others.
+package refresh
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/pacing"
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// Refresher decides when to poll next
+type Refresher struct {
+	// Min and Max bound the interval between polls
+	Min, Max time.Duration
+	// RateLimit reports the API usage of the current window, as
+	// ynab.Client.RateLimit does. It may be nil.
+	RateLimit func() (used, limit int, ok bool)
+	// OnError is called with every failed poll. It may be nil.
+	OnError func(error)
+
+	interval time.Duration
+}
+
+// New returns a refresher polling every min to max
+func New(min, max time.Duration) *Refresher {
+	if max < min {
+		max = min
+	}
+	return &Refresher{Min: min, Max: max, interval: min}
+}
+
+// Next returns how long to wait after a poll that found changes or not,
+// or failed with err. Failures back off like polls without changes; the
+// API's rate limit error waits the maximum.
+func (r *Refresher) Next(changed bool, err error) time.Duration {
+	switch {
+	case errors.Is(err, ynab.ErrRateLimited):
+		r.interval = r.Max
+	case changed && err == nil:
+		r.interval = r.Min
+	default:
+		r.interval *= 2
+		if r.interval > r.Max {
+			r.interval = r.Max
+		}
+		if r.interval < r.Min {
+			r.interval = r.Min
+		}
+	}
+	return r.throttle(r.interval)
+}
+
+// throttle stretches d once more than half of the rate limit is used,
+// spreading the rest over the window and keeping a tenth of the limit
+// for other commands
+func (r *Refresher) throttle(d time.Duration) time.Duration {
+	if r.RateLimit == nil {
+		return d
+	}
+	used, limit, ok := r.RateLimit()
+	if !ok || limit <= 0 || used*2 <= limit {
+		return d
+	}
+	left := limit - used - limit/10
+	if left < 1 {
+		left = 1
+	}
+	if floor := pacing.DefaultWindow / time.Duration(left); d < floor {
+		return floor
+	}
+	return d
+}
+
+// Run waits, calls poll, and repeats until ctx is done. poll reports
+// whether it found anything new; its errors go to OnError and do not
+// stop the loop.
+func (r *Refresher) Run(ctx context.Context, poll func() (changed bool, err error)) error {
+	if r.interval == 0 {
+		r.interval = r.Min
+	}
+	wait := r.throttle(r.interval)
+	for {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+		changed, err := poll()
+		if err != nil && r.OnError != nil {
+			r.OnError(err)
+		}
+		wait = r.Next(changed, err)
+	}
+}
@@ -0,0 +1,69 @@
+package refresh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestNextBacksOffAndResets(t *testing.T) {
+	r := New(time.Minute, 8*time.Minute)
+	for i, want := range []time.Duration{2, 4, 8, 8} {
+		if got := r.Next(false, nil); got != want*time.Minute {
+			t.Errorf("idle poll %d: next = %v, want %vm", i+1, got, int(want))
+		}
+	}
+	if got := r.Next(true, nil); got != time.Minute {
+		t.Errorf("after a change: next = %v, want 1m", got)
+	}
+	if got := r.Next(false, errors.New("timeout")); got != 2*time.Minute {
+		t.Errorf("after an error: next = %v, want 2m", got)
+	}
+	if got := r.Next(false, fmt.Errorf("get: %w", ynab.ErrRateLimited)); got != 8*time.Minute {
+		t.Errorf("after rate limiting: next = %v, want 8m", got)
+	}
+}
+
+func TestNextHonorsRateLimit(t *testing.T) {
+	r := New(time.Minute, time.Hour)
+	used := 50
+	r.RateLimit = func() (int, int, bool) { return used, 200, true }
+	if got := r.Next(true, nil); got != time.Minute {
+		t.Errorf("at 50/200: next = %v, want 1m", got)
+	}
+	// 170 used leaves 10 above the reserve of 20: one poll per 6 minutes
+	used = 170
+	if got := r.Next(true, nil); got != 6*time.Minute {
+		t.Errorf("at 170/200: next = %v, want 6m", got)
+	}
+	used = 199
+	if got := r.Next(true, nil); got != time.Hour {
+		t.Errorf("at 199/200: next = %v, want 1h", got)
+	}
+}
+
+func TestRunStopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var errs []error
+	r := New(time.Millisecond, 2*time.Millisecond)
+	r.OnError = func(err error) { errs = append(errs, err) }
+	polls := 0
+	err := r.Run(ctx, func() (bool, error) {
+		polls++
+		if polls == 2 {
+			return false, errors.New("boom")
+		}
+		if polls == 3 {
+			cancel()
+		}
+		return false, nil
+	})
+	if err != nil || polls != 3 || len(errs) != 1 {
+		t.Errorf("Run = %v after %d polls and %d errors, want nil after 3 and 1", err, polls, len(errs))
+	}
+}
@@ -0,0 +1,78 @@
+package report
+
+import "github.com/langtind/ynabctl/pkg/ynab"
+
+// accountGroups maps YNAB account types to summary groups. Types not
+// listed (otherAsset, otherLiability, anything new) count as tracking.
+var accountGroups = map[string]string{
+	"checking":     "cash",
+	"savings":      "cash",
+	"cash":         "cash",
+	"creditCard":   "credit",
+	"lineOfCredit": "credit",
+	"mortgage":     "loans",
+	"autoLoan":     "loans",
+	"studentLoan":  "loans",
+	"personalLoan": "loans",
+	"medicalDebt":  "loans",
+	"otherDebt":    "loans",
+}
+
+// accountGroupOrder is the order groups are listed in
+var accountGroupOrder = []string{"cash", "credit", "loans", "tracking"}
+
+// AccountLine is one account in an AccountsSummary
+type AccountLine struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Balance int64  `json:"balance" amount:"milliunits"`
+	Closed  bool   `json:"closed,omitempty"`
+}
+
+// AccountGroup is the accounts of one group and their total
+type AccountGroup struct {
+	Group    string        `json:"group"`
+	Accounts []AccountLine `json:"accounts"`
+	Subtotal int64         `json:"subtotal" amount:"milliunits"`
+}
+
+// AccountsSummary groups accounts into cash, credit, loans and tracking.
+// Net is the sum of all balances (debts are negative).
+type AccountsSummary struct {
+	Groups []AccountGroup `json:"groups"`
+	Net    int64          `json:"net" amount:"milliunits"`
+}
+
+// SummarizeAccounts groups accounts by type. Deleted accounts are always
+// left out, closed ones unless includeClosed is set. Empty groups are
+// omitted.
+func SummarizeAccounts(accounts []ynab.Account, includeClosed bool) *AccountsSummary {
+	byGroup := map[string]*AccountGroup{}
+	s := &AccountsSummary{Groups: []AccountGroup{}}
+
+	for _, a := range accounts {
+		if a.Deleted || (a.Closed && !includeClosed) {
+			continue
+		}
+		group, ok := accountGroups[a.Type]
+		if !ok {
+			group = "tracking"
+		}
+		g := byGroup[group]
+		if g == nil {
+			g = &AccountGroup{Group: group}
+			byGroup[group] = g
+		}
+		g.Accounts = append(g.Accounts, AccountLine{ID: a.ID, Name: a.Name, Type: a.Type, Balance: a.Balance, Closed: a.Closed})
+		g.Subtotal += a.Balance
+		s.Net += a.Balance
+	}
+
+	for _, name := range accountGroupOrder {
+		if g := byGroup[name]; g != nil {
+			s.Groups = append(s.Groups, *g)
+		}
+	}
+	return s
+}
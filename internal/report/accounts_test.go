@@ -0,0 +1,47 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestSummarizeAccounts(t *testing.T) {
+	accounts := []ynab.Account{
+		{ID: "1", Name: "Checking", Type: "checking", Balance: 1500000},
+		{ID: "2", Name: "House", Type: "otherAsset", Balance: 300000000},
+		{ID: "3", Name: "Visa", Type: "creditCard", Balance: -250000},
+		{ID: "4", Name: "Mortgage", Type: "mortgage", Balance: -200000000},
+		{ID: "5", Name: "Wallet", Type: "cash", Balance: 50000},
+		{ID: "6", Name: "Old", Type: "savings", Balance: 0, Closed: true},
+		{ID: "7", Name: "Gone", Type: "checking", Balance: 999, Deleted: true},
+	}
+
+	s := SummarizeAccounts(accounts, false)
+	want := []struct {
+		group    string
+		n        int
+		subtotal int64
+	}{
+		{"cash", 2, 1550000},
+		{"credit", 1, -250000},
+		{"loans", 1, -200000000},
+		{"tracking", 1, 300000000},
+	}
+	if len(s.Groups) != len(want) {
+		t.Fatalf("groups = %+v", s.Groups)
+	}
+	for i, w := range want {
+		g := s.Groups[i]
+		if g.Group != w.group || len(g.Accounts) != w.n || g.Subtotal != w.subtotal {
+			t.Errorf("group %d = %s/%d/%d, want %+v", i, g.Group, len(g.Accounts), g.Subtotal, w)
+		}
+	}
+	if s.Net != 101300000 {
+		t.Errorf("net = %d", s.Net)
+	}
+
+	if s := SummarizeAccounts(accounts, true); len(s.Groups[0].Accounts) != 3 {
+		t.Errorf("closed account not included: %+v", s.Groups[0])
+	}
+}
@@ -0,0 +1,45 @@
+package report
+
+import (
+	"sort"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// Allowance is where a family member's allowance stands: the balance of
+// their category, what it is credited each week, and what was spent
+// from it since a date. Transactions are those spends, newest first.
+type Allowance struct {
+	Member       string             `json:"member"`
+	CategoryID   string             `json:"category_id"`
+	CategoryName string             `json:"category_name"`
+	Balance      int64              `json:"balance" amount:"milliunits"`
+	Weekly       int64              `json:"weekly" amount:"milliunits"`
+	Since        string             `json:"since"`
+	Spent        int64              `json:"spent" amount:"milliunits"`
+	Transactions []ynab.Transaction `json:"transactions"`
+}
+
+// AllowanceStatus sums what was spent from category c on or after since.
+// txns are the category's transactions; inflows such as refunds count
+// against the spending.
+func AllowanceStatus(member string, c ynab.Category, weekly int64, txns []ynab.Transaction, since string) *Allowance {
+	a := &Allowance{
+		Member:       member,
+		CategoryID:   c.ID,
+		CategoryName: c.Name,
+		Balance:      c.Balance,
+		Weekly:       weekly,
+		Since:        since,
+		Transactions: []ynab.Transaction{},
+	}
+	for _, t := range txns {
+		if t.Deleted || t.Date < since {
+			continue
+		}
+		a.Spent -= t.Amount
+		a.Transactions = append(a.Transactions, t)
+	}
+	sort.SliceStable(a.Transactions, func(i, j int) bool { return a.Transactions[i].Date > a.Transactions[j].Date })
+	return a
+}
@@ -0,0 +1,29 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestAllowanceStatus(t *testing.T) {
+	c := ynab.Category{ID: "kid1", Name: "Emma's allowance", Balance: 85000}
+	txns := []ynab.Transaction{
+		{ID: "t1", Date: "2026-09-01", Amount: -40000},
+		{ID: "t2", Date: "2026-10-02", Amount: -25000},
+		{ID: "t3", Date: "2026-10-09", Amount: -15000},
+		{ID: "t4", Date: "2026-10-10", Amount: 5000},
+		{ID: "t5", Date: "2026-10-11", Amount: -99000, Deleted: true},
+	}
+
+	a := AllowanceStatus("Emma", c, 50000, txns, "2026-09-19")
+	if a.Spent != 35000 {
+		t.Errorf("spent = %d, want 35000", a.Spent)
+	}
+	if a.Balance != 85000 || a.Weekly != 50000 || a.CategoryName != c.Name {
+		t.Errorf("allowance = %+v", a)
+	}
+	if len(a.Transactions) != 3 || a.Transactions[0].ID != "t4" || a.Transactions[2].ID != "t2" {
+		t.Errorf("transactions = %+v, want t4, t3, t2", a.Transactions)
+	}
+}
@@ -0,0 +1,152 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// maxAmortizationMonths bounds a projection; a loan not paid off in 100
+// years is reported as never paid off
+const maxAmortizationMonths = 1200
+
+// AmortizationRow is one projected monthly payment. Payment is the
+// principal and interest paid, without escrow. Amounts are milliunits.
+type AmortizationRow struct {
+	Month     string `json:"month"`
+	Payment   int64  `json:"payment" amount:"milliunits"`
+	Interest  int64  `json:"interest" amount:"milliunits"`
+	Principal int64  `json:"principal" amount:"milliunits"`
+	Balance   int64  `json:"balance" amount:"milliunits"`
+}
+
+// AmortizationPlan is the projected payoff of a loan at one payment
+type AmortizationPlan struct {
+	Months        int               `json:"months"`
+	PayoffMonth   string            `json:"payoff_month"`
+	TotalInterest int64             `json:"total_interest" amount:"milliunits"`
+	TotalPaid     int64             `json:"total_paid" amount:"milliunits"`
+	Schedule      []AmortizationRow `json:"schedule,omitempty"`
+}
+
+// Amortization projects the payoff of a loan account from its interest
+// rate, minimum payment and escrow as entered in YNAB, with and without
+// an extra monthly payment. Balance is the principal owed, as a
+// positive amount; InterestRate is a yearly percentage.
+type Amortization struct {
+	AccountID     string            `json:"account_id"`
+	AccountName   string            `json:"account_name"`
+	Balance       int64             `json:"balance" amount:"milliunits"`
+	InterestRate  float64           `json:"interest_rate"`
+	Payment       int64             `json:"payment" amount:"milliunits"`
+	Escrow        int64             `json:"escrow" amount:"milliunits"`
+	Extra         int64             `json:"extra,omitempty" amount:"milliunits"`
+	Baseline      AmortizationPlan  `json:"baseline"`
+	WithExtra     *AmortizationPlan `json:"with_extra,omitempty"`
+	MonthsSaved   int               `json:"months_saved,omitempty"`
+	InterestSaved int64             `json:"interest_saved,omitempty" amount:"milliunits"`
+}
+
+// Amortize projects the payoff of a loan account, paying its minimum
+// payment each month from the month after now, plus extra if it is
+// positive. Interest rates, minimum payments and escrow are taken from
+// the account's values in effect in each projected month, so scheduled
+// rate changes are followed. Escrow is paid on top of the minimum
+// payment and does not reduce the principal.
+func Amortize(account ynab.Account, extra int64, now time.Time) (*Amortization, error) {
+	if len(account.DebtInterestRates) == 0 && len(account.DebtMinimumPayments) == 0 {
+		return nil, fmt.Errorf("account %q has no interest rate or minimum payment; set them up as a loan in YNAB", account.Name)
+	}
+	principal := -account.Balance
+	if principal <= 0 {
+		return nil, fmt.Errorf("account %q has nothing left to pay", account.Name)
+	}
+
+	today := now.Format("2006-01-02")
+	a := &Amortization{
+		AccountID:    account.ID,
+		AccountName:  account.Name,
+		Balance:      principal,
+		InterestRate: float64(periodicValue(account.DebtInterestRates, today)) / 1000,
+		Payment:      periodicValue(account.DebtMinimumPayments, today),
+		Escrow:       periodicValue(account.DebtEscrowAmounts, today),
+	}
+
+	baseline, err := amortize(account, principal, 0, now)
+	if err != nil {
+		return nil, err
+	}
+	a.Baseline = *baseline
+	if extra > 0 {
+		a.Extra = extra
+		if a.WithExtra, err = amortize(account, principal, extra, now); err != nil {
+			return nil, err
+		}
+		a.MonthsSaved = a.Baseline.Months - a.WithExtra.Months
+		a.InterestSaved = a.Baseline.TotalInterest - a.WithExtra.TotalInterest
+	}
+	return a, nil
+}
+
+// amortize projects the monthly payments until principal is paid off
+func amortize(account ynab.Account, principal, extra int64, now time.Time) (*AmortizationPlan, error) {
+	plan := &AmortizationPlan{}
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for principal > 0 {
+		if plan.Months == maxAmortizationMonths {
+			return nil, fmt.Errorf("account %q is not paid off within %d years at this payment", account.Name, maxAmortizationMonths/12)
+		}
+		month = month.AddDate(0, 1, 0)
+		date := month.Format("2006-01-02")
+
+		rate := float64(periodicValue(account.DebtInterestRates, date)) / 1000 / 100 / 12
+		interest := int64(math.Round(float64(principal) * rate))
+		payment := periodicValue(account.DebtMinimumPayments, date) + extra
+		if payment <= interest {
+			return nil, fmt.Errorf("the payment of %.2f in %s does not cover the interest of %.2f; account %q is never paid off",
+				ynab.MilliunitsToAmount(payment), month.Format("2006-01"), ynab.MilliunitsToAmount(interest), account.Name)
+		}
+		if payment > principal+interest {
+			payment = principal + interest
+		}
+		principal -= payment - interest
+
+		plan.Months++
+		plan.TotalInterest += interest
+		plan.TotalPaid += payment + periodicValue(account.DebtEscrowAmounts, date)
+		plan.Schedule = append(plan.Schedule, AmortizationRow{
+			Month:     month.Format("2006-01"),
+			Payment:   payment,
+			Interest:  interest,
+			Principal: payment - interest,
+			Balance:   principal,
+		})
+	}
+	plan.PayoffMonth = month.Format("2006-01")
+	return plan, nil
+}
+
+// periodicValue returns the value of a YNAB loan field (a map from the
+// date a value takes effect to the value) in effect on date. Before the
+// first date the first value applies.
+func periodicValue(values map[string]int64, date string) int64 {
+	if len(values) == 0 {
+		return 0
+	}
+	dates := make([]string, 0, len(values))
+	for d := range values {
+		dates = append(dates, d)
+	}
+	sort.Strings(dates)
+	value := values[dates[0]]
+	for _, d := range dates {
+		if d > date {
+			break
+		}
+		value = values[d]
+	}
+	return value
+}
@@ -0,0 +1,69 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestAmortize(t *testing.T) {
+	loan := ynab.Account{
+		ID:                  "m",
+		Name:                "Mortgage",
+		Type:                "mortgage",
+		Balance:             -100000000,
+		DebtInterestRates:   map[string]int64{"2020-01-01": 6000},
+		DebtMinimumPayments: map[string]int64{"2020-01-01": 1110210},
+		DebtEscrowAmounts:   map[string]int64{"2020-01-01": 200000},
+	}
+	now := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	a, err := Amortize(loan, 500000, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.InterestRate != 6 || a.Payment != 1110210 || a.Escrow != 200000 {
+		t.Errorf("loan details = %.2f%% %d %d", a.InterestRate, a.Payment, a.Escrow)
+	}
+	b := a.Baseline
+	if b.Months != 120 || b.PayoffMonth != "2036-10" {
+		t.Errorf("baseline paid off in %d months (%s), want 120 (2036-10)", b.Months, b.PayoffMonth)
+	}
+	if b.Schedule[0].Month != "2026-11" || b.Schedule[0].Interest != 500000 {
+		t.Errorf("first payment = %+v", b.Schedule[0])
+	}
+	if last := b.Schedule[len(b.Schedule)-1]; last.Balance != 0 {
+		t.Errorf("last balance = %d", last.Balance)
+	}
+	if b.TotalPaid != 100000000+b.TotalInterest+120*200000 {
+		t.Errorf("total paid %d does not add up", b.TotalPaid)
+	}
+	if a.WithExtra == nil || a.MonthsSaved <= 0 || a.InterestSaved <= 0 {
+		t.Errorf("extra payments saved nothing: %+v", a.WithExtra)
+	}
+	if a.MonthsSaved != b.Months-a.WithExtra.Months {
+		t.Errorf("months saved = %d", a.MonthsSaved)
+	}
+
+	loan.DebtMinimumPayments = map[string]int64{"2020-01-01": 400000}
+	if _, err := Amortize(loan, 0, now); err == nil {
+		t.Error("expected an error when the payment does not cover the interest")
+	}
+	if _, err := Amortize(ynab.Account{Name: "Checking", Balance: -1000}, 0, now); err == nil {
+		t.Error("expected an error for an account without loan details")
+	}
+}
+
+func TestPeriodicValue(t *testing.T) {
+	rates := map[string]int64{"2024-01-01": 4000, "2026-01-01": 5000, "2025-01-01": 4500}
+	cases := map[string]int64{"2023-06-01": 4000, "2024-01-01": 4000, "2025-06-01": 4500, "2030-01-01": 5000}
+	for date, want := range cases {
+		if got := periodicValue(rates, date); got != want {
+			t.Errorf("periodicValue(%s) = %d, want %d", date, got, want)
+		}
+	}
+	if got := periodicValue(nil, "2025-01-01"); got != 0 {
+		t.Errorf("empty map = %d", got)
+	}
+}
@@ -0,0 +1,87 @@
+package report
+
+import (
+	"sort"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// Sources of age-of-money observations, in order of preference when
+// several fall on the same date
+const (
+	AgeFromRecord   = "recorded"
+	AgeFromSnapshot = "snapshot"
+	AgeFromMonth    = "month"
+)
+
+// AgePoint is the age of money observed on a date
+type AgePoint struct {
+	Date       string `json:"date"`
+	AgeOfMoney int    `json:"age_of_money"`
+	Source     string `json:"source"`
+}
+
+// AgeOfMoneyTrend is the history of the age of money, oldest first
+type AgeOfMoneyTrend struct {
+	Points  []AgePoint `json:"points"`
+	Current int        `json:"current"`
+	Change  int        `json:"change_days"`
+	Min     int        `json:"min"`
+	Max     int        `json:"max"`
+}
+
+// MonthAgePoints turns the age of money of past months into points dated
+// on each month's last day. The current and future months are left out,
+// as their value is not final.
+func MonthAgePoints(months []ynab.Month, currentMonth string) []AgePoint {
+	var points []AgePoint
+	for _, m := range months {
+		if m.Deleted || m.AgeOfMoney == 0 || m.Month >= currentMonth {
+			continue
+		}
+		start, err := time.Parse("2006-01-02", m.Month)
+		if err != nil {
+			continue
+		}
+		points = append(points, AgePoint{
+			Date:       start.AddDate(0, 1, -1).Format("2006-01-02"),
+			AgeOfMoney: m.AgeOfMoney,
+			Source:     AgeFromMonth,
+		})
+	}
+	return points
+}
+
+// TrendAgeOfMoney merges observations into a trend. When several fall on
+// the same date, recorded ones win over snapshots, and snapshots over
+// month data.
+func TrendAgeOfMoney(points []AgePoint) *AgeOfMoneyTrend {
+	rank := map[string]int{AgeFromRecord: 0, AgeFromSnapshot: 1, AgeFromMonth: 2}
+	byDate := map[string]AgePoint{}
+	for _, p := range points {
+		if prev, ok := byDate[p.Date]; !ok || rank[p.Source] < rank[prev.Source] {
+			byDate[p.Date] = p
+		}
+	}
+
+	t := &AgeOfMoneyTrend{Points: []AgePoint{}}
+	for _, p := range byDate {
+		t.Points = append(t.Points, p)
+	}
+	sort.Slice(t.Points, func(i, j int) bool { return t.Points[i].Date < t.Points[j].Date })
+
+	for i, p := range t.Points {
+		if i == 0 || p.AgeOfMoney < t.Min {
+			t.Min = p.AgeOfMoney
+		}
+		if p.AgeOfMoney > t.Max {
+			t.Max = p.AgeOfMoney
+		}
+	}
+	if n := len(t.Points); n > 0 {
+		t.Current = t.Points[n-1].AgeOfMoney
+		t.Change = t.Current - t.Points[0].AgeOfMoney
+	}
+	return t
+}
@@ -0,0 +1,35 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestAgeOfMoneyTrend(t *testing.T) {
+	months := []ynab.Month{
+		{Month: "2025-03-01", AgeOfMoney: 40}, // current, not final
+		{Month: "2025-02-01", AgeOfMoney: 35},
+		{Month: "2025-01-01", AgeOfMoney: 30},
+		{Month: "2024-12-01", AgeOfMoney: 0}, // no data
+	}
+	points := MonthAgePoints(months, "2025-03-01")
+	if len(points) != 2 || points[1].Date != "2025-01-31" {
+		t.Fatalf("month points = %+v", points)
+	}
+
+	points = append(points,
+		AgePoint{Date: "2025-02-28", AgeOfMoney: 36, Source: AgeFromSnapshot},
+		AgePoint{Date: "2025-03-10", AgeOfMoney: 28, Source: AgeFromRecord},
+	)
+	trend := TrendAgeOfMoney(points)
+	if len(trend.Points) != 3 {
+		t.Fatalf("points = %+v", trend.Points)
+	}
+	if trend.Points[1].Source != AgeFromSnapshot || trend.Points[1].AgeOfMoney != 36 {
+		t.Errorf("snapshot should win over month data: %+v", trend.Points[1])
+	}
+	if trend.Current != 28 || trend.Change != -2 || trend.Min != 28 || trend.Max != 36 {
+		t.Errorf("trend = %+v", trend)
+	}
+}
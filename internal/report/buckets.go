@@ -0,0 +1,76 @@
+package report
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// Bucket sizes for BucketTransactions
+const (
+	BucketDay   = "day"
+	BucketWeek  = "week"
+	BucketMonth = "month"
+)
+
+// TransactionBucket is the transactions of one day, ISO week or month,
+// labelled 2025-07-14, 2025-W29 or 2025-07, with their total
+type TransactionBucket struct {
+	Bucket       string             `json:"bucket"`
+	Count        int                `json:"count"`
+	Total        int64              `json:"total" amount:"milliunits"`
+	Transactions []ynab.Transaction `json:"transactions"`
+}
+
+// BucketTransactions splits txns, sorted by date in either direction,
+// into buckets of consecutive transactions in the same period. Deleted
+// transactions are left out.
+func BucketTransactions(txns []ynab.Transaction, by string) ([]TransactionBucket, error) {
+	label, err := bucketLabel(by)
+	if err != nil {
+		return nil, err
+	}
+	buckets := []TransactionBucket{}
+	for _, t := range txns {
+		if t.Deleted {
+			continue
+		}
+		l := label(t.Date)
+		n := len(buckets)
+		if n == 0 || buckets[n-1].Bucket != l {
+			buckets = append(buckets, TransactionBucket{Bucket: l})
+			n++
+		}
+		b := &buckets[n-1]
+		b.Count++
+		b.Total += t.Amount
+		b.Transactions = append(b.Transactions, t)
+	}
+	return buckets, nil
+}
+
+// bucketLabel returns the function naming the bucket of a date
+func bucketLabel(by string) (func(date string) string, error) {
+	switch by {
+	case BucketDay:
+		return func(date string) string { return date }, nil
+	case BucketWeek:
+		return func(date string) string {
+			d, err := time.Parse("2006-01-02", date)
+			if err != nil {
+				return date
+			}
+			year, week := d.ISOWeek()
+			return fmt.Sprintf("%04d-W%02d", year, week)
+		}, nil
+	case BucketMonth:
+		return func(date string) string {
+			if len(date) < 7 {
+				return date
+			}
+			return date[:7]
+		}, nil
+	}
+	return nil, fmt.Errorf("invalid bucket %q (want day|week|month)", by)
+}
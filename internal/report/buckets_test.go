@@ -0,0 +1,44 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestBucketTransactions(t *testing.T) {
+	txns := []ynab.Transaction{
+		{ID: "t1", Date: "2025-06-29", Amount: -10000},
+		{ID: "t2", Date: "2025-06-30", Amount: -5000},
+		{ID: "t3", Date: "2025-07-01", Amount: -2000, Deleted: true},
+		{ID: "t4", Date: "2025-07-02", Amount: 1000},
+	}
+
+	tests := []struct {
+		by     string
+		labels []string
+		totals []int64
+	}{
+		{BucketDay, []string{"2025-06-29", "2025-06-30", "2025-07-02"}, []int64{-10000, -5000, 1000}},
+		{BucketWeek, []string{"2025-W26", "2025-W27"}, []int64{-10000, -4000}},
+		{BucketMonth, []string{"2025-06", "2025-07"}, []int64{-15000, 1000}},
+	}
+	for _, tt := range tests {
+		buckets, err := BucketTransactions(txns, tt.by)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(buckets) != len(tt.labels) {
+			t.Fatalf("%s: buckets = %+v", tt.by, buckets)
+		}
+		for i, b := range buckets {
+			if b.Bucket != tt.labels[i] || b.Total != tt.totals[i] || b.Count != len(b.Transactions) {
+				t.Errorf("%s: bucket %d = %+v, want %s with total %d", tt.by, i, b, tt.labels[i], tt.totals[i])
+			}
+		}
+	}
+
+	if _, err := BucketTransactions(txns, "year"); err == nil {
+		t.Error("BucketTransactions accepted an unknown bucket")
+	}
+}
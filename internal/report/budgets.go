@@ -0,0 +1,35 @@
+package report
+
+import "github.com/langtind/ynabctl/pkg/ynab"
+
+// BudgetSummary is a budget enriched with account and activity figures.
+type BudgetSummary struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	LastModifiedOn  string `json:"last_modified_on"`
+	AccountCount    int    `json:"account_count"`
+	OnBudgetBalance int64  `json:"balance" amount:"milliunits"`
+	LastActivity    string `json:"last_activity,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+
+// SummarizeBudget computes the summary for b from its accounts and
+// recent transactions. Closed and deleted accounts are not counted.
+func SummarizeBudget(b ynab.Budget, accounts []ynab.Account, txns []ynab.Transaction) BudgetSummary {
+	s := BudgetSummary{ID: b.ID, Name: b.Name, LastModifiedOn: b.LastModifiedOn}
+	for _, a := range accounts {
+		if a.Deleted || a.Closed {
+			continue
+		}
+		s.AccountCount++
+		if a.OnBudget {
+			s.OnBudgetBalance += a.Balance
+		}
+	}
+	for _, t := range txns {
+		if !t.Deleted && t.Date > s.LastActivity {
+			s.LastActivity = t.Date
+		}
+	}
+	return s
+}
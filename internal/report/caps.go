@@ -0,0 +1,97 @@
+package report
+
+import (
+	"github.com/langtind/ynabctl/internal/names"
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// Cap states
+const (
+	CapOK       = "ok"
+	CapWarning  = "warning"
+	CapExceeded = "exceeded"
+)
+
+// DefaultCapWarnAt is the share of a cap at which a group is flagged
+const DefaultCapWarnAt = 0.8
+
+// Cap is a monthly spending cap for a category group. WarnAt is the
+// share of the cap (0-1] at which the group is flagged; zero means
+// DefaultCapWarnAt.
+type Cap struct {
+	Group  string
+	Limit  int64
+	WarnAt float64
+}
+
+// CapStatus is the spending of one capped group. Spent is the outflow
+// less refunds, as positive milliunits.
+type CapStatus struct {
+	Group     string  `json:"category_group"`
+	Spent     int64   `json:"spent" amount:"milliunits"`
+	Cap       int64   `json:"cap" amount:"milliunits"`
+	Remaining int64   `json:"remaining" amount:"milliunits"`
+	Percent   float64 `json:"percent"`
+	State     string  `json:"state"`
+}
+
+// CapsReport compares the spending of category groups with their caps
+type CapsReport struct {
+	Month   string      `json:"month"`
+	Groups  []CapStatus `json:"groups"`
+	Unknown []string    `json:"unknown_groups,omitempty"`
+}
+
+// CheckCaps sums the activity of each capped group's categories in
+// month (the categories of a month detail, which carry the month's
+// activity) and compares it with the cap. groups maps categories to
+// their group; caps name groups ignoring case and emojis. Caps naming a
+// group that does not exist are listed in Unknown.
+func CheckCaps(month string, groups []ynab.CategoryGroup, activity []ynab.Category, caps []Cap) *CapsReport {
+	byCategory := map[string]int64{}
+	for _, c := range activity {
+		if !c.Deleted {
+			byCategory[c.ID] = c.Activity
+		}
+	}
+
+	r := &CapsReport{Month: month, Groups: []CapStatus{}}
+	for _, cp := range caps {
+		var group *ynab.CategoryGroup
+		for i := range groups {
+			if !groups[i].Deleted && names.Equal(groups[i].Name, cp.Group) {
+				group = &groups[i]
+				break
+			}
+		}
+		if group == nil {
+			r.Unknown = append(r.Unknown, cp.Group)
+			continue
+		}
+
+		var net int64
+		for _, c := range group.Categories {
+			net += byCategory[c.ID]
+		}
+		s := CapStatus{Group: group.Name, Spent: -net, Cap: cp.Limit, State: CapOK}
+		if s.Spent < 0 {
+			s.Spent = 0
+		}
+		s.Remaining = s.Cap - s.Spent
+		if s.Cap > 0 {
+			s.Percent = float64(s.Spent) / float64(s.Cap) * 100
+		}
+		warnAt := cp.WarnAt
+		if warnAt <= 0 {
+			warnAt = DefaultCapWarnAt
+		}
+		switch {
+		case s.Spent > s.Cap:
+			s.State = CapExceeded
+		case float64(s.Spent) >= warnAt*float64(s.Cap):
+			s.State = CapWarning
+		}
+		r.Groups = append(r.Groups, s)
+	}
+	return r
+}
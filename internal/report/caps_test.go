@@ -0,0 +1,45 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestCheckCaps(t *testing.T) {
+	groups := []ynab.CategoryGroup{
+		{Name: "🎉 Fun", Categories: []ynab.Category{{ID: "games"}, {ID: "dining"}}},
+		{Name: "Bills", Categories: []ynab.Category{{ID: "rent"}}},
+		{Name: "Travel", Categories: []ynab.Category{{ID: "flights"}}},
+		{Name: "Gifts", Categories: []ynab.Category{{ID: "gifts"}}},
+	}
+	activity := []ynab.Category{
+		{ID: "games", Activity: -500000},
+		{ID: "dining", Activity: -1200000},
+		{ID: "rent", Activity: -10000000},
+		{ID: "flights", Activity: -1000000},
+		{ID: "gifts", Activity: 300000}, // refund only
+	}
+	caps := []Cap{
+		{Group: "fun", Limit: 2000000},
+		{Group: "Bills", Limit: 9000000},
+		{Group: "Travel", Limit: 2000000, WarnAt: 0.4},
+		{Group: "Gifts", Limit: 1000000},
+		{Group: "Hobbies", Limit: 1000000},
+	}
+
+	r := CheckCaps("2025-08-01", groups, activity, caps)
+	want := []CapStatus{
+		{Group: "🎉 Fun", Spent: 1700000, Cap: 2000000, Remaining: 300000, Percent: 85, State: CapWarning},
+		{Group: "Bills", Spent: 10000000, Cap: 9000000, Remaining: -1000000, Percent: 10000000.0 / 9000000 * 100, State: CapExceeded},
+		{Group: "Travel", Spent: 1000000, Cap: 2000000, Remaining: 1000000, Percent: 50, State: CapWarning},
+		{Group: "Gifts", Spent: 0, Cap: 1000000, Remaining: 1000000, Percent: 0, State: CapOK},
+	}
+	if !reflect.DeepEqual(r.Groups, want) {
+		t.Errorf("got %+v\nwant %+v", r.Groups, want)
+	}
+	if !reflect.DeepEqual(r.Unknown, []string{"Hobbies"}) {
+		t.Errorf("unknown = %v, want [Hobbies]", r.Unknown)
+	}
+}
@@ -0,0 +1,42 @@
+package report
+
+import "sort"
+
+// CategoryMonth is one month of a category. Amounts are milliunits.
+type CategoryMonth struct {
+	Month    string `json:"month"`
+	Budgeted int64  `json:"budgeted" amount:"milliunits"`
+	Activity int64  `json:"activity" amount:"milliunits"`
+	Balance  int64  `json:"balance" amount:"milliunits"`
+}
+
+// CategoryHistory is a category month by month, oldest first, with the
+// totals and monthly averages of its budgeted amounts and activity
+type CategoryHistory struct {
+	CategoryID      string          `json:"category_id"`
+	CategoryName    string          `json:"category_name"`
+	Months          []CategoryMonth `json:"months"`
+	TotalBudgeted   int64           `json:"total_budgeted" amount:"milliunits"`
+	TotalActivity   int64           `json:"total_activity" amount:"milliunits"`
+	AverageBudgeted int64           `json:"average_budgeted" amount:"milliunits"`
+	AverageActivity int64           `json:"average_activity" amount:"milliunits"`
+}
+
+// SummarizeCategoryHistory sorts months and adds their totals and
+// averages
+func SummarizeCategoryHistory(categoryID, name string, months []CategoryMonth) *CategoryHistory {
+	sort.Slice(months, func(i, j int) bool { return months[i].Month < months[j].Month })
+	h := &CategoryHistory{CategoryID: categoryID, CategoryName: name, Months: months}
+	if h.Months == nil {
+		h.Months = []CategoryMonth{}
+	}
+	for _, m := range months {
+		h.TotalBudgeted += m.Budgeted
+		h.TotalActivity += m.Activity
+	}
+	if n := int64(len(months)); n > 0 {
+		h.AverageBudgeted = h.TotalBudgeted / n
+		h.AverageActivity = h.TotalActivity / n
+	}
+	return h
+}
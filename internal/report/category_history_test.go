@@ -0,0 +1,24 @@
+package report
+
+import "testing"
+
+func TestSummarizeCategoryHistory(t *testing.T) {
+	h := SummarizeCategoryHistory("c1", "Groceries", []CategoryMonth{
+		{Month: "2025-03-01", Budgeted: 5000000, Activity: -4500000, Balance: 1000000},
+		{Month: "2025-01-01", Budgeted: 4000000, Activity: -4200000, Balance: -200000},
+		{Month: "2025-02-01", Budgeted: 4500000, Activity: -3800000, Balance: 500000},
+	})
+	if h.Months[0].Month != "2025-01-01" || h.Months[2].Month != "2025-03-01" {
+		t.Errorf("months not sorted: %+v", h.Months)
+	}
+	if h.TotalBudgeted != 13500000 || h.TotalActivity != -12500000 {
+		t.Errorf("totals = %d, %d", h.TotalBudgeted, h.TotalActivity)
+	}
+	if h.AverageBudgeted != 4500000 || h.AverageActivity != -4166666 {
+		t.Errorf("averages = %d, %d", h.AverageBudgeted, h.AverageActivity)
+	}
+
+	if empty := SummarizeCategoryHistory("c1", "Groceries", nil); empty.AverageActivity != 0 || empty.Months == nil {
+		t.Errorf("empty history = %+v", empty)
+	}
+}
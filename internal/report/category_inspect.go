@@ -0,0 +1,63 @@
+package report
+
+import (
+	"sort"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// CategoryInspection is the full picture of one category: this month's
+// figures and goal, its trend, its latest transactions and the
+// scheduled transactions that will use it
+type CategoryInspection struct {
+	Category     ynab.Category               `json:"category"`
+	Month        string                      `json:"month"`
+	History      *CategoryHistory            `json:"history"`
+	Transactions []ynab.Transaction          `json:"recent_transactions"`
+	Scheduled    []ynab.ScheduledTransaction `json:"scheduled"`
+}
+
+// InspectCategory puts together the inspection of category c. Of txns,
+// the recent latest ones in c are kept, newest first; of scheduled,
+// those in c (or with a part in it), soonest first.
+func InspectCategory(c ynab.Category, month string, history *CategoryHistory, txns []ynab.Transaction, scheduled []ynab.ScheduledTransaction, recent int) *CategoryInspection {
+	in := &CategoryInspection{
+		Category:     c,
+		Month:        month,
+		History:      history,
+		Transactions: []ynab.Transaction{},
+		Scheduled:    []ynab.ScheduledTransaction{},
+	}
+
+	for _, t := range txns {
+		if !t.Deleted {
+			in.Transactions = append(in.Transactions, t)
+		}
+	}
+	sort.SliceStable(in.Transactions, func(i, j int) bool { return in.Transactions[i].Date > in.Transactions[j].Date })
+	if len(in.Transactions) > recent {
+		in.Transactions = in.Transactions[:recent]
+	}
+
+	for _, s := range scheduled {
+		if !s.Deleted && schedulesCategory(s, c.ID) {
+			in.Scheduled = append(in.Scheduled, s)
+		}
+	}
+	sort.SliceStable(in.Scheduled, func(i, j int) bool { return in.Scheduled[i].DateNext < in.Scheduled[j].DateNext })
+	return in
+}
+
+// schedulesCategory reports whether s, or one of its parts, is in the
+// category with the given ID
+func schedulesCategory(s ynab.ScheduledTransaction, categoryID string) bool {
+	if s.CategoryID == categoryID {
+		return true
+	}
+	for _, sub := range s.Subtransactions {
+		if !sub.Deleted && sub.CategoryID == categoryID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestInspectCategory(t *testing.T) {
+	c := ynab.Category{ID: "food", Name: "Groceries"}
+	txns := []ynab.Transaction{
+		{ID: "t1", Date: "2026-10-01"},
+		{ID: "t2", Date: "2026-10-09"},
+		{ID: "t3", Date: "2026-10-05", Deleted: true},
+		{ID: "t4", Date: "2026-10-03"},
+	}
+	scheduled := []ynab.ScheduledTransaction{
+		{ID: "s1", CategoryID: "rent", DateNext: "2026-11-01"},
+		{ID: "s2", CategoryID: "food", DateNext: "2026-11-20"},
+		{ID: "s3", DateNext: "2026-10-25", Subtransactions: []ynab.ScheduledSubtransaction{{CategoryID: "food"}}},
+		{ID: "s4", CategoryID: "food", Deleted: true},
+	}
+
+	in := InspectCategory(c, "2026-10-01", nil, txns, scheduled, 2)
+	if len(in.Transactions) != 2 || in.Transactions[0].ID != "t2" || in.Transactions[1].ID != "t4" {
+		t.Errorf("transactions = %+v, want t2 and t4", in.Transactions)
+	}
+	if len(in.Scheduled) != 2 || in.Scheduled[0].ID != "s3" || in.Scheduled[1].ID != "s2" {
+		t.Errorf("scheduled = %+v, want s3 then s2", in.Scheduled)
+	}
+}
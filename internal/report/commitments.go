@@ -0,0 +1,128 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// monthlyFactor is how many times per month each scheduled frequency
+// occurs on average. "never" (one-off) schedules are not commitments.
+var monthlyFactor = map[string]float64{
+	"daily":           365.0 / 12,
+	"weekly":          52.0 / 12,
+	"everyOtherWeek":  26.0 / 12,
+	"twiceAMonth":     2,
+	"every4Weeks":     13.0 / 12,
+	"monthly":         1,
+	"everyOtherMonth": 1.0 / 2,
+	"every3Months":    1.0 / 3,
+	"every4Months":    1.0 / 4,
+	"twiceAYear":      1.0 / 6,
+	"yearly":          1.0 / 12,
+	"everyOtherYear":  1.0 / 24,
+}
+
+// MonthlyEquivalent converts a scheduled amount to its average monthly
+// value. ok is false for one-off or unknown frequencies.
+func MonthlyEquivalent(amount int64, frequency string) (monthly int64, ok bool) {
+	f, ok := monthlyFactor[frequency]
+	if !ok {
+		return 0, false
+	}
+	return int64(math.Round(float64(amount) * f)), true
+}
+
+// CommitmentItem is one recurring scheduled outflow.
+type CommitmentItem struct {
+	Payee     string `json:"payee"`
+	Category  string `json:"category"`
+	Account   string `json:"account"`
+	Frequency string `json:"frequency"`
+	Amount    int64  `json:"amount" amount:"milliunits"`
+	Monthly   int64  `json:"monthly" amount:"milliunits"`
+}
+
+// CommitmentRow is the monthly total for one group.
+type CommitmentRow struct {
+	Name    string `json:"name"`
+	Monthly int64  `json:"monthly" amount:"milliunits"`
+	Count   int    `json:"count"`
+}
+
+// Commitments summarizes fixed monthly obligations from scheduled
+// transactions. Monthly amounts are positive milliunits.
+type Commitments struct {
+	GroupBy       string           `json:"group_by"`
+	Total         int64            `json:"total" amount:"milliunits"`
+	MonthlyIncome int64            `json:"income" amount:"milliunits"`
+	Rows          []CommitmentRow  `json:"rows"`
+	Items         []CommitmentItem `json:"items"`
+}
+
+// CommitmentsBy normalizes recurring scheduled outflows to a monthly
+// equivalent and groups them by category or account. Recurring inflows
+// are summed into MonthlyIncome. Transfers are skipped unless
+// includeTransfers is set.
+func CommitmentsBy(scheduled []ynab.ScheduledTransaction, groupBy string, includeTransfers bool) (*Commitments, error) {
+	if groupBy != ByCategory && groupBy != ByAccount {
+		return nil, fmt.Errorf("unknown group-by %q (want category|account)", groupBy)
+	}
+
+	c := &Commitments{GroupBy: groupBy, Rows: []CommitmentRow{}, Items: []CommitmentItem{}}
+	totals := map[string]*CommitmentRow{}
+
+	for _, st := range scheduled {
+		if st.Deleted || (st.TransferAccountID != "" && !includeTransfers) {
+			continue
+		}
+		monthly, ok := MonthlyEquivalent(st.Amount, st.Frequency)
+		if !ok {
+			continue
+		}
+		if monthly > 0 {
+			c.MonthlyIncome += monthly
+			continue
+		}
+		if monthly == 0 {
+			continue
+		}
+
+		item := CommitmentItem{
+			Payee:     st.PayeeName,
+			Category:  valueOr(st.CategoryName, "(uncategorized)"),
+			Account:   st.AccountName,
+			Frequency: st.Frequency,
+			Amount:    -st.Amount,
+			Monthly:   -monthly,
+		}
+		c.Items = append(c.Items, item)
+		c.Total += item.Monthly
+
+		key := item.Category
+		if groupBy == ByAccount {
+			key = item.Account
+		}
+		row, ok := totals[key]
+		if !ok {
+			row = &CommitmentRow{Name: key}
+			totals[key] = row
+		}
+		row.Monthly += item.Monthly
+		row.Count++
+	}
+
+	for _, row := range totals {
+		c.Rows = append(c.Rows, *row)
+	}
+	sort.Slice(c.Rows, func(i, j int) bool {
+		if c.Rows[i].Monthly != c.Rows[j].Monthly {
+			return c.Rows[i].Monthly > c.Rows[j].Monthly
+		}
+		return c.Rows[i].Name < c.Rows[j].Name
+	})
+	sort.SliceStable(c.Items, func(i, j int) bool { return c.Items[i].Monthly > c.Items[j].Monthly })
+	return c, nil
+}
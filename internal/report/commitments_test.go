@@ -0,0 +1,61 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestMonthlyEquivalent(t *testing.T) {
+	cases := []struct {
+		amount    int64
+		frequency string
+		want      int64
+		ok        bool
+	}{
+		{-100000, "monthly", -100000, true},
+		{-12000, "weekly", -52000, true},
+		{-120000, "yearly", -10000, true},
+		{-30000, "every3Months", -10000, true},
+		{-50000, "never", 0, false},
+	}
+	for _, c := range cases {
+		got, ok := MonthlyEquivalent(c.amount, c.frequency)
+		if got != c.want || ok != c.ok {
+			t.Errorf("MonthlyEquivalent(%d, %s) = %d, %v; want %d, %v", c.amount, c.frequency, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestCommitmentsBy(t *testing.T) {
+	scheduled := []ynab.ScheduledTransaction{
+		{Frequency: "monthly", Amount: -1000000, CategoryName: "Rent", AccountName: "Checking"},
+		{Frequency: "yearly", Amount: -1200000, CategoryName: "Insurance", AccountName: "Checking"},
+		{Frequency: "monthly", Amount: -15000, CategoryName: "Insurance", AccountName: "Credit"},
+		{Frequency: "monthly", Amount: 3000000, AccountName: "Checking"},
+		{Frequency: "never", Amount: -500000, CategoryName: "Gifts"},
+		{Frequency: "monthly", Amount: -200000, TransferAccountID: "savings"},
+	}
+
+	c, err := CommitmentsBy(scheduled, ByCategory, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Total != 1115000 {
+		t.Errorf("total = %d, want 1115000", c.Total)
+	}
+	if c.MonthlyIncome != 3000000 {
+		t.Errorf("income = %d, want 3000000", c.MonthlyIncome)
+	}
+	if len(c.Rows) != 2 || c.Rows[0].Name != "Rent" || c.Rows[1].Monthly != 115000 {
+		t.Errorf("unexpected rows: %+v", c.Rows)
+	}
+
+	c, err = CommitmentsBy(scheduled, ByAccount, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Total != 1315000 {
+		t.Errorf("total with transfers = %d, want 1315000", c.Total)
+	}
+}
@@ -0,0 +1,150 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// BudgetData is what Consolidate needs of one budget. Rate converts its
+// amounts to the report currency: one unit of the budget's currency is
+// worth Rate units of the report's.
+type BudgetData struct {
+	ID           string
+	Name         string
+	Currency     string
+	Rate         float64
+	Accounts     []ynab.Account
+	Transactions []ynab.Transaction
+	Scheduled    []ynab.ScheduledTransaction
+}
+
+// ConsolidatedBudget is the share of one budget in a consolidated
+// report, in the report currency
+type ConsolidatedBudget struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Currency string  `json:"currency"`
+	Rate     float64 `json:"rate"`
+	NetWorth int64   `json:"net_worth" amount:"milliunits"`
+	Spending int64   `json:"spending" amount:"milliunits"`
+	Upcoming int64   `json:"upcoming" amount:"milliunits"`
+}
+
+// UpcomingBill is one occurrence of a scheduled outflow
+type UpcomingBill struct {
+	Date    string `json:"date"`
+	Budget  string `json:"budget"`
+	Account string `json:"account"`
+	Payee   string `json:"payee"`
+	Amount  int64  `json:"amount" amount:"milliunits"`
+}
+
+// Consolidated combines several budgets into one net worth, spending and
+// upcoming bills report. All amounts are in Currency.
+type Consolidated struct {
+	Currency      string               `json:"currency"`
+	StartDate     string               `json:"start_date,omitempty"`
+	EndDate       string               `json:"end_date,omitempty"`
+	Until         string               `json:"until"`
+	Budgets       []ConsolidatedBudget `json:"budgets"`
+	NetWorth      int64                `json:"net_worth" amount:"milliunits"`
+	Spending      *Spending            `json:"spending"`
+	Upcoming      []UpcomingBill       `json:"upcoming"`
+	UpcomingTotal int64                `json:"upcoming_total" amount:"milliunits"`
+}
+
+// Consolidate merges budgets in the report currency. Net worth is the sum
+// of open account balances. Spending between start and end is grouped by
+// groupBy, and groups with the same name in several budgets (e.g. a
+// "Groceries" category in each) are added up. Upcoming bills are the
+// scheduled outflows, transfers excluded, through until.
+func Consolidate(budgets []BudgetData, currency, groupBy, start, end string, until time.Time) (*Consolidated, error) {
+	c := &Consolidated{
+		Currency:  currency,
+		StartDate: start,
+		EndDate:   end,
+		Until:     until.Format("2006-01-02"),
+		Budgets:   []ConsolidatedBudget{},
+		Spending:  &Spending{GroupBy: groupBy, StartDate: start, EndDate: end, Rows: []SpendingRow{}},
+		Upcoming:  []UpcomingBill{},
+	}
+	rows := map[string]*SpendingRow{}
+	for _, b := range budgets {
+		convert := func(milliunits int64) int64 {
+			return int64(math.Round(float64(milliunits) * b.Rate))
+		}
+		part := ConsolidatedBudget{ID: b.ID, Name: b.Name, Currency: b.Currency, Rate: b.Rate}
+		part.NetWorth = convert(SummarizeAccounts(b.Accounts, false).Net)
+
+		spending, err := SpendingBy(b.Transactions, groupBy, start, end)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range spending.Rows {
+			row := rows[r.Name]
+			if row == nil {
+				row = &SpendingRow{Name: r.Name}
+				rows[r.Name] = row
+			}
+			amount := convert(r.Amount)
+			row.Amount += amount
+			row.Count += r.Count
+			part.Spending += amount
+		}
+
+		for _, st := range b.Scheduled {
+			if st.Deleted || st.Amount >= 0 || st.TransferAccountID != "" {
+				continue
+			}
+			payee := st.PayeeName
+			if payee == "" {
+				payee = st.CategoryName
+			}
+			for _, d := range Occurrences(st, until) {
+				amount := convert(-st.Amount)
+				c.Upcoming = append(c.Upcoming, UpcomingBill{Date: d, Budget: b.Name, Account: st.AccountName, Payee: payee, Amount: amount})
+				part.Upcoming += amount
+			}
+		}
+
+		c.NetWorth += part.NetWorth
+		c.Spending.Total += part.Spending
+		c.UpcomingTotal += part.Upcoming
+		c.Budgets = append(c.Budgets, part)
+	}
+
+	for _, r := range rows {
+		c.Spending.Rows = append(c.Spending.Rows, *r)
+	}
+	sort.Slice(c.Spending.Rows, func(i, j int) bool {
+		a, b := c.Spending.Rows[i], c.Spending.Rows[j]
+		if a.Amount != b.Amount {
+			return a.Amount > b.Amount
+		}
+		return a.Name < b.Name
+	})
+	sort.SliceStable(c.Upcoming, func(i, j int) bool { return c.Upcoming[i].Date < c.Upcoming[j].Date })
+	return c, nil
+}
+
+// ParseRates reads currency rates given as "USD=10.85": one unit of the
+// currency is worth that many units of the report currency
+func ParseRates(specs []string) (map[string]float64, error) {
+	rates := map[string]float64{}
+	for _, spec := range specs {
+		code, value, ok := strings.Cut(spec, "=")
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if !ok || err != nil || rate <= 0 || code == "" {
+			return nil, fmt.Errorf("invalid rate %q (want CODE=rate, e.g. USD=10.85)", spec)
+		}
+		rates[code] = rate
+	}
+	return rates, nil
+}
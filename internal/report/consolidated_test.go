@@ -0,0 +1,72 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestConsolidate(t *testing.T) {
+	personal := BudgetData{
+		ID: "b1", Name: "Personal", Currency: "NOK", Rate: 1,
+		Accounts: []ynab.Account{
+			{ID: "a1", Type: "checking", Balance: 10000000},
+			{ID: "a2", Type: "creditCard", Balance: -2000000},
+			{ID: "a3", Type: "savings", Balance: 99000000, Closed: true},
+		},
+		Transactions: []ynab.Transaction{
+			{Date: "2026-10-02", Amount: -500000, CategoryName: "Groceries", AccountName: "Checking"},
+			{Date: "2026-10-03", Amount: -200000, CategoryName: "Fun", AccountName: "Checking"},
+		},
+		Scheduled: []ynab.ScheduledTransaction{
+			{DateNext: "2026-10-20", Frequency: "weekly", Amount: -100000, PayeeName: "Gym", AccountName: "Checking"},
+			{DateNext: "2026-10-21", Frequency: "monthly", Amount: 3000000, PayeeName: "Salary"},
+			{DateNext: "2026-10-22", Frequency: "monthly", Amount: -1000000, PayeeName: "Transfer", TransferAccountID: "a3"},
+		},
+	}
+	shared := BudgetData{
+		ID: "b2", Name: "Shared", Currency: "USD", Rate: 10,
+		Accounts: []ynab.Account{{ID: "a4", Type: "checking", Balance: 1000000}},
+		Transactions: []ynab.Transaction{
+			{Date: "2026-10-05", Amount: -30000, CategoryName: "Groceries", AccountName: "Joint"},
+		},
+		Scheduled: []ynab.ScheduledTransaction{
+			{DateNext: "2026-10-25", Frequency: "monthly", Amount: -150000, PayeeName: "Rent", AccountName: "Joint"},
+		},
+	}
+
+	until := time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC)
+	c, err := Consolidate([]BudgetData{personal, shared}, "NOK", ByCategory, "2026-10-01", "2026-10-31", until)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.NetWorth != 18000000 {
+		t.Errorf("net worth = %d, want 18000000", c.NetWorth)
+	}
+	if c.Spending.Total != 1000000 || len(c.Spending.Rows) != 2 {
+		t.Fatalf("spending = %+v", c.Spending)
+	}
+	if r := c.Spending.Rows[0]; r.Name != "Groceries" || r.Amount != 800000 || r.Count != 2 {
+		t.Errorf("top row = %+v, want Groceries 800000 from 2 transactions", r)
+	}
+	// two gym sessions and the rent; no salary, no transfer
+	if len(c.Upcoming) != 3 || c.Upcoming[1].Payee != "Rent" || c.Upcoming[1].Amount != 1500000 {
+		t.Errorf("upcoming = %+v", c.Upcoming)
+	}
+	if c.UpcomingTotal != 1700000 || c.Budgets[1].Upcoming != 1500000 {
+		t.Errorf("upcoming total = %d, shared = %d", c.UpcomingTotal, c.Budgets[1].Upcoming)
+	}
+}
+
+func TestParseRates(t *testing.T) {
+	rates, err := ParseRates([]string{"usd=10.85", "EUR = 11.5"})
+	if err != nil || rates["USD"] != 10.85 || rates["EUR"] != 11.5 {
+		t.Errorf("ParseRates = %v, %v", rates, err)
+	}
+	for _, bad := range []string{"USD", "USD=abc", "USD=-1", "=2"} {
+		if _, err := ParseRates([]string{bad}); err == nil {
+			t.Errorf("ParseRates(%q) succeeded", bad)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package report
+
+import (
+	"github.com/langtind/ynabctl/internal/names"
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// Exclusions are accounts and categories, by name or ID, left out of
+// reports, e.g. a reimbursable business card that would skew personal
+// spending
+type Exclusions struct {
+	Accounts   []string
+	Categories []string
+}
+
+// excluded reports whether id or name is in list
+func excluded(list []string, id, name string) bool {
+	for _, v := range list {
+		if v == id || names.Equal(v, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Transactions returns the transactions not in an excluded account. Parts
+// of a split in an excluded category are dropped and the amount reduced
+// accordingly; a transaction left without parts is dropped.
+func (e Exclusions) Transactions(txns []ynab.Transaction) []ynab.Transaction {
+	if len(e.Accounts) == 0 && len(e.Categories) == 0 {
+		return txns
+	}
+	var kept []ynab.Transaction
+	for _, t := range txns {
+		if excluded(e.Accounts, t.AccountID, t.AccountName) {
+			continue
+		}
+		if len(t.Subtransactions) == 0 {
+			if t.CategoryID == "" || !excluded(e.Categories, t.CategoryID, t.CategoryName) {
+				kept = append(kept, t)
+			}
+			continue
+		}
+		var subs []ynab.Subtransaction
+		var amount int64
+		for _, s := range t.Subtransactions {
+			if s.CategoryID == "" || !excluded(e.Categories, s.CategoryID, s.CategoryName) {
+				subs = append(subs, s)
+				amount += s.Amount
+			}
+		}
+		if len(subs) == 0 {
+			continue
+		}
+		if len(subs) < len(t.Subtransactions) {
+			t.Subtransactions, t.Amount = subs, amount
+		}
+		kept = append(kept, t)
+	}
+	return kept
+}
+
+// AccountList returns the accounts that are not excluded
+func (e Exclusions) AccountList(accounts []ynab.Account) []ynab.Account {
+	var kept []ynab.Account
+	for _, a := range accounts {
+		if !excluded(e.Accounts, a.ID, a.Name) {
+			kept = append(kept, a)
+		}
+	}
+	return kept
+}
+
+// CategoryList returns the categories that are not excluded
+func (e Exclusions) CategoryList(categories []ynab.Category) []ynab.Category {
+	var kept []ynab.Category
+	for _, c := range categories {
+		if !excluded(e.Categories, c.ID, c.Name) {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// Groups returns the category groups with excluded categories removed
+func (e Exclusions) Groups(groups []ynab.CategoryGroup) []ynab.CategoryGroup {
+	kept := make([]ynab.CategoryGroup, len(groups))
+	for i, g := range groups {
+		g.Categories = e.CategoryList(g.Categories)
+		kept[i] = g
+	}
+	return kept
+}
@@ -0,0 +1,46 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestExclusionsTransactions(t *testing.T) {
+	e := Exclusions{Accounts: []string{"Work Visa"}, Categories: []string{"cat-reimb"}}
+	txns := []ynab.Transaction{
+		{ID: "a", AccountName: "Work Visa", AccountID: "acc-work", Amount: -100_000},
+		{ID: "b", AccountName: "Checking", CategoryID: "cat-food", CategoryName: "Food", Amount: -50_000},
+		{ID: "c", AccountName: "Checking", CategoryID: "cat-reimb", CategoryName: "Reimbursable", Amount: -20_000},
+		{ID: "d", AccountName: "Checking", Amount: -90_000, Subtransactions: []ynab.Subtransaction{
+			{CategoryID: "cat-food", Amount: -60_000},
+			{CategoryID: "cat-reimb", Amount: -30_000},
+		}},
+	}
+	got := e.Transactions(txns)
+	if len(got) != 2 || got[0].ID != "b" || got[1].ID != "d" {
+		t.Fatalf("kept %+v, want b and d", got)
+	}
+	if got[1].Amount != -60_000 || len(got[1].Subtransactions) != 1 {
+		t.Errorf("split = %d with %d parts, want -60000 with 1", got[1].Amount, len(got[1].Subtransactions))
+	}
+	if len(txns[3].Subtransactions) != 2 {
+		t.Error("the original split was modified")
+	}
+
+	if got := (Exclusions{}).Transactions(txns); len(got) != len(txns) {
+		t.Errorf("no exclusions kept %d of %d", len(got), len(txns))
+	}
+}
+
+func TestExclusionsGroups(t *testing.T) {
+	e := Exclusions{Categories: []string{"reimbursable"}}
+	groups := []ynab.CategoryGroup{{Name: "Work", Categories: []ynab.Category{{Name: "Reimbursable"}, {Name: "Lunch"}}}}
+	got := e.Groups(groups)
+	if len(got[0].Categories) != 1 || got[0].Categories[0].Name != "Lunch" {
+		t.Errorf("categories = %+v, want only Lunch", got[0].Categories)
+	}
+	if len(groups[0].Categories) != 2 {
+		t.Error("the original group was modified")
+	}
+}
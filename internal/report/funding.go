@@ -0,0 +1,59 @@
+package report
+
+import "github.com/langtind/ynabctl/pkg/ynab"
+
+// FundingItem is one category of a funding run, in priority order
+type FundingItem struct {
+	CategoryID string `json:"category_id"`
+	Name       string `json:"name"`
+	Need       int64  `json:"need" amount:"milliunits"`
+	Funded     int64  `json:"funded" amount:"milliunits"`
+	Short      int64  `json:"short" amount:"milliunits"`
+	// Budgeted is the category's budgeted amount after funding
+	Budgeted int64 `json:"budgeted" amount:"milliunits"`
+}
+
+// Funding is an amount spread over categories in priority order
+type Funding struct {
+	Month    string        `json:"month"`
+	Amount   int64         `json:"amount" amount:"milliunits"`
+	Funded   int64         `json:"funded" amount:"milliunits"`
+	LeftOver int64         `json:"left_over" amount:"milliunits"`
+	Short    int64         `json:"short" amount:"milliunits"`
+	Items    []FundingItem `json:"items"`
+}
+
+// MonthlyNeed is what a category still needs this month: what its goal
+// is underfunded by, or for a category without a goal, the amount it is
+// overspent by
+func MonthlyNeed(c ynab.Category) int64 {
+	if c.GoalType != "" {
+		return max(c.GoalUnderFunded, 0)
+	}
+	return max(-c.Balance, 0)
+}
+
+// FundInOrder gives each category, in order, as much of amount as it
+// still needs this month until the amount runs out. Categories that
+// need nothing are listed with nothing funded.
+func FundInOrder(month string, categories []ynab.Category, amount int64) *Funding {
+	f := &Funding{Month: month, Amount: amount, Items: []FundingItem{}}
+	left := amount
+	for _, c := range categories {
+		need := MonthlyNeed(c)
+		funded := min(need, left)
+		left -= funded
+		f.Items = append(f.Items, FundingItem{
+			CategoryID: c.ID,
+			Name:       c.Name,
+			Need:       need,
+			Funded:     funded,
+			Short:      need - funded,
+			Budgeted:   c.Budgeted + funded,
+		})
+		f.Funded += funded
+		f.Short += need - funded
+	}
+	f.LeftOver = left
+	return f
+}
@@ -0,0 +1,40 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestFundInOrder(t *testing.T) {
+	categories := []ynab.Category{
+		{ID: "rent", Name: "Rent", GoalType: "NEED", GoalUnderFunded: 1_000_000, Budgeted: 500_000},
+		{ID: "food", Name: "Food", Balance: -300_000},
+		{ID: "fun", Name: "Fun", GoalType: "MF", GoalUnderFunded: 0},
+		{ID: "car", Name: "Car", GoalType: "TB", GoalUnderFunded: 800_000},
+	}
+	f := FundInOrder("2026-10-01", categories, 2_000_000)
+
+	want := []struct{ funded, short int64 }{
+		{1_000_000, 0},
+		{300_000, 0},
+		{0, 0},
+		{700_000, 100_000},
+	}
+	for i, w := range want {
+		if it := f.Items[i]; it.Funded != w.funded || it.Short != w.short {
+			t.Errorf("%s: funded %d short %d, want %d and %d", it.Name, it.Funded, it.Short, w.funded, w.short)
+		}
+	}
+	if f.Items[0].Budgeted != 1_500_000 {
+		t.Errorf("Rent budgeted = %d, want 1500000", f.Items[0].Budgeted)
+	}
+	if f.Funded != 2_000_000 || f.LeftOver != 0 || f.Short != 100_000 {
+		t.Errorf("funded %d, left over %d, short %d; want 2000000, 0, 100000", f.Funded, f.LeftOver, f.Short)
+	}
+
+	f = FundInOrder("2026-10-01", categories[:2], 2_000_000)
+	if f.LeftOver != 700_000 || f.Short != 0 {
+		t.Errorf("left over %d, short %d; want 700000 and 0", f.LeftOver, f.Short)
+	}
+}
@@ -0,0 +1,161 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// GoalScheduleRow is the projected funding of one goal. Contributions
+// line up with GoalSchedule.Months.
+type GoalScheduleRow struct {
+	Group         string  `json:"category_group"`
+	Category      string  `json:"category"`
+	GoalType      string  `json:"goal_type"`
+	TargetMonth   string  `json:"target_month,omitempty"`
+	Remaining     int64   `json:"remaining" amount:"milliunits"`
+	Contributions []int64 `json:"contributions"`
+}
+
+// GoalSchedule projects the month-by-month funding every goal needs.
+// Amounts are milliunits; Over lists months whose total exceeds Income.
+type GoalSchedule struct {
+	Months []string          `json:"months"`
+	Rows   []GoalScheduleRow `json:"rows"`
+	Totals []int64           `json:"totals"`
+	Income int64             `json:"income" amount:"milliunits"`
+	Over   []string          `json:"over_income,omitempty"`
+}
+
+// ScheduleGoals spreads the remaining amount of each goal with a target
+// month evenly over the months from currentMonth (YYYY-MM-01) through the
+// target month. Monthly funding goals (MF) need their target every month.
+// The projection covers horizon months; income is the expected monthly
+// income to compare the totals with (zero to skip the comparison).
+func ScheduleGoals(groups []ynab.CategoryGroup, currentMonth string, horizon int, income int64) (*GoalSchedule, error) {
+	start, err := time.Parse("2006-01-02", currentMonth)
+	if err != nil {
+		return nil, fmt.Errorf("invalid month %q: %w", currentMonth, err)
+	}
+
+	s := &GoalSchedule{Income: income}
+	for i := 0; i < horizon; i++ {
+		s.Months = append(s.Months, start.AddDate(0, i, 0).Format("2006-01-02"))
+	}
+	s.Totals = make([]int64, horizon)
+
+	for _, g := range groups {
+		if g.Deleted || g.Hidden {
+			continue
+		}
+		for _, c := range g.Categories {
+			if c.Deleted || c.Hidden || c.GoalType == "" {
+				continue
+			}
+			row := GoalScheduleRow{
+				Group:         g.Name,
+				Category:      c.Name,
+				GoalType:      c.GoalType,
+				TargetMonth:   c.GoalTargetMonth,
+				Contributions: make([]int64, horizon),
+			}
+
+			switch {
+			case c.GoalTargetMonth != "":
+				row.Remaining = c.GoalOverallLeft
+				if row.Remaining <= 0 {
+					row.Remaining = c.GoalTarget - c.Balance
+				}
+				if row.Remaining <= 0 {
+					continue
+				}
+				spread(row.Contributions, row.Remaining, monthsUntil(start, c.GoalTargetMonth))
+			case c.GoalType == "MF":
+				for i := range row.Contributions {
+					row.Contributions[i] = c.GoalTarget
+				}
+				row.Remaining = c.GoalTarget * int64(horizon)
+			default:
+				continue
+			}
+
+			for i, v := range row.Contributions {
+				s.Totals[i] += v
+			}
+			s.Rows = append(s.Rows, row)
+		}
+	}
+
+	if income > 0 {
+		for i, total := range s.Totals {
+			if total > income {
+				s.Over = append(s.Over, s.Months[i])
+			}
+		}
+	}
+	return s, nil
+}
+
+// monthsUntil counts the months from start through target inclusive; a
+// target in the past counts as the current month
+func monthsUntil(start time.Time, target string) int {
+	t, err := time.Parse("2006-01-02", target)
+	if err != nil {
+		return 1
+	}
+	n := (t.Year()-start.Year())*12 + int(t.Month()-start.Month()) + 1
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// spread divides amount evenly over the first n slots, rounding up so the
+// goal is met on time; slots beyond the horizon are dropped
+func spread(slots []int64, amount int64, n int) {
+	per := (amount + int64(n) - 1) / int64(n)
+	left := amount
+	for i := 0; i < n && i < len(slots) && left > 0; i++ {
+		v := per
+		if v > left {
+			v = left
+		}
+		slots[i] = v
+		left -= v
+	}
+}
+
+// WriteCSV writes the schedule with one column per month and a final
+// TOTAL row. Amounts are decimal currency units.
+func (s *GoalSchedule) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := append([]string{"category_group", "category", "goal_type", "target_month"}, s.Months...)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, r := range s.Rows {
+		record := []string{r.Group, r.Category, r.GoalType, r.TargetMonth}
+		for _, v := range r.Contributions {
+			record = append(record, formatMilliunits(v))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	total := []string{"", "TOTAL", "", ""}
+	for _, v := range s.Totals {
+		total = append(total, formatMilliunits(v))
+	}
+	if err := cw.Write(total); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatMilliunits(v int64) string {
+	return fmt.Sprintf("%.2f", ynab.MilliunitsToAmount(v))
+}
@@ -0,0 +1,54 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestScheduleGoals(t *testing.T) {
+	groups := []ynab.CategoryGroup{{
+		Name: "Savings",
+		Categories: []ynab.Category{
+			{Name: "Vacation", GoalType: "TBD", GoalTargetMonth: "2025-03-01", GoalTarget: 3000000, Balance: 1000000, GoalOverallLeft: 2000000},
+			{Name: "Car", GoalType: "TBD", GoalTargetMonth: "2024-12-01", GoalTarget: 500000, Balance: 200000},
+			{Name: "Streaming", GoalType: "MF", GoalTarget: 200000},
+			{Name: "Buffer", GoalType: "TB", GoalTarget: 9000000},
+			{Name: "Done", GoalType: "TBD", GoalTargetMonth: "2025-06-01", GoalTarget: 100000, Balance: 100000},
+		},
+	}}
+
+	s, err := ScheduleGoals(groups, "2025-01-01", 4, 1500000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Rows) != 3 {
+		t.Fatalf("rows = %+v, want Vacation, Car, Streaming", s.Rows)
+	}
+
+	vacation := s.Rows[0].Contributions
+	if vacation[0] != 666667 || vacation[2] != 666666 || vacation[3] != 0 {
+		t.Errorf("vacation = %v, want 2000 spread over 3 months", vacation)
+	}
+	car := s.Rows[1].Contributions
+	if car[0] != 300000 || car[1] != 0 {
+		t.Errorf("overdue car goal = %v, want all due now", car)
+	}
+	if s.Totals[0] != 1166667 || s.Totals[3] != 200000 {
+		t.Errorf("totals = %v", s.Totals)
+	}
+	if len(s.Over) != 0 {
+		t.Errorf("over = %v, want none", s.Over)
+	}
+
+	var buf bytes.Buffer
+	if err := s.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[4], ",TOTAL,,,1166.67,") {
+		t.Errorf("unexpected CSV:\n%s", buf.String())
+	}
+}
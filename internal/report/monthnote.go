@@ -0,0 +1,81 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// MonthNote holds the values a month note template can use. Amounts are
+// in currency units, so templates can print them directly.
+type MonthNote struct {
+	Month        string
+	ToBeBudgeted float64
+	Income       float64
+	Funded       float64
+	Activity     float64
+	AgeOfMoney   int
+	// Goals counts the categories with a goal; Underfunded lists those
+	// that still need money this month, most needed first
+	Goals        int
+	GoalsOnTrack int
+	Underfunded  []NoteCategory
+	NeededTotal  float64
+}
+
+// NoteCategory is a category that still needs money this month
+type NoteCategory struct {
+	Name   string
+	Needed float64
+}
+
+// NewMonthNote computes the template values of month m. Hidden and
+// deleted categories are left out.
+func NewMonthNote(m *ynab.Month) *MonthNote {
+	n := &MonthNote{
+		Month:        m.Month,
+		ToBeBudgeted: ynab.MilliunitsToAmount(m.ToBeBudgeted),
+		Income:       ynab.MilliunitsToAmount(m.Income),
+		Funded:       ynab.MilliunitsToAmount(m.Budgeted),
+		Activity:     ynab.MilliunitsToAmount(m.Activity),
+		AgeOfMoney:   m.AgeOfMoney,
+		Underfunded:  []NoteCategory{},
+	}
+	for _, c := range m.Categories {
+		if c.Deleted || c.Hidden || c.GoalType == "" {
+			continue
+		}
+		n.Goals++
+		if c.GoalUnderFunded <= 0 {
+			n.GoalsOnTrack++
+			continue
+		}
+		needed := ynab.MilliunitsToAmount(c.GoalUnderFunded)
+		n.Underfunded = append(n.Underfunded, NoteCategory{Name: c.Name, Needed: needed})
+		n.NeededTotal += needed
+	}
+	sort.SliceStable(n.Underfunded, func(i, j int) bool { return n.Underfunded[i].Needed > n.Underfunded[j].Needed })
+	return n
+}
+
+// noteFuncs are the functions available to month note templates
+var noteFuncs = template.FuncMap{
+	"amount": func(v float64) string { return fmt.Sprintf("%.2f", v) },
+}
+
+// RenderMonthNote executes a text/template with the values of n. The
+// result is trimmed of surrounding blank lines.
+func RenderMonthNote(tmpl string, n *MonthNote) (string, error) {
+	t, err := template.New("note").Funcs(noteFuncs).Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, n); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return strings.Trim(b.String(), "\n"), nil
+}
@@ -0,0 +1,46 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestNewMonthNote(t *testing.T) {
+	m := &ynab.Month{
+		Month:        "2026-10-01",
+		ToBeBudgeted: 125_500,
+		Budgeted:     4_000_000,
+		Categories: []ynab.Category{
+			{Name: "Rent", GoalType: "NEED"},
+			{Name: "Car", GoalType: "TB", GoalUnderFunded: 200_000},
+			{Name: "Vacation", GoalType: "TBD", GoalUnderFunded: 500_000},
+			{Name: "Old", GoalType: "TB", GoalUnderFunded: 100_000, Hidden: true},
+			{Name: "Groceries"},
+		},
+	}
+	n := NewMonthNote(m)
+	if n.Goals != 3 || n.GoalsOnTrack != 1 {
+		t.Errorf("goals = %d, on track %d; want 3 and 1", n.Goals, n.GoalsOnTrack)
+	}
+	if len(n.Underfunded) != 2 || n.Underfunded[0].Name != "Vacation" || n.NeededTotal != 700 {
+		t.Errorf("underfunded = %+v (total %v), want Vacation then Car, 700", n.Underfunded, n.NeededTotal)
+	}
+
+	got, err := RenderMonthNote(`
+TBB: {{amount .ToBeBudgeted}}, funded {{amount .Funded}}
+{{range .Underfunded}}- {{.Name}} needs {{amount .Needed}}
+{{end}}`, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "TBB: 125.50, funded 4000.00\n- Vacation needs 500.00\n- Car needs 200.00"
+	if got != want {
+		t.Errorf("rendered %q, want %q", got, want)
+	}
+
+	if _, err := RenderMonthNote("{{.Nope}}", n); err == nil || !strings.Contains(err.Error(), "render") {
+		t.Errorf("unknown field: err = %v, want a render error", err)
+	}
+}
@@ -0,0 +1,105 @@
+package report
+
+import (
+	"sort"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// BudgetedState is the budgeted amount of every category in one month,
+// as observed at RecordedAt. Successive states reveal budget moves.
+type BudgetedState struct {
+	BudgetID   string             `json:"budget_id"`
+	Month      string             `json:"month"`
+	RecordedAt string             `json:"recorded_at"`
+	Categories []BudgetedCategory `json:"categories"`
+}
+
+// BudgetedCategory is one category of a BudgetedState
+type BudgetedCategory struct {
+	ID       string `json:"id"`
+	Group    string `json:"category_group"`
+	Name     string `json:"name"`
+	Budgeted int64  `json:"budgeted" amount:"milliunits"`
+}
+
+// StateFromMonth records the budgeted amounts of a month
+func StateFromMonth(budgetID string, m *ynab.Month, recordedAt string) BudgetedState {
+	s := BudgetedState{BudgetID: budgetID, Month: m.Month, RecordedAt: recordedAt}
+	for _, c := range m.Categories {
+		if c.Deleted {
+			continue
+		}
+		s.Categories = append(s.Categories, BudgetedCategory{
+			ID:       c.ID,
+			Group:    c.CategoryGroupName,
+			Name:     c.Name,
+			Budgeted: c.Budgeted,
+		})
+	}
+	return s
+}
+
+// MoveRow is the change in budgeted for one category
+type MoveRow struct {
+	Group    string `json:"category_group"`
+	Category string `json:"category"`
+	Before   int64  `json:"before" amount:"milliunits"`
+	After    int64  `json:"after" amount:"milliunits"`
+	Change   int64  `json:"change" amount:"milliunits"`
+}
+
+// Moves lists the categories whose budgeted amount changed between two
+// states. MovedIn and MovedOut sum the increases and decreases; their
+// difference is the net change in assigned money.
+type Moves struct {
+	Month    string    `json:"month"`
+	Since    string    `json:"since"`
+	Until    string    `json:"until"`
+	Rows     []MoveRow `json:"rows"`
+	MovedIn  int64     `json:"moved_in" amount:"milliunits"`
+	MovedOut int64     `json:"moved_out" amount:"milliunits"`
+}
+
+// MovesBetween compares two states of the same month. Rows are sorted
+// by the size of the change, largest first.
+func MovesBetween(before, after BudgetedState) *Moves {
+	m := &Moves{Month: after.Month, Since: before.RecordedAt, Until: after.RecordedAt, Rows: []MoveRow{}}
+
+	old := map[string]BudgetedCategory{}
+	for _, c := range before.Categories {
+		old[c.ID] = c
+	}
+	for _, c := range after.Categories {
+		prev := old[c.ID]
+		if c.Budgeted == prev.Budgeted {
+			continue
+		}
+		m.Rows = append(m.Rows, MoveRow{
+			Group:    c.Group,
+			Category: c.Name,
+			Before:   prev.Budgeted,
+			After:    c.Budgeted,
+			Change:   c.Budgeted - prev.Budgeted,
+		})
+	}
+
+	for _, r := range m.Rows {
+		if r.Change > 0 {
+			m.MovedIn += r.Change
+		} else {
+			m.MovedOut -= r.Change
+		}
+	}
+	sort.SliceStable(m.Rows, func(i, j int) bool {
+		return abs(m.Rows[i].Change) > abs(m.Rows[j].Change)
+	})
+	return m
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,35 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestMovesBetween(t *testing.T) {
+	month := &ynab.Month{Month: "2025-01-01", Categories: []ynab.Category{
+		{ID: "rent", Name: "Rent", CategoryGroupName: "Bills", Budgeted: 1000000},
+		{ID: "food", Name: "Groceries", CategoryGroupName: "Everyday", Budgeted: 400000},
+		{ID: "fun", Name: "Fun", CategoryGroupName: "Everyday", Budgeted: 100000},
+	}}
+	before := StateFromMonth("b", month, "2025-01-05T10:00:00Z")
+
+	month.Categories[1].Budgeted = 500000 // +100 groceries
+	month.Categories[2].Budgeted = 0      // -100 fun
+	month.Categories = append(month.Categories, ynab.Category{ID: "new", Name: "Gifts", Budgeted: 50000})
+	after := StateFromMonth("b", month, "2025-01-20T10:00:00Z")
+
+	m := MovesBetween(before, after)
+	if len(m.Rows) != 3 {
+		t.Fatalf("rows = %+v, want 3 changes", m.Rows)
+	}
+	if m.Rows[2].Category != "Gifts" || m.Rows[2].Before != 0 {
+		t.Errorf("smallest change should be the new category: %+v", m.Rows[2])
+	}
+	if m.MovedIn != 150000 || m.MovedOut != 100000 {
+		t.Errorf("in/out = %d/%d, want 150000/100000", m.MovedIn, m.MovedOut)
+	}
+	if m.Since != before.RecordedAt || m.Until != after.RecordedAt {
+		t.Errorf("range = %s..%s", m.Since, m.Until)
+	}
+}
@@ -0,0 +1,139 @@
+package report
+
+import (
+	"sort"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// Occurrences returns the dates (YYYY-MM-DD) on which a scheduled
+// transaction falls from its next date through until, inclusive.
+func Occurrences(st ynab.ScheduledTransaction, until time.Time) []string {
+	first, err := time.Parse("2006-01-02", st.DateNext)
+	if err != nil {
+		return nil
+	}
+
+	var dates []string
+	for i := 0; ; i++ {
+		d, ok := nthOccurrence(first, st.Frequency, i)
+		if !ok || d.After(until) {
+			break
+		}
+		dates = append(dates, d.Format("2006-01-02"))
+	}
+	return dates
+}
+
+// nthOccurrence returns the i-th occurrence after first for a frequency.
+// Month-based frequencies count from first so day-of-month is kept
+// (clamped by Go's date normalization for short months).
+func nthOccurrence(first time.Time, frequency string, i int) (time.Time, bool) {
+	switch frequency {
+	case "never":
+		return first, i == 0
+	case "daily":
+		return first.AddDate(0, 0, i), true
+	case "weekly":
+		return first.AddDate(0, 0, 7*i), true
+	case "everyOtherWeek":
+		return first.AddDate(0, 0, 14*i), true
+	case "every4Weeks":
+		return first.AddDate(0, 0, 28*i), true
+	case "twiceAMonth":
+		// the next date and 15 days later, every month
+		return first.AddDate(0, i/2, 15*(i%2)), true
+	case "monthly":
+		return first.AddDate(0, i, 0), true
+	case "everyOtherMonth":
+		return first.AddDate(0, 2*i, 0), true
+	case "every3Months":
+		return first.AddDate(0, 3*i, 0), true
+	case "every4Months":
+		return first.AddDate(0, 4*i, 0), true
+	case "twiceAYear":
+		return first.AddDate(0, 6*i, 0), true
+	case "yearly":
+		return first.AddDate(i, 0, 0), true
+	case "everyOtherYear":
+		return first.AddDate(2*i, 0, 0), true
+	}
+	return time.Time{}, false
+}
+
+// ProjectedItem is one upcoming scheduled transaction and the balance
+// after it
+type ProjectedItem struct {
+	Date    string `json:"date"`
+	Payee   string `json:"payee"`
+	Amount  int64  `json:"amount" amount:"milliunits"`
+	Balance int64  `json:"balance" amount:"milliunits"`
+}
+
+// Projection is an account's balance run forward through its scheduled
+// transactions. Shortfall is how far the lowest balance falls below the
+// minimum (zero if it never does).
+type Projection struct {
+	AccountID      string          `json:"account_id"`
+	Account        string          `json:"account"`
+	Until          string          `json:"until"`
+	ClearedBalance int64           `json:"cleared_balance" amount:"milliunits"`
+	MinBalance     int64           `json:"min_balance" amount:"milliunits"`
+	Items          []ProjectedItem `json:"items"`
+	LowestBalance  int64           `json:"lowest_balance" amount:"milliunits"`
+	LowestDate     string          `json:"lowest_date,omitempty"`
+	Shortfall      int64           `json:"shortfall" amount:"milliunits"`
+}
+
+// ProjectBalance starts from the account's cleared balance and applies
+// its scheduled transactions up to until. Inflows are only applied when
+// includeInflows is set, so by default the projection is the worst case.
+func ProjectBalance(account ynab.Account, scheduled []ynab.ScheduledTransaction, until time.Time, minBalance int64, includeInflows bool) *Projection {
+	p := &Projection{
+		AccountID:      account.ID,
+		Account:        account.Name,
+		Until:          until.Format("2006-01-02"),
+		ClearedBalance: account.ClearedBalance,
+		MinBalance:     minBalance,
+		Items:          []ProjectedItem{},
+		LowestBalance:  account.ClearedBalance,
+	}
+
+	for _, st := range scheduled {
+		if st.Deleted || st.AccountID != account.ID {
+			continue
+		}
+		if st.Amount >= 0 && !includeInflows {
+			continue
+		}
+		payee := st.PayeeName
+		if payee == "" {
+			payee = st.CategoryName
+		}
+		for _, d := range Occurrences(st, until) {
+			p.Items = append(p.Items, ProjectedItem{Date: d, Payee: payee, Amount: st.Amount})
+		}
+	}
+	// outflows before inflows on the same day, to stay conservative
+	sort.SliceStable(p.Items, func(i, j int) bool {
+		if p.Items[i].Date != p.Items[j].Date {
+			return p.Items[i].Date < p.Items[j].Date
+		}
+		return p.Items[i].Amount < p.Items[j].Amount
+	})
+
+	balance := account.ClearedBalance
+	for i := range p.Items {
+		balance += p.Items[i].Amount
+		p.Items[i].Balance = balance
+		if balance < p.LowestBalance {
+			p.LowestBalance = balance
+			p.LowestDate = p.Items[i].Date
+		}
+	}
+	if p.LowestBalance < minBalance {
+		p.Shortfall = minBalance - p.LowestBalance
+	}
+	return p
+}
@@ -0,0 +1,63 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestOccurrences(t *testing.T) {
+	until := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		frequency string
+		want      []string
+	}{
+		{"never", []string{"2025-01-31"}},
+		{"monthly", []string{"2025-01-31", "2025-03-03", "2025-03-31"}},
+		{"everyOtherMonth", []string{"2025-01-31", "2025-03-31"}},
+		{"twiceAMonth", []string{"2025-01-31", "2025-02-15", "2025-03-03", "2025-03-18", "2025-03-31"}},
+		{"yearly", []string{"2025-01-31"}},
+	}
+	for _, c := range cases {
+		got := Occurrences(ynab.ScheduledTransaction{DateNext: "2025-01-31", Frequency: c.frequency}, until)
+		if len(got) != len(c.want) {
+			t.Errorf("%s: got %v, want %v", c.frequency, got, c.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("%s: got %v, want %v", c.frequency, got, c.want)
+				break
+			}
+		}
+	}
+}
+
+func TestProjectBalance(t *testing.T) {
+	account := ynab.Account{ID: "chk", Name: "Checking", ClearedBalance: 1000000}
+	scheduled := []ynab.ScheduledTransaction{
+		{AccountID: "chk", DateNext: "2025-01-05", Frequency: "weekly", Amount: -300000, PayeeName: "Groceries"},
+		{AccountID: "chk", DateNext: "2025-01-10", Frequency: "monthly", Amount: 2000000, PayeeName: "Salary"},
+		{AccountID: "other", DateNext: "2025-01-06", Frequency: "monthly", Amount: -9000000},
+	}
+	until := time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC)
+
+	p := ProjectBalance(account, scheduled, until, 0, false)
+	if len(p.Items) != 2 || p.LowestBalance != 400000 || p.Shortfall != 0 {
+		t.Errorf("without inflows: %+v", p)
+	}
+
+	p = ProjectBalance(account, scheduled, until, 500000, true)
+	if len(p.Items) != 3 {
+		t.Fatalf("with inflows: %+v", p.Items)
+	}
+	if p.LowestBalance != 700000 || p.LowestDate != "2025-01-05" || p.Shortfall != 0 {
+		t.Errorf("lowest = %d on %s, shortfall %d", p.LowestBalance, p.LowestDate, p.Shortfall)
+	}
+
+	p = ProjectBalance(account, scheduled, until, 800000, true)
+	if p.Shortfall != 100000 {
+		t.Errorf("shortfall = %d, want 100000", p.Shortfall)
+	}
+}
@@ -0,0 +1,37 @@
+package report
+
+import (
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// ReconciledDate returns the local date (YYYY-MM-DD) an account was last
+// reconciled, or "" if it never was
+func ReconciledDate(a ynab.Account) string {
+	if a.LastReconciledAt == "" {
+		return ""
+	}
+	t, err := time.Parse(time.RFC3339, a.LastReconciledAt)
+	if err != nil {
+		return ""
+	}
+	return t.Local().Format("2006-01-02")
+}
+
+// StaleReconciliation returns the open accounts not reconciled within
+// maxAge of now, including those never reconciled
+func StaleReconciliation(accounts []ynab.Account, maxAge time.Duration, now time.Time) []ynab.Account {
+	cutoff := now.Add(-maxAge)
+	stale := []ynab.Account{}
+	for _, a := range accounts {
+		if a.Deleted || a.Closed {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, a.LastReconciledAt)
+		if err != nil || t.Before(cutoff) {
+			stale = append(stale, a)
+		}
+	}
+	return stale
+}
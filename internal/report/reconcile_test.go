@@ -0,0 +1,32 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestStaleReconciliation(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	accounts := []ynab.Account{
+		{Name: "Checking", LastReconciledAt: "2026-10-01T08:00:00Z"},
+		{Name: "Visa", LastReconciledAt: "2026-08-20T08:00:00Z"},
+		{Name: "Cash"},
+		{Name: "Old", Closed: true},
+	}
+	stale := StaleReconciliation(accounts, 30*24*time.Hour, now)
+	if len(stale) != 2 || stale[0].Name != "Visa" || stale[1].Name != "Cash" {
+		t.Errorf("stale = %+v, want Visa and Cash", stale)
+	}
+}
+
+func TestReconciledDate(t *testing.T) {
+	if got := ReconciledDate(ynab.Account{}); got != "" {
+		t.Errorf("never reconciled = %q, want empty", got)
+	}
+	a := ynab.Account{LastReconciledAt: "2026-10-01T12:00:00Z"}
+	if got, want := ReconciledDate(a), time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC).Local().Format("2006-01-02"); got != want {
+		t.Errorf("ReconciledDate = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,135 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// Kinds of reference problems
+const (
+	RefDeletedCategory = "deleted-category"
+	RefDeletedPayee    = "deleted-payee"
+	RefDeletedAccount  = "deleted-account"
+	RefDeletedTransfer = "deleted-transfer-account"
+	RefOrphanedPayee   = "orphaned-transfer-payee"
+)
+
+// ReferenceProblem is a transaction or payee pointing at a record that
+// is deleted or unknown. TransactionID is empty for payee problems.
+type ReferenceProblem struct {
+	Kind          string `json:"kind"`
+	TransactionID string `json:"transaction_id,omitempty"`
+	Date          string `json:"date,omitempty"`
+	Amount        int64  `json:"amount" amount:"milliunits"`
+	Account       string `json:"account,omitempty"`
+	PayeeID       string `json:"payee_id,omitempty"`
+	Payee         string `json:"payee,omitempty"`
+	RefID         string `json:"ref_id"`
+	RefName       string `json:"ref_name,omitempty"`
+	Fix           string `json:"fix"`
+}
+
+// References finds transactions whose category, payee, account or
+// transfer account is deleted or unknown, and transfer payees whose
+// account is gone. Parts of a split are checked one by one. Deleted
+// transactions are skipped. Problems are sorted by kind, then newest
+// transaction first.
+func References(txns []ynab.Transaction, accounts []ynab.Account, groups []ynab.CategoryGroup, payees []ynab.Payee) []ReferenceProblem {
+	liveAccounts := map[string]bool{}
+	for _, a := range accounts {
+		liveAccounts[a.ID] = !a.Deleted
+	}
+	liveCategories := map[string]bool{}
+	for _, g := range groups {
+		for _, c := range g.Categories {
+			liveCategories[c.ID] = !c.Deleted && !g.Deleted
+		}
+	}
+	livePayees := map[string]bool{}
+	for _, p := range payees {
+		livePayees[p.ID] = !p.Deleted
+	}
+
+	problems := []ReferenceProblem{}
+	usage := map[string]int{}
+	add := func(t ynab.Transaction, kind, refID, refName, fix string) {
+		problems = append(problems, ReferenceProblem{
+			Kind:          kind,
+			TransactionID: t.ID,
+			Date:          t.Date,
+			Amount:        t.Amount,
+			Account:       t.AccountName,
+			PayeeID:       t.PayeeID,
+			Payee:         t.PayeeName,
+			RefID:         refID,
+			RefName:       refName,
+			Fix:           fix,
+		})
+	}
+
+	for _, t := range txns {
+		if t.Deleted {
+			continue
+		}
+		usage[t.PayeeID]++
+		if !liveAccounts[t.AccountID] {
+			add(t, RefDeletedAccount, t.AccountID, t.AccountName,
+				fmt.Sprintf("ynabctl transactions delete %s", t.ID))
+			continue
+		}
+		if t.PayeeID != "" && !livePayees[t.PayeeID] {
+			add(t, RefDeletedPayee, t.PayeeID, t.PayeeName,
+				fmt.Sprintf("ynabctl transactions update %s --payee-name %q", t.ID, t.PayeeName))
+		}
+		if t.TransferAccountID != "" && !liveAccounts[t.TransferAccountID] {
+			add(t, RefDeletedTransfer, t.TransferAccountID, "",
+				fmt.Sprintf("ynabctl transactions update %s --payee-name <payee> --category <category>", t.ID))
+		}
+		if len(t.Subtransactions) == 0 {
+			if t.CategoryID != "" && !liveCategories[t.CategoryID] {
+				add(t, RefDeletedCategory, t.CategoryID, t.CategoryName,
+					fmt.Sprintf("ynabctl transactions update %s --category <category>", t.ID))
+			}
+			continue
+		}
+		for _, s := range t.Subtransactions {
+			if s.Deleted {
+				continue
+			}
+			if s.PayeeID != "" {
+				usage[s.PayeeID]++
+			}
+			if s.CategoryID != "" && !liveCategories[s.CategoryID] {
+				add(t, RefDeletedCategory, s.CategoryID, s.CategoryName,
+					"recategorize the split in YNAB")
+			}
+		}
+	}
+
+	for _, p := range payees {
+		if p.Deleted || p.TransferAccountID == "" || liveAccounts[p.TransferAccountID] {
+			continue
+		}
+		fix := fmt.Sprintf("unused; rename it out of the way: ynabctl payees update %s --name %q", p.ID, "zz "+p.Name)
+		if n := usage[p.ID]; n > 0 {
+			fix = fmt.Sprintf("used by %d transactions; give them another payee", n)
+		}
+		problems = append(problems, ReferenceProblem{
+			Kind:    RefOrphanedPayee,
+			PayeeID: p.ID,
+			Payee:   p.Name,
+			RefID:   p.TransferAccountID,
+			Fix:     fix,
+		})
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Kind != problems[j].Kind {
+			return problems[i].Kind < problems[j].Kind
+		}
+		return problems[i].Date > problems[j].Date
+	})
+	return problems
+}
@@ -0,0 +1,57 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestReferences(t *testing.T) {
+	accounts := []ynab.Account{
+		{ID: "checking", Name: "Checking"},
+		{ID: "old", Name: "Old card", Deleted: true},
+	}
+	groups := []ynab.CategoryGroup{{ID: "g1", Categories: []ynab.Category{
+		{ID: "food", Name: "Food"},
+		{ID: "gone", Name: "Gone", Deleted: true},
+	}}}
+	payees := []ynab.Payee{
+		{ID: "shop", Name: "Shop"},
+		{ID: "closed", Name: "Closed shop", Deleted: true},
+		{ID: "xfer-old", Name: "Transfer : Old card", TransferAccountID: "old"},
+		{ID: "xfer-lost", Name: "Transfer : Lost", TransferAccountID: "lost"},
+	}
+	txns := []ynab.Transaction{
+		{ID: "ok", Date: "2026-10-01", AccountID: "checking", PayeeID: "shop", CategoryID: "food"},
+		{ID: "t1", Date: "2026-10-02", AccountID: "checking", PayeeID: "shop", CategoryID: "gone"},
+		{ID: "t2", Date: "2026-10-03", AccountID: "checking", PayeeID: "closed", PayeeName: "Closed shop", CategoryID: "food"},
+		{ID: "t3", Date: "2026-10-04", AccountID: "checking", PayeeID: "xfer-old", TransferAccountID: "old"},
+		{ID: "t4", Date: "2026-10-05", AccountID: "old", PayeeID: "shop", CategoryID: "gone"},
+		{ID: "t5", Date: "2026-10-06", AccountID: "checking", PayeeID: "shop", Subtransactions: []ynab.Subtransaction{
+			{CategoryID: "food"}, {CategoryID: "gone"},
+		}},
+		{ID: "t6", Date: "2026-10-07", AccountID: "checking", CategoryID: "gone", Deleted: true},
+	}
+
+	got := References(txns, accounts, groups, payees)
+	want := []struct{ kind, txn, ref string }{
+		{RefDeletedAccount, "t4", "old"},
+		{RefDeletedCategory, "t5", "gone"},
+		{RefDeletedCategory, "t1", "gone"},
+		{RefDeletedPayee, "t2", "closed"},
+		{RefDeletedTransfer, "t3", "old"},
+		{RefOrphanedPayee, "", "old"},
+		{RefOrphanedPayee, "", "lost"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d problems, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Kind != w.kind || got[i].TransactionID != w.txn || got[i].RefID != w.ref {
+			t.Errorf("problem %d = %s %s %s, want %s %s %s", i, got[i].Kind, got[i].TransactionID, got[i].RefID, w.kind, w.txn, w.ref)
+		}
+	}
+	if got[5].Fix != "used by 1 transactions; give them another payee" {
+		t.Errorf("fix of used transfer payee = %q", got[5].Fix)
+	}
+}
@@ -0,0 +1,82 @@
+package report
+
+import (
+	"math"
+	"sort"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// cashAccountTypes are the on-budget account types counted as cash.
+// Credit cards and loans are liabilities, not runway.
+var cashAccountTypes = map[string]bool{
+	"checking": true,
+	"savings":  true,
+	"cash":     true,
+}
+
+// Runway is the emergency-fund metric: how many months of average
+// expenses the available money would cover. Amounts are milliunits.
+type Runway struct {
+	FromMonth         string   `json:"from_month"`
+	ToMonth           string   `json:"to_month"`
+	MonthsAveraged    int      `json:"months_averaged"`
+	AverageExpenses   int64    `json:"average_expenses" amount:"milliunits"`
+	Cash              int64    `json:"cash" amount:"milliunits"`
+	CashMonths        float64  `json:"cash_months"`
+	SavingsCategories []string `json:"savings_categories,omitempty"`
+	Savings           int64    `json:"savings" amount:"milliunits"`
+	SavingsMonths     float64  `json:"savings_months"`
+}
+
+// ComputeRunway averages the spending of the last n complete months
+// before currentMonth (YYYY-MM-01) and divides the on-budget cash and the
+// balance of the given savings categories by it. Months with net
+// inflows (refunds exceeding spending) count as zero expenses.
+func ComputeRunway(months []ynab.Month, accounts []ynab.Account, savings []ynab.Category, currentMonth string, n int) *Runway {
+	var past []ynab.Month
+	for _, m := range months {
+		if !m.Deleted && m.Month < currentMonth {
+			past = append(past, m)
+		}
+	}
+	sort.Slice(past, func(i, j int) bool { return past[i].Month > past[j].Month })
+	if n > 0 && len(past) > n {
+		past = past[:n]
+	}
+
+	r := &Runway{MonthsAveraged: len(past)}
+	if len(past) > 0 {
+		r.ToMonth = past[0].Month
+		r.FromMonth = past[len(past)-1].Month
+		var total int64
+		for _, m := range past {
+			if m.Activity < 0 {
+				total -= m.Activity
+			}
+		}
+		r.AverageExpenses = total / int64(len(past))
+	}
+
+	for _, a := range accounts {
+		if a.OnBudget && !a.Closed && !a.Deleted && cashAccountTypes[a.Type] {
+			r.Cash += a.Balance
+		}
+	}
+	for _, c := range savings {
+		r.SavingsCategories = append(r.SavingsCategories, c.Name)
+		r.Savings += c.Balance
+	}
+
+	r.CashMonths = coverage(r.Cash, r.AverageExpenses)
+	r.SavingsMonths = coverage(r.Savings, r.AverageExpenses)
+	return r
+}
+
+// coverage returns amount/expenses in months, rounded to one decimal
+func coverage(amount, expenses int64) float64 {
+	if expenses <= 0 || amount <= 0 {
+		return 0
+	}
+	return math.Round(float64(amount)/float64(expenses)*10) / 10
+}
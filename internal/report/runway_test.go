@@ -0,0 +1,46 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestComputeRunway(t *testing.T) {
+	months := []ynab.Month{
+		{Month: "2025-04-01", Activity: -9000000},
+		{Month: "2025-05-01", Activity: -2000000},
+		{Month: "2025-06-01", Activity: -4000000},
+		{Month: "2025-07-01", Activity: 500000},   // refunds only
+		{Month: "2025-08-01", Activity: -1000000}, // current month, ignored
+	}
+	accounts := []ynab.Account{
+		{Type: "checking", OnBudget: true, Balance: 5000000},
+		{Type: "savings", OnBudget: true, Balance: 7000000},
+		{Type: "creditCard", OnBudget: true, Balance: -1000000},
+		{Type: "cash", OnBudget: true, Closed: true, Balance: 100000},
+		{Type: "otherAsset", OnBudget: false, Balance: 90000000},
+	}
+	savings := []ynab.Category{{Name: "Emergency Fund", Balance: 4000000}}
+
+	r := ComputeRunway(months, accounts, savings, "2025-08-01", 3)
+	if r.MonthsAveraged != 3 || r.FromMonth != "2025-05-01" || r.ToMonth != "2025-07-01" {
+		t.Fatalf("unexpected window: %+v", r)
+	}
+	if r.AverageExpenses != 2000000 {
+		t.Errorf("average = %d, want 2000000", r.AverageExpenses)
+	}
+	if r.Cash != 12000000 || r.CashMonths != 6 {
+		t.Errorf("cash = %d (%.1f months), want 12000000 (6)", r.Cash, r.CashMonths)
+	}
+	if r.Savings != 4000000 || r.SavingsMonths != 2 {
+		t.Errorf("savings = %d (%.1f months), want 4000000 (2)", r.Savings, r.SavingsMonths)
+	}
+}
+
+func TestComputeRunwayNoHistory(t *testing.T) {
+	r := ComputeRunway(nil, []ynab.Account{{Type: "checking", OnBudget: true, Balance: 1000}}, nil, "2025-08-01", 6)
+	if r.MonthsAveraged != 0 || r.CashMonths != 0 {
+		t.Errorf("unexpected runway without history: %+v", r)
+	}
+}
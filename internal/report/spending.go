@@ -0,0 +1,153 @@
+// Package report aggregates budget data into summaries for the report
+// and overview commands.
+package report
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/langtind/ynabctl/internal/tags"
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// Group-by keys accepted by Spending.
+const (
+	ByCategory = "category"
+	ByPayee    = "payee"
+	ByAccount  = "account"
+	ByTag      = "tag"
+)
+
+// Untagged is the group name used for spending without any memo tag.
+const Untagged = "(untagged)"
+
+// SpendingRow is the total outflow for one group.
+type SpendingRow struct {
+	Name   string `json:"name"`
+	Amount int64  `json:"amount" amount:"milliunits"`
+	Count  int    `json:"count"`
+}
+
+// Spending is an outflow summary grouped by a single dimension.
+type Spending struct {
+	GroupBy   string        `json:"group_by"`
+	StartDate string        `json:"start_date,omitempty"`
+	EndDate   string        `json:"end_date,omitempty"`
+	Total     int64         `json:"total" amount:"milliunits"`
+	Rows      []SpendingRow `json:"rows"`
+}
+
+// line is a single categorised outflow, either a whole transaction or
+// one part of a split.
+type line struct {
+	amount     int64
+	payee      string
+	categoryID string
+	category   string
+	account    string
+	tags       []string
+}
+
+// SpendingBy sums outflows in txns by groupBy. Amounts in the result are
+// positive milliunits. Transfers, inflows and deleted transactions are
+// ignored; split transactions are broken into their parts. Transactions
+// dated after endDate are skipped when endDate is set.
+func SpendingBy(txns []ynab.Transaction, groupBy, startDate, endDate string) (*Spending, error) {
+	switch groupBy {
+	case ByCategory, ByPayee, ByAccount, ByTag:
+	default:
+		return nil, fmt.Errorf("unknown group-by %q (want category|payee|account|tag)", groupBy)
+	}
+
+	totals := map[string]*SpendingRow{}
+	s := &Spending{GroupBy: groupBy, StartDate: startDate, EndDate: endDate, Rows: []SpendingRow{}}
+
+	for _, t := range txns {
+		if t.Deleted || (startDate != "" && t.Date < startDate) || (endDate != "" && t.Date > endDate) {
+			continue
+		}
+		for _, l := range lines(t) {
+			if l.amount >= 0 {
+				continue
+			}
+			var keys []string
+			switch groupBy {
+			case ByCategory:
+				keys = []string{valueOr(l.category, "(uncategorized)")}
+			case ByPayee:
+				keys = []string{valueOr(l.payee, "(no payee)")}
+			case ByAccount:
+				keys = []string{l.account}
+			case ByTag:
+				keys = l.tags
+				if len(keys) == 0 {
+					keys = []string{Untagged}
+				}
+			}
+			for _, k := range keys {
+				row, ok := totals[k]
+				if !ok {
+					row = &SpendingRow{Name: k}
+					totals[k] = row
+				}
+				row.Amount += -l.amount
+				row.Count++
+			}
+			s.Total += -l.amount
+		}
+	}
+
+	for _, row := range totals {
+		s.Rows = append(s.Rows, *row)
+	}
+	sort.Slice(s.Rows, func(i, j int) bool {
+		if s.Rows[i].Amount != s.Rows[j].Amount {
+			return s.Rows[i].Amount > s.Rows[j].Amount
+		}
+		return s.Rows[i].Name < s.Rows[j].Name
+	})
+	return s, nil
+}
+
+func lines(t ynab.Transaction) []line {
+	if t.TransferAccountID != "" {
+		return nil
+	}
+	if len(t.Subtransactions) == 0 {
+		return []line{{
+			amount:     t.Amount,
+			payee:      t.PayeeName,
+			categoryID: t.CategoryID,
+			category:   t.CategoryName,
+			account:    t.AccountName,
+			tags:       tags.Extract(t.Memo),
+		}}
+	}
+
+	var out []line
+	for _, st := range t.Subtransactions {
+		if st.Deleted || st.TransferAccountID != "" {
+			continue
+		}
+		payee := st.PayeeName
+		if payee == "" {
+			payee = t.PayeeName
+		}
+		out = append(out, line{
+			amount:     st.Amount,
+			payee:      payee,
+			categoryID: st.CategoryID,
+			category:   st.CategoryName,
+			account:    t.AccountName,
+			tags:       tags.Extract(t.Memo + " " + st.Memo),
+		})
+	}
+	return out
+}
+
+func valueOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
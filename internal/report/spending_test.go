@@ -0,0 +1,47 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestSpendingByTag(t *testing.T) {
+	txns := []ynab.Transaction{
+		{Date: "2025-07-01", Amount: -100000, Memo: "hotel #vacation2025", CategoryName: "Travel"},
+		{Date: "2025-07-02", Amount: -20000, Memo: "#vacation2025 #food", CategoryName: "Dining"},
+		{Date: "2025-07-03", Amount: -5000, CategoryName: "Dining"},
+		{Date: "2025-07-04", Amount: 50000, Memo: "#vacation2025 refund"},
+		{Date: "2025-07-05", Amount: -70000, TransferAccountID: "savings"},
+		{Date: "2025-07-06", Amount: -30000, Memo: "#food", Subtransactions: []ynab.Subtransaction{
+			{Amount: -10000, CategoryName: "Dining"},
+			{Amount: -20000, CategoryName: "Groceries", Memo: "#party"},
+		}},
+	}
+
+	s, err := SpendingBy(txns, ByTag, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Total != 155000 {
+		t.Errorf("total = %d, want 155000", s.Total)
+	}
+	want := map[string]int64{"vacation2025": 120000, "food": 50000, "party": 20000, Untagged: 5000}
+	if len(s.Rows) != len(want) {
+		t.Fatalf("got %d rows, want %d: %+v", len(s.Rows), len(want), s.Rows)
+	}
+	for _, r := range s.Rows {
+		if want[r.Name] != r.Amount {
+			t.Errorf("%s = %d, want %d", r.Name, r.Amount, want[r.Name])
+		}
+	}
+	if s.Rows[0].Name != "vacation2025" {
+		t.Errorf("rows not sorted by amount: %+v", s.Rows)
+	}
+}
+
+func TestSpendingByUnknown(t *testing.T) {
+	if _, err := SpendingBy(nil, "weekday", "", ""); err == nil {
+		t.Error("expected error for unknown group-by")
+	}
+}
@@ -0,0 +1,80 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// Status is the state of a budget at a glance: money left to budget,
+// transactions waiting for approval or a category, and age of money
+type Status struct {
+	Month         string    `json:"month"`
+	ToBeBudgeted  int64     `json:"to_be_budgeted" amount:"milliunits"`
+	Unapproved    int       `json:"unapproved"`
+	Uncategorized int       `json:"uncategorized"`
+	AgeOfMoney    int       `json:"age_of_money"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ComputeStatus counts the unapproved and uncategorized transactions
+// and takes To Be Budgeted and age of money from month. Only
+// transactions in on-budget accounts count as uncategorized, and
+// transfers never do.
+func ComputeStatus(month *ynab.Month, transactions []ynab.Transaction, accounts []ynab.Account, now time.Time) *Status {
+	onBudget := map[string]bool{}
+	for _, a := range accounts {
+		if a.OnBudget && !a.Deleted {
+			onBudget[a.ID] = true
+		}
+	}
+
+	s := &Status{
+		Month:        month.Month,
+		ToBeBudgeted: month.ToBeBudgeted,
+		AgeOfMoney:   month.AgeOfMoney,
+		UpdatedAt:    now,
+	}
+	for _, t := range transactions {
+		if t.Deleted {
+			continue
+		}
+		if !t.Approved {
+			s.Unapproved++
+		}
+		if onBudget[t.AccountID] && t.TransferAccountID == "" &&
+			(t.CategoryID == "" || t.CategoryName == "Uncategorized") {
+			s.Uncategorized++
+		}
+	}
+	return s
+}
+
+// Short formats the status as one line for a terminal status bar, e.g.
+// "TBB:1 250 | unappr:4 | uncat:2 | AoM:34d". To Be Budgeted is rounded
+// to whole currency units.
+func (s *Status) Short() string {
+	return fmt.Sprintf("TBB:%s | unappr:%d | uncat:%d | AoM:%dd",
+		groupThousands(int64(math.Round(ynab.MilliunitsToAmount(s.ToBeBudgeted)))),
+		s.Unapproved, s.Uncategorized, s.AgeOfMoney)
+}
+
+// groupThousands writes n with a space between groups of three digits
+func groupThousands(n int64) string {
+	sign := ""
+	if n < 0 {
+		sign, n = "-", -n
+	}
+	digits := strconv.FormatInt(n, 10)
+	var groups []string
+	for len(digits) > 3 {
+		groups = append([]string{digits[len(digits)-3:]}, groups...)
+		digits = digits[:len(digits)-3]
+	}
+	groups = append([]string{digits}, groups...)
+	return sign + strings.Join(groups, " ")
+}
@@ -0,0 +1,42 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestComputeStatus(t *testing.T) {
+	month := &ynab.Month{Month: "2025-08-01", ToBeBudgeted: 1250400, AgeOfMoney: 34}
+	accounts := []ynab.Account{
+		{ID: "checking", OnBudget: true},
+		{ID: "house", OnBudget: false},
+	}
+	transactions := []ynab.Transaction{
+		{AccountID: "checking", Approved: true, CategoryID: "food"},
+		{AccountID: "checking", Approved: false, CategoryID: "food"},
+		{AccountID: "checking", Approved: false}, // unapproved and uncategorized
+		{AccountID: "checking", Approved: true, CategoryID: "x", CategoryName: "Uncategorized"},
+		{AccountID: "checking", Approved: true, TransferAccountID: "savings"}, // transfer
+		{AccountID: "house", Approved: true},                                  // tracking account
+		{AccountID: "checking", Approved: false, Deleted: true},
+	}
+
+	s := ComputeStatus(month, transactions, accounts, time.Unix(0, 0))
+	if s.Unapproved != 2 || s.Uncategorized != 2 {
+		t.Errorf("unapproved = %d, uncategorized = %d; want 2, 2", s.Unapproved, s.Uncategorized)
+	}
+	if got := s.Short(); got != "TBB:1 250 | unappr:2 | uncat:2 | AoM:34d" {
+		t.Errorf("Short() = %q", got)
+	}
+}
+
+func TestGroupThousands(t *testing.T) {
+	cases := map[int64]string{0: "0", 999: "999", 1000: "1 000", -1234567: "-1 234 567"}
+	for n, want := range cases {
+		if got := groupThousands(n); got != want {
+			t.Errorf("groupThousands(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
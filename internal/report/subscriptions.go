@@ -0,0 +1,191 @@
+package report
+
+import (
+	"sort"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/names"
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// Subscription is a recurring payment found in the transaction history
+// that has no scheduled transaction yet. Amount is the median payment.
+type Subscription struct {
+	PayeeID      string `json:"payee_id"`
+	PayeeName    string `json:"payee_name"`
+	AccountID    string `json:"account_id"`
+	AccountName  string `json:"account_name"`
+	CategoryID   string `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	Frequency    string `json:"frequency"`
+	Amount       int64  `json:"amount" amount:"milliunits"`
+	Count        int    `json:"count"`
+	FirstDate    string `json:"first_date"`
+	LastDate     string `json:"last_date"`
+	NextDate     string `json:"next_date"`
+}
+
+// cadence is a recurrence the detector recognizes, as a YNAB scheduled
+// frequency with the range of days allowed between payments
+type cadence struct {
+	frequency      string
+	minGap, maxGap int
+	minCount       int
+	next           func(time.Time) time.Time
+}
+
+var cadences = []cadence{
+	{"weekly", 6, 8, 4, func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }},
+	{"monthly", 26, 35, 3, func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }},
+	{"yearly", 350, 380, 2, func(t time.Time) time.Time { return t.AddDate(1, 0, 0) }},
+}
+
+// amountTolerance is how far, as a share of the median, a payment may
+// differ and still count as the same subscription
+const amountTolerance = 0.1
+
+// DetectSubscriptions finds outflows to the same payee with similar
+// amounts at a steady weekly, monthly or yearly rhythm. Payees that
+// already have a scheduled transaction are skipped, and so are series
+// whose last payment is more than one and a half periods before now,
+// as they have likely ended. The result is sorted by payee.
+func DetectSubscriptions(txns []ynab.Transaction, scheduled []ynab.ScheduledTransaction, now time.Time) []Subscription {
+	byPayee := map[string][]ynab.Transaction{}
+	for _, t := range txns {
+		if t.Deleted || t.Amount >= 0 || t.TransferAccountID != "" || t.PayeeName == "" {
+			continue
+		}
+		key := t.PayeeID
+		if key == "" {
+			key = t.PayeeName
+		}
+		byPayee[key] = append(byPayee[key], t)
+	}
+
+	subs := []Subscription{}
+	for _, payeeTxns := range byPayee {
+		if isScheduled(payeeTxns[0], scheduled) {
+			continue
+		}
+		for _, series := range amountClusters(payeeTxns) {
+			if s, ok := recurring(series, now); ok {
+				subs = append(subs, s)
+			}
+		}
+	}
+	sort.Slice(subs, func(i, j int) bool {
+		if subs[i].PayeeName != subs[j].PayeeName {
+			return subs[i].PayeeName < subs[j].PayeeName
+		}
+		return subs[i].Amount < subs[j].Amount
+	})
+	return subs
+}
+
+// isScheduled reports whether a scheduled transaction pays t's payee
+func isScheduled(t ynab.Transaction, scheduled []ynab.ScheduledTransaction) bool {
+	for _, s := range scheduled {
+		if s.Deleted {
+			continue
+		}
+		if (t.PayeeID != "" && s.PayeeID == t.PayeeID) || names.Equal(s.PayeeName, t.PayeeName) {
+			return true
+		}
+	}
+	return false
+}
+
+// amountClusters splits the transactions of a payee into groups of
+// similar amounts, each sorted by date
+func amountClusters(txns []ynab.Transaction) [][]ynab.Transaction {
+	sorted := append([]ynab.Transaction(nil), txns...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Amount > sorted[j].Amount })
+
+	var clusters [][]ynab.Transaction
+	for _, t := range sorted {
+		n := len(clusters)
+		if n > 0 && similar(clusters[n-1][0].Amount, t.Amount) {
+			clusters[n-1] = append(clusters[n-1], t)
+		} else {
+			clusters = append(clusters, []ynab.Transaction{t})
+		}
+	}
+	for _, c := range clusters {
+		sort.SliceStable(c, func(i, j int) bool { return c[i].Date < c[j].Date })
+	}
+	return clusters
+}
+
+// similar reports whether b is within amountTolerance of a
+func similar(a, b int64) bool {
+	diff := a - b
+	if diff < 0 {
+		diff = -diff
+	}
+	if a < 0 {
+		a = -a
+	}
+	return float64(diff) <= float64(a)*amountTolerance
+}
+
+// recurring returns the series as a subscription if its payments follow
+// one of the cadences and it is still active at now
+func recurring(series []ynab.Transaction, now time.Time) (Subscription, bool) {
+	dates := make([]time.Time, 0, len(series))
+	for _, t := range series {
+		d, err := time.Parse("2006-01-02", t.Date)
+		if err != nil {
+			return Subscription{}, false
+		}
+		dates = append(dates, d)
+	}
+
+	for _, c := range cadences {
+		if len(dates) < c.minCount || !steady(dates, c) {
+			continue
+		}
+		last := dates[len(dates)-1]
+		period := c.next(last).Sub(last)
+		if now.Sub(last) > period*3/2 {
+			return Subscription{}, false
+		}
+		next := c.next(last)
+		for next.Before(now.Truncate(24 * time.Hour)) {
+			next = c.next(next)
+		}
+
+		amounts := make([]int64, len(series))
+		for i, t := range series {
+			amounts[i] = t.Amount
+		}
+		sort.Slice(amounts, func(i, j int) bool { return amounts[i] < amounts[j] })
+
+		latest := series[len(series)-1]
+		return Subscription{
+			PayeeID:      latest.PayeeID,
+			PayeeName:    latest.PayeeName,
+			AccountID:    latest.AccountID,
+			AccountName:  latest.AccountName,
+			CategoryID:   latest.CategoryID,
+			CategoryName: latest.CategoryName,
+			Frequency:    c.frequency,
+			Amount:       amounts[len(amounts)/2],
+			Count:        len(series),
+			FirstDate:    series[0].Date,
+			LastDate:     latest.Date,
+			NextDate:     next.Format("2006-01-02"),
+		}, true
+	}
+	return Subscription{}, false
+}
+
+// steady reports whether every gap between consecutive dates fits c
+func steady(dates []time.Time, c cadence) bool {
+	for i := 1; i < len(dates); i++ {
+		gap := int(dates[i].Sub(dates[i-1]).Hours() / 24)
+		if gap < c.minGap || gap > c.maxGap {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,47 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestDetectSubscriptions(t *testing.T) {
+	now := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	txn := func(payee, date string, amount int64) ynab.Transaction {
+		return ynab.Transaction{PayeeID: "p-" + payee, PayeeName: payee, Date: date, Amount: amount, AccountName: "Visa"}
+	}
+	txns := []ynab.Transaction{
+		// monthly, price varies a little
+		txn("StreamFlix", "2026-07-05", -129_000),
+		txn("StreamFlix", "2026-08-05", -129_000),
+		txn("StreamFlix", "2026-09-05", -135_000),
+		txn("StreamFlix", "2026-10-05", -129_000),
+		// groceries: same payee, irregular
+		txn("FreshMart", "2026-09-01", -450_000),
+		txn("FreshMart", "2026-09-03", -120_000),
+		txn("FreshMart", "2026-09-20", -800_000),
+		// monthly, but already scheduled
+		txn("FiberNet", "2026-08-15", -499_000),
+		txn("FiberNet", "2026-09-15", -499_000),
+		txn("FiberNet", "2026-10-15", -499_000),
+		// monthly, but ended in the spring
+		txn("OldGym", "2026-02-10", -300_000),
+		txn("OldGym", "2026-03-10", -300_000),
+		txn("OldGym", "2026-04-10", -300_000),
+	}
+	scheduled := []ynab.ScheduledTransaction{{PayeeName: "FiberNet", Frequency: "monthly"}}
+
+	subs := DetectSubscriptions(txns, scheduled, now)
+	if len(subs) != 1 {
+		t.Fatalf("found %+v, want only StreamFlix", subs)
+	}
+	s := subs[0]
+	if s.PayeeName != "StreamFlix" || s.Frequency != "monthly" || s.Count != 4 {
+		t.Errorf("subscription = %+v, want StreamFlix monthly x4", s)
+	}
+	if s.Amount != -129_000 || s.NextDate != "2026-11-05" {
+		t.Errorf("amount %d next %s, want -129000 and 2026-11-05", s.Amount, s.NextDate)
+	}
+}
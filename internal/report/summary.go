@@ -0,0 +1,212 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// MonthSummary is what happened in a month compared to the month
+// before: where the money went, what is overspent and who was paid for
+// the first time
+type MonthSummary struct {
+	Month         string              `json:"month"`
+	PreviousMonth string              `json:"previous_month"`
+	Income        int64               `json:"income" amount:"milliunits"`
+	Spent         int64               `json:"spent" amount:"milliunits"`
+	PreviousSpent int64               `json:"previous_spent"`
+	TopCategories []SpendingRow       `json:"top_categories"`
+	Overspent     []OverspentCategory `json:"overspent"`
+	Changes       []CategoryChange    `json:"changes"`
+	NewPayees     []SpendingRow       `json:"new_payees"`
+}
+
+// OverspentCategory is a category with a negative balance
+type OverspentCategory struct {
+	Group   string `json:"category_group"`
+	Name    string `json:"name"`
+	Balance int64  `json:"balance" amount:"milliunits"`
+}
+
+// CategoryChange compares the spending in a category with the month
+// before
+type CategoryChange struct {
+	Category string `json:"category"`
+	Spent    int64  `json:"spent" amount:"milliunits"`
+	Previous int64  `json:"previous"`
+	Change   int64  `json:"change" amount:"milliunits"`
+}
+
+// SummarizeMonth builds the summary of month from its budget data and
+// the transactions since some time before the previous month. A payee
+// is new when it has no transaction in txns before the month. Each list
+// is cut to the top entries.
+func SummarizeMonth(month *ynab.Month, txns []ynab.Transaction, top int) (*MonthSummary, error) {
+	start, err := time.Parse("2006-01-02", month.Month)
+	if err != nil {
+		return nil, fmt.Errorf("invalid month %q", month.Month)
+	}
+	prevStart := start.AddDate(0, -1, 0)
+	end := start.AddDate(0, 1, -1).Format("2006-01-02")
+	prevEnd := start.AddDate(0, 0, -1).Format("2006-01-02")
+
+	current, err := SpendingBy(txns, ByCategory, month.Month, end)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := SpendingBy(txns, ByCategory, prevStart.Format("2006-01-02"), prevEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &MonthSummary{
+		Month:         month.Month,
+		PreviousMonth: prevStart.Format("2006-01-02"),
+		Income:        month.Income,
+		Spent:         current.Total,
+		PreviousSpent: previous.Total,
+		TopCategories: firstRows(current.Rows, top),
+		Overspent:     []OverspentCategory{},
+		Changes:       []CategoryChange{},
+		NewPayees:     []SpendingRow{},
+	}
+
+	for _, c := range month.Categories {
+		if !c.Deleted && !c.Hidden && c.Balance < 0 {
+			s.Overspent = append(s.Overspent, OverspentCategory{Group: c.CategoryGroupName, Name: c.Name, Balance: c.Balance})
+		}
+	}
+	sort.SliceStable(s.Overspent, func(i, j int) bool { return s.Overspent[i].Balance < s.Overspent[j].Balance })
+
+	changes := map[string]*CategoryChange{}
+	for _, r := range current.Rows {
+		changes[r.Name] = &CategoryChange{Category: r.Name, Spent: r.Amount}
+	}
+	for _, r := range previous.Rows {
+		c, ok := changes[r.Name]
+		if !ok {
+			c = &CategoryChange{Category: r.Name}
+			changes[r.Name] = c
+		}
+		c.Previous = r.Amount
+	}
+	for _, c := range changes {
+		c.Change = c.Spent - c.Previous
+		if c.Change != 0 {
+			s.Changes = append(s.Changes, *c)
+		}
+	}
+	sort.Slice(s.Changes, func(i, j int) bool {
+		a, b := abs(s.Changes[i].Change), abs(s.Changes[j].Change)
+		if a != b {
+			return a > b
+		}
+		return s.Changes[i].Category < s.Changes[j].Category
+	})
+	if top > 0 && len(s.Changes) > top {
+		s.Changes = s.Changes[:top]
+	}
+
+	seen := map[string]bool{}
+	for _, t := range txns {
+		if !t.Deleted && t.Date < month.Month {
+			seen[t.PayeeName] = true
+		}
+	}
+	payees, err := SpendingBy(txns, ByPayee, month.Month, end)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range payees.Rows {
+		if !seen[r.Name] && r.Name != "(no payee)" {
+			s.NewPayees = append(s.NewPayees, r)
+		}
+	}
+	s.NewPayees = firstRows(s.NewPayees, top)
+
+	return s, nil
+}
+
+// Markdown writes the summary as short prose with lists, to be pasted
+// into a chat or a note
+func (s *MonthSummary) Markdown() string {
+	var b strings.Builder
+	name := monthName(s.Month)
+	prevName := monthName(s.PreviousMonth)
+
+	fmt.Fprintf(&b, "## %s\n\n", name)
+	fmt.Fprintf(&b, "We spent **%s** in %s", money(s.Spent), name)
+	switch {
+	case s.PreviousSpent == 0:
+		b.WriteString(".")
+	case s.Spent == s.PreviousSpent:
+		fmt.Fprintf(&b, ", the same as in %s.", prevName)
+	default:
+		word := "more"
+		if s.Spent < s.PreviousSpent {
+			word = "less"
+		}
+		pct := float64(abs(s.Spent-s.PreviousSpent)) * 100 / float64(s.PreviousSpent)
+		fmt.Fprintf(&b, ", %.0f%% %s than in %s (%s).", pct, word, prevName, money(s.PreviousSpent))
+	}
+	if s.Income > 0 {
+		fmt.Fprintf(&b, " Income was %s.", money(s.Income))
+	}
+	b.WriteString("\n")
+
+	if len(s.TopCategories) > 0 {
+		b.WriteString("\n### Where the money went\n\n")
+		for i, r := range s.TopCategories {
+			fmt.Fprintf(&b, "%d. %s: %s\n", i+1, r.Name, money(r.Amount))
+		}
+	}
+
+	b.WriteString("\n### Overspending\n\n")
+	if len(s.Overspent) == 0 {
+		b.WriteString("Nothing is overspent.\n")
+	}
+	for _, c := range s.Overspent {
+		fmt.Fprintf(&b, "- **%s** is overspent by %s\n", c.Name, money(-c.Balance))
+	}
+
+	if len(s.Changes) > 0 {
+		fmt.Fprintf(&b, "\n### Compared to %s\n\n", prevName)
+		for _, c := range s.Changes {
+			word := "up"
+			if c.Change < 0 {
+				word = "down"
+			}
+			fmt.Fprintf(&b, "- %s: %s, %s %s\n", c.Category, money(c.Spent), word, money(abs(c.Change)))
+		}
+	}
+
+	if len(s.NewPayees) > 0 {
+		b.WriteString("\n### New payees\n\n")
+		for _, r := range s.NewPayees {
+			fmt.Fprintf(&b, "- %s: %s\n", r.Name, money(r.Amount))
+		}
+	}
+	return b.String()
+}
+
+func firstRows(rows []SpendingRow, n int) []SpendingRow {
+	if n > 0 && len(rows) > n {
+		return rows[:n]
+	}
+	return rows
+}
+
+func monthName(month string) string {
+	t, err := time.Parse("2006-01-02", month)
+	if err != nil {
+		return month
+	}
+	return t.Format("January 2006")
+}
+
+func money(milliunits int64) string {
+	return fmt.Sprintf("%.2f", ynab.MilliunitsToAmount(milliunits))
+}
@@ -0,0 +1,62 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestSummarizeMonth(t *testing.T) {
+	month := &ynab.Month{
+		Month:  "2025-03-01",
+		Income: 3000000,
+		Categories: []ynab.Category{
+			{Name: "Dining Out", CategoryGroupName: "Fun", Balance: -45000},
+			{Name: "Groceries", CategoryGroupName: "Food", Balance: 20000},
+			{Name: "Old", Hidden: true, Balance: -1000},
+		},
+	}
+	txns := []ynab.Transaction{
+		{Date: "2025-02-10", Amount: -300000, PayeeName: "Market", CategoryName: "Groceries"},
+		{Date: "2025-02-12", Amount: -50000, PayeeName: "Cafe", CategoryName: "Dining Out"},
+		{Date: "2025-03-03", Amount: -350000, PayeeName: "Market", CategoryName: "Groceries"},
+		{Date: "2025-03-09", Amount: -95000, PayeeName: "Bistro", CategoryName: "Dining Out"},
+		{Date: "2025-03-15", Amount: 3000000, PayeeName: "Employer", CategoryName: "Inflow: Ready to Assign"},
+		{Date: "2025-04-01", Amount: -999000, PayeeName: "Later", CategoryName: "Groceries"},
+	}
+
+	s, err := SummarizeMonth(month, txns, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Spent != 445000 || s.PreviousSpent != 350000 || s.PreviousMonth != "2025-02-01" {
+		t.Errorf("spent = %d, previous = %d (%s)", s.Spent, s.PreviousSpent, s.PreviousMonth)
+	}
+	if len(s.TopCategories) != 2 || s.TopCategories[0].Name != "Groceries" {
+		t.Errorf("top = %+v", s.TopCategories)
+	}
+	if len(s.Overspent) != 1 || s.Overspent[0].Name != "Dining Out" {
+		t.Errorf("overspent = %+v", s.Overspent)
+	}
+	if len(s.Changes) != 2 || s.Changes[0].Category != "Groceries" || s.Changes[0].Change != 50000 {
+		t.Errorf("changes = %+v", s.Changes)
+	}
+	if len(s.NewPayees) != 1 || s.NewPayees[0].Name != "Bistro" {
+		t.Errorf("new payees = %+v", s.NewPayees)
+	}
+
+	md := s.Markdown()
+	for _, want := range []string{
+		"## March 2025",
+		"We spent **445.00** in March 2025, 27% more than in February 2025 (350.00).",
+		"1. Groceries: 350.00",
+		"- **Dining Out** is overspent by 45.00",
+		"- Groceries: 350.00, up 50.00",
+		"- Bistro: 95.00",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown lacks %q:\n%s", want, md)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package report
+
+import (
+	"sort"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// Sources of To Be Budgeted observations
+const (
+	TBBFromSnapshot = "snapshot"
+	TBBFromCurrent  = "current"
+)
+
+// TBB is the To Be Budgeted (Ready to Assign) amount of a month
+type TBB struct {
+	Month        string `json:"month"`
+	ToBeBudgeted int64  `json:"to_be_budgeted" amount:"milliunits"`
+	Income       int64  `json:"income" amount:"milliunits"`
+	Budgeted     int64  `json:"budgeted" amount:"milliunits"`
+}
+
+// NewTBB returns the To Be Budgeted amount of m
+func NewTBB(m *ynab.Month) *TBB {
+	return &TBB{Month: m.Month, ToBeBudgeted: m.ToBeBudgeted, Income: m.Income, Budgeted: m.Budgeted}
+}
+
+// TBBPoint is To Be Budgeted observed on a date
+type TBBPoint struct {
+	Date         string `json:"date"`
+	Month        string `json:"month"`
+	ToBeBudgeted int64  `json:"to_be_budgeted" amount:"milliunits"`
+	Source       string `json:"source"`
+}
+
+// TBBHistory is the history of To Be Budgeted, oldest first
+type TBBHistory struct {
+	Points  []TBBPoint `json:"points"`
+	Current int64      `json:"current_tbb" amount:"milliunits"`
+	Change  int64      `json:"change" amount:"milliunits"`
+	Min     int64      `json:"min_tbb" amount:"milliunits"`
+	Max     int64      `json:"max_tbb" amount:"milliunits"`
+}
+
+// TrendTBB merges observations into a history. When several fall on the
+// same date, the current value wins over snapshots and a later snapshot
+// over an earlier one.
+func TrendTBB(points []TBBPoint) *TBBHistory {
+	byDate := map[string]TBBPoint{}
+	for _, p := range points {
+		if prev, ok := byDate[p.Date]; !ok || prev.Source != TBBFromCurrent {
+			byDate[p.Date] = p
+		}
+	}
+
+	h := &TBBHistory{Points: []TBBPoint{}}
+	for _, p := range byDate {
+		h.Points = append(h.Points, p)
+	}
+	sort.Slice(h.Points, func(i, j int) bool { return h.Points[i].Date < h.Points[j].Date })
+
+	for i, p := range h.Points {
+		if i == 0 || p.ToBeBudgeted < h.Min {
+			h.Min = p.ToBeBudgeted
+		}
+		if i == 0 || p.ToBeBudgeted > h.Max {
+			h.Max = p.ToBeBudgeted
+		}
+	}
+	if n := len(h.Points); n > 0 {
+		h.Current = h.Points[n-1].ToBeBudgeted
+		h.Change = h.Current - h.Points[0].ToBeBudgeted
+	}
+	return h
+}
+
+// TBBBreach is a month whose To Be Budgeted would go negative
+type TBBBreach struct {
+	Month    string
+	Before   int64
+	Assigned int64
+	After    int64
+}
+
+// CheckTBB returns the first month, in order, where assigning more money
+// would leave To Be Budgeted negative. tbb holds the current amount of
+// each month and assigned the increase of its budgeted total. Months
+// where money is only taken back are never a breach.
+func CheckTBB(tbb, assigned map[string]int64) *TBBBreach {
+	var months []string
+	for m := range assigned {
+		months = append(months, m)
+	}
+	sort.Strings(months)
+	for _, m := range months {
+		if assigned[m] <= 0 {
+			continue
+		}
+		if after := tbb[m] - assigned[m]; after < 0 {
+			return &TBBBreach{Month: m, Before: tbb[m], Assigned: assigned[m], After: after}
+		}
+	}
+	return nil
+}
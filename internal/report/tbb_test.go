@@ -0,0 +1,35 @@
+package report
+
+import "testing"
+
+func TestTrendTBB(t *testing.T) {
+	h := TrendTBB([]TBBPoint{
+		{Date: "2026-03-02", ToBeBudgeted: 5000, Source: TBBFromSnapshot},
+		{Date: "2026-03-01", ToBeBudgeted: 12000, Source: TBBFromSnapshot},
+		{Date: "2026-03-02", ToBeBudgeted: -1000, Source: TBBFromCurrent},
+		{Date: "2026-03-02", ToBeBudgeted: 7000, Source: TBBFromSnapshot},
+	})
+	if len(h.Points) != 2 {
+		t.Fatalf("points = %+v", h.Points)
+	}
+	if h.Points[1].Source != TBBFromCurrent {
+		t.Errorf("the current value should win: %+v", h.Points[1])
+	}
+	if h.Current != -1000 || h.Change != -13000 || h.Min != -1000 || h.Max != 12000 {
+		t.Errorf("history = %+v", h)
+	}
+}
+
+func TestCheckTBB(t *testing.T) {
+	tbb := map[string]int64{"2026-03-01": 10000, "2026-04-01": 0}
+	if b := CheckTBB(tbb, map[string]int64{"2026-03-01": 10000}); b != nil {
+		t.Errorf("assigning all of it is fine: %+v", b)
+	}
+	if b := CheckTBB(tbb, map[string]int64{"2026-04-01": -5000}); b != nil {
+		t.Errorf("taking money back is fine: %+v", b)
+	}
+	b := CheckTBB(tbb, map[string]int64{"2026-03-01": 12000, "2026-04-01": 1})
+	if b == nil || b.Month != "2026-03-01" || b.After != -2000 {
+		t.Errorf("breach = %+v", b)
+	}
+}
@@ -0,0 +1,143 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// Week is one slice of a month in a Weekly report. The first and last
+// weeks are cut at the month boundaries, so they may be shorter.
+type Week struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+	Days  int    `json:"days"`
+}
+
+// WeeklyRow is one category's spending per week next to its monthly
+// budget prorated over the days of each week
+type WeeklyRow struct {
+	Group    string  `json:"category_group"`
+	Category string  `json:"category"`
+	Budgeted int64   `json:"budgeted" amount:"milliunits"`
+	Spent    []int64 `json:"spent"`
+	Prorated []int64 `json:"prorated"`
+}
+
+// Weekly slices a month's spending into weeks (or two-week periods)
+type Weekly struct {
+	Month    string      `json:"month"`
+	StartDay string      `json:"start_day"`
+	Weeks    []Week      `json:"weeks"`
+	Rows     []WeeklyRow `json:"rows"`
+}
+
+// ParseWeekday accepts a weekday name such as "monday" or "Mon"
+func ParseWeekday(s string) (time.Weekday, error) {
+	lower := strings.ToLower(s)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if len(lower) >= 3 && strings.HasPrefix(strings.ToLower(d.String()), lower) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid weekday %q", s)
+}
+
+// SplitMonth cuts the month starting at monthStart (YYYY-MM-DD) into
+// periods of length days (7 or 14) that begin on startDay. The first
+// period runs from the 1st to the day before the first startDay.
+func SplitMonth(monthStart string, startDay time.Weekday, length int) ([]Week, error) {
+	first, err := time.Parse("2006-01-02", monthStart)
+	if err != nil {
+		return nil, fmt.Errorf("invalid month %q", monthStart)
+	}
+	last := first.AddDate(0, 1, -1)
+
+	var weeks []Week
+	start := first
+	end := first.AddDate(0, 0, (int(startDay)-int(first.Weekday())+7)%7-1)
+	if end.Before(start) {
+		end = start.AddDate(0, 0, length-1)
+	}
+	for !start.After(last) {
+		if end.After(last) {
+			end = last
+		}
+		weeks = append(weeks, Week{
+			Start: start.Format("2006-01-02"),
+			End:   end.Format("2006-01-02"),
+			Days:  int(end.Sub(start).Hours()/24) + 1,
+		})
+		start = end.AddDate(0, 0, 1)
+		end = start.AddDate(0, 0, length-1)
+	}
+	return weeks, nil
+}
+
+// WeeklySpending builds a Weekly report for month from its categories and
+// the transactions of the month. Categories are listed in the month's
+// order when they have a budget or spending; hidden and deleted ones are
+// left out. Inflows and transfers are not spending.
+func WeeklySpending(month *ynab.Month, txns []ynab.Transaction, startDay time.Weekday, length int) (*Weekly, error) {
+	weeks, err := SplitMonth(month.Month, startDay, length)
+	if err != nil {
+		return nil, err
+	}
+	w := &Weekly{Month: month.Month, StartDay: startDay.String(), Weeks: weeks, Rows: []WeeklyRow{}}
+
+	spent := map[string][]int64{}
+	for _, t := range txns {
+		if t.Deleted {
+			continue
+		}
+		i := weekIndex(weeks, t.Date)
+		if i < 0 {
+			continue
+		}
+		for _, l := range lines(t) {
+			if l.amount >= 0 || l.categoryID == "" {
+				continue
+			}
+			if spent[l.categoryID] == nil {
+				spent[l.categoryID] = make([]int64, len(weeks))
+			}
+			spent[l.categoryID][i] += -l.amount
+		}
+	}
+
+	daysInMonth := 0
+	for _, wk := range weeks {
+		daysInMonth += wk.Days
+	}
+	for _, c := range month.Categories {
+		if c.Deleted || c.Hidden || (c.Budgeted == 0 && spent[c.ID] == nil) {
+			continue
+		}
+		row := WeeklyRow{
+			Group:    c.CategoryGroupName,
+			Category: c.Name,
+			Budgeted: c.Budgeted,
+			Spent:    spent[c.ID],
+			Prorated: make([]int64, len(weeks)),
+		}
+		if row.Spent == nil {
+			row.Spent = make([]int64, len(weeks))
+		}
+		for i, wk := range weeks {
+			row.Prorated[i] = c.Budgeted * int64(wk.Days) / int64(daysInMonth)
+		}
+		w.Rows = append(w.Rows, row)
+	}
+	return w, nil
+}
+
+func weekIndex(weeks []Week, date string) int {
+	for i, wk := range weeks {
+		if date >= wk.Start && date <= wk.End {
+			return i
+		}
+	}
+	return -1
+}
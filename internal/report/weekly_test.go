@@ -0,0 +1,75 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+func TestSplitMonth(t *testing.T) {
+	// January 2025 starts on a Wednesday
+	weeks, err := SplitMonth("2025-01-01", time.Monday, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Week{
+		{"2025-01-01", "2025-01-05", 5},
+		{"2025-01-06", "2025-01-12", 7},
+		{"2025-01-13", "2025-01-19", 7},
+		{"2025-01-20", "2025-01-26", 7},
+		{"2025-01-27", "2025-01-31", 5},
+	}
+	if len(weeks) != len(want) {
+		t.Fatalf("weeks = %+v", weeks)
+	}
+	for i := range want {
+		if weeks[i] != want[i] {
+			t.Errorf("week %d = %+v, want %+v", i, weeks[i], want[i])
+		}
+	}
+
+	weeks, _ = SplitMonth("2025-01-01", time.Wednesday, 14)
+	if len(weeks) != 3 || weeks[0].Days != 14 || weeks[2].Days != 3 {
+		t.Errorf("biweekly = %+v", weeks)
+	}
+}
+
+func TestParseWeekday(t *testing.T) {
+	for _, s := range []string{"monday", "Mon", "MONDAY"} {
+		if d, err := ParseWeekday(s); err != nil || d != time.Monday {
+			t.Errorf("%s = %v, %v", s, d, err)
+		}
+	}
+	if _, err := ParseWeekday("mo"); err == nil {
+		t.Error("two letters should be ambiguous")
+	}
+}
+
+func TestWeeklySpending(t *testing.T) {
+	month := &ynab.Month{Month: "2025-02-01", Categories: []ynab.Category{
+		{ID: "food", Name: "Groceries", Budgeted: 280000},
+		{ID: "fun", Name: "Fun", Budgeted: 0},
+		{ID: "gone", Name: "Old", Budgeted: 1000, Hidden: true},
+	}}
+	txns := []ynab.Transaction{
+		{Date: "2025-02-03", Amount: -50000, CategoryID: "food"},
+		{Date: "2025-02-04", Amount: -10000, CategoryID: "food"},
+		{Date: "2025-02-20", Amount: 5000, CategoryID: "food"},         // refund, not spending
+		{Date: "2025-02-10", Amount: -90000, TransferAccountID: "sav"}, // transfer
+		{Date: "2025-03-01", Amount: -1000, CategoryID: "food"},        // next month
+	}
+
+	// February 2025 starts on a Saturday and has exactly 28 days
+	w, err := WeeklySpending(month, txns, time.Saturday, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(w.Weeks) != 4 || len(w.Rows) != 1 {
+		t.Fatalf("weeks %d rows %+v", len(w.Weeks), w.Rows)
+	}
+	r := w.Rows[0]
+	if r.Spent[0] != 60000 || r.Spent[2] != 0 || r.Prorated[0] != 70000 {
+		t.Errorf("row = %+v", r)
+	}
+}
@@ -0,0 +1,163 @@
+// Package reqfile reads request files: YAML mappings of flag names to
+// values, so a long create or update command can be written down,
+// reviewed and kept under version control.
+//
+//	account: Checking
+//	amount: -42.50
+//	payee_name: Grocery Store
+//	memo: "weekly shop #food"
+//
+// Keys may use underscores or dashes. A list sets a repeatable flag
+// once per element. Commands that create several records at once also
+// accept a list of such mappings (see ParseBatch).
+package reqfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrEmpty is returned for a request file without any request in it
+var ErrEmpty = errors.New("request file is empty")
+
+// Field is one entry of a request file
+type Field struct {
+	// Name is the flag name, with underscores turned into dashes
+	Name string
+	// Values holds the raw text of the value, or of each list element
+	Values []string
+	Line   int
+}
+
+// Load reads the request file at path; "-" reads standard input
+func Load(path string) ([]Field, error) {
+	data, err := read(path)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return fields, nil
+}
+
+// LoadBatch reads a request file holding either one request or a list
+// of them. list reports whether the file held a list.
+func LoadBatch(path string) (requests [][]Field, list bool, err error) {
+	data, err := read(path)
+	if err != nil {
+		return nil, false, err
+	}
+	requests, list, err = ParseBatch(data)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", path, err)
+	}
+	return requests, list, nil
+}
+
+func read(path string) ([]byte, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request file: %w", err)
+	}
+	return data, nil
+}
+
+// Parse decodes a request file in the order the keys are written.
+// Scalars are kept as written, so dates and amounts are not reformatted
+// by the YAML decoder.
+func Parse(data []byte) ([]Field, error) {
+	root, err := parseRoot(data)
+	if err != nil {
+		return nil, err
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: expected a mapping of flag names to values", root.Line)
+	}
+	return parseMapping(root)
+}
+
+// ParseBatch decodes a request file holding one request (a mapping) or
+// a list of requests (a sequence of mappings)
+func ParseBatch(data []byte) (requests [][]Field, list bool, err error) {
+	root, err := parseRoot(data)
+	if err != nil {
+		return nil, false, err
+	}
+	if root.Kind != yaml.SequenceNode {
+		fields, err := Parse(data)
+		if err != nil {
+			return nil, false, err
+		}
+		return [][]Field{fields}, false, nil
+	}
+	for _, item := range root.Content {
+		if item.Kind != yaml.MappingNode {
+			return nil, true, fmt.Errorf("line %d: expected a mapping of flag names to values", item.Line)
+		}
+		fields, err := parseMapping(item)
+		if err != nil {
+			return nil, true, err
+		}
+		requests = append(requests, fields)
+	}
+	if len(requests) == 0 {
+		return nil, true, ErrEmpty
+	}
+	return requests, true, nil
+}
+
+// parseRoot returns the top node of a YAML document, or ErrEmpty for
+// a document holding nothing but whitespace and comments
+func parseRoot(data []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, ErrEmpty
+	}
+	return doc.Content[0], nil
+}
+
+// parseMapping decodes one mapping of flag names to values
+func parseMapping(root *yaml.Node) ([]Field, error) {
+	var fields []Field
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		f := Field{Name: strings.ReplaceAll(strings.TrimSpace(key.Value), "_", "-"), Line: key.Line}
+		if seen[f.Name] {
+			return nil, fmt.Errorf("line %d: %s is given twice", key.Line, key.Value)
+		}
+		seen[f.Name] = true
+
+		switch value.Kind {
+		case yaml.ScalarNode:
+			f.Values = []string{value.Value}
+		case yaml.SequenceNode:
+			for _, item := range value.Content {
+				if item.Kind != yaml.ScalarNode {
+					return nil, fmt.Errorf("line %d: %s must be a list of plain values", item.Line, key.Value)
+				}
+				f.Values = append(f.Values, item.Value)
+			}
+		default:
+			return nil, fmt.Errorf("line %d: %s must be a value or a list of values", value.Line, key.Value)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
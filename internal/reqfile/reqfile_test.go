@@ -0,0 +1,91 @@
+package reqfile
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	fields, err := Parse([]byte(`account: Checking
+date: 2025-07-01
+amount: -42.50
+payee_name: Grocery Store
+approved: true
+memo: ""
+category:
+  - Groceries
+  - Household
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Field{
+		{Name: "account", Values: []string{"Checking"}, Line: 1},
+		{Name: "date", Values: []string{"2025-07-01"}, Line: 2},
+		{Name: "amount", Values: []string{"-42.50"}, Line: 3},
+		{Name: "payee-name", Values: []string{"Grocery Store"}, Line: 4},
+		{Name: "approved", Values: []string{"true"}, Line: 5},
+		{Name: "memo", Values: []string{""}, Line: 6},
+		{Name: "category", Values: []string{"Groceries", "Household"}, Line: 7},
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("got %+v\nwant %+v", fields, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, doc := range []string{
+		"- a\n- b\n",
+		"memo: a\nmemo: b\n",
+		"payee_name: a\npayee-name: b\n",
+		"account:\n  name: Checking\n",
+		"category:\n  - [a, b]\n",
+		"account: [unclosed\n",
+	} {
+		if _, err := Parse([]byte(doc)); err == nil {
+			t.Errorf("%q: expected an error", doc)
+		}
+	}
+
+	for _, doc := range []string{"", "# nothing yet\n"} {
+		if _, err := Parse([]byte(doc)); !errors.Is(err, ErrEmpty) {
+			t.Errorf("%q: got %v, want ErrEmpty", doc, err)
+		}
+	}
+}
+
+func TestParseBatch(t *testing.T) {
+	requests, list, err := ParseBatch([]byte(`- name: Checking
+  type: checking
+  balance: 1000
+- name: Savings
+  type: savings
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !list || len(requests) != 2 {
+		t.Fatalf("got %d requests (list %v)", len(requests), list)
+	}
+	if got := requests[1][0]; got.Name != "name" || got.Values[0] != "Savings" || got.Line != 4 {
+		t.Errorf("second request starts with %+v", got)
+	}
+
+	requests, list, err = ParseBatch([]byte("name: Checking\n"))
+	if err != nil || list || len(requests) != 1 {
+		t.Errorf("single mapping: got %v, %v, %v", requests, list, err)
+	}
+
+	for _, doc := range []string{"- a\n- b\n", "- name: a\n  name: b\n"} {
+		if _, _, err := ParseBatch([]byte(doc)); err == nil {
+			t.Errorf("%q: expected an error", doc)
+		}
+	}
+
+	for _, doc := range []string{"", "# accounts to create\n", "[]\n"} {
+		if _, _, err := ParseBatch([]byte(doc)); !errors.Is(err, ErrEmpty) {
+			t.Errorf("%q: got %v, want ErrEmpty", doc, err)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Package respcache keeps the API's GET responses on disk, one file per
+// token and request path, so read commands can run offline (--offline)
+// from what was last fetched.
+package respcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Store is a directory of saved responses. It implements
+// ynab.ResponseStore.
+type Store struct {
+	dir string
+	// owner tells apart the responses fetched with different tokens, as
+	// paths like /user and /budgets do not name the account
+	owner string
+}
+
+// entry is the file saved for one request path
+type entry struct {
+	Path      string          `json:"path"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	Body      json.RawMessage `json:"body"`
+}
+
+// Dir returns the default store directory in the user cache directory
+// (e.g. ~/.cache/ynabctl/responses)
+func Dir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "ynabctl", "responses")
+}
+
+// New returns the store in dir
+func New(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// ForToken returns the store of the responses fetched with token. The
+// token itself is not saved, only a hash of it.
+func (s *Store) ForToken(token string) *Store {
+	sum := sha256.Sum256([]byte(token))
+	return &Store{dir: s.dir, owner: hex.EncodeToString(sum[:8])}
+}
+
+// file returns the file of a request path
+func (s *Store) file(path string) string {
+	sum := sha256.Sum256([]byte(s.owner + "\n" + path))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+// Load returns the saved response of path and when it was fetched. A
+// missing or unreadable file is reported as not saved.
+func (s *Store) Load(path string) ([]byte, time.Time, bool) {
+	data, err := os.ReadFile(s.file(path))
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil || e.Path != path {
+		return nil, time.Time{}, false
+	}
+	return e.Body, e.FetchedAt, true
+}
+
+// Store saves the response of path as fetched now
+func (s *Store) Store(path string, body []byte) error {
+	if !json.Valid(body) {
+		return fmt.Errorf("response of %s is not JSON", path)
+	}
+	data, err := json.Marshal(entry{Path: path, FetchedAt: time.Now().UTC(), Body: body})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("mkdir %s: %w", s.dir, err)
+	}
+	// write and rename, so a concurrent Load never sees half a file
+	tmp, err := os.CreateTemp(s.dir, "*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.file(path))
+}
+
+// Invalidate removes the saved responses of paths starting with prefix,
+// e.g. "/budgets/<id>" after a change to that budget, whatever token
+// fetched them, as budgets can be shared. It implements
+// ynab.ResponseInvalidator.
+func (s *Store) Invalidate(prefix string) error {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		var e struct {
+			Path string `json:"path"`
+		}
+		if json.Unmarshal(data, &e) == nil && strings.HasPrefix(e.Path, prefix) {
+			if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Clear removes every saved response and returns how many there were
+func (s *Store) Clear() (int, error) {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return 0, err
+	}
+	for _, f := range files {
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			return 0, err
+		}
+	}
+	return len(files), nil
+}
@@ -0,0 +1,84 @@
+package respcache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStoreAndLoad(t *testing.T) {
+	s := New(t.TempDir())
+	if _, _, ok := s.Load("/budgets/b1/payees"); ok {
+		t.Fatal("empty store returned a response")
+	}
+
+	before := time.Now().Add(-time.Second)
+	body := []byte(`{"data":{"payees":[]}}`)
+	if err := s.Store("/budgets/b1/payees", body); err != nil {
+		t.Fatal(err)
+	}
+	got, fetchedAt, ok := s.Load("/budgets/b1/payees")
+	if !ok || string(got) != string(body) {
+		t.Errorf("Load = %s, %v", got, ok)
+	}
+	if fetchedAt.Before(before) {
+		t.Errorf("fetchedAt = %v, want about now", fetchedAt)
+	}
+	if _, _, ok := s.Load("/budgets/b2/payees"); ok {
+		t.Error("another path returned a response")
+	}
+
+	if err := s.Store("/user", []byte("not json")); err == nil {
+		t.Error("expected an error for a body that is not JSON")
+	}
+}
+
+func TestInvalidateAndClear(t *testing.T) {
+	s := New(t.TempDir())
+	for _, path := range []string{"/budgets/b1/payees", "/budgets/b1/accounts", "/budgets/b2/payees", "/user"} {
+		if err := s.Store(path, []byte(`{}`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := s.Invalidate("/budgets/b1"); err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]bool{"/budgets/b1/payees": false, "/budgets/b1/accounts": false, "/budgets/b2/payees": true, "/user": true} {
+		if _, _, ok := s.Load(path); ok != want {
+			t.Errorf("after Invalidate, %s saved = %t", path, ok)
+		}
+	}
+
+	n, err := s.Clear()
+	if err != nil || n != 2 {
+		t.Errorf("Clear = %d, %v", n, err)
+	}
+	if _, _, ok := s.Load("/user"); ok {
+		t.Error("/user still saved after Clear")
+	}
+}
+
+func TestForToken(t *testing.T) {
+	dir := t.TempDir()
+	a, b := New(dir).ForToken("token-a"), New(dir).ForToken("token-b")
+	if err := a.Store("/budgets", []byte(`{"data":{"budgets":[]}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := b.Load("/budgets"); ok {
+		t.Error("a response fetched with another token was returned")
+	}
+	if _, _, ok := New(dir).ForToken("token-a").Load("/budgets"); !ok {
+		t.Error("the response is not returned for the same token")
+	}
+
+	// a change to a budget invalidates it for every token
+	if err := b.Store("/budgets/b1/payees", []byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Invalidate("/budgets/b1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := b.Load("/budgets/b1/payees"); ok {
+		t.Error("Invalidate kept another token's response")
+	}
+}
@@ -0,0 +1,317 @@
+// Package schedplan reads and writes scheduled transaction plans: YAML
+// files declaring the scheduled transactions a budget should have, so
+// recurring bills can be kept in version control and applied.
+package schedplan
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/langtind/ynabctl/internal/names"
+	"github.com/langtind/ynabctl/pkg/ynab"
+	"gopkg.in/yaml.v3"
+)
+
+// Plan is the content of a scheduled transaction plan file. Amounts are
+// in currency units.
+type Plan struct {
+	BudgetID  string  `yaml:"budget_id,omitempty"`
+	Scheduled []Entry `yaml:"scheduled"`
+}
+
+// Entry is a scheduled transaction. It is matched with an existing one
+// by ID if given, otherwise by account and payee. Account and category
+// are names or IDs. Date is only used when the entry is created.
+type Entry struct {
+	ID        string  `yaml:"id,omitempty"`
+	Account   string  `yaml:"account"`
+	Payee     string  `yaml:"payee"`
+	Category  string  `yaml:"category,omitempty"`
+	Amount    float64 `yaml:"amount"`
+	Frequency string  `yaml:"frequency"`
+	Date      string  `yaml:"date,omitempty"`
+	Memo      string  `yaml:"memo,omitempty"`
+	Flag      string  `yaml:"flag,omitempty"`
+}
+
+// Load reads a plan file. Frequencies are normalized, and entries that
+// would match the same scheduled transaction are an error.
+func Load(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Plan
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	seen := map[string]bool{}
+	for i := range p.Scheduled {
+		e := &p.Scheduled[i]
+		if e.Account == "" || e.Payee == "" {
+			return nil, fmt.Errorf("%s: entry %d needs an account and a payee", path, i+1)
+		}
+		if e.Frequency, err = ynab.ParseFrequency(e.Frequency); err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", path, e.label(), err)
+		}
+		key := e.ID
+		if key == "" {
+			key = names.Fold(e.Account) + "\x00" + names.Fold(e.Payee)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("%s: %s is listed twice; give the entries an id to tell them apart", path, e.label())
+		}
+		seen[key] = true
+	}
+	return &p, nil
+}
+
+// Marshal renders the plan as YAML
+func (p *Plan) Marshal() ([]byte, error) {
+	return yaml.Marshal(p)
+}
+
+// Write saves the plan as YAML
+func (p *Plan) Write(path string) error {
+	data, err := p.Marshal()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Export builds a plan from the budget's scheduled transactions. Deleted
+// ones are left out; each entry starts at the next date of its schedule.
+func Export(budgetID string, scheduled []ynab.ScheduledTransaction) *Plan {
+	p := &Plan{BudgetID: budgetID}
+	for _, s := range scheduled {
+		if s.Deleted {
+			continue
+		}
+		p.Scheduled = append(p.Scheduled, Entry{
+			ID:        s.ID,
+			Account:   s.AccountName,
+			Payee:     s.PayeeName,
+			Category:  s.CategoryName,
+			Amount:    ynab.MilliunitsToAmount(s.Amount),
+			Frequency: s.Frequency,
+			Date:      s.DateNext,
+			Memo:      s.Memo,
+			Flag:      s.FlagColor,
+		})
+	}
+	return p
+}
+
+func (e Entry) label() string {
+	return e.Payee + " (" + e.Account + ")"
+}
+
+// Change actions
+const (
+	Create = "create"
+	Update = "update"
+	Delete = "delete"
+)
+
+// Field is one field of a change. Before is empty for a created
+// scheduled transaction and After for a deleted one.
+type Field struct {
+	Name   string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// Change is one scheduled transaction to create, update or delete. ID is
+// empty for a create. Save is what is sent to YNAB for a create or
+// update.
+type Change struct {
+	Action  string                        `json:"action"`
+	ID      string                        `json:"id,omitempty"`
+	Payee   string                        `json:"payee"`
+	Account string                        `json:"account"`
+	Fields  []Field                       `json:"fields"`
+	Save    ynab.SaveScheduledTransaction `json:"-"`
+}
+
+// Diff compares the plan with the budget's scheduled transactions.
+// Missing ones are created and drifted ones updated; with prune, those
+// not in the plan are deleted. Accounts and categories that do not
+// exist are an error, so typos are not silently skipped. The date of a
+// new entry defaults to today and must not lie in the past.
+func Diff(p *Plan, current []ynab.ScheduledTransaction, accounts []ynab.Account, groups []ynab.CategoryGroup, prune bool, today time.Time) ([]Change, error) {
+	changes := []Change{}
+	matched := map[string]bool{}
+	for _, e := range p.Scheduled {
+		account, err := findAccount(accounts, e.Account)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.label(), err)
+		}
+		category := ynab.Category{}
+		if e.Category != "" {
+			if category, err = findCategory(groups, e.Category); err != nil {
+				return nil, fmt.Errorf("%s: %w", e.label(), err)
+			}
+		}
+
+		existing, err := match(current, e, account.ID)
+		if err != nil {
+			return nil, err
+		}
+		save := ynab.SaveScheduledTransaction{
+			AccountID:  account.ID,
+			Frequency:  e.Frequency,
+			Amount:     toMilliunits(e.Amount),
+			PayeeName:  e.Payee,
+			CategoryID: category.ID,
+			Memo:       e.Memo,
+			FlagColor:  e.Flag,
+		}
+
+		if existing == nil {
+			save.Date = e.Date
+			if save.Date == "" {
+				save.Date = today.Format("2006-01-02")
+			}
+			if err := ynab.ValidateSchedule(save.Date, save.Frequency, today); err != nil {
+				return nil, fmt.Errorf("%s: %w", e.label(), err)
+			}
+			c := Change{Action: Create, Payee: e.Payee, Account: account.Name, Save: save}
+			c.add("account", "", account.Name)
+			c.add("payee", "", e.Payee)
+			c.add("category", "", category.Name)
+			c.add("amount", "", formatAmount(save.Amount))
+			c.add("frequency", "", save.Frequency)
+			c.add("date", "", save.Date)
+			c.add("memo", "", save.Memo)
+			c.add("flag", "", save.FlagColor)
+			changes = append(changes, c)
+			continue
+		}
+
+		matched[existing.ID] = true
+		if len(existing.Subtransactions) > 0 {
+			return nil, fmt.Errorf("%s: %s is a split scheduled transaction, which cannot be applied from a plan", e.label(), existing.ID)
+		}
+		// YNAB replaces the whole scheduled transaction, so unchanged
+		// fields are sent as they are
+		save.Date = existing.DateFirst
+		if names.Equal(existing.PayeeName, e.Payee) {
+			save.PayeeID, save.PayeeName = existing.PayeeID, ""
+		}
+		c := Change{Action: Update, ID: existing.ID, Payee: existing.PayeeName, Account: existing.AccountName, Save: save}
+		if save.AccountID != existing.AccountID {
+			c.add("account", existing.AccountName, account.Name)
+		}
+		if save.PayeeName != "" {
+			c.add("payee", existing.PayeeName, e.Payee)
+		}
+		if save.CategoryID != existing.CategoryID {
+			c.add("category", existing.CategoryName, category.Name)
+		}
+		c.add("amount", formatAmount(existing.Amount), formatAmount(save.Amount))
+		c.add("frequency", existing.Frequency, save.Frequency)
+		c.add("memo", existing.Memo, save.Memo)
+		c.add("flag", existing.FlagColor, save.FlagColor)
+		if len(c.Fields) > 0 {
+			changes = append(changes, c)
+		}
+	}
+
+	if prune {
+		for _, s := range current {
+			if s.Deleted || matched[s.ID] {
+				continue
+			}
+			c := Change{Action: Delete, ID: s.ID, Payee: s.PayeeName, Account: s.AccountName}
+			c.add("amount", formatAmount(s.Amount), "")
+			c.add("frequency", s.Frequency, "")
+			c.add("next date", s.DateNext, "")
+			changes = append(changes, c)
+		}
+	}
+	return changes, nil
+}
+
+// add records a field of c if before and after differ
+func (c *Change) add(name, before, after string) {
+	if before != after {
+		c.Fields = append(c.Fields, Field{Name: name, Before: before, After: after})
+	}
+}
+
+// match finds the scheduled transaction an entry describes: the one with
+// its ID, or the one in account paying its payee
+func match(current []ynab.ScheduledTransaction, e Entry, accountID string) (*ynab.ScheduledTransaction, error) {
+	var found *ynab.ScheduledTransaction
+	for i, s := range current {
+		if s.Deleted {
+			continue
+		}
+		if e.ID != "" {
+			if s.ID == e.ID {
+				return &current[i], nil
+			}
+			continue
+		}
+		if s.AccountID == accountID && names.Equal(s.PayeeName, e.Payee) {
+			if found != nil {
+				return nil, fmt.Errorf("%s matches several scheduled transactions (%s, %s); add the id of one to the plan", e.label(), found.ID, s.ID)
+			}
+			found = &current[i]
+		}
+	}
+	if e.ID != "" {
+		return nil, fmt.Errorf("%s: scheduled transaction %s not found", e.label(), e.ID)
+	}
+	return found, nil
+}
+
+// findAccount looks an open account up by ID or name
+func findAccount(accounts []ynab.Account, ref string) (ynab.Account, error) {
+	for _, a := range accounts {
+		if !a.Deleted && !a.Closed && (a.ID == ref || names.Equal(a.Name, ref)) {
+			return a, nil
+		}
+	}
+	return ynab.Account{}, fmt.Errorf("account %q not found", ref)
+}
+
+// findCategory looks a category up by ID, name, or "Group: Name"
+func findCategory(groups []ynab.CategoryGroup, ref string) (ynab.Category, error) {
+	var found []ynab.Category
+	for _, g := range groups {
+		if g.Deleted {
+			continue
+		}
+		for _, c := range g.Categories {
+			if c.Deleted {
+				continue
+			}
+			if c.ID == ref {
+				return c, nil
+			}
+			if names.Equal(c.Name, ref) || strings.EqualFold(g.Name+": "+c.Name, ref) {
+				found = append(found, c)
+			}
+		}
+	}
+	switch len(found) {
+	case 0:
+		return ynab.Category{}, fmt.Errorf("category %q not found", ref)
+	case 1:
+		return found[0], nil
+	}
+	return ynab.Category{}, fmt.Errorf("category %q is ambiguous; write it as \"Group: Name\"", ref)
+}
+
+func formatAmount(milliunits int64) string {
+	return fmt.Sprintf("%.2f", ynab.MilliunitsToAmount(milliunits))
+}
+
+func toMilliunits(amount float64) int64 {
+	return ynab.AmountToMilliunits(amount)
+}
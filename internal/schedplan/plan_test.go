@@ -0,0 +1,126 @@
+package schedplan
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+var today = time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+func testBudget() ([]ynab.ScheduledTransaction, []ynab.Account, []ynab.CategoryGroup) {
+	scheduled := []ynab.ScheduledTransaction{
+		{ID: "s1", DateFirst: "2024-01-01", DateNext: "2025-04-01", Frequency: "monthly", Amount: -1000000,
+			AccountID: "chk", AccountName: "Checking", PayeeID: "p1", PayeeName: "Landlord",
+			CategoryID: "rent", CategoryName: "Rent"},
+		{ID: "s2", DateFirst: "2024-06-15", DateNext: "2025-06-15", Frequency: "yearly", Amount: -99000,
+			AccountID: "chk", AccountName: "Checking", PayeeID: "p2", PayeeName: "Gym"},
+	}
+	accounts := []ynab.Account{{ID: "chk", Name: "Checking"}, {ID: "cc", Name: "Visa"}}
+	groups := []ynab.CategoryGroup{
+		{Name: "Bills", Categories: []ynab.Category{{ID: "rent", Name: "Rent"}, {ID: "net", Name: "Internet"}}},
+	}
+	return scheduled, accounts, groups
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	scheduled, accounts, groups := testBudget()
+	p := Export("b1", scheduled)
+	if len(p.Scheduled) != 2 {
+		t.Fatalf("plan = %+v", p)
+	}
+
+	path := filepath.Join(t.TempDir(), "scheduled.yaml")
+	if err := p.Write(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := Diff(loaded, scheduled, accounts, groups, true, today)
+	if err != nil || len(changes) != 0 {
+		t.Errorf("unchanged plan has changes %+v, %v", changes, err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	scheduled, accounts, groups := testBudget()
+	p := &Plan{Scheduled: []Entry{
+		{Account: "checking", Payee: "landlord", Category: "Bills: Rent", Amount: -1050, Frequency: "monthly"},
+		{Account: "Visa", Payee: "ISP", Category: "internet", Amount: -49.9, Frequency: "monthly", Date: "2025-03-20"},
+	}}
+
+	changes, err := Diff(p, scheduled, accounts, groups, false, today)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("changes = %+v", changes)
+	}
+
+	update := changes[0]
+	if update.Action != Update || update.ID != "s1" || len(update.Fields) != 1 || update.Fields[0].Name != "amount" {
+		t.Errorf("update = %+v", update)
+	}
+	if update.Save.PayeeID != "p1" || update.Save.Date != "2024-01-01" || update.Save.Amount != -1050000 {
+		t.Errorf("update sends %+v", update.Save)
+	}
+
+	create := changes[1]
+	if create.Action != Create || create.Save.AccountID != "cc" || create.Save.CategoryID != "net" ||
+		create.Save.PayeeName != "ISP" || create.Save.Date != "2025-03-20" || create.Save.Amount != -49900 {
+		t.Errorf("create = %+v", create)
+	}
+
+	changes, err = Diff(p, scheduled, accounts, groups, true, today)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 3 || changes[2].Action != Delete || changes[2].ID != "s2" {
+		t.Errorf("pruned changes = %+v", changes)
+	}
+}
+
+func TestDiffErrors(t *testing.T) {
+	scheduled, accounts, groups := testBudget()
+	tests := []struct {
+		entry Entry
+		want  string
+	}{
+		{Entry{Account: "Savings", Payee: "X", Frequency: "monthly"}, "account"},
+		{Entry{Account: "Checking", Payee: "X", Category: "Food", Frequency: "monthly"}, "category"},
+		{Entry{ID: "gone", Account: "Checking", Payee: "X", Frequency: "monthly"}, "not found"},
+		{Entry{Account: "Checking", Payee: "X", Frequency: "monthly", Date: "2025-01-01"}, "past"},
+	}
+	for _, tt := range tests {
+		_, err := Diff(&Plan{Scheduled: []Entry{tt.entry}}, scheduled, accounts, groups, false, today)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Diff(%+v) error = %v, want %q", tt.entry, err, tt.want)
+		}
+	}
+}
+
+func TestLoadRejectsDuplicates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scheduled.yaml")
+	data := `scheduled:
+  - account: Checking
+    payee: Gym
+    amount: -10
+    frequency: every-other-week
+  - account: checking
+    payee: GYM
+    amount: -20
+    frequency: monthly
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "twice") {
+		t.Errorf("Load error = %v, want duplicate entry", err)
+	}
+}
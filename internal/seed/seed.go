@@ -0,0 +1,221 @@
+// Package seed generates realistic fake budget data: accounts, category
+// groups, payees and a few months of transactions. It is used to fill
+// test budgets, so automations can be tried without risking real data.
+package seed
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// ImportPrefix starts the import_id of every seeded transaction, so they
+// can be removed with "transactions purge --import-prefix"
+const ImportPrefix = "YNABCTL-SEED:"
+
+// InflowCategory is the category income is assigned to
+const InflowCategory = "Inflow: Ready to Assign"
+
+// Account is an account to create, with its starting balance in
+// milliunits
+type Account struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Balance int64  `json:"balance" amount:"milliunits"`
+}
+
+// Group is a category group and its categories
+type Group struct {
+	Name       string   `json:"name"`
+	Categories []string `json:"categories"`
+}
+
+// Transaction is a transaction to create. Income has the category
+// InflowCategory and no group.
+type Transaction struct {
+	Account  string `json:"account"`
+	Date     string `json:"date"`
+	Amount   int64  `json:"amount" amount:"milliunits"`
+	Payee    string `json:"payee"`
+	Group    string `json:"group,omitempty"`
+	Category string `json:"category"`
+	Memo     string `json:"memo,omitempty"`
+	Cleared  bool   `json:"cleared"`
+	Approved bool   `json:"approved"`
+	ImportID string `json:"import_id"`
+}
+
+// Data is everything a seed run creates
+type Data struct {
+	Seed         int64         `json:"seed"`
+	Accounts     []Account     `json:"accounts"`
+	Groups       []Group       `json:"groups"`
+	Transactions []Transaction `json:"transactions"`
+}
+
+// Summary is the outcome of a seed run
+type Summary struct {
+	Seed         int64  `json:"seed"`
+	Accounts     int    `json:"accounts"`
+	Categories   int    `json:"categories"`
+	Transactions int    `json:"transactions"`
+	Duplicates   int    `json:"duplicates"`
+	FirstDate    string `json:"first_date"`
+	LastDate     string `json:"last_date"`
+	ImportPrefix string `json:"import_prefix"`
+}
+
+// Accounts every seeded budget gets
+const (
+	checking = "Seed Checking"
+	savings  = "Seed Savings"
+	card     = "Seed Visa"
+)
+
+var accounts = []Account{
+	{Name: checking, Type: "checking", Balance: 25_000_000},
+	{Name: savings, Type: "savings", Balance: 60_000_000},
+	{Name: card, Type: "creditCard", Balance: -1_500_000},
+}
+
+// bill is a transaction repeated on the same day every month
+type bill struct {
+	day            int
+	group, cat     string
+	payee, account string
+	amount         int64
+}
+
+var bills = []bill{
+	{1, "Seed Bills", "Rent", "Oakwood Properties", checking, -12_000_000},
+	{10, "Seed Bills", "Electricity", "Northern Power", checking, -900_000},
+	{15, "Seed Bills", "Internet", "FiberNet", card, -499_000},
+	{20, "Seed Bills", "Phone", "MobileCo", card, -299_000},
+	{25, "", InflowCategory, "Acme Corp Payroll", checking, 42_000_000},
+}
+
+// spend is a kind of everyday purchase, in currency units
+type spend struct {
+	group, cat string
+	payees     []string
+	min, max   int64
+	weight     int
+}
+
+var spends = []spend{
+	{"Seed Everyday", "Groceries", []string{"FreshMart", "Corner Grocery", "SuperSave", "Farmers Market"}, 80, 1400, 10},
+	{"Seed Everyday", "Eating Out", []string{"Pizza Place", "Sushi Bar", "Cafe Central", "Burger Barn"}, 60, 900, 5},
+	{"Seed Everyday", "Transport", []string{"City Transit", "Fuel Station", "Taxi Now"}, 40, 800, 4},
+	{"Seed Fun", "Entertainment", []string{"Cinema City", "StreamFlix", "Concert Hall"}, 99, 700, 2},
+	{"Seed Fun", "Shopping", []string{"Bookstore", "Outdoor Store", "Electronics Hub", "Online Marketplace"}, 150, 3500, 3},
+}
+
+// tags are memo hashtags given to a few transactions
+var tags = []string{"#vacation", "#gift", "#work", "#kids"}
+
+// Generate returns n transactions spread over the months ending at end,
+// with the accounts and categories they use. The same seed gives the
+// same data.
+func Generate(seed int64, n, months int, end time.Time) *Data {
+	rng := rand.New(rand.NewSource(seed))
+	end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	start := time.Date(end.Year(), end.Month()-time.Month(months-1), 1, 0, 0, 0, 0, time.UTC)
+
+	d := &Data{Seed: seed, Accounts: accounts, Groups: groups()}
+
+	var txns []Transaction
+	for m := start; !m.After(end); m = m.AddDate(0, 1, 0) {
+		for _, b := range bills {
+			date := m.AddDate(0, 0, b.day-1)
+			if date.After(end) {
+				continue
+			}
+			txns = append(txns, Transaction{
+				Account: b.account, Date: date.Format("2006-01-02"), Amount: b.amount,
+				Payee: b.payee, Group: b.group, Category: b.cat,
+			})
+		}
+	}
+
+	days := int(end.Sub(start).Hours()/24) + 1
+	for len(txns) < n {
+		s := pickSpend(rng)
+		account := checking
+		if rng.Intn(10) < 3 {
+			account = card
+		}
+		t := Transaction{
+			Account:  account,
+			Date:     start.AddDate(0, 0, rng.Intn(days)).Format("2006-01-02"),
+			Amount:   -(s.min + rng.Int63n(s.max-s.min+1)) * 1000,
+			Payee:    s.payees[rng.Intn(len(s.payees))],
+			Group:    s.group,
+			Category: s.cat,
+		}
+		if rng.Intn(20) == 0 {
+			t.Memo = tags[rng.Intn(len(tags))]
+		}
+		txns = append(txns, t)
+	}
+	if len(txns) > n {
+		txns = txns[:n]
+	}
+
+	sort.SliceStable(txns, func(i, j int) bool { return txns[i].Date < txns[j].Date })
+	recent := end.AddDate(0, 0, -5).Format("2006-01-02")
+	for i := range txns {
+		t := &txns[i]
+		t.Cleared = t.Date < recent
+		t.Approved = t.Cleared || rng.Intn(2) == 0
+		t.ImportID = fmt.Sprintf("%s%d:%d", ImportPrefix, seed%1_000_000, i)
+	}
+	d.Transactions = txns
+	return d
+}
+
+// pickSpend returns a random kind of purchase, by weight
+func pickSpend(rng *rand.Rand) spend {
+	total := 0
+	for _, s := range spends {
+		total += s.weight
+	}
+	w := rng.Intn(total)
+	for _, s := range spends {
+		if w < s.weight {
+			return s
+		}
+		w -= s.weight
+	}
+	return spends[len(spends)-1]
+}
+
+// groups returns the category groups used by bills and spends
+func groups() []Group {
+	var gs []Group
+	index := map[string]int{}
+	add := func(group, cat string) {
+		if group == "" {
+			return
+		}
+		i, ok := index[group]
+		if !ok {
+			i = len(gs)
+			index[group] = i
+			gs = append(gs, Group{Name: group})
+		}
+		for _, c := range gs[i].Categories {
+			if c == cat {
+				return
+			}
+		}
+		gs[i].Categories = append(gs[i].Categories, cat)
+	}
+	for _, b := range bills {
+		add(b.group, b.cat)
+	}
+	for _, s := range spends {
+		add(s.group, s.cat)
+	}
+	return gs
+}
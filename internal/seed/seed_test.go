@@ -0,0 +1,67 @@
+package seed
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerate(t *testing.T) {
+	end := time.Date(2026, 3, 18, 0, 0, 0, 0, time.UTC)
+	d := Generate(42, 200, 3, end)
+	if len(d.Transactions) != 200 {
+		t.Fatalf("got %d transactions, want 200", len(d.Transactions))
+	}
+	if !reflect.DeepEqual(d, Generate(42, 200, 3, end)) {
+		t.Error("the same seed should give the same data")
+	}
+
+	categories := map[string]bool{InflowCategory: true}
+	for _, g := range d.Groups {
+		for _, c := range g.Categories {
+			categories[g.Name+"/"+c] = true
+		}
+	}
+	accounts := map[string]bool{}
+	for _, a := range d.Accounts {
+		accounts[a.Name] = true
+	}
+
+	ids := map[string]bool{}
+	salaries := 0
+	for i, txn := range d.Transactions {
+		if txn.Date < "2026-01-01" || txn.Date > "2026-03-18" {
+			t.Errorf("%s is outside the three months", txn.Date)
+		}
+		if i > 0 && txn.Date < d.Transactions[i-1].Date {
+			t.Error("transactions are not sorted by date")
+		}
+		key := txn.Group + "/" + txn.Category
+		if txn.Group == "" {
+			key = txn.Category
+		}
+		if !categories[key] || !accounts[txn.Account] {
+			t.Errorf("unknown category or account in %+v", txn)
+		}
+		if txn.Category == InflowCategory {
+			salaries++
+		} else if txn.Amount >= 0 {
+			t.Errorf("spending should be an outflow: %+v", txn)
+		}
+		if !strings.HasPrefix(txn.ImportID, ImportPrefix) || len(txn.ImportID) > 36 || ids[txn.ImportID] {
+			t.Errorf("bad import_id %q", txn.ImportID)
+		}
+		ids[txn.ImportID] = true
+	}
+	if salaries != 2 {
+		t.Errorf("got %d salaries, want 2 (the 25th of March is still ahead)", salaries)
+	}
+}
+
+func TestGenerateFewerThanBills(t *testing.T) {
+	d := Generate(1, 3, 12, time.Date(2026, 3, 18, 0, 0, 0, 0, time.UTC))
+	if len(d.Transactions) != 3 {
+		t.Errorf("got %d transactions, want 3", len(d.Transactions))
+	}
+}
@@ -0,0 +1,254 @@
+// Package slugs gives accounts, categories and payees short, stable
+// identifiers such as "groceries" or "visa-credit".
+//
+// A slug is made from the name the first time a record is seen and then
+// kept, so scripts using it survive both renames in YNAB and the
+// records' UUIDs being unknown to the user. Slugs are unique per budget
+// and kind, and persisted in a JSON file.
+package slugs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/langtind/ynabctl/internal/names"
+)
+
+// Kinds that get slugs
+const (
+	Account  = "account"
+	Category = "category"
+	Payee    = "payee"
+)
+
+// Entry is the slug of one record
+type Entry struct {
+	BudgetID string `json:"budget_id"`
+	Kind     string `json:"kind"`
+	ID       string `json:"id"`
+	Slug     string `json:"slug"`
+}
+
+// Store holds the slugs of all budgets, backed by a JSON file. Methods
+// are safe for concurrent use and on a nil Store.
+type Store struct {
+	path  string
+	mu    sync.Mutex
+	byID  map[string]Entry
+	taken map[string]string // budget, kind and slug to ID
+	dirty bool
+}
+
+// Path returns the default store location next to the config file
+func Path(configDir string) string {
+	return filepath.Join(configDir, "slugs.json")
+}
+
+// New returns an empty store that saves to path
+func New(path string) *Store {
+	return &Store{path: path, byID: map[string]Entry{}, taken: map[string]string{}}
+}
+
+// Load reads the store at path. A missing file yields an empty store.
+func Load(path string) (*Store, error) {
+	s := New(path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return s, err
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return s, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for _, e := range entries {
+		s.add(e)
+	}
+	return s, nil
+}
+
+// Save writes the store back to its file if anything changed
+func (s *Store) Save() error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.dirty {
+		return nil
+	}
+
+	entries := make([]Entry, 0, len(s.byID))
+	for _, e := range s.byID {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.BudgetID != b.BudgetID {
+			return a.BudgetID < b.BudgetID
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Slug < b.Slug
+	})
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("mkdir %s: %w", filepath.Dir(s.path), err)
+	}
+	if err := os.WriteFile(s.path, data, 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", s.path, err)
+	}
+	s.dirty = false
+	return nil
+}
+
+// Assign returns the slug of a record, making one from its name if it
+// has none yet. A slug already taken in the budget gets a number, e.g.
+// "groceries-2". Kinds without slugs return "".
+func (s *Store) Assign(budgetID, kind, id, name string) string {
+	if s == nil || id == "" || !hasSlugs(kind) {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.byID[id]; ok {
+		return e.Slug
+	}
+	base := Make(name)
+	if base == "" {
+		base = kind
+	}
+	slug := base
+	for n := 2; s.taken[key(budgetID, kind, slug)] != ""; n++ {
+		slug = base + "-" + strconv.Itoa(n)
+	}
+	s.add(Entry{BudgetID: budgetID, Kind: kind, ID: id, Slug: slug})
+	s.dirty = true
+	return slug
+}
+
+// Set gives a record a chosen slug, replacing the one it had. The slug
+// must be valid and not taken by another record of the same kind.
+func (s *Store) Set(budgetID, kind, id, slug string) error {
+	if !Valid(slug) {
+		return fmt.Errorf("invalid slug %q: use lowercase letters, digits and dashes", slug)
+	}
+	if !hasSlugs(kind) {
+		return fmt.Errorf("%ss have no slugs", kind)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if other := s.taken[key(budgetID, kind, slug)]; other != "" && other != id {
+		return fmt.Errorf("slug %q is taken by %s %s", slug, kind, other)
+	}
+	if old, ok := s.byID[id]; ok {
+		delete(s.taken, key(old.BudgetID, old.Kind, old.Slug))
+	}
+	s.add(Entry{BudgetID: budgetID, Kind: kind, ID: id, Slug: slug})
+	s.dirty = true
+	return nil
+}
+
+// Slug returns the slug of id, or "" if it has none
+func (s *Store) Slug(id string) string {
+	if s == nil {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.byID[id].Slug
+}
+
+// Lookup returns the ID of the record of kind with slug in a budget
+func (s *Store) Lookup(budgetID, kind, slug string) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.taken[key(budgetID, kind, slug)]
+	return id, id != ""
+}
+
+// Entries returns the slugs of a budget, sorted by kind and slug
+func (s *Store) Entries(budgetID string) []Entry {
+	entries := []Entry{}
+	if s == nil {
+		return entries
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.byID {
+		if e.BudgetID == budgetID {
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Kind != entries[j].Kind {
+			return entries[i].Kind < entries[j].Kind
+		}
+		return entries[i].Slug < entries[j].Slug
+	})
+	return entries
+}
+
+func (s *Store) add(e Entry) {
+	s.byID[e.ID] = e
+	s.taken[key(e.BudgetID, e.Kind, e.Slug)] = e.ID
+}
+
+func key(budgetID, kind, slug string) string {
+	return budgetID + "/" + kind + "/" + slug
+}
+
+func hasSlugs(kind string) bool {
+	return kind == Account || kind == Category || kind == Payee
+}
+
+// transliterations of letters common in budget names
+var transliterations = strings.NewReplacer(
+	"æ", "ae", "ø", "o", "å", "a", "ä", "a", "ö", "o", "ü", "u", "ß", "ss",
+	"é", "e", "è", "e", "ê", "e", "á", "a", "à", "a", "í", "i", "ó", "o", "ú", "u", "ñ", "n", "ç", "c",
+	"&", " and ",
+)
+
+// Make turns a name into a slug: emojis removed, letters lowercased and
+// transliterated to ASCII, everything else collapsed into single dashes.
+// "🛒 Groceries & Food" becomes "groceries-and-food".
+func Make(name string) string {
+	name = transliterations.Replace(strings.ToLower(names.StripEmoji(name)))
+	var b strings.Builder
+	dash := false
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+var validSlug = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Valid reports whether s has the form of a slug
+func Valid(s string) bool {
+	return validSlug.MatchString(s)
+}
@@ -0,0 +1,80 @@
+package slugs
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestMake(t *testing.T) {
+	tests := map[string]string{
+		"🛒 Groceries":        "groceries",
+		"Visa (Credit)":      "visa-credit",
+		"Bil & Båt":          "bil-and-bat",
+		"  Kids' allowance ": "kids-allowance",
+		"Strøm":              "strom",
+		"🎉":                  "",
+	}
+	for name, want := range tests {
+		if got := Make(name); got != want {
+			t.Errorf("Make(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestAssignIsStable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slugs.json")
+	s := New(path)
+
+	if got := s.Assign("b1", Category, "c1", "Groceries"); got != "groceries" {
+		t.Errorf("slug = %q, want groceries", got)
+	}
+	if got := s.Assign("b1", Category, "c2", "🛒 groceries"); got != "groceries-2" {
+		t.Errorf("second slug = %q, want groceries-2", got)
+	}
+	if got := s.Assign("b1", Account, "a1", "Groceries"); got != "groceries" {
+		t.Errorf("account slug = %q, want groceries (slugs are per kind)", got)
+	}
+	if got := s.Assign("b1", "transaction", "t1", "x"); got != "" {
+		t.Errorf("transaction slug = %q, want none", got)
+	}
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// renamed in YNAB: the slug stays
+	if got := loaded.Assign("b1", Category, "c1", "Food"); got != "groceries" {
+		t.Errorf("slug after rename = %q, want groceries", got)
+	}
+	if id, ok := loaded.Lookup("b1", Category, "groceries-2"); !ok || id != "c2" {
+		t.Errorf("Lookup = %q, %t", id, ok)
+	}
+	if _, ok := loaded.Lookup("b2", Category, "groceries"); ok {
+		t.Error("slug found in another budget")
+	}
+}
+
+func TestSet(t *testing.T) {
+	s := New("")
+	s.Assign("b1", Category, "c1", "Groceries")
+	s.Assign("b1", Category, "c2", "Food")
+
+	if err := s.Set("b1", Category, "c2", "groceries"); err == nil {
+		t.Error("Set took a slug in use")
+	}
+	if err := s.Set("b1", Category, "c2", "Not Valid"); err == nil {
+		t.Error("Set accepted an invalid slug")
+	}
+	if err := s.Set("b1", Category, "c2", "mat"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Lookup("b1", Category, "food"); ok {
+		t.Error("old slug still resolves")
+	}
+	if s.Slug("c2") != "mat" {
+		t.Errorf("slug = %q, want mat", s.Slug("c2"))
+	}
+}
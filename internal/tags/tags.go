@@ -0,0 +1,95 @@
+// Package tags treats hashtags in transaction memos ("#vacation2025")
+// as lightweight tags, since YNAB has no native tag support.
+package tags
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// tagChars are the characters a tag is made of; anything else ends it
+const tagChars = `[\p{L}\p{N}_-]`
+
+var (
+	reTag  = regexp.MustCompile(`(?:^|\s)#(` + tagChars + `+)`)
+	reName = regexp.MustCompile(`^` + tagChars + `+$`)
+)
+
+// Extract returns the tags in memo, lowercased and without the leading
+// "#", in order of first appearance.
+func Extract(memo string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, m := range reTag.FindAllStringSubmatch(memo, -1) {
+		tag := strings.ToLower(m[1])
+		if !seen[tag] {
+			seen[tag] = true
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+// Has reports whether memo carries tag. Matching is case-insensitive
+// and a leading "#" on tag is optional.
+func Has(memo, tag string) bool {
+	tag = Normalize(tag)
+	for _, t := range Extract(memo) {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Valid reports whether tag, with or without its "#", only has letters,
+// digits, "_" and "-", so it is found in a memo again once added.
+func Valid(tag string) bool {
+	return reName.MatchString(Normalize(tag))
+}
+
+// Add appends "#tag" to memo unless it is already present or not Valid.
+func Add(memo, tag string) string {
+	tag = Normalize(tag)
+	if !Valid(tag) || Has(memo, tag) {
+		return memo
+	}
+	memo = strings.TrimSpace(memo)
+	if memo == "" {
+		return "#" + tag
+	}
+	return memo + " #" + tag
+}
+
+// Remove strips every occurrence of "#tag" from memo, as Extract finds
+// them, together with the space before it (or after it, at the start of
+// the memo). The rest of memo is kept as it is, so a memo without the
+// tag is returned unchanged.
+func Remove(memo, tag string) string {
+	tag = Normalize(tag)
+	var b strings.Builder
+	last := 0
+	for _, m := range reTag.FindAllStringSubmatchIndex(memo, -1) {
+		if strings.ToLower(memo[m[2]:m[3]]) != tag {
+			continue
+		}
+		hash, end := m[2]-1, m[3]
+		before := strings.TrimRightFunc(memo[last:hash], unicode.IsSpace)
+		if before == "" && b.Len() == 0 {
+			end = len(memo) - len(strings.TrimLeftFunc(memo[end:], unicode.IsSpace))
+		}
+		b.WriteString(before)
+		last = end
+	}
+	if last == 0 {
+		return memo
+	}
+	b.WriteString(memo[last:])
+	return b.String()
+}
+
+// Normalize lowercases tag and strips a leading "#".
+func Normalize(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
@@ -0,0 +1,59 @@
+package tags
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	cases := []struct {
+		memo string
+		want []string
+	}{
+		{"", nil},
+		{"dinner #Vacation2025", []string{"vacation2025"}},
+		{"#work lunch #client-x #work", []string{"work", "client-x"}},
+		{"issue#42 is not a tag", nil},
+	}
+	for _, c := range cases {
+		if got := Extract(c.memo); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Extract(%q) = %v, want %v", c.memo, got, c.want)
+		}
+	}
+}
+
+func TestAddRemove(t *testing.T) {
+	if got := Add("dinner", "#trip"); got != "dinner #trip" {
+		t.Errorf("Add: got %q", got)
+	}
+	if got := Add("dinner #Trip", "trip"); got != "dinner #Trip" {
+		t.Errorf("Add existing: got %q", got)
+	}
+	if got := Add("", "trip"); got != "#trip" {
+		t.Errorf("Add to empty: got %q", got)
+	}
+	if got := Add("dinner", "food!"); got != "dinner" {
+		t.Errorf("Add invalid: got %q", got)
+	}
+
+	for _, c := range []struct{ memo, tag, want string }{
+		{"#trip dinner #TRIP out", "trip", "dinner out"},
+		{"Lunch #food, Bob", "food", "Lunch, Bob"},
+		{"#trip #trip", "trip", ""},
+		{"dinner #trip", "#trip", "dinner"},
+		{"#tripod #trip-2 a  b", "trip", "#tripod #trip-2 a  b"},
+		{"a  b", "trip", "a  b"},
+	} {
+		if got := Remove(c.memo, c.tag); got != c.want {
+			t.Errorf("Remove(%q) = %q, want %q", c.memo, got, c.want)
+		}
+	}
+}
+
+func TestValid(t *testing.T) {
+	for tag, want := range map[string]bool{"#trip": true, "vacation-2025": true, "mat_ut": true, "reise": true, "food!": false, "two words": false, "#": false} {
+		if got := Valid(tag); got != want {
+			t.Errorf("Valid(%q) = %v, want %v", tag, got, want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Package trust parses trusted-payee lists used to auto-approve
+// imported transactions.
+//
+// A list has one payee per line. Blank lines and lines starting with #
+// are ignored. A payee may carry its own outflow limit after a "|":
+//
+//	Netflix
+//	Spotify | 15
+//	REMA 1000 | 800
+package trust
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/langtind/ynabctl/pkg/ynab"
+)
+
+// Entry is a single trusted payee. MaxAmount is in milliunits; zero
+// means the payee has no limit of its own.
+type Entry struct {
+	Payee     string
+	MaxAmount int64
+}
+
+// List is a set of trusted payees keyed by normalized name.
+type List struct {
+	entries map[string]Entry
+}
+
+// Load reads a trust list from a file.
+func Load(path string) (*List, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse reads a trust list from r.
+func Parse(r io.Reader) (*List, error) {
+	l := &List{entries: map[string]Entry{}}
+	sc := bufio.NewScanner(r)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		e := Entry{Payee: line}
+		if name, limit, ok := strings.Cut(line, "|"); ok {
+			e.Payee = strings.TrimSpace(name)
+			amount, err := strconv.ParseFloat(strings.TrimSpace(limit), 64)
+			if err != nil || amount < 0 {
+				return nil, fmt.Errorf("line %d: invalid amount %q", lineNo, strings.TrimSpace(limit))
+			}
+			e.MaxAmount = ynab.AmountToMilliunits(amount)
+		}
+		if e.Payee == "" {
+			return nil, fmt.Errorf("line %d: missing payee name", lineNo)
+		}
+		l.entries[normalize(e.Payee)] = e
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Len returns the number of trusted payees.
+func (l *List) Len() int {
+	return len(l.entries)
+}
+
+// Check reports whether a transaction with the given payee name and
+// milliunit amount may be approved. globalMax (milliunits, zero for no
+// limit) applies on top of any per-payee limit. When the transaction is
+// not trusted, reason explains why.
+func (l *List) Check(payee string, amount, globalMax int64) (ok bool, reason string) {
+	e, found := l.entries[normalize(payee)]
+	if !found {
+		return false, "payee not trusted"
+	}
+	abs := amount
+	if abs < 0 {
+		abs = -abs
+	}
+	if e.MaxAmount > 0 && abs > e.MaxAmount {
+		return false, fmt.Sprintf("amount %.2f exceeds payee limit %.2f", float64(abs)/1000, float64(e.MaxAmount)/1000)
+	}
+	if globalMax > 0 && abs > globalMax {
+		return false, fmt.Sprintf("amount %.2f exceeds --max-amount %.2f", float64(abs)/1000, float64(globalMax)/1000)
+	}
+	return true, ""
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
@@ -0,0 +1,54 @@
+package trust
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	l, err := Parse(strings.NewReader(`
+# subscriptions
+Netflix
+Spotify | 15
+Coffee | 2.01
+  rema   1000 | 800
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Len() != 4 {
+		t.Fatalf("got %d entries, want 4", l.Len())
+	}
+
+	cases := []struct {
+		payee     string
+		amount    int64
+		globalMax int64
+		want      bool
+	}{
+		{"Netflix", -129000, 0, true},
+		{"netflix", -129000, 100000, false},
+		{"Spotify", -14990, 0, true},
+		{"Spotify", -15010, 0, false},
+		{"Coffee", -2010, 0, true},
+		{"Coffee", -2020, 0, false},
+		{"REMA 1000", -500000, 0, true},
+		{"REMA 1000", -500000, 300000, false},
+		{"Unknown Shop", -1000, 0, false},
+	}
+	for _, c := range cases {
+		got, reason := l.Check(c.payee, c.amount, c.globalMax)
+		if got != c.want {
+			t.Errorf("Check(%q, %d, %d) = %v (%s), want %v", c.payee, c.amount, c.globalMax, got, reason, c.want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	if _, err := Parse(strings.NewReader("Netflix | abc")); err == nil {
+		t.Error("expected error for bad amount")
+	}
+	if _, err := Parse(strings.NewReader(" | 10")); err == nil {
+		t.Error("expected error for missing payee")
+	}
+}
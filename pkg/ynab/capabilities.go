@@ -0,0 +1,125 @@
+package ynab
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Capability describes what the API returned for one probed endpoint
+type Capability struct {
+	Endpoint      string   `json:"endpoint"`
+	Path          string   `json:"path"`
+	Available     bool     `json:"available"`
+	Error         string   `json:"error,omitempty"`
+	Records       int      `json:"records"`
+	NewFields     []string `json:"new_fields,omitempty"`
+	MissingFields []string `json:"missing_fields,omitempty"`
+}
+
+// probe is an endpoint checked by ProbeCapabilities
+type probe struct {
+	name    string
+	path    string
+	dataKey string
+	record  interface{}
+}
+
+// ProbeCapabilities calls each read endpoint once and compares the
+// fields in the response with the fields this client knows about.
+// NewFields are returned by the API but not decoded; MissingFields are
+// expected but absent from every returned record.
+func (c *Client) ProbeCapabilities(budgetID string) []Capability {
+	probes := []probe{
+		{"user", "/user", "user", User{}},
+		{"budgets", "/budgets", "budgets", Budget{}},
+	}
+	if budgetID != "" {
+		b := "/budgets/" + budgetID
+		probes = append(probes,
+			probe{"budget settings", b + "/settings", "settings", BudgetSettings{}},
+			probe{"accounts", b + "/accounts", "accounts", Account{}},
+			probe{"categories", b + "/categories", "category_groups", CategoryGroup{}},
+			probe{"payees", b + "/payees", "payees", Payee{}},
+			probe{"months", b + "/months", "months", Month{}},
+			probe{"month detail", b + "/months/current", "month", Month{}},
+			probe{"transactions", b + "/transactions?type=unapproved", "transactions", Transaction{}},
+			probe{"scheduled transactions", b + "/scheduled_transactions", "scheduled_transactions", ScheduledTransaction{}},
+		)
+	}
+
+	caps := make([]Capability, 0, len(probes))
+	for _, p := range probes {
+		caps = append(caps, c.probe(p))
+	}
+	return caps
+}
+
+func (c *Client) probe(p probe) Capability {
+	capability := Capability{Endpoint: p.name, Path: p.path}
+
+	body, err := c.doRequest("GET", p.path, nil)
+	if err != nil {
+		capability.Error = err.Error()
+		return capability
+	}
+	capability.Available = true
+
+	var resp struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		capability.Error = fmt.Sprintf("failed to parse response: %v", err)
+		return capability
+	}
+
+	var records []map[string]json.RawMessage
+	raw := resp.Data[p.dataKey]
+	if err := json.Unmarshal(raw, &records); err != nil {
+		var single map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &single); err != nil {
+			capability.Error = fmt.Sprintf("unexpected shape for %q", p.dataKey)
+			return capability
+		}
+		records = []map[string]json.RawMessage{single}
+	}
+	capability.Records = len(records)
+	if len(records) == 0 {
+		return capability
+	}
+
+	known := jsonFields(reflect.TypeOf(p.record))
+	seen := map[string]bool{}
+	for _, r := range records {
+		for k := range r {
+			seen[k] = true
+		}
+	}
+	for k := range seen {
+		if !known[k] {
+			capability.NewFields = append(capability.NewFields, k)
+		}
+	}
+	for k := range known {
+		if !seen[k] {
+			capability.MissingFields = append(capability.MissingFields, k)
+		}
+	}
+	sort.Strings(capability.NewFields)
+	sort.Strings(capability.MissingFields)
+	return capability
+}
+
+// jsonFields returns the JSON names of the top-level fields of struct t
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
+}
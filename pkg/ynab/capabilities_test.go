@@ -0,0 +1,14 @@
+package ynab
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJSONFields(t *testing.T) {
+	got := jsonFields(reflect.TypeOf(Payee{}))
+	want := map[string]bool{"id": true, "name": true, "transfer_account_id": true, "deleted": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("jsonFields(Payee) = %v, want %v", got, want)
+	}
+}
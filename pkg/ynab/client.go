@@ -0,0 +1,1635 @@
+package ynab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBaseURL is the address of the YNAB API
+const DefaultBaseURL = "https://api.ynab.com/v1"
+
+// Client handles communication with the YNAB API. It is safe for
+// concurrent use.
+type Client struct {
+	*state
+
+	// ctx is the context of every request; see WithContext
+	ctx context.Context
+
+	// fresh makes reads skip the memo; see Fresh
+	fresh bool
+}
+
+// state is shared by a Client and the copies made by WithContext
+type state struct {
+	httpClient *http.Client
+	token      string
+	baseURL    string
+
+	// memo holds GET response bodies by path for the lifetime of the
+	// client, so one invocation never fetches the same path twice
+	memoMu sync.Mutex
+	memo   map[string][]byte
+
+	mutationsMu sync.Mutex
+	mutations   []Mutation
+
+	names NameRecorder
+
+	rateMu    sync.Mutex
+	rateUsed  int
+	rateLimit int
+	pacer     Pacer
+
+	writeGuard func(budgetID string) error
+
+	decodeWarn func(*DecodeWarning)
+	// partialMu guards the fields responses could not be decoded for, by
+	// record and field and by the kind of record they stop writes to
+	partialMu        sync.Mutex
+	partial          map[string]*DecodeWarning
+	partialResources map[string]*DecodeWarning
+
+	store    ResponseStore
+	offline  bool
+	cacheTTL time.Duration
+
+	// asOfMu guards asOf, the fetch time of the oldest response served
+	// from the store
+	asOfMu sync.Mutex
+	asOf   time.Time
+}
+
+// Option configures a Client created by New
+type Option func(*state)
+
+// WithHTTPClient sends requests with hc instead of a client with a 30
+// second timeout
+func WithHTTPClient(hc *http.Client) Option {
+	return func(s *state) { s.httpClient = hc }
+}
+
+// WithBaseURL sends requests to url instead of DefaultBaseURL, e.g. a
+// test server
+func WithBaseURL(url string) Option {
+	return func(s *state) { s.baseURL = strings.TrimSuffix(url, "/") }
+}
+
+// WithWriteGuard makes every write request first call guard with the
+// budget it targets; the request is not sent if guard returns an error
+func WithWriteGuard(guard func(budgetID string) error) Option {
+	return func(s *state) { s.writeGuard = guard }
+}
+
+// Pacer is told about every request before it is sent, so it can delay
+// requests to stay under the API rate limit
+type Pacer interface {
+	Wait()
+	Observe(used, limit int)
+}
+
+// WithPacer makes every request wait for p and reports the rate limit
+// returned by the API to it
+func WithPacer(p Pacer) Option {
+	return func(s *state) { s.pacer = p }
+}
+
+// WithNameRecorder passes every successful response to r
+func WithNameRecorder(r NameRecorder) Option {
+	return func(s *state) { s.names = r }
+}
+
+// ResponseStore keeps GET response bodies between runs, so they can be
+// served when the API cannot be reached
+type ResponseStore interface {
+	Load(path string) (body []byte, fetchedAt time.Time, ok bool)
+	Store(path string, body []byte) error
+}
+
+// WithResponseStore saves every successful GET response in s. Delta
+// requests (last_knowledge_of_server) are not saved: they differ on
+// every run and are of no use offline. A failure to save only costs
+// offline coverage and is not reported.
+func WithResponseStore(s ResponseStore) Option {
+	return func(st *state) { st.store = s }
+}
+
+// ResponseInvalidator is implemented by response stores that can drop
+// saved responses by path prefix
+type ResponseInvalidator interface {
+	Invalidate(prefix string) error
+}
+
+// WithCacheTTL serves GET requests from the response store without a
+// request while the saved response is younger than ttl, so scripts that
+// read the same lists repeatedly stay under the rate limit. Delta
+// requests and reads of a Fresh client always go to the API. After a
+// successful write, the saved responses of the budget are dropped if the
+// store is a ResponseInvalidator. A ttl of 0 turns this off.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(s *state) { s.cacheTTL = ttl }
+}
+
+// WithOffline serves GET requests from the response store only, without
+// any network access. Paths the store lacks and all writes fail with
+// ErrOffline. Use DataAsOf to tell how old the served data is.
+func WithOffline() Option {
+	return func(s *state) { s.offline = true }
+}
+
+// DataAsOf returns when the oldest response served from the response
+// store was fetched. ok is false if no response came from the store.
+func (c *Client) DataAsOf() (t time.Time, ok bool) {
+	c.asOfMu.Lock()
+	defer c.asOfMu.Unlock()
+	return c.asOf, !c.asOf.IsZero()
+}
+
+// WithContext returns a client that sends its requests with ctx, so they
+// are abandoned when ctx is canceled or times out. The returned client
+// shares its cache, rate limit and mutation log with c.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	return &Client{state: c.state, ctx: ctx, fresh: c.fresh}
+}
+
+// Fresh returns a client whose reads are always sent to the API instead
+// of being answered from the memo, e.g. to check that a record did not
+// change since it was first read. The responses still update the memo.
+func (c *Client) Fresh() *Client {
+	return &Client{state: c.state, ctx: c.ctx, fresh: true}
+}
+
+// RateLimit returns the request count and limit of the current rate
+// limit window as last reported by the API. ok is false until a response
+// carried the X-Rate-Limit header.
+func (c *Client) RateLimit() (used, limit int, ok bool) {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+	return c.rateUsed, c.rateLimit, c.rateLimit > 0
+}
+
+// observeRateLimit records an X-Rate-Limit header such as "36/200"
+func (c *Client) observeRateLimit(header string) {
+	var used, limit int
+	if _, err := fmt.Sscanf(header, "%d/%d", &used, &limit); err != nil || limit <= 0 {
+		return
+	}
+	c.rateMu.Lock()
+	c.rateUsed, c.rateLimit = used, limit
+	c.rateMu.Unlock()
+	if c.pacer != nil {
+		c.pacer.Observe(used, limit)
+	}
+}
+
+// NameRecorder learns ID to name mappings from API response bodies
+type NameRecorder interface {
+	Learn(budgetID string, body []byte)
+}
+
+// budgetIDFromPath returns the budget ID of a /budgets/{id}/... path
+func budgetIDFromPath(path string) string {
+	rest, ok := strings.CutPrefix(path, "/budgets/")
+	if !ok {
+		return ""
+	}
+	id, _, _ := strings.Cut(rest, "/")
+	id, _, _ = strings.Cut(id, "?")
+	return id
+}
+
+// Mutation records a write request made by the client
+type Mutation struct {
+	Method string   `json:"method"`
+	Path   string   `json:"path"`
+	IDs    []string `json:"ids,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// Mutations returns the write requests made so far, including failed ones
+func (c *Client) Mutations() []Mutation {
+	c.mutationsMu.Lock()
+	defer c.mutationsMu.Unlock()
+	return append([]Mutation(nil), c.mutations...)
+}
+
+// recordMutation notes a write request and the IDs of the records it touched
+func (c *Client) recordMutation(method, path string, respBody []byte, err error) {
+	m := Mutation{Method: method, Path: path}
+	if err != nil {
+		m.Error = err.Error()
+	} else {
+		m.IDs = affectedIDs(respBody)
+	}
+	c.mutationsMu.Lock()
+	c.mutations = append(c.mutations, m)
+	c.mutationsMu.Unlock()
+}
+
+// affectedIDs extracts record IDs from a write response: the "id" of a
+// single returned record, or of every record in a returned list
+func affectedIDs(respBody []byte) []string {
+	var resp struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if json.Unmarshal(respBody, &resp) != nil {
+		return nil
+	}
+	var ids []string
+	for _, raw := range resp.Data {
+		var one struct {
+			ID string `json:"id"`
+		}
+		if json.Unmarshal(raw, &one) == nil && one.ID != "" {
+			ids = append(ids, one.ID)
+			continue
+		}
+		var many []struct {
+			ID string `json:"id"`
+		}
+		if json.Unmarshal(raw, &many) == nil {
+			for _, r := range many {
+				if r.ID != "" {
+					ids = append(ids, r.ID)
+				}
+			}
+		}
+	}
+	sort.Strings(ids)
+	// bulk responses list the same records under several keys
+	uniq := ids[:0]
+	for i, id := range ids {
+		if i == 0 || id != ids[i-1] {
+			uniq = append(uniq, id)
+		}
+	}
+	return uniq
+}
+
+// New creates a client for the YNAB API authenticating with a personal
+// access token or OAuth access token
+func New(token string, opts ...Option) *Client {
+	s := &state{
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		token:   token,
+		baseURL: DefaultBaseURL,
+		memo:    map[string][]byte{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return &Client{state: s, ctx: context.Background()}
+}
+
+// Error represents a YNAB API error. Use errors.Is with the Err* values
+// to branch on the kind of error.
+type Error struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Detail     string `json:"detail"`
+	StatusCode int    `json:"-"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Name, e.Detail)
+}
+
+// ErrorResponse wraps the error from YNAB API
+type ErrorResponse struct {
+	Error *Error `json:"error"`
+}
+
+// doRequest performs an HTTP request to the YNAB API. GET responses are
+// memoized per path; any successful write clears the memo so later reads
+// see the change. Delta requests are not memoized: each carries a new
+// server knowledge, so a polling loop would grow the memo without bound.
+func (c *Client) doRequest(method, path string, body interface{}) ([]byte, error) {
+	if method == "GET" && !c.fresh && !isDelta(path) {
+		c.memoMu.Lock()
+		cached, ok := c.memo[path]
+		c.memoMu.Unlock()
+		if ok {
+			return cached, nil
+		}
+	}
+	if c.offline {
+		return c.offlineRequest(method, path)
+	}
+	if method == "GET" && !c.fresh {
+		if body, ok := c.cachedResponse(path); ok {
+			return body, nil
+		}
+	}
+
+	if method != "GET" && c.writeGuard != nil {
+		if err := c.writeGuard(budgetIDFromPath(path)); err != nil {
+			return nil, err
+		}
+	}
+	if method != "GET" {
+		if err := c.checkPartial(path); err != nil {
+			return nil, err
+		}
+	}
+
+	respBody, err := c.send(method, path, body)
+	if method != "GET" {
+		c.recordMutation(method, path, respBody, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if c.names != nil {
+		c.names.Learn(budgetIDFromPath(path), respBody)
+	}
+
+	c.memoMu.Lock()
+	switch {
+	case method != "GET":
+		c.memo = map[string][]byte{}
+	case !isDelta(path):
+		c.memo[path] = respBody
+	}
+	c.memoMu.Unlock()
+
+	if method == "GET" && c.store != nil && !isDelta(path) {
+		_ = c.store.Store(path, respBody)
+	}
+	if inv, ok := c.store.(ResponseInvalidator); ok && method != "GET" && c.cacheTTL > 0 {
+		if id := budgetIDFromPath(path); id != "" {
+			_ = inv.Invalidate("/budgets/" + id)
+		}
+	}
+
+	return respBody, nil
+}
+
+// isDelta reports whether path asks for the changes since a server
+// knowledge
+func isDelta(path string) bool {
+	return strings.Contains(path, "last_knowledge_of_server=")
+}
+
+// cachedResponse returns the saved response of path if WithCacheTTL is
+// set and it is young enough
+func (c *Client) cachedResponse(path string) ([]byte, bool) {
+	if c.cacheTTL <= 0 || c.store == nil || isDelta(path) {
+		return nil, false
+	}
+	body, fetchedAt, ok := c.store.Load(path)
+	if !ok || time.Since(fetchedAt) >= c.cacheTTL {
+		return nil, false
+	}
+	c.memoMu.Lock()
+	c.memo[path] = body
+	c.memoMu.Unlock()
+	return body, true
+}
+
+// offlineRequest answers a request from the response store
+func (c *Client) offlineRequest(method, path string) ([]byte, error) {
+	if method != "GET" {
+		return nil, fmt.Errorf("%w: %s %s needs the API", ErrOffline, method, path)
+	}
+	if c.store == nil {
+		return nil, fmt.Errorf("%w: no response store", ErrOffline)
+	}
+	body, fetchedAt, ok := c.store.Load(path)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s was never fetched online", ErrOffline, path)
+	}
+
+	c.asOfMu.Lock()
+	if c.asOf.IsZero() || fetchedAt.Before(c.asOf) {
+		c.asOf = fetchedAt
+	}
+	c.asOfMu.Unlock()
+
+	c.memoMu.Lock()
+	c.memo[path] = body
+	c.memoMu.Unlock()
+	return body, nil
+}
+
+// send performs an HTTP request to the YNAB API, retrying once after a
+// transient failure (see retryable)
+func (c *Client) send(method, path string, body interface{}) ([]byte, error) {
+	var jsonBody []byte
+	if body != nil {
+		var err error
+		jsonBody, err = json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+	}
+
+	respBody, err := c.sendOnce(method, path, jsonBody)
+	if err != nil && retryable(method, err) {
+		time.Sleep(retryDelay)
+		respBody, err = c.sendOnce(method, path, jsonBody)
+	}
+	return respBody, err
+}
+
+// sendOnce performs a single HTTP request
+func (c *Client) sendOnce(method, path string, jsonBody []byte) ([]byte, error) {
+	var bodyReader io.Reader
+	if jsonBody != nil {
+		bodyReader = bytes.NewReader(jsonBody)
+	}
+
+	req, err := http.NewRequestWithContext(c.ctx, method, c.baseURL+path, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	if c.pacer != nil {
+		c.pacer.Wait()
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctxErr := c.ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, fmt.Errorf("%w: %w", ErrNetwork, err)
+	}
+	defer resp.Body.Close()
+	c.observeRateLimit(resp.Header.Get("X-Rate-Limit"))
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read response body: %w", ErrNetwork, err)
+	}
+
+	if resp.StatusCode >= 400 {
+		var errResp ErrorResponse
+		if err := json.Unmarshal(respBody, &errResp); err == nil && errResp.Error != nil {
+			errResp.Error.StatusCode = resp.StatusCode
+			return nil, errResp.Error
+		}
+		return nil, &Error{
+			Name:       http.StatusText(resp.StatusCode),
+			Detail:     fmt.Sprintf("%s (status %d)", strings.TrimSpace(string(respBody)), resp.StatusCode),
+			StatusCode: resp.StatusCode,
+		}
+	}
+
+	return respBody, nil
+}
+
+// User types
+type User struct {
+	ID string `json:"id"`
+}
+
+type UserResponse struct {
+	Data struct {
+		User User `json:"user"`
+	} `json:"data"`
+}
+
+// GetUser returns the authenticated user
+func (c *Client) GetUser() (*User, error) {
+	body, err := c.doRequest("GET", "/user", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp UserResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Data.User, nil
+}
+
+// Budget types
+type Budget struct {
+	ID             string          `json:"id"`
+	Name           string          `json:"name"`
+	LastModifiedOn string          `json:"last_modified_on"`
+	FirstMonth     string          `json:"first_month"`
+	LastMonth      string          `json:"last_month"`
+	DateFormat     *DateFormat     `json:"date_format"`
+	CurrencyFormat *CurrencyFormat `json:"currency_format"`
+}
+
+type DateFormat struct {
+	Format string `json:"format"`
+}
+
+type CurrencyFormat struct {
+	ISOCode          string `json:"iso_code"`
+	ExampleFormat    string `json:"example_format"`
+	DecimalDigits    int    `json:"decimal_digits"`
+	DecimalSeparator string `json:"decimal_separator"`
+	SymbolFirst      bool   `json:"symbol_first"`
+	GroupSeparator   string `json:"group_separator"`
+	CurrencySymbol   string `json:"currency_symbol"`
+	DisplaySymbol    bool   `json:"display_symbol"`
+}
+
+type BudgetSettings struct {
+	DateFormat     DateFormat     `json:"date_format"`
+	CurrencyFormat CurrencyFormat `json:"currency_format"`
+}
+
+type BudgetsResponse struct {
+	Data struct {
+		Budgets []Budget `json:"budgets"`
+	} `json:"data"`
+}
+
+type BudgetDetailResponse struct {
+	Data struct {
+		Budget Budget `json:"budget"`
+	} `json:"data"`
+}
+
+type BudgetSettingsResponse struct {
+	Data struct {
+		Settings BudgetSettings `json:"settings"`
+	} `json:"data"`
+}
+
+// GetBudgets returns all budgets
+func (c *Client) GetBudgets() ([]Budget, error) {
+	body, err := c.doRequest("GET", "/budgets", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp BudgetsResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return resp.Data.Budgets, nil
+}
+
+// GetBudget returns a specific budget
+func (c *Client) GetBudget(budgetID string) (*Budget, error) {
+	body, err := c.doRequest("GET", fmt.Sprintf("/budgets/%s", budgetID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp BudgetDetailResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Data.Budget, nil
+}
+
+// GetBudgetSettings returns settings for a specific budget
+func (c *Client) GetBudgetSettings(budgetID string) (*BudgetSettings, error) {
+	body, err := c.doRequest("GET", fmt.Sprintf("/budgets/%s/settings", budgetID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp BudgetSettingsResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Data.Settings, nil
+}
+
+// Account types
+type Account struct {
+	ID                  string           `json:"id"`
+	Name                string           `json:"name"`
+	Type                string           `json:"type"`
+	OnBudget            bool             `json:"on_budget"`
+	Closed              bool             `json:"closed"`
+	Note                string           `json:"note"`
+	Balance             int64            `json:"balance" amount:"milliunits"`
+	ClearedBalance      int64            `json:"cleared_balance" amount:"milliunits"`
+	UnclearedBalance    int64            `json:"uncleared_balance" amount:"milliunits"`
+	TransferPayeeID     string           `json:"transfer_payee_id"`
+	DirectImportLinked  bool             `json:"direct_import_linked"`
+	DirectImportInError bool             `json:"direct_import_in_error"`
+	LastReconciledAt    string           `json:"last_reconciled_at"`
+	DebtOriginalBalance int64            `json:"debt_original_balance" amount:"milliunits"`
+	DebtInterestRates   map[string]int64 `json:"debt_interest_rates"`
+	DebtMinimumPayments map[string]int64 `json:"debt_minimum_payments"`
+	DebtEscrowAmounts   map[string]int64 `json:"debt_escrow_amounts"`
+	Deleted             bool             `json:"deleted"`
+}
+
+type AccountsResponse struct {
+	Data struct {
+		Accounts        []Account `json:"accounts"`
+		ServerKnowledge int64     `json:"server_knowledge"`
+	} `json:"data"`
+}
+
+type AccountResponse struct {
+	Data struct {
+		Account Account `json:"account"`
+	} `json:"data"`
+}
+
+// GetAccounts returns all accounts for a budget
+func (c *Client) GetAccounts(budgetID string) ([]Account, error) {
+	body, err := c.doRequest("GET", fmt.Sprintf("/budgets/%s/accounts", budgetID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp AccountsResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return resp.Data.Accounts, nil
+}
+
+// GetAccountsSince returns the accounts changed since lastKnowledge, including
+// deleted ones, and the server knowledge to pass next time. A
+// lastKnowledge of 0 returns all of them.
+func (c *Client) GetAccountsSince(budgetID string, lastKnowledge int64) ([]Account, int64, error) {
+	path := fmt.Sprintf("/budgets/%s/accounts?last_knowledge_of_server=%d", budgetID, lastKnowledge)
+
+	body, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var resp AccountsResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return resp.Data.Accounts, resp.Data.ServerKnowledge, nil
+}
+
+// GetAccount returns a specific account
+func (c *Client) GetAccount(budgetID, accountID string) (*Account, error) {
+	body, err := c.doRequest("GET", fmt.Sprintf("/budgets/%s/accounts/%s", budgetID, accountID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp AccountResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Data.Account, nil
+}
+
+// SaveAccount represents an account to create
+type SaveAccount struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Balance int64  `json:"balance" amount:"milliunits"`
+	Note    string `json:"note,omitempty"`
+}
+
+// CreateAccountRequest represents the request to create an account
+type CreateAccountRequest struct {
+	Account SaveAccount `json:"account"`
+}
+
+// TrackingAccountTypes are account types YNAB always creates off budget
+var TrackingAccountTypes = []string{
+	"otherAsset", "otherLiability", "mortgage", "autoLoan", "studentLoan",
+	"personalLoan", "medicalDebt", "otherDebt",
+}
+
+// BudgetAccountTypes are account types YNAB creates on budget
+var BudgetAccountTypes = []string{
+	"checking", "savings", "cash", "creditCard", "lineOfCredit",
+}
+
+// IsTrackingAccountType reports whether accounts of this type are off budget
+func IsTrackingAccountType(accountType string) bool {
+	for _, t := range TrackingAccountTypes {
+		if t == accountType {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateAccount creates a new account
+func (c *Client) CreateAccount(budgetID string, account SaveAccount) (*Account, error) {
+	req := CreateAccountRequest{Account: account}
+
+	body, err := c.doRequest("POST", fmt.Sprintf("/budgets/%s/accounts", budgetID), req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp AccountResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Data.Account, nil
+}
+
+// Category types
+type CategoryGroup struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Hidden     bool       `json:"hidden"`
+	Deleted    bool       `json:"deleted"`
+	Categories []Category `json:"categories"`
+}
+
+type Category struct {
+	ID                      string `json:"id"`
+	CategoryGroupID         string `json:"category_group_id"`
+	CategoryGroupName       string `json:"category_group_name"`
+	Name                    string `json:"name"`
+	Hidden                  bool   `json:"hidden"`
+	OriginalCategoryGroupID string `json:"original_category_group_id"`
+	Note                    string `json:"note"`
+	Budgeted                int64  `json:"budgeted" amount:"milliunits"`
+	Activity                int64  `json:"activity" amount:"milliunits"`
+	Balance                 int64  `json:"balance" amount:"milliunits"`
+	GoalType                string `json:"goal_type"`
+	GoalDay                 int    `json:"goal_day"`
+	GoalCadence             int    `json:"goal_cadence"`
+	GoalCadenceFrequency    int    `json:"goal_cadence_frequency"`
+	GoalCreationMonth       string `json:"goal_creation_month"`
+	GoalTarget              int64  `json:"goal_target" amount:"milliunits"`
+	GoalTargetMonth         string `json:"goal_target_month"`
+	GoalPercentageComplete  int    `json:"goal_percentage_complete"`
+	GoalMonthsToBudget      int    `json:"goal_months_to_budget"`
+	GoalUnderFunded         int64  `json:"goal_under_funded" amount:"milliunits"`
+	GoalOverallFunded       int64  `json:"goal_overall_funded" amount:"milliunits"`
+	GoalOverallLeft         int64  `json:"goal_overall_left" amount:"milliunits"`
+	Deleted                 bool   `json:"deleted"`
+}
+
+type CategoriesResponse struct {
+	Data struct {
+		CategoryGroups  []CategoryGroup `json:"category_groups"`
+		ServerKnowledge int64           `json:"server_knowledge"`
+	} `json:"data"`
+}
+
+type CategoryResponse struct {
+	Data struct {
+		Category Category `json:"category"`
+	} `json:"data"`
+}
+
+// GetCategories returns all categories for a budget
+func (c *Client) GetCategories(budgetID string) ([]CategoryGroup, error) {
+	body, err := c.doRequest("GET", fmt.Sprintf("/budgets/%s/categories", budgetID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp CategoriesResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return resp.Data.CategoryGroups, nil
+}
+
+// GetCategoriesSince returns the category groups changed since
+// lastKnowledge, each with only its changed categories, including deleted
+// ones, and the server knowledge to pass next time. A lastKnowledge of 0
+// returns all of them.
+func (c *Client) GetCategoriesSince(budgetID string, lastKnowledge int64) ([]CategoryGroup, int64, error) {
+	path := fmt.Sprintf("/budgets/%s/categories?last_knowledge_of_server=%d", budgetID, lastKnowledge)
+
+	body, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var resp CategoriesResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return resp.Data.CategoryGroups, resp.Data.ServerKnowledge, nil
+}
+
+// GetCategory returns a specific category
+func (c *Client) GetCategory(budgetID, categoryID string) (*Category, error) {
+	body, err := c.doRequest("GET", fmt.Sprintf("/budgets/%s/categories/%s", budgetID, categoryID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp CategoryResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Data.Category, nil
+}
+
+// GetMonthCategory returns a category as it is in a specific budget month
+func (c *Client) GetMonthCategory(budgetID, month, categoryID string) (*Category, error) {
+	body, err := c.doRequest("GET", fmt.Sprintf("/budgets/%s/months/%s/categories/%s", budgetID, month, categoryID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp CategoryResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Data.Category, nil
+}
+
+// UpdateCategoryRequest represents the request to update a category
+type UpdateCategoryRequest struct {
+	Category struct {
+		Budgeted int64 `json:"budgeted" amount:"milliunits"`
+	} `json:"category"`
+}
+
+// UpdateCategory updates a category for a specific month
+func (c *Client) UpdateCategory(budgetID, categoryID, month string, budgeted int64) (*Category, error) {
+	req := UpdateCategoryRequest{}
+	req.Category.Budgeted = budgeted
+
+	body, err := c.doRequest("PATCH", fmt.Sprintf("/budgets/%s/months/%s/categories/%s", budgetID, month, categoryID), req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp CategoryResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Data.Category, nil
+}
+
+// UpdateCategoryGoal sets the goal target of a category. The category
+// must already have a goal; YNAB does not create one from the target.
+func (c *Client) UpdateCategoryGoal(budgetID, categoryID string, goalTarget int64) (*Category, error) {
+	req := struct {
+		Category struct {
+			GoalTarget int64 `json:"goal_target" amount:"milliunits"`
+		} `json:"category"`
+	}{}
+	req.Category.GoalTarget = goalTarget
+
+	body, err := c.doRequest("PATCH", fmt.Sprintf("/budgets/%s/categories/%s", budgetID, categoryID), req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp CategoryResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Data.Category, nil
+}
+
+// CategoryGroupResponse represents the response for a single category group
+type CategoryGroupResponse struct {
+	Data struct {
+		CategoryGroup CategoryGroup `json:"category_group"`
+	} `json:"data"`
+}
+
+// CreateCategoryGroup creates an empty category group
+func (c *Client) CreateCategoryGroup(budgetID, name string) (*CategoryGroup, error) {
+	req := struct {
+		CategoryGroup struct {
+			Name string `json:"name"`
+		} `json:"category_group"`
+	}{}
+	req.CategoryGroup.Name = name
+
+	body, err := c.doRequest("POST", fmt.Sprintf("/budgets/%s/category_groups", budgetID), req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp CategoryGroupResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Data.CategoryGroup, nil
+}
+
+// SaveCategory represents a category to create
+type SaveCategory struct {
+	Name            string `json:"name"`
+	CategoryGroupID string `json:"category_group_id"`
+	Note            string `json:"note,omitempty"`
+	GoalTarget      *int64 `json:"goal_target,omitempty" amount:"milliunits"`
+}
+
+// CreateCategory creates a category in an existing group
+func (c *Client) CreateCategory(budgetID string, category SaveCategory) (*Category, error) {
+	req := struct {
+		Category SaveCategory `json:"category"`
+	}{category}
+
+	body, err := c.doRequest("POST", fmt.Sprintf("/budgets/%s/categories", budgetID), req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp CategoryResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Data.Category, nil
+}
+
+// Payee types
+type Payee struct {
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	TransferAccountID string `json:"transfer_account_id"`
+	Deleted           bool   `json:"deleted"`
+}
+
+type PayeesResponse struct {
+	Data struct {
+		Payees          []Payee `json:"payees"`
+		ServerKnowledge int64   `json:"server_knowledge"`
+	} `json:"data"`
+}
+
+type PayeeResponse struct {
+	Data struct {
+		Payee Payee `json:"payee"`
+	} `json:"data"`
+}
+
+// GetPayees returns all payees for a budget
+func (c *Client) GetPayees(budgetID string) ([]Payee, error) {
+	body, err := c.doRequest("GET", fmt.Sprintf("/budgets/%s/payees", budgetID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp PayeesResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return resp.Data.Payees, nil
+}
+
+// GetPayeesSince returns the payees changed since lastKnowledge, including
+// deleted ones, and the server knowledge to pass next time. A
+// lastKnowledge of 0 returns all of them.
+func (c *Client) GetPayeesSince(budgetID string, lastKnowledge int64) ([]Payee, int64, error) {
+	path := fmt.Sprintf("/budgets/%s/payees?last_knowledge_of_server=%d", budgetID, lastKnowledge)
+
+	body, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var resp PayeesResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return resp.Data.Payees, resp.Data.ServerKnowledge, nil
+}
+
+// GetPayee returns a specific payee
+func (c *Client) GetPayee(budgetID, payeeID string) (*Payee, error) {
+	body, err := c.doRequest("GET", fmt.Sprintf("/budgets/%s/payees/%s", budgetID, payeeID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp PayeeResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Data.Payee, nil
+}
+
+// UpdatePayeeRequest represents the request to update a payee
+type UpdatePayeeRequest struct {
+	Payee struct {
+		Name string `json:"name"`
+	} `json:"payee"`
+}
+
+// UpdatePayee updates a payee
+func (c *Client) UpdatePayee(budgetID, payeeID, name string) (*Payee, error) {
+	req := UpdatePayeeRequest{}
+	req.Payee.Name = name
+
+	body, err := c.doRequest("PATCH", fmt.Sprintf("/budgets/%s/payees/%s", budgetID, payeeID), req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp PayeeResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Data.Payee, nil
+}
+
+// Transaction types
+type Transaction struct {
+	ID                      string           `json:"id"`
+	Date                    string           `json:"date"`
+	Amount                  int64            `json:"amount" amount:"milliunits"`
+	Memo                    string           `json:"memo"`
+	Cleared                 string           `json:"cleared"`
+	Approved                bool             `json:"approved"`
+	FlagColor               string           `json:"flag_color"`
+	FlagName                string           `json:"flag_name"`
+	AccountID               string           `json:"account_id"`
+	AccountName             string           `json:"account_name"`
+	PayeeID                 string           `json:"payee_id"`
+	PayeeName               string           `json:"payee_name"`
+	CategoryID              string           `json:"category_id"`
+	CategoryName            string           `json:"category_name"`
+	TransferAccountID       string           `json:"transfer_account_id"`
+	TransferTransactionID   string           `json:"transfer_transaction_id"`
+	MatchedTransactionID    string           `json:"matched_transaction_id"`
+	ImportID                string           `json:"import_id"`
+	ImportPayeeName         string           `json:"import_payee_name"`
+	ImportPayeeNameOriginal string           `json:"import_payee_name_original"`
+	DebtTransactionType     string           `json:"debt_transaction_type"`
+	Deleted                 bool             `json:"deleted"`
+	Subtransactions         []Subtransaction `json:"subtransactions"`
+}
+
+type Subtransaction struct {
+	ID                    string `json:"id"`
+	TransactionID         string `json:"transaction_id"`
+	Amount                int64  `json:"amount" amount:"milliunits"`
+	Memo                  string `json:"memo"`
+	PayeeID               string `json:"payee_id"`
+	PayeeName             string `json:"payee_name"`
+	CategoryID            string `json:"category_id"`
+	CategoryName          string `json:"category_name"`
+	TransferAccountID     string `json:"transfer_account_id"`
+	TransferTransactionID string `json:"transfer_transaction_id"`
+	Deleted               bool   `json:"deleted"`
+}
+
+type TransactionsResponse struct {
+	Data struct {
+		Transactions    []Transaction `json:"transactions"`
+		ServerKnowledge int64         `json:"server_knowledge"`
+	} `json:"data"`
+}
+
+type TransactionResponse struct {
+	Data struct {
+		Transaction     Transaction `json:"transaction"`
+		ServerKnowledge int64       `json:"server_knowledge"`
+	} `json:"data"`
+}
+
+// TransactionFilter contains filters for listing transactions
+type TransactionFilter struct {
+	SinceDate  string
+	Type       string
+	AccountID  string
+	CategoryID string
+	PayeeID    string
+}
+
+// GetTransactions returns transactions for a budget with optional filters
+func (c *Client) GetTransactions(budgetID string, filter *TransactionFilter) ([]Transaction, error) {
+	path := fmt.Sprintf("/budgets/%s/transactions", budgetID)
+
+	if filter != nil {
+		params := url.Values{}
+		if filter.SinceDate != "" {
+			params.Set("since_date", filter.SinceDate)
+		}
+		if filter.Type != "" {
+			params.Set("type", filter.Type)
+		}
+		if len(params) > 0 {
+			path += "?" + params.Encode()
+		}
+	}
+
+	body, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TransactionsResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return resp.Data.Transactions, nil
+}
+
+// GetTransactionsByAccount returns transactions for a specific account
+func (c *Client) GetTransactionsByAccount(budgetID, accountID string, sinceDate string) ([]Transaction, error) {
+	path := fmt.Sprintf("/budgets/%s/accounts/%s/transactions", budgetID, accountID)
+	if sinceDate != "" {
+		path += "?since_date=" + sinceDate
+	}
+
+	body, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TransactionsResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return resp.Data.Transactions, nil
+}
+
+// GetTransactionsByCategory returns transactions for a specific category
+func (c *Client) GetTransactionsByCategory(budgetID, categoryID string, sinceDate string) ([]Transaction, error) {
+	path := fmt.Sprintf("/budgets/%s/categories/%s/transactions", budgetID, categoryID)
+	if sinceDate != "" {
+		path += "?since_date=" + sinceDate
+	}
+
+	body, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TransactionsResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return resp.Data.Transactions, nil
+}
+
+// GetTransactionsByPayee returns transactions for a specific payee
+func (c *Client) GetTransactionsByPayee(budgetID, payeeID string, sinceDate string) ([]Transaction, error) {
+	path := fmt.Sprintf("/budgets/%s/payees/%s/transactions", budgetID, payeeID)
+	if sinceDate != "" {
+		path += "?since_date=" + sinceDate
+	}
+
+	body, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TransactionsResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return resp.Data.Transactions, nil
+}
+
+// GetTransaction returns a specific transaction
+func (c *Client) GetTransaction(budgetID, transactionID string) (*Transaction, error) {
+	txn, _, err := c.GetTransactionWithKnowledge(budgetID, transactionID)
+	return txn, err
+}
+
+// GetTransactionWithKnowledge returns a specific transaction together
+// with the server knowledge at the time it was read
+func (c *Client) GetTransactionWithKnowledge(budgetID, transactionID string) (*Transaction, int64, error) {
+	body, err := c.doRequest("GET", fmt.Sprintf("/budgets/%s/transactions/%s", budgetID, transactionID), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var resp TransactionResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Data.Transaction, resp.Data.ServerKnowledge, nil
+}
+
+// GetTransactionsSince returns the transactions that changed after the
+// given server knowledge, along with the current server knowledge
+func (c *Client) GetTransactionsSince(budgetID string, lastKnowledge int64) ([]Transaction, int64, error) {
+	path := fmt.Sprintf("/budgets/%s/transactions?last_knowledge_of_server=%d", budgetID, lastKnowledge)
+
+	body, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var resp TransactionsResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return resp.Data.Transactions, resp.Data.ServerKnowledge, nil
+}
+
+// CreateTransactionRequest represents the request to create a transaction
+type CreateTransactionRequest struct {
+	Transaction SaveTransaction `json:"transaction"`
+}
+
+type SaveTransaction struct {
+	AccountID  string `json:"account_id"`
+	Date       string `json:"date"`
+	Amount     int64  `json:"amount" amount:"milliunits"`
+	PayeeID    string `json:"payee_id,omitempty"`
+	PayeeName  string `json:"payee_name,omitempty"`
+	CategoryID string `json:"category_id,omitempty"`
+	Memo       string `json:"memo,omitempty"`
+	Cleared    string `json:"cleared,omitempty"`
+	Approved   bool   `json:"approved,omitempty"`
+	FlagColor  string `json:"flag_color,omitempty"`
+	ImportID   string `json:"import_id,omitempty"`
+}
+
+// CreateTransaction creates a new transaction
+func (c *Client) CreateTransaction(budgetID string, txn SaveTransaction) (*Transaction, error) {
+	req := CreateTransactionRequest{Transaction: txn}
+
+	body, err := c.doRequest("POST", fmt.Sprintf("/budgets/%s/transactions", budgetID), req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TransactionResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Data.Transaction, nil
+}
+
+// CreateTransactionsResult is the outcome of a bulk transaction create
+type CreateTransactionsResult struct {
+	TransactionIDs     []string      `json:"transaction_ids"`
+	Transactions       []Transaction `json:"transactions"`
+	DuplicateImportIDs []string      `json:"duplicate_import_ids"`
+	ServerKnowledge    int64         `json:"server_knowledge"`
+}
+
+// CreateTransactions creates several transactions in a single request.
+// Transactions whose import_id already exists are skipped by YNAB and
+// reported in DuplicateImportIDs.
+func (c *Client) CreateTransactions(budgetID string, txns []SaveTransaction) (*CreateTransactionsResult, error) {
+	req := struct {
+		Transactions []SaveTransaction `json:"transactions"`
+	}{Transactions: txns}
+
+	body, err := c.doRequest("POST", fmt.Sprintf("/budgets/%s/transactions", budgetID), req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Data CreateTransactionsResult `json:"data"`
+	}
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Data, nil
+}
+
+// UpdateTransactionRequest represents the request to update a transaction
+type UpdateTransactionRequest struct {
+	Transaction SaveTransaction `json:"transaction"`
+}
+
+// UpdateTransaction updates an existing transaction
+func (c *Client) UpdateTransaction(budgetID, transactionID string, txn SaveTransaction) (*Transaction, error) {
+	req := UpdateTransactionRequest{Transaction: txn}
+
+	body, err := c.doRequest("PUT", fmt.Sprintf("/budgets/%s/transactions/%s", budgetID, transactionID), req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TransactionResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Data.Transaction, nil
+}
+
+// PatchTransaction is a partial transaction update. Only non-nil fields
+// are sent, so fields changed concurrently by someone else are left alone.
+type PatchTransaction struct {
+	ID         string  `json:"id"`
+	AccountID  *string `json:"account_id,omitempty"`
+	Date       *string `json:"date,omitempty"`
+	Amount     *int64  `json:"amount,omitempty" amount:"milliunits"`
+	PayeeID    *string `json:"payee_id,omitempty"`
+	PayeeName  *string `json:"payee_name,omitempty"`
+	CategoryID *string `json:"category_id,omitempty"`
+	Memo       *string `json:"memo,omitempty"`
+	Cleared    *string `json:"cleared,omitempty"`
+	Approved   *bool   `json:"approved,omitempty"`
+	FlagColor  *string `json:"flag_color,omitempty"`
+}
+
+// PatchTransactions applies partial updates to several transactions in a
+// single request. It returns the updated transactions and the server
+// knowledge after the update.
+func (c *Client) PatchTransactions(budgetID string, patches []PatchTransaction) ([]Transaction, int64, error) {
+	req := struct {
+		Transactions []PatchTransaction `json:"transactions"`
+	}{Transactions: patches}
+
+	body, err := c.doRequest("PATCH", fmt.Sprintf("/budgets/%s/transactions", budgetID), req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var resp TransactionsResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return resp.Data.Transactions, resp.Data.ServerKnowledge, nil
+}
+
+// ApproveTransactions marks the given transactions as approved in a single request
+func (c *Client) ApproveTransactions(budgetID string, transactionIDs []string) ([]Transaction, error) {
+	approved := true
+	patches := make([]PatchTransaction, 0, len(transactionIDs))
+	for _, id := range transactionIDs {
+		patches = append(patches, PatchTransaction{ID: id, Approved: &approved})
+	}
+	txns, _, err := c.PatchTransactions(budgetID, patches)
+	return txns, err
+}
+
+// DeleteTransaction deletes a transaction
+func (c *Client) DeleteTransaction(budgetID, transactionID string) (*Transaction, error) {
+	body, err := c.doRequest("DELETE", fmt.Sprintf("/budgets/%s/transactions/%s", budgetID, transactionID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TransactionResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Data.Transaction, nil
+}
+
+// ScheduledTransaction types
+type ScheduledTransaction struct {
+	ID                string                    `json:"id"`
+	DateFirst         string                    `json:"date_first"`
+	DateNext          string                    `json:"date_next"`
+	Frequency         string                    `json:"frequency"`
+	Amount            int64                     `json:"amount" amount:"milliunits"`
+	Memo              string                    `json:"memo"`
+	FlagColor         string                    `json:"flag_color"`
+	FlagName          string                    `json:"flag_name"`
+	AccountID         string                    `json:"account_id"`
+	AccountName       string                    `json:"account_name"`
+	PayeeID           string                    `json:"payee_id"`
+	PayeeName         string                    `json:"payee_name"`
+	CategoryID        string                    `json:"category_id"`
+	CategoryName      string                    `json:"category_name"`
+	TransferAccountID string                    `json:"transfer_account_id"`
+	Deleted           bool                      `json:"deleted"`
+	Subtransactions   []ScheduledSubtransaction `json:"subtransactions"`
+}
+
+type ScheduledSubtransaction struct {
+	ID                     string `json:"id"`
+	ScheduledTransactionID string `json:"scheduled_transaction_id"`
+	Amount                 int64  `json:"amount" amount:"milliunits"`
+	Memo                   string `json:"memo"`
+	PayeeID                string `json:"payee_id"`
+	CategoryID             string `json:"category_id"`
+	TransferAccountID      string `json:"transfer_account_id"`
+	Deleted                bool   `json:"deleted"`
+}
+
+type ScheduledTransactionsResponse struct {
+	Data struct {
+		ScheduledTransactions []ScheduledTransaction `json:"scheduled_transactions"`
+		ServerKnowledge       int64                  `json:"server_knowledge"`
+	} `json:"data"`
+}
+
+type ScheduledTransactionResponse struct {
+	Data struct {
+		ScheduledTransaction ScheduledTransaction `json:"scheduled_transaction"`
+	} `json:"data"`
+}
+
+// GetScheduledTransactions returns all scheduled transactions for a budget
+func (c *Client) GetScheduledTransactions(budgetID string) ([]ScheduledTransaction, error) {
+	body, err := c.doRequest("GET", fmt.Sprintf("/budgets/%s/scheduled_transactions", budgetID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ScheduledTransactionsResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return resp.Data.ScheduledTransactions, nil
+}
+
+// GetScheduledTransactionsSince returns the scheduled transactions changed since lastKnowledge, including
+// deleted ones, and the server knowledge to pass next time. A
+// lastKnowledge of 0 returns all of them.
+func (c *Client) GetScheduledTransactionsSince(budgetID string, lastKnowledge int64) ([]ScheduledTransaction, int64, error) {
+	path := fmt.Sprintf("/budgets/%s/scheduled_transactions?last_knowledge_of_server=%d", budgetID, lastKnowledge)
+
+	body, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var resp ScheduledTransactionsResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return resp.Data.ScheduledTransactions, resp.Data.ServerKnowledge, nil
+}
+
+// GetScheduledTransaction returns a specific scheduled transaction
+func (c *Client) GetScheduledTransaction(budgetID, scheduledTransactionID string) (*ScheduledTransaction, error) {
+	body, err := c.doRequest("GET", fmt.Sprintf("/budgets/%s/scheduled_transactions/%s", budgetID, scheduledTransactionID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ScheduledTransactionResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Data.ScheduledTransaction, nil
+}
+
+// SaveScheduledTransaction represents a scheduled transaction to create or update
+type SaveScheduledTransaction struct {
+	AccountID  string `json:"account_id"`
+	Date       string `json:"date"`
+	Frequency  string `json:"frequency"`
+	Amount     int64  `json:"amount" amount:"milliunits"`
+	PayeeID    string `json:"payee_id,omitempty"`
+	PayeeName  string `json:"payee_name,omitempty"`
+	CategoryID string `json:"category_id,omitempty"`
+	Memo       string `json:"memo,omitempty"`
+	FlagColor  string `json:"flag_color,omitempty"`
+}
+
+// CreateScheduledTransactionRequest represents the request to create a scheduled transaction
+type CreateScheduledTransactionRequest struct {
+	ScheduledTransaction SaveScheduledTransaction `json:"scheduled_transaction"`
+}
+
+// CreateScheduledTransaction creates a new scheduled transaction
+func (c *Client) CreateScheduledTransaction(budgetID string, st SaveScheduledTransaction) (*ScheduledTransaction, error) {
+	req := CreateScheduledTransactionRequest{ScheduledTransaction: st}
+
+	body, err := c.doRequest("POST", fmt.Sprintf("/budgets/%s/scheduled_transactions", budgetID), req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ScheduledTransactionResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Data.ScheduledTransaction, nil
+}
+
+// UpdateScheduledTransaction updates an existing scheduled transaction
+func (c *Client) UpdateScheduledTransaction(budgetID, scheduledTransactionID string, st SaveScheduledTransaction) (*ScheduledTransaction, error) {
+	req := CreateScheduledTransactionRequest{ScheduledTransaction: st}
+
+	body, err := c.doRequest("PUT", fmt.Sprintf("/budgets/%s/scheduled_transactions/%s", budgetID, scheduledTransactionID), req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ScheduledTransactionResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Data.ScheduledTransaction, nil
+}
+
+// DeleteScheduledTransaction deletes a scheduled transaction
+func (c *Client) DeleteScheduledTransaction(budgetID, scheduledTransactionID string) (*ScheduledTransaction, error) {
+	body, err := c.doRequest("DELETE", fmt.Sprintf("/budgets/%s/scheduled_transactions/%s", budgetID, scheduledTransactionID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ScheduledTransactionResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Data.ScheduledTransaction, nil
+}
+
+// Month types
+type Month struct {
+	Month        string     `json:"month"`
+	Note         string     `json:"note"`
+	Income       int64      `json:"income" amount:"milliunits"`
+	Budgeted     int64      `json:"budgeted" amount:"milliunits"`
+	Activity     int64      `json:"activity" amount:"milliunits"`
+	ToBeBudgeted int64      `json:"to_be_budgeted" amount:"milliunits"`
+	AgeOfMoney   int        `json:"age_of_money"`
+	Deleted      bool       `json:"deleted"`
+	Categories   []Category `json:"categories"`
+}
+
+type MonthsResponse struct {
+	Data struct {
+		Months []Month `json:"months"`
+	} `json:"data"`
+}
+
+type MonthResponse struct {
+	Data struct {
+		Month Month `json:"month"`
+	} `json:"data"`
+}
+
+// GetMonths returns all budget months
+func (c *Client) GetMonths(budgetID string) ([]Month, error) {
+	body, err := c.doRequest("GET", fmt.Sprintf("/budgets/%s/months", budgetID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp MonthsResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return resp.Data.Months, nil
+}
+
+// GetMonth returns a specific budget month
+func (c *Client) GetMonth(budgetID, month string) (*Month, error) {
+	body, err := c.doRequest("GET", fmt.Sprintf("/budgets/%s/months/%s", budgetID, month), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp MonthResponse
+	if err := c.parseResponse(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp.Data.Month, nil
+}
+
+// Currency conversion helpers
+
+// MilliunitsToAmount converts YNAB milliunits to a float amount
+func MilliunitsToAmount(milliunits int64) float64 {
+	return float64(milliunits) / 1000.0
+}
+
+// AmountToMilliunits converts a float amount to YNAB milliunits,
+// rounding to the nearest milliunit: 2.01 is 2010, not 2009.
+func AmountToMilliunits(amount float64) int64 {
+	return int64(math.Round(amount * 1000))
+}
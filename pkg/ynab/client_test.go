@@ -0,0 +1,381 @@
+package ynab
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestMemoization(t *testing.T) {
+	calls := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[r.Method+" "+r.URL.Path]++
+		switch r.URL.Path {
+		case "/budgets/b1/payees":
+			_, _ = w.Write([]byte(`{"data":{"payees":[{"id":"p1","name":"Shop"}]}}`))
+		case "/budgets/b1/transactions":
+			_, _ = w.Write([]byte(`{"data":{"transactions":[],"server_knowledge":8}}`))
+		case "/budgets/b1/payees/p1":
+			_, _ = w.Write([]byte(`{"data":{"payee":{"id":"p1","name":"Store"}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := New("token", WithBaseURL(srv.URL))
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.GetPayees("b1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := calls["GET /budgets/b1/payees"]; got != 1 {
+		t.Errorf("GET payees called %d times, want 1", got)
+	}
+
+	if _, err := c.UpdatePayee("b1", "p1", "Store"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetPayees("b1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := calls["GET /budgets/b1/payees"]; got != 2 {
+		t.Errorf("GET payees called %d times after write, want 2", got)
+	}
+
+	if _, err := c.Fresh().GetPayees("b1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := calls["GET /budgets/b1/payees"]; got != 3 {
+		t.Errorf("GET payees called %d times after a fresh read, want 3", got)
+	}
+
+	for k := int64(1); k <= 3; k++ {
+		if _, _, err := c.Fresh().GetTransactionsSince("b1", k); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, _, err := c.GetTransactionsSince("b1", 3); err != nil {
+		t.Fatal(err)
+	}
+	if got := calls["GET /budgets/b1/transactions"]; got != 4 {
+		t.Errorf("GET transactions delta called %d times, want 4", got)
+	}
+	c.memoMu.Lock()
+	defer c.memoMu.Unlock()
+	for path := range c.memo {
+		if isDelta(path) {
+			t.Errorf("delta response %s memoized", path)
+		}
+	}
+}
+
+func TestErrorKindsAndRetry(t *testing.T) {
+	retryDelay = 0
+	calls := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[r.Method+" "+r.URL.Path]++
+		switch r.URL.Path {
+		case "/user":
+			// fails once, then succeeds
+			if calls["GET /user"] == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"user":{"id":"u1"}}}`))
+		case "/budgets/b1/payees/p1":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/budgets/b1/transactions":
+			w.WriteHeader(http.StatusGatewayTimeout)
+		case "/budgets":
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"id":"401","name":"unauthorized","detail":"Unauthorized"}}`))
+		case "/budgets/b1/accounts":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := New("token", WithBaseURL(srv.URL))
+
+	if _, err := c.GetUser(); err != nil {
+		t.Errorf("GET after one 500 should succeed on retry: %v", err)
+	}
+
+	_, err := c.UpdatePayee("b1", "p1", "x")
+	if !errors.Is(err, ErrServer) {
+		t.Errorf("UpdatePayee error = %v, want ErrServer", err)
+	}
+	if calls["PATCH /budgets/b1/payees/p1"] != 1 {
+		t.Errorf("write retried after a 500: %d calls", calls["PATCH /budgets/b1/payees/p1"])
+	}
+
+	// YNAB may have booked the transaction before the gateway gave up
+	if _, err := c.CreateTransaction("b1", SaveTransaction{AccountID: "a1", Amount: -1000}); !errors.Is(err, ErrServer) {
+		t.Errorf("CreateTransaction error = %v, want ErrServer", err)
+	}
+	if calls["POST /budgets/b1/transactions"] != 1 {
+		t.Errorf("POST retried after a 504: %d calls", calls["POST /budgets/b1/transactions"])
+	}
+
+	cases := []struct {
+		call func() error
+		want error
+	}{
+		{func() error { _, err := c.GetBudgets(); return err }, ErrUnauthorized},
+		{func() error { _, err := c.GetAccounts("b1"); return err }, ErrRateLimited},
+		{func() error { _, err := c.GetPayee("b1", "missing"); return err }, ErrNotFound},
+	}
+	for _, tc := range cases {
+		err := tc.call()
+		if !errors.Is(err, tc.want) {
+			t.Errorf("error %v, want %v", err, tc.want)
+		}
+		if errors.Is(err, ErrNetwork) {
+			t.Errorf("error %v should not be a network error", err)
+		}
+	}
+
+	srv.Close()
+	if _, err := c.GetPayees("b2"); !errors.Is(err, ErrNetwork) {
+		t.Errorf("error after server shutdown = %v, want ErrNetwork", err)
+	}
+}
+
+func TestContextAndOptions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user":
+			_, _ = w.Write([]byte(`{"data":{"user":{"id":"u1"}}}`))
+		case "/budgets/b1/transactions":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"id":"400","name":"bad_request","detail":"date is invalid"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	var guarded []string
+	c := New("token", WithBaseURL(srv.URL+"/"), WithWriteGuard(func(budgetID string) error {
+		guarded = append(guarded, budgetID)
+		return nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.WithContext(ctx).GetUser(); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled request error = %v, want context.Canceled", err)
+	}
+	// the canceled request is not memoized, and the original client
+	// keeps working
+	if _, err := c.GetUser(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := c.CreateTransaction("b1", SaveTransaction{AccountID: "a", Date: "x"})
+	if !errors.Is(err, ErrBadRequest) {
+		t.Errorf("error = %v, want ErrBadRequest", err)
+	}
+	if len(guarded) != 1 || guarded[0] != "b1" {
+		t.Errorf("write guard saw %v", guarded)
+	}
+}
+
+func TestCreateCategory(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, r.Method+" "+r.URL.Path+" "+string(data))
+		switch r.URL.Path {
+		case "/budgets/b1/category_groups":
+			_, _ = w.Write([]byte(`{"data":{"category_group":{"id":"g1","name":"Bills"}}}`))
+		case "/budgets/b1/categories":
+			_, _ = w.Write([]byte(`{"data":{"category":{"id":"c1","name":"Rent","category_group_id":"g1"}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := New("token", WithBaseURL(srv.URL))
+	g, err := c.CreateCategoryGroup("b1", "Bills")
+	if err != nil || g.ID != "g1" {
+		t.Fatalf("CreateCategoryGroup = %+v, %v", g, err)
+	}
+	cat, err := c.CreateCategory("b1", SaveCategory{Name: "Rent", CategoryGroupID: g.ID})
+	if err != nil || cat.ID != "c1" {
+		t.Fatalf("CreateCategory = %+v, %v", cat, err)
+	}
+
+	want := []string{
+		`POST /budgets/b1/category_groups {"category_group":{"name":"Bills"}}`,
+		`POST /budgets/b1/categories {"category":{"name":"Rent","category_group_id":"g1"}}`,
+	}
+	for i, w := range want {
+		if i >= len(bodies) || bodies[i] != w {
+			t.Errorf("request %d = %q, want %q", i, bodies, w)
+		}
+	}
+}
+
+// memStore is a ResponseStore in memory
+type memStore map[string][]byte
+
+func (m memStore) Load(path string) ([]byte, time.Time, bool) {
+	body, ok := m[path]
+	return body, time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC), ok
+}
+
+func (m memStore) Store(path string, body []byte) error {
+	m[path] = body
+	return nil
+}
+
+func TestResponseStoreAndOffline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/budgets/b1/payees":
+			_, _ = w.Write([]byte(`{"data":{"payees":[{"id":"p1","name":"Shop"}]}}`))
+		case "/budgets/b1/transactions":
+			_, _ = w.Write([]byte(`{"data":{"transactions":[],"server_knowledge":7}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	store := memStore{}
+	online := New("token", WithBaseURL(srv.URL), WithResponseStore(store))
+	if _, err := online.GetPayees("b1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := online.GetTransactionsSince("b1", 3); err != nil {
+		t.Fatal(err)
+	}
+	if len(store) != 1 {
+		t.Errorf("stored %d responses, want only the payees: %v", len(store), store)
+	}
+	if _, ok := online.DataAsOf(); ok {
+		t.Error("online client reports stored data")
+	}
+
+	srv.Close()
+	offline := New("token", WithBaseURL(srv.URL), WithResponseStore(store), WithOffline())
+	payees, err := offline.GetPayees("b1")
+	if err != nil || len(payees) != 1 {
+		t.Fatalf("offline GetPayees = %v, %v", payees, err)
+	}
+	if asOf, ok := offline.DataAsOf(); !ok || asOf.Day() != 1 {
+		t.Errorf("DataAsOf = %v, %v", asOf, ok)
+	}
+	if _, err := offline.GetAccounts("b1"); !errors.Is(err, ErrOffline) {
+		t.Errorf("uncached read error = %v, want ErrOffline", err)
+	}
+	if _, err := offline.UpdatePayee("b1", "p1", "x"); !errors.Is(err, ErrOffline) {
+		t.Errorf("offline write error = %v, want ErrOffline", err)
+	}
+}
+
+// ttlStore is a ResponseStore and ResponseInvalidator that saves
+// responses as fetched at the time of Store
+type ttlStore map[string]struct {
+	body []byte
+	at   time.Time
+}
+
+func (s ttlStore) Load(path string) ([]byte, time.Time, bool) {
+	e, ok := s[path]
+	return e.body, e.at, ok
+}
+
+func (s ttlStore) Store(path string, body []byte) error {
+	s[path] = struct {
+		body []byte
+		at   time.Time
+	}{body, time.Now()}
+	return nil
+}
+
+func (s ttlStore) Invalidate(prefix string) error {
+	for path := range s {
+		if strings.HasPrefix(path, prefix) {
+			delete(s, path)
+		}
+	}
+	return nil
+}
+
+func TestCacheTTL(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Method == http.MethodPatch {
+			_, _ = w.Write([]byte(`{"data":{"payee":{"id":"p1","name":"Store"}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"payees":[{"id":"p1","name":"Shop"}]}}`))
+	}))
+	defer srv.Close()
+
+	store := ttlStore{}
+	newClient := func(ttl time.Duration) *Client {
+		return New("token", WithBaseURL(srv.URL), WithResponseStore(store), WithCacheTTL(ttl))
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := newClient(time.Hour).GetPayees("b1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1 with a cached response", requests)
+	}
+
+	if _, err := newClient(time.Nanosecond).GetPayees("b1"); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want the expired response fetched again", requests)
+	}
+
+	c := newClient(time.Hour)
+	if _, err := c.UpdatePayee("b1", "p1", "Store"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newClient(time.Hour).GetPayees("b1"); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 4 {
+		t.Errorf("requests = %d, want the payees fetched again after a write", requests)
+	}
+
+	if _, err := newClient(time.Hour).Fresh().GetPayees("b1"); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 5 {
+		t.Errorf("requests = %d, want a fresh read sent to the API", requests)
+	}
+}
+
+func TestAmountToMilliunits(t *testing.T) {
+	for amount, want := range map[float64]int64{
+		2.01:    2010,
+		-2.01:   -2010,
+		0.29:    290,
+		-42.5:   -42500,
+		1234.56: 1234560,
+	} {
+		if got := AmountToMilliunits(amount); got != want {
+			t.Errorf("AmountToMilliunits(%v) = %d, want %d", amount, got, want)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package ynab
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DecodeWarning is a field of an API response whose type is not the one
+// the client expects, e.g. after YNAB changed the API. The rest of the
+// response is decoded and the field left at its zero value, so reads keep
+// working.
+type DecodeWarning struct {
+	// Record is the Go type holding the field, e.g. "Transaction"
+	Record string
+	// Field is the path of the field in the response
+	Field string
+	// Value is the JSON type received
+	Value string
+	// Type is the Go type expected
+	Type string
+}
+
+func (w *DecodeWarning) Error() string {
+	return fmt.Sprintf("API field %q of %s has unexpected type %s (expected %s)", w.Field, w.Record, w.Value, w.Type)
+}
+
+// ErrPartialRecord is returned for a write to the kind of record a
+// response was only partly decoded for, as the write could send the
+// zeroed field back. Check the DecodeWarning it wraps.
+var ErrPartialRecord = errors.New("refusing to change records that were only partly decoded")
+
+// WithDecodeWarnings makes the client call warn once for each field of
+// a response it could not decode
+func WithDecodeWarnings(warn func(*DecodeWarning)) Option {
+	return func(s *state) { s.decodeWarn = warn }
+}
+
+// recordResources maps the records decoded from responses to the path
+// segment under which they are changed. Records not listed are never
+// written back; warnings about unknown types refuse every write.
+var recordResources = map[string]string{
+	"Account":                 "accounts",
+	"CategoryGroup":           "categories",
+	"Category":                "categories",
+	"Payee":                   "payees",
+	"Transaction":             "transactions",
+	"Subtransaction":          "transactions",
+	"ScheduledTransaction":    "scheduled_transactions",
+	"ScheduledSubtransaction": "scheduled_transactions",
+	"User":                    "",
+	"Budget":                  "",
+	"BudgetSettings":          "",
+	"DateFormat":              "",
+	"CurrencyFormat":          "",
+	"Month":                   "",
+}
+
+// parseResponse decodes an API response body into v. A field with an
+// unexpected type is reported as a DecodeWarning instead of failing the
+// whole response, and writes to its kind of record are refused from then
+// on.
+func (c *Client) parseResponse(body []byte, v interface{}) error {
+	err := json.Unmarshal(body, v)
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		return err
+	}
+	w := &DecodeWarning{
+		Record: recordOf(reflect.TypeOf(v), typeErr.Field),
+		Field:  typeErr.Field,
+		Value:  typeErr.Value,
+		Type:   typeErr.Type.String(),
+	}
+	resource, known := recordResources[w.Record]
+	if !known {
+		resource = "*"
+	}
+
+	c.partialMu.Lock()
+	if c.partial == nil {
+		c.partial = map[string]*DecodeWarning{}
+		c.partialResources = map[string]*DecodeWarning{}
+	}
+	key := w.Record + "." + w.Field
+	_, seen := c.partial[key]
+	c.partial[key] = w
+	if resource != "" {
+		c.partialResources[resource] = w
+	}
+	c.partialMu.Unlock()
+
+	if !seen && c.decodeWarn != nil {
+		c.decodeWarn(w)
+	}
+	return nil
+}
+
+// recordOf returns the name of the struct type holding the field at
+// path (e.g. "data.transactions.amount") in values of type t
+func recordOf(t reflect.Type, path string) string {
+	segments := strings.Split(path, ".")
+	for _, name := range segments[:len(segments)-1] {
+		t = elem(t)
+		if t.Kind() != reflect.Struct {
+			return ""
+		}
+		field, ok := fieldByJSONName(t, name)
+		if !ok {
+			return ""
+		}
+		t = field.Type
+	}
+	return elem(t).Name()
+}
+
+// elem returns the type of the values a pointer, slice or map holds
+func elem(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	return t
+}
+
+func fieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ","); tag == name {
+			return t.Field(i), true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// checkPartial returns ErrPartialRecord for a write to path when a
+// response was only partly decoded for its kind of record
+func (c *Client) checkPartial(path string) error {
+	c.partialMu.Lock()
+	defer c.partialMu.Unlock()
+	w := c.partialResources[pathResource(path)]
+	if w == nil {
+		w = c.partialResources["*"]
+	}
+	if w == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrPartialRecord, w)
+}
+
+// pathResource returns the kind of record a budget path is about, e.g.
+// "transactions" for /budgets/<id>/accounts/<id>/transactions and
+// "categories" for /budgets/<id>/months/<month>/categories/<id>
+func pathResource(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) < 3 || segments[0] != "budgets" {
+		return ""
+	}
+	rest := segments[2:]
+	if len(rest)%2 == 0 {
+		return rest[len(rest)-2]
+	}
+	return rest[len(rest)-1]
+}
@@ -0,0 +1,81 @@
+package ynab
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseResponseToleratesTypeChanges(t *testing.T) {
+	var warnings []*DecodeWarning
+	c := New("token", WithDecodeWarnings(func(w *DecodeWarning) { warnings = append(warnings, w) }))
+
+	body := []byte(`{"data":{"account":{"id":"a1","name":"Checking","balance":"12.50","closed":false}}}`)
+	for i := 0; i < 2; i++ {
+		var resp AccountResponse
+		if err := c.parseResponse(body, &resp); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Data.Account.ID != "a1" || resp.Data.Account.Name != "Checking" {
+			t.Errorf("remaining fields not decoded: %+v", resp.Data.Account)
+		}
+	}
+	if len(warnings) != 1 || warnings[0].Record != "Account" || warnings[0].Value != "string" {
+		t.Errorf("warnings = %+v, want one about Account", warnings)
+	}
+
+	var resp AccountResponse
+	if err := c.parseResponse([]byte(`{"data":`), &resp); err == nil {
+		t.Error("expected error for malformed JSON")
+	}
+}
+
+func TestPartialRecordsRefuseWrites(t *testing.T) {
+	writes := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method != "GET":
+			writes++
+			_, _ = w.Write([]byte(`{"data":{"payee":{"id":"p1","name":"x"},"transaction":{"id":"t1"}}}`))
+		case r.URL.Path == "/budgets/b1/scheduled_transactions/s1":
+			_, _ = w.Write([]byte(`{"data":{"scheduled_transaction":{"id":"s1","amount":"-5.00","frequency":"monthly"}}}`))
+		}
+	}))
+	defer srv.Close()
+	c := New("token", WithBaseURL(srv.URL))
+
+	s, err := c.GetScheduledTransaction("b1", "s1")
+	if err != nil || s.ID != "s1" || s.Frequency != "monthly" {
+		t.Fatalf("GetScheduledTransaction = %+v, %v", s, err)
+	}
+	_, err = c.UpdateScheduledTransaction("b1", "s1", SaveScheduledTransaction{AccountID: "a1", Frequency: s.Frequency, Amount: s.Amount})
+	var w *DecodeWarning
+	if !errors.Is(err, ErrPartialRecord) || !errors.As(err, &w) || w.Record != "ScheduledTransaction" {
+		t.Errorf("update after a partial decode = %v, want ErrPartialRecord", err)
+	}
+	if writes != 0 {
+		t.Errorf("%d writes sent", writes)
+	}
+
+	// other kinds of records can still be changed
+	if _, err := c.UpdatePayee("b1", "p1", "x"); err != nil {
+		t.Errorf("UpdatePayee = %v", err)
+	}
+}
+
+func TestPathResource(t *testing.T) {
+	for path, want := range map[string]string{
+		"/budgets/b1/transactions":                     "transactions",
+		"/budgets/b1/transactions/t1":                  "transactions",
+		"/budgets/b1/accounts/a1/transactions?since=x": "transactions",
+		"/budgets/b1/months/2026-10-01/categories/c1":  "categories",
+		"/budgets/b1/scheduled_transactions/s1":        "scheduled_transactions",
+		"/budgets/b1":                                  "",
+		"/user":                                        "",
+	} {
+		if got := pathResource(path); got != want {
+			t.Errorf("pathResource(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
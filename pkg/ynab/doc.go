@@ -0,0 +1,32 @@
+// Package ynab is a client for the YNAB API (https://api.ynab.com). It
+// is the client used by the ynabctl command and can be imported by other
+// Go programs.
+//
+//	c := ynab.New(token)
+//	budgets, err := c.GetBudgets()
+//	if errors.Is(err, ynab.ErrUnauthorized) {
+//		// the token is invalid or revoked
+//	}
+//
+// Amounts are in milliunits (1000 = 1.00); see MilliunitsToAmount.
+//
+// Options given to New replace the HTTP client or base URL, pace
+// requests to stay under the rate limit (WithPacer) and check every
+// write before it is sent (WithWriteGuard). WithContext binds requests
+// to a context for cancellation and deadlines:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	defer cancel()
+//	txns, err := c.WithContext(ctx).GetTransactions(budgetID, nil)
+//
+// API failures are returned as *Error and match ErrBadRequest,
+// ErrUnauthorized, ErrNotFound, ErrConflict, ErrRateLimited or ErrServer
+// with errors.Is; failures to reach the API match ErrNetwork. Reads are
+// retried once after a transient failure. GET responses are cached for
+// the lifetime of a Client and the cache is cleared by any write, so a
+// Client is meant for one task, not a long-running process.
+//
+// WithResponseStore keeps GET responses between runs; with WithOffline
+// as well, reads are served from that store without network access and
+// fail with ErrOffline when it lacks them.
+package ynab
@@ -0,0 +1,76 @@
+package ynab
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// Kinds of API errors. Errors returned by the client match at most one
+// of them with errors.Is.
+var (
+	// ErrBadRequest means the request was invalid, e.g. a malformed
+	// date or an unknown field value (status 400)
+	ErrBadRequest = errors.New("bad request")
+	// ErrConflict means the request conflicts with existing data, e.g. a
+	// duplicate import_id (status 409)
+	ErrConflict = errors.New("conflict")
+	// ErrRateLimited means the 200 requests per hour limit was exceeded
+	ErrRateLimited = errors.New("rate limited")
+	// ErrNotFound means the budget or record does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized means the token is missing, invalid or revoked, or
+	// the account has no access (e.g. an expired subscription)
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrServer means YNAB failed to handle the request (status 5xx)
+	ErrServer = errors.New("server error")
+	// ErrNetwork means no response was received
+	ErrNetwork = errors.New("network error")
+	// ErrOffline means the client works offline (WithOffline) and the
+	// request cannot be answered from the response store
+	ErrOffline = errors.New("not available offline")
+)
+
+// retryDelay is the pause before retrying a transient failure
+var retryDelay = time.Second
+
+// Is reports whether the API error is of the given kind
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrServer:
+		return e.StatusCode >= 500
+	}
+	return false
+}
+
+// retryable reports whether a failed request is worth one more attempt.
+// Reads are retried after any server error or dropped connection. Writes
+// are only retried when the gateway reports it could not reach YNAB (502,
+// 503). A 504 means the gateway stopped waiting while YNAB may already
+// have made the change, so retrying could create a duplicate.
+func retryable(method string, err error) bool {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable:
+			return true
+		}
+		return method == "GET" && apiErr.StatusCode >= 500
+	}
+	if method != "GET" || !errors.Is(err, ErrNetwork) {
+		return false
+	}
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
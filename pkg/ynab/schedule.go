@@ -0,0 +1,85 @@
+package ynab
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Frequencies are the recurrence values the API accepts for scheduled
+// transactions
+var Frequencies = []string{
+	"never", "daily", "weekly", "everyOtherWeek", "twiceAMonth",
+	"every4Weeks", "monthly", "everyOtherMonth", "every3Months",
+	"every4Months", "twiceAYear", "yearly", "everyOtherYear",
+}
+
+// ParseFrequency returns the API spelling of a frequency. Case and
+// dashes, underscores or spaces are ignored ("every-other-week" is
+// everyOtherWeek). Unknown values are rejected with the closest match.
+func ParseFrequency(s string) (string, error) {
+	key := normalizeFrequency(s)
+	for _, f := range Frequencies {
+		if normalizeFrequency(f) == key {
+			return f, nil
+		}
+	}
+
+	best, bestDist := "", 4
+	for _, f := range Frequencies {
+		if d := editDistance(key, normalizeFrequency(f)); d < bestDist {
+			best, bestDist = f, d
+		}
+	}
+	if best != "" {
+		return "", fmt.Errorf("invalid frequency %q, did you mean %q? (valid: %s)", s, best, strings.Join(Frequencies, ", "))
+	}
+	return "", fmt.Errorf("invalid frequency %q (valid: %s)", s, strings.Join(Frequencies, ", "))
+}
+
+// ValidateSchedule checks the first date of a scheduled transaction
+// before it is sent. The API wants a date from today up to five years
+// ahead. A twiceAMonth schedule repeats 15 days after its date, so the
+// date must be on day 1 to 15 for both to fall in the same month.
+func ValidateSchedule(date, frequency string, today time.Time) error {
+	d, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return fmt.Errorf("invalid date %q (want YYYY-MM-DD)", date)
+	}
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	if d.Before(today) {
+		return fmt.Errorf("date %s is in the past; scheduled transactions must start today or later", date)
+	}
+	if limit := today.AddDate(5, 0, 0); d.After(limit) {
+		return fmt.Errorf("date %s is more than 5 years ahead (latest %s)", date, limit.Format("2006-01-02"))
+	}
+	if frequency == "twiceAMonth" && d.Day() > 15 {
+		return fmt.Errorf("twiceAMonth on day %d: the second date would be 15 days later, in the next month; pick a date on day 1-15", d.Day())
+	}
+	return nil
+}
+
+func normalizeFrequency(s string) string {
+	return strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(s)))
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
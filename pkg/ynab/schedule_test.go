@@ -0,0 +1,55 @@
+package ynab
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFrequency(t *testing.T) {
+	for in, want := range map[string]string{
+		"monthly":          "monthly",
+		"Monthly":          "monthly",
+		"every-other-week": "everyOtherWeek",
+		"twice_a_month":    "twiceAMonth",
+	} {
+		got, err := ParseFrequency(in)
+		if err != nil || got != want {
+			t.Errorf("ParseFrequency(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+
+	_, err := ParseFrequency("montly")
+	if err == nil || !strings.Contains(err.Error(), `did you mean "monthly"`) {
+		t.Errorf("montly: %v", err)
+	}
+	_, err = ParseFrequency("fortnightly")
+	if err == nil || strings.Contains(err.Error(), "did you mean") {
+		t.Errorf("fortnightly: %v", err)
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	today := time.Date(2025, 3, 10, 15, 0, 0, 0, time.Local)
+	for _, tc := range []struct {
+		date, frequency string
+		wantErr         string
+	}{
+		{"2025-03-10", "monthly", ""},
+		{"2025-03-31", "monthly", ""},
+		{"2025-03-15", "twiceAMonth", ""},
+		{"2025-03-31", "twiceAMonth", "day 31"},
+		{"2025-03-09", "monthly", "in the past"},
+		{"2030-03-11", "yearly", "5 years"},
+		{"2025-02-30", "monthly", "invalid date"},
+		{"10/03/2025", "monthly", "invalid date"},
+	} {
+		err := ValidateSchedule(tc.date, tc.frequency, today)
+		switch {
+		case tc.wantErr == "" && err != nil:
+			t.Errorf("%s %s: %v", tc.date, tc.frequency, err)
+		case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+			t.Errorf("%s %s: got %v, want %q", tc.date, tc.frequency, err, tc.wantErr)
+		}
+	}
+}
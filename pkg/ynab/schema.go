@@ -0,0 +1,89 @@
+package ynab
+
+import (
+	"reflect"
+	"sort"
+)
+
+// SpecURL is where YNAB publishes the OpenAPI specification of the API
+const SpecURL = "https://api.ynab.com/papi/open_api_spec.yaml"
+
+// Endpoint is an API operation, with path parameters written as in the
+// OpenAPI specification, e.g. GET /budgets/{budget_id}/payees
+type Endpoint struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// Endpoints returns the operations this client calls
+func Endpoints() []Endpoint {
+	return []Endpoint{
+		{"GET", "/user"},
+		{"GET", "/budgets"},
+		{"GET", "/budgets/{budget_id}"},
+		{"GET", "/budgets/{budget_id}/settings"},
+		{"GET", "/budgets/{budget_id}/accounts"},
+		{"POST", "/budgets/{budget_id}/accounts"},
+		{"GET", "/budgets/{budget_id}/accounts/{account_id}"},
+		{"GET", "/budgets/{budget_id}/accounts/{account_id}/transactions"},
+		{"GET", "/budgets/{budget_id}/categories"},
+		{"POST", "/budgets/{budget_id}/categories"},
+		{"GET", "/budgets/{budget_id}/categories/{category_id}"},
+		{"PATCH", "/budgets/{budget_id}/categories/{category_id}"},
+		{"GET", "/budgets/{budget_id}/categories/{category_id}/transactions"},
+		{"POST", "/budgets/{budget_id}/category_groups"},
+		{"GET", "/budgets/{budget_id}/months"},
+		{"GET", "/budgets/{budget_id}/months/{month}"},
+		{"GET", "/budgets/{budget_id}/months/{month}/categories/{category_id}"},
+		{"PATCH", "/budgets/{budget_id}/months/{month}/categories/{category_id}"},
+		{"GET", "/budgets/{budget_id}/payees"},
+		{"GET", "/budgets/{budget_id}/payees/{payee_id}"},
+		{"PATCH", "/budgets/{budget_id}/payees/{payee_id}"},
+		{"GET", "/budgets/{budget_id}/payees/{payee_id}/transactions"},
+		{"GET", "/budgets/{budget_id}/transactions"},
+		{"POST", "/budgets/{budget_id}/transactions"},
+		{"PATCH", "/budgets/{budget_id}/transactions"},
+		{"GET", "/budgets/{budget_id}/transactions/{transaction_id}"},
+		{"PUT", "/budgets/{budget_id}/transactions/{transaction_id}"},
+		{"DELETE", "/budgets/{budget_id}/transactions/{transaction_id}"},
+		{"GET", "/budgets/{budget_id}/scheduled_transactions"},
+		{"POST", "/budgets/{budget_id}/scheduled_transactions"},
+		{"GET", "/budgets/{budget_id}/scheduled_transactions/{scheduled_transaction_id}"},
+		{"PUT", "/budgets/{budget_id}/scheduled_transactions/{scheduled_transaction_id}"},
+		{"DELETE", "/budgets/{budget_id}/scheduled_transactions/{scheduled_transaction_id}"},
+	}
+}
+
+// schemaTypes maps the OpenAPI schemas of response records to the
+// structs that decode them
+var schemaTypes = map[string]interface{}{
+	"User":                        User{},
+	"BudgetSummary":               Budget{},
+	"BudgetSettings":              BudgetSettings{},
+	"DateFormat":                  DateFormat{},
+	"CurrencyFormat":              CurrencyFormat{},
+	"Account":                     Account{},
+	"CategoryGroupWithCategories": CategoryGroup{},
+	"Category":                    Category{},
+	"Payee":                       Payee{},
+	"MonthDetail":                 Month{},
+	"TransactionDetail":           Transaction{},
+	"SubTransaction":              Subtransaction{},
+	"ScheduledTransactionDetail":  ScheduledTransaction{},
+	"ScheduledSubTransaction":     ScheduledSubtransaction{},
+}
+
+// SchemaFields returns, by OpenAPI schema name, the JSON fields the
+// client decodes from records of that schema
+func SchemaFields() map[string][]string {
+	fields := make(map[string][]string, len(schemaTypes))
+	for name, record := range schemaTypes {
+		var names []string
+		for f := range jsonFields(reflect.TypeOf(record)) {
+			names = append(names, f)
+		}
+		sort.Strings(names)
+		fields[name] = names
+	}
+	return fields
+}
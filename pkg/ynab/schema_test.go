@@ -0,0 +1,47 @@
+package ynab
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestEndpointsMatchClient keeps Endpoints in step with the paths the
+// client requests
+func TestEndpointsMatchClient(t *testing.T) {
+	src, err := os.ReadFile("client.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	param := regexp.MustCompile(`\{[a-z_]+\}`)
+	listed := map[string]bool{}
+	for _, e := range Endpoints() {
+		listed[param.ReplaceAllString(e.Path, "%s")] = true
+	}
+
+	used := map[string]bool{}
+	for _, m := range regexp.MustCompile(`"(/(?:user|budgets)(?:/[^"?]+)?)[?"]`).FindAllStringSubmatch(string(src), -1) {
+		path := strings.ReplaceAll(m[1], "%d", "%s")
+		used[path] = true
+		if !listed[path] {
+			t.Errorf("client requests %s, which Endpoints lacks", path)
+		}
+	}
+	for path := range listed {
+		if !used[path] {
+			t.Errorf("Endpoints lists %s, which the client never requests", path)
+		}
+	}
+}
+
+func TestSchemaFields(t *testing.T) {
+	fields := SchemaFields()
+	payee := strings.Join(fields["Payee"], ",")
+	if payee != "deleted,id,name,transfer_account_id" {
+		t.Errorf("Payee fields = %s", payee)
+	}
+	if len(fields["TransactionDetail"]) == 0 {
+		t.Error("no TransactionDetail fields")
+	}
+}
@@ -0,0 +1,46 @@
+package ynab
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// NewHTTPClient returns an HTTP client with a 30 second timeout that
+// connects through the proxy named by HTTPS_PROXY (or HTTP_PROXY) unless
+// NO_PROXY excludes the host. If caBundle is set, the PEM certificates
+// in that file are trusted in addition to the system roots, e.g. the CA
+// of a corporate proxy that inspects TLS. Pass it to WithHTTPClient.
+func NewHTTPClient(caBundle string) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	if caBundle != "" {
+		pool, err := LoadCABundle(caBundle)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return &http.Client{Transport: transport, Timeout: 30 * time.Second}, nil
+}
+
+// LoadCABundle returns the system certificate pool with the PEM
+// certificates in path added. A file without any certificate is an
+// error, so a wrong path is not mistaken for a working setup.
+func LoadCABundle(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("CA bundle %s holds no PEM certificates", path)
+	}
+	return pool, nil
+}
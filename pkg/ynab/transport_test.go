@@ -0,0 +1,53 @@
+package ynab
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewHTTPClientCABundle(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"user":{"id":"u1"}}}`))
+	}))
+	defer srv.Close()
+
+	// the test server's certificate is not trusted by the system roots
+	hc, err := NewHTTPClient("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New("token", WithBaseURL(srv.URL), WithHTTPClient(hc)).GetUser(); err == nil {
+		t.Error("request to an untrusted server succeeded")
+	}
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(bundle, cert, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	hc, err = NewHTTPClient(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := New("token", WithBaseURL(srv.URL), WithHTTPClient(hc)).GetUser()
+	if err != nil || user.ID != "u1" {
+		t.Errorf("GetUser with CA bundle = %+v, %v", user, err)
+	}
+}
+
+func TestLoadCABundleWithoutCertificates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(path, []byte("not a certificate\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCABundle(path); err == nil {
+		t.Error("LoadCABundle accepted a file without certificates")
+	}
+	if _, err := LoadCABundle(filepath.Join(t.TempDir(), "missing.pem")); err == nil {
+		t.Error("LoadCABundle accepted a missing file")
+	}
+}